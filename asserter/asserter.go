@@ -19,10 +19,15 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path"
+	"sort"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
+// configurationFilePermissions are the permissions
+// used when saving a *Configuration to a file.
+const configurationFilePermissions = 0600
+
 // Asserter contains all logic to perform static
 // validation on Rosetta Server responses.
 type Asserter struct {
@@ -33,6 +38,14 @@ type Asserter struct {
 	errorTypeMap        map[int32]*types.Error
 	genesisBlock        *types.BlockIdentifier
 	timestampStartIndex int64
+	validations         *Validations
+
+	// minTimestamp and maxTimestamp bound the
+	// acceptable block timestamps (inclusive).
+	minTimestamp           int64
+	maxTimestamp           int64
+	exemptGenesisTimestamp bool
+	limits                 *Limits
 
 	// These variables are used for request assertion.
 	historicalBalanceLookup bool
@@ -41,6 +54,28 @@ type Asserter struct {
 	mempoolCoins            bool
 }
 
+// Option is used to overwrite default values in
+// an Asserter constructed for use in a client.
+type Option func(a *Asserter)
+
+// WithTimestampBounds overrides the minimum and maximum
+// acceptable block timestamps (in milliseconds). By default,
+// MinUnixEpoch and MaxUnixEpoch are used.
+func WithTimestampBounds(min int64, max int64) Option {
+	return func(a *Asserter) {
+		a.minTimestamp = min
+		a.maxTimestamp = max
+	}
+}
+
+// WithGenesisTimestampExemption skips timestamp validation
+// for the genesis block (which often has a timestamp of 0).
+func WithGenesisTimestampExemption(exempt bool) Option {
+	return func(a *Asserter) {
+		a.exemptGenesisTimestamp = exempt
+	}
+}
+
 // NewServer constructs a new Asserter for use in the
 // server package.
 func NewServer(
@@ -87,27 +122,41 @@ func NewClientWithResponses(
 	network *types.NetworkIdentifier,
 	networkStatus *types.NetworkStatusResponse,
 	networkOptions *types.NetworkOptionsResponse,
+	options ...Option,
 ) (*Asserter, error) {
 	if err := NetworkIdentifier(network); err != nil {
 		return nil, err
 	}
 
-	if err := NetworkStatusResponse(networkStatus); err != nil {
-		return nil, err
+	if networkStatus == nil {
+		return nil, ErrNetworkStatusResponseIsNil
 	}
 
 	if err := NetworkOptionsResponse(networkOptions); err != nil {
 		return nil, err
 	}
 
-	return NewClientWithOptions(
+	asserter, err := NewClientWithOptions(
 		network,
 		networkStatus.GenesisBlockIdentifier,
 		networkOptions.Allow.OperationTypes,
 		networkOptions.Allow.OperationStatuses,
 		networkOptions.Allow.Errors,
 		networkOptions.Allow.TimestampStartIndex,
+		append([]Option{WithCallMethods(networkOptions.Allow.CallMethods)}, options...)...,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The NetworkStatusResponse is asserted after the Asserter
+	// is constructed so that any configured timestamp bounds
+	// are respected.
+	if err := asserter.NetworkStatusResponse(networkStatus); err != nil {
+		return nil, err
+	}
+
+	return asserter, nil
 }
 
 // Configuration is the static configuration of an Asserter. This
@@ -120,6 +169,12 @@ type Configuration struct {
 	AllowedOperationStatuses   []*types.OperationStatus `json:"allowed_operation_statuses"`
 	AllowedErrors              []*types.Error           `json:"allowed_errors"`
 	AllowedTimestampStartIndex int64                    `json:"allowed_timestamp_start_index"`
+	Validations                *Validations             `json:"validations,omitempty"`
+	MinTimestamp               *int64                   `json:"min_timestamp,omitempty"`
+	MaxTimestamp               *int64                   `json:"max_timestamp,omitempty"`
+	ExemptGenesisTimestamp     bool                     `json:"exempt_genesis_timestamp,omitempty"`
+	Limits                     *Limits                  `json:"limits,omitempty"`
+	AllowedCallMethods         []string                 `json:"allowed_call_methods"`
 }
 
 // NewClientWithFile constructs a new Asserter using a specification
@@ -140,6 +195,26 @@ func NewClientWithFile(
 		return nil, err
 	}
 
+	minTimestamp := int64(MinUnixEpoch)
+	if config.MinTimestamp != nil {
+		minTimestamp = *config.MinTimestamp
+	}
+
+	maxTimestamp := int64(MaxUnixEpoch)
+	if config.MaxTimestamp != nil {
+		maxTimestamp = *config.MaxTimestamp
+	}
+
+	options := []Option{
+		WithValidations(config.Validations),
+		WithTimestampBounds(minTimestamp, maxTimestamp),
+		WithGenesisTimestampExemption(config.ExemptGenesisTimestamp),
+		WithLimits(config.Limits),
+	}
+	if config.AllowedCallMethods != nil {
+		options = append(options, WithCallMethods(config.AllowedCallMethods))
+	}
+
 	return NewClientWithOptions(
 		config.NetworkIdentifier,
 		config.GenesisBlockIdentifier,
@@ -147,6 +222,7 @@ func NewClientWithFile(
 		config.AllowedOperationStatuses,
 		config.AllowedErrors,
 		&config.AllowedTimestampStartIndex,
+		options...,
 	)
 }
 
@@ -160,6 +236,7 @@ func NewClientWithOptions(
 	operationStatuses []*types.OperationStatus,
 	errors []*types.Error,
 	timestampStartIndex *int64,
+	options ...Option,
 ) (*Asserter, error) {
 	if err := NetworkIdentifier(network); err != nil {
 		return nil, err
@@ -197,6 +274,8 @@ func NewClientWithOptions(
 		operationTypes:      operationTypes,
 		genesisBlock:        genesisBlockIdentifier,
 		timestampStartIndex: parsedTimestampStartIndex,
+		minTimestamp:        MinUnixEpoch,
+		maxTimestamp:        MaxUnixEpoch,
 	}
 
 	asserter.operationStatusMap = map[string]bool{}
@@ -209,12 +288,33 @@ func NewClientWithOptions(
 		asserter.errorTypeMap[err.Code] = err
 	}
 
+	for _, opt := range options {
+		opt(asserter)
+	}
+
+	if asserter.minTimestamp > asserter.maxTimestamp {
+		return nil, fmt.Errorf(
+			"%w: min %d > max %d",
+			ErrTimestampBoundsInvalid,
+			asserter.minTimestamp,
+			asserter.maxTimestamp,
+		)
+	}
+
 	return asserter, nil
 }
 
 // ClientConfiguration returns all variables currently set in an Asserter.
 // This function will error if it is called on an uninitialized asserter.
 func (a *Asserter) ClientConfiguration() (*Configuration, error) {
+	return a.ToConfiguration()
+}
+
+// ToConfiguration returns the *Configuration of a client Asserter
+// (in the same schema read by NewClientWithFile). Allowed operation
+// statuses and errors are sorted so that the output is deterministic.
+// This function will error if it is called on an uninitialized asserter.
+func (a *Asserter) ToConfiguration() (*Configuration, error) {
 	if a == nil {
 		return nil, ErrAsserterNotInitialized
 	}
@@ -226,11 +326,26 @@ func (a *Asserter) ClientConfiguration() (*Configuration, error) {
 			Successful: v,
 		})
 	}
+	sort.Slice(operationStatuses, func(i, j int) bool {
+		return operationStatuses[i].Status < operationStatuses[j].Status
+	})
 
 	errors := []*types.Error{}
 	for _, v := range a.errorTypeMap {
 		errors = append(errors, v)
 	}
+	sort.Slice(errors, func(i, j int) bool {
+		return errors[i].Code < errors[j].Code
+	})
+
+	var callMethods []string
+	if a.callMethods != nil {
+		callMethods = []string{}
+		for method := range a.callMethods {
+			callMethods = append(callMethods, method)
+		}
+		sort.Strings(callMethods)
+	}
 
 	return &Configuration{
 		NetworkIdentifier:          a.network,
@@ -239,9 +354,35 @@ func (a *Asserter) ClientConfiguration() (*Configuration, error) {
 		AllowedOperationStatuses:   operationStatuses,
 		AllowedErrors:              errors,
 		AllowedTimestampStartIndex: a.timestampStartIndex,
+		Validations:                a.validations,
+		MinTimestamp:               types.Int64(a.minTimestamp),
+		MaxTimestamp:               types.Int64(a.maxTimestamp),
+		ExemptGenesisTimestamp:     a.exemptGenesisTimestamp,
+		Limits:                     a.limits,
+		AllowedCallMethods:         callMethods,
 	}, nil
 }
 
+// SaveConfiguration writes the *Configuration of a client Asserter
+// to filePath. The file can be loaded with NewClientWithFile.
+func (a *Asserter) SaveConfiguration(filePath string) error {
+	config, err := a.ToConfiguration()
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: unable to marshal configuration", err)
+	}
+
+	if err := ioutil.WriteFile(path.Clean(filePath), content, configurationFilePermissions); err != nil {
+		return fmt.Errorf("%w: unable to write configuration to %s", err, filePath)
+	}
+
+	return nil
+}
+
 // OperationSuccessful returns a boolean indicating if a types.Operation is
 // successful and should be applied in a transaction. This should only be called
 // AFTER an operation has been validated.
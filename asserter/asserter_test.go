@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -478,3 +479,166 @@ func TestNew(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestConfigurationRoundTrip(t *testing.T) {
+	network := &types.NetworkIdentifier{
+		Blockchain: "hello",
+		Network:    "world",
+	}
+	original, err := NewClientWithResponses(
+		network,
+		&types.NetworkStatusResponse{
+			GenesisBlockIdentifier: &types.BlockIdentifier{
+				Index: 0,
+				Hash:  "block 0",
+			},
+			CurrentBlockIdentifier: &types.BlockIdentifier{
+				Index: 100,
+				Hash:  "block 100",
+			},
+			CurrentBlockTimestamp: MinUnixEpoch + 1,
+		},
+		&types.NetworkOptionsResponse{
+			Version: &types.Version{
+				RosettaVersion: "1.4.0",
+				NodeVersion:    "1.0",
+			},
+			Allow: &types.Allow{
+				OperationStatuses: []*types.OperationStatus{
+					{
+						Status:     "SUCCESS",
+						Successful: true,
+					},
+					{
+						Status:     "FAILURE",
+						Successful: false,
+					},
+				},
+				OperationTypes: []string{"PAYMENT", "FEE"},
+				Errors: []*types.Error{
+					{
+						Code:      2,
+						Message:   "error 2",
+						Retriable: true,
+					},
+					{
+						Code:    1,
+						Message: "error 1",
+					},
+				},
+				TimestampStartIndex: types.Int64(5),
+			},
+		},
+		WithValidations(&Validations{
+			RequireFeeOperation: true,
+		}),
+	)
+	assert.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "configuration")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filePath := path.Join(dir, "asserter.json")
+	assert.NoError(t, original.SaveConfiguration(filePath))
+
+	loaded, err := NewClientWithFile(filePath)
+	assert.NoError(t, err)
+
+	originalConfig, err := original.ToConfiguration()
+	assert.NoError(t, err)
+	loadedConfig, err := loaded.ToConfiguration()
+	assert.NoError(t, err)
+	assert.Equal(t, originalConfig, loadedConfig)
+	assert.Equal(t, []*types.Error{
+		{
+			Code:    1,
+			Message: "error 1",
+		},
+		{
+			Code:      2,
+			Message:   "error 2",
+			Retriable: true,
+		},
+	}, loadedConfig.AllowedErrors)
+
+	// Both asserters must return the same results.
+	payment := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: 0,
+		},
+		Type:   "PAYMENT",
+		Status: types.String("SUCCESS"),
+	}
+	fee := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: 1,
+		},
+		Type:   "FEE",
+		Status: types.String("FAILURE"),
+	}
+	blocks := []*types.Block{
+		{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: 1,
+				Hash:  "block 1",
+			},
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Index: 0,
+				Hash:  "block 0",
+			},
+			Timestamp: 1,
+			Transactions: []*types.Transaction{
+				{
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: "tx",
+					},
+					Operations: []*types.Operation{payment, fee},
+				},
+			},
+		},
+		{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: 5,
+				Hash:  "block 5",
+			},
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Index: 4,
+				Hash:  "block 4",
+			},
+			Timestamp: 1,
+		},
+		{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: 6,
+				Hash:  "block 6",
+			},
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Index: 5,
+				Hash:  "block 5",
+			},
+			Timestamp: MinUnixEpoch + 1,
+			Transactions: []*types.Transaction{
+				{
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: "tx",
+					},
+					Operations: []*types.Operation{payment},
+				},
+			},
+		},
+	}
+	for _, block := range blocks {
+		assert.Equal(t, original.Block(block), loaded.Block(block))
+	}
+
+	assert.Equal(
+		t,
+		original.Error(&types.Error{Code: 2, Message: "error 2"}),
+		loaded.Error(&types.Error{Code: 2, Message: "error 2"}),
+	)
+	assert.Error(t, loaded.Error(&types.Error{Code: 2, Message: "error 2"}))
+
+	var nilAsserter *Asserter
+	assert.Equal(t, ErrAsserterNotInitialized, nilAsserter.SaveConfiguration(filePath))
+}
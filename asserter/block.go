@@ -136,9 +136,42 @@ func containsInt64(valid []int64, value int64) bool {
 	return false
 }
 
+// statusRule describes how the Operation.Status field
+// is validated.
+type statusRule int
+
+const (
+	// statusRequired requires a valid Operation.Status
+	// (used for operations in blocks).
+	statusRequired statusRule = iota
+
+	// statusEmpty requires an empty Operation.Status
+	// (used for operations in construction).
+	statusEmpty
+
+	// statusOptional allows an empty Operation.Status
+	// but requires a valid Operation.Status if one is
+	// populated (used for operations in the mempool).
+	statusOptional
+)
+
+// constructionStatusRule returns the statusRule
+// used for block or construction operations.
+func constructionStatusRule(construction bool) statusRule {
+	if construction {
+		return statusEmpty
+	}
+
+	return statusRequired
+}
+
 // OperationStatus returns an error if an operation.Status
 // is not valid.
 func (a *Asserter) OperationStatus(status *string, construction bool) error {
+	return a.operationStatus(status, constructionStatusRule(construction))
+}
+
+func (a *Asserter) operationStatus(status *string, rule statusRule) error {
 	if a == nil {
 		return ErrAsserterNotInitialized
 	}
@@ -149,14 +182,14 @@ func (a *Asserter) OperationStatus(status *string, construction bool) error {
 	// we need to handle a populated but empty Operation.Status
 	// field gracefully.
 	if status == nil || len(*status) == 0 {
-		if construction {
+		if rule != statusRequired {
 			return nil
 		}
 
 		return ErrOperationStatusMissing
 	}
 
-	if construction {
+	if rule == statusEmpty {
 		return ErrOperationStatusNotEmptyForConstruction
 	}
 
@@ -187,6 +220,14 @@ func (a *Asserter) Operation(
 	operation *types.Operation,
 	index int64,
 	construction bool,
+) error {
+	return a.operation(operation, index, constructionStatusRule(construction))
+}
+
+func (a *Asserter) operation(
+	operation *types.Operation,
+	index int64,
+	rule statusRule,
 ) error {
 	if a == nil {
 		return ErrAsserterNotInitialized
@@ -196,6 +237,10 @@ func (a *Asserter) Operation(
 		return ErrOperationIsNil
 	}
 
+	if err := a.metadataSize(operation.Metadata); err != nil {
+		return fmt.Errorf("%w: operation metadata is invalid in operation %d", err, index)
+	}
+
 	if err := OperationIdentifier(operation.OperationIdentifier, index); err != nil {
 		return fmt.Errorf("%w: Operation identifier is invalid in operation %d", err, index)
 	}
@@ -204,7 +249,7 @@ func (a *Asserter) Operation(
 		return fmt.Errorf("%w: operation type is invalid in operation %d", err, index)
 	}
 
-	if err := a.OperationStatus(operation.Status, construction); err != nil {
+	if err := a.operationStatus(operation.Status, rule); err != nil {
 		return fmt.Errorf("%w: operation status is invalid in operation %d", err, index)
 	}
 
@@ -289,28 +334,37 @@ func (a *Asserter) Operations(
 	operations []*types.Operation,
 	construction bool,
 ) error {
-	if len(operations) == 0 && construction {
+	return a.operations(operations, constructionStatusRule(construction))
+}
+
+func (a *Asserter) operations(
+	operations []*types.Operation,
+	rule statusRule,
+) error {
+	if len(operations) == 0 && rule == statusEmpty {
 		return ErrNoOperationsForConstruction
 	}
 
+	anyRelatedOrder := a.validations != nil && a.validations.AllowAnyRelatedOperationOrder
 	for i, op := range operations {
 		// Ensure operations are sorted
-		if err := a.Operation(op, int64(i), construction); err != nil {
+		if err := a.operation(op, int64(i), rule); err != nil {
 			return err
 		}
 
 		// Ensure an operation's related_operations are only
 		// operations with an index less than the operation
+		// (or any other operation in the transaction, if allowed)
 		// and that there are no duplicates.
 		relatedIndexes := []int64{}
 		for _, relatedOp := range op.RelatedOperations {
-			if relatedOp.Index >= op.OperationIdentifier.Index {
-				return fmt.Errorf(
-					"%w: related operation index %d >= operation index %d",
-					ErrRelatedOperationIndexOutOfOrder,
-					relatedOp.Index,
-					op.OperationIdentifier.Index,
-				)
+			if err := relatedOperationIndex(
+				relatedOp.Index,
+				op.OperationIdentifier.Index,
+				int64(len(operations)),
+				anyRelatedOrder,
+			); err != nil {
+				return err
 			}
 
 			if containsInt64(relatedIndexes, relatedOp.Index) {
@@ -325,6 +379,106 @@ func (a *Asserter) Operations(
 		}
 	}
 
+	// Operations can only reference operations with a lower
+	// index unless any order is allowed, so we only need to check
+	// for cycles when any order is allowed.
+	if anyRelatedOrder {
+		if err := relatedOperationsAcyclic(operations); err != nil {
+			return err
+		}
+	}
+
+	rejectCreatedAndSpent := a.validations != nil && a.validations.RejectCoinCreatedAndSpent
+	if err := a.CoinChanges(operations, rejectCreatedAndSpent); err != nil {
+		return err
+	}
+
+	// Chain-specific validations only apply to operations
+	// that have been (or may be) included on-chain.
+	if rule == statusEmpty {
+		return nil
+	}
+
+	return a.validateOperations(operations)
+}
+
+// relatedOperationIndex returns an error if a related operation
+// index is not valid for an operation. If anyOrder is false, the
+// related operation index must be less than the operation index.
+// Otherwise, it must be the index of some other operation in the
+// transaction.
+func relatedOperationIndex(related int64, index int64, count int64, anyOrder bool) error {
+	if !anyOrder {
+		if related >= index {
+			return fmt.Errorf(
+				"%w: related operation index %d >= operation index %d",
+				ErrRelatedOperationIndexOutOfOrder,
+				related,
+				index,
+			)
+		}
+
+		return nil
+	}
+
+	if related == index {
+		return fmt.Errorf(
+			"%w: operation index %d",
+			ErrRelatedOperationSelfReference,
+			index,
+		)
+	}
+
+	if related < 0 || related >= count {
+		return fmt.Errorf(
+			"%w: related operation index %d not found for operation index %d",
+			ErrRelatedOperationIndexNotFound,
+			related,
+			index,
+		)
+	}
+
+	return nil
+}
+
+// relatedOperationsAcyclic returns an error if the
+// related_operations of a slice of operations form a
+// cycle. This should only be called AFTER all
+// related operation indexes have been validated.
+func relatedOperationsAcyclic(operations []*types.Operation) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	states := make([]int, len(operations))
+	var visit func(i int64) error
+	visit = func(i int64) error {
+		switch states[i] {
+		case visiting:
+			return fmt.Errorf("%w: operation index %d", ErrRelatedOperationCycle, i)
+		case visited:
+			return nil
+		}
+
+		states[i] = visiting
+		for _, relatedOp := range operations[i].RelatedOperations {
+			if err := visit(relatedOp.Index); err != nil {
+				return err
+			}
+		}
+		states[i] = visited
+
+		return nil
+	}
+
+	for i := range operations {
+		if err := visit(int64(i)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -333,6 +487,13 @@ func (a *Asserter) Operations(
 // is invalid, or if any operation index is reused within a transaction.
 func (a *Asserter) Transaction(
 	transaction *types.Transaction,
+) error {
+	return a.transaction(transaction, statusRequired)
+}
+
+func (a *Asserter) transaction(
+	transaction *types.Transaction,
+	rule statusRule,
 ) error {
 	if a == nil {
 		return ErrAsserterNotInitialized
@@ -342,11 +503,19 @@ func (a *Asserter) Transaction(
 		return ErrTxIsNil
 	}
 
+	if err := a.operationCount(len(transaction.Operations)); err != nil {
+		return err
+	}
+
+	if err := a.metadataSize(transaction.Metadata); err != nil {
+		return fmt.Errorf("%w: transaction metadata is invalid", err)
+	}
+
 	if err := TransactionIdentifier(transaction.TransactionIdentifier); err != nil {
 		return err
 	}
 
-	if err := a.Operations(transaction.Operations, false); err != nil {
+	if err := a.operations(transaction.Operations, rule); err != nil {
 		return fmt.Errorf(
 			"%w invalid operation in transaction %s",
 			err,
@@ -367,14 +536,15 @@ func (a *Asserter) Transaction(
 
 // RelatedTransactions returns an error if the related transactions array is non-null and non-empty
 // and
-// any of the related transactions contain invalid types, invalid network identifiers,
-// invalid transaction identifiers, or a direction not defined by the enum.
+// any of the related transactions are nil, contain invalid types, invalid network identifiers,
+// invalid transaction identifiers, a direction not defined by the enum, or if the same
+// (network, hash, direction) triple is included more than once.
 func (a *Asserter) RelatedTransactions(relatedTransactions []*types.RelatedTransaction) error {
-	if dup := DuplicateRelatedTransaction(relatedTransactions); dup != nil {
-		return fmt.Errorf("%w: %v", ErrDuplicateRelatedTransaction, dup)
-	}
-
 	for i, relatedTransaction := range relatedTransactions {
+		if relatedTransaction == nil {
+			return fmt.Errorf("%w: related transaction at index %d", ErrRelatedTransactionIsNil, i)
+		}
+
 		if relatedTransaction.NetworkIdentifier != nil {
 			if err := NetworkIdentifier(relatedTransaction.NetworkIdentifier); err != nil {
 				return fmt.Errorf(
@@ -402,6 +572,10 @@ func (a *Asserter) RelatedTransactions(relatedTransactions []*types.RelatedTrans
 		}
 	}
 
+	if dup := DuplicateRelatedTransaction(relatedTransactions); dup != nil {
+		return fmt.Errorf("%w: %s", ErrDuplicateRelatedTransaction, types.PrintStruct(dup))
+	}
+
 	return nil
 }
 
@@ -434,13 +608,29 @@ func (a *Asserter) Direction(direction types.Direction) error {
 }
 
 // Timestamp returns an error if the timestamp
-// on a block is less than or equal to 0.
+// on a block is before MinUnixEpoch or after
+// MaxUnixEpoch.
 func Timestamp(timestamp int64) error {
+	return timestampInBounds(timestamp, MinUnixEpoch, MaxUnixEpoch)
+}
+
+// Timestamp returns an error if the timestamp
+// on a block is outside of the timestamp bounds
+// configured in the Asserter.
+func (a *Asserter) Timestamp(timestamp int64) error {
+	if a == nil {
+		return ErrAsserterNotInitialized
+	}
+
+	return timestampInBounds(timestamp, a.minTimestamp, a.maxTimestamp)
+}
+
+func timestampInBounds(timestamp int64, min int64, max int64) error {
 	switch {
-	case timestamp < MinUnixEpoch:
-		return fmt.Errorf("%w: %d", ErrTimestampBeforeMin, timestamp)
-	case timestamp > MaxUnixEpoch:
-		return fmt.Errorf("%w: %d", ErrTimestampAfterMax, timestamp)
+	case timestamp < min:
+		return fmt.Errorf("%w: %d < %d", ErrTimestampBeforeMin, timestamp, min)
+	case timestamp > max:
+		return fmt.Errorf("%w: %d > %d", ErrTimestampAfterMax, timestamp, max)
 	default:
 		return nil
 	}
@@ -458,6 +648,14 @@ func (a *Asserter) Block(
 		return ErrBlockIsNil
 	}
 
+	if err := a.transactionCount(len(block.Transactions)); err != nil {
+		return err
+	}
+
+	if err := a.metadataSize(block.Metadata); err != nil {
+		return fmt.Errorf("%w: block metadata is invalid", err)
+	}
+
 	if err := BlockIdentifier(block.BlockIdentifier); err != nil {
 		return err
 	}
@@ -479,9 +677,11 @@ func (a *Asserter) Block(
 	}
 
 	// Only check for timestamp validity if timestamp start index is <=
-	// the current block index.
-	if a.timestampStartIndex <= block.BlockIdentifier.Index {
-		if err := Timestamp(block.Timestamp); err != nil {
+	// the current block index (and the block is not an exempted
+	// genesis block).
+	exempt := a.exemptGenesisTimestamp && a.genesisBlock.Index == block.BlockIdentifier.Index
+	if a.timestampStartIndex <= block.BlockIdentifier.Index && !exempt {
+		if err := a.Timestamp(block.Timestamp); err != nil {
 			return err
 		}
 	}
@@ -492,5 +692,5 @@ func (a *Asserter) Block(
 		}
 	}
 
-	return nil
+	return a.validateBlockCurrencies(block)
 }
@@ -17,6 +17,8 @@ package asserter
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -501,6 +503,124 @@ func TestOperation(t *testing.T) {
 	}
 }
 
+func TestRelatedTransactions(t *testing.T) {
+	var (
+		network = &types.NetworkIdentifier{
+			Blockchain: "hello",
+			Network:    "world",
+		}
+		otherNetwork = &types.NetworkIdentifier{
+			Blockchain: "hello",
+			Network:    "other",
+		}
+		txIdentifier = &types.TransactionIdentifier{
+			Hash: "blah",
+		}
+	)
+
+	var tests = map[string]struct {
+		relatedTransactions []*types.RelatedTransaction
+		err                 error
+	}{
+		"nil": {},
+		"valid": {
+			relatedTransactions: []*types.RelatedTransaction{
+				{
+					TransactionIdentifier: txIdentifier,
+					Direction:             types.Forward,
+				},
+				{
+					NetworkIdentifier:     network,
+					TransactionIdentifier: txIdentifier,
+					Direction:             types.Backward,
+				},
+			},
+		},
+		"same hash on different networks and directions": {
+			relatedTransactions: []*types.RelatedTransaction{
+				{
+					NetworkIdentifier:     network,
+					TransactionIdentifier: txIdentifier,
+					Direction:             types.Forward,
+				},
+				{
+					NetworkIdentifier:     otherNetwork,
+					TransactionIdentifier: txIdentifier,
+					Direction:             types.Forward,
+				},
+				{
+					NetworkIdentifier:     network,
+					TransactionIdentifier: txIdentifier,
+					Direction:             types.Backward,
+				},
+			},
+		},
+		"nil related transaction": {
+			relatedTransactions: []*types.RelatedTransaction{nil},
+			err:                 ErrRelatedTransactionIsNil,
+		},
+		"invalid network identifier": {
+			relatedTransactions: []*types.RelatedTransaction{
+				{
+					NetworkIdentifier: &types.NetworkIdentifier{
+						Blockchain: "hello",
+					},
+					TransactionIdentifier: txIdentifier,
+					Direction:             types.Forward,
+				},
+			},
+			err: ErrNetworkIdentifierNetworkMissing,
+		},
+		"missing transaction identifier": {
+			relatedTransactions: []*types.RelatedTransaction{
+				{
+					NetworkIdentifier: network,
+					Direction:         types.Forward,
+				},
+			},
+			err: ErrTxIdentifierIsNil,
+		},
+		"invalid direction": {
+			relatedTransactions: []*types.RelatedTransaction{
+				{
+					TransactionIdentifier: txIdentifier,
+					Direction:             "sideways",
+				},
+			},
+			err: ErrInvalidDirection,
+		},
+		"duplicate": {
+			relatedTransactions: []*types.RelatedTransaction{
+				{
+					NetworkIdentifier:     network,
+					TransactionIdentifier: txIdentifier,
+					Direction:             types.Forward,
+				},
+				{
+					NetworkIdentifier: network,
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: "blah",
+					},
+					Direction: types.Forward,
+				},
+			},
+			err: ErrDuplicateRelatedTransaction,
+		},
+	}
+
+	asserter := &Asserter{}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := asserter.RelatedTransactions(test.relatedTransactions)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestBlock(t *testing.T) {
 	genesisIdentifier := &types.BlockIdentifier{
 		Hash:  "gen",
@@ -1031,3 +1151,190 @@ func TestBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestTimestampBounds(t *testing.T) {
+	var (
+		genesis = &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: 0,
+				Hash:  "block 0",
+			},
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Index: 0,
+				Hash:  "block 0",
+			},
+			Timestamp: 0,
+		}
+		futureBlock = &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: 1,
+				Hash:  "block 1",
+			},
+			ParentBlockIdentifier: genesis.BlockIdentifier,
+			Timestamp:             MaxUnixEpoch * 2,
+		}
+		futureStatus = &types.NetworkStatusResponse{
+			GenesisBlockIdentifier: genesis.BlockIdentifier,
+			CurrentBlockIdentifier: futureBlock.BlockIdentifier,
+			CurrentBlockTimestamp:  futureBlock.Timestamp,
+		}
+	)
+
+	var tests = map[string]struct {
+		options []Option
+
+		genesisErr   error
+		futureErr    error
+		responsesErr error
+		constructErr error
+	}{
+		"default bounds": {
+			genesisErr:   ErrTimestampBeforeMin,
+			futureErr:    ErrTimestampAfterMax,
+			responsesErr: ErrTimestampAfterMax,
+		},
+		"genesis exempt": {
+			options: []Option{
+				WithGenesisTimestampExemption(true),
+			},
+			futureErr:    ErrTimestampAfterMax,
+			responsesErr: ErrTimestampAfterMax,
+		},
+		"relaxed bounds": {
+			options: []Option{
+				WithTimestampBounds(0, MaxUnixEpoch*2),
+			},
+		},
+		"relaxed max bound": {
+			options: []Option{
+				WithTimestampBounds(MinUnixEpoch, MaxUnixEpoch*2),
+				WithGenesisTimestampExemption(true),
+			},
+		},
+		"invalid bounds": {
+			options: []Option{
+				WithTimestampBounds(MaxUnixEpoch, MinUnixEpoch),
+			},
+			constructErr: ErrTimestampBoundsInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := NewClientWithOptions(
+				&types.NetworkIdentifier{
+					Blockchain: "hello",
+					Network:    "world",
+				},
+				genesis.BlockIdentifier,
+				[]string{"PAYMENT"},
+				[]*types.OperationStatus{
+					{
+						Status:     "SUCCESS",
+						Successful: true,
+					},
+				},
+				[]*types.Error{},
+				types.Int64(0),
+				test.options...,
+			)
+			if test.constructErr != nil {
+				assert.True(t, errors.Is(err, test.constructErr))
+				assert.Nil(t, asserter)
+				return
+			}
+			assert.NoError(t, err)
+
+			err = asserter.Block(genesis)
+			if test.genesisErr != nil {
+				assert.True(t, errors.Is(err, test.genesisErr))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			err = asserter.Block(futureBlock)
+			if test.futureErr != nil {
+				assert.True(t, errors.Is(err, test.futureErr))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			asserter, err = NewClientWithResponses(
+				&types.NetworkIdentifier{
+					Blockchain: "hello",
+					Network:    "world",
+				},
+				futureStatus,
+				&types.NetworkOptionsResponse{
+					Version: &types.Version{
+						RosettaVersion: "1.4.0",
+						NodeVersion:    "1.0",
+					},
+					Allow: &types.Allow{
+						OperationStatuses: []*types.OperationStatus{
+							{
+								Status:     "SUCCESS",
+								Successful: true,
+							},
+						},
+						OperationTypes: []string{"PAYMENT"},
+					},
+				},
+				test.options...,
+			)
+			if test.responsesErr != nil {
+				assert.True(t, errors.Is(err, test.responsesErr))
+				assert.Nil(t, asserter)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, asserter)
+			}
+
+			err = NetworkStatusResponseWithOptions(futureStatus, test.options...)
+			if test.responsesErr != nil {
+				assert.True(t, errors.Is(err, test.responsesErr))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTimestampBoundsFile(t *testing.T) {
+	tmpfile, err := ioutil.TempFile("", "test.json")
+	assert.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+
+	_, err = tmpfile.Write([]byte(`{
+		"network_identifier": {"blockchain": "hello", "network": "world"},
+		"genesis_block_identifier": {"index": 0, "hash": "block 0"},
+		"allowed_operation_types": ["PAYMENT"],
+		"allowed_operation_statuses": [{"status": "SUCCESS", "successful": true}],
+		"allowed_errors": [],
+		"allowed_timestamp_start_index": 0,
+		"min_timestamp": 0
+	}`))
+	assert.NoError(t, err)
+	assert.NoError(t, tmpfile.Close())
+
+	asserter, err := NewClientWithFile(tmpfile.Name())
+	assert.NoError(t, err)
+
+	configuration, err := asserter.ClientConfiguration()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), *configuration.MinTimestamp)
+	assert.Equal(t, int64(MaxUnixEpoch), *configuration.MaxTimestamp)
+	assert.False(t, configuration.ExemptGenesisTimestamp)
+
+	assert.NoError(t, asserter.Block(&types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		Timestamp: 0,
+	}))
+}
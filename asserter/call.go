@@ -0,0 +1,52 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asserter
+
+import (
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// WithCallMethods sets the methods supported by /call
+// (populated from Allow.CallMethods in /network/options).
+// If a client Asserter is not initialized with any call
+// methods, the method of a CallRequest is not checked
+// against the supported methods.
+func WithCallMethods(methods []string) Option {
+	return func(a *Asserter) {
+		a.callMethods = map[string]struct{}{}
+		for _, method := range methods {
+			a.callMethods[method] = struct{}{}
+		}
+	}
+}
+
+// CallResponse ensures a *types.CallResponse
+// is valid. The result of a call is not inspected
+// but it must be populated (it can be an empty map).
+//
+// CallResponse.Idempotent is a required field but
+// its presence cannot be checked once the response has
+// been decoded (it defaults to false).
+func CallResponse(response *types.CallResponse) error {
+	if response == nil {
+		return ErrCallResponseIsNil
+	}
+
+	if response.Result == nil {
+		return ErrCallResultIsNil
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asserter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestCallClient(t *testing.T) {
+	network := &types.NetworkIdentifier{
+		Blockchain: "hello",
+		Network:    "world",
+	}
+	asserter, err := NewClientWithResponses(
+		network,
+		&types.NetworkStatusResponse{
+			GenesisBlockIdentifier: &types.BlockIdentifier{
+				Index: 0,
+				Hash:  "block 0",
+			},
+			CurrentBlockIdentifier: &types.BlockIdentifier{
+				Index: 100,
+				Hash:  "block 100",
+			},
+			CurrentBlockTimestamp: MinUnixEpoch + 1,
+		},
+		&types.NetworkOptionsResponse{
+			Version: &types.Version{
+				RosettaVersion: "1.4.0",
+				NodeVersion:    "1.0",
+			},
+			Allow: &types.Allow{
+				OperationStatuses: []*types.OperationStatus{
+					{
+						Status:     "SUCCESS",
+						Successful: true,
+					},
+				},
+				OperationTypes: []string{"PAYMENT"},
+				CallMethods:    []string{"eth_call", "eth_getBlock"},
+			},
+		},
+	)
+	assert.NoError(t, err)
+
+	var requestTests = map[string]struct {
+		request *types.CallRequest
+		err     error
+	}{
+		"supported method": {
+			request: &types.CallRequest{
+				NetworkIdentifier: network,
+				Method:            "eth_call",
+			},
+		},
+		"other supported method": {
+			request: &types.CallRequest{
+				NetworkIdentifier: network,
+				Method:            "eth_getBlock",
+				Parameters: map[string]interface{}{
+					"index": 10,
+				},
+			},
+		},
+		"unsupported method": {
+			request: &types.CallRequest{
+				NetworkIdentifier: network,
+				Method:            "eth_sendTransaction",
+			},
+			err: ErrCallMethodUnsupported,
+		},
+		"empty method": {
+			request: &types.CallRequest{
+				NetworkIdentifier: network,
+			},
+			err: ErrCallMethodEmpty,
+		},
+		"invalid network": {
+			request: &types.CallRequest{
+				Method: "eth_call",
+			},
+			err: ErrNetworkIdentifierIsNil,
+		},
+		"nil request": {
+			err: ErrCallRequestIsNil,
+		},
+	}
+
+	for name, test := range requestTests {
+		t.Run(name, func(t *testing.T) {
+			err := asserter.CallRequest(test.request)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	config, err := asserter.ToConfiguration()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"eth_call", "eth_getBlock"}, config.AllowedCallMethods)
+}
+
+func TestCallResponse(t *testing.T) {
+	var tests = map[string]struct {
+		response *types.CallResponse
+		err      error
+	}{
+		"valid response": {
+			response: &types.CallResponse{
+				Result: map[string]interface{}{
+					"hello": "world",
+				},
+				Idempotent: true,
+			},
+		},
+		"empty result": {
+			response: &types.CallResponse{
+				Result: map[string]interface{}{},
+			},
+		},
+		"nil result": {
+			response: &types.CallResponse{
+				Idempotent: true,
+			},
+			err: ErrCallResultIsNil,
+		},
+		"nil response": {
+			err: ErrCallResponseIsNil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.err, CallResponse(test.response))
+		})
+	}
+}
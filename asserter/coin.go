@@ -105,3 +105,57 @@ func CoinAction(action types.CoinAction) error {
 
 	return nil
 }
+
+// CoinChanges returns an error if the coin changes in the
+// operations of a single transaction are inconsistent. A coin
+// may only be spent once in a transaction and, if
+// rejectCreatedAndSpent is true, a coin may not be both
+// created and spent in the same transaction. Operations with
+// an unsuccessful status are ignored.
+//
+// This should only be called AFTER the operations have been
+// validated.
+func (a *Asserter) CoinChanges(
+	operations []*types.Operation,
+	rejectCreatedAndSpent bool,
+) error {
+	if a == nil {
+		return ErrAsserterNotInitialized
+	}
+
+	created := map[string]struct{}{}
+	spent := map[string]struct{}{}
+	for _, op := range operations {
+		if op.CoinChange == nil || op.CoinChange.CoinIdentifier == nil {
+			continue
+		}
+
+		if op.Status != nil && len(*op.Status) > 0 && !a.operationStatusMap[*op.Status] {
+			continue
+		}
+
+		identifier := op.CoinChange.CoinIdentifier.Identifier
+		switch op.CoinChange.CoinAction {
+		case types.CoinSpent:
+			if _, exists := spent[identifier]; exists {
+				return fmt.Errorf("%w: %s", ErrCoinSpentMultipleTimes, identifier)
+			}
+
+			spent[identifier] = struct{}{}
+		case types.CoinCreated:
+			created[identifier] = struct{}{}
+		}
+
+		if !rejectCreatedAndSpent {
+			continue
+		}
+
+		_, isCreated := created[identifier]
+		_, isSpent := spent[identifier]
+		if isCreated && isSpent {
+			return fmt.Errorf("%w: %s", ErrCoinCreatedAndSpent, identifier)
+		}
+	}
+
+	return nil
+}
@@ -189,3 +189,108 @@ func TestCoinChange(t *testing.T) {
 		})
 	}
 }
+
+func TestCoinChanges(t *testing.T) {
+	coinOperation := func(
+		index int64,
+		status string,
+		identifier string,
+		action types.CoinAction,
+	) *types.Operation {
+		op := validationsOperation(index, "PAYMENT", status, &types.AccountIdentifier{
+			Address: "addr",
+		}, "100")
+		op.CoinChange = &types.CoinChange{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: identifier,
+			},
+			CoinAction: action,
+		}
+
+		return op
+	}
+
+	var tests = map[string]struct {
+		operations            []*types.Operation
+		rejectCreatedAndSpent bool
+		err                   error
+	}{
+		"no coin changes": {
+			operations: []*types.Operation{
+				validationsOperation(0, "PAYMENT", "SUCCESS", nil, ""),
+			},
+		},
+		"spend many create many": {
+			operations: []*types.Operation{
+				coinOperation(0, "SUCCESS", "coin1", types.CoinSpent),
+				coinOperation(1, "SUCCESS", "coin2", types.CoinSpent),
+				coinOperation(2, "SUCCESS", "coin3", types.CoinCreated),
+				coinOperation(3, "SUCCESS", "coin4", types.CoinCreated),
+			},
+			rejectCreatedAndSpent: true,
+		},
+		"created and spent allowed": {
+			operations: []*types.Operation{
+				coinOperation(0, "SUCCESS", "coin1", types.CoinCreated),
+				coinOperation(1, "SUCCESS", "coin1", types.CoinSpent),
+			},
+		},
+		"created and spent rejected": {
+			operations: []*types.Operation{
+				coinOperation(0, "SUCCESS", "coin1", types.CoinCreated),
+				coinOperation(1, "SUCCESS", "coin1", types.CoinSpent),
+			},
+			rejectCreatedAndSpent: true,
+			err:                   ErrCoinCreatedAndSpent,
+		},
+		"spent and created rejected": {
+			operations: []*types.Operation{
+				coinOperation(0, "SUCCESS", "coin1", types.CoinSpent),
+				coinOperation(1, "SUCCESS", "coin1", types.CoinCreated),
+			},
+			rejectCreatedAndSpent: true,
+			err:                   ErrCoinCreatedAndSpent,
+		},
+		"double spend": {
+			operations: []*types.Operation{
+				coinOperation(0, "SUCCESS", "coin1", types.CoinSpent),
+				coinOperation(1, "SUCCESS", "coin2", types.CoinCreated),
+				coinOperation(2, "SUCCESS", "coin1", types.CoinSpent),
+			},
+			err: ErrCoinSpentMultipleTimes,
+		},
+		"double spend with failed operation": {
+			operations: []*types.Operation{
+				coinOperation(0, "FAILURE", "coin1", types.CoinSpent),
+				coinOperation(1, "SUCCESS", "coin1", types.CoinSpent),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter := validationsTestAsserter(t, &Validations{
+				RejectCoinCreatedAndSpent: test.rejectCreatedAndSpent,
+			})
+
+			err := asserter.CoinChanges(test.operations, test.rejectCreatedAndSpent)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			err = asserter.Transaction(&types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: test.operations,
+			})
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
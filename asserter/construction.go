@@ -20,6 +20,20 @@ import (
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
+const (
+	// compressedPublicKeyLen is the length of a
+	// SEC compressed secp256k1 public key.
+	compressedPublicKeyLen = 33
+
+	// schnorrBip340PublicKeyLen is the length of an
+	// x-only secp256k1 public key (as specified in BIP340).
+	schnorrBip340PublicKeyLen = 32
+
+	// schnorrBip340SignatureLen is the length of a
+	// BIP340 signature.
+	schnorrBip340SignatureLen = 64
+)
+
 // ConstructionPreprocessResponse returns an error if
 // the request public keys are not valid AccountIdentifiers.
 func ConstructionPreprocessResponse(
@@ -184,6 +198,100 @@ func ConstructionPayloadsResponse(
 	return nil
 }
 
+// ConstructionPayloadsSigners returns an error if the
+// *SigningPayload in a *types.ConstructionPayloadsResponse
+// do not cover the signers implied by the operations provided
+// to /construction/payloads (the intent). Every account with a
+// negative amount in the intent must have at least one
+// *SigningPayload and no *SigningPayload may be for an account
+// that is not in the intent. If supportedSignatureTypes is not
+// empty, any populated *SigningPayload.SignatureType must be one
+// of supportedSignatureTypes.
+//
+// This should only be called AFTER the response has been
+// asserted with ConstructionPayloadsResponse.
+func ConstructionPayloadsSigners(
+	intent []*types.Operation,
+	response *types.ConstructionPayloadsResponse,
+	supportedSignatureTypes []types.SignatureType,
+) error {
+	if response == nil {
+		return ErrConstructionPayloadsResponseIsNil
+	}
+
+	intentAccounts := map[string]struct{}{}
+	requiredSigners := map[string]*types.AccountIdentifier{}
+	for _, op := range intent {
+		if op == nil || op.Account == nil {
+			continue
+		}
+
+		key := types.Hash(op.Account)
+		intentAccounts[key] = struct{}{}
+		if op.Amount == nil {
+			continue
+		}
+
+		value, err := types.AmountValue(op.Amount)
+		if err != nil {
+			return fmt.Errorf("%w: unable to parse intent amount", err)
+		}
+
+		if value.Sign() < 0 {
+			requiredSigners[key] = op.Account
+		}
+	}
+
+	covered := map[string]struct{}{}
+	for i, payload := range response.Payloads {
+		key := types.Hash(payload.AccountIdentifier)
+		if _, ok := intentAccounts[key]; !ok {
+			return fmt.Errorf(
+				"%w: signing payload %d is for %s",
+				ErrSigningPayloadAccountNotInIntent,
+				i,
+				types.PrintStruct(payload.AccountIdentifier),
+			)
+		}
+		covered[key] = struct{}{}
+
+		if len(payload.SignatureType) == 0 || len(supportedSignatureTypes) == 0 {
+			continue
+		}
+
+		if !containsSignatureType(supportedSignatureTypes, payload.SignatureType) {
+			return fmt.Errorf(
+				"%w: signing payload %d has signature type %s",
+				ErrSigningPayloadSignatureTypeUnsupported,
+				i,
+				payload.SignatureType,
+			)
+		}
+	}
+
+	for key, account := range requiredSigners {
+		if _, ok := covered[key]; !ok {
+			return fmt.Errorf(
+				"%w: %s",
+				ErrSigningPayloadSignerMissing,
+				types.PrintStruct(account),
+			)
+		}
+	}
+
+	return nil
+}
+
+func containsSignatureType(valid []types.SignatureType, value types.SignatureType) bool {
+	for _, v := range valid {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 // PublicKey returns an error if
 // the *types.PublicKey is nil, is not
 // valid hex, or has an undefined CurveType.
@@ -293,6 +401,57 @@ func Signatures(
 		if BytesArrayZero(signature.Bytes) {
 			return ErrSignatureBytesZero
 		}
+
+		if err := SignatureLength(signature); err != nil {
+			return fmt.Errorf("%w: signature %d has invalid length", err, i)
+		}
+	}
+
+	return nil
+}
+
+// SignatureLength returns an error if a
+// *types.Signature with a fixed-length SignatureType
+// has the wrong number of bytes or a PublicKey that
+// cannot be used with that SignatureType.
+//
+// Currently, only types.SchnorrBip340 signatures are checked
+// (64-byte signatures with a 32-byte x-only or 33-byte
+// compressed secp256k1 public key).
+func SignatureLength(
+	signature *types.Signature,
+) error {
+	if signature.SignatureType != types.SchnorrBip340 {
+		return nil
+	}
+
+	if signature.PublicKey.CurveType != types.Secp256k1 {
+		return fmt.Errorf(
+			"%w: %s does not support %s",
+			ErrSignatureTypeCurveTypeMismatch,
+			signature.PublicKey.CurveType,
+			signature.SignatureType,
+		)
+	}
+
+	if len(signature.PublicKey.Bytes) != schnorrBip340PublicKeyLen &&
+		len(signature.PublicKey.Bytes) != compressedPublicKeyLen {
+		return fmt.Errorf(
+			"%w: expected %d or %d bytes but got %d",
+			ErrPublicKeyBytesLengthInvalid,
+			schnorrBip340PublicKeyLen,
+			compressedPublicKeyLen,
+			len(signature.PublicKey.Bytes),
+		)
+	}
+
+	if len(signature.Bytes) != schnorrBip340SignatureLen {
+		return fmt.Errorf(
+			"%w: expected %d bytes but got %d",
+			ErrSignatureBytesLengthInvalid,
+			schnorrBip340SignatureLen,
+			len(signature.Bytes),
+		)
 	}
 
 	return nil
@@ -304,7 +463,12 @@ func SignatureType(
 	signature types.SignatureType,
 ) error {
 	switch signature {
-	case types.Ecdsa, types.EcdsaRecovery, types.Ed25519, types.Schnorr1, types.SchnorrPoseidon:
+	case types.Ecdsa,
+		types.EcdsaRecovery,
+		types.Ed25519,
+		types.Schnorr1,
+		types.SchnorrPoseidon,
+		types.SchnorrBip340:
 		return nil
 	default:
 		return fmt.Errorf("%w: %s", ErrSignatureTypeNotSupported, signature)
@@ -15,6 +15,7 @@
 package asserter
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"testing"
@@ -580,6 +581,126 @@ func TestConstructionPayloadsResponse(t *testing.T) {
 	}
 }
 
+func TestConstructionPayloadsSigners(t *testing.T) {
+	var (
+		alice = &types.AccountIdentifier{
+			Address: "alice",
+		}
+		bob = &types.AccountIdentifier{
+			Address: "bob",
+		}
+		carol = &types.AccountIdentifier{
+			Address: "carol",
+		}
+		currency = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 8,
+		}
+		intentOperation = func(index int64, account *types.AccountIdentifier, value string) *types.Operation {
+			return &types.Operation{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: index,
+				},
+				Type:    "PAYMENT",
+				Account: account,
+				Amount: &types.Amount{
+					Value:    value,
+					Currency: currency,
+				},
+			}
+		}
+		payload = func(account *types.AccountIdentifier, signatureType types.SignatureType) *types.SigningPayload {
+			return &types.SigningPayload{
+				AccountIdentifier: account,
+				Bytes:             []byte("48656c6c6f20476f7068657221"),
+				SignatureType:     signatureType,
+			}
+		}
+
+		// Alice and Bob both fund a payment to Carol
+		// (so both must sign).
+		multisigIntent = []*types.Operation{
+			intentOperation(0, alice, "-50"),
+			intentOperation(1, bob, "-50"),
+			intentOperation(2, carol, "100"),
+		}
+	)
+
+	var tests = map[string]struct {
+		intent         []*types.Operation
+		payloads       []*types.SigningPayload
+		signatureTypes []types.SignatureType
+		err            error
+	}{
+		"multisig covered": {
+			intent: multisigIntent,
+			payloads: []*types.SigningPayload{
+				payload(alice, types.Ecdsa),
+				payload(bob, types.Ecdsa),
+			},
+			signatureTypes: []types.SignatureType{types.Ecdsa},
+		},
+		"multisig covered with multiple payloads per signer": {
+			intent: multisigIntent,
+			payloads: []*types.SigningPayload{
+				payload(alice, ""),
+				payload(alice, ""),
+				payload(bob, ""),
+			},
+		},
+		"multisig missing signer": {
+			intent: multisigIntent,
+			payloads: []*types.SigningPayload{
+				payload(alice, types.Ecdsa),
+			},
+			err: ErrSigningPayloadSignerMissing,
+		},
+		"payload for recipient": {
+			intent: multisigIntent,
+			payloads: []*types.SigningPayload{
+				payload(alice, types.Ecdsa),
+				payload(bob, types.Ecdsa),
+				payload(carol, types.Ecdsa),
+			},
+		},
+		"payload for account not in intent": {
+			intent: multisigIntent,
+			payloads: []*types.SigningPayload{
+				payload(alice, types.Ecdsa),
+				payload(bob, types.Ecdsa),
+				payload(&types.AccountIdentifier{Address: "dave"}, types.Ecdsa),
+			},
+			err: ErrSigningPayloadAccountNotInIntent,
+		},
+		"unsupported signature type": {
+			intent: multisigIntent,
+			payloads: []*types.SigningPayload{
+				payload(alice, types.Ecdsa),
+				payload(bob, types.Ed25519),
+			},
+			signatureTypes: []types.SignatureType{types.Ecdsa, types.EcdsaRecovery},
+			err:            ErrSigningPayloadSignatureTypeUnsupported,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			response := &types.ConstructionPayloadsResponse{
+				UnsignedTransaction: "tx blob",
+				Payloads:            test.payloads,
+			}
+			assert.NoError(t, ConstructionPayloadsResponse(response))
+
+			err := ConstructionPayloadsSigners(test.intent, response, test.signatureTypes)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestPublicKey(t *testing.T) {
 	var tests = map[string]struct {
 		publicKey *types.PublicKey
@@ -799,6 +920,94 @@ func TestSignatures(t *testing.T) {
 			},
 			err: ErrSignaturesReturnedSigMismatch,
 		},
+		"valid schnorr bip340 signature (x-only public key)": {
+			signatures: []*types.Signature{
+				{
+					SigningPayload: &types.SigningPayload{
+						AccountIdentifier: validAccount,
+						Bytes:             []byte("blah"),
+						SignatureType:     types.SchnorrBip340,
+					},
+					PublicKey: &types.PublicKey{
+						Bytes:     bytes.Repeat([]byte{2}, 32),
+						CurveType: types.Secp256k1,
+					},
+					SignatureType: types.SchnorrBip340,
+					Bytes:         bytes.Repeat([]byte{1}, 64),
+				},
+			},
+		},
+		"valid schnorr bip340 signature (compressed public key)": {
+			signatures: []*types.Signature{
+				{
+					SigningPayload: &types.SigningPayload{
+						AccountIdentifier: validAccount,
+						Bytes:             []byte("blah"),
+						SignatureType:     types.SchnorrBip340,
+					},
+					PublicKey: &types.PublicKey{
+						Bytes:     bytes.Repeat([]byte{2}, 33),
+						CurveType: types.Secp256k1,
+					},
+					SignatureType: types.SchnorrBip340,
+					Bytes:         bytes.Repeat([]byte{1}, 64),
+				},
+			},
+		},
+		"invalid schnorr bip340 signature length": {
+			signatures: []*types.Signature{
+				{
+					SigningPayload: &types.SigningPayload{
+						AccountIdentifier: validAccount,
+						Bytes:             []byte("blah"),
+						SignatureType:     types.SchnorrBip340,
+					},
+					PublicKey: &types.PublicKey{
+						Bytes:     bytes.Repeat([]byte{2}, 32),
+						CurveType: types.Secp256k1,
+					},
+					SignatureType: types.SchnorrBip340,
+					Bytes:         bytes.Repeat([]byte{1}, 65),
+				},
+			},
+			err: ErrSignatureBytesLengthInvalid,
+		},
+		"invalid schnorr bip340 public key length": {
+			signatures: []*types.Signature{
+				{
+					SigningPayload: &types.SigningPayload{
+						AccountIdentifier: validAccount,
+						Bytes:             []byte("blah"),
+						SignatureType:     types.SchnorrBip340,
+					},
+					PublicKey: &types.PublicKey{
+						Bytes:     []byte("hello"),
+						CurveType: types.Secp256k1,
+					},
+					SignatureType: types.SchnorrBip340,
+					Bytes:         bytes.Repeat([]byte{1}, 64),
+				},
+			},
+			err: ErrPublicKeyBytesLengthInvalid,
+		},
+		"invalid schnorr bip340 curve type": {
+			signatures: []*types.Signature{
+				{
+					SigningPayload: &types.SigningPayload{
+						AccountIdentifier: validAccount,
+						Bytes:             []byte("blah"),
+						SignatureType:     types.SchnorrBip340,
+					},
+					PublicKey: &types.PublicKey{
+						Bytes:     bytes.Repeat([]byte{2}, 32),
+						CurveType: types.Edwards25519,
+					},
+					SignatureType: types.SchnorrBip340,
+					Bytes:         bytes.Repeat([]byte{1}, 64),
+				},
+			},
+			err: ErrSignatureTypeCurveTypeMismatch,
+		},
 	}
 
 	for name, test := range tests {
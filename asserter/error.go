@@ -15,13 +15,18 @@
 package asserter
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 // Error ensures a *types.Error matches some error
-// provided in `/network/options`.
+// provided in `/network/options` (both the code and the
+// retriable flag must match) and that any details are
+// a valid JSON map. If the Asserter was not initialized
+// with `/network/options` (i.e. for use in a server),
+// only the format of the *types.Error is checked.
 func (a *Asserter) Error(
 	err *types.Error,
 ) error {
@@ -33,6 +38,14 @@ func (a *Asserter) Error(
 		return err
 	}
 
+	if err := errorDetails(err.Details); err != nil {
+		return err
+	}
+
+	if a.errorTypeMap == nil {
+		return nil
+	}
+
 	val, ok := a.errorTypeMap[err.Code]
 	if !ok {
 		return fmt.Errorf(
@@ -53,12 +66,29 @@ func (a *Asserter) Error(
 
 	if val.Retriable != err.Retriable {
 		return fmt.Errorf(
-			"%w: expected %s actual %s",
+			"%w: expected %t actual %t for code %d",
 			ErrErrorRetriableMismatch,
-			val.Message,
-			err.Message,
+			val.Retriable,
+			err.Retriable,
+			err.Code,
 		)
 	}
 
 	return nil
 }
+
+// errorDetails returns an error if *types.Error.Details
+// contains an empty key or cannot be encoded as JSON.
+func errorDetails(details map[string]interface{}) error {
+	for k := range details {
+		if len(k) == 0 {
+			return fmt.Errorf("%w: empty key", ErrErrorDetailsInvalid)
+		}
+	}
+
+	if _, err := json.Marshal(details); err != nil {
+		return fmt.Errorf("%w: %s", ErrErrorDetailsInvalid, err.Error())
+	}
+
+	return nil
+}
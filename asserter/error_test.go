@@ -16,6 +16,7 @@ package asserter
 
 import (
 	"errors"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -93,6 +94,36 @@ func TestErrorMap(t *testing.T) {
 			},
 			expectedErr: ErrErrorMessageMismatch,
 		},
+		"details with empty key": {
+			err: &types.Error{
+				Code:      10,
+				Message:   "error 10",
+				Retriable: true,
+				Details: map[string]interface{}{
+					"": "goodbye",
+				},
+			},
+			expectedErr: ErrErrorDetailsInvalid,
+		},
+		"details not encodable": {
+			err: &types.Error{
+				Code:      10,
+				Message:   "error 10",
+				Retriable: true,
+				Details: map[string]interface{}{
+					"hello": math.Inf(1),
+				},
+			},
+			expectedErr: ErrErrorDetailsInvalid,
+		},
+		"non-retriable error marked retriable": {
+			err: &types.Error{
+				Code:      1,
+				Message:   "error 1",
+				Retriable: true,
+			},
+			expectedErr: ErrErrorRetriableMismatch,
+		},
 	}
 
 	for name, test := range tests {
@@ -163,3 +194,29 @@ func TestErrorMap(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorServer(t *testing.T) {
+	asserter, err := NewServer(
+		[]string{"PAYMENT"},
+		false,
+		[]*types.NetworkIdentifier{
+			{
+				Blockchain: "hello",
+				Network:    "world",
+			},
+		},
+		nil,
+		false,
+	)
+	assert.NoError(t, err)
+
+	// Servers are not initialized with an error catalog,
+	// so only the format of the error is asserted.
+	assert.NoError(t, asserter.Error(&types.Error{
+		Code:    20,
+		Message: "error 20",
+	}))
+	assert.True(t, errors.Is(asserter.Error(&types.Error{
+		Code: 20,
+	}), ErrErrorMessageMissing))
+}
@@ -70,7 +70,12 @@ var (
 	ErrRelatedOperationIndexOutOfOrder = errors.New(
 		"related operation has index greater than operation",
 	)
-	ErrRelatedOperationIndexDuplicate     = errors.New("found duplicate related operation index")
+	ErrRelatedOperationIndexDuplicate = errors.New("found duplicate related operation index")
+	ErrRelatedOperationIndexNotFound  = errors.New(
+		"related operation index not found in transaction",
+	)
+	ErrRelatedOperationSelfReference      = errors.New("operation references itself")
+	ErrRelatedOperationCycle              = errors.New("related operations contain a cycle")
 	ErrBlockIdentifierIsNil               = errors.New("BlockIdentifier is nil")
 	ErrBlockIdentifierHashMissing         = errors.New("BlockIdentifier.Hash is missing")
 	ErrBlockIdentifierIndexIsNeg          = errors.New("BlockIdentifier.Index is negative")
@@ -82,8 +87,8 @@ var (
 	ErrTxIdentifierHashMissing        = errors.New("TransactionIdentifier.Hash is missing")
 	ErrNoOperationsForConstruction    = errors.New("operations cannot be empty for construction")
 	ErrTxIsNil                        = errors.New("Transaction is nil")
-	ErrTimestampBeforeMin             = errors.New("timestamp is before 01/01/2000")
-	ErrTimestampAfterMax              = errors.New("timestamp is after 01/01/2040")
+	ErrTimestampBeforeMin             = errors.New("timestamp is before the minimum timestamp")
+	ErrTimestampAfterMax              = errors.New("timestamp is after the maximum timestamp")
 	ErrBlockIsNil                     = errors.New("Block is nil")
 	ErrBlockHashEqualsParentBlockHash = errors.New(
 		"BlockIdentifier.Hash == ParentBlockIdentifier.Hash",
@@ -95,6 +100,18 @@ var (
 		"invalid direction (must be 'forward' or 'backward')",
 	)
 	ErrDuplicateRelatedTransaction = errors.New("duplicate related transaction")
+	ErrRelatedTransactionIsNil     = errors.New("RelatedTransaction is nil")
+	ErrOperationAccountMissing     = errors.New("Operation.Account is missing")
+	ErrFeeOperationMissing         = errors.New("transaction does not contain a fee operation")
+	ErrFeeOperationNotNegative     = errors.New("fee operation amount is not negative")
+	ErrTooManyFeeOperations        = errors.New("transaction contains too many fee operations")
+	ErrPaymentsUnbalanced          = errors.New("payment operations do not sum to zero")
+	ErrTooManyTransactions         = errors.New("block contains too many transactions")
+	ErrTooManyOperations           = errors.New("transaction contains too many operations")
+	ErrMetadataTooLarge            = errors.New("metadata is too large")
+	ErrCurrencyInconsistent        = errors.New(
+		"currencies with the same symbol have different decimals or metadata",
+	)
 
 	BlockErrs = []error{
 		ErrAmountValueMissing,
@@ -115,6 +132,9 @@ var (
 		ErrOperationStatusNotEmptyForConstruction,
 		ErrRelatedOperationIndexOutOfOrder,
 		ErrRelatedOperationIndexDuplicate,
+		ErrRelatedOperationIndexNotFound,
+		ErrRelatedOperationSelfReference,
+		ErrRelatedOperationCycle,
 		ErrBlockIdentifierIsNil,
 		ErrBlockIdentifierHashMissing,
 		ErrBlockIdentifierIndexIsNeg,
@@ -131,17 +151,33 @@ var (
 		ErrBlockIndexPrecedesParentBlockIndex,
 		ErrInvalidDirection,
 		ErrDuplicateRelatedTransaction,
+		ErrRelatedTransactionIsNil,
+		ErrOperationAccountMissing,
+		ErrFeeOperationMissing,
+		ErrFeeOperationNotNegative,
+		ErrTooManyFeeOperations,
+		ErrPaymentsUnbalanced,
+		ErrCurrencyInconsistent,
+		ErrTooManyTransactions,
+		ErrTooManyOperations,
+		ErrMetadataTooLarge,
 	}
 )
 
 // Coin Errors
 var (
-	ErrCoinIsNil            = errors.New("coin cannot be nil")
-	ErrCoinDuplicate        = errors.New("duplicate coin identifier detected")
-	ErrCoinIdentifierIsNil  = errors.New("coin identifier cannot be nil")
-	ErrCoinIdentifierNotSet = errors.New("coin identifier cannot be empty")
-	ErrCoinChangeIsNil      = errors.New("coin change cannot be nil")
-	ErrCoinActionInvalid    = errors.New("not a valid coin action")
+	ErrCoinIsNil              = errors.New("coin cannot be nil")
+	ErrCoinDuplicate          = errors.New("duplicate coin identifier detected")
+	ErrCoinIdentifierIsNil    = errors.New("coin identifier cannot be nil")
+	ErrCoinIdentifierNotSet   = errors.New("coin identifier cannot be empty")
+	ErrCoinChangeIsNil        = errors.New("coin change cannot be nil")
+	ErrCoinActionInvalid      = errors.New("not a valid coin action")
+	ErrCoinSpentMultipleTimes = errors.New(
+		"coin spent more than once in the same transaction",
+	)
+	ErrCoinCreatedAndSpent = errors.New(
+		"coin created and spent in the same transaction",
+	)
 
 	CoinErrs = []error{
 		ErrCoinIsNil,
@@ -150,6 +186,8 @@ var (
 		ErrCoinIdentifierNotSet,
 		ErrCoinChangeIsNil,
 		ErrCoinActionInvalid,
+		ErrCoinSpentMultipleTimes,
+		ErrCoinCreatedAndSpent,
 	}
 )
 
@@ -197,6 +235,7 @@ var (
 	ErrPublicKeyIsNil                            = errors.New("PublicKey cannot be nil")
 	ErrPublicKeyBytesEmpty                       = errors.New("public key bytes cannot be empty")
 	ErrPublicKeyBytesZero                        = errors.New("public key bytes 0")
+	ErrPublicKeyBytesLengthInvalid               = errors.New("invalid public key bytes length")
 	ErrCurveTypeNotSupported                     = errors.New("not a supported CurveType")
 	ErrSigningPayloadIsNil                       = errors.New("signing payload cannot be nil")
 	ErrSigningPayloadAddrEmpty                   = errors.New(
@@ -212,9 +251,22 @@ var (
 	ErrSignaturesReturnedSigMismatch = errors.New(
 		"requested signature type does not match returned signature type",
 	)
-	ErrSignatureBytesEmpty       = errors.New("signature bytes cannot be empty")
-	ErrSignatureBytesZero        = errors.New("signature bytes cannot be 0")
-	ErrSignatureTypeNotSupported = errors.New("not a supported SignatureType")
+	ErrSignatureBytesEmpty            = errors.New("signature bytes cannot be empty")
+	ErrSignatureBytesZero             = errors.New("signature bytes cannot be 0")
+	ErrSignatureTypeNotSupported      = errors.New("not a supported SignatureType")
+	ErrSignatureBytesLengthInvalid    = errors.New("invalid signature bytes length")
+	ErrSignatureTypeCurveTypeMismatch = errors.New(
+		"signature type is not supported by public key curve type",
+	)
+	ErrSigningPayloadSignerMissing = errors.New(
+		"no signing payload for a signer in the intent",
+	)
+	ErrSigningPayloadAccountNotInIntent = errors.New(
+		"signing payload account is not in the intent",
+	)
+	ErrSigningPayloadSignatureTypeUnsupported = errors.New(
+		"signing payload signature type is not supported",
+	)
 
 	ConstructionErrs = []error{
 		ErrConstructionPreprocessResponseIsNil,
@@ -236,6 +288,7 @@ var (
 		ErrPublicKeyIsNil,
 		ErrPublicKeyBytesEmpty,
 		ErrPublicKeyBytesZero,
+		ErrPublicKeyBytesLengthInvalid,
 		ErrCurveTypeNotSupported,
 		ErrSigningPayloadIsNil,
 		ErrSigningPayloadAddrEmpty,
@@ -246,6 +299,11 @@ var (
 		ErrSignatureBytesEmpty,
 		ErrSignatureBytesZero,
 		ErrSignatureTypeNotSupported,
+		ErrSignatureBytesLengthInvalid,
+		ErrSignatureTypeCurveTypeMismatch,
+		ErrSigningPayloadSignerMissing,
+		ErrSigningPayloadAccountNotInIntent,
+		ErrSigningPayloadSignatureTypeUnsupported,
 	}
 )
 
@@ -288,6 +346,9 @@ var (
 	ErrTimestampStartIndexInvalid = errors.New(
 		"TimestampStartIndex is invalid",
 	)
+	ErrTimestampBoundsInvalid = errors.New(
+		"minimum timestamp is greater than maximum timestamp",
+	)
 	ErrSyncStatusCurrentIndexNegative = errors.New(
 		"SyncStatus.CurrentIndex is negative",
 	)
@@ -322,6 +383,7 @@ var (
 		ErrBalanceExemptionSubAccountAddressEmpty,
 		ErrBalanceExemptionNoHistoricalLookup,
 		ErrTimestampStartIndexInvalid,
+		ErrTimestampBoundsInvalid,
 		ErrSyncStatusCurrentIndexNegative,
 		ErrSyncStatusTargetIndexNegative,
 		ErrSyncStatusStageInvalid,
@@ -433,27 +495,48 @@ var (
 
 // Events Errors
 var (
-	ErrMaxSequenceInvalid    = errors.New("max sequence invalid")
-	ErrSequenceInvalid       = errors.New("sequence invalid")
-	ErrBlockEventTypeInvalid = errors.New("block event type invalid")
-	ErrSequenceOutOfOrder    = errors.New("sequence out of order")
+	ErrMaxSequenceInvalid             = errors.New("max sequence invalid")
+	ErrSequenceInvalid                = errors.New("sequence invalid")
+	ErrBlockEventTypeInvalid          = errors.New("block event type invalid")
+	ErrSequenceOutOfOrder             = errors.New("sequence out of order")
+	ErrBlockEventIsNil                = errors.New("block event is nil")
+	ErrSequenceGreaterThanMaxSequence = errors.New("sequence greater than max sequence")
+	ErrSequenceStartMismatch          = errors.New("first sequence does not match start sequence")
 
 	EventsErrs = []error{
 		ErrMaxSequenceInvalid,
 		ErrSequenceInvalid,
 		ErrBlockEventTypeInvalid,
 		ErrSequenceOutOfOrder,
+		ErrBlockEventIsNil,
+		ErrSequenceGreaterThanMaxSequence,
+		ErrSequenceStartMismatch,
 	}
 )
 
 // Search Errors
 var (
-	ErrNextOffsetInvalid = errors.New("next offset invalid")
-	ErrTotalCountInvalid = errors.New("total count invalid")
+	ErrNextOffsetInvalid              = errors.New("next offset invalid")
+	ErrTotalCountInvalid              = errors.New("total count invalid")
+	ErrBlockTransactionIsNil          = errors.New("block transaction is nil")
+	ErrNextOffsetNotGreaterThanOffset = errors.New("next offset is not greater than offset")
 
 	SearchErrs = []error{
 		ErrNextOffsetInvalid,
 		ErrTotalCountInvalid,
+		ErrBlockTransactionIsNil,
+		ErrNextOffsetNotGreaterThanOffset,
+	}
+)
+
+// Call Errors
+var (
+	ErrCallResponseIsNil = errors.New("CallResponse is nil")
+	ErrCallResultIsNil   = errors.New("CallResponse.Result is nil")
+
+	CallErrs = []error{
+		ErrCallResponseIsNil,
+		ErrCallResultIsNil,
 	}
 )
 
@@ -467,6 +550,7 @@ var (
 		"Error.Message does not match message from /network/options",
 	)
 	ErrErrorRetriableMismatch = errors.New("Error.Retriable mismatch")
+	ErrErrorDetailsInvalid    = errors.New("Error.Details is not a valid map")
 	ErrErrorDescriptionEmpty  = errors.New(
 		"Error.Description is provided but is empty",
 	)
@@ -478,6 +562,7 @@ var (
 		ErrErrorUnexpectedCode,
 		ErrErrorMessageMismatch,
 		ErrErrorRetriableMismatch,
+		ErrErrorDetailsInvalid,
 		ErrErrorDescriptionEmpty,
 	}
 )
@@ -496,6 +581,7 @@ func Err(err error) (bool, string) {
 		"events error":          EventsErrs,
 		"search error":          SearchErrs,
 		"error error":           ErrorErrs,
+		"call error":            CallErrs,
 	}
 
 	for key, val := range assertErrs {
@@ -15,6 +15,8 @@
 package asserter
 
 import (
+	"fmt"
+
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -23,6 +25,10 @@ import (
 func BlockEvent(
 	event *types.BlockEvent,
 ) error {
+	if event == nil {
+		return ErrBlockEventIsNil
+	}
+
 	if event.Sequence < 0 {
 		return ErrSequenceInvalid
 	}
@@ -41,11 +47,19 @@ func BlockEvent(
 }
 
 // EventsBlocksResponse ensures a *types.EventsBlocksResponse
-// is valid.
+// is valid. Events must have consecutive (strictly increasing)
+// sequences that do not exceed maxSequence (usually the
+// MaxSequence of the response).
+//
+// If startSequence is provided (usually the offset of the
+// request), the first event must have that sequence (so
+// that gaps between pages of events can be detected).
 func EventsBlocksResponse(
+	maxSequence int64,
 	response *types.EventsBlocksResponse,
+	startSequence *int64,
 ) error {
-	if response.MaxSequence < 0 {
+	if maxSequence < 0 || response.MaxSequence < 0 {
 		return ErrMaxSequenceInvalid
 	}
 
@@ -57,11 +71,29 @@ func EventsBlocksResponse(
 
 		if seq == -1 {
 			seq = event.Sequence
+
+			if startSequence != nil && seq != *startSequence {
+				return fmt.Errorf(
+					"%w: expected %d but got %d",
+					ErrSequenceStartMismatch,
+					*startSequence,
+					seq,
+				)
+			}
 		}
 
 		if event.Sequence != seq+int64(i) {
 			return ErrSequenceOutOfOrder
 		}
+
+		if event.Sequence > maxSequence {
+			return fmt.Errorf(
+				"%w: sequence %d > max sequence %d",
+				ErrSequenceGreaterThanMaxSequence,
+				event.Sequence,
+				maxSequence,
+			)
+		}
 	}
 
 	return nil
@@ -24,8 +24,10 @@ import (
 
 func TestEventsBlocksResponse(t *testing.T) {
 	tests := map[string]struct {
-		response *types.EventsBlocksResponse
-		err      error
+		maxSequence   *int64
+		startSequence *int64
+		response      *types.EventsBlocksResponse
+		err           error
 	}{
 		"no events": {
 			response: &types.EventsBlocksResponse{},
@@ -36,6 +38,38 @@ func TestEventsBlocksResponse(t *testing.T) {
 			},
 			err: ErrMaxSequenceInvalid,
 		},
+		"invalid provided max": {
+			maxSequence: types.Int64(-1),
+			response: &types.EventsBlocksResponse{
+				MaxSequence: 100,
+			},
+			err: ErrMaxSequenceInvalid,
+		},
+		"sequence greater than provided max": {
+			maxSequence: types.Int64(0),
+			response: &types.EventsBlocksResponse{
+				MaxSequence: 100,
+				Events: []*types.BlockEvent{
+					{
+						Sequence: 0,
+						BlockIdentifier: &types.BlockIdentifier{
+							Hash:  "0",
+							Index: 0,
+						},
+						Type: types.ADDED,
+					},
+					{
+						Sequence: 1,
+						BlockIdentifier: &types.BlockIdentifier{
+							Hash:  "0",
+							Index: 0,
+						},
+						Type: types.REMOVED,
+					},
+				},
+			},
+			err: ErrSequenceGreaterThanMaxSequence,
+		},
 		"valid event": {
 			response: &types.EventsBlocksResponse{
 				MaxSequence: 100,
@@ -178,12 +212,107 @@ func TestEventsBlocksResponse(t *testing.T) {
 			},
 			err: ErrSequenceInvalid,
 		},
+		"sequence greater than max sequence": {
+			response: &types.EventsBlocksResponse{
+				MaxSequence: 1,
+				Events: []*types.BlockEvent{
+					{
+						Sequence: 1,
+						BlockIdentifier: &types.BlockIdentifier{
+							Hash:  "0",
+							Index: 0,
+						},
+						Type: types.ADDED,
+					},
+					{
+						Sequence: 2,
+						BlockIdentifier: &types.BlockIdentifier{
+							Hash:  "0",
+							Index: 0,
+						},
+						Type: types.REMOVED,
+					},
+				},
+			},
+			err: ErrSequenceGreaterThanMaxSequence,
+		},
+		"expected start sequence": {
+			startSequence: types.Int64(4),
+			response: &types.EventsBlocksResponse{
+				MaxSequence: 100,
+				Events: []*types.BlockEvent{
+					{
+						Sequence: 4,
+						BlockIdentifier: &types.BlockIdentifier{
+							Hash:  "0",
+							Index: 0,
+						},
+						Type: types.ADDED,
+					},
+					{
+						Sequence: 5,
+						BlockIdentifier: &types.BlockIdentifier{
+							Hash:  "0",
+							Index: 0,
+						},
+						Type: types.REMOVED,
+					},
+				},
+			},
+		},
+		"gap between pages": {
+			startSequence: types.Int64(4),
+			response: &types.EventsBlocksResponse{
+				MaxSequence: 100,
+				Events: []*types.BlockEvent{
+					{
+						Sequence: 6,
+						BlockIdentifier: &types.BlockIdentifier{
+							Hash:  "0",
+							Index: 0,
+						},
+						Type: types.ADDED,
+					},
+				},
+			},
+			err: ErrSequenceStartMismatch,
+		},
+		"start sequence with no events": {
+			startSequence: types.Int64(4),
+			response: &types.EventsBlocksResponse{
+				MaxSequence: 3,
+			},
+		},
+		"nil event": {
+			response: &types.EventsBlocksResponse{
+				MaxSequence: 100,
+				Events: []*types.BlockEvent{
+					{
+						Sequence: 0,
+						BlockIdentifier: &types.BlockIdentifier{
+							Hash:  "0",
+							Index: 0,
+						},
+						Type: types.ADDED,
+					},
+					nil,
+				},
+			},
+			err: ErrBlockEventIsNil,
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
+			maxSequence := test.response.MaxSequence
+			if test.maxSequence != nil {
+				maxSequence = *test.maxSequence
+			}
+
 			err := EventsBlocksResponse(
+				maxSequence,
 				test.response,
+				test.startSequence,
 			)
 			if test.err != nil {
 				assert.Contains(t, err.Error(), test.err.Error())
@@ -0,0 +1,134 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asserter
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// DefaultMaxOperationsPerTransaction is the default maximum
+	// number of operations in a transaction.
+	DefaultMaxOperationsPerTransaction = 1000000
+
+	// DefaultMaxTransactionsPerBlock is the default maximum
+	// number of transactions in a block.
+	DefaultMaxTransactionsPerBlock = 1000000
+
+	// DefaultMaxMetadataBytes is the default maximum size
+	// of any metadata object (after JSON encoding).
+	DefaultMaxMetadataBytes = 10 * 1024 * 1024 // 10 MB
+)
+
+// Limits caps the size of the responses asserted by
+// a client Asserter. Limits are checked before any other
+// assertion so that an oversized response is rejected before
+// it is traversed. Any limit that is <= 0 is set to its default.
+//
+// Limits are only checked if provided with WithLimits (checking
+// MaxMetadataBytes requires encoding each metadata object).
+type Limits struct {
+	MaxOperationsPerTransaction int64 `json:"max_operations_per_transaction,omitempty"`
+	MaxTransactionsPerBlock     int64 `json:"max_transactions_per_block,omitempty"`
+	MaxMetadataBytes            int64 `json:"max_metadata_bytes,omitempty"`
+}
+
+// DefaultLimits returns the *Limits used for
+// any limit that is not provided to WithLimits.
+func DefaultLimits() *Limits {
+	return &Limits{
+		MaxOperationsPerTransaction: DefaultMaxOperationsPerTransaction,
+		MaxTransactionsPerBlock:     DefaultMaxTransactionsPerBlock,
+		MaxMetadataBytes:            DefaultMaxMetadataBytes,
+	}
+}
+
+// WithLimits enables *Limits for a client
+// Asserter (if limits is not nil).
+func WithLimits(limits *Limits) Option {
+	return func(a *Asserter) {
+		if limits == nil {
+			return
+		}
+
+		parsed := DefaultLimits()
+		if limits.MaxOperationsPerTransaction > 0 {
+			parsed.MaxOperationsPerTransaction = limits.MaxOperationsPerTransaction
+		}
+
+		if limits.MaxTransactionsPerBlock > 0 {
+			parsed.MaxTransactionsPerBlock = limits.MaxTransactionsPerBlock
+		}
+
+		if limits.MaxMetadataBytes > 0 {
+			parsed.MaxMetadataBytes = limits.MaxMetadataBytes
+		}
+
+		a.limits = parsed
+	}
+}
+
+func (a *Asserter) transactionCount(count int) error {
+	if a.limits == nil || int64(count) <= a.limits.MaxTransactionsPerBlock {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: %d > %d",
+		ErrTooManyTransactions,
+		count,
+		a.limits.MaxTransactionsPerBlock,
+	)
+}
+
+func (a *Asserter) operationCount(count int) error {
+	if a.limits == nil || int64(count) <= a.limits.MaxOperationsPerTransaction {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: %d > %d",
+		ErrTooManyOperations,
+		count,
+		a.limits.MaxOperationsPerTransaction,
+	)
+}
+
+// metadataSize returns an error if the JSON encoding of
+// metadata is larger than the configured limit. Each metadata
+// object is encoded exactly once (nested metadata objects
+// are checked independently).
+func (a *Asserter) metadataSize(metadata map[string]interface{}) error {
+	if a.limits == nil || len(metadata) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("%w: unable to encode metadata", err)
+	}
+
+	if int64(len(encoded)) > a.limits.MaxMetadataBytes {
+		return fmt.Errorf(
+			"%w: %d bytes > %d bytes",
+			ErrMetadataTooLarge,
+			len(encoded),
+			a.limits.MaxMetadataBytes,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asserter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func limitsTestBlock(transactions int, operations int, metadata int) *types.Block {
+	block := &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: 1,
+			Hash:  "block 1",
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		Timestamp: MinUnixEpoch + 1,
+	}
+
+	for i := 0; i < transactions; i++ {
+		tx := &types.Transaction{
+			TransactionIdentifier: &types.TransactionIdentifier{
+				Hash: fmt.Sprintf("tx %d", i),
+			},
+		}
+		for j := 0; j < operations; j++ {
+			tx.Operations = append(tx.Operations, &types.Operation{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: int64(j),
+				},
+				Type:   "PAYMENT",
+				Status: types.String("SUCCESS"),
+			})
+		}
+		block.Transactions = append(block.Transactions, tx)
+	}
+
+	if metadata > 0 {
+		block.Metadata = map[string]interface{}{
+			"blob": strings.Repeat("a", metadata),
+		}
+	}
+
+	return block
+}
+
+func TestLimits(t *testing.T) {
+	largeMetadata := map[string]interface{}{
+		"blob": strings.Repeat("a", 2048),
+	}
+
+	var tests = map[string]struct {
+		limits *Limits
+		block  *types.Block
+		err    error
+	}{
+		"no limits": {
+			block: limitsTestBlock(10, 10, 2048),
+		},
+		"default limits": {
+			limits: &Limits{},
+			block:  limitsTestBlock(10, 10, 2048),
+		},
+		"within limits": {
+			limits: &Limits{
+				MaxOperationsPerTransaction: 10,
+				MaxTransactionsPerBlock:     10,
+				MaxMetadataBytes:            4096,
+			},
+			block: limitsTestBlock(10, 10, 2048),
+		},
+		"too many transactions": {
+			limits: &Limits{
+				MaxTransactionsPerBlock: 10,
+			},
+			block: limitsTestBlock(11, 1, 0),
+			err:   ErrTooManyTransactions,
+		},
+		"too many operations": {
+			limits: &Limits{
+				MaxOperationsPerTransaction: 100,
+			},
+			block: limitsTestBlock(1, 101, 0),
+			err:   ErrTooManyOperations,
+		},
+		"block metadata too large": {
+			limits: &Limits{
+				MaxMetadataBytes: 1024,
+			},
+			block: limitsTestBlock(1, 1, 2048),
+			err:   ErrMetadataTooLarge,
+		},
+		"transaction metadata too large": {
+			limits: &Limits{
+				MaxMetadataBytes: 1024,
+			},
+			block: func() *types.Block {
+				b := limitsTestBlock(1, 1, 0)
+				b.Transactions[0].Metadata = largeMetadata
+				return b
+			}(),
+			err: ErrMetadataTooLarge,
+		},
+		"operation metadata too large": {
+			limits: &Limits{
+				MaxMetadataBytes: 1024,
+			},
+			block: func() *types.Block {
+				b := limitsTestBlock(1, 2, 0)
+				b.Transactions[0].Operations[1].Metadata = largeMetadata
+				return b
+			}(),
+			err: ErrMetadataTooLarge,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := NewClientWithOptions(
+				&types.NetworkIdentifier{
+					Blockchain: "hello",
+					Network:    "world",
+				},
+				&types.BlockIdentifier{
+					Index: 0,
+					Hash:  "block 0",
+				},
+				[]string{"PAYMENT"},
+				[]*types.OperationStatus{
+					{
+						Status:     "SUCCESS",
+						Successful: true,
+					},
+				},
+				nil,
+				nil,
+				WithLimits(test.limits),
+			)
+			assert.NoError(t, err)
+
+			err = asserter.Block(test.block)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithLimitsDefaults(t *testing.T) {
+	asserter := &Asserter{}
+	WithLimits(nil)(asserter)
+	assert.Nil(t, asserter.limits)
+
+	WithLimits(&Limits{MaxMetadataBytes: 10})(asserter)
+	assert.Equal(t, &Limits{
+		MaxOperationsPerTransaction: DefaultMaxOperationsPerTransaction,
+		MaxTransactionsPerBlock:     DefaultMaxTransactionsPerBlock,
+		MaxMetadataBytes:            10,
+	}, asserter.limits)
+
+	WithLimits(nil)(asserter)
+	assert.Equal(t, int64(10), asserter.limits.MaxMetadataBytes)
+}
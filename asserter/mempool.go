@@ -33,3 +33,16 @@ func MempoolTransactions(
 
 	return nil
 }
+
+// MempoolTransaction returns an error if a types.Transaction
+// returned by /mempool/transaction is invalid. Unlike Transaction,
+// operations are not required to have a status (as a transaction
+// in the mempool has not yet been included in a block). However,
+// any populated status must still be valid and all identifiers,
+// amounts, currencies, and related operations are asserted
+// exactly as they are for a block (and construction) transaction.
+func (a *Asserter) MempoolTransaction(
+	transaction *types.Transaction,
+) error {
+	return a.transaction(transaction, statusOptional)
+}
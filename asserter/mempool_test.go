@@ -0,0 +1,302 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asserter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestMempoolTransactions(t *testing.T) {
+	var tests = map[string]struct {
+		transactions []*types.TransactionIdentifier
+		err          error
+	}{
+		"valid transactions": {
+			transactions: []*types.TransactionIdentifier{
+				{Hash: "tx 1"},
+				{Hash: "tx 2"},
+			},
+		},
+		"no transactions": {},
+		"nil transaction": {
+			transactions: []*types.TransactionIdentifier{
+				{Hash: "tx 1"},
+				nil,
+			},
+			err: ErrTxIdentifierIsNil,
+		},
+		"missing hash": {
+			transactions: []*types.TransactionIdentifier{
+				{Hash: "tx 1"},
+				{},
+			},
+			err: ErrTxIdentifierHashMissing,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.err, MempoolTransactions(test.transactions))
+		})
+	}
+}
+
+func TestMempoolTransaction(t *testing.T) {
+	var (
+		validAmount = &types.Amount{
+			Value: "1000",
+			Currency: &types.Currency{
+				Symbol:   "BTC",
+				Decimals: 8,
+			},
+		}
+
+		validAccount = &types.AccountIdentifier{
+			Address: "test",
+		}
+	)
+
+	var tests = map[string]struct {
+		transaction *types.Transaction
+		err         error
+	}{
+		"operations without status": {
+			transaction: &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						Type:    "PAYMENT",
+						Account: validAccount,
+						Amount:  validAmount,
+					},
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 1,
+						},
+						Type:    "PAYMENT",
+						Status:  types.String(""),
+						Account: validAccount,
+						Amount:  validAmount,
+					},
+				},
+			},
+		},
+		"operations with status": {
+			transaction: &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						Type:    "PAYMENT",
+						Status:  types.String("SUCCESS"),
+						Account: validAccount,
+						Amount:  validAmount,
+					},
+				},
+			},
+		},
+		"no operations": {
+			transaction: &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+			},
+		},
+		"invalid status": {
+			transaction: &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						Type:    "PAYMENT",
+						Status:  types.String("PENDING"),
+						Account: validAccount,
+						Amount:  validAmount,
+					},
+				},
+			},
+			err: ErrOperationStatusInvalid,
+		},
+		"invalid type": {
+			transaction: &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						Type: "STAKE",
+					},
+				},
+			},
+			err: ErrOperationTypeInvalid,
+		},
+		"invalid operation identifier": {
+			transaction: &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 1,
+						},
+						Type: "PAYMENT",
+					},
+				},
+			},
+			err: ErrOperationIdentifierIndexOutOfOrder,
+		},
+		"invalid amount": {
+			transaction: &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						Type:    "PAYMENT",
+						Account: validAccount,
+						Amount: &types.Amount{
+							Value:    "1.5",
+							Currency: validAmount.Currency,
+						},
+					},
+				},
+			},
+			err: ErrAmountIsNotInt,
+		},
+		"invalid currency": {
+			transaction: &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						Type:    "PAYMENT",
+						Account: validAccount,
+						Amount: &types.Amount{
+							Value: "1000",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: -1,
+							},
+						},
+					},
+				},
+			},
+			err: ErrAmountCurrencyHasNegDecimals,
+		},
+		"invalid related operation": {
+			transaction: &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						RelatedOperations: []*types.OperationIdentifier{
+							{Index: 1},
+						},
+						Type: "PAYMENT",
+					},
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 1,
+						},
+						Type: "PAYMENT",
+					},
+				},
+			},
+			err: ErrRelatedOperationIndexOutOfOrder,
+		},
+		"missing transaction identifier": {
+			transaction: &types.Transaction{},
+			err:         ErrTxIdentifierIsNil,
+		},
+		"nil transaction": {
+			err: ErrTxIsNil,
+		},
+	}
+
+	asserter, err := NewClientWithOptions(
+		&types.NetworkIdentifier{
+			Blockchain: "hello",
+			Network:    "world",
+		},
+		&types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		[]string{"PAYMENT"},
+		[]*types.OperationStatus{
+			{
+				Status:     "SUCCESS",
+				Successful: true,
+			},
+		},
+		nil,
+		nil,
+	)
+	assert.NoError(t, err)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := asserter.MempoolTransaction(test.transaction)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Transactions in blocks must always
+			// populate the operation status.
+			if test.err == nil && len(test.transaction.Operations) > 0 &&
+				test.transaction.Operations[0].Status == nil {
+				assert.True(t, errors.Is(
+					asserter.Transaction(test.transaction),
+					ErrOperationStatusMissing,
+				))
+			}
+		})
+	}
+}
@@ -102,6 +102,57 @@ func SyncStatus(status *types.SyncStatus) error {
 // NetworkStatusResponse ensures any types.NetworkStatusResponse
 // is valid.
 func NetworkStatusResponse(response *types.NetworkStatusResponse) error {
+	return networkStatusResponse(response, Timestamp)
+}
+
+// NetworkStatusResponse ensures any types.NetworkStatusResponse
+// is valid (using the timestamp bounds configured in the Asserter).
+func (a *Asserter) NetworkStatusResponse(response *types.NetworkStatusResponse) error {
+	if a == nil {
+		return ErrAsserterNotInitialized
+	}
+
+	return networkStatusResponse(response, func(timestamp int64) error {
+		if a.exemptGenesisTimestamp &&
+			response.CurrentBlockIdentifier.Index == a.genesisBlock.Index {
+			return nil
+		}
+
+		return a.Timestamp(timestamp)
+	})
+}
+
+// NetworkStatusResponseWithOptions ensures any types.NetworkStatusResponse
+// is valid (using the timestamp bounds configured by options). This can be
+// used to validate a types.NetworkStatusResponse before an Asserter is
+// constructed.
+func NetworkStatusResponseWithOptions(
+	response *types.NetworkStatusResponse,
+	options ...Option,
+) error {
+	a := &Asserter{
+		minTimestamp: MinUnixEpoch,
+		maxTimestamp: MaxUnixEpoch,
+	}
+
+	for _, opt := range options {
+		opt(a)
+	}
+
+	return networkStatusResponse(response, func(timestamp int64) error {
+		if a.exemptGenesisTimestamp && response.GenesisBlockIdentifier != nil &&
+			response.CurrentBlockIdentifier.Index == response.GenesisBlockIdentifier.Index {
+			return nil
+		}
+
+		return a.Timestamp(timestamp)
+	})
+}
+
+func networkStatusResponse(
+	response *types.NetworkStatusResponse,
+	timestamp func(int64) error,
+) error {
 	if response == nil {
 		return ErrNetworkStatusResponseIsNil
 	}
@@ -110,7 +161,7 @@ func NetworkStatusResponse(response *types.NetworkStatusResponse) error {
 		return err
 	}
 
-	if err := Timestamp(response.CurrentBlockTimestamp); err != nil {
+	if err := timestamp(response.CurrentBlockTimestamp); err != nil {
 		return err
 	}
 
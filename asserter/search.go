@@ -15,12 +15,18 @@
 package asserter
 
 import (
+	"fmt"
+
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 // SearchTransactionsResponse ensures a
-// *types.SearchTransactionsResponse is valid.
+// *types.SearchTransactionsResponse is valid. If the
+// *types.SearchTransactionsRequest is provided, the
+// NextOffset (if populated) must be greater than the
+// offset of the request.
 func (a *Asserter) SearchTransactionsResponse(
+	request *types.SearchTransactionsRequest,
 	response *types.SearchTransactionsResponse,
 ) error {
 	if a == nil {
@@ -31,11 +37,31 @@ func (a *Asserter) SearchTransactionsResponse(
 		return ErrNextOffsetInvalid
 	}
 
+	if response.NextOffset != nil && request != nil {
+		offset := int64(0)
+		if request.Offset != nil {
+			offset = *request.Offset
+		}
+
+		if *response.NextOffset <= offset {
+			return fmt.Errorf(
+				"%w: next offset %d <= offset %d",
+				ErrNextOffsetNotGreaterThanOffset,
+				*response.NextOffset,
+				offset,
+			)
+		}
+	}
+
 	if response.TotalCount < 0 {
 		return ErrTotalCountInvalid
 	}
 
-	for _, blockTransaction := range response.Transactions {
+	for i, blockTransaction := range response.Transactions {
+		if blockTransaction == nil {
+			return fmt.Errorf("%w: transaction %d", ErrBlockTransactionIsNil, i)
+		}
+
 		if err := BlockIdentifier(blockTransaction.BlockIdentifier); err != nil {
 			return err
 		}
@@ -54,6 +54,7 @@ func TestSearchTransactionsResponse(t *testing.T) {
 		},
 	}
 	tests := map[string]struct {
+		request  *types.SearchTransactionsRequest
 		response *types.SearchTransactionsResponse
 		err      error
 	}{
@@ -71,6 +72,51 @@ func TestSearchTransactionsResponse(t *testing.T) {
 			},
 			err: ErrNextOffsetInvalid,
 		},
+		"next greater than offset": {
+			request: &types.SearchTransactionsRequest{
+				Offset: types.Int64(5),
+			},
+			response: &types.SearchTransactionsResponse{
+				NextOffset: types.Int64(6),
+			},
+		},
+		"next greater than default offset": {
+			request: &types.SearchTransactionsRequest{},
+			response: &types.SearchTransactionsResponse{
+				NextOffset: types.Int64(1),
+			},
+		},
+		"next equal to offset": {
+			request: &types.SearchTransactionsRequest{
+				Offset: types.Int64(5),
+			},
+			response: &types.SearchTransactionsResponse{
+				NextOffset: types.Int64(5),
+			},
+			err: ErrNextOffsetNotGreaterThanOffset,
+		},
+		"next less than offset": {
+			request: &types.SearchTransactionsRequest{
+				Offset: types.Int64(5),
+			},
+			response: &types.SearchTransactionsResponse{
+				NextOffset: types.Int64(2),
+			},
+			err: ErrNextOffsetNotGreaterThanOffset,
+		},
+		"next equal to default offset": {
+			request: &types.SearchTransactionsRequest{},
+			response: &types.SearchTransactionsResponse{
+				NextOffset: types.Int64(0),
+			},
+			err: ErrNextOffsetNotGreaterThanOffset,
+		},
+		"no next with offset": {
+			request: &types.SearchTransactionsRequest{
+				Offset: types.Int64(5),
+			},
+			response: &types.SearchTransactionsResponse{},
+		},
 		"valid count": {
 			response: &types.SearchTransactionsResponse{
 				TotalCount: 0,
@@ -105,6 +151,42 @@ func TestSearchTransactionsResponse(t *testing.T) {
 			},
 			err: ErrBlockIdentifierHashMissing,
 		},
+		"valid next + missing blockIdentifier": {
+			response: &types.SearchTransactionsResponse{
+				NextOffset: types.Int64(1),
+				Transactions: []*types.BlockTransaction{
+					{
+						Transaction: validTransaction,
+					},
+				},
+			},
+			err: ErrBlockIdentifierIsNil,
+		},
+		"valid next + invalid operation": {
+			response: &types.SearchTransactionsResponse{
+				NextOffset: types.Int64(1),
+				Transactions: []*types.BlockTransaction{
+					{
+						BlockIdentifier: validBlockIdentifier,
+						Transaction: &types.Transaction{
+							TransactionIdentifier: validTransaction.TransactionIdentifier,
+							Operations: []*types.Operation{
+								{
+									OperationIdentifier: &types.OperationIdentifier{
+										Index: int64(0),
+									},
+									Type:    "STAKE",
+									Status:  types.String("SUCCESS"),
+									Account: validAccount,
+									Amount:  validAmount,
+								},
+							},
+						},
+					},
+				},
+			},
+			err: ErrOperationTypeInvalid,
+		},
 		"valid next + invalid transaction": {
 			err: ErrTxIdentifierIsNil,
 			response: &types.SearchTransactionsResponse{
@@ -117,6 +199,48 @@ func TestSearchTransactionsResponse(t *testing.T) {
 				},
 			},
 		},
+		"valid next + duplicate related transaction": {
+			err: ErrDuplicateRelatedTransaction,
+			response: &types.SearchTransactionsResponse{
+				NextOffset: types.Int64(1),
+				Transactions: []*types.BlockTransaction{
+					{
+						BlockIdentifier: validBlockIdentifier,
+						Transaction: &types.Transaction{
+							TransactionIdentifier: validTransaction.TransactionIdentifier,
+							Operations:            validTransaction.Operations,
+							RelatedTransactions: []*types.RelatedTransaction{
+								{
+									TransactionIdentifier: &types.TransactionIdentifier{
+										Hash: "related",
+									},
+									Direction: types.Backward,
+								},
+								{
+									TransactionIdentifier: &types.TransactionIdentifier{
+										Hash: "related",
+									},
+									Direction: types.Backward,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"valid next + nil transaction": {
+			err: ErrBlockTransactionIsNil,
+			response: &types.SearchTransactionsResponse{
+				NextOffset: types.Int64(1),
+				Transactions: []*types.BlockTransaction{
+					{
+						BlockIdentifier: validBlockIdentifier,
+						Transaction:     validTransaction,
+					},
+					nil,
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -168,6 +292,7 @@ func TestSearchTransactionsResponse(t *testing.T) {
 			assert.NoError(t, err)
 
 			err = asserter.SearchTransactionsResponse(
+				test.request,
 				test.response,
 			)
 			if test.err != nil {
@@ -411,6 +411,12 @@ func (a *Asserter) ValidCallMethod(method string) error {
 		return ErrCallMethodEmpty
 	}
 
+	// Client asserters may not be initialized
+	// with the supported call methods.
+	if a.callMethods == nil {
+		return nil
+	}
+
 	if _, ok := a.callMethods[method]; !ok {
 		return fmt.Errorf("%w: %s", ErrCallMethodUnsupported, method)
 	}
@@ -419,7 +425,8 @@ func (a *Asserter) ValidCallMethod(method string) error {
 }
 
 // CallRequest ensures that a types.CallRequest
-// is well-formatted.
+// is well-formatted. This can be used by both servers
+// and clients.
 func (a *Asserter) CallRequest(request *types.CallRequest) error {
 	if a == nil {
 		return ErrAsserterNotInitialized
@@ -429,7 +436,13 @@ func (a *Asserter) CallRequest(request *types.CallRequest) error {
 		return ErrCallRequestIsNil
 	}
 
-	if err := a.ValidSupportedNetwork(request.NetworkIdentifier); err != nil {
+	// Only servers are initialized with a list of
+	// supported networks.
+	if a.supportedNetworks == nil {
+		if err := NetworkIdentifier(request.NetworkIdentifier); err != nil {
+			return err
+		}
+	} else if err := a.ValidSupportedNetwork(request.NetworkIdentifier); err != nil {
 		return err
 	}
 
@@ -0,0 +1,264 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asserter
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	// DefaultFeeOperationTypes are the operation types
+	// considered fees when Validations.FeeOperationTypes
+	// is not populated.
+	DefaultFeeOperationTypes = []string{"FEE"}
+
+	// DefaultPaymentOperationTypes are the operation types
+	// considered payments when Validations.PaymentOperationTypes
+	// is not populated.
+	DefaultPaymentOperationTypes = []string{"PAYMENT"}
+)
+
+// Validations is an optional set of chain-specific invariants
+// enforced on the operations of each transaction. Unless noted
+// otherwise, invariants are not enforced on construction
+// operations. All toggles are disabled by default.
+type Validations struct {
+	// RequireFeeOperation ensures every transaction contains
+	// at least one fee operation.
+	RequireFeeOperation bool `json:"require_fee_operation"`
+
+	// FeeOperationMustBeNegative ensures every fee operation
+	// has an amount with a negative value.
+	FeeOperationMustBeNegative bool `json:"fee_operation_must_be_negative"`
+
+	// MaxFeeOperations is the maximum number of fee operations
+	// allowed in a transaction. If MaxFeeOperations <= 0, there
+	// is no limit.
+	MaxFeeOperations int64 `json:"max_fee_operations"`
+
+	// RequireBalancedPayments ensures the amounts of all
+	// payment operations in a transaction sum to zero (per
+	// currency). Operations with an unsuccessful status are
+	// ignored.
+	RequireBalancedPayments bool `json:"require_balanced_payments"`
+
+	// RequireOperationAccount ensures every operation
+	// populates Operation.Account.
+	RequireOperationAccount bool `json:"require_operation_account"`
+
+	// AllowAnyRelatedOperationOrder allows operations to reference
+	// any other operation in the same transaction (instead of only
+	// operations with a lower index). Self references, duplicate
+	// references, and cycles are still rejected. This is also
+	// enforced on construction operations.
+	AllowAnyRelatedOperationOrder bool `json:"allow_any_related_operation_order"`
+
+	// RejectCoinCreatedAndSpent ensures no coin is both
+	// created and spent in the same transaction.
+	RejectCoinCreatedAndSpent bool `json:"reject_coin_created_and_spent"`
+
+	// RequireConsistentCurrencies ensures all currencies in
+	// a block with the same symbol have the same decimals
+	// and metadata.
+	RequireConsistentCurrencies bool `json:"require_consistent_currencies"`
+
+	// FeeOperationTypes are the operation types considered
+	// fees. If empty, DefaultFeeOperationTypes is used.
+	FeeOperationTypes []string `json:"fee_operation_types,omitempty"`
+
+	// PaymentOperationTypes are the operation types considered
+	// payments. If empty, DefaultPaymentOperationTypes is used.
+	PaymentOperationTypes []string `json:"payment_operation_types,omitempty"`
+}
+
+// WithValidations sets the Validations enforced by
+// the Asserter.
+func WithValidations(validations *Validations) Option {
+	return func(a *Asserter) {
+		a.validations = validations
+	}
+}
+
+func (v *Validations) feeOperationTypes() []string {
+	if len(v.FeeOperationTypes) == 0 {
+		return DefaultFeeOperationTypes
+	}
+
+	return v.FeeOperationTypes
+}
+
+func (v *Validations) paymentOperationTypes() []string {
+	if len(v.PaymentOperationTypes) == 0 {
+		return DefaultPaymentOperationTypes
+	}
+
+	return v.PaymentOperationTypes
+}
+
+// validateOperations returns an error if a slice of
+// already asserted operations violates any enabled
+// Validations.
+func (a *Asserter) validateOperations(operations []*types.Operation) error {
+	v := a.validations
+	if v == nil {
+		return nil
+	}
+
+	feeOperations := int64(0)
+	paymentSums := map[string]*big.Int{}
+	for _, op := range operations {
+		if v.RequireOperationAccount && op.Account == nil {
+			return fmt.Errorf(
+				"%w: operation %d",
+				ErrOperationAccountMissing,
+				op.OperationIdentifier.Index,
+			)
+		}
+
+		if containsString(v.feeOperationTypes(), op.Type) {
+			feeOperations++
+
+			if v.FeeOperationMustBeNegative {
+				if err := negativeAmount(op.Amount); err != nil {
+					return fmt.Errorf(
+						"%w: operation %d",
+						err,
+						op.OperationIdentifier.Index,
+					)
+				}
+			}
+		}
+
+		if !v.RequireBalancedPayments || op.Amount == nil ||
+			!containsString(v.paymentOperationTypes(), op.Type) {
+			continue
+		}
+
+		if op.Status != nil && len(*op.Status) > 0 && !a.operationStatusMap[*op.Status] {
+			continue
+		}
+
+		value, err := types.AmountValue(op.Amount)
+		if err != nil {
+			return fmt.Errorf(
+				"%w: unable to parse amount in operation %d",
+				err,
+				op.OperationIdentifier.Index,
+			)
+		}
+
+		key := types.Hash(op.Amount.Currency)
+		if _, ok := paymentSums[key]; !ok {
+			paymentSums[key] = new(big.Int)
+		}
+		paymentSums[key].Add(paymentSums[key], value)
+	}
+
+	if v.RequireFeeOperation && feeOperations == 0 {
+		return ErrFeeOperationMissing
+	}
+
+	if v.MaxFeeOperations > 0 && feeOperations > v.MaxFeeOperations {
+		return fmt.Errorf(
+			"%w: found %d fee operations (max %d)",
+			ErrTooManyFeeOperations,
+			feeOperations,
+			v.MaxFeeOperations,
+		)
+	}
+
+	for _, sum := range paymentSums {
+		if sum.Sign() != 0 {
+			return fmt.Errorf("%w: payments sum to %s", ErrPaymentsUnbalanced, sum.String())
+		}
+	}
+
+	return nil
+}
+
+// negativeAmount returns an error if a fee amount
+// is missing or is not negative.
+func negativeAmount(amount *types.Amount) error {
+	if amount == nil {
+		return ErrFeeOperationNotNegative
+	}
+
+	value, err := types.AmountValue(amount)
+	if err != nil {
+		return err
+	}
+
+	if value.Sign() >= 0 {
+		return fmt.Errorf("%w: %s", ErrFeeOperationNotNegative, amount.Value)
+	}
+
+	return nil
+}
+
+// currencySighting is the first *types.Currency seen
+// with some symbol in a block (and the transaction
+// it was seen in).
+type currencySighting struct {
+	currency    *types.Currency
+	transaction string
+}
+
+// validateBlockCurrencies returns an error if any two currencies
+// in an already asserted block share a symbol but have different
+// decimals or metadata.
+func (a *Asserter) validateBlockCurrencies(block *types.Block) error {
+	if a.validations == nil || !a.validations.RequireConsistentCurrencies {
+		return nil
+	}
+
+	seen := map[string]*currencySighting{}
+	for _, tx := range block.Transactions {
+		for _, op := range tx.Operations {
+			if op.Amount == nil {
+				continue
+			}
+
+			currency := op.Amount.Currency
+			first, ok := seen[currency.Symbol]
+			if !ok {
+				seen[currency.Symbol] = &currencySighting{
+					currency:    currency,
+					transaction: tx.TransactionIdentifier.Hash,
+				}
+				continue
+			}
+
+			if first.currency.Decimals == currency.Decimals &&
+				(len(first.currency.Metadata) == 0 && len(currency.Metadata) == 0 ||
+					types.Hash(first.currency) == types.Hash(currency)) {
+				continue
+			}
+
+			return fmt.Errorf(
+				"%w: %s in transaction %s conflicts with %s in transaction %s",
+				ErrCurrencyInconsistent,
+				types.PrintStruct(currency),
+				tx.TransactionIdentifier.Hash,
+				types.PrintStruct(first.currency),
+				first.transaction,
+			)
+		}
+	}
+
+	return nil
+}
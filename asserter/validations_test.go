@@ -0,0 +1,498 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asserter
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func validationsTestAsserter(t *testing.T, validations *Validations) *Asserter {
+	asserter, err := NewClientWithOptions(
+		&types.NetworkIdentifier{
+			Blockchain: "hello",
+			Network:    "world",
+		},
+		&types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		[]string{"PAYMENT", "FEE", "REWARD"},
+		[]*types.OperationStatus{
+			{
+				Status:     "SUCCESS",
+				Successful: true,
+			},
+			{
+				Status:     "FAILURE",
+				Successful: false,
+			},
+		},
+		[]*types.Error{},
+		nil,
+		WithValidations(validations),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, asserter)
+
+	return asserter
+}
+
+func validationsOperation(
+	index int64,
+	opType string,
+	status string,
+	account *types.AccountIdentifier,
+	value string,
+) *types.Operation {
+	op := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: index,
+		},
+		Type:    opType,
+		Status:  types.String(status),
+		Account: account,
+	}
+
+	if len(value) > 0 {
+		op.Amount = &types.Amount{
+			Value: value,
+			Currency: &types.Currency{
+				Symbol:   "BTC",
+				Decimals: 8,
+			},
+		}
+	}
+
+	return op
+}
+
+func TestValidations(t *testing.T) {
+	var (
+		sender = &types.AccountIdentifier{
+			Address: "sender",
+		}
+		recipient = &types.AccountIdentifier{
+			Address: "recipient",
+		}
+
+		balancedWithFee = []*types.Operation{
+			validationsOperation(0, "PAYMENT", "SUCCESS", sender, "-100"),
+			validationsOperation(1, "PAYMENT", "SUCCESS", recipient, "100"),
+			validationsOperation(2, "FEE", "SUCCESS", sender, "-10"),
+		}
+		noFee = []*types.Operation{
+			validationsOperation(0, "PAYMENT", "SUCCESS", sender, "-100"),
+			validationsOperation(1, "PAYMENT", "SUCCESS", recipient, "100"),
+		}
+		positiveFee = []*types.Operation{
+			validationsOperation(0, "FEE", "SUCCESS", sender, "10"),
+		}
+		twoFees = []*types.Operation{
+			validationsOperation(0, "FEE", "SUCCESS", sender, "-10"),
+			validationsOperation(1, "FEE", "SUCCESS", sender, "-5"),
+		}
+		unbalanced = []*types.Operation{
+			validationsOperation(0, "PAYMENT", "SUCCESS", sender, "-100"),
+			validationsOperation(1, "PAYMENT", "SUCCESS", recipient, "90"),
+		}
+		unbalancedFailed = []*types.Operation{
+			validationsOperation(0, "PAYMENT", "SUCCESS", sender, "-100"),
+			validationsOperation(1, "PAYMENT", "SUCCESS", recipient, "100"),
+			validationsOperation(2, "PAYMENT", "FAILURE", recipient, "100"),
+		}
+		noAccount = []*types.Operation{
+			validationsOperation(0, "REWARD", "SUCCESS", nil, ""),
+		}
+	)
+
+	var tests = map[string]struct {
+		validations *Validations
+		operations  []*types.Operation
+		err         error
+	}{
+		"no validations": {
+			operations: unbalanced,
+		},
+		"fee required (pass)": {
+			validations: &Validations{
+				RequireFeeOperation: true,
+			},
+			operations: balancedWithFee,
+		},
+		"fee required (fail)": {
+			validations: &Validations{
+				RequireFeeOperation: true,
+			},
+			operations: noFee,
+			err:        ErrFeeOperationMissing,
+		},
+		"fee required with custom type (pass)": {
+			validations: &Validations{
+				RequireFeeOperation: true,
+				FeeOperationTypes:   []string{"REWARD"},
+			},
+			operations: noAccount,
+		},
+		"fee negative (pass)": {
+			validations: &Validations{
+				FeeOperationMustBeNegative: true,
+			},
+			operations: balancedWithFee,
+		},
+		"fee negative (fail)": {
+			validations: &Validations{
+				FeeOperationMustBeNegative: true,
+			},
+			operations: positiveFee,
+			err:        ErrFeeOperationNotNegative,
+		},
+		"max fee operations (pass)": {
+			validations: &Validations{
+				MaxFeeOperations: 2,
+			},
+			operations: twoFees,
+		},
+		"max fee operations (fail)": {
+			validations: &Validations{
+				MaxFeeOperations: 1,
+			},
+			operations: twoFees,
+			err:        ErrTooManyFeeOperations,
+		},
+		"balanced payments (pass)": {
+			validations: &Validations{
+				RequireBalancedPayments: true,
+			},
+			operations: balancedWithFee,
+		},
+		"balanced payments ignores unsuccessful operations": {
+			validations: &Validations{
+				RequireBalancedPayments: true,
+			},
+			operations: unbalancedFailed,
+		},
+		"balanced payments (fail)": {
+			validations: &Validations{
+				RequireBalancedPayments: true,
+			},
+			operations: unbalanced,
+			err:        ErrPaymentsUnbalanced,
+		},
+		"operation account required (pass)": {
+			validations: &Validations{
+				RequireOperationAccount: true,
+			},
+			operations: balancedWithFee,
+		},
+		"operation account required (fail)": {
+			validations: &Validations{
+				RequireOperationAccount: true,
+			},
+			operations: noAccount,
+			err:        ErrOperationAccountMissing,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter := validationsTestAsserter(t, test.validations)
+
+			err := asserter.Transaction(&types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: test.operations,
+			})
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Validations are never applied to construction
+			// operations.
+			constructionOps := []*types.Operation{}
+			for _, op := range test.operations {
+				constructionOp := *op
+				constructionOp.Status = nil
+				constructionOps = append(constructionOps, &constructionOp)
+			}
+			assert.NoError(t, asserter.Operations(constructionOps, true))
+		})
+	}
+}
+
+func TestValidationsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "validations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filePath := path.Join(dir, "asserter.json")
+	assert.NoError(t, ioutil.WriteFile(filePath, []byte(`{
+		"network_identifier": {"blockchain": "hello", "network": "world"},
+		"genesis_block_identifier": {"index": 0, "hash": "block 0"},
+		"allowed_operation_types": ["PAYMENT", "FEE"],
+		"allowed_operation_statuses": [{"status": "SUCCESS", "successful": true}],
+		"allowed_errors": [],
+		"validations": {
+			"require_fee_operation": true,
+			"max_fee_operations": 1
+		}
+	}`), 0600))
+
+	asserter, err := NewClientWithFile(filePath)
+	assert.NoError(t, err)
+
+	configuration, err := asserter.ClientConfiguration()
+	assert.NoError(t, err)
+	assert.Equal(t, &Validations{
+		RequireFeeOperation: true,
+		MaxFeeOperations:    1,
+	}, configuration.Validations)
+
+	err = asserter.Transaction(&types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: "tx",
+		},
+		Operations: []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type:   "PAYMENT",
+				Status: types.String("SUCCESS"),
+			},
+		},
+	})
+	assert.True(t, errors.Is(err, ErrFeeOperationMissing))
+}
+
+func TestAllowAnyRelatedOperationOrder(t *testing.T) {
+	relatedOperation := func(index int64, related ...int64) *types.Operation {
+		op := validationsOperation(index, "PAYMENT", "SUCCESS", nil, "")
+		for _, r := range related {
+			op.RelatedOperations = append(op.RelatedOperations, &types.OperationIdentifier{
+				Index: r,
+			})
+		}
+
+		return op
+	}
+
+	var tests = map[string]struct {
+		operations []*types.Operation
+		strictErr  error
+		anyErr     error
+	}{
+		"backward references": {
+			operations: []*types.Operation{
+				relatedOperation(0),
+				relatedOperation(1, 0),
+				relatedOperation(2, 0, 1),
+			},
+		},
+		"forward references": {
+			operations: []*types.Operation{
+				relatedOperation(0, 2),
+				relatedOperation(1, 2),
+				relatedOperation(2),
+			},
+			strictErr: ErrRelatedOperationIndexOutOfOrder,
+		},
+		"self reference": {
+			operations: []*types.Operation{
+				relatedOperation(0),
+				relatedOperation(1, 1),
+			},
+			strictErr: ErrRelatedOperationIndexOutOfOrder,
+			anyErr:    ErrRelatedOperationSelfReference,
+		},
+		"missing reference": {
+			operations: []*types.Operation{
+				relatedOperation(0, 3),
+				relatedOperation(1),
+			},
+			strictErr: ErrRelatedOperationIndexOutOfOrder,
+			anyErr:    ErrRelatedOperationIndexNotFound,
+		},
+		"duplicate reference": {
+			operations: []*types.Operation{
+				relatedOperation(0, 1, 1),
+				relatedOperation(1),
+			},
+			strictErr: ErrRelatedOperationIndexOutOfOrder,
+			anyErr:    ErrRelatedOperationIndexDuplicate,
+		},
+		"cycle": {
+			operations: []*types.Operation{
+				relatedOperation(0, 2),
+				relatedOperation(1, 0),
+				relatedOperation(2, 1),
+			},
+			strictErr: ErrRelatedOperationIndexOutOfOrder,
+			anyErr:    ErrRelatedOperationCycle,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, anyOrder := range []bool{false, true} {
+				asserter := validationsTestAsserter(t, &Validations{
+					AllowAnyRelatedOperationOrder: anyOrder,
+				})
+
+				expected := test.strictErr
+				if anyOrder {
+					expected = test.anyErr
+				}
+
+				err := asserter.Transaction(&types.Transaction{
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: "tx",
+					},
+					Operations: test.operations,
+				})
+				if expected != nil {
+					assert.True(t, errors.Is(err, expected))
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireConsistentCurrencies(t *testing.T) {
+	currencyOperation := func(index int64, currency *types.Currency) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: index,
+			},
+			Type:   "PAYMENT",
+			Status: types.String("SUCCESS"),
+			Account: &types.AccountIdentifier{
+				Address: "addr",
+			},
+			Amount: &types.Amount{
+				Value:    "100",
+				Currency: currency,
+			},
+		}
+	}
+	block := func(txs ...[]*types.Operation) *types.Block {
+		b := &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: 1,
+				Hash:  "block 1",
+			},
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Index: 0,
+				Hash:  "block 0",
+			},
+			Timestamp: MinUnixEpoch + 1,
+		}
+		for i, ops := range txs {
+			b.Transactions = append(b.Transactions, &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: fmt.Sprintf("tx %d", i),
+				},
+				Operations: ops,
+			})
+		}
+
+		return b
+	}
+
+	var (
+		btc = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 8,
+		}
+		btc6 = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 6,
+		}
+		btcMetadata = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 8,
+			Metadata: map[string]interface{}{
+				"issuer": "blah",
+			},
+		}
+		eth = &types.Currency{
+			Symbol:   "ETH",
+			Decimals: 18,
+		}
+	)
+
+	var tests = map[string]struct {
+		block *types.Block
+		err   error
+	}{
+		"consistent currencies": {
+			block: block(
+				[]*types.Operation{currencyOperation(0, btc), currencyOperation(1, eth)},
+				[]*types.Operation{currencyOperation(0, btc)},
+			),
+		},
+		"consistent metadata": {
+			block: block(
+				[]*types.Operation{currencyOperation(0, btcMetadata)},
+				[]*types.Operation{currencyOperation(0, btcMetadata)},
+			),
+		},
+		"different decimals": {
+			block: block(
+				[]*types.Operation{currencyOperation(0, btc), currencyOperation(1, eth)},
+				[]*types.Operation{currencyOperation(0, btc6)},
+			),
+			err: ErrCurrencyInconsistent,
+		},
+		"different metadata": {
+			block: block(
+				[]*types.Operation{currencyOperation(0, btc), currencyOperation(1, btcMetadata)},
+			),
+			err: ErrCurrencyInconsistent,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter := validationsTestAsserter(t, nil)
+			assert.NoError(t, asserter.Block(test.block))
+
+			asserter = validationsTestAsserter(t, &Validations{
+				RequireConsistentCurrencies: true,
+			})
+			err := asserter.Block(test.block)
+			if test.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, test.err))
+			assert.Contains(t, err.Error(), "tx 0")
+		})
+	}
+}
@@ -108,22 +108,25 @@ func (a *CallAPIService) Call(
 			return nil, nil, err
 		}
 
-		return nil, &v, fmt.Errorf("%+v", v)
+		return nil, &v, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err:        fmt.Errorf("%+v", v),
+		}
 	case _nethttp.StatusBadGateway,
 		_nethttp.StatusServiceUnavailable,
 		_nethttp.StatusGatewayTimeout,
 		_nethttp.StatusRequestTimeout:
-		return nil, nil, fmt.Errorf(
-			"%w: code: %d body: %s",
-			ErrRetriable,
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		return nil, nil, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err: fmt.Errorf(
+				"%w: code: %d body: %s",
+				ErrRetriable,
+				localVarHTTPResponse.StatusCode,
+				string(localVarBody),
+			),
+		}
 	default:
-		return nil, nil, fmt.Errorf(
-			"invalid status code: %d body: %s",
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		clientErr, err := a.client.unexpectedStatus(localVarHTTPResponse, localVarBody)
+		return nil, clientErr, err
 	}
 }
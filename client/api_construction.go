@@ -100,23 +100,26 @@ func (a *ConstructionAPIService) ConstructionCombine(
 			return nil, nil, err
 		}
 
-		return nil, &v, fmt.Errorf("%+v", v)
+		return nil, &v, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err:        fmt.Errorf("%+v", v),
+		}
 	case _nethttp.StatusBadGateway,
 		_nethttp.StatusServiceUnavailable,
 		_nethttp.StatusGatewayTimeout,
 		_nethttp.StatusRequestTimeout:
-		return nil, nil, fmt.Errorf(
-			"%w: code: %d body: %s",
-			ErrRetriable,
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		return nil, nil, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err: fmt.Errorf(
+				"%w: code: %d body: %s",
+				ErrRetriable,
+				localVarHTTPResponse.StatusCode,
+				string(localVarBody),
+			),
+		}
 	default:
-		return nil, nil, fmt.Errorf(
-			"invalid status code: %d body: %s",
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		clientErr, err := a.client.unexpectedStatus(localVarHTTPResponse, localVarBody)
+		return nil, clientErr, err
 	}
 }
 
@@ -186,23 +189,26 @@ func (a *ConstructionAPIService) ConstructionDerive(
 			return nil, nil, err
 		}
 
-		return nil, &v, fmt.Errorf("%+v", v)
+		return nil, &v, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err:        fmt.Errorf("%+v", v),
+		}
 	case _nethttp.StatusBadGateway,
 		_nethttp.StatusServiceUnavailable,
 		_nethttp.StatusGatewayTimeout,
 		_nethttp.StatusRequestTimeout:
-		return nil, nil, fmt.Errorf(
-			"%w: code: %d body: %s",
-			ErrRetriable,
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		return nil, nil, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err: fmt.Errorf(
+				"%w: code: %d body: %s",
+				ErrRetriable,
+				localVarHTTPResponse.StatusCode,
+				string(localVarBody),
+			),
+		}
 	default:
-		return nil, nil, fmt.Errorf(
-			"invalid status code: %d body: %s",
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		clientErr, err := a.client.unexpectedStatus(localVarHTTPResponse, localVarBody)
+		return nil, clientErr, err
 	}
 }
 
@@ -272,23 +278,26 @@ func (a *ConstructionAPIService) ConstructionHash(
 			return nil, nil, err
 		}
 
-		return nil, &v, fmt.Errorf("%+v", v)
+		return nil, &v, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err:        fmt.Errorf("%+v", v),
+		}
 	case _nethttp.StatusBadGateway,
 		_nethttp.StatusServiceUnavailable,
 		_nethttp.StatusGatewayTimeout,
 		_nethttp.StatusRequestTimeout:
-		return nil, nil, fmt.Errorf(
-			"%w: code: %d body: %s",
-			ErrRetriable,
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		return nil, nil, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err: fmt.Errorf(
+				"%w: code: %d body: %s",
+				ErrRetriable,
+				localVarHTTPResponse.StatusCode,
+				string(localVarBody),
+			),
+		}
 	default:
-		return nil, nil, fmt.Errorf(
-			"invalid status code: %d body: %s",
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		clientErr, err := a.client.unexpectedStatus(localVarHTTPResponse, localVarBody)
+		return nil, clientErr, err
 	}
 }
 
@@ -365,23 +374,26 @@ func (a *ConstructionAPIService) ConstructionMetadata(
 			return nil, nil, err
 		}
 
-		return nil, &v, fmt.Errorf("%+v", v)
+		return nil, &v, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err:        fmt.Errorf("%+v", v),
+		}
 	case _nethttp.StatusBadGateway,
 		_nethttp.StatusServiceUnavailable,
 		_nethttp.StatusGatewayTimeout,
 		_nethttp.StatusRequestTimeout:
-		return nil, nil, fmt.Errorf(
-			"%w: code: %d body: %s",
-			ErrRetriable,
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		return nil, nil, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err: fmt.Errorf(
+				"%w: code: %d body: %s",
+				ErrRetriable,
+				localVarHTTPResponse.StatusCode,
+				string(localVarBody),
+			),
+		}
 	default:
-		return nil, nil, fmt.Errorf(
-			"invalid status code: %d body: %s",
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		clientErr, err := a.client.unexpectedStatus(localVarHTTPResponse, localVarBody)
+		return nil, clientErr, err
 	}
 }
 
@@ -452,23 +464,26 @@ func (a *ConstructionAPIService) ConstructionParse(
 			return nil, nil, err
 		}
 
-		return nil, &v, fmt.Errorf("%+v", v)
+		return nil, &v, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err:        fmt.Errorf("%+v", v),
+		}
 	case _nethttp.StatusBadGateway,
 		_nethttp.StatusServiceUnavailable,
 		_nethttp.StatusGatewayTimeout,
 		_nethttp.StatusRequestTimeout:
-		return nil, nil, fmt.Errorf(
-			"%w: code: %d body: %s",
-			ErrRetriable,
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		return nil, nil, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err: fmt.Errorf(
+				"%w: code: %d body: %s",
+				ErrRetriable,
+				localVarHTTPResponse.StatusCode,
+				string(localVarBody),
+			),
+		}
 	default:
-		return nil, nil, fmt.Errorf(
-			"invalid status code: %d body: %s",
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		clientErr, err := a.client.unexpectedStatus(localVarHTTPResponse, localVarBody)
+		return nil, clientErr, err
 	}
 }
 
@@ -544,23 +559,26 @@ func (a *ConstructionAPIService) ConstructionPayloads(
 			return nil, nil, err
 		}
 
-		return nil, &v, fmt.Errorf("%+v", v)
+		return nil, &v, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err:        fmt.Errorf("%+v", v),
+		}
 	case _nethttp.StatusBadGateway,
 		_nethttp.StatusServiceUnavailable,
 		_nethttp.StatusGatewayTimeout,
 		_nethttp.StatusRequestTimeout:
-		return nil, nil, fmt.Errorf(
-			"%w: code: %d body: %s",
-			ErrRetriable,
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		return nil, nil, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err: fmt.Errorf(
+				"%w: code: %d body: %s",
+				ErrRetriable,
+				localVarHTTPResponse.StatusCode,
+				string(localVarBody),
+			),
+		}
 	default:
-		return nil, nil, fmt.Errorf(
-			"invalid status code: %d body: %s",
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		clientErr, err := a.client.unexpectedStatus(localVarHTTPResponse, localVarBody)
+		return nil, clientErr, err
 	}
 }
 
@@ -634,23 +652,26 @@ func (a *ConstructionAPIService) ConstructionPreprocess(
 			return nil, nil, err
 		}
 
-		return nil, &v, fmt.Errorf("%+v", v)
+		return nil, &v, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err:        fmt.Errorf("%+v", v),
+		}
 	case _nethttp.StatusBadGateway,
 		_nethttp.StatusServiceUnavailable,
 		_nethttp.StatusGatewayTimeout,
 		_nethttp.StatusRequestTimeout:
-		return nil, nil, fmt.Errorf(
-			"%w: code: %d body: %s",
-			ErrRetriable,
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		return nil, nil, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err: fmt.Errorf(
+				"%w: code: %d body: %s",
+				ErrRetriable,
+				localVarHTTPResponse.StatusCode,
+				string(localVarBody),
+			),
+		}
 	default:
-		return nil, nil, fmt.Errorf(
-			"invalid status code: %d body: %s",
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		clientErr, err := a.client.unexpectedStatus(localVarHTTPResponse, localVarBody)
+		return nil, clientErr, err
 	}
 }
 
@@ -723,22 +744,25 @@ func (a *ConstructionAPIService) ConstructionSubmit(
 			return nil, nil, err
 		}
 
-		return nil, &v, fmt.Errorf("%+v", v)
+		return nil, &v, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err:        fmt.Errorf("%+v", v),
+		}
 	case _nethttp.StatusBadGateway,
 		_nethttp.StatusServiceUnavailable,
 		_nethttp.StatusGatewayTimeout,
 		_nethttp.StatusRequestTimeout:
-		return nil, nil, fmt.Errorf(
-			"%w: code: %d body: %s",
-			ErrRetriable,
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		return nil, nil, &StatusError{
+			StatusCode: localVarHTTPResponse.StatusCode,
+			Err: fmt.Errorf(
+				"%w: code: %d body: %s",
+				ErrRetriable,
+				localVarHTTPResponse.StatusCode,
+				string(localVarBody),
+			),
+		}
 	default:
-		return nil, nil, fmt.Errorf(
-			"invalid status code: %d body: %s",
-			localVarHTTPResponse.StatusCode,
-			string(localVarBody),
-		)
+		clientErr, err := a.client.unexpectedStatus(localVarHTTPResponse, localVarBody)
+		return nil, clientErr, err
 	}
 }
@@ -18,6 +18,7 @@ package client
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
@@ -30,6 +31,8 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 var (
@@ -130,6 +133,12 @@ func contains(haystack []string, needle string) bool {
 
 // callAPI do the request.
 func (c *APIClient) callAPI(ctx context.Context, request *http.Request) (*http.Response, error) {
+	for _, intercept := range c.cfg.RequestInterceptors {
+		if err := intercept(request); err != nil {
+			return nil, err
+		}
+	}
+
 	if c.cfg.Debug {
 		dump, err := httputil.DumpRequestOut(request, true)
 		if err != nil {
@@ -139,6 +148,22 @@ func (c *APIClient) callAPI(ctx context.Context, request *http.Request) (*http.R
 	}
 
 	resp, err := c.cfg.HTTPClient.Do(request.WithContext(ctx))
+	if err == nil {
+		if decompressErr := decompressResponse(resp); decompressErr != nil {
+			resp, err = nil, decompressErr
+		}
+	}
+
+	for _, intercept := range c.cfg.ResponseInterceptors {
+		if interceptErr := intercept(resp, err); interceptErr != nil {
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			return nil, interceptErr
+		}
+	}
+
 	if err != nil {
 		return resp, err
 	}
@@ -151,7 +176,47 @@ func (c *APIClient) callAPI(ctx context.Context, request *http.Request) (*http.R
 		log.Printf("\n%s\n", string(dump))
 	}
 
-	return resp, err
+	return resp, nil
+}
+
+// decompressResponse transparently decompresses the
+// body of a gzip encoded response.
+func decompressResponse(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return fmt.Errorf("%w: unable to decompress response", err)
+	}
+
+	resp.Body = &gzipReadCloser{Reader: reader, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return nil
+}
+
+// gzipReadCloser closes both the *gzip.Reader
+// and the underlying response body.
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close closes the *gzip.Reader and the
+// underlying response body.
+func (g *gzipReadCloser) Close() error {
+	if err := g.Reader.Close(); err != nil {
+		g.body.Close()
+		return err
+	}
+
+	return g.body.Close()
 }
 
 // ChangeBasePath changes base path to allow switching to mocks
@@ -223,6 +288,14 @@ func (c *APIClient) prepareRequest(
 	// Add the user agent to the request.
 	localVarRequest.Header.Add("User-Agent", c.cfg.UserAgent)
 
+	// Request compressed responses, if applicable. When
+	// Accept-Encoding is set explicitly, the transport does
+	// not decompress the response (so it is decompressed
+	// in callAPI).
+	if c.cfg.Compression {
+		localVarRequest.Header.Set("Accept-Encoding", "gzip")
+	}
+
 	if ctx != nil {
 		// add context to the request
 		localVarRequest = localVarRequest.WithContext(ctx)
@@ -232,6 +305,25 @@ func (c *APIClient) prepareRequest(
 		localVarRequest.Header.Add(header, value)
 	}
 
+	if ctx != nil {
+		// Basic HTTP Authentication
+		if auth, ok := ctx.Value(ContextBasicAuth).(BasicAuth); ok {
+			localVarRequest.SetBasicAuth(auth.UserName, auth.Password)
+		}
+
+		// AccessToken Authentication
+		if auth, ok := ctx.Value(ContextAccessToken).(string); ok {
+			localVarRequest.Header.Set("Authorization", "Bearer "+auth)
+		}
+
+		// Per-request headers (override any default headers)
+		if headers, ok := ctx.Value(ContextHeaders).(map[string]string); ok {
+			for header, value := range headers {
+				localVarRequest.Header.Set(header, value)
+			}
+		}
+	}
+
 	return localVarRequest, nil
 }
 
@@ -255,6 +347,48 @@ func (c *APIClient) decode(v interface{}, b []byte, contentType string) (err err
 	return fmt.Errorf("unsupported response content-type: %s body: %s", contentType, string(b))
 }
 
+// StatusError is returned by the APIClient when a request fails
+// with an HTTP status code other than 200. If the response body
+// is a *types.Error, it is also returned by the request.
+type StatusError struct {
+	StatusCode int
+	Err        error
+}
+
+// Error returns the message of Err.
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns Err (so that errors.Is
+// can be used on a *StatusError).
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// unexpectedStatus returns the *types.Error (if the response body
+// is a *types.Error) and *StatusError for a response with a status
+// code that is not defined in the spec (ex: a 4xx status code).
+func (c *APIClient) unexpectedStatus(resp *http.Response, body []byte) (*types.Error, error) {
+	var v types.Error
+	err := c.decode(&v, body, resp.Header.Get("Content-Type"))
+	if err == nil && len(v.Message) > 0 {
+		return &v, &StatusError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("%+v", v),
+		}
+	}
+
+	return nil, &StatusError{
+		StatusCode: resp.StatusCode,
+		Err: fmt.Errorf(
+			"invalid status code: %d body: %s",
+			resp.StatusCode,
+			string(body),
+		),
+	}
+}
+
 // Set request body from an interface{}
 func setBody(body interface{}, contentType string) (bodyBuf *bytes.Buffer, err error) {
 	if bodyBuf == nil {
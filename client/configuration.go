@@ -44,6 +44,10 @@ var (
 
 	// ContextAPIKey takes an APIKey as authentication for the request
 	ContextAPIKey = contextKey("apikey")
+
+	// ContextHeaders takes a map[string]string of headers to add to the
+	// request (overriding any default headers with the same name).
+	ContextHeaders = contextKey("headers")
 )
 
 // BasicAuth provides basic http authentication to a request passed via context using
@@ -59,6 +63,21 @@ type APIKey struct {
 	Prefix string
 }
 
+// RequestInterceptor is invoked with each request before it
+// is sent (ex: to add tracing headers or to rewrite the URL).
+// Returning an error prevents the request (and any subsequent
+// interceptors) from being executed and the error is returned
+// to the caller.
+type RequestInterceptor func(*http.Request) error
+
+// ResponseInterceptor is invoked with the response (or error)
+// of each request after it is received (ex: to record the raw
+// payload). Returning an error prevents any subsequent
+// interceptors from being executed and the request fails with
+// the returned error (wrap ErrRetriable to indicate that
+// the request should be retried).
+type ResponseInterceptor func(*http.Response, error) error
+
 // ServerVariable stores the information about a server variable
 type ServerVariable struct {
 	Description  string
@@ -74,6 +93,9 @@ type ServerConfiguration struct {
 }
 
 // Configuration stores the configuration of the API client
+//
+// If Compression is true, the client requests gzip compressed
+// responses (by sending "Accept-Encoding: gzip") and decompresses them.
 type Configuration struct {
 	BasePath      string            `json:"basePath,omitempty"`
 	Host          string            `json:"host,omitempty"`
@@ -81,8 +103,14 @@ type Configuration struct {
 	DefaultHeader map[string]string `json:"defaultHeader,omitempty"`
 	UserAgent     string            `json:"userAgent,omitempty"`
 	Debug         bool              `json:"debug,omitempty"`
+	Compression   bool              `json:"compression,omitempty"`
 	Servers       []ServerConfiguration
 	HTTPClient    *http.Client
+
+	// Interceptors are executed in order (see
+	// RequestInterceptor and ResponseInterceptor).
+	RequestInterceptors  []RequestInterceptor
+	ResponseInterceptors []ResponseInterceptor
 }
 
 // NewConfiguration returns a new Configuration object
@@ -32,7 +32,7 @@ esac
 # Remove existing client generated code
 mkdir -p tmp;
 DIRS=( types client server )
-IGNORED_FILES=( README.md utils.go utils_test.go marshal_test.go account_currency.go account_coin.go )
+IGNORED_FILES=( README.md utils.go utils_test.go marshal_test.go account_currency.go account_coin.go middleware.go middleware_test.go validation.go validation_test.go indexer_test.go compression.go compression_test.go streaming.go streaming_test.go )
 
 for dir in "${DIRS[@]}"
 do
@@ -0,0 +1,55 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/constructor/worker"
+)
+
+// Option is used to overwrite default values in
+// Coordinator construction. Any Option not provided
+// falls back to the default value.
+type Option func(c *Coordinator)
+
+// WithWorkerOptions provides options to use when
+// constructing the *worker.Worker used to process
+// Jobs (ex: worker.WithAllowedHosts).
+func WithWorkerOptions(options ...worker.Option) Option {
+	return func(c *Coordinator) {
+		c.workerOptions = options
+	}
+}
+
+// WithJobTimeout sets the default amount of time a Job
+// may take to complete before it is marked as failed. This
+// can be overridden by the Timeout of a Workflow or the
+// timeout reserved variable of a Job.
+func WithJobTimeout(timeout time.Duration) Option {
+	return func(c *Coordinator) {
+		c.jobTimeout = timeout
+	}
+}
+
+// WithDryRun causes the Coordinator to construct, sign, and
+// parse transactions without ever broadcasting them. Jobs
+// are marked as job.CompletedWithoutBroadcast once their
+// first transaction is constructed.
+func WithDryRun() Option {
+	return func(c *Coordinator) {
+		c.dryRun = true
+	}
+}
@@ -39,6 +39,7 @@ func New(
 	handler Handler,
 	parser *parser.Parser,
 	inputWorkflows []*job.Workflow,
+	options ...Option,
 ) (*Coordinator, error) {
 	if len(inputWorkflows) == 0 {
 		return nil, ErrNoWorkflows
@@ -88,11 +89,10 @@ func New(
 		workflows = append(workflows, workflow)
 	}
 
-	return &Coordinator{
+	c := &Coordinator{
 		storage:               storage,
 		helper:                helper,
 		handler:               handler,
-		worker:                worker.New(helper),
 		parser:                parser,
 		attemptedJobs:         []string{},
 		attemptedWorkflows:    []string{},
@@ -101,7 +101,26 @@ func New(
 		createAccountWorkflow: createAccountWorkflow,
 		requestFundsWorkflow:  requestFundsWorkflow,
 		returnFundsWorkflow:   returnFundsWorkflow,
-	}, nil
+		now:                   time.Now,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	c.worker = worker.New(helper, c.workerOptions...)
+
+	// We only look for timed out jobs if some timeout
+	// is configured to avoid scanning jobs on each
+	// invocation of process.
+	c.timeoutsEnabled = c.jobTimeout > 0
+	for _, workflow := range inputWorkflows {
+		if workflow.Timeout > 0 {
+			c.timeoutsEnabled = true
+		}
+	}
+
+	return c, nil
 }
 
 func (c *Coordinator) findJob(
@@ -218,11 +237,13 @@ func (c *Coordinator) findJob(
 }
 
 // createTransaction constructs and signs a transaction with the provided intent.
+// It returns the parse result of the signed transaction (including its
+// identifier) and the signed network transaction.
 func (c *Coordinator) createTransaction(
 	ctx context.Context,
 	dbTx database.Transaction,
 	broadcast *job.Broadcast,
-) (*types.TransactionIdentifier, string, []*types.Amount, error) {
+) (*job.ParseResult, string, []*types.Amount, error) {
 	metadataRequest, requiredPublicKeys, err := c.helper.Preprocess(
 		ctx,
 		broadcast.Network,
@@ -313,7 +334,7 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: unable to combine signatures", err)
 	}
 
-	signedParsedOps, signers, _, err := c.helper.Parse(
+	signedParsedOps, signers, parsedMetadata, err := c.helper.Parse(
 		ctx,
 		broadcast.Network,
 		true,
@@ -345,7 +366,12 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: unable to get transaction hash", err)
 	}
 
-	return transactionIdentifier, networkTransaction, nil, nil
+	return &job.ParseResult{
+		TransactionIdentifier: transactionIdentifier,
+		Operations:            signedParsedOps,
+		Signers:               signers,
+		Metadata:              parsedMetadata,
+	}, networkTransaction, nil, nil
 }
 
 // BroadcastComplete is called by the broadcast coordinator
@@ -366,6 +392,18 @@ func (c *Coordinator) BroadcastComplete(
 		)
 	}
 
+	// A Job that timed out while broadcasting is already
+	// failed, so there is nothing to update.
+	if j.Status == job.Failed {
+		color.Yellow(
+			"broadcast complete for timed out job \"%s (%s)\"\n",
+			j.Workflow,
+			jobIdentifier,
+		)
+
+		return nil
+	}
+
 	if err := j.BroadcastComplete(ctx, transaction); err != nil {
 		return fmt.Errorf("%w: unable to mark broadcast complete", err)
 	}
@@ -455,6 +493,11 @@ func (c *Coordinator) invokeHandlersAndBroadcast(
 		}
 	}
 
+	// Nothing is ever enqueued for broadcast in dry-run mode.
+	if c.dryRun {
+		return nil
+	}
+
 	// Run Broadcast all after transaction committed.
 	if err := c.helper.BroadcastAll(ctx); err != nil {
 		return fmt.Errorf("%w: unable to broadcast all transactions", err)
@@ -478,6 +521,12 @@ func (c *Coordinator) process( // nolint:gocognit
 		return NoHeadBlockWaitTime, nil
 	}
 
+	if c.timeoutsEnabled {
+		if err := c.expireJobs(ctx); err != nil {
+			return -1, fmt.Errorf("%w: unable to expire jobs", err)
+		}
+	}
+
 	// Update job and store broadcast in a single DB transaction.
 	// If job update fails, all associated state changes are rolled
 	// back.
@@ -523,6 +572,9 @@ func (c *Coordinator) process( // nolint:gocognit
 			c.addToUnprocessed(j)
 			return 0, nil
 		}
+		if errors.Is(executionErr.Err, worker.ErrAssertionFailed) {
+			return c.failJob(ctx, dbTx, j, executionErr)
+		}
 
 		// Log the exeuction error to the terminal so
 		// the caller can debug their scripts.
@@ -531,6 +583,12 @@ func (c *Coordinator) process( // nolint:gocognit
 		return -1, fmt.Errorf("%w: unable to process job", executionErr.Err)
 	}
 
+	if c.timeoutsEnabled {
+		if err := c.setDeadline(j); err != nil {
+			return -1, fmt.Errorf("%w: unable to set job deadline", err)
+		}
+	}
+
 	// Update job (or store for the first time)
 	//
 	// Note, we ALWAYS store jobs even if they are complete on
@@ -543,9 +601,11 @@ func (c *Coordinator) process( // nolint:gocognit
 	j.Identifier = jobIdentifier
 
 	var transactionCreated *types.TransactionIdentifier
+	var skippedTransaction string
+	var skippedParseResult *job.ParseResult
 	if broadcast != nil {
 		// Construct Transaction (or dry run)
-		transactionIdentifier, networkTransaction, suggestedFees, err := c.createTransaction(
+		parseResult, networkTransaction, suggestedFees, err := c.createTransaction(
 			ctx,
 			dbTx,
 			broadcast,
@@ -564,7 +624,26 @@ func (c *Coordinator) process( // nolint:gocognit
 			if _, err := c.storage.Update(ctx, dbTx, j); err != nil {
 				return -1, fmt.Errorf("%w: unable to update job after dry run", err)
 			}
+		} else if c.skipBroadcast(j.Workflow) {
+			// Store the signed transaction on the job instead of
+			// enqueueing it in BroadcastStorage.
+			if err := j.BroadcastSkipped(ctx, networkTransaction, parseResult); err != nil {
+				return -1, fmt.Errorf("%w: unable to mark broadcast skipped", err)
+			}
+
+			if _, err := c.storage.Update(ctx, dbTx, j); err != nil {
+				return -1, fmt.Errorf("%w: unable to update job after skipped broadcast", err)
+			}
+
+			skippedTransaction = networkTransaction
+			skippedParseResult = parseResult
+			log.Printf(
+				`skipped broadcast of transaction "%s" for job "%s" (dry run)`,
+				parseResult.TransactionIdentifier.Hash,
+				jobIdentifier,
+			)
 		} else {
+			transactionIdentifier := parseResult.TransactionIdentifier
 			// Invoke Broadcast storage (in same TX as update job)
 			if err := c.helper.Broadcast(
 				ctx,
@@ -597,6 +676,18 @@ func (c *Coordinator) process( // nolint:gocognit
 		return -1, fmt.Errorf("%w: unable to commit job update", err)
 	}
 
+	skipHandler, ok := c.handler.(SkipHandler)
+	if skippedParseResult != nil && ok {
+		if err := skipHandler.BroadcastSkipped(
+			ctx,
+			jobIdentifier,
+			skippedTransaction,
+			skippedParseResult,
+		); err != nil {
+			return -1, fmt.Errorf("%w: unable to handle skipped broadcast", err)
+		}
+	}
+
 	// Invoke handlers and broadcast
 	if err := c.invokeHandlersAndBroadcast(ctx, jobIdentifier, transactionCreated); err != nil {
 		return -1, fmt.Errorf("%w: unable to handle job success", err)
@@ -605,6 +696,188 @@ func (c *Coordinator) process( // nolint:gocognit
 	return 0, nil
 }
 
+// workflow returns the *job.Workflow with the
+// provided name (or nil if it does not exist).
+func (c *Coordinator) workflow(name string) *job.Workflow {
+	workflows := append([]*job.Workflow{
+		c.createAccountWorkflow,
+		c.requestFundsWorkflow,
+		c.returnFundsWorkflow,
+	}, c.workflows...)
+	for _, workflow := range workflows {
+		if workflow != nil && workflow.Name == name {
+			return workflow
+		}
+	}
+
+	return nil
+}
+
+// workflowTimeout returns the timeout of
+// the workflow with the provided name.
+func (c *Coordinator) workflowTimeout(name string) time.Duration {
+	workflow := c.workflow(name)
+	if workflow != nil && workflow.Timeout > 0 {
+		return time.Duration(workflow.Timeout) * time.Second
+	}
+
+	return c.jobTimeout
+}
+
+// skipBroadcast returns a boolean indicating if
+// transactions constructed by the workflow with the
+// provided name should not be broadcast.
+func (c *Coordinator) skipBroadcast(name string) bool {
+	if c.dryRun {
+		return true
+	}
+
+	workflow := c.workflow(name)
+	return workflow != nil && workflow.DryRun
+}
+
+// setDeadline populates the Deadline of a *job.Job
+// using its timeout and creation time.
+func (c *Coordinator) setDeadline(j *job.Job) error {
+	timeout, err := j.Timeout(c.workflowTimeout(j.Workflow))
+	if err != nil {
+		return err
+	}
+
+	if j.CreatedAt == 0 {
+		j.CreatedAt = c.now().UnixNano() / int64(time.Millisecond)
+	}
+
+	if timeout <= 0 {
+		j.Deadline = 0
+		return nil
+	}
+
+	j.Deadline = j.CreatedAt + int64(timeout/time.Millisecond)
+	c.trackDeadline(j.Deadline)
+	return nil
+}
+
+// trackDeadline records a job deadline so that expireJobs
+// does not scan jobs for timeouts before it is reached.
+func (c *Coordinator) trackDeadline(deadline int64) {
+	if deadline > 0 && (c.nextDeadline == 0 || deadline < c.nextDeadline) {
+		c.nextDeadline = deadline
+	}
+}
+
+// expireJobs marks all jobs that have exceeded their
+// deadline as failed and flags any of their pending
+// broadcasts to expire. This frees the concurrency
+// slots held by these jobs. Jobs are only scanned
+// once the earliest known deadline is reached.
+func (c *Coordinator) expireJobs(ctx context.Context) error {
+	now := c.now()
+	if c.deadlinesLoaded &&
+		(c.nextDeadline == 0 || now.UnixNano()/int64(time.Millisecond) < c.nextDeadline) {
+		return nil
+	}
+
+	dbTx := c.helper.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	ready, err := c.storage.Ready(ctx, dbTx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrJobsUnretrievable, err.Error())
+	}
+
+	broadcasting, err := c.storage.Broadcasting(ctx, dbTx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBroadcastsUnretrievable, err.Error())
+	}
+
+	expirer, canExpireBroadcasts := c.helper.(BroadcastExpirer)
+
+	c.nextDeadline = 0
+	c.deadlinesLoaded = false
+	timedOut := []string{}
+	for _, j := range append(ready, broadcasting...) {
+		if j.Status == job.Broadcasting && !canExpireBroadcasts {
+			continue
+		}
+
+		if !j.Expired(now) {
+			c.trackDeadline(j.Deadline)
+			continue
+		}
+
+		if j.Status == job.Broadcasting {
+			if err := expirer.ExpireBroadcasts(ctx, dbTx, j.Identifier); err != nil {
+				return fmt.Errorf("%w: unable to expire broadcasts", err)
+			}
+		}
+
+		j.Status = job.Failed
+		if _, err := c.storage.Update(ctx, dbTx, j); err != nil {
+			return fmt.Errorf("%w: unable to update timed out job", err)
+		}
+
+		color.Red("job \"%s (%s)\" timed out\n", j.Workflow, j.Identifier)
+		timedOut = append(timedOut, j.Identifier)
+	}
+
+	if len(timedOut) == 0 {
+		c.deadlinesLoaded = true
+		return nil
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: unable to commit timed out jobs", err)
+	}
+	c.deadlinesLoaded = true
+
+	// Jobs that were previously blocked on
+	// a concurrency slot may now be processed.
+	c.resetVars()
+
+	timeoutHandler, ok := c.handler.(TimeoutHandler)
+	if !ok {
+		return nil
+	}
+
+	for _, jobIdentifier := range timedOut {
+		if err := timeoutHandler.JobTimedOut(ctx, jobIdentifier); err != nil {
+			return fmt.Errorf("%w: unable to handle job timeout", err)
+		}
+	}
+
+	return nil
+}
+
+// failJob marks a *job.Job as failed when one of its
+// assertions is not satisfied. Unlike other execution
+// errors, this does not halt processing.
+func (c *Coordinator) failJob(
+	ctx context.Context,
+	dbTx database.Transaction,
+	j *job.Job,
+	executionErr *worker.Error,
+) (time.Duration, error) {
+	// Log the execution error to the terminal so
+	// the caller can see which assertion failed.
+	executionErr.Log()
+
+	j.Status = job.Failed
+	jobIdentifier, err := c.storage.Update(ctx, dbTx, j)
+	if err != nil {
+		return -1, fmt.Errorf("%w: unable to update failed job", err)
+	}
+
+	c.resetVars()
+	log.Printf(`failed workflow "%s" for job "%s"`, j.Workflow, jobIdentifier)
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return -1, fmt.Errorf("%w: unable to commit failed job", err)
+	}
+
+	return 0, nil
+}
+
 // processLoop calls process until we should
 // not continue or an error is returned.
 func (c *Coordinator) processLoop(
@@ -19,9 +19,11 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/tidwall/gjson"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/constructor/job"
@@ -2467,3 +2469,670 @@ func TestNoReservedWorkflows(t *testing.T) {
 	jobStorage.AssertExpectations(t)
 	helper.AssertExpectations(t)
 }
+
+// handlerWithTimeout is a Handler that also
+// implements TimeoutHandler.
+type handlerWithTimeout struct {
+	*mocks.Handler
+	*mocks.TimeoutHandler
+}
+
+func TestProcess_JobTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	jobStorage := &mocks.JobStorage{}
+	helper := &mocks.Helper{}
+	handler := &mocks.Handler{}
+	timeoutHandler := &mocks.TimeoutHandler{}
+	p := defaultParser(t)
+	workflows := []*job.Workflow{
+		{
+			Name:        "transfer",
+			Concurrency: 1,
+			Timeout:     600,
+			Scenarios: []*job.Scenario{
+				{
+					Name: "setup",
+					Actions: []*job.Action{
+						{
+							Type:       job.SetVariable,
+							Input:      `{"symbol":"tBTC", "decimals":8}`,
+							OutputPath: "currency",
+						},
+						{ // override the workflow timeout
+							Type:       job.SetVariable,
+							Input:      `"60"`,
+							OutputPath: "timeout",
+						},
+					},
+				},
+				{
+					Name: "wait",
+					Actions: []*job.Action{
+						{
+							Type:       job.FindBalance,
+							Input:      `{"minimum_balance":{"value": "100", "currency": {{currency}}}}`,
+							OutputPath: "sender",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	c, err := New(
+		jobStorage,
+		helper,
+		&handlerWithTimeout{handler, timeoutHandler},
+		p,
+		workflows,
+	)
+	assert.NotNil(t, c)
+	assert.NoError(t, err)
+
+	currentTime := time.Unix(1000, 0)
+	c.now = func() time.Time {
+		return currentTime
+	}
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		dir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, db)
+	defer db.Close(ctx)
+
+	// Start first job
+	helper.On("HeadBlockExists", ctx).Return(true).Once()
+	expireTx := db.ReadTransaction(ctx)
+	helper.On("DatabaseTransaction", ctx).Return(expireTx).Once()
+	jobStorage.On("Ready", ctx, expireTx).Return([]*job.Job{}, nil).Once()
+	jobStorage.On("Broadcasting", ctx, expireTx).Return([]*job.Job{}, nil).Once()
+	dbTx := db.ReadTransaction(ctx)
+	helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+	jobStorage.On("Ready", ctx, dbTx).Return([]*job.Job{}, nil).Once()
+	jobStorage.On("Processing", ctx, dbTx, "transfer").Return([]*job.Job{}, nil).Once()
+	var j job.Job
+	jobStorage.On(
+		"Update",
+		ctx,
+		dbTx,
+		mock.Anything,
+	).Return(
+		jobIdentifier,
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			j = *args.Get(2).(*job.Job)
+			j.Identifier = jobIdentifier
+		},
+	).Once()
+	helper.On("BroadcastAll", ctx).Return(nil).Once()
+
+	sleepTime, err := c.process(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), sleepTime)
+	assert.Equal(t, job.Ready, j.Status)
+	assert.Equal(t, int64(1000000), j.CreatedAt)
+	assert.Equal(t, int64(1060000), j.Deadline)
+
+	// Job is stuck waiting for funds and holds the
+	// only concurrency slot (jobs are not scanned for
+	// timeouts before the earliest deadline)
+	currentTime = currentTime.Add(30 * time.Second)
+	helper.On("HeadBlockExists", ctx).Return(true).Once()
+	dbTx2 := db.ReadTransaction(ctx)
+	helper.On("DatabaseTransaction", ctx).Return(dbTx2).Once()
+	jobStorage.On("Ready", ctx, dbTx2).Return([]*job.Job{&j}, nil).Once()
+	helper.On("AllAccounts", ctx, dbTx2).Return([]*types.AccountIdentifier{
+		{Address: "address1"},
+	}, nil).Once()
+	helper.On("LockedAccounts", ctx, dbTx2).Return([]*types.AccountIdentifier{
+		{Address: "address1"},
+	}, nil).Once()
+
+	sleepTime, err = c.process(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), sleepTime)
+
+	helper.On("HeadBlockExists", ctx).Return(true).Once()
+	dbTx3 := db.ReadTransaction(ctx)
+	helper.On("DatabaseTransaction", ctx).Return(dbTx3).Once()
+	jobStorage.On("Ready", ctx, dbTx3).Return([]*job.Job{&j}, nil).Once()
+	jobStorage.On("Processing", ctx, dbTx3, "transfer").Return([]*job.Job{&j}, nil).Once()
+	jobStorage.On("Broadcasting", ctx, dbTx3).Return([]*job.Job{}, nil).Once()
+
+	sleepTime, err = c.process(ctx, false)
+	assert.True(t, errors.Is(err, ErrStalled))
+	assert.Equal(t, time.Duration(-1), sleepTime)
+
+	// Fast-forward past the deadline so the job times out
+	// and its slot is freed for a new job
+	currentTime = currentTime.Add(2 * time.Minute)
+	helper.On("HeadBlockExists", ctx).Return(true).Once()
+	expireTx4 := db.ReadTransaction(ctx)
+	helper.On("DatabaseTransaction", ctx).Return(expireTx4).Once()
+	jobStorage.On("Ready", ctx, expireTx4).Return([]*job.Job{&j}, nil).Once()
+	jobStorage.On("Broadcasting", ctx, expireTx4).Return([]*job.Job{}, nil).Once()
+	jobStorage.On(
+		"Update",
+		ctx,
+		expireTx4,
+		mock.Anything,
+	).Return(
+		jobIdentifier,
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			assert.Equal(t, job.Failed, args.Get(2).(*job.Job).Status)
+		},
+	).Once()
+	timeoutHandler.On("JobTimedOut", ctx, jobIdentifier).Return(nil).Once()
+	dbTx4 := db.ReadTransaction(ctx)
+	helper.On("DatabaseTransaction", ctx).Return(dbTx4).Once()
+	jobStorage.On("Ready", ctx, dbTx4).Return([]*job.Job{}, nil).Once()
+	jobStorage.On("Processing", ctx, dbTx4, "transfer").Return([]*job.Job{}, nil).Once()
+	var j2 job.Job
+	jobStorage.On(
+		"Update",
+		ctx,
+		dbTx4,
+		mock.Anything,
+	).Return(
+		"job2",
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			j2 = *args.Get(2).(*job.Job)
+			j2.Identifier = "job2"
+		},
+	).Once()
+	helper.On("BroadcastAll", ctx).Return(nil).Once()
+
+	sleepTime, err = c.process(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), sleepTime)
+	assert.Equal(t, job.Ready, j2.Status)
+	assert.Equal(t, int64(1150000), j2.CreatedAt)
+	assert.Equal(t, int64(1210000), j2.Deadline)
+
+	jobStorage.AssertExpectations(t)
+	helper.AssertExpectations(t)
+	handler.AssertExpectations(t)
+	timeoutHandler.AssertExpectations(t)
+}
+
+// helperWithExpirer is a Helper that also
+// implements BroadcastExpirer.
+type helperWithExpirer struct {
+	*mocks.Helper
+	*mocks.BroadcastExpirer
+}
+
+func TestExpireJobs_Broadcasting(t *testing.T) {
+	tests := map[string]struct {
+		expirer bool
+	}{
+		"helper without BroadcastExpirer": {},
+		"helper with BroadcastExpirer": {
+			expirer: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			jobStorage := &mocks.JobStorage{}
+			helper := &mocks.Helper{}
+			expirer := &mocks.BroadcastExpirer{}
+			handler := &mocks.Handler{}
+			p := defaultParser(t)
+			workflows := []*job.Workflow{
+				{
+					Name:        "transfer",
+					Concurrency: 1,
+					Timeout:     60,
+				},
+			}
+
+			var coordinatorHelper Helper = helper
+			if test.expirer {
+				coordinatorHelper = &helperWithExpirer{helper, expirer}
+			}
+
+			c, err := New(
+				jobStorage,
+				coordinatorHelper,
+				handler,
+				p,
+				workflows,
+			)
+			assert.NotNil(t, c)
+			assert.NoError(t, err)
+
+			c.now = func() time.Time {
+				return time.Unix(1000, 0)
+			}
+
+			dir, err := utils.CreateTempDir()
+			assert.NoError(t, err)
+			defer utils.RemoveTempDir(dir)
+
+			db, err := database.NewBadgerDatabase(
+				ctx,
+				dir,
+				database.WithIndexCacheSize(database.TinyIndexCacheSize),
+			)
+			assert.NoError(t, err)
+			defer db.Close(ctx)
+
+			j := &job.Job{
+				Identifier: jobIdentifier,
+				Workflow:   "transfer",
+				Status:     job.Broadcasting,
+				CreatedAt:  900000,
+				Deadline:   960000,
+			}
+			dbTx := db.ReadTransaction(ctx)
+			helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+			jobStorage.On("Ready", ctx, dbTx).Return([]*job.Job{}, nil).Once()
+			jobStorage.On("Broadcasting", ctx, dbTx).Return([]*job.Job{j}, nil).Once()
+			if test.expirer {
+				expirer.On("ExpireBroadcasts", ctx, dbTx, jobIdentifier).Return(nil).Once()
+				jobStorage.On(
+					"Update",
+					ctx,
+					dbTx,
+					mock.Anything,
+				).Return(
+					jobIdentifier,
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						assert.Equal(t, job.Failed, args.Get(2).(*job.Job).Status)
+					},
+				).Once()
+			}
+
+			assert.NoError(t, c.expireJobs(ctx))
+
+			// Jobs are not scanned again until a
+			// new deadline is tracked.
+			assert.NoError(t, c.expireJobs(ctx))
+			assert.True(t, c.deadlinesLoaded)
+			assert.Equal(t, int64(0), c.nextDeadline)
+
+			jobStorage.AssertExpectations(t)
+			helper.AssertExpectations(t)
+			expirer.AssertExpectations(t)
+			handler.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBroadcastComplete_TimedOutJob(t *testing.T) {
+	ctx := context.Background()
+
+	jobStorage := &mocks.JobStorage{}
+	helper := &mocks.Helper{}
+	handler := &mocks.Handler{}
+	p := defaultParser(t)
+	workflows := []*job.Workflow{
+		{
+			Name:        "transfer",
+			Concurrency: 1,
+			Timeout:     60,
+		},
+	}
+
+	c, err := New(
+		jobStorage,
+		helper,
+		handler,
+		p,
+		workflows,
+	)
+	assert.NotNil(t, c)
+	assert.NoError(t, err)
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		dir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	defer db.Close(ctx)
+
+	dbTx := db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	// An expired broadcast of a timed out job should
+	// not attempt to update the (terminal) job.
+	jobStorage.On("Get", ctx, dbTx, jobIdentifier).Return(&job.Job{
+		Identifier: jobIdentifier,
+		Workflow:   "transfer",
+		Status:     job.Failed,
+	}, nil).Once()
+
+	assert.NoError(t, c.BroadcastComplete(ctx, dbTx, jobIdentifier, nil))
+
+	jobStorage.AssertExpectations(t)
+	helper.AssertExpectations(t)
+	handler.AssertExpectations(t)
+}
+
+// handlerWithSkip is a Handler that also
+// implements SkipHandler.
+type handlerWithSkip struct {
+	*mocks.Handler
+	*mocks.SkipHandler
+}
+
+func TestProcess_SkipBroadcast(t *testing.T) {
+	tests := map[string]struct {
+		options        []Option
+		workflowDryRun bool
+		broadcastAll   bool
+		noSkipHandler  bool
+	}{
+		"coordinator dry run": {
+			options: []Option{WithDryRun()},
+		},
+		"workflow dry run": {
+			workflowDryRun: true,
+			broadcastAll:   true,
+		},
+		"handler without SkipHandler": {
+			options:       []Option{WithDryRun()},
+			noSkipHandler: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			jobStorage := &mocks.JobStorage{}
+			helper := &mocks.Helper{}
+			handler := &mocks.Handler{}
+			skipHandler := &mocks.SkipHandler{}
+			p := defaultParser(t)
+			workflows := []*job.Workflow{
+				{
+					Name:        "transfer",
+					Concurrency: 1,
+					DryRun:      test.workflowDryRun,
+					Scenarios: []*job.Scenario{
+						{
+							Name: "transfer",
+							Actions: []*job.Action{
+								{
+									Type:       job.SetVariable,
+									Input:      `{"network":"Testnet3", "blockchain":"Bitcoin"}`,
+									OutputPath: "transfer.network",
+								},
+								{
+									Type:       job.SetVariable,
+									Input:      `"1"`,
+									OutputPath: "transfer.confirmation_depth",
+								},
+								{
+									Type:       job.SetVariable,
+									Input:      `[{"operation_identifier":{"index":0},"type":"Vin","account":{"address":"address1"},"amount":{"value":"-100","currency":{"symbol":"tBTC","decimals":8}}},{"operation_identifier":{"index":1},"type":"Vout","account":{"address":"address2"},"amount":{"value":"90","currency":{"symbol":"tBTC","decimals":8}}}]`, // nolint
+									OutputPath: "transfer.operations",
+								},
+							},
+						},
+						{
+							Name: "print_transaction",
+							Actions: []*job.Action{
+								{
+									Type:  job.PrintMessage,
+									Input: `{{transfer.transaction}}`,
+								},
+							},
+						},
+					},
+				},
+			}
+
+			var coordinatorHandler Handler = &handlerWithSkip{handler, skipHandler}
+			if test.noSkipHandler {
+				coordinatorHandler = handler
+			}
+
+			c, err := New(
+				jobStorage,
+				helper,
+				coordinatorHandler,
+				p,
+				workflows,
+				test.options...,
+			)
+			assert.NotNil(t, c)
+			assert.NoError(t, err)
+
+			dir, err := utils.CreateTempDir()
+			assert.NoError(t, err)
+			defer utils.RemoveTempDir(dir)
+
+			db, err := database.NewBadgerDatabase(
+				ctx,
+				dir,
+				database.WithIndexCacheSize(database.TinyIndexCacheSize),
+			)
+			assert.NoError(t, err)
+			assert.NotNil(t, db)
+			defer db.Close(ctx)
+
+			helper.On("HeadBlockExists", ctx).Return(true).Once()
+			dbTx := db.ReadTransaction(ctx)
+			helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+			jobStorage.On("Ready", ctx, dbTx).Return([]*job.Job{}, nil).Once()
+			jobStorage.On("Processing", ctx, dbTx, "transfer").Return([]*job.Job{}, nil).Once()
+			var j job.Job
+			jobStorage.On(
+				"Update",
+				ctx,
+				dbTx,
+				mock.Anything,
+			).Return(
+				jobIdentifier,
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					j = *args.Get(2).(*job.Job)
+					j.Identifier = jobIdentifier
+				},
+			).Twice()
+
+			// Construct Transaction
+			network := &types.NetworkIdentifier{
+				Blockchain: "Bitcoin",
+				Network:    "Testnet3",
+			}
+			currency := &types.Currency{
+				Symbol:   "tBTC",
+				Decimals: 8,
+			}
+			ops := []*types.Operation{
+				{
+					OperationIdentifier: &types.OperationIdentifier{
+						Index: 0,
+					},
+					Type: "Vin",
+					Account: &types.AccountIdentifier{
+						Address: "address1",
+					},
+					Amount: &types.Amount{
+						Value:    "-100",
+						Currency: currency,
+					},
+				},
+				{
+					OperationIdentifier: &types.OperationIdentifier{
+						Index: 1,
+					},
+					Type: "Vout",
+					Account: &types.AccountIdentifier{
+						Address: "address2",
+					},
+					Amount: &types.Amount{
+						Value:    "90",
+						Currency: currency,
+					},
+				},
+			}
+			metadataOptions := map[string]interface{}{
+				"metadata": "test",
+			}
+			helper.On(
+				"Preprocess",
+				ctx,
+				network,
+				ops,
+				map[string]interface{}(nil),
+			).Return(metadataOptions, []*types.AccountIdentifier{}, nil, nil).Once()
+			fetchedMetadata := map[string]interface{}{
+				"tx_meta": "help",
+			}
+			helper.On(
+				"Metadata",
+				ctx,
+				network,
+				metadataOptions,
+				[]*types.PublicKey{},
+			).Return(fetchedMetadata, nil, nil).Once()
+			signingPayloads := []*types.SigningPayload{
+				{
+					AccountIdentifier: &types.AccountIdentifier{Address: "address1"},
+					Bytes:             []byte("blah"),
+					SignatureType:     types.Ecdsa,
+				},
+			}
+			helper.On(
+				"Payloads",
+				ctx,
+				network,
+				ops,
+				fetchedMetadata,
+				[]*types.PublicKey{},
+			).Return(unsignedTx, signingPayloads, nil).Once()
+			helper.On(
+				"Parse",
+				ctx,
+				network,
+				false,
+				unsignedTx,
+			).Return(ops, []*types.AccountIdentifier{}, nil, nil).Once()
+			signatures := []*types.Signature{
+				{
+					SigningPayload: signingPayloads[0],
+					PublicKey: &types.PublicKey{
+						Bytes:     []byte("pubkey"),
+						CurveType: types.Secp256k1,
+					},
+					SignatureType: types.Ecdsa,
+					Bytes:         []byte("signature"),
+				},
+			}
+			helper.On(
+				"Sign",
+				ctx,
+				signingPayloads,
+			).Return(signatures, nil).Once()
+			helper.On(
+				"Combine",
+				ctx,
+				network,
+				unsignedTx,
+				signatures,
+			).Return(networkTx, nil).Once()
+			signers := []*types.AccountIdentifier{
+				{Address: "address1"},
+			}
+			parsedMetadata := map[string]interface{}{
+				"parsed": "metadata",
+			}
+			helper.On(
+				"Parse",
+				ctx,
+				network,
+				true,
+				networkTx,
+			).Return(ops, signers, parsedMetadata, nil).Once()
+			txIdentifier := &types.TransactionIdentifier{Hash: "transaction hash"}
+			helper.On(
+				"Hash",
+				ctx,
+				network,
+				networkTx,
+			).Return(txIdentifier, nil).Once()
+			parseResult := &job.ParseResult{
+				TransactionIdentifier: txIdentifier,
+				Operations:            ops,
+				Signers:               signers,
+				Metadata:              parsedMetadata,
+			}
+			if !test.noSkipHandler {
+				skipHandler.On(
+					"BroadcastSkipped",
+					ctx,
+					jobIdentifier,
+					networkTx,
+					parseResult,
+				).Return(nil).Once()
+			}
+			if test.broadcastAll {
+				helper.On("BroadcastAll", ctx).Return(nil).Once()
+			}
+
+			sleepTime, err := c.process(ctx, false)
+			assert.NoError(t, err)
+			assert.Equal(t, time.Duration(0), sleepTime)
+
+			// The signed transaction and parse result should be
+			// stored on the job without creating a broadcast.
+			assert.Equal(t, job.CompletedWithoutBroadcast, j.Status)
+			assert.Equal(t, 1, j.Index)
+			assert.Equal(t, networkTx, gjson.Get(j.State, "transfer.signed_transaction").String())
+			assert.Equal(
+				t,
+				types.Hash(parseResult),
+				types.Hash(gjson.Get(j.State, "transfer.parsed_transaction").Value()),
+			)
+			helper.AssertNotCalled(
+				t,
+				"Broadcast",
+				mock.Anything,
+				mock.Anything,
+				mock.Anything,
+				mock.Anything,
+				mock.Anything,
+				mock.Anything,
+				mock.Anything,
+				mock.Anything,
+			)
+			handler.AssertNotCalled(t, "TransactionCreated", mock.Anything, mock.Anything, mock.Anything)
+			if !test.broadcastAll {
+				helper.AssertNotCalled(t, "BroadcastAll", mock.Anything)
+			}
+
+			jobStorage.AssertExpectations(t)
+			helper.AssertExpectations(t)
+			handler.AssertExpectations(t)
+			skipHandler.AssertExpectations(t)
+		})
+	}
+}
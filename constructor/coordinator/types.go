@@ -209,6 +209,40 @@ type Handler interface {
 	) error
 }
 
+// SkipHandler can optionally be implemented by a Handler to
+// be notified (instead of TransactionCreated) when a transaction
+// is constructed in dry-run mode and its broadcast is skipped.
+type SkipHandler interface {
+	BroadcastSkipped(
+		context.Context,
+		string, // job identifier
+		string, // signed transaction
+		*job.ParseResult,
+	) error
+}
+
+// BroadcastExpirer can optionally be implemented by a Helper
+// to flag all pending broadcasts of a Job to expire when it
+// times out. If a Helper does not implement BroadcastExpirer,
+// jobs are not timed out while they are broadcasting.
+type BroadcastExpirer interface {
+	ExpireBroadcasts(
+		context.Context,
+		database.Transaction,
+		string, // Job.Identifier
+	) error
+}
+
+// TimeoutHandler can optionally be implemented by a Handler
+// to be notified when a Job exceeds its timeout and is
+// marked as failed.
+type TimeoutHandler interface {
+	JobTimedOut(
+		context.Context,
+		string, // job identifier
+	) error
+}
+
 // Coordinator faciliates the creation and processing
 // of jobs.
 type Coordinator struct {
@@ -218,6 +252,28 @@ type Coordinator struct {
 	parser  *parser.Parser
 	worker  *worker.Worker
 
+	workerOptions []worker.Option
+
+	// dryRun indicates that no transactions
+	// should be broadcast.
+	dryRun bool
+
+	// jobTimeout is the timeout used for workflows
+	// that do not specify a timeout.
+	jobTimeout      time.Duration
+	timeoutsEnabled bool
+
+	// now is used to determine if a job has
+	// timed out (overridden in tests).
+	now func() time.Time
+
+	// nextDeadline is the earliest deadline of any
+	// job that may time out (0 if there is none). Jobs
+	// are only scanned for timeouts once it is reached
+	// (or if deadlinesLoaded is false).
+	nextDeadline    int64
+	deadlinesLoaded bool
+
 	attemptedJobs        []string
 	attemptedWorkflows   []string
 	seenErrCreateAccount bool
@@ -38,6 +38,10 @@ var (
 	// dry run completion.
 	ErrUnableToHandleDryRun = errors.New("unable to handle dry run")
 
+	// ErrUnableToHandleBroadcastSkipped is returned if a Job cannot
+	// handle a skipped broadcast (in dry-run mode).
+	ErrUnableToHandleBroadcastSkipped = errors.New("unable to handle skipped broadcast")
+
 	// ErrUnableToCreateBroadcast is returned when it is not possible
 	// to create a broadcast or check if a broadcast should be created
 	// from a job.
@@ -59,4 +63,9 @@ var (
 	// are populated, but construction preprocess metadata is
 	// invalid (ok to be missing).
 	ErrMetadataInvalid = errors.New("metadata invalid")
+
+	// ErrTimeoutInvalid is returned when the timeout
+	// variable is populated but is not a non-negative
+	// number of seconds.
+	ErrTimeoutInvalid = errors.New("invalid timeout")
 )
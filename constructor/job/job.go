@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
@@ -247,3 +248,71 @@ func (j *Job) DryRunComplete(
 
 	return nil
 }
+
+// BroadcastSkipped is invoked instead of broadcasting
+// a transaction when in dry-run mode. The signed transaction
+// and its parse result are stored in the scenario state and
+// the Job is marked as CompletedWithoutBroadcast.
+func (j *Job) BroadcastSkipped(
+	ctx context.Context,
+	signedTransaction string,
+	parseResult *ParseResult,
+) error {
+	scenario, err := j.getBroadcastScenario()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnableToHandleBroadcastSkipped, err.Error())
+	}
+
+	newState, err := sjson.Set(
+		j.State,
+		fmt.Sprintf("%s.%s", scenario.Name, SignedTransaction),
+		signedTransaction,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: unable to store signed transaction in state %s",
+			ErrUnableToHandleBroadcastSkipped,
+			err,
+		)
+	}
+
+	newState, err = sjson.SetRaw(
+		newState,
+		fmt.Sprintf("%s.%s", scenario.Name, ParsedTransaction),
+		types.PrintStruct(parseResult),
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: unable to store parse result in state %s",
+			ErrUnableToHandleBroadcastSkipped,
+			err,
+		)
+	}
+
+	j.State = newState
+	j.Status = CompletedWithoutBroadcast
+	return nil
+}
+
+// Timeout returns the amount of time a Job may take to
+// complete. If the Timeout reserved variable is populated,
+// it overrides the provided workflowTimeout.
+func (j *Job) Timeout(workflowTimeout time.Duration) (time.Duration, error) {
+	value := gjson.Get(j.State, string(Timeout))
+	if !value.Exists() {
+		return workflowTimeout, nil
+	}
+
+	seconds, ok := new(big.Int).SetString(value.String(), 10)
+	if !ok || !seconds.IsInt64() || seconds.Sign() < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrTimeoutInvalid, value.Raw)
+	}
+
+	return time.Duration(seconds.Int64()) * time.Second, nil
+}
+
+// Expired returns a boolean indicating if a Job
+// has exceeded its Deadline at the provided time.
+func (j *Job) Expired(now time.Time) bool {
+	return j.Deadline > 0 && now.UnixNano()/int64(time.Millisecond) >= j.Deadline
+}
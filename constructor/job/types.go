@@ -64,6 +64,23 @@ const (
 	// SuggestedFee is the []*types.Amount returned from
 	// an implementation's /construction/metadata endpoint (if implemented).
 	SuggestedFee ReservedVariable = "suggested_fee"
+
+	// SignedTransaction is the network transaction constructed
+	// for a scenario when its broadcast is skipped because the
+	// coordinator (or Workflow) is in dry-run mode.
+	SignedTransaction ReservedVariable = "signed_transaction"
+
+	// ParsedTransaction is the *ParseResult returned from
+	// an implementation's /construction/parse endpoint for
+	// the SignedTransaction of a scenario.
+	ParsedTransaction ReservedVariable = "parsed_transaction"
+
+	// Timeout is the number of seconds a Job may take to
+	// complete before it is marked as failed. Unlike other
+	// reserved variables, it is read from the top level of
+	// a Job's state (not scoped to a scenario) and overrides
+	// the Workflow's Timeout.
+	Timeout ReservedVariable = "timeout"
 )
 
 // ActionType is a type of Action that can be processed.
@@ -80,6 +97,13 @@ const (
 	// Derive calls `/construction/derive` with a *keys.PublicKey.
 	Derive ActionType = "derive"
 
+	// GenerateAccounts generates some number of *keys.KeyPair,
+	// derives their *types.AccountIdentifier using
+	// `/construction/derive`, and saves them to key storage.
+	// This is useful when setting up scenarios that require
+	// many accounts (like multi-signature or batch transfers).
+	GenerateAccounts ActionType = "generate_accounts"
+
 	// SetVariable allows for setting the value of any
 	// variable (as opposed to calculating it using
 	// other actions).
@@ -101,9 +125,10 @@ const (
 	// execution.
 	PrintMessage ActionType = "print_message"
 
-	// Math is used to perform addition or subtraction of variables. It is
-	// most commonly used to determine how much to send to a change output
-	// on UTXO blockchains.
+	// Math is used to perform arithmetic (addition, subtraction,
+	// multiplication, division, and modulo) or comparison of variables.
+	// It is most commonly used to determine how much to send to a change
+	// output on UTXO blockchains.
 	Math ActionType = "math"
 
 	// RandomString generates a string according to some provided regex.
@@ -111,7 +136,7 @@ const (
 	// on-chain origination.
 	RandomString ActionType = "random_string"
 
-	// RandomNumber generates a random number in some range [min, max).
+	// RandomNumber generates a random number in some range [min, max].
 	// It is used to generate random transaction amounts.
 	RandomNumber ActionType = "random_number"
 
@@ -126,6 +151,22 @@ const (
 	// suggested fee to broadcast a transaction.
 	Assert ActionType = "assert"
 
+	// AssertEqual ensures that two provided values are equal and
+	// causes the job to fail if this is not true. If both values
+	// are integers (or strings containing integers), they are
+	// compared numerically. Otherwise, they are compared as JSON.
+	AssertEqual ActionType = "assert_equal"
+
+	// AssertNotEmpty ensures that a provided value is not null,
+	// an empty string, an empty array, or an empty object and
+	// causes the job to fail if this is not true.
+	AssertNotEmpty ActionType = "assert_not_empty"
+
+	// AssertIn ensures that a provided value is equal (using the
+	// same semantics as AssertEqual) to some element of a provided
+	// array and causes the job to fail if this is not true.
+	AssertIn ActionType = "assert_in"
+
 	// LoadEnv loads some value from an environment variable. This
 	// is very useful injecting an API token for algorithmic fauceting
 	// when running CI.
@@ -133,7 +174,8 @@ const (
 
 	// HTTPRequest makes an HTTP request at some URL. This is useful
 	// for making a request to a faucet to automate Construction API
-	// testing.
+	// testing. Requests are only made to hosts allowed by the
+	// Worker (see worker.WithAllowedHosts).
 	HTTPRequest ActionType = "http_request"
 
 	// SetBlob stores an arbitrary blob at some key (any valid JSON is
@@ -164,6 +206,17 @@ type GenerateKeyInput struct {
 	CurveType types.CurveType `json:"curve_type"`
 }
 
+// GenerateAccountsInput is the input for GenerateAccounts.
+type GenerateAccountsInput struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	CurveType         types.CurveType          `json:"curve_type"`
+	Count             int                      `json:"count"`
+
+	// Metadata is provided to `/construction/derive`
+	// for each generated key.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
 // SaveAccountInput is the input for SaveAccount.
 type SaveAccountInput struct {
 	AccountIdentifier *types.AccountIdentifier `json:"account_identifier"`
@@ -189,6 +242,34 @@ const (
 
 	// Subtraction is LeftValue - RightValue.
 	Subtraction MathOperation = "subtraction"
+
+	// Multiplication is LeftValue * RightValue.
+	Multiplication MathOperation = "multiplication"
+
+	// Division is LeftValue / RightValue, rounded
+	// according to the provided RoundingMode.
+	Division MathOperation = "division"
+
+	// Modulo is LeftValue mod RightValue. The result
+	// is always non-negative (Euclidean modulus).
+	Modulo MathOperation = "modulo"
+
+	// Comparison compares LeftValue and RightValue. The
+	// result is "-1" if LeftValue < RightValue, "0" if
+	// LeftValue == RightValue, and "1" if LeftValue > RightValue.
+	Comparison MathOperation = "comparison"
+)
+
+// RoundingMode determines how the result of
+// a Division is rounded when it is not an integer.
+type RoundingMode string
+
+const (
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor RoundingMode = "floor"
+
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling RoundingMode = "ceiling"
 )
 
 // MathInput is the input to Math.
@@ -196,6 +277,10 @@ type MathInput struct {
 	Operation  MathOperation `json:"operation"`
 	LeftValue  string        `json:"left_value"`
 	RightValue string        `json:"right_value"`
+
+	// Rounding must be populated when Operation
+	// is Division and is ignored otherwise.
+	Rounding RoundingMode `json:"rounding,omitempty"`
 }
 
 // FindBalanceInput is the input to FindBalance.
@@ -234,6 +319,19 @@ type FindBalanceInput struct {
 	// for avoiding using the same Coin twice.
 	NotCoins []*types.CoinIdentifier `json:"not_coins,omitempty"`
 
+	// RequireCoins is the minimum number of coins the account must hold
+	// (excluding NotCoins) to be returned. This is useful when constructing
+	// multi-input transactions on UTXO-based blockchains where the total
+	// balance alone does not guarantee a usable coin distribution.
+	// If the value is <= 0, the number of coins is not checked.
+	RequireCoins int `json:"require_coins,omitempty"`
+
+	// MinimumCoinValue is the minimum value (in the currency of MinimumBalance)
+	// a coin must have to be counted towards RequireCoins. If RequireCoins is
+	// not populated, the account must hold at least 1 such coin. When
+	// RequireCoin is true, the returned coin must also have at least this value.
+	MinimumCoinValue string `json:"minimum_coin_value,omitempty"`
+
 	// CreateLimit is used to determine if we should create a new address using
 	// the CreateAccount Workflow. This will only occur if the
 	// total number of addresses is under some pre-defined limit.
@@ -260,12 +358,24 @@ type FindBalanceOutput struct {
 }
 
 // RandomNumberInput is used to generate a random
-// number in the range [minimum, maximum).
+// number in the range [minimum, maximum].
 type RandomNumberInput struct {
 	Minimum string `json:"minimum"`
 	Maximum string `json:"maximum"`
 }
 
+// AssertEqualInput is the input to AssertEqual.
+type AssertEqualInput struct {
+	LeftValue  json.RawMessage `json:"left_value"`
+	RightValue json.RawMessage `json:"right_value"`
+}
+
+// AssertInInput is the input to AssertIn.
+type AssertInInput struct {
+	Value json.RawMessage   `json:"value"`
+	Array []json.RawMessage `json:"array"`
+}
+
 // FindCurrencyAmountInput is the input
 // to FindCurrencyAmount.
 type FindCurrencyAmountInput struct {
@@ -290,11 +400,27 @@ type HTTPRequestInput struct {
 	URL     string `json:"url"`
 	Timeout int    `json:"timeout"`
 
+	// Headers are set on the request after the
+	// default Accept and Content-Type headers, so
+	// they can be used to override them.
+	Headers map[string]string `json:"headers,omitempty"`
+
 	// If the Method is POST, the Body
 	// can be populated with JSON.
 	Body string `json:"body"`
 }
 
+// HTTPRequestOutput is returned by
+// HTTP Request.
+type HTTPRequestOutput struct {
+	StatusCode int `json:"status_code"`
+
+	// Body is the response body. If the body
+	// is not valid JSON, it is stored as a
+	// JSON string.
+	Body json.RawMessage `json:"body"`
+}
+
 // SetBlobInput is the input to
 // SetBlob.
 type SetBlobInput struct {
@@ -361,6 +487,18 @@ type Workflow struct {
 	// that take days to play out.
 	Concurrency int         `json:"concurrency"`
 	Scenarios   []*Scenario `json:"scenarios"`
+
+	// Timeout is the number of seconds a Job executing
+	// this workflow may take to complete before it is
+	// marked as failed. If Timeout is 0, the default
+	// timeout of the coordinator is used.
+	Timeout int `json:"timeout,omitempty"`
+
+	// DryRun indicates that Jobs executing this workflow
+	// should construct and sign their first transaction but
+	// never broadcast it. This differs from the dry_run reserved
+	// variable, which stops after /construction/metadata.
+	DryRun bool `json:"dry_run,omitempty"`
 }
 
 // Status is status of a Job.
@@ -380,6 +518,11 @@ const (
 	// Completed means that all scenarios were
 	// completed successfully.
 	Completed Status = "completed"
+
+	// CompletedWithoutBroadcast means that a transaction
+	// was constructed and signed in dry-run mode but was
+	// not broadcast (so no further scenarios were run).
+	CompletedWithoutBroadcast Status = "completed_without_broadcast"
 )
 
 // Job is an instantion of a Workflow.
@@ -396,6 +539,17 @@ type Job struct {
 	// Workflow is the name of the workflow being executed.
 	Workflow string `json:"workflow"`
 
+	// CreatedAt is the time (in milliseconds since the Unix
+	// epoch) when a Job is stored in JobStorage for the
+	// first time.
+	CreatedAt int64 `json:"created_at,omitempty"`
+
+	// Deadline is the time (in milliseconds since the Unix
+	// epoch) after which a Job that is not complete is
+	// marked as failed. If Deadline is 0, the Job never
+	// times out.
+	Deadline int64 `json:"deadline,omitempty"`
+
 	// Scenarios are copied into each context in case
 	// a configuration file changes that could corrupt
 	// in-process flows.
@@ -412,3 +566,12 @@ type Broadcast struct {
 	ConfirmationDepth int64
 	DryRun            bool
 }
+
+// ParseResult is the result of calling /construction/parse
+// on a signed transaction constructed in dry-run mode.
+type ParseResult struct {
+	TransactionIdentifier *types.TransactionIdentifier `json:"transaction_identifier"`
+	Operations            []*types.Operation           `json:"operations"`
+	Signers               []*types.AccountIdentifier   `json:"signers"`
+	Metadata              map[string]interface{}       `json:"metadata,omitempty"`
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+// Option is used to overwrite default values in
+// Worker construction. Any Option not provided
+// falls back to the default value.
+type Option func(w *Worker)
+
+// WithAllowedHosts allows the HTTPRequest action to
+// make requests to the provided hosts (including any
+// redirects). Each host may optionally include a port
+// (ex: "localhost:8080"), in which case only that port
+// is allowed. If neither WithAllowedHosts nor
+// WithAllowAllHosts is provided, all requests are denied.
+func WithAllowedHosts(hosts ...string) Option {
+	return func(w *Worker) {
+		w.allowedHosts = hosts
+	}
+}
+
+// WithAllowAllHosts allows the HTTPRequest action
+// to make requests to any host. This should only be
+// used if all workflows are trusted, as any header
+// (including credentials) can be sent to any host.
+func WithAllowAllHosts() Option {
+	return func(w *Worker) {
+		w.allowAllHosts = true
+	}
+}
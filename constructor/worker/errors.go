@@ -57,6 +57,17 @@ var (
 	// are no pending broadcasts, this usually means that we need
 	// to request funds.
 	ErrUnsatisfiable = errors.New("unsatisfiable balance")
+
+	// ErrHostNotAllowed is returned when an HTTPRequest
+	// is made (or redirected) to a host that is not in the
+	// allowlist provided to the Worker.
+	ErrHostNotAllowed = errors.New("host not allowed")
+
+	// ErrAssertionFailed is returned when an AssertEqual,
+	// AssertNotEmpty, or AssertIn action is not satisfied.
+	// Unlike other errors, this causes the Job to be marked
+	// as failed instead of halting execution.
+	ErrAssertionFailed = errors.New("assertion failed")
 )
 
 // Error is returned by worker execution.
@@ -22,6 +22,13 @@ import (
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
+const (
+	// maxHTTPRedirects is the maximum number of
+	// redirects followed by the HTTPRequest action
+	// (the same limit as the default http.Client).
+	maxHTTPRedirects = 10
+)
+
 // Helper is used by the worker to process Jobs.
 type Helper interface {
 	// StoreKey is called to persist a
@@ -92,4 +99,7 @@ type Helper interface {
 // Worker processes jobs.
 type Worker struct {
 	helper Helper
+
+	allowedHosts  []string
+	allowAllHosts bool
 }
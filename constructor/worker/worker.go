@@ -17,13 +17,18 @@ package worker
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math/big"
 	"net/http"
 	"net/url"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/lucasjones/reggen"
@@ -38,8 +43,13 @@ import (
 )
 
 // New returns a new *Worker.
-func New(helper Helper) *Worker {
-	return &Worker{helper: helper}
+func New(helper Helper, options ...Option) *Worker {
+	w := &Worker{helper: helper}
+	for _, opt := range options {
+		opt(w)
+	}
+
+	return w
 }
 
 func marshalString(value string) string {
@@ -59,6 +69,8 @@ func (w *Worker) invokeWorker(
 		return GenerateKeyWorker(input)
 	case job.Derive:
 		return w.DeriveWorker(ctx, input)
+	case job.GenerateAccounts:
+		return w.GenerateAccountsWorker(ctx, dbTx, input)
 	case job.SaveAccount:
 		return "", w.SaveAccountWorker(ctx, dbTx, input)
 	case job.PrintMessage:
@@ -74,12 +86,18 @@ func (w *Worker) invokeWorker(
 		return RandomNumberWorker(input)
 	case job.Assert:
 		return "", AssertWorker(input)
+	case job.AssertEqual:
+		return "", AssertEqualWorker(input)
+	case job.AssertNotEmpty:
+		return "", AssertNotEmptyWorker(input)
+	case job.AssertIn:
+		return "", AssertInWorker(input)
 	case job.FindCurrencyAmount:
 		return FindCurrencyAmountWorker(input)
 	case job.LoadEnv:
 		return LoadEnvWorker(input)
 	case job.HTTPRequest:
-		return HTTPRequestWorker(input)
+		return w.HTTPRequestWorker(ctx, input)
 	case job.SetBlob:
 		return "", w.SetBlobWorker(ctx, dbTx, input)
 	case job.GetBlob:
@@ -244,6 +262,90 @@ func GenerateKeyWorker(rawInput string) (string, error) {
 	return types.PrintStruct(kp), nil
 }
 
+// GenerateAccountsWorker generates Count keys, derives an
+// account for each using /construction/derive, and saves
+// each account and associated KeyPair in KeyStorage. It returns
+// the []*types.AccountIdentifier of the generated accounts.
+//
+// All accounts are derived before any are stored, so a failed
+// derivation never persists any keys. If storing a key fails,
+// the error includes the accounts stored in dbTx before the
+// failure (these are discarded if dbTx is not committed).
+func (w *Worker) GenerateAccountsWorker(
+	ctx context.Context,
+	dbTx database.Transaction,
+	rawInput string,
+) (string, error) {
+	var input job.GenerateAccountsInput
+	err := job.UnmarshalInput([]byte(rawInput), &input)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	if input.Count <= 0 {
+		return "", fmt.Errorf("%w: count %d must be positive", ErrInvalidInput, input.Count)
+	}
+
+	keyPairs := make([]*keys.KeyPair, input.Count)
+	accounts := make([]*types.AccountIdentifier, input.Count)
+	for i := 0; i < input.Count; i++ {
+		kp, err := keys.GenerateKeypair(input.CurveType)
+		if err != nil {
+			return "", fmt.Errorf(
+				"%w: unable to generate key %d of %d (no keys stored): %s",
+				ErrActionFailed,
+				i+1,
+				input.Count,
+				err.Error(),
+			)
+		}
+
+		accountIdentifier, _, err := w.helper.Derive(
+			ctx,
+			input.NetworkIdentifier,
+			kp.PublicKey,
+			input.Metadata,
+		)
+		if err != nil {
+			return "", fmt.Errorf(
+				"%w: unable to derive account %d of %d (no keys stored): %s",
+				ErrActionFailed,
+				i+1,
+				input.Count,
+				err.Error(),
+			)
+		}
+
+		if err := asserter.AccountIdentifier(accountIdentifier); err != nil {
+			return "", fmt.Errorf(
+				"%w: derived account %d of %d is invalid (no keys stored): %s",
+				ErrActionFailed,
+				i+1,
+				input.Count,
+				err.Error(),
+			)
+		}
+
+		keyPairs[i] = kp
+		accounts[i] = accountIdentifier
+	}
+
+	for i, account := range accounts {
+		if err := w.helper.StoreKey(ctx, dbTx, account, keyPairs[i]); err != nil {
+			return "", fmt.Errorf(
+				"%w: unable to store key %d of %d (stored %s): %s",
+				ErrActionFailed,
+				i+1,
+				input.Count,
+				types.PrintStruct(accounts[:i]),
+				err.Error(),
+			)
+		}
+	}
+
+	return types.PrintStruct(accounts), nil
+}
+
 // SaveAccountWorker saves a *types.AccountIdentifier and associated KeyPair
 // in KeyStorage.
 func (w *Worker) SaveAccountWorker(
@@ -304,6 +406,33 @@ func MathWorker(rawInput string) (string, error) {
 		result, err = types.AddValues(input.LeftValue, input.RightValue)
 	case job.Subtraction:
 		result, err = types.SubtractValues(input.LeftValue, input.RightValue)
+	case job.Multiplication:
+		result, err = types.MultiplyValues(input.LeftValue, input.RightValue)
+	case job.Division:
+		var roundUp bool
+		switch input.Rounding {
+		case job.RoundFloor:
+		case job.RoundCeiling:
+			roundUp = true
+		default:
+			return "", fmt.Errorf(
+				"%w: %s is not a supported rounding mode",
+				ErrInvalidInput,
+				input.Rounding,
+			)
+		}
+
+		result, err = types.DivideValues(input.LeftValue, input.RightValue, roundUp)
+	case job.Modulo:
+		if cmp, cmpErr := types.CompareValues(input.RightValue, "0"); cmpErr == nil && cmp == 0 {
+			return "", fmt.Errorf("%w: cannot take modulo by zero", ErrInvalidInput)
+		}
+
+		result, err = types.ModuloValues(input.LeftValue, input.RightValue)
+	case job.Comparison:
+		var cmp int
+		cmp, err = types.CompareValues(input.LeftValue, input.RightValue)
+		result = strconv.Itoa(cmp)
 	default:
 		return "", fmt.Errorf("%s is not a supported math operation", input.Operation)
 	}
@@ -315,7 +444,7 @@ func MathWorker(rawInput string) (string, error) {
 }
 
 // RandomNumberWorker generates a random number in the range
-// [minimum,maximum).
+// [minimum,maximum].
 func RandomNumberWorker(rawInput string) (string, error) {
 	var input job.RandomNumberInput
 	err := job.UnmarshalInput([]byte(rawInput), &input)
@@ -333,7 +462,18 @@ func RandomNumberWorker(rawInput string) (string, error) {
 		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 	}
 
-	randNum, err := utils.RandomNumber(min, max)
+	if max.Cmp(min) < 0 {
+		return "", fmt.Errorf(
+			"%w: maximum value %s < minimum value %s",
+			ErrActionFailed,
+			max.String(),
+			min.String(),
+		)
+	}
+
+	// utils.RandomNumber excludes the maximum, so we
+	// extend the range by 1 to make it inclusive.
+	randNum, err := utils.RandomNumber(min, new(big.Int).Add(max, big.NewInt(1)))
 	if err != nil {
 		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 	}
@@ -395,9 +535,99 @@ func balanceMessage(input *job.FindBalanceInput) string {
 		)
 	}
 
+	if input.RequireCoins > 0 {
+		message = fmt.Sprintf(
+			"%s with at least %d coins",
+			message,
+			input.RequireCoins,
+		)
+	}
+
+	if len(input.MinimumCoinValue) > 0 {
+		message = fmt.Sprintf(
+			"%s with minimum coin value %s",
+			message,
+			input.MinimumCoinValue,
+		)
+	}
+
 	return message
 }
 
+// coinShapeRequired returns a boolean indicating if the
+// FindBalanceInput constrains the coins held by an account.
+func coinShapeRequired(input *job.FindBalanceInput) bool {
+	return input.RequireCoins > 0 || len(input.MinimumCoinValue) > 0
+}
+
+// coinValueSufficient returns a boolean indicating if a coin
+// value is >= minimum. An empty minimum is always satisfied.
+func coinValueSufficient(value string, minimum string) (bool, error) {
+	if len(minimum) == 0 {
+		return true, nil
+	}
+
+	diff, err := types.SubtractValues(value, minimum)
+	if err != nil {
+		return false, err
+	}
+
+	bigIntDiff, err := types.BigInt(diff)
+	if err != nil {
+		return false, err
+	}
+
+	return bigIntDiff.Sign() >= 0, nil
+}
+
+// checkCoinShape returns a boolean indicating if an account
+// holds enough coins (excluding NotCoins) of at least
+// MinimumCoinValue to satisfy the FindBalanceInput.
+func (w *Worker) checkCoinShape(
+	ctx context.Context,
+	dbTx database.Transaction,
+	input *job.FindBalanceInput,
+	account *types.AccountIdentifier,
+) (bool, error) {
+	coins, err := w.helper.Coins(ctx, dbTx, account, input.MinimumBalance.Currency)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+	}
+
+	disallowedCoins := []string{}
+	for _, coinIdentifier := range input.NotCoins {
+		disallowedCoins = append(disallowedCoins, types.Hash(coinIdentifier))
+	}
+
+	requiredCoins := input.RequireCoins
+	if requiredCoins <= 0 {
+		requiredCoins = 1
+	}
+
+	eligibleCoins := 0
+	for _, coin := range coins {
+		if utils.ContainsString(disallowedCoins, types.Hash(coin.CoinIdentifier)) {
+			continue
+		}
+
+		sufficient, err := coinValueSufficient(coin.Amount.Value, input.MinimumCoinValue)
+		if err != nil {
+			return false, fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+		}
+
+		if !sufficient {
+			continue
+		}
+
+		eligibleCoins++
+		if eligibleCoins >= requiredCoins {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (w *Worker) checkAccountCoins(
 	ctx context.Context,
 	dbTx database.Transaction,
@@ -433,6 +663,15 @@ func (w *Worker) checkAccountCoins(
 			continue
 		}
 
+		sufficient, err := coinValueSufficient(coin.Amount.Value, input.MinimumCoinValue)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+		}
+
+		if !sufficient {
+			continue
+		}
+
 		return types.PrintStruct(&job.FindBalanceOutput{
 			AccountIdentifier: account,
 			Balance:           coin.Amount,
@@ -574,6 +813,21 @@ func findBalanceWorkerInputValidation(input *job.FindBalanceInput) error {
 		}
 	}
 
+	if input.RequireCoins < 0 {
+		return errors.New("require coins cannot be negative")
+	}
+
+	if len(input.MinimumCoinValue) > 0 {
+		minimumCoinValue, err := types.BigInt(input.MinimumCoinValue)
+		if err != nil {
+			return fmt.Errorf("%w: minimum coin value invalid", err)
+		}
+
+		if minimumCoinValue.Sign() < 0 {
+			return errors.New("minimum coin value cannot be negative")
+		}
+	}
+
 	return nil
 }
 
@@ -652,6 +906,20 @@ func (w *Worker) FindBalanceWorker(
 			continue
 		}
 
+		// If we require a particular coin distribution, we skip
+		// accounts that do not hold enough sufficiently large coins
+		// (regardless of their total balance).
+		if coinShapeRequired(&input) {
+			satisfied, err := w.checkCoinShape(ctx, dbTx, &input, account)
+			if err != nil {
+				return "", err
+			}
+
+			if !satisfied {
+				continue
+			}
+		}
+
 		var output string
 		var err error
 		if input.RequireCoin {
@@ -709,6 +977,138 @@ func AssertWorker(rawInput string) error {
 	return nil
 }
 
+// integerValue returns the *big.Int represented by a JSON
+// number or string. If the value is not an integer, it
+// returns false.
+func integerValue(raw json.RawMessage) (*big.Int, bool) {
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, false
+	}
+
+	var str string
+	switch v := value.(type) {
+	case json.Number:
+		str = v.String()
+	case string:
+		str = v
+	default:
+		return nil, false
+	}
+
+	parsed, err := types.BigInt(str)
+	if err != nil {
+		return nil, false
+	}
+
+	return parsed, true
+}
+
+// valuesEqual compares two JSON values. If both values
+// are integers, they are compared numerically. Otherwise,
+// they are compared structurally.
+func valuesEqual(a json.RawMessage, b json.RawMessage) (bool, error) {
+	aInt, aOk := integerValue(a)
+	bInt, bOk := integerValue(b)
+	if aOk && bOk {
+		return aInt.Cmp(bInt) == 0, nil
+	}
+
+	var aVal, bVal interface{}
+	if err := json.Unmarshal(a, &aVal); err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(b, &bVal); err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(aVal, bVal), nil
+}
+
+// AssertEqualWorker checks if two inputs are equal.
+func AssertEqualWorker(rawInput string) error {
+	var input job.AssertEqualInput
+	err := job.UnmarshalInput([]byte(rawInput), &input)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	equal, err := valuesEqual(input.LeftValue, input.RightValue)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	if !equal {
+		return fmt.Errorf(
+			"%w: left value %s != right value %s",
+			ErrAssertionFailed,
+			input.LeftValue,
+			input.RightValue,
+		)
+	}
+
+	return nil
+}
+
+// AssertNotEmptyWorker checks if an input is not null,
+// an empty string, an empty array, or an empty object.
+func AssertNotEmptyWorker(rawInput string) error {
+	var input interface{}
+	err := job.UnmarshalInput([]byte(rawInput), &input)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	var empty bool
+	switch v := input.(type) {
+	case nil:
+		empty = true
+	case string:
+		empty = len(v) == 0
+	case []interface{}:
+		empty = len(v) == 0
+	case map[string]interface{}:
+		empty = len(v) == 0
+	}
+
+	if empty {
+		return fmt.Errorf("%w: value %s is empty", ErrAssertionFailed, rawInput)
+	}
+
+	return nil
+}
+
+// AssertInWorker checks if an input is equal to
+// some element of an array.
+func AssertInWorker(rawInput string) error {
+	var input job.AssertInInput
+	err := job.UnmarshalInput([]byte(rawInput), &input)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	for _, element := range input.Array {
+		equal, err := valuesEqual(input.Value, element)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+		}
+
+		if equal {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"%w: value %s not in array %s",
+		ErrAssertionFailed,
+		input.Value,
+		types.PrintStruct(input.Array),
+	)
+}
+
 // FindCurrencyAmountWorker finds a *types.Amount with a specific
 // *types.Currency in a []*types.Amount.
 func FindCurrencyAmountWorker(rawInput string) (string, error) {
@@ -755,64 +1155,151 @@ func LoadEnvWorker(rawInput string) (string, error) {
 	return os.Getenv(input), nil
 }
 
-// HTTPRequestWorker makes an HTTP request and returns the response to
-// store in a variable. This is useful for algorithmic fauceting.
+// hostAllowed returns a boolean indicating if
+// an HTTPRequest can be made to a *url.URL.
+func (w *Worker) hostAllowed(u *url.URL) bool {
+	if w.allowAllHosts {
+		return true
+	}
+
+	for _, host := range w.allowedHosts {
+		if strings.EqualFold(host, u.Host) || strings.EqualFold(host, u.Hostname()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HTTPRequestWorker makes an HTTP request (to any host) and returns
+// the response to store in a variable. This is useful for
+// algorithmic fauceting.
+//
+// Deprecated: Use (*Worker).HTTPRequestWorker, which restricts
+// requests to allowed hosts and returns the status code.
 func HTTPRequestWorker(rawInput string) (string, error) {
+	w := New(nil, WithAllowAllHosts())
+	_, body, err := w.httpRequest(context.Background(), rawInput)
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
+
+// HTTPRequestWorker makes an HTTP request and returns the status
+// code and response to store in a variable. This is useful for
+// algorithmic fauceting. Requests (and redirects) are only made
+// to hosts allowed by WithAllowedHosts or WithAllowAllHosts.
+func (w *Worker) HTTPRequestWorker(ctx context.Context, rawInput string) (string, error) {
+	statusCode, body, err := w.httpRequest(ctx, rawInput)
+	if err != nil {
+		return "", err
+	}
+
+	// We store the body as a JSON string if it
+	// cannot be parsed as JSON.
+	if !json.Valid(body) {
+		body, err = json.Marshal(string(body))
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+		}
+	}
+
+	return types.PrintStruct(&job.HTTPRequestOutput{
+		StatusCode: statusCode,
+		Body:       body,
+	}), nil
+}
+
+// httpRequest makes an HTTP request and returns
+// the status code and body of a successful response.
+func (w *Worker) httpRequest(ctx context.Context, rawInput string) (int, []byte, error) {
 	var input job.HTTPRequestInput
 	err := job.UnmarshalInput([]byte(rawInput), &input)
 	if err != nil {
-		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+		return -1, nil, fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
 	}
 
 	if input.Timeout <= 0 {
-		return "", fmt.Errorf("%w: %d is not a valid timeout", ErrInvalidInput, input.Timeout)
+		return -1, nil, fmt.Errorf("%w: %d is not a valid timeout", ErrInvalidInput, input.Timeout)
 	}
 
-	if _, err := url.ParseRequestURI(input.URL); err != nil {
-		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	parsedURL, err := url.ParseRequestURI(input.URL)
+	if err != nil {
+		return -1, nil, fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	if !w.hostAllowed(parsedURL) {
+		return -1, nil, fmt.Errorf("%w: %s", ErrHostNotAllowed, parsedURL.Host)
 	}
 
-	client := &http.Client{Timeout: time.Duration(input.Timeout) * time.Second}
+	client := &http.Client{
+		Timeout: time.Duration(input.Timeout) * time.Second,
+
+		// The default client follows redirects to any
+		// host (forwarding any custom headers), so we
+		// must check each redirect against the allowlist.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !w.hostAllowed(req.URL) {
+				return fmt.Errorf("%w: %s", ErrHostNotAllowed, req.URL.Host)
+			}
+
+			if len(via) >= maxHTTPRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxHTTPRedirects)
+			}
+
+			return nil
+		},
+	}
 	var request *http.Request
 	switch input.Method {
 	case job.MethodGet:
-		request, err = http.NewRequest(http.MethodGet, input.URL, nil)
+		request, err = http.NewRequestWithContext(ctx, http.MethodGet, input.URL, nil)
 		if err != nil {
-			return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+			return -1, nil, fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 		}
 		request.Header.Set("Accept", "application/json")
 	case job.MethodPost:
-		request, err = http.NewRequest(
+		request, err = http.NewRequestWithContext(
+			ctx,
 			http.MethodPost,
 			input.URL,
 			bytes.NewBufferString(input.Body),
 		)
 		if err != nil {
-			return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+			return -1, nil, fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 		}
 		request.Header.Set("Content-Type", "application/json")
 		request.Header.Set("Accept", "application/json")
 	default:
-		return "", fmt.Errorf(
+		return -1, nil, fmt.Errorf(
 			"%w: %s is not a supported HTTP method",
 			ErrInvalidInput,
 			input.Method,
 		)
 	}
 
+	for key, value := range input.Headers {
+		request.Header.Set(key, value)
+	}
+
 	resp, err := client.Do(request)
+	if errors.Is(err, ErrHostNotAllowed) {
+		return -1, nil, fmt.Errorf("%w: redirect %s", ErrHostNotAllowed, err.Error())
+	}
 	if err != nil {
-		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+		return -1, nil, fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+		return -1, nil, fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf(
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return -1, nil, fmt.Errorf(
 			"%w: status code %d with body %s",
 			ErrActionFailed,
 			resp.StatusCode,
@@ -820,7 +1307,7 @@ func HTTPRequestWorker(rawInput string) (string, error) {
 		)
 	}
 
-	return string(body), nil
+	return resp.StatusCode, body, nil
 }
 
 // SetBlobWorker transactionally saves a key and value for use
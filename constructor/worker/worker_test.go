@@ -16,12 +16,14 @@ package worker
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"regexp"
 	"testing"
@@ -32,6 +34,7 @@ import (
 	"github.com/tidwall/gjson"
 
 	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	"github.com/coinbase/rosetta-sdk-go/keys"
 	mocks "github.com/coinbase/rosetta-sdk-go/mocks/constructor/worker"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -134,6 +137,20 @@ func TestBalanceMessage(t *testing.T) {
 			},
 			message: `looking for balance {"value":"100","currency":{"symbol":"BTC","decimals":8}} on account {"address":"hello"} != to coins [{"identifier":"coin1"}]`, // nolint
 		},
+		"message with required coins": {
+			input: &job.FindBalanceInput{
+				RequireCoins:     2,
+				MinimumCoinValue: "50",
+				MinimumBalance: &types.Amount{
+					Value: "100",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+			},
+			message: `looking for balance {"value":"100","currency":{"symbol":"BTC","decimals":8}} with at least 2 coins with minimum coin value 50`, // nolint
+		},
 	}
 
 	for name, test := range tests {
@@ -903,91 +920,739 @@ func TestFindBalanceWorker(t *testing.T) {
 			}(),
 			err: ErrUnsatisfiable,
 		},
-		"invalid amount": {
+		"find balance with required coins (wrong coin distribution)": {
 			input: &job.FindBalanceInput{
 				MinimumBalance: &types.Amount{
-					Value: "",
+					Value: "100",
 					Currency: &types.Currency{
 						Symbol:   "BTC",
 						Decimals: 8,
 					},
 				},
-				NotAddress:  []string{"addr4"},
-				RequireCoin: true,
-				NotCoins: []*types.CoinIdentifier{
+				RequireCoins:     2,
+				MinimumCoinValue: "50",
+				CreateLimit:      -1,
+			},
+			mockHelper: func() *mocks.Helper {
+				helper := &mocks.Helper{}
+				helper.On(
+					"AllAccounts",
+					ctx,
+					mock.Anything,
+				).Return(
+					[]*types.AccountIdentifier{
+						{Address: "addr2"},
+						{Address: "addr1"},
+						{Address: "addr3"},
+					},
+					nil,
+				).Once()
+				helper.On(
+					"LockedAccounts",
+					ctx,
+					mock.Anything,
+				).Return(
+					[]*types.AccountIdentifier{
+						{Address: "addr2"},
+					},
+					nil,
+				).Once()
+				helper.On("Coins", ctx, mock.Anything, &types.AccountIdentifier{
+					Address:    "addr1",
+					SubAccount: (*types.SubAccountIdentifier)(nil),
+				}, &types.Currency{
+					Symbol:   "BTC",
+					Decimals: 8,
+				}).Return([]*types.Coin{
 					{
-						Identifier: "coin1",
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin1",
+						},
+						Amount: &types.Amount{
+							Value: "19990",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+					{
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin2",
+						},
+						Amount: &types.Amount{
+							Value: "10",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+				}, nil).Once()
+				helper.On("Coins", ctx, mock.Anything, &types.AccountIdentifier{
+					Address:    "addr3",
+					SubAccount: (*types.SubAccountIdentifier)(nil),
+				}, &types.Currency{
+					Symbol:   "BTC",
+					Decimals: 8,
+				}).Return([]*types.Coin{
+					{
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin3",
+						},
+						Amount: &types.Amount{
+							Value: "60",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+					{
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin4",
+						},
+						Amount: &types.Amount{
+							Value: "60",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+				}, nil).Once()
+				helper.On("Balance", ctx, mock.Anything, &types.AccountIdentifier{
+					Address:    "addr3",
+					SubAccount: (*types.SubAccountIdentifier)(nil),
+				}, &types.Currency{
+					Symbol:   "BTC",
+					Decimals: 8,
+				}).Return(&types.Amount{
+					Value: "120",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				}, nil).Once()
+
+				return helper
+			}(),
+			output: &job.FindBalanceOutput{
+				AccountIdentifier: &types.AccountIdentifier{
+					Address: "addr3",
+				},
+				Balance: &types.Amount{
+					Value: "120",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
 					},
 				},
-				CreateLimit: 2,
 			},
-			mockHelper: &mocks.Helper{},
-			err:        ErrInvalidInput,
 		},
-		"invalid currency": {
+		"could not find required coins (unsatisfiable)": {
 			input: &job.FindBalanceInput{
 				MinimumBalance: &types.Amount{
 					Value: "100",
 					Currency: &types.Currency{
-						Symbol:   "",
+						Symbol:   "BTC",
 						Decimals: 8,
 					},
 				},
-				NotAddress:  []string{"addr4"},
-				RequireCoin: true,
+				RequireCoins: 3,
 				NotCoins: []*types.CoinIdentifier{
 					{
-						Identifier: "coin1",
+						Identifier: "coin3",
 					},
 				},
-				CreateLimit: 2,
+				CreateLimit: 100,
 			},
-			mockHelper: &mocks.Helper{},
-			err:        ErrInvalidInput,
+			mockHelper: func() *mocks.Helper {
+				helper := &mocks.Helper{}
+				helper.On(
+					"AllAccounts",
+					ctx,
+					mock.Anything,
+				).Return(
+					[]*types.AccountIdentifier{
+						{Address: "addr2"},
+						{Address: "addr1"},
+						{Address: "addr3"},
+					},
+					nil,
+				).Once()
+				helper.On(
+					"LockedAccounts",
+					ctx,
+					mock.Anything,
+				).Return(
+					[]*types.AccountIdentifier{
+						{Address: "addr2"},
+					},
+					nil,
+				).Once()
+				helper.On("Coins", ctx, mock.Anything, &types.AccountIdentifier{
+					Address:    "addr1",
+					SubAccount: (*types.SubAccountIdentifier)(nil),
+				}, &types.Currency{
+					Symbol:   "BTC",
+					Decimals: 8,
+				}).Return([]*types.Coin{
+					{
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin1",
+						},
+						Amount: &types.Amount{
+							Value: "20000",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+				}, nil).Once()
+				helper.On("Coins", ctx, mock.Anything, &types.AccountIdentifier{
+					Address:    "addr3",
+					SubAccount: (*types.SubAccountIdentifier)(nil),
+				}, &types.Currency{
+					Symbol:   "BTC",
+					Decimals: 8,
+				}).Return([]*types.Coin{
+					{
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin2",
+						},
+						Amount: &types.Amount{
+							Value: "40",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+					{
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin3",
+						},
+						Amount: &types.Amount{
+							Value: "40",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+					{
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin4",
+						},
+						Amount: &types.Amount{
+							Value: "40",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+				}, nil).Once()
+
+				return helper
+			}(),
+			err: ErrUnsatisfiable,
+		},
+		"could not find required coins (create)": {
+			input: &job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value: "0",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+				MinimumCoinValue: "1",
+				CreateLimit:      100,
+			},
+			mockHelper: func() *mocks.Helper {
+				helper := &mocks.Helper{}
+				helper.On(
+					"AllAccounts",
+					ctx,
+					mock.Anything,
+				).Return(
+					[]*types.AccountIdentifier{
+						{Address: "addr2"},
+						{Address: "addr1"},
+						{Address: "addr3"},
+					},
+					nil,
+				).Once()
+				helper.On(
+					"LockedAccounts",
+					ctx,
+					mock.Anything,
+				).Return(
+					[]*types.AccountIdentifier{
+						{Address: "addr2"},
+					},
+					nil,
+				).Once()
+				helper.On("Coins", ctx, mock.Anything, &types.AccountIdentifier{
+					Address:    "addr1",
+					SubAccount: (*types.SubAccountIdentifier)(nil),
+				}, &types.Currency{
+					Symbol:   "BTC",
+					Decimals: 8,
+				}).Return([]*types.Coin{
+					{
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin1",
+						},
+						Amount: &types.Amount{
+							Value: "0",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+				}, nil).Once()
+				helper.On("Coins", ctx, mock.Anything, &types.AccountIdentifier{
+					Address:    "addr3",
+					SubAccount: (*types.SubAccountIdentifier)(nil),
+				}, &types.Currency{
+					Symbol:   "BTC",
+					Decimals: 8,
+				}).Return([]*types.Coin{}, nil).Once()
+
+				return helper
+			}(),
+			err: ErrCreateAccount,
+		},
+		"find coin with minimum coin value": {
+			input: &job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value: "100",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+				RequireCoin:      true,
+				MinimumCoinValue: "500",
+			},
+			mockHelper: func() *mocks.Helper {
+				helper := &mocks.Helper{}
+				helper.On(
+					"AllAccounts",
+					ctx,
+					mock.Anything,
+				).Return(
+					[]*types.AccountIdentifier{
+						{Address: "addr2"},
+						{Address: "addr1"},
+						{Address: "addr3"},
+					},
+					nil,
+				).Once()
+				helper.On(
+					"LockedAccounts",
+					ctx,
+					mock.Anything,
+				).Return(
+					[]*types.AccountIdentifier{
+						{Address: "addr2"},
+					},
+					nil,
+				).Once()
+				helper.On("Coins", ctx, mock.Anything, &types.AccountIdentifier{
+					Address:    "addr1",
+					SubAccount: (*types.SubAccountIdentifier)(nil),
+				}, &types.Currency{
+					Symbol:   "BTC",
+					Decimals: 8,
+				}).Return([]*types.Coin{
+					{
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin1",
+						},
+						Amount: &types.Amount{
+							Value: "100",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+					{
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: "coin2",
+						},
+						Amount: &types.Amount{
+							Value: "600",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+				}, nil).Twice()
+
+				return helper
+			}(),
+			output: &job.FindBalanceOutput{
+				AccountIdentifier: &types.AccountIdentifier{
+					Address: "addr1",
+				},
+				Balance: &types.Amount{
+					Value: "600",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+				Coin: &types.CoinIdentifier{
+					Identifier: "coin2",
+				},
+			},
+		},
+		"invalid require coins": {
+			input: &job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value: "100",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+				RequireCoins: -1,
+			},
+			mockHelper: &mocks.Helper{},
+			err:        ErrInvalidInput,
+		},
+		"invalid minimum coin value": {
+			input: &job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value: "100",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+				RequireCoins:     1,
+				MinimumCoinValue: "-1",
+			},
+			mockHelper: &mocks.Helper{},
+			err:        ErrInvalidInput,
+		},
+		"invalid amount": {
+			input: &job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value: "",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+				NotAddress:  []string{"addr4"},
+				RequireCoin: true,
+				NotCoins: []*types.CoinIdentifier{
+					{
+						Identifier: "coin1",
+					},
+				},
+				CreateLimit: 2,
+			},
+			mockHelper: &mocks.Helper{},
+			err:        ErrInvalidInput,
+		},
+		"invalid currency": {
+			input: &job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value: "100",
+					Currency: &types.Currency{
+						Symbol:   "",
+						Decimals: 8,
+					},
+				},
+				NotAddress:  []string{"addr4"},
+				RequireCoin: true,
+				NotCoins: []*types.CoinIdentifier{
+					{
+						Identifier: "coin1",
+					},
+				},
+				CreateLimit: 2,
+			},
+			mockHelper: &mocks.Helper{},
+			err:        ErrInvalidInput,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup DB
+			dir, err := utils.CreateTempDir()
+			assert.NoError(t, err)
+			defer utils.RemoveTempDir(dir)
+
+			db, err := database.NewBadgerDatabase(
+				ctx,
+				dir,
+				database.WithIndexCacheSize(database.TinyIndexCacheSize),
+			)
+			assert.NoError(t, err)
+			assert.NotNil(t, db)
+			defer db.Close(ctx)
+
+			dbTx := db.Transaction(ctx)
+			defer dbTx.Discard(ctx)
+
+			worker := New(test.mockHelper)
+			output, err := worker.FindBalanceWorker(ctx, dbTx, types.PrintStruct(test.input))
+			if test.err != nil {
+				assert.Equal(t, "", output)
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, types.PrintStruct(test.output), output)
+			}
+
+			test.mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func assertVariableEquality(t *testing.T, state string, variable string, expected interface{}) {
+	var saved interface{}
+	err := json.Unmarshal([]byte(gjson.Get(state, variable).Raw), &saved)
+	assert.NoError(t, err)
+	assert.Equal(t, types.Hash(expected), types.Hash(saved))
+}
+
+func TestGenerateAccountsWorker(t *testing.T) {
+	ctx := context.Background()
+	network := &types.NetworkIdentifier{
+		Blockchain: "Bitcoin",
+		Network:    "Testnet3",
+	}
+
+	// deriveAccount returns a unique *types.AccountIdentifier
+	// for each *types.PublicKey.
+	deriveAccount := func(
+		ctx context.Context,
+		network *types.NetworkIdentifier,
+		publicKey *types.PublicKey,
+		metadata map[string]interface{},
+	) *types.AccountIdentifier {
+		return &types.AccountIdentifier{Address: hex.EncodeToString(publicKey.Bytes)}
+	}
+
+	// keyMatchesAccount ensures each *keys.KeyPair is stored
+	// with the account derived from it.
+	keyMatchesAccount := func(
+		ctx context.Context,
+		dbTx database.Transaction,
+		account *types.AccountIdentifier,
+		kp *keys.KeyPair,
+	) error {
+		if account.Address != hex.EncodeToString(kp.PublicKey.Bytes) {
+			return errors.New("key stored with incorrect account")
+		}
+
+		return nil
+	}
+
+	tests := map[string]struct {
+		input  *job.GenerateAccountsInput
+		helper *mocks.Helper
+
+		stored int
+		err    error
+	}{
+		"count=1": {
+			input: &job.GenerateAccountsInput{
+				NetworkIdentifier: network,
+				CurveType:         types.Secp256k1,
+				Count:             1,
+			},
+			helper: func() *mocks.Helper {
+				h := &mocks.Helper{}
+				h.On(
+					"Derive",
+					ctx,
+					network,
+					mock.Anything,
+					map[string]interface{}(nil),
+				).Return(deriveAccount, nil, nil).Once()
+				h.On(
+					"StoreKey",
+					ctx,
+					mock.Anything,
+					mock.Anything,
+					mock.Anything,
+				).Return(keyMatchesAccount).Once()
+
+				return h
+			}(),
+			stored: 1,
+		},
+		"count=25": {
+			input: &job.GenerateAccountsInput{
+				NetworkIdentifier: network,
+				CurveType:         types.Edwards25519,
+				Count:             25,
+				Metadata: map[string]interface{}{
+					"foo": "bar",
+				},
+			},
+			helper: func() *mocks.Helper {
+				h := &mocks.Helper{}
+				h.On(
+					"Derive",
+					ctx,
+					network,
+					mock.Anything,
+					map[string]interface{}{
+						"foo": "bar",
+					},
+				).Return(deriveAccount, nil, nil).Times(25)
+				h.On(
+					"StoreKey",
+					ctx,
+					mock.Anything,
+					mock.Anything,
+					mock.Anything,
+				).Return(keyMatchesAccount).Times(25)
+
+				return h
+			}(),
+			stored: 25,
+		},
+		"derive fails on 7th key": {
+			input: &job.GenerateAccountsInput{
+				NetworkIdentifier: network,
+				CurveType:         types.Secp256k1,
+				Count:             10,
+			},
+			helper: func() *mocks.Helper {
+				h := &mocks.Helper{}
+				h.On(
+					"Derive",
+					ctx,
+					network,
+					mock.Anything,
+					map[string]interface{}(nil),
+				).Return(deriveAccount, nil, nil).Times(6)
+				h.On(
+					"Derive",
+					ctx,
+					network,
+					mock.Anything,
+					map[string]interface{}(nil),
+				).Return(nil, nil, errors.New("derive failed")).Once()
+
+				return h
+			}(),
+			err: ErrActionFailed,
+		},
+		"store fails on 3rd key": {
+			input: &job.GenerateAccountsInput{
+				NetworkIdentifier: network,
+				CurveType:         types.Secp256k1,
+				Count:             5,
+			},
+			helper: func() *mocks.Helper {
+				h := &mocks.Helper{}
+				h.On(
+					"Derive",
+					ctx,
+					network,
+					mock.Anything,
+					map[string]interface{}(nil),
+				).Return(deriveAccount, nil, nil).Times(5)
+				h.On(
+					"StoreKey",
+					ctx,
+					mock.Anything,
+					mock.Anything,
+					mock.Anything,
+				).Return(keyMatchesAccount).Twice()
+				h.On(
+					"StoreKey",
+					ctx,
+					mock.Anything,
+					mock.Anything,
+					mock.Anything,
+				).Return(errors.New("store failed")).Once()
+
+				return h
+			}(),
+			stored: 2,
+			err:    ErrActionFailed,
+		},
+		"invalid count": {
+			input: &job.GenerateAccountsInput{
+				NetworkIdentifier: network,
+				CurveType:         types.Secp256k1,
+				Count:             0,
+			},
+			helper: &mocks.Helper{},
+			err:    ErrInvalidInput,
+		},
+		"invalid curve": {
+			input: &job.GenerateAccountsInput{
+				NetworkIdentifier: network,
+				CurveType:         "blah",
+				Count:             2,
+			},
+			helper: &mocks.Helper{},
+			err:    ErrActionFailed,
 		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			// Setup DB
-			dir, err := utils.CreateTempDir()
-			assert.NoError(t, err)
-			defer utils.RemoveTempDir(dir)
-
-			db, err := database.NewBadgerDatabase(
-				ctx,
-				dir,
-				database.WithIndexCacheSize(database.TinyIndexCacheSize),
-			)
-			assert.NoError(t, err)
-			assert.NotNil(t, db)
-			defer db.Close(ctx)
-
-			dbTx := db.Transaction(ctx)
-			defer dbTx.Discard(ctx)
-
-			worker := New(test.mockHelper)
-			output, err := worker.FindBalanceWorker(ctx, dbTx, types.PrintStruct(test.input))
+			worker := New(test.helper)
+			output, err := worker.GenerateAccountsWorker(ctx, nil, types.PrintStruct(test.input))
 			if test.err != nil {
 				assert.Equal(t, "", output)
 				assert.True(t, errors.Is(err, test.err))
+
+				// The error should report which accounts
+				// were stored before failing.
+				if test.stored > 0 {
+					assert.Contains(t, err.Error(), fmt.Sprintf("unable to store key %d", test.stored+1))
+				}
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, types.PrintStruct(test.output), output)
+
+				var accounts []*types.AccountIdentifier
+				assert.NoError(t, json.Unmarshal([]byte(output), &accounts))
+				assert.Len(t, accounts, test.stored)
+
+				addresses := map[string]struct{}{}
+				for _, account := range accounts {
+					addresses[account.Address] = struct{}{}
+				}
+				assert.Len(t, addresses, test.stored)
 			}
 
-			test.mockHelper.AssertExpectations(t)
+			if test.stored == 0 {
+				test.helper.AssertNotCalled(
+					t,
+					"StoreKey",
+					mock.Anything,
+					mock.Anything,
+					mock.Anything,
+					mock.Anything,
+				)
+			}
+
+			test.helper.AssertExpectations(t)
 		})
 	}
 }
 
-func assertVariableEquality(t *testing.T, state string, variable string, expected interface{}) {
-	var saved interface{}
-	err := json.Unmarshal([]byte(gjson.Get(state, variable).Raw), &saved)
-	assert.NoError(t, err)
-	assert.Equal(t, types.Hash(expected), types.Hash(saved))
-}
-
 func TestJob_ComplicatedTransfer(t *testing.T) {
 	ctx := context.Background()
 	s := &job.Scenario{
@@ -1443,6 +2108,35 @@ func TestJob_Failures(t *testing.T) {
 			},
 			helper: &mocks.Helper{},
 		},
+		"assertion failed: assert_equal": {
+			scenario: &job.Scenario{
+				Name: "check_balance",
+				Actions: []*job.Action{
+					{
+						Type:       job.SetVariable,
+						Input:      `"100"`,
+						OutputPath: "balance",
+					},
+					{
+						Type:  job.AssertEqual,
+						Input: `{"left_value":{{balance}}, "right_value":"200"}`,
+					},
+				},
+			},
+			executionErr: &Error{
+				Workflow:    "random",
+				Scenario:    "check_balance",
+				ActionIndex: 1,
+				Action: &job.Action{
+					Type:  job.AssertEqual,
+					Input: `{"left_value":{{balance}}, "right_value":"200"}`,
+				},
+				ProcessedInput: `{"left_value":"100", "right_value":"200"}`,
+				State:          `{"balance":"100"}`,
+				Err:            ErrAssertionFailed,
+			},
+			helper: &mocks.Helper{},
+		},
 		"invalid input: generate key": {
 			scenario: &job.Scenario{
 				Name: "create_address",
@@ -1683,15 +2377,374 @@ func TestJob_Failures(t *testing.T) {
 	}
 }
 
+func TestMathWorker(t *testing.T) {
+	var tests = map[string]struct {
+		input *job.MathInput
+
+		output string
+		err    error
+	}{
+		"addition": {
+			input: &job.MathInput{
+				Operation:  job.Addition,
+				LeftValue:  "-10",
+				RightValue: "3",
+			},
+			output: `"-7"`,
+		},
+		"subtraction": {
+			input: &job.MathInput{
+				Operation:  job.Subtraction,
+				LeftValue:  "-10",
+				RightValue: "3",
+			},
+			output: `"-13"`,
+		},
+		"multiplication": {
+			input: &job.MathInput{
+				Operation:  job.Multiplication,
+				LeftValue:  "1000000000000000000000",
+				RightValue: "3",
+			},
+			output: `"3000000000000000000000"`,
+		},
+		"multiplication (negative)": {
+			input: &job.MathInput{
+				Operation:  job.Multiplication,
+				LeftValue:  "-4",
+				RightValue: "3",
+			},
+			output: `"-12"`,
+		},
+		"division (floor)": {
+			input: &job.MathInput{
+				Operation:  job.Division,
+				LeftValue:  "10",
+				RightValue: "3",
+				Rounding:   job.RoundFloor,
+			},
+			output: `"3"`,
+		},
+		"division (ceiling)": {
+			input: &job.MathInput{
+				Operation:  job.Division,
+				LeftValue:  "10",
+				RightValue: "3",
+				Rounding:   job.RoundCeiling,
+			},
+			output: `"4"`,
+		},
+		"division (negative floor)": {
+			input: &job.MathInput{
+				Operation:  job.Division,
+				LeftValue:  "-10",
+				RightValue: "3",
+				Rounding:   job.RoundFloor,
+			},
+			output: `"-4"`,
+		},
+		"division (negative ceiling)": {
+			input: &job.MathInput{
+				Operation:  job.Division,
+				LeftValue:  "-10",
+				RightValue: "3",
+				Rounding:   job.RoundCeiling,
+			},
+			output: `"-3"`,
+		},
+		"division (missing rounding)": {
+			input: &job.MathInput{
+				Operation:  job.Division,
+				LeftValue:  "10",
+				RightValue: "3",
+			},
+			err: ErrInvalidInput,
+		},
+		"division by zero": {
+			input: &job.MathInput{
+				Operation:  job.Division,
+				LeftValue:  "10",
+				RightValue: "0",
+				Rounding:   job.RoundFloor,
+			},
+			err: ErrActionFailed,
+		},
+		"modulo": {
+			input: &job.MathInput{
+				Operation:  job.Modulo,
+				LeftValue:  "10",
+				RightValue: "3",
+			},
+			output: `"1"`,
+		},
+		"modulo (negative)": {
+			input: &job.MathInput{
+				Operation:  job.Modulo,
+				LeftValue:  "-10",
+				RightValue: "3",
+			},
+			output: `"2"`,
+		},
+		"modulo (negative divisor)": {
+			input: &job.MathInput{
+				Operation:  job.Modulo,
+				LeftValue:  "10",
+				RightValue: "-3",
+			},
+			output: `"1"`,
+		},
+		"modulo by zero": {
+			input: &job.MathInput{
+				Operation:  job.Modulo,
+				LeftValue:  "10",
+				RightValue: "0",
+			},
+			err: ErrInvalidInput,
+		},
+		"modulo (invalid value)": {
+			input: &job.MathInput{
+				Operation:  job.Modulo,
+				LeftValue:  "10",
+				RightValue: "1.5",
+			},
+			err: ErrActionFailed,
+		},
+		"comparison (less)": {
+			input: &job.MathInput{
+				Operation:  job.Comparison,
+				LeftValue:  "-10",
+				RightValue: "3",
+			},
+			output: `"-1"`,
+		},
+		"comparison (equal)": {
+			input: &job.MathInput{
+				Operation:  job.Comparison,
+				LeftValue:  "-3",
+				RightValue: "-3",
+			},
+			output: `"0"`,
+		},
+		"comparison (greater)": {
+			input: &job.MathInput{
+				Operation:  job.Comparison,
+				LeftValue:  "3",
+				RightValue: "-10",
+			},
+			output: `"1"`,
+		},
+		"invalid value": {
+			input: &job.MathInput{
+				Operation:  job.Multiplication,
+				LeftValue:  "1.5",
+				RightValue: "3",
+			},
+			err: ErrActionFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, err := MathWorker(types.PrintStruct(test.input))
+			if test.err != nil {
+				assert.Equal(t, "", output)
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.output, output)
+			}
+		})
+	}
+}
+
+func TestRandomNumberWorker(t *testing.T) {
+	var tests = map[string]struct {
+		input *job.RandomNumberInput
+
+		possible map[string]struct{}
+		err      error
+	}{
+		"positive range": {
+			input: &job.RandomNumberInput{
+				Minimum: "1",
+				Maximum: "3",
+			},
+			possible: map[string]struct{}{`"1"`: {}, `"2"`: {}, `"3"`: {}},
+		},
+		"negative range": {
+			input: &job.RandomNumberInput{
+				Minimum: "-2",
+				Maximum: "-1",
+			},
+			possible: map[string]struct{}{`"-2"`: {}, `"-1"`: {}},
+		},
+		"single value": {
+			input: &job.RandomNumberInput{
+				Minimum: "-5",
+				Maximum: "-5",
+			},
+			possible: map[string]struct{}{`"-5"`: {}},
+		},
+		"maximum < minimum": {
+			input: &job.RandomNumberInput{
+				Minimum: "-100",
+				Maximum: "-200",
+			},
+			err: ErrActionFailed,
+		},
+		"invalid minimum": {
+			input: &job.RandomNumberInput{
+				Minimum: "hello",
+				Maximum: "10",
+			},
+			err: ErrActionFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			seen := map[string]struct{}{}
+			for i := 0; i < 100; i++ {
+				output, err := RandomNumberWorker(types.PrintStruct(test.input))
+				if test.err != nil {
+					assert.Equal(t, "", output)
+					assert.True(t, errors.Is(err, test.err))
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Contains(t, test.possible, output)
+				seen[output] = struct{}{}
+			}
+
+			// With 100 draws, every value in these small
+			// ranges should be observed at least once.
+			assert.Equal(t, test.possible, seen)
+		})
+	}
+}
+
+func TestAssertWorkers(t *testing.T) {
+	var tests = map[string]struct {
+		action job.ActionType
+		input  string
+
+		err error
+	}{
+		"assert_equal: equal strings": {
+			action: job.AssertEqual,
+			input:  `{"left_value":"hello","right_value":"hello"}`,
+		},
+		"assert_equal: different strings": {
+			action: job.AssertEqual,
+			input:  `{"left_value":"hello","right_value":"Hello"}`,
+			err:    ErrAssertionFailed,
+		},
+		"assert_equal: numeric strings": {
+			action: job.AssertEqual,
+			input:  `{"left_value":"0100","right_value":"100"}`,
+		},
+		"assert_equal: numeric string and number": {
+			action: job.AssertEqual,
+			input:  `{"left_value":"-100","right_value":-100}`,
+		},
+		"assert_equal: big integers": {
+			action: job.AssertEqual,
+			input:  `{"left_value":"100000000000000000000000000001","right_value":"100000000000000000000000000000"}`, // nolint
+			err:    ErrAssertionFailed,
+		},
+		"assert_equal: decimal strings compared as strings": {
+			action: job.AssertEqual,
+			input:  `{"left_value":"1.0","right_value":"1"}`,
+			err:    ErrAssertionFailed,
+		},
+		"assert_equal: number and non-numeric string": {
+			action: job.AssertEqual,
+			input:  `{"left_value":"abc","right_value":10}`,
+			err:    ErrAssertionFailed,
+		},
+		"assert_equal: objects": {
+			action: job.AssertEqual,
+			input:  `{"left_value":{"a":1,"b":[1,2]},"right_value":{"b":[1,2],"a":1}}`,
+		},
+		"assert_equal: missing value": {
+			action: job.AssertEqual,
+			input:  `{"left_value":"hello"}`,
+			err:    ErrInvalidInput,
+		},
+		"assert_not_empty: string": {
+			action: job.AssertNotEmpty,
+			input:  `"hello"`,
+		},
+		"assert_not_empty: zero": {
+			action: job.AssertNotEmpty,
+			input:  `"0"`,
+		},
+		"assert_not_empty: empty string": {
+			action: job.AssertNotEmpty,
+			input:  `""`,
+			err:    ErrAssertionFailed,
+		},
+		"assert_not_empty: null": {
+			action: job.AssertNotEmpty,
+			input:  `null`,
+			err:    ErrAssertionFailed,
+		},
+		"assert_not_empty: empty array": {
+			action: job.AssertNotEmpty,
+			input:  `[]`,
+			err:    ErrAssertionFailed,
+		},
+		"assert_not_empty: empty object": {
+			action: job.AssertNotEmpty,
+			input:  `{}`,
+			err:    ErrAssertionFailed,
+		},
+		"assert_in: string": {
+			action: job.AssertIn,
+			input:  `{"value":"b","array":["a","b"]}`,
+		},
+		"assert_in: numeric": {
+			action: job.AssertIn,
+			input:  `{"value":"010","array":["1",10]}`,
+		},
+		"assert_in: missing": {
+			action: job.AssertIn,
+			input:  `{"value":"c","array":["a","b"]}`,
+			err:    ErrAssertionFailed,
+		},
+		"assert_in: empty array": {
+			action: job.AssertIn,
+			input:  `{"value":"c","array":[]}`,
+			err:    ErrAssertionFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			worker := New(&mocks.Helper{})
+			output, err := worker.invokeWorker(context.Background(), nil, test.action, test.input)
+			assert.Equal(t, "", output)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestHTTPRequestWorker(t *testing.T) {
 	var tests = map[string]struct {
 		input          *job.HTTPRequestInput
 		dontPrependURL bool
+		allowedHosts   []string
 
 		expectedPath    string
 		expectedLatency int
 		expectedMethod  string
 		expectedBody    string
+		expectedHeaders map[string]string
 
 		response    string
 		contentType string
@@ -1713,7 +2766,7 @@ func TestHTTPRequestWorker(t *testing.T) {
 			contentType:     "application/json; charset=UTF-8",
 			response:        `{"money":100}`,
 			statusCode:      http.StatusOK,
-			output:          `{"money":100}`,
+			output:          `{"status_code":200,"body":{"money":100}}`,
 		},
 		"simple post": {
 			input: &job.HTTPRequestInput{
@@ -1729,7 +2782,7 @@ func TestHTTPRequestWorker(t *testing.T) {
 			contentType:     "application/json; charset=UTF-8",
 			response:        `{"money":100}`,
 			statusCode:      http.StatusOK,
-			output:          `{"money":100}`,
+			output:          `{"status_code":200,"body":{"money":100}}`,
 		},
 		"invalid method": {
 			input: &job.HTTPRequestInput{
@@ -1799,6 +2852,111 @@ func TestHTTPRequestWorker(t *testing.T) {
 			statusCode:      http.StatusInternalServerError,
 			err:             ErrActionFailed,
 		},
+		"created": {
+			input: &job.HTTPRequestInput{
+				Method:  job.MethodPost,
+				URL:     "/faucet",
+				Timeout: 10,
+				Body:    `{"address":"123"}`,
+			},
+			expectedPath:    "/faucet",
+			expectedLatency: 1,
+			expectedMethod:  http.MethodPost,
+			expectedBody:    `{"address":"123"}`,
+			contentType:     "application/json; charset=UTF-8",
+			response:        `{"money":100}`,
+			statusCode:      http.StatusCreated,
+			output:          `{"status_code":201,"body":{"money":100}}`,
+		},
+		"not found": {
+			input: &job.HTTPRequestInput{
+				Method:  job.MethodGet,
+				URL:     "/faucet?test=123",
+				Timeout: 10,
+			},
+			expectedPath:    "/faucet?test=123",
+			expectedLatency: 1,
+			expectedMethod:  http.MethodGet,
+			expectedBody:    "",
+			contentType:     "application/json; charset=UTF-8",
+			response:        `{"error":"not found"}`,
+			statusCode:      http.StatusNotFound,
+			err:             ErrActionFailed,
+		},
+		"headers": {
+			input: &job.HTTPRequestInput{
+				Method:  job.MethodPost,
+				URL:     "/faucet",
+				Timeout: 10,
+				Headers: map[string]string{
+					"Authorization": "Bearer token",
+					"Content-Type":  "text/plain",
+				},
+				Body: "hello",
+			},
+			expectedPath:    "/faucet",
+			expectedLatency: 1,
+			expectedMethod:  http.MethodPost,
+			expectedBody:    "hello",
+			expectedHeaders: map[string]string{
+				"Authorization": "Bearer token",
+				"Content-Type":  "text/plain",
+				"Accept":        "application/json",
+			},
+			contentType: "application/json; charset=UTF-8",
+			response:    `{"money":100}`,
+			statusCode:  http.StatusOK,
+			output:      `{"status_code":200,"body":{"money":100}}`,
+		},
+		"non-JSON body": {
+			input: &job.HTTPRequestInput{
+				Method:  job.MethodGet,
+				URL:     "/faucet?test=123",
+				Timeout: 10,
+			},
+			expectedPath:    "/faucet?test=123",
+			expectedLatency: 1,
+			expectedMethod:  http.MethodGet,
+			expectedBody:    "",
+			contentType:     "text/plain",
+			response:        `sent "100"`,
+			statusCode:      http.StatusOK,
+			output:          `{"status_code":200,"body":"sent \"100\""}`,
+		},
+		"allowed host": {
+			input: &job.HTTPRequestInput{
+				Method:  job.MethodGet,
+				URL:     "/faucet?test=123",
+				Timeout: 10,
+			},
+			allowedHosts:    []string{"example.com", "127.0.0.1"},
+			expectedPath:    "/faucet?test=123",
+			expectedLatency: 1,
+			expectedMethod:  http.MethodGet,
+			expectedBody:    "",
+			contentType:     "application/json; charset=UTF-8",
+			response:        `{"money":100}`,
+			statusCode:      http.StatusOK,
+			output:          `{"status_code":200,"body":{"money":100}}`,
+		},
+		"disallowed host": {
+			input: &job.HTTPRequestInput{
+				Method:  job.MethodGet,
+				URL:     "/faucet?test=123",
+				Timeout: 10,
+			},
+			allowedHosts: []string{"example.com"},
+			err:          ErrHostNotAllowed,
+		},
+		"no allowed hosts": {
+			input: &job.HTTPRequestInput{
+				Method:  job.MethodGet,
+				URL:     "/faucet?test=123",
+				Timeout: 10,
+			},
+			allowedHosts: []string{},
+			err:          ErrHostNotAllowed,
+		},
 		"invalid content type": { // we don't throw an error
 			input: &job.HTTPRequestInput{
 				Method:  job.MethodGet,
@@ -1812,7 +2970,7 @@ func TestHTTPRequestWorker(t *testing.T) {
 			contentType:     "text/plain",
 			response:        `{"money":100}`,
 			statusCode:      http.StatusOK,
-			output:          `{"money":100}`,
+			output:          `{"status_code":200,"body":{"money":100}}`,
 		},
 	}
 
@@ -1827,6 +2985,10 @@ func TestHTTPRequestWorker(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, test.expectedBody, string(body))
 
+				for key, value := range test.expectedHeaders {
+					assert.Equal(t, value, r.Header.Get(key))
+				}
+
 				time.Sleep(time.Duration(test.expectedLatency) * time.Millisecond)
 
 				w.Header().Set("Content-Type", test.contentType)
@@ -1840,7 +3002,16 @@ func TestHTTPRequestWorker(t *testing.T) {
 				test.input.URL = ts.URL + test.input.URL
 			}
 
-			output, err := HTTPRequestWorker(types.PrintStruct(test.input))
+			options := []Option{WithAllowAllHosts()}
+			if test.allowedHosts != nil {
+				options = []Option{WithAllowedHosts(test.allowedHosts...)}
+			}
+
+			worker := New(&mocks.Helper{}, options...)
+			output, err := worker.HTTPRequestWorker(
+				context.Background(),
+				types.PrintStruct(test.input),
+			)
 			if test.err != nil {
 				assert.Equal(t, "", output)
 				assert.True(t, errors.Is(err, test.err))
@@ -1852,6 +3023,57 @@ func TestHTTPRequestWorker(t *testing.T) {
 	}
 }
 
+func TestHTTPRequestWorkerRedirect(t *testing.T) {
+	redirected := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"money":100}`)
+	}))
+	defer target.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/faucet", http.StatusFound)
+	}))
+	defer ts.Close()
+
+	input := types.PrintStruct(&job.HTTPRequestInput{
+		Method:  job.MethodGet,
+		URL:     ts.URL + "/faucet",
+		Timeout: 10,
+		Headers: map[string]string{"X-Api-Key": "secret"},
+	})
+	origin, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+	redirect, err := url.Parse(target.URL)
+	assert.NoError(t, err)
+
+	t.Run("redirect to disallowed host", func(t *testing.T) {
+		worker := New(&mocks.Helper{}, WithAllowedHosts(origin.Host))
+		output, err := worker.HTTPRequestWorker(context.Background(), input)
+		assert.Equal(t, "", output)
+		assert.True(t, errors.Is(err, ErrHostNotAllowed))
+		assert.False(t, redirected)
+	})
+
+	t.Run("redirect to allowed host", func(t *testing.T) {
+		worker := New(&mocks.Helper{}, WithAllowedHosts(origin.Host, redirect.Host))
+		output, err := worker.HTTPRequestWorker(context.Background(), input)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"status_code":200,"body":{"money":100}}`, output)
+		assert.True(t, redirected)
+	})
+
+	t.Run("deprecated worker", func(t *testing.T) {
+		output, err := HTTPRequestWorker(input)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"money":100}`, output)
+	})
+}
+
 func TestBlobWorkers(t *testing.T) {
 	tests := map[string]struct {
 		scenario *job.Scenario
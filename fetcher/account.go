@@ -17,6 +17,7 @@ package fetcher
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -32,6 +33,8 @@ func (f *Fetcher) AccountBalance(
 	block *types.PartialBlockIdentifier,
 	currencies []*types.Currency,
 ) (*types.BlockIdentifier, []*types.Amount, map[string]interface{}, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, nil, nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -39,7 +42,10 @@ func (f *Fetcher) AccountBalance(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.AccountAPI.AccountBalance(ctx,
+	requestCtx, cancel := f.requestContext(ctx, AccountEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.AccountAPI.AccountBalance(requestCtx,
 		&types.AccountBalanceRequest{
 			NetworkIdentifier: network,
 			AccountIdentifier: account,
@@ -77,10 +83,8 @@ func (f *Fetcher) AccountBalanceRetry(
 	block *types.PartialBlockIdentifier,
 	currencies []*types.Currency,
 ) (*types.BlockIdentifier, []*types.Amount, map[string]interface{}, *Error) {
-	backoffRetries := backoffRetries(
-		f.retryElapsedTime,
-		f.maxRetries,
-	)
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
 
 	for {
 		responseBlock, balances, metadata, err := f.AccountBalance(
@@ -102,13 +106,15 @@ func (f *Fetcher) AccountBalanceRetry(
 
 		if is, _ := asserter.Err(err.Err); is {
 			fetcherErr := &Error{
-				Err:       fmt.Errorf("%w: /account/balance not attempting retry", err.Err),
-				ClientErr: err.ClientErr,
+				Err:        fmt.Errorf("%w: /account/balance not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
 			}
 			return nil, nil, nil, fetcherErr
 		}
 
 		if err := tryAgain(
+			ctx,
 			fmt.Sprintf("/account/balance %s", types.PrintStruct(account)),
 			backoffRetries,
 			err,
@@ -118,6 +124,86 @@ func (f *Fetcher) AccountBalanceRetry(
 	}
 }
 
+// AccountBalancesRetry fetches the validated AccountBalance
+// of each account (using AccountBalanceRetry) with at most
+// concurrency requests in flight. If a block is provided, a
+// historical lookup is performed for each account.
+//
+// Responses are keyed by the hash of each account (see
+// types.Hash). If the balance of any account could not be
+// fetched, an *AccountBalancesError describing each failure is
+// returned alongside the balances that were fetched.
+func (f *Fetcher) AccountBalancesRetry(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	accounts []*types.AccountIdentifier,
+	block *types.PartialBlockIdentifier,
+	concurrency int,
+) (map[string]*types.AccountBalanceResponse, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		accountsToFetch = make(chan int)
+		results         = make([]*types.AccountBalanceResponse, len(accounts))
+		failures        = make([]*Error, len(accounts))
+		wg              sync.WaitGroup
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := range accountsToFetch {
+				responseBlock, balances, metadata, err := f.AccountBalanceRetry(
+					ctx,
+					network,
+					accounts[j],
+					block,
+					nil,
+				)
+				if err != nil {
+					failures[j] = err
+					continue
+				}
+
+				results[j] = &types.AccountBalanceResponse{
+					BlockIdentifier: responseBlock,
+					Balances:        balances,
+					Metadata:        metadata,
+				}
+			}
+		}()
+	}
+
+	for i := range accounts {
+		accountsToFetch <- i
+	}
+	close(accountsToFetch)
+	wg.Wait()
+
+	balances := map[string]*types.AccountBalanceResponse{}
+	failed := []*AccountBalanceFailure{}
+	for i, account := range accounts {
+		if failures[i] != nil {
+			failed = append(failed, &AccountBalanceFailure{
+				Account: account,
+				Err:     failures[i],
+			})
+			continue
+		}
+
+		balances[types.Hash(account)] = results[i]
+	}
+
+	if len(failed) > 0 {
+		return balances, &AccountBalancesError{Failures: failed}
+	}
+
+	return balances, nil
+}
+
 // AccountCoins returns the validated response
 // from the AccountCoins method.
 func (f *Fetcher) AccountCoins(
@@ -127,6 +213,8 @@ func (f *Fetcher) AccountCoins(
 	includeMempool bool,
 	currencies []*types.Currency,
 ) (*types.BlockIdentifier, []*types.Coin, map[string]interface{}, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, nil, nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -134,7 +222,10 @@ func (f *Fetcher) AccountCoins(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.AccountAPI.AccountCoins(ctx,
+	requestCtx, cancel := f.requestContext(ctx, AccountEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.AccountAPI.AccountCoins(requestCtx,
 		&types.AccountCoinsRequest{
 			NetworkIdentifier: network,
 			AccountIdentifier: account,
@@ -170,10 +261,8 @@ func (f *Fetcher) AccountCoinsRetry(
 	includeMempool bool,
 	currencies []*types.Currency,
 ) (*types.BlockIdentifier, []*types.Coin, map[string]interface{}, *Error) {
-	backoffRetries := backoffRetries(
-		f.retryElapsedTime,
-		f.maxRetries,
-	)
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
 
 	for {
 		responseBlock, coins, metadata, err := f.AccountCoins(
@@ -195,13 +284,15 @@ func (f *Fetcher) AccountCoinsRetry(
 
 		if is, _ := asserter.Err(err.Err); is {
 			fetcherErr := &Error{
-				Err:       fmt.Errorf("%w: /account/coins not attempting retry", err.Err),
-				ClientErr: err.ClientErr,
+				Err:        fmt.Errorf("%w: /account/coins not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
 			}
 			return nil, nil, nil, fetcherErr
 		}
 
 		if err := tryAgain(
+			ctx,
 			fmt.Sprintf("/account/coins %s", types.PrintStruct(account)),
 			backoffRetries,
 			err,
@@ -17,9 +17,11 @@ package fetcher
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -202,6 +204,7 @@ func TestAccountBalanceRetry(t *testing.T) {
 			assert.Equal(test.expectedAmounts, amounts)
 			assert.Nil(metadata)
 			assert.True(checkError(err, test.expectedError))
+			assertStatusCode(t, err, test.expectedError, http.StatusInternalServerError)
 		})
 	}
 }
@@ -339,6 +342,128 @@ func TestAccountCoinsRetry(t *testing.T) {
 			assert.Equal(test.expectedCoins, coins)
 			assert.Nil(metadata)
 			assert.True(checkError(err, test.expectedError))
+			assertStatusCode(t, err, test.expectedError, http.StatusInternalServerError)
 		})
 	}
 }
+
+func TestAccountBalancesRetry(t *testing.T) {
+	var (
+		assert      = assert.New(t)
+		ctx         = context.Background()
+		concurrency = 4
+		accounts    = []*types.AccountIdentifier{}
+
+		l        sync.Mutex
+		inFlight = 0
+		maxSeen  = 0
+		tries    = map[string]int{}
+	)
+	for i := 0; i < 20; i++ {
+		accounts = append(accounts, &types.AccountIdentifier{
+			Address: fmt.Sprintf("address %d", i),
+		})
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var accountRequest *types.AccountBalanceRequest
+		assert.NoError(json.NewDecoder(r.Body).Decode(&accountRequest))
+		assert.Equal(basicNetwork, accountRequest.NetworkIdentifier)
+		assert.Equal(int64(5), *accountRequest.BlockIdentifier.Index)
+
+		var index int
+		_, err := fmt.Sscanf(accountRequest.AccountIdentifier.Address, "address %d", &index)
+		assert.NoError(err)
+
+		l.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		tries[accountRequest.AccountIdentifier.Address]++
+		attempt := tries[accountRequest.AccountIdentifier.Address]
+		l.Unlock()
+
+		// Give other workers a chance to start
+		// requests concurrently.
+		time.Sleep(5 * time.Millisecond)
+
+		l.Lock()
+		inFlight--
+		l.Unlock()
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		switch {
+		case index%5 == 0:
+			// Every 5th account fails with a
+			// non-retriable error.
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintln(w, types.PrettyPrintStruct(&types.Error{
+				Code:    1,
+				Message: "account not found",
+			}))
+		case index%3 == 0 && attempt == 1:
+			// Every 3rd account succeeds on retry.
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintln(w, types.PrettyPrintStruct(&types.Error{
+				Retriable: true,
+			}))
+		default:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, types.PrettyPrintStruct(
+				&types.AccountBalanceResponse{
+					BlockIdentifier: &types.BlockIdentifier{
+						Index: 5,
+						Hash:  "block 5",
+					},
+					Balances: basicAmounts,
+				},
+			))
+		}
+	}))
+	defer ts.Close()
+
+	f := New(
+		ts.URL,
+		WithRetryElapsedTime(5*time.Second),
+		WithRetryBackoff(time.Millisecond, 2, 10*time.Millisecond),
+	)
+	balances, err := f.AccountBalancesRetry(
+		ctx,
+		basicNetwork,
+		accounts,
+		&types.PartialBlockIdentifier{Index: types.Int64(5)},
+		concurrency,
+	)
+	assert.True(errors.Is(err, ErrAccountBalancesFailed))
+
+	var balancesErr *AccountBalancesError
+	assert.True(errors.As(err, &balancesErr))
+	assert.Len(balancesErr.Failures, 4)
+	for i, failure := range balancesErr.Failures {
+		assert.Equal(accounts[i*5], failure.Account)
+		assert.True(errors.Is(failure.Err, ErrRequestFailed))
+		assert.Equal(int32(1), failure.Err.ClientErr.Code)
+	}
+
+	assert.Len(balances, 16)
+	for i, account := range accounts {
+		balance, ok := balances[types.Hash(account)]
+		if i%5 == 0 {
+			assert.False(ok)
+			continue
+		}
+
+		assert.Equal(&types.AccountBalanceResponse{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: 5,
+				Hash:  "block 5",
+			},
+			Balances: basicAmounts,
+		}, balance)
+	}
+
+	assert.LessOrEqual(maxSeen, concurrency)
+	assert.Equal(2, tries["address 3"])
+	assert.Equal(1, tries["address 5"])
+}
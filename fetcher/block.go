@@ -79,22 +79,24 @@ func (f *Fetcher) fetchChannelTransactions(
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
 	for transactionIdentifier := range txsToFetch {
-		backoffRetries := backoffRetries(
-			f.retryElapsedTime,
-			f.maxRetries,
-		)
+		backoffRetries := f.backoffRetries()
 
 		var tx *types.BlockTransactionResponse
 		for {
 			var clientErr *types.Error
 			var err error
-			tx, clientErr, err = f.rosettaClient.BlockAPI.BlockTransaction(ctx,
+			requestCtx, cancel := f.requestContext(
+				contextWithBackoff(ctx, backoffRetries),
+				BlockEndpoint,
+			)
+			tx, clientErr, err = f.rosettaClient.BlockAPI.BlockTransaction(requestCtx,
 				&types.BlockTransactionRequest{
 					NetworkIdentifier:     network,
 					BlockIdentifier:       block,
 					TransactionIdentifier: transactionIdentifier,
 				},
 			)
+			cancel()
 			if err == nil {
 				break
 			}
@@ -111,7 +113,7 @@ func (f *Fetcher) fetchChannelTransactions(
 			))
 
 			txFetchErr := fmt.Sprintf("transaction %s", types.PrintStruct(transactionIdentifier))
-			if err := tryAgain(txFetchErr, backoffRetries, fetchErr); err != nil {
+			if err := tryAgain(ctx, txFetchErr, backoffRetries, fetchErr); err != nil {
 				return err
 			}
 		}
@@ -140,6 +142,8 @@ func (f *Fetcher) UnsafeTransactions(
 	block *types.BlockIdentifier,
 	transactionIdentifiers []*types.TransactionIdentifier,
 ) ([]*types.Transaction, *Error) {
+	network = f.defaultNetwork(network)
+
 	if len(transactionIdentifiers) == 0 {
 		return nil, nil
 	}
@@ -213,6 +217,8 @@ func (f *Fetcher) UnsafeBlock(
 	network *types.NetworkIdentifier,
 	blockIdentifier *types.PartialBlockIdentifier,
 ) (*types.Block, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -220,7 +226,10 @@ func (f *Fetcher) UnsafeBlock(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	blockResponse, clientErr, err := f.rosettaClient.BlockAPI.Block(ctx, &types.BlockRequest{
+	requestCtx, cancel := f.requestContext(ctx, BlockEndpoint)
+	defer cancel()
+
+	blockResponse, clientErr, err := f.rosettaClient.BlockAPI.Block(requestCtx, &types.BlockRequest{
 		NetworkIdentifier: network,
 		BlockIdentifier:   blockIdentifier,
 	})
@@ -272,7 +281,7 @@ func (f *Fetcher) Block(
 		return nil, nil
 	}
 
-	if err := f.Asserter.Block(block); err != nil {
+	if err := f.validateBlock(ctx, network, block); err != nil {
 		fetcherErr := &Error{
 			Err: fmt.Errorf("%w: /block", err),
 		}
@@ -284,6 +293,9 @@ func (f *Fetcher) Block(
 
 // BlockRetry retrieves a validated Block
 // with a specified number of retries and max elapsed time.
+//
+// If the block cache is enabled (see WithBlockCache), blocks
+// requested by hash are served from the cache when possible.
 func (f *Fetcher) BlockRetry(
 	ctx context.Context,
 	network *types.NetworkIdentifier,
@@ -293,10 +305,15 @@ func (f *Fetcher) BlockRetry(
 		return nil, &Error{Err: err}
 	}
 
-	backoffRetries := backoffRetries(
-		f.retryElapsedTime,
-		f.maxRetries,
-	)
+	network = f.defaultNetwork(network)
+	if f.blockCache != nil && blockIdentifier.Hash != nil {
+		if block, ok := f.blockCache.get(network, blockIdentifier); ok {
+			return block, nil
+		}
+	}
+
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
 
 	for {
 		block, err := f.Block(
@@ -305,6 +322,12 @@ func (f *Fetcher) BlockRetry(
 			blockIdentifier,
 		)
 		if err == nil {
+			// Any block can be cached by its hash (even
+			// if it was requested by index).
+			if f.blockCache != nil && block != nil {
+				f.blockCache.add(network, block)
+			}
+
 			return block, nil
 		}
 
@@ -314,14 +337,77 @@ func (f *Fetcher) BlockRetry(
 
 		if is, _ := asserter.Err(err.Err); is {
 			fetcherErr := &Error{
-				Err:       fmt.Errorf("%w: /block not attempting retry", err.Err),
-				ClientErr: err.ClientErr,
+				Err:        fmt.Errorf("%w: /block not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
 			}
 			return nil, fetcherErr
 		}
 
 		blockFetchErr := fmt.Sprintf("block %s", types.PrintStruct(blockIdentifier))
-		if err := tryAgain(blockFetchErr, backoffRetries, err); err != nil {
+		if err := tryAgain(ctx, blockFetchErr, backoffRetries, err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// UnvalidatedBlock is a *types.Block returned by
+// UnsafeBlockRetry that may not have passed assertion.
+// It is a distinct type so that it cannot be accidentally
+// passed to code expecting a validated *types.Block
+// (like storage).
+type UnvalidatedBlock struct {
+	// Block is the decoded block returned by the
+	// Rosetta server (nil if the block is omitted).
+	Block *types.Block
+
+	// AssertionErr is the error returned by the
+	// asserter when validating Block (nil if Block
+	// is valid).
+	AssertionErr error
+}
+
+// UnsafeBlockRetry retrieves a Block with a specified
+// number of retries and max elapsed time. Unlike BlockRetry,
+// a block that fails assertion is still returned (along
+// with the assertion error) so that the response of a
+// non-conformant implementation can be inspected.
+//
+// This should only be used for debugging. Use BlockRetry
+// to fetch blocks that will be stored or processed.
+func (f *Fetcher) UnsafeBlockRetry(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	blockIdentifier *types.PartialBlockIdentifier,
+) (*UnvalidatedBlock, *Error) {
+	if err := asserter.PartialBlockIdentifier(blockIdentifier); err != nil {
+		return nil, &Error{Err: err}
+	}
+
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
+
+	for {
+		block, err := f.UnsafeBlock(
+			ctx,
+			network,
+			blockIdentifier,
+		)
+		if err == nil {
+			unvalidatedBlock := &UnvalidatedBlock{Block: block}
+			if block != nil {
+				unvalidatedBlock.AssertionErr = f.currentAsserter().Block(block)
+			}
+
+			return unvalidatedBlock, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, &Error{Err: ctx.Err()}
+		}
+
+		blockFetchErr := fmt.Sprintf("block %s", types.PrintStruct(blockIdentifier))
+		if err := tryAgain(ctx, blockFetchErr, backoffRetries, err); err != nil {
 			return nil, err
 		}
 	}
@@ -0,0 +1,193 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// BlockCacheStats contains the counters of
+// the block cache (see WithBlockCache).
+type BlockCacheStats struct {
+	// Hits is the number of requests for a block
+	// by hash that were served from the cache.
+	Hits uint64 `json:"hits"`
+
+	// Misses is the number of requests for a block
+	// by hash that were not in the cache. Requests
+	// for a block by index alone always bypass the
+	// cache and are not counted.
+	Misses uint64 `json:"misses"`
+
+	// Blocks is the number of blocks
+	// currently in the cache.
+	Blocks int `json:"blocks"`
+}
+
+// blockCache is a size-bounded cache of validated
+// blocks keyed by network and block hash. When the
+// cache is full, the least recently used block is
+// evicted.
+//
+// Blocks are stored JSON encoded, so each block
+// returned by the cache is a new copy that can be
+// modified by the caller without affecting the cache
+// (and blocks can be modified after they are added).
+type blockCache struct {
+	size int
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // most recently used at the front
+
+	hits   uint64
+	misses uint64
+}
+
+// blockCacheEntry is the value of
+// each element in blockCache.order.
+type blockCacheEntry struct {
+	key     string
+	index   int64
+	encoded []byte
+}
+
+func newBlockCache(size int) *blockCache {
+	return &blockCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// blockCacheKey returns the key of a block
+// with a hash on a network.
+func blockCacheKey(network *types.NetworkIdentifier, hash string) string {
+	return fmt.Sprintf("%s/%s", types.Hash(network), hash)
+}
+
+// get returns the cached block for a
+// *types.PartialBlockIdentifier with a hash.
+// If the *types.PartialBlockIdentifier also has
+// an index that doesn't match the cached block,
+// the request is treated as a miss.
+func (c *blockCache) get(
+	network *types.NetworkIdentifier,
+	blockIdentifier *types.PartialBlockIdentifier,
+) (*types.Block, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[blockCacheKey(network, *blockIdentifier.Hash)]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	entry := element.Value.(*blockCacheEntry)
+	if blockIdentifier.Index != nil && *blockIdentifier.Index != entry.index {
+		c.misses++
+		return nil, false
+	}
+
+	var block types.Block
+	if err := json.Unmarshal(entry.encoded, &block); err != nil {
+		c.misses++
+		return nil, false
+	}
+
+	c.hits++
+	c.order.MoveToFront(element)
+	return &block, true
+}
+
+// add stores a copy of a block in the cache (evicting
+// the least recently used block if the cache is full).
+// Blocks that cannot be encoded are not cached.
+func (c *blockCache) add(network *types.NetworkIdentifier, block *types.Block) {
+	encoded, err := json.Marshal(block)
+	if err != nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := blockCacheKey(network, block.BlockIdentifier.Hash)
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*blockCacheEntry).encoded = encoded
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&blockCacheEntry{
+		key:     key,
+		index:   block.BlockIdentifier.Index,
+		encoded: encoded,
+	})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*blockCacheEntry).key)
+	}
+}
+
+// purge removes all blocks from the cache.
+func (c *blockCache) purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+}
+
+// stats returns the current counters of the cache.
+func (c *blockCache) stats() *BlockCacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return &BlockCacheStats{
+		Hits:   c.hits,
+		Misses: c.misses,
+		Blocks: c.order.Len(),
+	}
+}
+
+// BlockCacheStats returns the counters of the block
+// cache (nil if the block cache is not enabled).
+func (f *Fetcher) BlockCacheStats() *BlockCacheStats {
+	if f.blockCache == nil {
+		return nil
+	}
+
+	return f.blockCache.stats()
+}
+
+// PurgeBlockCache removes all blocks from the block
+// cache (if enabled). The hit and miss counters are
+// not reset.
+func (f *Fetcher) PurgeBlockCache() {
+	if f.blockCache == nil {
+		return
+	}
+
+	f.blockCache.purge()
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func cacheTestBlock(index int64) *types.Block {
+	return &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: index,
+			Hash:  fmt.Sprintf("block %d", index),
+		},
+	}
+}
+
+func TestBlockCacheEviction(t *testing.T) {
+	c := newBlockCache(2)
+	for i := int64(0); i < 3; i++ {
+		c.add(basicNetwork, cacheTestBlock(i))
+
+		// Block 0 is used more recently than block 1
+		if i == 1 {
+			_, ok := c.get(basicNetwork, types.ConstructPartialBlockIdentifier(
+				cacheTestBlock(0).BlockIdentifier,
+			))
+			assert.True(t, ok)
+		}
+	}
+
+	for i, expected := range []bool{true, false, true} {
+		block := cacheTestBlock(int64(i))
+		cached, ok := c.get(basicNetwork, types.ConstructPartialBlockIdentifier(
+			block.BlockIdentifier,
+		))
+		assert.Equal(t, expected, ok)
+		if expected {
+			assert.Equal(t, block, cached)
+		}
+	}
+
+	assert.Equal(t, &BlockCacheStats{
+		Hits:   3,
+		Misses: 1,
+		Blocks: 2,
+	}, c.stats())
+}
+
+func TestBlockCacheCopies(t *testing.T) {
+	c := newBlockCache(1)
+	block := cacheTestBlock(1)
+	block.Metadata = map[string]interface{}{"hello": "world"}
+	c.add(basicNetwork, block)
+
+	// Modifying a block after it is added
+	// does not modify the cache
+	block.Metadata["hello"] = "added"
+	blockIdentifier := types.ConstructPartialBlockIdentifier(block.BlockIdentifier)
+	cached, ok := c.get(basicNetwork, blockIdentifier)
+	assert.True(t, ok)
+	assert.Equal(t, "world", cached.Metadata["hello"])
+
+	// Modifying a cached block does not
+	// modify the cache (or other callers)
+	cached.Metadata["hello"] = "cached"
+	cached.BlockIdentifier.Index = 2
+	cachedAgain, ok := c.get(basicNetwork, blockIdentifier)
+	assert.True(t, ok)
+	assert.NotSame(t, cached, cachedAgain)
+	assert.Equal(t, "world", cachedAgain.Metadata["hello"])
+	assert.Equal(t, int64(1), cachedAgain.BlockIdentifier.Index)
+}
+
+func TestBlockCacheDisabled(t *testing.T) {
+	f := New("http://localhost", WithBlockCache(0))
+	assert.Nil(t, f.BlockCacheStats())
+
+	// Purging a disabled cache is a no-op
+	f.PurgeBlockCache()
+}
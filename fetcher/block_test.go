@@ -17,6 +17,7 @@ package fetcher
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -258,3 +259,231 @@ func TestBlockRetry(t *testing.T) {
 		})
 	}
 }
+
+func TestUnsafeBlockRetry(t *testing.T) {
+	invalidBlock := &types.Block{
+		BlockIdentifier:       basicBlock,
+		ParentBlockIdentifier: basicBlock,
+		Timestamp:             1582833600000,
+	}
+
+	var tests = map[string]struct {
+		blockResponse       *types.Block
+		errorsBeforeSuccess int
+
+		expectedBlock        *types.Block
+		expectedAssertionErr error
+		expectedError        error
+	}{
+		"valid block": {
+			blockResponse: basicFullBlock,
+			expectedBlock: basicFullBlock,
+		},
+		"invalid block": {
+			blockResponse:        invalidBlock,
+			expectedBlock:        invalidBlock,
+			expectedAssertionErr: asserter.ErrBlockHashEqualsParentBlockHash,
+		},
+		"invalid block after retries": {
+			blockResponse:        invalidBlock,
+			errorsBeforeSuccess:  2,
+			expectedBlock:        invalidBlock,
+			expectedAssertionErr: asserter.ErrBlockHashEqualsParentBlockHash,
+		},
+		"omitted block": {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				assert = assert.New(t)
+				ctx    = context.Background()
+				tries  = 0
+			)
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal("/block", r.URL.RequestURI())
+
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				if tries < test.errorsBeforeSuccess {
+					w.WriteHeader(http.StatusInternalServerError)
+					fmt.Fprintln(w, types.PrettyPrintStruct(&types.Error{
+						Retriable: true,
+					}))
+					tries++
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, types.PrettyPrintStruct(&types.BlockResponse{
+					Block: test.blockResponse,
+				}))
+			}))
+
+			defer ts.Close()
+			a, err := asserter.NewClientWithOptions(
+				basicNetwork,
+				&types.BlockIdentifier{
+					Index: 0,
+					Hash:  "block 0",
+				},
+				basicNetworkOptions.Allow.OperationTypes,
+				basicNetworkOptions.Allow.OperationStatuses,
+				nil,
+				nil,
+			)
+			assert.NoError(err)
+
+			f := New(
+				ts.URL,
+				WithRetryElapsedTime(5*time.Second),
+				WithRetryBackoff(time.Millisecond, 2, 10*time.Millisecond),
+				WithAsserter(a),
+			)
+			partialBlock := types.ConstructPartialBlockIdentifier(basicBlock)
+
+			// The strict path rejects invalid blocks
+			if test.expectedAssertionErr != nil {
+				block, blockErr := f.BlockRetry(ctx, basicNetwork, partialBlock)
+				assert.Nil(block)
+				assert.True(checkError(blockErr, test.expectedAssertionErr))
+				tries = 0
+			}
+
+			unvalidatedBlock, blockErr := f.UnsafeBlockRetry(ctx, basicNetwork, partialBlock)
+			assert.True(checkError(blockErr, test.expectedError))
+			assert.Equal(test.expectedBlock, unvalidatedBlock.Block)
+			if test.expectedAssertionErr != nil {
+				assert.True(errors.Is(unvalidatedBlock.AssertionErr, test.expectedAssertionErr))
+			} else {
+				assert.NoError(unvalidatedBlock.AssertionErr)
+			}
+		})
+	}
+}
+
+func TestBlockRetryCache(t *testing.T) {
+	ctx := context.Background()
+	blockRequests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/block", r.URL.RequestURI())
+		blockRequests++
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, types.PrettyPrintStruct(&types.BlockResponse{
+			Block: basicFullBlock,
+		}))
+	}))
+	defer ts.Close()
+
+	a, err := asserter.NewClientWithOptions(
+		basicNetwork,
+		&types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		basicNetworkOptions.Allow.OperationTypes,
+		basicNetworkOptions.Allow.OperationStatuses,
+		nil,
+		nil,
+	)
+	assert.NoError(t, err)
+
+	f := New(
+		ts.URL,
+		WithRetryElapsedTime(5*time.Second),
+		WithAsserter(a),
+		WithBlockCache(10),
+	)
+
+	byHash := &types.PartialBlockIdentifier{Hash: &basicBlock.Hash}
+	byIndex := &types.PartialBlockIdentifier{Index: &basicBlock.Index}
+	wrongIndex := int64(11)
+	byHashAndWrongIndex := &types.PartialBlockIdentifier{
+		Hash:  &basicBlock.Hash,
+		Index: &wrongIndex,
+	}
+	otherNetwork := &types.NetworkIdentifier{
+		Blockchain: "blockchain",
+		Network:    "other network",
+	}
+
+	tests := []struct {
+		name            string
+		network         *types.NetworkIdentifier
+		blockIdentifier *types.PartialBlockIdentifier
+
+		expectedRequests int
+		expectedStats    *BlockCacheStats
+		purge            bool
+	}{
+		{
+			name:             "hash miss",
+			network:          basicNetwork,
+			blockIdentifier:  byHash,
+			expectedRequests: 1,
+			expectedStats:    &BlockCacheStats{Misses: 1, Blocks: 1},
+		},
+		{
+			name:             "hash hit",
+			network:          basicNetwork,
+			blockIdentifier:  byHash,
+			expectedRequests: 1,
+			expectedStats:    &BlockCacheStats{Hits: 1, Misses: 1, Blocks: 1},
+		},
+		{
+			name:             "index bypasses cache",
+			network:          basicNetwork,
+			blockIdentifier:  byIndex,
+			expectedRequests: 2,
+			expectedStats:    &BlockCacheStats{Hits: 1, Misses: 1, Blocks: 1},
+		},
+		{
+			name:             "index bypasses cache again",
+			network:          basicNetwork,
+			blockIdentifier:  byIndex,
+			expectedRequests: 3,
+			expectedStats:    &BlockCacheStats{Hits: 1, Misses: 1, Blocks: 1},
+		},
+		{
+			name:             "hash with mismatched index",
+			network:          basicNetwork,
+			blockIdentifier:  byHashAndWrongIndex,
+			expectedRequests: 4,
+			expectedStats:    &BlockCacheStats{Hits: 1, Misses: 2, Blocks: 1},
+		},
+		{
+			name:             "other network",
+			network:          otherNetwork,
+			blockIdentifier:  byHash,
+			expectedRequests: 5,
+			expectedStats:    &BlockCacheStats{Hits: 1, Misses: 3, Blocks: 2},
+		},
+		{
+			name:             "hash after purge",
+			network:          basicNetwork,
+			blockIdentifier:  byHash,
+			purge:            true,
+			expectedRequests: 6,
+			expectedStats:    &BlockCacheStats{Hits: 1, Misses: 4, Blocks: 1},
+		},
+	}
+
+	// Tests are run in order because
+	// each depends on the cache state.
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.purge {
+				f.PurgeBlockCache()
+				assert.Equal(t, 0, f.BlockCacheStats().Blocks)
+			}
+
+			block, blockErr := f.BlockRetry(ctx, test.network, test.blockIdentifier)
+			assert.Nil(t, blockErr)
+			assert.Equal(t, basicFullBlock, block)
+			assert.Equal(t, test.expectedRequests, blockRequests)
+			assert.Equal(t, test.expectedStats, f.BlockCacheStats())
+		})
+	}
+}
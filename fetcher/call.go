@@ -37,18 +37,37 @@ func (f *Fetcher) Call(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.CallAPI.Call(
-		ctx,
-		&types.CallRequest{
-			NetworkIdentifier: network,
-			Method:            method,
-			Parameters:        parameters,
-		},
-	)
+	request := &types.CallRequest{
+		NetworkIdentifier: network,
+		Method:            method,
+		Parameters:        parameters,
+	}
+
+	// Reject requests for methods that are not supported
+	// before making the request (when the asserter has
+	// been initialized).
+	if a := f.currentAsserter(); a != nil {
+		if err := a.CallRequest(request); err != nil {
+			return nil, false, &Error{
+				Err: fmt.Errorf("%w: /call", err),
+			}
+		}
+	}
+
+	requestCtx, cancel := f.requestContext(ctx, CallEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.CallAPI.Call(requestCtx, request)
 	if err != nil {
 		return nil, false, f.RequestFailedError(clientErr, err, "/call")
 	}
 
+	if err := asserter.CallResponse(response); err != nil {
+		return nil, false, &Error{
+			Err: fmt.Errorf("%w: /call", err),
+		}
+	}
+
 	return response.Result, response.Idempotent, nil
 }
 
@@ -60,10 +79,8 @@ func (f *Fetcher) CallRetry(
 	method string,
 	parameters map[string]interface{},
 ) (map[string]interface{}, bool, *Error) {
-	backoffRetries := backoffRetries(
-		f.retryElapsedTime,
-		f.maxRetries,
-	)
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
 
 	for {
 		result, idempotent, err := f.Call(
@@ -84,13 +101,15 @@ func (f *Fetcher) CallRetry(
 
 		if is, _ := asserter.Err(err.Err); is {
 			fetcherErr := &Error{
-				Err:       fmt.Errorf("%w: /call not attempting retry", err.Err),
-				ClientErr: err.ClientErr,
+				Err:        fmt.Errorf("%w: /call not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
 			}
 			return nil, false, fetcherErr
 		}
 
 		if err := tryAgain(
+			ctx,
 			fmt.Sprintf("/call %s:%s", method, types.PrintStruct(parameters)),
 			backoffRetries,
 			err,
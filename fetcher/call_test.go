@@ -17,6 +17,7 @@ package fetcher
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -25,6 +26,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -160,6 +162,86 @@ func TestCallRetry(t *testing.T) {
 			assert.Equal(test.expectedResult, result)
 			assert.Equal(test.expectedIdempotency, idempotency)
 			assert.True(checkError(err, test.expectedError))
+			assertStatusCode(t, err, test.expectedError, http.StatusInternalServerError)
+		})
+	}
+}
+
+func TestCallAssertion(t *testing.T) {
+	callOptions := &types.NetworkOptionsResponse{
+		Version: basicNetworkOptions.Version,
+		Allow: &types.Allow{
+			OperationStatuses: basicNetworkOptions.Allow.OperationStatuses,
+			OperationTypes:    basicNetworkOptions.Allow.OperationTypes,
+			CallMethods:       []string{"eth_call", "eth_getBlock"},
+		},
+	}
+
+	var tests = map[string]struct {
+		method         string
+		result         map[string]interface{}
+		expectedCalls  int
+		expectedResult map[string]interface{}
+		expectedError  error
+	}{
+		"supported method": {
+			method:         "eth_call",
+			result:         map[string]interface{}{"hello": "world"},
+			expectedCalls:  1,
+			expectedResult: map[string]interface{}{"hello": "world"},
+		},
+		"empty result": {
+			method:         "eth_getBlock",
+			result:         map[string]interface{}{},
+			expectedCalls:  1,
+			expectedResult: map[string]interface{}{},
+		},
+		"unsupported method": {
+			method:        "eth_sendTransaction",
+			expectedError: asserter.ErrCallMethodUnsupported,
+		},
+		"nil result": {
+			method:        "eth_call",
+			expectedCalls: 1,
+			expectedError: asserter.ErrCallResultIsNil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				switch r.URL.RequestURI() {
+				case "/network/list":
+					fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkList))
+				case "/network/status":
+					fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkStatus))
+				case "/network/options":
+					fmt.Fprintln(w, types.PrettyPrintStruct(callOptions))
+				case "/call":
+					calls++
+					fmt.Fprintln(w, types.PrettyPrintStruct(&types.CallResponse{
+						Result:     test.result,
+						Idempotent: true,
+					}))
+				}
+			}))
+			defer ts.Close()
+
+			f := New(ts.URL, WithMaxRetries(1))
+			_, _, fetchErr := f.InitializeAsserter(context.Background(), basicNetwork)
+			assert.Nil(t, fetchErr)
+
+			result, _, err := f.CallRetry(context.Background(), basicNetwork, test.method, nil)
+			assert.Equal(t, test.expectedCalls, calls)
+			assert.Equal(t, test.expectedResult, result)
+			if test.expectedError == nil {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, test.expectedError))
 		})
 	}
 }
@@ -15,6 +15,7 @@
 package fetcher
 
 import (
+	"crypto/tls"
 	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
@@ -49,6 +50,22 @@ func WithRetryElapsedTime(retryElapsedTime time.Duration) Option {
 	}
 }
 
+// WithRetryBackoff overrides the default exponential
+// backoff used between retries of a request. The delay
+// before each retry is chosen at random (full jitter)
+// between 0 and min(maxDelay, base * multiplier^attempts).
+func WithRetryBackoff(
+	base time.Duration,
+	multiplier float64,
+	maxDelay time.Duration,
+) Option {
+	return func(f *Fetcher) {
+		f.retryBase = base
+		f.retryMultiplier = multiplier
+		f.retryCap = maxDelay
+	}
+}
+
 // WithAsserter sets the asserter.Asserter on construction
 // so it does not need to be initialized.
 func WithAsserter(asserter *asserter.Asserter) Option {
@@ -57,6 +74,15 @@ func WithAsserter(asserter *asserter.Asserter) Option {
 	}
 }
 
+// WithAsserterOptions provides options used to construct
+// the asserter.Asserter when it is initialized (or refreshed)
+// and to validate any NetworkStatus fetched before then.
+func WithAsserterOptions(options ...asserter.Option) Option {
+	return func(f *Fetcher) {
+		f.asserterOptions = options
+	}
+}
+
 // WithInsecureTLS overrides the default TLS
 // security settings to allow insecure certificates
 // on an HTTPS connection.
@@ -77,6 +103,34 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithEndpointTimeout sets the timeout of each request
+// made to a class of endpoints (ex: a longer timeout for
+// /block than for /network/status). The timeout is applied
+// per attempt, so a request that times out may be retried.
+//
+// When any endpoint timeout is set, the HTTP client timeout
+// (see WithTimeout) is only applied to endpoints without
+// their own timeout (so endpoint timeouts may be longer).
+func WithEndpointTimeout(endpoint Endpoint, timeout time.Duration) Option {
+	return func(f *Fetcher) {
+		if f.endpointTimeouts == nil {
+			f.endpointTimeouts = map[Endpoint]time.Duration{}
+		}
+
+		f.endpointTimeouts[endpoint] = timeout
+	}
+}
+
+// WithHeaders adds headers (ex: Authorization or an
+// API key) to every request made by the fetcher. To vary
+// headers per call, provide them in the context using
+// client.ContextHeaders.
+func WithHeaders(headers map[string]string) Option {
+	return func(f *Fetcher) {
+		f.headers = headers
+	}
+}
+
 // WithMaxConnections limits the number of concurrent
 // requests the fetcher will attempt at once.
 func WithMaxConnections(connections int) Option {
@@ -85,6 +139,123 @@ func WithMaxConnections(connections int) Option {
 	}
 }
 
+// WithMaxIdleConnsPerHost overrides the default maximum
+// number of idle (keep-alive) connections kept open to the
+// Rosetta server. Setting this to at least the number of
+// concurrent requests avoids connection churn (and TIME_WAIT
+// exhaustion) when syncing with high concurrency.
+//
+// Like all transport options, this is ignored if WithClient
+// is provided.
+func WithMaxIdleConnsPerHost(connections int) Option {
+	return func(f *Fetcher) {
+		f.maxIdleConnsPerHost = connections
+	}
+}
+
+// WithIdleConnTimeout overrides the default amount of time
+// an idle (keep-alive) connection will remain open.
+func WithIdleConnTimeout(timeout time.Duration) Option {
+	return func(f *Fetcher) {
+		f.idleConnTimeout = timeout
+	}
+}
+
+// WithDisableCompression prevents the fetcher from
+// requesting gzip compressed responses.
+func WithDisableCompression() Option {
+	return func(f *Fetcher) {
+		f.disableCompression = true
+	}
+}
+
+// WithCompression explicitly requests gzip compressed
+// responses from the Rosetta server and decompresses
+// them (even if a custom *http.Client is provided
+// using WithClient).
+func WithCompression() Option {
+	return func(f *Fetcher) {
+		f.compression = true
+	}
+}
+
+// WithRequestInterceptors adds interceptors that are
+// executed (in order) with every request before it is
+// sent (including retries).
+func WithRequestInterceptors(interceptors ...client.RequestInterceptor) Option {
+	return func(f *Fetcher) {
+		f.requestInterceptors = append(f.requestInterceptors, interceptors...)
+	}
+}
+
+// WithResponseInterceptors adds interceptors that are
+// executed (in order) with every response after it is
+// received. If an interceptor returns an error that wraps
+// client.ErrRetriable, the request is retried.
+func WithResponseInterceptors(interceptors ...client.ResponseInterceptor) Option {
+	return func(f *Fetcher) {
+		f.responseInterceptors = append(f.responseInterceptors, interceptors...)
+	}
+}
+
+// WithBlockCache caches up to size blocks fetched with
+// BlockRetry in memory. Blocks requested by hash are immutable,
+// so they are served from the cache (if present) without making
+// a request. Blocks requested by index alone always bypass the
+// cache because the block at an index can change in a reorg.
+//
+// Each block served from the cache is a copy (so it may
+// be modified by the caller). If size is not positive,
+// blocks are not cached.
+func WithBlockCache(size int) Option {
+	return func(f *Fetcher) {
+		if size <= 0 {
+			f.blockCache = nil
+			return
+		}
+
+		f.blockCache = newBlockCache(size)
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect
+// to the Rosetta server (ex: to provide custom root CAs or
+// client certificates).
+func WithTLSConfig(config *tls.Config) Option {
+	return func(f *Fetcher) {
+		f.tlsConfig = config
+	}
+}
+
+// WithHTTP2 forces the fetcher to attempt HTTP/2 or, if
+// enabled is false, disables HTTP/2 (some gateways misbehave
+// when it is used). By default, HTTP/2 is used if the Rosetta
+// server supports it.
+func WithHTTP2(enabled bool) Option {
+	return func(f *Fetcher) {
+		f.http2 = &enabled
+	}
+}
+
+// WithAsserterRefresh enables automatically refreshing the
+// asserter (see RefreshAsserter) when a block contains an
+// unknown operation type or status (ex: after a node upgrade).
+// The asserter is refreshed at most once per interval.
+func WithAsserterRefresh(interval time.Duration) Option {
+	return func(f *Fetcher) {
+		f.asserterRefreshInterval = interval
+	}
+}
+
+// WithObserver sets a FetcherObserver that is notified
+// of every request made by the fetcher (including each
+// retry attempt).
+func WithObserver(observer FetcherObserver) Option {
+	return func(f *Fetcher) {
+		f.observer = observer
+	}
+}
+
 // WithForceRetry overrides the default
 // retry handling logic and treats every error
 // as retriable.
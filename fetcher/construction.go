@@ -33,6 +33,8 @@ func (f *Fetcher) ConstructionCombine(
 	unsignedTransaction string,
 	signatures []*types.Signature,
 ) (string, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return "", &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -40,7 +42,10 @@ func (f *Fetcher) ConstructionCombine(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionCombine(ctx,
+	requestCtx, cancel := f.requestContext(ctx, ConstructionEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionCombine(requestCtx,
 		&types.ConstructionCombineRequest{
 			NetworkIdentifier:   network,
 			UnsignedTransaction: unsignedTransaction,
@@ -72,6 +77,8 @@ func (f *Fetcher) ConstructionDerive(
 	publicKey *types.PublicKey,
 	metadata map[string]interface{},
 ) (*types.AccountIdentifier, map[string]interface{}, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -79,7 +86,10 @@ func (f *Fetcher) ConstructionDerive(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionDerive(ctx,
+	requestCtx, cancel := f.requestContext(ctx, ConstructionEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionDerive(requestCtx,
 		&types.ConstructionDeriveRequest{
 			NetworkIdentifier: network,
 			PublicKey:         publicKey,
@@ -107,6 +117,8 @@ func (f *Fetcher) ConstructionHash(
 	network *types.NetworkIdentifier,
 	signedTransaction string,
 ) (*types.TransactionIdentifier, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -114,7 +126,10 @@ func (f *Fetcher) ConstructionHash(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionHash(ctx,
+	requestCtx, cancel := f.requestContext(ctx, ConstructionEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionHash(requestCtx,
 		&types.ConstructionHashRequest{
 			NetworkIdentifier: network,
 			SignedTransaction: signedTransaction,
@@ -142,6 +157,8 @@ func (f *Fetcher) ConstructionMetadata(
 	options map[string]interface{},
 	publicKeys []*types.PublicKey,
 ) (map[string]interface{}, []*types.Amount, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -149,7 +166,10 @@ func (f *Fetcher) ConstructionMetadata(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	metadata, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionMetadata(ctx,
+	requestCtx, cancel := f.requestContext(ctx, ConstructionEndpoint)
+	defer cancel()
+
+	metadata, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionMetadata(requestCtx,
 		&types.ConstructionMetadataRequest{
 			NetworkIdentifier: network,
 			Options:           options,
@@ -181,6 +201,8 @@ func (f *Fetcher) ConstructionParse(
 	signed bool,
 	transaction string,
 ) ([]*types.Operation, []*types.AccountIdentifier, map[string]interface{}, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, nil, nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -188,7 +210,10 @@ func (f *Fetcher) ConstructionParse(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionParse(ctx,
+	requestCtx, cancel := f.requestContext(ctx, ConstructionEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionParse(requestCtx,
 		&types.ConstructionParseRequest{
 			NetworkIdentifier: network,
 			Signed:            signed,
@@ -199,7 +224,7 @@ func (f *Fetcher) ConstructionParse(
 		return nil, nil, nil, f.RequestFailedError(clientErr, err, "/construction/parse")
 	}
 
-	if err := f.Asserter.ConstructionParseResponse(response, signed); err != nil {
+	if err := f.currentAsserter().ConstructionParseResponse(response, signed); err != nil {
 		fetcherErr := &Error{
 			Err: fmt.Errorf("%w: /construction/parse", err),
 		}
@@ -227,6 +252,8 @@ func (f *Fetcher) ConstructionPayloads(
 	metadata map[string]interface{},
 	publicKeys []*types.PublicKey,
 ) (string, []*types.SigningPayload, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return "", nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -234,7 +261,10 @@ func (f *Fetcher) ConstructionPayloads(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionPayloads(ctx,
+	requestCtx, cancel := f.requestContext(ctx, ConstructionEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionPayloads(requestCtx,
 		&types.ConstructionPayloadsRequest{
 			NetworkIdentifier: network,
 			Operations:        operations,
@@ -270,6 +300,8 @@ func (f *Fetcher) ConstructionPreprocess(
 	operations []*types.Operation,
 	metadata map[string]interface{},
 ) (map[string]interface{}, []*types.AccountIdentifier, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -277,7 +309,10 @@ func (f *Fetcher) ConstructionPreprocess(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionPreprocess(ctx,
+	requestCtx, cancel := f.requestContext(ctx, ConstructionEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionPreprocess(requestCtx,
 		&types.ConstructionPreprocessRequest{
 			NetworkIdentifier: network,
 			Operations:        operations,
@@ -306,6 +341,8 @@ func (f *Fetcher) ConstructionSubmit(
 	network *types.NetworkIdentifier,
 	signedTransaction string,
 ) (*types.TransactionIdentifier, map[string]interface{}, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -313,8 +350,11 @@ func (f *Fetcher) ConstructionSubmit(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
+	requestCtx, cancel := f.requestContext(ctx, ConstructionEndpoint)
+	defer cancel()
+
 	submitResponse, clientErr, err := f.rosettaClient.ConstructionAPI.ConstructionSubmit(
-		ctx,
+		requestCtx,
 		&types.ConstructionSubmitRequest{
 			NetworkIdentifier: network,
 			SignedTransaction: signedTransaction,
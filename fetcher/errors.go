@@ -19,7 +19,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 
+	"github.com/coinbase/rosetta-sdk-go/client"
 	utils "github.com/coinbase/rosetta-sdk-go/errors"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -30,12 +32,69 @@ type Error struct {
 	Err       error        `json:"err"`
 	ClientErr *types.Error `json:"client_err"`
 
+	// ClientErrAssertion is populated when ClientErr does not
+	// conform to the errors returned in /network/options (an
+	// unknown code or a mismatched message or retriable flag).
+	ClientErrAssertion error `json:"client_err_assertion,omitempty"`
+
+	// StatusCode is the HTTP status code of the
+	// failed response (0 if no response was received).
+	StatusCode int `json:"status_code,omitempty"`
+
 	// Retry is a boolean that indicates if the request should be retried.
 	// It is the combination of the *types.Error.Retriable status and a
 	// collection of transient errors.
 	Retry bool `json:"retry"`
 }
 
+// Error returns the message of Err.
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return types.PrintStruct(e.ClientErr)
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns Err (so that errors.Is
+// can be used on an *Error).
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// As populates a *RosettaError target (using errors.As)
+// if the request failed with a *types.Error.
+func (e *Error) As(target interface{}) bool {
+	rosettaErr, ok := target.(**RosettaError)
+	if !ok || e.ClientErr == nil {
+		return false
+	}
+
+	*rosettaErr = &RosettaError{
+		Err:        e.ClientErr,
+		StatusCode: e.StatusCode,
+	}
+	return true
+}
+
+// RosettaError is a *types.Error returned by a Rosetta
+// server (with the HTTP status code of the response). It
+// can be extracted from any *Error using errors.As.
+type RosettaError struct {
+	Err        *types.Error
+	StatusCode int
+}
+
+// Error returns a description of the *types.Error.
+func (e *RosettaError) Error() string {
+	return fmt.Sprintf(
+		"rosetta error %d (status %d): %s",
+		e.Err.Code,
+		e.StatusCode,
+		e.Err.Message,
+	)
+}
+
 // RequestFailedError creates a new *Error and asserts the provided
 // rosettaErr was provided in /network/options.
 func (f *Fetcher) RequestFailedError(
@@ -46,27 +105,82 @@ func (f *Fetcher) RequestFailedError(
 	// Only check for error correctness if err is not context.Canceled
 	// and it is not transient (usually caused by the client failing
 	// the request).
-	if !errors.Is(err, context.Canceled) && !transientError(err) {
-		// If there is a *types.Error assertion error, we log it instead
-		// of exiting. Exiting abruptly here may cause unintended consequences.
-		if assertionErr := f.Asserter.Error(rosettaErr); assertionErr != nil {
+	var assertionErr error
+	if !errors.Is(err, context.Canceled) && !transientError(err) && f.currentAsserter() != nil {
+		// If there is a *types.Error assertion error, we log and
+		// report it instead of exiting. Exiting abruptly here may
+		// cause unintended consequences.
+		assertionErr = f.currentAsserter().Error(rosettaErr)
+		if assertionErr != nil {
 			log.Printf("error %s assertion failed: %s", types.PrintStruct(rosettaErr), assertionErr)
 		}
 	}
 
+	var statusCode int
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) {
+		statusCode = statusErr.StatusCode
+	}
+
 	return &Error{
-		Err:       fmt.Errorf("%w: %s %s", ErrRequestFailed, message, err.Error()),
-		ClientErr: rosettaErr,
+		Err:                fmt.Errorf("%w: %s %s", ErrRequestFailed, message, err.Error()),
+		ClientErr:          rosettaErr,
+		ClientErrAssertion: assertionErr,
+		StatusCode:         statusCode,
 		Retry: ((rosettaErr != nil && rosettaErr.Retriable) || transientError(err) || f.forceRetry) &&
 			!errors.Is(err, context.Canceled),
 	}
 }
 
+// AccountBalanceFailure is the *Error returned when
+// fetching the balance of an account.
+type AccountBalanceFailure struct {
+	Account *types.AccountIdentifier
+	Err     *Error
+}
+
+// AccountBalancesError is returned by AccountBalancesRetry
+// when the balance of any account could not be fetched
+// (in the order the accounts were provided).
+type AccountBalancesError struct {
+	Failures []*AccountBalanceFailure
+}
+
+// Error returns a description of each failure.
+func (e *AccountBalancesError) Error() string {
+	messages := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		messages[i] = fmt.Sprintf(
+			"%s: %s",
+			types.PrintStruct(failure.Account),
+			failure.Err.Error(),
+		)
+	}
+
+	return fmt.Sprintf(
+		"%s for %d accounts: %s",
+		ErrAccountBalancesFailed.Error(),
+		len(e.Failures),
+		strings.Join(messages, "; "),
+	)
+}
+
+// Unwrap returns ErrAccountBalancesFailed (so that
+// errors.Is can be used on an *AccountBalancesError).
+func (e *AccountBalancesError) Unwrap() error {
+	return ErrAccountBalancesFailed
+}
+
 var (
 	// ErrNoNetworks is returned when there are no
 	// networks available for syncing.
 	ErrNoNetworks = errors.New("no networks available")
 
+	// ErrMultipleNetworks is returned when selecting a
+	// default network and more than one network is
+	// available.
+	ErrMultipleNetworks = errors.New("multiple networks available")
+
 	// ErrNetworkMissing is returned during asserter initialization
 	// when the provided *types.NetworkIdentifier is not in the
 	// *types.NetworkListResponse.
@@ -82,6 +196,10 @@ var (
 	// ErrCouldNotAcquireSemaphore is returned when acquiring
 	// the connection semaphore returns an error.
 	ErrCouldNotAcquireSemaphore = errors.New("could not acquire semaphore")
+
+	// ErrAccountBalancesFailed is returned when the balance of
+	// any account could not be fetched by AccountBalancesRetry.
+	ErrAccountBalancesFailed = errors.New("unable to fetch account balances")
 )
 
 // Err takes an error as an argument and returns
@@ -89,10 +207,12 @@ var (
 func Err(err error) bool {
 	fetcherErrors := []error{
 		ErrNoNetworks,
+		ErrMultipleNetworks,
 		ErrNetworkMissing,
 		ErrRequestFailed,
 		ErrExhaustedRetries,
 		ErrCouldNotAcquireSemaphore,
+		ErrAccountBalancesFailed,
 	}
 
 	return utils.FindError(fetcherErrors, err)
@@ -15,10 +15,17 @@
 package fetcher
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 func TestErr(t *testing.T) {
@@ -43,3 +50,176 @@ func TestErr(t *testing.T) {
 		})
 	}
 }
+
+func TestRosettaError(t *testing.T) {
+	rosettaErr := &types.Error{
+		Code:      12,
+		Message:   "invalid account",
+		Retriable: false,
+	}
+
+	var tests = map[string]struct {
+		clientErr  *types.Error
+		status     int
+		closed     bool
+		maxRetries uint64
+
+		expectedRequests int
+		expectedError    error
+		expectedClient   *types.Error
+	}{
+		"non-retriable error": {
+			clientErr:        rosettaErr,
+			status:           http.StatusInternalServerError,
+			maxRetries:       5,
+			expectedRequests: 1,
+			expectedError:    ErrRequestFailed,
+			expectedClient:   rosettaErr,
+		},
+		"retriable error": {
+			clientErr: &types.Error{
+				Code:      13,
+				Message:   "node unavailable",
+				Retriable: true,
+			},
+			status:           http.StatusInternalServerError,
+			maxRetries:       2,
+			expectedRequests: 3,
+			expectedError:    ErrExhaustedRetries,
+			expectedClient: &types.Error{
+				Code:      13,
+				Message:   "node unavailable",
+				Retriable: true,
+			},
+		},
+		"client error with 4xx status": {
+			clientErr:        rosettaErr,
+			status:           http.StatusBadRequest,
+			maxRetries:       5,
+			expectedRequests: 1,
+			expectedError:    ErrRequestFailed,
+			expectedClient:   rosettaErr,
+		},
+		"transport failure": {
+			closed:        true,
+			maxRetries:    2,
+			expectedError: ErrExhaustedRetries,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				w.WriteHeader(test.status)
+				fmt.Fprintln(w, types.PrettyPrintStruct(test.clientErr))
+			}))
+			if test.closed {
+				ts.Close()
+			} else {
+				defer ts.Close()
+			}
+
+			clock := &fakeClock{}
+			f := New(ts.URL, WithMaxRetries(test.maxRetries))
+			f.clock = clock
+
+			status, fetchErr := f.NetworkStatusRetry(context.Background(), basicNetwork, nil)
+			assert.Nil(t, status)
+			assert.Equal(t, test.expectedRequests, requests)
+			assert.True(t, errors.Is(fetchErr, test.expectedError))
+			assert.Equal(t, test.expectedClient, fetchErr.ClientErr)
+			assert.Equal(t, test.status, fetchErr.StatusCode)
+
+			var typedErr *RosettaError
+			if test.expectedClient == nil {
+				assert.False(t, errors.As(fetchErr, &typedErr))
+				assert.Len(t, clock.sleeps, int(test.maxRetries))
+				return
+			}
+
+			assert.True(t, errors.As(fetchErr, &typedErr))
+			assert.Equal(t, test.expectedClient, typedErr.Err)
+			assert.Equal(t, test.status, typedErr.StatusCode)
+			assert.Equal(t, test.expectedClient.Retriable, typedErr.Err.Retriable)
+			assert.Contains(t, typedErr.Error(), test.expectedClient.Message)
+		})
+	}
+}
+
+func TestRosettaErrorConformance(t *testing.T) {
+	catalog := []*types.Error{
+		{
+			Code:      12,
+			Message:   "invalid account",
+			Retriable: false,
+		},
+	}
+
+	var tests = map[string]struct {
+		clientErr     *types.Error
+		status        int
+		expectedError error
+	}{
+		"known error": {
+			clientErr: catalog[0],
+			status:    http.StatusInternalServerError,
+		},
+		"unknown code": {
+			clientErr: &types.Error{
+				Code:    13,
+				Message: "node unavailable",
+			},
+			status:        http.StatusInternalServerError,
+			expectedError: asserter.ErrErrorUnexpectedCode,
+		},
+		"retriable mismatch": {
+			clientErr: &types.Error{
+				Code:      12,
+				Message:   "invalid account",
+				Retriable: true,
+			},
+			status:        http.StatusInternalServerError,
+			expectedError: asserter.ErrErrorRetriableMismatch,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				w.WriteHeader(test.status)
+				fmt.Fprintln(w, types.PrettyPrintStruct(test.clientErr))
+			}))
+			defer ts.Close()
+
+			a, err := asserter.NewClientWithOptions(
+				basicNetwork,
+				&types.BlockIdentifier{
+					Index: 0,
+					Hash:  "block 0",
+				},
+				basicNetworkOptions.Allow.OperationTypes,
+				basicNetworkOptions.Allow.OperationStatuses,
+				catalog,
+				nil,
+			)
+			assert.NoError(t, err)
+
+			f := New(ts.URL, WithAsserter(a))
+
+			_, fetchErr := f.NetworkStatus(context.Background(), basicNetwork, nil)
+			assert.True(t, errors.Is(fetchErr, ErrRequestFailed))
+			assert.Equal(t, test.clientErr, fetchErr.ClientErr)
+			assert.Equal(t, test.status, fetchErr.StatusCode)
+			if test.expectedError == nil {
+				assert.NoError(t, fetchErr.ClientErrAssertion)
+				return
+			}
+
+			assert.True(t, errors.Is(fetchErr.ClientErrAssertion, test.expectedError))
+		})
+	}
+}
@@ -30,6 +30,8 @@ func (f *Fetcher) EventsBlocks(
 	offset *int64,
 	limit *int64,
 ) (int64, []*types.BlockEvent, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return -1, nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -37,7 +39,10 @@ func (f *Fetcher) EventsBlocks(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.EventsAPI.EventsBlocks(ctx,
+	requestCtx, cancel := f.requestContext(ctx, EventsEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.EventsAPI.EventsBlocks(requestCtx,
 		&types.EventsBlocksRequest{
 			NetworkIdentifier: network,
 			Offset:            offset,
@@ -49,7 +54,9 @@ func (f *Fetcher) EventsBlocks(
 	}
 
 	if err := asserter.EventsBlocksResponse(
+		response.MaxSequence,
 		response,
+		offset,
 	); err != nil {
 		fetcherErr := &Error{
 			Err: fmt.Errorf(
@@ -71,10 +78,8 @@ func (f *Fetcher) EventsBlocksRetry(
 	offset *int64,
 	limit *int64,
 ) (int64, []*types.BlockEvent, *Error) {
-	backoffRetries := backoffRetries(
-		f.retryElapsedTime,
-		f.maxRetries,
-	)
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
 
 	for {
 		maxSequence, events, err := f.EventsBlocks(
@@ -95,13 +100,15 @@ func (f *Fetcher) EventsBlocksRetry(
 
 		if is, _ := asserter.Err(err.Err); is {
 			fetcherErr := &Error{
-				Err:       fmt.Errorf("%w: /events/blocks not attempting retry", err.Err),
-				ClientErr: err.ClientErr,
+				Err:        fmt.Errorf("%w: /events/blocks not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
 			}
 			return -1, nil, fetcherErr
 		}
 
 		if err := tryAgain(
+			ctx,
 			fmt.Sprintf("/events/blocks %+v %+v", offset, limit),
 			backoffRetries,
 			err,
@@ -110,3 +117,43 @@ func (f *Fetcher) EventsBlocksRetry(
 		}
 	}
 }
+
+// EventsBlocksIterate fetches all *types.BlockEvent starting
+// at offset (using EventsBlocksRetry) and invokes handler
+// with each page of events until the MaxSequence returned by
+// the server has been reached. If handler returns an error,
+// iteration stops and the error is returned.
+func (f *Fetcher) EventsBlocksIterate(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	offset int64,
+	limit *int64,
+	handler func(maxSequence int64, events []*types.BlockEvent) error,
+) *Error {
+	for {
+		maxSequence, events, err := f.EventsBlocksRetry(
+			ctx,
+			network,
+			&offset,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		if err := handler(maxSequence, events); err != nil {
+			return &Error{
+				Err: fmt.Errorf("%w: /events/blocks handler failed", err),
+			}
+		}
+
+		offset = events[len(events)-1].Sequence + 1
+		if offset > maxSequence {
+			return nil
+		}
+	}
+}
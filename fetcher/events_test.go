@@ -17,6 +17,7 @@ package fetcher
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -25,6 +26,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -165,6 +167,122 @@ func TestEventsBlocksRetry(t *testing.T) {
 			assert.Equal(test.expectedMaxSeq, maxSeq)
 			assert.Equal(test.expectedEvents, events)
 			assert.True(checkError(err, test.expectedError))
+			assertStatusCode(t, err, test.expectedError, http.StatusInternalServerError)
+		})
+	}
+}
+
+func TestEventsBlocksIterate(t *testing.T) {
+	var (
+		limit  = int64(3)
+		events = []*types.BlockEvent{}
+	)
+	for i := int64(0); i < 8; i++ {
+		events = append(events, &types.BlockEvent{
+			Sequence: i,
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: i,
+				Hash:  fmt.Sprintf("block %d", i),
+			},
+			Type: types.ADDED,
+		})
+	}
+
+	var tests = map[string]struct {
+		offset       int64
+		skipSequence bool
+		handlerErr   error
+
+		expectedEvents []*types.BlockEvent
+		expectedPages  int
+		expectedError  error
+	}{
+		"all events": {
+			expectedEvents: events,
+			expectedPages:  3,
+		},
+		"from offset": {
+			offset:         5,
+			expectedEvents: events[5:],
+			expectedPages:  1,
+		},
+		"offset past max sequence": {
+			offset:         10,
+			expectedEvents: []*types.BlockEvent{},
+		},
+		"sequence mismatch": {
+			skipSequence:   true,
+			expectedEvents: []*types.BlockEvent{},
+			expectedError:  asserter.ErrSequenceStartMismatch,
+		},
+		"handler error": {
+			handlerErr:     errors.New("handler failed"),
+			expectedEvents: events[:3],
+			expectedPages:  1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				assert = assert.New(t)
+				ctx    = context.Background()
+			)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req *types.EventsBlocksRequest
+				assert.NoError(json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(limit, *req.Limit)
+
+				start := *req.Offset
+				if test.skipSequence {
+					start++
+				}
+
+				page := []*types.BlockEvent{}
+				for i := start; i < start+limit && i < int64(len(events)); i++ {
+					page = append(page, events[i])
+				}
+
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, types.PrettyPrintStruct(
+					&types.EventsBlocksResponse{
+						MaxSequence: int64(len(events) - 1),
+						Events:      page,
+					},
+				))
+			}))
+
+			defer ts.Close()
+
+			f := New(
+				ts.URL,
+				WithRetryElapsedTime(5*time.Second),
+				WithMaxRetries(1),
+			)
+
+			seen := []*types.BlockEvent{}
+			pages := 0
+			err := f.EventsBlocksIterate(
+				ctx,
+				basicNetwork,
+				test.offset,
+				&limit,
+				func(maxSequence int64, page []*types.BlockEvent) error {
+					assert.Equal(int64(len(events)-1), maxSequence)
+					seen = append(seen, page...)
+					pages++
+
+					return test.handlerErr
+				},
+			)
+			assert.Equal(test.expectedEvents, seen)
+			assert.Equal(test.expectedPages, pages)
+			if test.handlerErr != nil {
+				assert.True(checkError(err, test.handlerErr))
+			} else {
+				assert.True(checkError(err, test.expectedError))
+			}
 		})
 	}
 }
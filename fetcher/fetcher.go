@@ -19,7 +19,9 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"golang.org/x/sync/semaphore"
@@ -38,6 +40,19 @@ const (
 	// attempt a retry on a failed request.
 	DefaultRetries = 10
 
+	// DefaultRetryBase is the default base delay
+	// between retries of a failed request.
+	DefaultRetryBase = 500 * time.Millisecond
+
+	// DefaultRetryMultiplier is the default factor the
+	// delay between retries is multiplied by after each
+	// retry.
+	DefaultRetryMultiplier = float64(2) // nolint:gomnd
+
+	// DefaultRetryCap is the default maximum delay
+	// between retries of a failed request.
+	DefaultRetryCap = 30 * time.Second
+
 	// DefaultHTTPTimeout is the default timeout for
 	// HTTP requests.
 	DefaultHTTPTimeout = 10 * time.Second
@@ -61,19 +76,110 @@ const (
 	semaphoreRequestWeight = int64(1)
 )
 
+// Endpoint is a class of Rosetta API endpoints that
+// can be assigned its own request timeout.
+type Endpoint string
+
+const (
+	// BlockEndpoint covers /block and /block/transaction.
+	BlockEndpoint Endpoint = "block"
+
+	// AccountEndpoint covers /account/balance and
+	// /account/coins.
+	AccountEndpoint Endpoint = "account"
+
+	// ConstructionEndpoint covers all /construction/*
+	// endpoints.
+	ConstructionEndpoint Endpoint = "construction"
+
+	// NetworkEndpoint covers /network/list, /network/status,
+	// and /network/options.
+	NetworkEndpoint Endpoint = "network"
+
+	// MempoolEndpoint covers /mempool and
+	// /mempool/transaction.
+	MempoolEndpoint Endpoint = "mempool"
+
+	// CallEndpoint covers /call.
+	CallEndpoint Endpoint = "call"
+
+	// EventsEndpoint covers /events/blocks.
+	EventsEndpoint Endpoint = "events"
+
+	// SearchEndpoint covers /search/transactions.
+	SearchEndpoint Endpoint = "search"
+)
+
 // Fetcher contains all logic to communicate with a Rosetta Server.
 type Fetcher struct {
 	// Asserter is a public variable because
 	// it can be used to determine if a retrieved
 	// types.Operation is successful and should
 	// be applied.
+	//
+	// If RefreshAsserter may be called concurrently,
+	// Asserter must not be modified directly.
 	Asserter         *asserter.Asserter
+	asserterLock     sync.RWMutex
+	asserterOptions  []asserter.Option
 	rosettaClient    *client.APIClient
 	maxConnections   int
 	maxRetries       uint64
 	retryElapsedTime time.Duration
 	insecureTLS      bool
 	forceRetry       bool
+	headers          map[string]string
+	compression      bool
+
+	requestInterceptors  []client.RequestInterceptor
+	responseInterceptors []client.ResponseInterceptor
+
+	// Transport settings used when the fetcher
+	// constructs its own *http.Client (ignored
+	// if WithClient is provided).
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	disableCompression  bool
+	tlsConfig           *tls.Config
+	http2               *bool
+
+	// If asserterRefreshInterval > 0, the asserter
+	// is refreshed (at most once per interval) when
+	// a block contains an unknown operation type or
+	// status.
+	asserterRefreshInterval time.Duration
+	asserterRefreshLock     sync.Mutex
+	lastAsserterRefresh     time.Time
+
+	// observer is notified of every
+	// request (if not nil).
+	observer FetcherObserver
+
+	// blockCache stores blocks fetched by BlockRetry
+	// (if not nil).
+	blockCache *blockCache
+
+	// network is the *types.NetworkIdentifier selected
+	// by InitializeDefaultNetwork. It is used by any
+	// request not provided a *types.NetworkIdentifier.
+	network *types.NetworkIdentifier
+
+	// endpointTimeouts limits the duration of each
+	// request made to a class of endpoints. When endpoint
+	// timeouts are set, the timeout of the HTTP client is
+	// moved to defaultTimeout (so that longer endpoint
+	// timeouts apply) and used for all other endpoints.
+	endpointTimeouts map[Endpoint]time.Duration
+	defaultTimeout   time.Duration
+
+	// Retries are delayed using exponential backoff
+	// (starting at retryBase and multiplied by
+	// retryMultiplier up to retryCap) with full jitter.
+	retryBase       time.Duration
+	retryMultiplier float64
+	retryCap        time.Duration
+	clock           clock
+	jitter          func(time.Duration) time.Duration
 
 	// connectionSemaphore is used to limit the
 	// number of concurrent requests we make.
@@ -86,9 +192,16 @@ func New(
 	options ...Option,
 ) *Fetcher {
 	f := &Fetcher{
-		maxConnections:   DefaultMaxConnections,
-		maxRetries:       DefaultRetries,
-		retryElapsedTime: DefaultElapsedTime,
+		maxConnections:      DefaultMaxConnections,
+		idleConnTimeout:     DefaultIdleConnTimeout,
+		maxIdleConnsPerHost: DefaultMaxConnections,
+		maxRetries:          DefaultRetries,
+		retryElapsedTime:    DefaultElapsedTime,
+		retryBase:           DefaultRetryBase,
+		retryMultiplier:     DefaultRetryMultiplier,
+		retryCap:            DefaultRetryCap,
+		clock:               realClock{},
+		jitter:              fullJitter,
 	}
 
 	// Override defaults with any provided options
@@ -102,9 +215,26 @@ func New(
 		// See this conversation around why `.Clone()` is used here:
 		// https://github.com/golang/go/issues/26013
 		defaultTransport := http.DefaultTransport.(*http.Transport).Clone()
-		defaultTransport.IdleConnTimeout = DefaultIdleConnTimeout
+		defaultTransport.IdleConnTimeout = f.idleConnTimeout
 		defaultTransport.MaxIdleConns = f.maxConnections
-		defaultTransport.MaxIdleConnsPerHost = DefaultMaxConnections
+		defaultTransport.MaxIdleConnsPerHost = f.maxIdleConnsPerHost
+		defaultTransport.DisableCompression = f.disableCompression
+		if f.tlsConfig != nil {
+			defaultTransport.TLSClientConfig = f.tlsConfig.Clone()
+		}
+
+		// HTTP/2 is disabled by providing a non-nil,
+		// empty TLSNextProto.
+		if f.http2 != nil {
+			defaultTransport.ForceAttemptHTTP2 = *f.http2
+			if !*f.http2 {
+				defaultTransport.TLSNextProto = map[string]func(
+					string,
+					*tls.Conn,
+				) http.RoundTripper{}
+			}
+		}
+
 		defaultHTTPClient := &http.Client{
 			Timeout:   DefaultHTTPTimeout,
 			Transport: defaultTransport,
@@ -117,20 +247,112 @@ func New(
 			defaultHTTPClient,
 		)
 		f.rosettaClient = client.NewAPIClient(clientCfg)
+	} else {
+		f.rosettaClient = copyClient(f.rosettaClient)
+	}
+
+	// Add headers to all requests (including retries)
+	for header, value := range f.headers {
+		f.rosettaClient.GetConfig().AddDefaultHeader(header, value)
 	}
 
+	if f.compression {
+		f.rosettaClient.GetConfig().Compression = true
+	}
+
+	// Interceptors are appended to any interceptors
+	// already set on a provided client. The chain is built
+	// in new slices so that the backing arrays of a provided
+	// client are never shared (or written to).
+	cfg := f.rosettaClient.GetConfig()
+	requestInterceptors := make(
+		[]client.RequestInterceptor,
+		0,
+		len(cfg.RequestInterceptors)+len(f.requestInterceptors),
+	)
+	requestInterceptors = append(requestInterceptors, cfg.RequestInterceptors...)
+	cfg.RequestInterceptors = append(requestInterceptors, f.requestInterceptors...)
+
+	responseInterceptors := make(
+		[]client.ResponseInterceptor,
+		0,
+		len(cfg.ResponseInterceptors)+len(f.responseInterceptors),
+	)
+	responseInterceptors = append(responseInterceptors, cfg.ResponseInterceptors...)
+	cfg.ResponseInterceptors = append(responseInterceptors, f.responseInterceptors...)
+
 	if f.insecureTLS {
-		if transport, ok := f.rosettaClient.GetConfig().HTTPClient.Transport.(*http.Transport); ok {
-			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402
+		if transport, ok := cfg.HTTPClient.Transport.(*http.Transport); ok {
+			// Preserve any custom TLS config (on a copy of
+			// the HTTP client and transport so that a provided
+			// client is not modified).
+			tlsConfig := &tls.Config{} // #nosec G402
+			if transport.TLSClientConfig != nil {
+				tlsConfig = transport.TLSClientConfig.Clone()
+			}
+
+			tlsConfig.InsecureSkipVerify = true // #nosec G402
+			insecureTransport := transport.Clone()
+			insecureTransport.TLSClientConfig = tlsConfig
+
+			httpClient := *cfg.HTTPClient
+			httpClient.Transport = insecureTransport
+			cfg.HTTPClient = &httpClient
 		}
 	}
 
+	// Observe all requests (wrapping the transport
+	// of a copy of the HTTP client so that a provided
+	// client is not modified)
+	if f.observer != nil {
+		transport := cfg.HTTPClient.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+
+		httpClient := *cfg.HTTPClient
+		httpClient.Transport = &observedTransport{
+			next:     transport,
+			observer: f.observer,
+		}
+		cfg.HTTPClient = &httpClient
+	}
+
+	// The HTTP client timeout applies to every request
+	// (regardless of the context), so it is replaced by
+	// a context timeout for endpoints without their own
+	// timeout (on a copy of the HTTP client so that a
+	// provided client is not modified).
+	if len(f.endpointTimeouts) > 0 && cfg.HTTPClient.Timeout > 0 {
+		f.defaultTimeout = cfg.HTTPClient.Timeout
+
+		httpClient := *cfg.HTTPClient
+		httpClient.Timeout = 0
+		cfg.HTTPClient = &httpClient
+	}
+
 	// Initialize the connection semaphore
 	f.connectionSemaphore = semaphore.NewWeighted(int64(f.maxConnections))
 
 	return f
 }
 
+// copyClient returns a new *client.APIClient with a shallow
+// copy of the configuration of a provided client (including its
+// default headers) so that the Fetcher options don't modify the
+// provided client. The HTTP client is shared until an option
+// needs to modify it.
+func copyClient(provided *client.APIClient) *client.APIClient {
+	providedCfg := provided.GetConfig()
+	cfg := *providedCfg
+	cfg.DefaultHeader = make(map[string]string, len(providedCfg.DefaultHeader))
+	for header, value := range providedCfg.DefaultHeader {
+		cfg.DefaultHeader[header] = value
+	}
+
+	return client.NewAPIClient(&cfg)
+}
+
 // InitializeAsserter creates an Asserter for
 // validating responses. The Asserter is created
 // by fetching the NetworkStatus and NetworkOptions
@@ -151,7 +373,7 @@ func (f *Fetcher) InitializeAsserter(
 	*types.NetworkStatusResponse,
 	*Error,
 ) {
-	if f.Asserter != nil {
+	if f.currentAsserter() != nil {
 		return nil, nil, &Error{Err: errors.New("asserter already initialized")}
 	}
 
@@ -182,10 +404,81 @@ func (f *Fetcher) InitializeAsserter(
 		primaryNetwork = networkIdentifier
 	}
 
+	networkStatus, _, err := f.initializeAsserter(ctx, primaryNetwork)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return primaryNetwork, networkStatus, nil
+}
+
+// InitializeDefaultNetwork creates an Asserter for
+// validating responses (like InitializeAsserter) using
+// the only network returned by NetworkList. If zero or
+// multiple networks are returned, an error is returned.
+//
+// The selected network is used by any subsequent request
+// that is not provided a *types.NetworkIdentifier.
+func (f *Fetcher) InitializeDefaultNetwork(
+	ctx context.Context,
+) (
+	*types.NetworkIdentifier,
+	*types.NetworkStatusResponse,
+	*types.NetworkOptionsResponse,
+	error,
+) {
+	if f.currentAsserter() != nil {
+		return nil, nil, nil, &Error{Err: errors.New("asserter already initialized")}
+	}
+
+	networkList, err := f.NetworkListRetry(ctx, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	switch len(networkList.NetworkIdentifiers) {
+	case 0:
+		return nil, nil, nil, &Error{Err: ErrNoNetworks}
+	case 1:
+	default:
+		return nil, nil, nil, &Error{
+			Err: fmt.Errorf(
+				"%w: %s",
+				ErrMultipleNetworks,
+				types.PrintStruct(networkList.NetworkIdentifiers),
+			),
+		}
+	}
+
+	network := networkList.NetworkIdentifiers[0]
+	networkStatus, networkOptions, err := f.initializeAsserter(ctx, network)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	f.network = network
+
+	return network, networkStatus, networkOptions, nil
+}
+
+// Network returns the *types.NetworkIdentifier selected
+// by InitializeDefaultNetwork (nil if it has not been
+// called).
+func (f *Fetcher) Network() *types.NetworkIdentifier {
+	return f.network
+}
+
+// initializeAsserter fetches the NetworkStatus and
+// NetworkOptions of network and uses them to create
+// the fetcher's Asserter.
+func (f *Fetcher) initializeAsserter(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+) (*types.NetworkStatusResponse, *types.NetworkOptionsResponse, *Error) {
 	// Attempt to fetch network status
 	networkStatus, err := f.NetworkStatusRetry(
 		ctx,
-		primaryNetwork,
+		network,
 		nil,
 	)
 	if err != nil {
@@ -195,7 +488,7 @@ func (f *Fetcher) InitializeAsserter(
 	// Attempt to fetch network options
 	networkOptions, err := f.NetworkOptionsRetry(
 		ctx,
-		primaryNetwork,
+		network,
 		nil,
 	)
 	if err != nil {
@@ -203,14 +496,124 @@ func (f *Fetcher) InitializeAsserter(
 	}
 
 	newAsserter, assertErr := asserter.NewClientWithResponses(
-		primaryNetwork,
+		network,
 		networkStatus,
 		networkOptions,
+		f.asserterOptions...,
 	)
 	if assertErr != nil {
 		return nil, nil, &Error{Err: assertErr}
 	}
-	f.Asserter = newAsserter
+	f.setAsserter(newAsserter)
 
-	return primaryNetwork, networkStatus, nil
+	return networkStatus, networkOptions, nil
+}
+
+// RefreshAsserter re-fetches the NetworkStatus and
+// NetworkOptions of network (or the network selected by
+// InitializeDefaultNetwork if nil) and replaces the Asserter
+// used to validate subsequent responses. This should be
+// called when a node is upgraded and supports new operation
+// types or statuses.
+//
+// Any concurrent request is validated with either the
+// old or the new Asserter.
+func (f *Fetcher) RefreshAsserter(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+) *Error {
+	_, _, err := f.initializeAsserter(ctx, f.defaultNetwork(network))
+	return err
+}
+
+// currentAsserter returns the Asserter used
+// to validate responses.
+func (f *Fetcher) currentAsserter() *asserter.Asserter {
+	f.asserterLock.RLock()
+	defer f.asserterLock.RUnlock()
+
+	return f.Asserter
+}
+
+// setAsserter replaces the Asserter used
+// to validate responses.
+func (f *Fetcher) setAsserter(a *asserter.Asserter) {
+	f.asserterLock.Lock()
+	defer f.asserterLock.Unlock()
+
+	f.Asserter = a
+}
+
+// validateBlock validates block with the current Asserter.
+// If the block contains an unknown operation type or status
+// and automatic refreshes are enabled, the Asserter is
+// refreshed (at most once per asserterRefreshInterval) and
+// the block is validated again.
+func (f *Fetcher) validateBlock(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	block *types.Block,
+) error {
+	staleAsserter := f.currentAsserter()
+	err := staleAsserter.Block(block)
+	if err == nil || f.asserterRefreshInterval <= 0 {
+		return err
+	}
+
+	if !errors.Is(err, asserter.ErrOperationTypeInvalid) &&
+		!errors.Is(err, asserter.ErrOperationStatusInvalid) {
+		return err
+	}
+
+	if !f.refreshStaleAsserter(ctx, network, staleAsserter) {
+		return err
+	}
+
+	return f.currentAsserter().Block(block)
+}
+
+// refreshStaleAsserter refreshes the Asserter if it has not
+// been replaced since staleAsserter was used and the last
+// refresh was at least asserterRefreshInterval ago. It returns
+// a boolean indicating if a new Asserter is available.
+func (f *Fetcher) refreshStaleAsserter(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	staleAsserter *asserter.Asserter,
+) bool {
+	f.asserterRefreshLock.Lock()
+	defer f.asserterRefreshLock.Unlock()
+
+	// Another request already refreshed
+	// the Asserter.
+	if f.currentAsserter() != staleAsserter {
+		return true
+	}
+
+	now := f.clock.Now()
+	if !f.lastAsserterRefresh.IsZero() &&
+		now.Sub(f.lastAsserterRefresh) < f.asserterRefreshInterval {
+		return false
+	}
+	f.lastAsserterRefresh = now
+
+	if err := f.RefreshAsserter(ctx, network); err != nil {
+		log.Printf("unable to refresh asserter: %s\n", err.Error())
+		return false
+	}
+
+	return true
+}
+
+// defaultNetwork returns network if it is not nil.
+// Otherwise, it returns the network selected by
+// InitializeDefaultNetwork.
+func (f *Fetcher) defaultNetwork(
+	network *types.NetworkIdentifier,
+) *types.NetworkIdentifier {
+	if network != nil {
+		return network
+	}
+
+	return f.network
 }
@@ -15,15 +15,21 @@
 package fetcher
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/client"
@@ -45,6 +51,18 @@ var (
 		},
 	}
 
+	genesisNetworkStatus = &types.NetworkStatusResponse{
+		CurrentBlockIdentifier: &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		CurrentBlockTimestamp: 0,
+		GenesisBlockIdentifier: &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+	}
+
 	complexNetworkList = &types.NetworkListResponse{
 		NetworkIdentifiers: []*types.NetworkIdentifier{
 			basicNetwork,
@@ -76,6 +94,7 @@ func TestInitializeAsserter(t *testing.T) {
 		networkList    *types.NetworkListResponse
 		networkStatus  *types.NetworkStatusResponse
 		networkOptions *types.NetworkOptionsResponse
+		options        []Option
 
 		expectedNetwork *types.NetworkIdentifier
 		expectedStatus  *types.NetworkStatusResponse
@@ -113,6 +132,28 @@ func TestInitializeAsserter(t *testing.T) {
 			expectedNetwork: otherNetwork,
 			expectedStatus:  otherNetworkStatus,
 		},
+		"zero timestamp genesis": {
+			networkRequest: &types.NetworkRequest{
+				NetworkIdentifier: basicNetwork,
+			},
+			networkList:    basicNetworkList,
+			networkStatus:  genesisNetworkStatus,
+			networkOptions: basicNetworkOptions,
+			options: []Option{
+				WithAsserterOptions(asserter.WithGenesisTimestampExemption(true)),
+			},
+			expectedNetwork: basicNetwork,
+			expectedStatus:  genesisNetworkStatus,
+		},
+		"zero timestamp genesis without options": {
+			networkRequest: &types.NetworkRequest{
+				NetworkIdentifier: basicNetwork,
+			},
+			networkList:    basicNetworkList,
+			networkStatus:  genesisNetworkStatus,
+			networkOptions: basicNetworkOptions,
+			expectedError:  asserter.ErrTimestampBeforeMin,
+		},
 		"no networks": {
 			network: otherNetwork,
 			networkRequest: &types.NetworkRequest{
@@ -186,7 +227,7 @@ func TestInitializeAsserter(t *testing.T) {
 
 			f := New(
 				ts.URL,
-				WithRetryElapsedTime(5*time.Second),
+				append([]Option{WithRetryElapsedTime(5 * time.Second)}, test.options...)...,
 			)
 
 			networkIdentifier, networkStatus, err := f.InitializeAsserter(ctx, test.network)
@@ -214,3 +255,814 @@ func TestNewWithHTTPCLient(t *testing.T) {
 	var assert = assert.New(t)
 	assert.Same(httpClient, fetcher.rosettaClient.GetConfig().HTTPClient)
 }
+
+func TestNewWithClientNotModified(t *testing.T) {
+	// Options applied by the fetcher must not modify
+	// the configuration of a client provided via WithClient.
+	var assert = assert.New(t)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	httpClient := &http.Client{Transport: transport}
+	cfg := client.NewConfiguration(
+		"https://serveraddress",
+		DefaultUserAgent,
+		httpClient,
+	)
+	cfg.AddDefaultHeader("X-Api-Key", "provided")
+	apiClient := client.NewAPIClient(cfg)
+
+	f := New(
+		"https://serveraddress",
+		WithClient(apiClient),
+		WithHeaders(map[string]string{"Authorization": "Bearer token"}),
+		WithCompression(),
+		WithInsecureTLS(),
+	)
+
+	fetcherCfg := f.rosettaClient.GetConfig()
+	assert.NotSame(cfg, fetcherCfg)
+	assert.Equal(map[string]string{
+		"X-Api-Key":     "provided",
+		"Authorization": "Bearer token",
+	}, fetcherCfg.DefaultHeader)
+	assert.True(fetcherCfg.Compression)
+	fetcherTransport, ok := fetcherCfg.HTTPClient.Transport.(*http.Transport)
+	assert.True(ok)
+	assert.True(fetcherTransport.TLSClientConfig.InsecureSkipVerify)
+
+	assert.Equal(map[string]string{"X-Api-Key": "provided"}, cfg.DefaultHeader)
+	assert.False(cfg.Compression)
+	assert.Same(httpClient, cfg.HTTPClient)
+	assert.Same(transport, httpClient.Transport)
+
+	// net/http may populate TLSClientConfig on its own,
+	// so we only check the field WithInsecureTLS sets.
+	if transport.TLSClientConfig != nil {
+		assert.False(transport.TLSClientConfig.InsecureSkipVerify)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		ctx      = context.Background()
+		requests = map[string][]http.Header{}
+		tries    = 0
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.RequestURI()] = append(requests[r.URL.RequestURI()], r.Header.Clone())
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+		// Fail the first request to ensure
+		// headers are added to retries.
+		if tries == 0 {
+			tries++
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintln(w, types.PrettyPrintStruct(&types.Error{
+				Retriable: true,
+			}))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.RequestURI() {
+		case "/network/list":
+			fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkList))
+		case "/block":
+			fmt.Fprintln(w, types.PrettyPrintStruct(&types.BlockResponse{
+				Block: basicFullBlock,
+			}))
+		}
+	}))
+	defer ts.Close()
+
+	a, err := asserter.NewClientWithOptions(
+		basicNetwork,
+		&types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		basicNetworkOptions.Allow.OperationTypes,
+		basicNetworkOptions.Allow.OperationStatuses,
+		nil,
+		nil,
+	)
+	assert.NoError(err)
+
+	f := New(
+		ts.URL,
+		WithRetryElapsedTime(5*time.Second),
+		WithRetryBackoff(time.Millisecond, 2, 10*time.Millisecond),
+		WithAsserter(a),
+		WithHeaders(map[string]string{
+			"Authorization": "Bearer token",
+			"X-Api-Key":     "default",
+		}),
+	)
+
+	networkList, fetchErr := f.NetworkListRetry(ctx, nil)
+	assert.Nil(fetchErr)
+	assert.Equal(basicNetworkList, networkList)
+
+	// Override headers for a single call
+	callCtx := context.WithValue(ctx, client.ContextHeaders, map[string]string{
+		"X-Api-Key": "tenant",
+	})
+	block, fetchErr := f.BlockRetry(
+		callCtx,
+		basicNetwork,
+		types.ConstructPartialBlockIdentifier(basicFullBlock.BlockIdentifier),
+	)
+	assert.Nil(fetchErr)
+	assert.Equal(basicFullBlock, block)
+
+	assert.Len(requests["/network/list"], 2)
+	for _, headers := range requests["/network/list"] {
+		assert.Equal("Bearer token", headers.Get("Authorization"))
+		assert.Equal("default", headers.Get("X-Api-Key"))
+		assert.Equal(DefaultUserAgent, headers.Get("User-Agent"))
+	}
+
+	assert.Len(requests["/block"], 1)
+	for _, headers := range requests["/block"] {
+		assert.Equal("Bearer token", headers.Get("Authorization"))
+		assert.Equal([]string{"tenant"}, headers["X-Api-Key"])
+	}
+}
+
+func TestEndpointTimeouts(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		responseDelay  = 200 * time.Millisecond
+		shortTimeout   = 50 * time.Millisecond
+		clientTimeout  = 100 * time.Millisecond
+		longTimeout    = 2 * time.Second
+		blockRequested = types.ConstructPartialBlockIdentifier(basicFullBlock.BlockIdentifier)
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(responseDelay)
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.RequestURI() {
+		case "/network/status":
+			fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkStatus))
+		case "/block":
+			fmt.Fprintln(w, types.PrettyPrintStruct(&types.BlockResponse{
+				Block: basicFullBlock,
+			}))
+		}
+	}))
+	defer ts.Close()
+
+	a, err := asserter.NewClientWithOptions(
+		basicNetwork,
+		&types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		basicNetworkOptions.Allow.OperationTypes,
+		basicNetworkOptions.Allow.OperationStatuses,
+		nil,
+		nil,
+	)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		options []Option
+
+		blockTimeout  bool
+		statusTimeout bool
+	}{
+		"defaults": {},
+		"short status timeout": {
+			options: []Option{
+				WithEndpointTimeout(BlockEndpoint, longTimeout),
+				WithEndpointTimeout(NetworkEndpoint, shortTimeout),
+			},
+			statusTimeout: true,
+		},
+		"short block timeout": {
+			options: []Option{
+				WithEndpointTimeout(BlockEndpoint, shortTimeout),
+				WithEndpointTimeout(NetworkEndpoint, longTimeout),
+			},
+			blockTimeout: true,
+		},
+		"unrelated endpoint timeout": {
+			options: []Option{
+				WithEndpointTimeout(MempoolEndpoint, shortTimeout),
+			},
+		},
+		"endpoint timeout above client timeout": {
+			options: []Option{
+				WithClient(client.NewAPIClient(client.NewConfiguration(
+					ts.URL,
+					DefaultUserAgent,
+					&http.Client{Timeout: clientTimeout},
+				))),
+				WithEndpointTimeout(BlockEndpoint, longTimeout),
+			},
+			statusTimeout: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			f := New(ts.URL, append(test.options, WithAsserter(a))...)
+
+			block, fetchErr := f.Block(ctx, basicNetwork, blockRequested)
+			if test.blockTimeout {
+				assert.Nil(block)
+				assert.NotNil(fetchErr)
+				assert.Contains(fetchErr.Err.Error(), context.DeadlineExceeded.Error())
+				assert.True(fetchErr.Retry)
+			} else {
+				assert.Nil(fetchErr)
+				assert.Equal(basicFullBlock, block)
+			}
+
+			status, fetchErr := f.NetworkStatus(ctx, basicNetwork, nil)
+			if test.statusTimeout {
+				assert.Nil(status)
+				assert.NotNil(fetchErr)
+				assert.Contains(fetchErr.Err.Error(), context.DeadlineExceeded.Error())
+				assert.True(fetchErr.Retry)
+			} else {
+				assert.Nil(fetchErr)
+				assert.Equal(basicNetworkStatus, status)
+			}
+		})
+	}
+}
+
+func TestInitializeDefaultNetwork(t *testing.T) {
+	var tests = map[string]struct {
+		networkList *types.NetworkListResponse
+
+		expectedNetwork *types.NetworkIdentifier
+		expectedStatus  *types.NetworkStatusResponse
+		expectedOptions *types.NetworkOptionsResponse
+		expectedError   error
+		expectedMessage string
+	}{
+		"single network": {
+			networkList:     basicNetworkList,
+			expectedNetwork: basicNetwork,
+			expectedStatus:  basicNetworkStatus,
+			expectedOptions: basicNetworkOptions,
+		},
+		"no networks": {
+			networkList:   &types.NetworkListResponse{},
+			expectedError: ErrNoNetworks,
+		},
+		"multiple networks": {
+			networkList:   complexNetworkList,
+			expectedError: ErrMultipleNetworks,
+			expectedMessage: fmt.Sprintf(
+				"multiple networks available: %s",
+				types.PrintStruct(complexNetworkList.NetworkIdentifiers),
+			),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				assert = assert.New(t)
+				ctx    = context.Background()
+			)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				w.WriteHeader(http.StatusOK)
+
+				var networkRequest *types.NetworkRequest
+				switch r.URL.RequestURI() {
+				case "/network/list":
+					fmt.Fprintln(w, types.PrettyPrintStruct(test.networkList))
+				case "/network/status":
+					assert.NoError(json.NewDecoder(r.Body).Decode(&networkRequest))
+					assert.Equal(basicNetwork, networkRequest.NetworkIdentifier)
+					fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkStatus))
+				case "/network/options":
+					assert.NoError(json.NewDecoder(r.Body).Decode(&networkRequest))
+					assert.Equal(basicNetwork, networkRequest.NetworkIdentifier)
+					fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkOptions))
+				case "/block":
+					var blockRequest *types.BlockRequest
+					assert.NoError(json.NewDecoder(r.Body).Decode(&blockRequest))
+					assert.Equal(basicNetwork, blockRequest.NetworkIdentifier)
+					fmt.Fprintln(w, types.PrettyPrintStruct(&types.BlockResponse{
+						Block: basicFullBlock,
+					}))
+				}
+			}))
+
+			defer ts.Close()
+
+			f := New(
+				ts.URL,
+				WithRetryElapsedTime(5*time.Second),
+			)
+
+			network, status, options, err := f.InitializeDefaultNetwork(ctx)
+			assert.Equal(test.expectedNetwork, network)
+			assert.Equal(test.expectedStatus, status)
+			assert.Equal(test.expectedOptions, options)
+			if test.expectedError != nil {
+				assert.True(errors.Is(err, test.expectedError))
+				if len(test.expectedMessage) > 0 {
+					assert.Equal(test.expectedMessage, err.Error())
+				}
+
+				assert.Nil(f.Asserter)
+				assert.Nil(f.Network())
+				return
+			}
+
+			assert.NoError(err)
+			assert.NotNil(f.Asserter)
+			assert.Equal(basicNetwork, f.Network())
+
+			// Requests without a network use the
+			// selected network.
+			block, fetchErr := f.BlockRetry(
+				ctx,
+				nil,
+				types.ConstructPartialBlockIdentifier(basicFullBlock.BlockIdentifier),
+			)
+			assert.Nil(fetchErr)
+			assert.Equal(basicFullBlock, block)
+		})
+	}
+}
+
+func TestTransportOptions(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		ctx    = context.Background()
+	)
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/network/list", r.URL.RequestURI())
+		assert.Empty(r.Header.Get("Accept-Encoding"))
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkList))
+	}))
+	defer ts.Close()
+
+	// Only trust the certificate of the test server
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ts.Certificate())
+	tlsConfig := &tls.Config{RootCAs: rootCAs} // #nosec G402
+
+	f := New(
+		ts.URL,
+		WithRetryElapsedTime(5*time.Second),
+		WithMaxIdleConnsPerHost(250),
+		WithIdleConnTimeout(5*time.Minute),
+		WithDisableCompression(),
+		WithTLSConfig(tlsConfig),
+		WithHTTP2(false),
+	)
+
+	transport, ok := f.rosettaClient.GetConfig().HTTPClient.Transport.(*http.Transport)
+	assert.True(ok)
+	assert.Equal(250, transport.MaxIdleConnsPerHost)
+	assert.Equal(DefaultMaxConnections, transport.MaxIdleConns)
+	assert.Equal(5*time.Minute, transport.IdleConnTimeout)
+	assert.True(transport.DisableCompression)
+	assert.Equal(rootCAs, transport.TLSClientConfig.RootCAs)
+	assert.False(transport.ForceAttemptHTTP2)
+	assert.NotNil(transport.TLSNextProto)
+	assert.Empty(transport.TLSNextProto)
+
+	networkList, err := f.NetworkListRetry(ctx, nil)
+	assert.Nil(err)
+	assert.Equal(basicNetworkList, networkList)
+
+	// Forcing HTTP/2 (with a custom TLS config)
+	f = New(
+		ts.URL,
+		WithTLSConfig(tlsConfig),
+		WithHTTP2(true),
+	)
+	transport, ok = f.rosettaClient.GetConfig().HTTPClient.Transport.(*http.Transport)
+	assert.True(ok)
+	assert.True(transport.ForceAttemptHTTP2)
+
+	// Defaults
+	f = New(ts.URL)
+	transport, ok = f.rosettaClient.GetConfig().HTTPClient.Transport.(*http.Transport)
+	assert.True(ok)
+	assert.Equal(DefaultMaxConnections, transport.MaxIdleConnsPerHost)
+	assert.Equal(DefaultIdleConnTimeout, transport.IdleConnTimeout)
+	assert.False(transport.DisableCompression)
+	if transport.TLSClientConfig != nil {
+		assert.Nil(transport.TLSClientConfig.RootCAs)
+		assert.False(transport.TLSClientConfig.InsecureSkipVerify)
+	}
+
+	// Insecure TLS preserves the provided TLS config
+	f = New(
+		ts.URL,
+		WithTLSConfig(tlsConfig),
+		WithInsecureTLS(),
+	)
+	transport, ok = f.rosettaClient.GetConfig().HTTPClient.Transport.(*http.Transport)
+	assert.True(ok)
+	assert.True(transport.TLSClientConfig.InsecureSkipVerify)
+	assert.Equal(rootCAs, transport.TLSClientConfig.RootCAs)
+	assert.False(tlsConfig.InsecureSkipVerify)
+}
+
+func TestCompression(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		ctx    = context.Background()
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/network/list", r.URL.RequestURI())
+		assert.Equal("gzip", r.Header.Get("Accept-Encoding"))
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+
+		writer := gzip.NewWriter(w)
+		fmt.Fprintln(writer, types.PrettyPrintStruct(basicNetworkList))
+		assert.NoError(writer.Close())
+	}))
+	defer ts.Close()
+
+	// Compressed responses are decompressed by the client
+	// even if the transport does not request compression
+	f := New(
+		ts.URL,
+		WithRetryElapsedTime(5*time.Second),
+		WithDisableCompression(),
+		WithCompression(),
+	)
+	assert.True(f.rosettaClient.GetConfig().Compression)
+
+	networkList, err := f.NetworkListRetry(ctx, nil)
+	assert.Nil(err)
+	assert.Equal(basicNetworkList, networkList)
+}
+
+func TestInterceptors(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		ctx      = context.Background()
+		requests = []http.Header{}
+		calls    = []string{}
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/network/list", r.URL.RequestURI())
+		requests = append(requests, r.Header.Clone())
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkList))
+	}))
+	defer ts.Close()
+
+	errBlocked := errors.New("blocked")
+	flaky := true
+	blocked := false
+	f := New(
+		ts.URL,
+		WithRetryElapsedTime(5*time.Second),
+		WithRetryBackoff(time.Millisecond, 2, 10*time.Millisecond),
+		WithRequestInterceptors(
+			func(r *http.Request) error {
+				calls = append(calls, "request 1")
+				r.Header.Set("X-Trace-Id", "trace")
+				return nil
+			},
+			func(r *http.Request) error {
+				calls = append(calls, "request 2")
+
+				// Headers set by prior interceptors are visible
+				assert.Equal("trace", r.Header.Get("X-Trace-Id"))
+				r.Header.Set("X-Trace-Id", r.Header.Get("X-Trace-Id")+"-2")
+				if blocked {
+					return errBlocked
+				}
+
+				return nil
+			},
+		),
+		WithResponseInterceptors(
+			func(resp *http.Response, err error) error {
+				calls = append(calls, "response 1")
+				assert.NoError(err)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+
+				// Fail the first response to ensure
+				// interceptor errors are retried
+				if flaky {
+					flaky = false
+					return fmt.Errorf("%w: flaky", client.ErrRetriable)
+				}
+
+				return nil
+			},
+			func(resp *http.Response, err error) error {
+				calls = append(calls, "response 2")
+				return nil
+			},
+		),
+	)
+
+	networkList, fetchErr := f.NetworkListRetry(ctx, nil)
+	assert.Nil(fetchErr)
+	assert.Equal(basicNetworkList, networkList)
+	assert.Equal([]string{
+		"request 1",
+		"request 2",
+		"response 1",
+		"request 1",
+		"request 2",
+		"response 1",
+		"response 2",
+	}, calls)
+	assert.Len(requests, 2)
+	for _, headers := range requests {
+		assert.Equal("trace-2", headers.Get("X-Trace-Id"))
+	}
+
+	// Errors that are not retriable short-circuit
+	// the request and are not retried
+	calls = []string{}
+	blocked = true
+	networkList, fetchErr = f.NetworkListRetry(ctx, nil)
+	assert.Nil(networkList)
+	assert.NotNil(fetchErr)
+	assert.True(errors.Is(fetchErr.Err, ErrRequestFailed))
+	assert.Contains(fetchErr.Err.Error(), errBlocked.Error())
+	assert.False(fetchErr.Retry)
+	assert.Equal([]string{"request 1", "request 2"}, calls)
+	assert.Len(requests, 2)
+}
+
+func TestInterceptorsWithClient(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		calls  = []string{}
+	)
+
+	// The provided interceptors have spare capacity so
+	// appending to them in place would be visible to
+	// other fetchers using the same client.
+	cfg := client.NewConfiguration("https://serveraddress", DefaultUserAgent, nil)
+	cfg.RequestInterceptors = make([]client.RequestInterceptor, 1, 10)
+	cfg.RequestInterceptors[0] = func(r *http.Request) error {
+		calls = append(calls, "provided")
+		return nil
+	}
+	cfg.ResponseInterceptors = make([]client.ResponseInterceptor, 0, 10)
+	apiClient := client.NewAPIClient(cfg)
+
+	newFetcher := func(name string) *Fetcher {
+		return New(
+			"https://serveraddress",
+			WithClient(apiClient),
+			WithRequestInterceptors(func(r *http.Request) error {
+				calls = append(calls, name)
+				return nil
+			}),
+			WithResponseInterceptors(func(resp *http.Response, err error) error {
+				return nil
+			}),
+		)
+	}
+	f1 := newFetcher("fetcher 1")
+	f2 := newFetcher("fetcher 2")
+
+	assert.Len(cfg.RequestInterceptors, 1)
+	assert.Len(cfg.ResponseInterceptors, 0)
+
+	for _, f := range []*Fetcher{f1, f2} {
+		interceptors := f.rosettaClient.GetConfig().RequestInterceptors
+		assert.Len(interceptors, 2)
+		assert.Len(f.rosettaClient.GetConfig().ResponseInterceptors, 1)
+		for _, interceptor := range interceptors {
+			assert.NoError(interceptor(nil))
+		}
+	}
+
+	assert.Equal([]string{"provided", "fetcher 1", "provided", "fetcher 2"}, calls)
+}
+
+func TestRefreshAsserter(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		stake = &types.Block{
+			BlockIdentifier:       basicFullBlock.BlockIdentifier,
+			ParentBlockIdentifier: basicFullBlock.ParentBlockIdentifier,
+			Timestamp:             basicFullBlock.Timestamp,
+			Transactions: []*types.Transaction{
+				{
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: "tx 1",
+					},
+					Operations: []*types.Operation{
+						{
+							OperationIdentifier: &types.OperationIdentifier{
+								Index: 0,
+							},
+							Type:   "stake",
+							Status: types.String("SUCCESS"),
+						},
+					},
+				},
+			},
+		}
+		partialBlock = types.ConstructPartialBlockIdentifier(stake.BlockIdentifier)
+	)
+
+	// newServer returns a server that only supports the
+	// stake operation type after upgrade is called.
+	newServer := func() (*httptest.Server, func(), *int) {
+		var (
+			l              sync.Mutex
+			upgraded       bool
+			optionRequests int
+		)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			w.WriteHeader(http.StatusOK)
+
+			l.Lock()
+			defer l.Unlock()
+
+			switch r.URL.RequestURI() {
+			case "/network/list":
+				fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkList))
+			case "/network/status":
+				fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkStatus))
+			case "/network/options":
+				optionRequests++
+				operationTypes := []string{"transfer"}
+				if upgraded {
+					operationTypes = append(operationTypes, "stake")
+				}
+
+				fmt.Fprintln(w, types.PrettyPrintStruct(&types.NetworkOptionsResponse{
+					Version: basicNetworkOptions.Version,
+					Allow: &types.Allow{
+						OperationStatuses: basicNetworkOptions.Allow.OperationStatuses,
+						OperationTypes:    operationTypes,
+					},
+				}))
+			case "/block":
+				fmt.Fprintln(w, types.PrettyPrintStruct(&types.BlockResponse{
+					Block: stake,
+				}))
+			}
+		}))
+
+		return ts, func() {
+			l.Lock()
+			defer l.Unlock()
+
+			upgraded = true
+		}, &optionRequests
+	}
+
+	t.Run("manual refresh", func(t *testing.T) {
+		ts, upgrade, _ := newServer()
+		defer ts.Close()
+
+		f := New(ts.URL)
+		_, _, fetchErr := f.InitializeAsserter(ctx, nil)
+		assert.Nil(t, fetchErr)
+
+		block, fetchErr := f.BlockRetry(ctx, basicNetwork, partialBlock)
+		assert.Nil(t, block)
+		assert.True(t, checkError(fetchErr, asserter.ErrOperationTypeInvalid))
+
+		upgrade()
+		block, fetchErr = f.BlockRetry(ctx, basicNetwork, partialBlock)
+		assert.Nil(t, block)
+		assert.True(t, checkError(fetchErr, asserter.ErrOperationTypeInvalid))
+
+		staleAsserter := f.Asserter
+		assert.Nil(t, f.RefreshAsserter(ctx, basicNetwork))
+		assert.NotEqual(t, staleAsserter, f.Asserter)
+
+		block, fetchErr = f.BlockRetry(ctx, basicNetwork, partialBlock)
+		assert.Nil(t, fetchErr)
+		assert.Equal(t, stake, block)
+	})
+
+	t.Run("automatic refresh", func(t *testing.T) {
+		ts, upgrade, optionRequests := newServer()
+		defer ts.Close()
+
+		clock := &fakeClock{now: time.Now()}
+		f := New(ts.URL, WithAsserterRefresh(time.Minute))
+		f.clock = clock
+
+		_, _, fetchErr := f.InitializeAsserter(ctx, nil)
+		assert.Nil(t, fetchErr)
+		assert.Equal(t, 1, *optionRequests)
+
+		// The refreshed asserter still does
+		// not support stake.
+		block, fetchErr := f.BlockRetry(ctx, basicNetwork, partialBlock)
+		assert.Nil(t, block)
+		assert.True(t, checkError(fetchErr, asserter.ErrOperationTypeInvalid))
+		assert.Equal(t, 2, *optionRequests)
+
+		// Refreshes are throttled
+		upgrade()
+		clock.now = clock.now.Add(30 * time.Second)
+		block, fetchErr = f.BlockRetry(ctx, basicNetwork, partialBlock)
+		assert.Nil(t, block)
+		assert.True(t, checkError(fetchErr, asserter.ErrOperationTypeInvalid))
+		assert.Equal(t, 2, *optionRequests)
+
+		clock.now = clock.now.Add(31 * time.Second)
+		block, fetchErr = f.BlockRetry(ctx, basicNetwork, partialBlock)
+		assert.Nil(t, fetchErr)
+		assert.Equal(t, stake, block)
+		assert.Equal(t, 3, *optionRequests)
+	})
+}
+
+func TestRefreshAsserter_Concurrent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+
+		switch r.URL.RequestURI() {
+		case "/network/status":
+			fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkStatus))
+		case "/network/options":
+			fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkOptions))
+		case "/block":
+			fmt.Fprintln(w, types.PrettyPrintStruct(&types.BlockResponse{
+				Block: basicFullBlock,
+			}))
+		}
+	}))
+	defer ts.Close()
+
+	var (
+		ctx          = context.Background()
+		f            = New(ts.URL)
+		partialBlock = types.ConstructPartialBlockIdentifier(basicFullBlock.BlockIdentifier)
+	)
+	assert.Nil(t, f.RefreshAsserter(ctx, basicNetwork))
+
+	// Blocks are validated with either the old or
+	// new asserter while it is refreshed (run with
+	// -race to detect unsynchronized access).
+	g, gctx := errgroup.WithContext(ctx)
+	for i := 0; i < 5; i++ {
+		g.Go(func() error {
+			for j := 0; j < 5; j++ {
+				if _, err := f.BlockRetry(gctx, basicNetwork, partialBlock); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		g.Go(func() error {
+			if err := f.RefreshAsserter(gctx, basicNetwork); err != nil {
+				return err
+			}
+
+			return nil
+		})
+	}
+	assert.NoError(t, g.Wait())
+}
+
+// assertStatusCode asserts that a request that failed with a
+// *types.Error can be extracted as a *RosettaError carrying
+// the HTTP status code of the response.
+func assertStatusCode(t *testing.T, fetcherErr *Error, expectedErr error, statusCode int) {
+	if !errors.Is(expectedErr, ErrRequestFailed) && !errors.Is(expectedErr, ErrExhaustedRetries) {
+		return
+	}
+
+	if !assert.NotNil(t, fetcherErr) {
+		return
+	}
+
+	var rosettaErr *RosettaError
+	assert.True(t, errors.As(fetcherErr, &rosettaErr))
+	assert.Equal(t, statusCode, rosettaErr.StatusCode)
+	assert.Equal(t, fetcherErr.ClientErr, rosettaErr.Err)
+}
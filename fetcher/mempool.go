@@ -28,6 +28,8 @@ func (f *Fetcher) Mempool(
 	ctx context.Context,
 	network *types.NetworkIdentifier,
 ) ([]*types.TransactionIdentifier, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -35,8 +37,11 @@ func (f *Fetcher) Mempool(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
+	requestCtx, cancel := f.requestContext(ctx, MempoolEndpoint)
+	defer cancel()
+
 	response, clientErr, err := f.rosettaClient.MempoolAPI.Mempool(
-		ctx,
+		requestCtx,
 		&types.NetworkRequest{
 			NetworkIdentifier: network,
 		},
@@ -56,6 +61,47 @@ func (f *Fetcher) Mempool(
 	return mempool, nil
 }
 
+// MempoolRetry retrieves the validated Mempool
+// with a specified number of retries and max elapsed time.
+func (f *Fetcher) MempoolRetry(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+) ([]*types.TransactionIdentifier, *Error) {
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
+
+	for {
+		mempool, err := f.Mempool(ctx, network)
+		if err == nil {
+			return mempool, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, &Error{
+				Err: ctx.Err(),
+			}
+		}
+
+		if is, _ := asserter.Err(err.Err); is {
+			fetcherErr := &Error{
+				Err:        fmt.Errorf("%w: /mempool not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
+			}
+			return nil, fetcherErr
+		}
+
+		if err := tryAgain(
+			ctx,
+			fmt.Sprintf("mempool %s", types.PrintStruct(network)),
+			backoffRetries,
+			err,
+		); err != nil {
+			return nil, err
+		}
+	}
+}
+
 // MempoolTransaction returns the validated response
 // from the MempoolTransaction method.
 func (f *Fetcher) MempoolTransaction(
@@ -63,6 +109,8 @@ func (f *Fetcher) MempoolTransaction(
 	network *types.NetworkIdentifier,
 	transaction *types.TransactionIdentifier,
 ) (*types.Transaction, map[string]interface{}, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -70,8 +118,11 @@ func (f *Fetcher) MempoolTransaction(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
+	requestCtx, cancel := f.requestContext(ctx, MempoolEndpoint)
+	defer cancel()
+
 	response, clientErr, err := f.rosettaClient.MempoolAPI.MempoolTransaction(
-		ctx,
+		requestCtx,
 		&types.MempoolTransactionRequest{
 			NetworkIdentifier:     network,
 			TransactionIdentifier: transaction,
@@ -82,7 +133,7 @@ func (f *Fetcher) MempoolTransaction(
 	}
 
 	mempoolTransaction := response.Transaction
-	if err := f.Asserter.Transaction(mempoolTransaction); err != nil {
+	if err := f.currentAsserter().MempoolTransaction(mempoolTransaction); err != nil {
 		fetcherErr := &Error{
 			Err: fmt.Errorf("%w: /mempool/transaction", err),
 		}
@@ -91,3 +142,49 @@ func (f *Fetcher) MempoolTransaction(
 
 	return mempoolTransaction, response.Metadata, nil
 }
+
+// MempoolTransactionRetry retrieves the validated MempoolTransaction
+// with a specified number of retries and max elapsed time.
+func (f *Fetcher) MempoolTransactionRetry(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	transaction *types.TransactionIdentifier,
+) (*types.Transaction, map[string]interface{}, *Error) {
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
+
+	for {
+		mempoolTransaction, metadata, err := f.MempoolTransaction(
+			ctx,
+			network,
+			transaction,
+		)
+		if err == nil {
+			return mempoolTransaction, metadata, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, nil, &Error{
+				Err: ctx.Err(),
+			}
+		}
+
+		if is, _ := asserter.Err(err.Err); is {
+			fetcherErr := &Error{
+				Err:        fmt.Errorf("%w: /mempool/transaction not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
+			}
+			return nil, nil, fetcherErr
+		}
+
+		if err := tryAgain(
+			ctx,
+			fmt.Sprintf("mempool transaction %s", types.PrintStruct(transaction)),
+			backoffRetries,
+			err,
+		); err != nil {
+			return nil, nil, err
+		}
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	basicMempool = []*types.TransactionIdentifier{
+		{Hash: "tx 1"},
+		{Hash: "tx 2"},
+	}
+
+	basicMempoolTransaction = &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: "tx 1",
+		},
+		Operations: []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: "transfer",
+				Account: &types.AccountIdentifier{
+					Address: "addr",
+				},
+				Amount: &types.Amount{
+					Value: "100",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+			},
+		},
+	}
+
+	basicMempoolMetadata = map[string]interface{}{
+		"descendant_fees": float64(123),
+	}
+)
+
+func TestMempoolRetry(t *testing.T) {
+	var tests = map[string]struct {
+		errorsBeforeSuccess int
+		response            []*types.TransactionIdentifier
+		expectedMempool     []*types.TransactionIdentifier
+		expectedError       error
+		retriableError      bool
+
+		fetcherMaxRetries uint64
+		shouldCancel      bool
+	}{
+		"no failures": {
+			response:          basicMempool,
+			expectedMempool:   basicMempool,
+			fetcherMaxRetries: 5,
+		},
+		"empty mempool": {
+			response:          []*types.TransactionIdentifier{},
+			expectedMempool:   []*types.TransactionIdentifier{},
+			fetcherMaxRetries: 5,
+		},
+		"retry failures": {
+			errorsBeforeSuccess: 2,
+			response:            basicMempool,
+			expectedMempool:     basicMempool,
+			fetcherMaxRetries:   5,
+			retriableError:      true,
+		},
+		"non-retriable error": {
+			errorsBeforeSuccess: 2,
+			fetcherMaxRetries:   5,
+			expectedError:       ErrRequestFailed,
+		},
+		"exhausted retries": {
+			errorsBeforeSuccess: 2,
+			expectedError:       ErrExhaustedRetries,
+			fetcherMaxRetries:   1,
+			retriableError:      true,
+		},
+		"malformed response": {
+			response: []*types.TransactionIdentifier{
+				{Hash: "tx 1"},
+				{},
+			},
+			fetcherMaxRetries: 5,
+			expectedError:     asserter.ErrTxIdentifierHashMissing,
+		},
+		"cancel context": {
+			errorsBeforeSuccess: 6,
+			expectedError:       context.Canceled,
+			fetcherMaxRetries:   5,
+			shouldCancel:        true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				tries       = 0
+				assert      = assert.New(t)
+				ctx, cancel = context.WithCancel(context.Background())
+				endpoint    = "/mempool"
+			)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal("POST", r.Method)
+				assert.Equal(endpoint, r.URL.RequestURI())
+
+				var req *types.NetworkRequest
+				assert.NoError(json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(basicNetwork, req.NetworkIdentifier)
+
+				if test.shouldCancel {
+					cancel()
+				}
+
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				if tries < test.errorsBeforeSuccess {
+					w.WriteHeader(http.StatusInternalServerError)
+					fmt.Fprintln(w, types.PrettyPrintStruct(&types.Error{
+						Retriable: test.retriableError,
+					}))
+					tries++
+					return
+				}
+
+				tries++
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, types.PrettyPrintStruct(&types.MempoolResponse{
+					TransactionIdentifiers: test.response,
+				}))
+			}))
+
+			defer ts.Close()
+
+			a, aerr := asserter.NewClientWithOptions(
+				basicNetwork,
+				&types.BlockIdentifier{
+					Index: 0,
+					Hash:  "block 0",
+				},
+				basicNetworkOptions.Allow.OperationTypes,
+				basicNetworkOptions.Allow.OperationStatuses,
+				nil,
+				nil,
+			)
+			assert.NoError(aerr)
+
+			f := New(
+				ts.URL,
+				WithRetryElapsedTime(5*time.Second),
+				WithRetryBackoff(time.Millisecond, 2, 10*time.Millisecond),
+				WithMaxRetries(test.fetcherMaxRetries),
+				WithAsserter(a),
+			)
+			mempool, err := f.MempoolRetry(ctx, basicNetwork)
+			assert.Equal(test.expectedMempool, mempool)
+			assert.True(checkError(err, test.expectedError))
+			assertStatusCode(t, err, test.expectedError, http.StatusInternalServerError)
+
+			// Malformed responses are not retried
+			if test.expectedError != nil && test.errorsBeforeSuccess == 0 {
+				assert.Equal(1, tries)
+			}
+		})
+	}
+}
+
+func TestMempoolTransactionRetry(t *testing.T) {
+	var tests = map[string]struct {
+		errorsBeforeSuccess int
+		response            *types.Transaction
+		expectedTransaction *types.Transaction
+		expectedMetadata    map[string]interface{}
+		expectedError       error
+		retriableError      bool
+
+		fetcherMaxRetries uint64
+		shouldCancel      bool
+	}{
+		"no failures": {
+			response:            basicMempoolTransaction,
+			expectedTransaction: basicMempoolTransaction,
+			expectedMetadata:    basicMempoolMetadata,
+			fetcherMaxRetries:   5,
+		},
+		"retry failures": {
+			errorsBeforeSuccess: 2,
+			response:            basicMempoolTransaction,
+			expectedTransaction: basicMempoolTransaction,
+			expectedMetadata:    basicMempoolMetadata,
+			fetcherMaxRetries:   5,
+			retriableError:      true,
+		},
+		"non-retriable error": {
+			errorsBeforeSuccess: 2,
+			fetcherMaxRetries:   5,
+			expectedError:       ErrRequestFailed,
+		},
+		"exhausted retries": {
+			errorsBeforeSuccess: 2,
+			expectedError:       ErrExhaustedRetries,
+			fetcherMaxRetries:   1,
+			retriableError:      true,
+		},
+		"missing transaction identifier": {
+			response: &types.Transaction{
+				Operations: basicMempoolTransaction.Operations,
+			},
+			fetcherMaxRetries: 5,
+			expectedError:     asserter.ErrTxIdentifierIsNil,
+		},
+		"invalid operation status": {
+			response: &types.Transaction{
+				TransactionIdentifier: basicMempoolTransaction.TransactionIdentifier,
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						Type:   "transfer",
+						Status: types.String("PENDING"),
+					},
+				},
+			},
+			fetcherMaxRetries: 5,
+			expectedError:     asserter.ErrOperationStatusInvalid,
+		},
+		"cancel context": {
+			errorsBeforeSuccess: 6,
+			expectedError:       context.Canceled,
+			fetcherMaxRetries:   5,
+			shouldCancel:        true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				tries       = 0
+				assert      = assert.New(t)
+				ctx, cancel = context.WithCancel(context.Background())
+				endpoint    = "/mempool/transaction"
+			)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal("POST", r.Method)
+				assert.Equal(endpoint, r.URL.RequestURI())
+
+				var req *types.MempoolTransactionRequest
+				assert.NoError(json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(basicNetwork, req.NetworkIdentifier)
+				assert.Equal(basicMempoolTransaction.TransactionIdentifier, req.TransactionIdentifier)
+
+				if test.shouldCancel {
+					cancel()
+				}
+
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				if tries < test.errorsBeforeSuccess {
+					w.WriteHeader(http.StatusInternalServerError)
+					fmt.Fprintln(w, types.PrettyPrintStruct(&types.Error{
+						Retriable: test.retriableError,
+					}))
+					tries++
+					return
+				}
+
+				tries++
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, types.PrettyPrintStruct(&types.MempoolTransactionResponse{
+					Transaction: test.response,
+					Metadata:    basicMempoolMetadata,
+				}))
+			}))
+
+			defer ts.Close()
+
+			a, aerr := asserter.NewClientWithOptions(
+				basicNetwork,
+				&types.BlockIdentifier{
+					Index: 0,
+					Hash:  "block 0",
+				},
+				basicNetworkOptions.Allow.OperationTypes,
+				basicNetworkOptions.Allow.OperationStatuses,
+				nil,
+				nil,
+			)
+			assert.NoError(aerr)
+
+			f := New(
+				ts.URL,
+				WithRetryElapsedTime(5*time.Second),
+				WithRetryBackoff(time.Millisecond, 2, 10*time.Millisecond),
+				WithMaxRetries(test.fetcherMaxRetries),
+				WithAsserter(a),
+			)
+			transaction, metadata, err := f.MempoolTransactionRetry(
+				ctx,
+				basicNetwork,
+				basicMempoolTransaction.TransactionIdentifier,
+			)
+			assert.Equal(test.expectedTransaction, transaction)
+			assert.Equal(test.expectedMetadata, metadata)
+			assert.True(checkError(err, test.expectedError))
+			assertStatusCode(t, err, test.expectedError, http.StatusInternalServerError)
+
+			// Malformed responses are not retried
+			if test.expectedError != nil && test.errorsBeforeSuccess == 0 {
+				assert.Equal(1, tries)
+			}
+		})
+	}
+}
@@ -29,6 +29,8 @@ func (f *Fetcher) NetworkStatus(
 	network *types.NetworkIdentifier,
 	metadata map[string]interface{},
 ) (*types.NetworkStatusResponse, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -36,8 +38,11 @@ func (f *Fetcher) NetworkStatus(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
+	requestCtx, cancel := f.requestContext(ctx, NetworkEndpoint)
+	defer cancel()
+
 	networkStatus, clientErr, err := f.rosettaClient.NetworkAPI.NetworkStatus(
-		ctx,
+		requestCtx,
 		&types.NetworkRequest{
 			NetworkIdentifier: network,
 			Metadata:          metadata,
@@ -47,7 +52,17 @@ func (f *Fetcher) NetworkStatus(
 		return nil, f.RequestFailedError(clientErr, err, "/network/status")
 	}
 
-	if err := asserter.NetworkStatusResponse(networkStatus); err != nil {
+	// Use any timestamp bounds configured in the asserter
+	// (or provided with WithAsserterOptions before it is
+	// initialized).
+	assertStatus := func(response *types.NetworkStatusResponse) error {
+		return asserter.NetworkStatusResponseWithOptions(response, f.asserterOptions...)
+	}
+	if a := f.currentAsserter(); a != nil {
+		assertStatus = a.NetworkStatusResponse
+	}
+
+	if err := assertStatus(networkStatus); err != nil {
 		fetcherErr := &Error{
 			Err: fmt.Errorf("%w: /network/status", err),
 		}
@@ -64,10 +79,8 @@ func (f *Fetcher) NetworkStatusRetry(
 	network *types.NetworkIdentifier,
 	metadata map[string]interface{},
 ) (*types.NetworkStatusResponse, *Error) {
-	backoffRetries := backoffRetries(
-		f.retryElapsedTime,
-		f.maxRetries,
-	)
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
 
 	for {
 		networkStatus, err := f.NetworkStatus(
@@ -87,13 +100,15 @@ func (f *Fetcher) NetworkStatusRetry(
 
 		if is, _ := asserter.Err(err.Err); is {
 			fetcherErr := &Error{
-				Err:       fmt.Errorf("%w: /network/status not attempting retry", err.Err),
-				ClientErr: err.ClientErr,
+				Err:        fmt.Errorf("%w: /network/status not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
 			}
 			return nil, fetcherErr
 		}
 
 		if err := tryAgain(
+			ctx,
 			fmt.Sprintf("network status %s", types.PrintStruct(network)),
 			backoffRetries,
 			err,
@@ -116,8 +131,11 @@ func (f *Fetcher) NetworkList(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
+	requestCtx, cancel := f.requestContext(ctx, NetworkEndpoint)
+	defer cancel()
+
 	networkList, clientErr, err := f.rosettaClient.NetworkAPI.NetworkList(
-		ctx,
+		requestCtx,
 		&types.MetadataRequest{
 			Metadata: metadata,
 		},
@@ -143,10 +161,8 @@ func (f *Fetcher) NetworkListRetry(
 	ctx context.Context,
 	metadata map[string]interface{},
 ) (*types.NetworkListResponse, *Error) {
-	backoffRetries := backoffRetries(
-		f.retryElapsedTime,
-		f.maxRetries,
-	)
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
 
 	for {
 		networkList, err := f.NetworkList(
@@ -165,13 +181,14 @@ func (f *Fetcher) NetworkListRetry(
 
 		if is, _ := asserter.Err(err.Err); is {
 			fetcherErr := &Error{
-				Err:       fmt.Errorf("%w: /network/list not attempting retry", err.Err),
-				ClientErr: err.ClientErr,
+				Err:        fmt.Errorf("%w: /network/list not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
 			}
 			return nil, fetcherErr
 		}
 
-		if err := tryAgain("NetworkList", backoffRetries, err); err != nil {
+		if err := tryAgain(ctx, "NetworkList", backoffRetries, err); err != nil {
 			return nil, err
 		}
 	}
@@ -184,6 +201,8 @@ func (f *Fetcher) NetworkOptions(
 	network *types.NetworkIdentifier,
 	metadata map[string]interface{},
 ) (*types.NetworkOptionsResponse, *Error) {
+	network = f.defaultNetwork(network)
+
 	if err := f.connectionSemaphore.Acquire(ctx, semaphoreRequestWeight); err != nil {
 		return nil, &Error{
 			Err: fmt.Errorf("%w: %s", ErrCouldNotAcquireSemaphore, err.Error()),
@@ -191,8 +210,11 @@ func (f *Fetcher) NetworkOptions(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
+	requestCtx, cancel := f.requestContext(ctx, NetworkEndpoint)
+	defer cancel()
+
 	networkOptions, clientErr, err := f.rosettaClient.NetworkAPI.NetworkOptions(
-		ctx,
+		requestCtx,
 		&types.NetworkRequest{
 			NetworkIdentifier: network,
 			Metadata:          metadata,
@@ -220,10 +242,8 @@ func (f *Fetcher) NetworkOptionsRetry(
 	network *types.NetworkIdentifier,
 	metadata map[string]interface{},
 ) (*types.NetworkOptionsResponse, *Error) {
-	backoffRetries := backoffRetries(
-		f.retryElapsedTime,
-		f.maxRetries,
-	)
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
 
 	for {
 		networkOptions, err := f.NetworkOptions(
@@ -243,13 +263,15 @@ func (f *Fetcher) NetworkOptionsRetry(
 
 		if is, _ := asserter.Err(err.Err); is {
 			fetcherErr := &Error{
-				Err:       fmt.Errorf("%w: /network/options not attempting retry", err.Err),
-				ClientErr: err.ClientErr,
+				Err:        fmt.Errorf("%w: /network/options not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
 			}
 			return nil, fetcherErr
 		}
 
 		if err := tryAgain(
+			ctx,
 			fmt.Sprintf("network options %s", types.PrintStruct(network)),
 			backoffRetries,
 			err,
@@ -175,6 +175,7 @@ func TestNetworkStatusRetry(t *testing.T) {
 			)
 			assert.Equal(test.expectedStatus, status)
 			assert.True(checkError(err, test.expectedError))
+			assertStatusCode(t, err, test.expectedError, http.StatusInternalServerError)
 		})
 	}
 }
@@ -275,6 +276,7 @@ func TestNetworkListRetry(t *testing.T) {
 			)
 			assert.Equal(test.expectedList, list)
 			assert.True(checkError(err, test.expectedError))
+			assertStatusCode(t, err, test.expectedError, http.StatusInternalServerError)
 		})
 	}
 }
@@ -378,6 +380,7 @@ func TestNetworkOptionsRetry(t *testing.T) {
 			)
 			assert.Equal(test.expectedOptions, options)
 			assert.True(checkError(err, test.expectedError))
+			assertStatusCode(t, err, test.expectedError, http.StatusInternalServerError)
 		})
 	}
 }
@@ -0,0 +1,221 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FetcherObserver is notified before and after every
+// HTTP request made by the fetcher (including each
+// retry attempt). It can be used to collect latency
+// and error-rate metrics per endpoint.
+//
+// Callbacks are invoked synchronously while the request
+// is in flight, so they MUST return quickly (ex: by
+// incrementing a counter). Any panic in a callback is
+// recovered (and logged) so that it cannot interrupt
+// a request.
+type FetcherObserver interface { // nolint:golint
+	// RequestStarted is invoked before a request
+	// is sent. attempt is 1 for the first attempt
+	// of a request and is incremented on each retry.
+	RequestStarted(method string, endpoint string, attempt int)
+
+	// RequestCompleted is invoked when the response
+	// headers are received or the request fails. statusCode
+	// is 0 and err is populated if no response was received.
+	RequestCompleted(
+		method string,
+		endpoint string,
+		attempt int,
+		duration time.Duration,
+		statusCode int,
+		err error,
+	)
+}
+
+// NoopFetcherObserver is a FetcherObserver that ignores
+// all requests. It can be embedded in implementations
+// that only need to implement one of the callbacks.
+type NoopFetcherObserver struct{}
+
+// RequestStarted is called before each request.
+func (NoopFetcherObserver) RequestStarted(string, string, int) {}
+
+// RequestCompleted is called after each request.
+func (NoopFetcherObserver) RequestCompleted(string, string, int, time.Duration, int, error) {}
+
+// attemptKey is the context key used to store the
+// *Backoff of a request (so the attempt number of
+// each request can be provided to the FetcherObserver).
+type attemptKey struct{}
+
+// contextWithBackoff returns a copy of ctx that
+// tracks the attempts of backoff.
+func contextWithBackoff(ctx context.Context, backoff *Backoff) context.Context {
+	return context.WithValue(ctx, attemptKey{}, backoff)
+}
+
+// attempt returns the attempt number of a request
+// made with ctx (1 if the request is not retried).
+func attempt(ctx context.Context) int {
+	backoff, ok := ctx.Value(attemptKey{}).(*Backoff)
+	if !ok {
+		return 1
+	}
+
+	return backoff.attempts + 1
+}
+
+// observedTransport is an http.RoundTripper that
+// notifies a FetcherObserver of each request.
+type observedTransport struct {
+	next     http.RoundTripper
+	observer FetcherObserver
+}
+
+// RoundTrip notifies the FetcherObserver before
+// and after invoking the wrapped http.RoundTripper.
+func (t *observedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		method   = req.Method
+		endpoint = req.URL.Path
+		attempt  = attempt(req.Context())
+	)
+
+	safeObserve(func() {
+		t.observer.RequestStarted(method, endpoint, attempt)
+	})
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+
+	safeObserve(func() {
+		t.observer.RequestCompleted(method, endpoint, attempt, duration, statusCode, err)
+	})
+
+	return resp, err
+}
+
+// safeObserve invokes callback and recovers
+// from any panic.
+func safeObserve(callback func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("fetcher observer panicked: %v\n", r)
+		}
+	}()
+
+	callback()
+}
+
+// EndpointStats are the aggregated requests
+// made to an endpoint.
+type EndpointStats struct {
+	Requests      int
+	Retries       int
+	Errors        int
+	StatusCodes   map[int]int
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+}
+
+// AggregateObserver is a FetcherObserver that
+// aggregates the requests made to each endpoint.
+type AggregateObserver struct {
+	NoopFetcherObserver
+
+	mutex sync.Mutex
+	stats map[string]*EndpointStats
+}
+
+// NewAggregateObserver returns a new *AggregateObserver.
+func NewAggregateObserver() *AggregateObserver {
+	return &AggregateObserver{
+		stats: map[string]*EndpointStats{},
+	}
+}
+
+// RequestCompleted records a completed request. A request
+// is considered to be an error if it failed or a non-200
+// status code was returned.
+func (o *AggregateObserver) RequestCompleted(
+	method string,
+	endpoint string,
+	attempt int,
+	duration time.Duration,
+	statusCode int,
+	err error,
+) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	stats, ok := o.stats[endpoint]
+	if !ok {
+		stats = &EndpointStats{StatusCodes: map[int]int{}}
+		o.stats[endpoint] = stats
+	}
+
+	stats.Requests++
+	if attempt > 1 {
+		stats.Retries++
+	}
+
+	if err != nil || statusCode != http.StatusOK {
+		stats.Errors++
+	}
+
+	if statusCode != 0 {
+		stats.StatusCodes[statusCode]++
+	}
+
+	stats.TotalDuration += duration
+	if duration > stats.MaxDuration {
+		stats.MaxDuration = duration
+	}
+}
+
+// Stats returns a copy of the *EndpointStats of
+// endpoint (nil if no requests were made to it).
+func (o *AggregateObserver) Stats(endpoint string) *EndpointStats {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	stats, ok := o.stats[endpoint]
+	if !ok {
+		return nil
+	}
+
+	statusCodes := make(map[int]int, len(stats.StatusCodes))
+	for code, count := range stats.StatusCodes {
+		statusCodes[code] = count
+	}
+
+	statsCopy := *stats
+	statsCopy.StatusCodes = statusCodes
+
+	return &statsCopy
+}
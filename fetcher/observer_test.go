@@ -0,0 +1,222 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/client"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+type request struct {
+	method     string
+	endpoint   string
+	attempt    int
+	statusCode int
+	completed  bool
+}
+
+// recordingObserver records each callback
+// (and optionally panics in RequestStarted).
+type recordingObserver struct {
+	mutex    sync.Mutex
+	requests []*request
+	panics   bool
+}
+
+func (o *recordingObserver) RequestStarted(method string, endpoint string, attempt int) {
+	o.mutex.Lock()
+	o.requests = append(o.requests, &request{
+		method:   method,
+		endpoint: endpoint,
+		attempt:  attempt,
+	})
+	o.mutex.Unlock()
+
+	if o.panics {
+		panic("observer failure")
+	}
+}
+
+func (o *recordingObserver) RequestCompleted(
+	method string,
+	endpoint string,
+	attempt int,
+	duration time.Duration,
+	statusCode int,
+	err error,
+) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.requests = append(o.requests, &request{
+		method:     method,
+		endpoint:   endpoint,
+		attempt:    attempt,
+		statusCode: statusCode,
+		completed:  true,
+	})
+}
+
+func observerServer(t *testing.T, errorsBeforeSuccess int) *httptest.Server {
+	tries := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		if tries < errorsBeforeSuccess {
+			tries++
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintln(w, types.PrettyPrintStruct(&types.Error{
+				Retriable: true,
+			}))
+			return
+		}
+
+		assert.Equal(t, "/network/list", r.URL.RequestURI())
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, types.PrettyPrintStruct(basicNetworkList))
+	}))
+}
+
+func TestObserver(t *testing.T) {
+	ts := observerServer(t, 2)
+	defer ts.Close()
+
+	observer := &recordingObserver{}
+	f := New(
+		ts.URL,
+		WithRetryBackoff(time.Millisecond, 2, 10*time.Millisecond),
+		WithObserver(observer),
+	)
+
+	networkList, err := f.NetworkListRetry(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, basicNetworkList, networkList)
+
+	expected := []*request{}
+	for i := 1; i <= 3; i++ {
+		statusCode := http.StatusInternalServerError
+		if i == 3 {
+			statusCode = http.StatusOK
+		}
+
+		expected = append(expected, &request{
+			method:   http.MethodPost,
+			endpoint: "/network/list",
+			attempt:  i,
+		}, &request{
+			method:     http.MethodPost,
+			endpoint:   "/network/list",
+			attempt:    i,
+			statusCode: statusCode,
+			completed:  true,
+		})
+	}
+	assert.Equal(t, expected, observer.requests)
+
+	// Requests without retries are the first attempt
+	observer.requests = nil
+	_, err = f.NetworkList(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, observer.requests, 2)
+	assert.Equal(t, 1, observer.requests[1].attempt)
+	assert.Equal(t, http.StatusOK, observer.requests[1].statusCode)
+}
+
+func TestObserver_Panic(t *testing.T) {
+	ts := observerServer(t, 0)
+	defer ts.Close()
+
+	observer := &recordingObserver{panics: true}
+	f := New(ts.URL, WithObserver(observer))
+
+	networkList, err := f.NetworkListRetry(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, basicNetworkList, networkList)
+	assert.Len(t, observer.requests, 2)
+	assert.True(t, observer.requests[1].completed)
+}
+
+func TestObserver_ProvidedClient(t *testing.T) {
+	ts := observerServer(t, 0)
+	defer ts.Close()
+
+	httpClient := &http.Client{}
+	observer := &recordingObserver{}
+	f := New(
+		ts.URL,
+		WithClient(client.NewAPIClient(client.NewConfiguration(
+			ts.URL,
+			DefaultUserAgent,
+			httpClient,
+		))),
+		WithObserver(observer),
+	)
+
+	_, err := f.NetworkListRetry(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, observer.requests, 2)
+
+	// The provided client is not modified
+	assert.Nil(t, httpClient.Transport)
+}
+
+func TestAggregateObserver(t *testing.T) {
+	ts := observerServer(t, 2)
+	defer ts.Close()
+
+	observer := NewAggregateObserver()
+	f := New(
+		ts.URL,
+		WithRetryBackoff(time.Millisecond, 2, 10*time.Millisecond),
+		WithObserver(observer),
+	)
+
+	_, err := f.NetworkListRetry(context.Background(), nil)
+	assert.Nil(t, err)
+
+	stats := observer.Stats("/network/list")
+	assert.Equal(t, 3, stats.Requests)
+	assert.Equal(t, 2, stats.Retries)
+	assert.Equal(t, 2, stats.Errors)
+	assert.Equal(t, map[int]int{
+		http.StatusInternalServerError: 2,
+		http.StatusOK:                  1,
+	}, stats.StatusCodes)
+	assert.True(t, stats.TotalDuration >= stats.MaxDuration)
+	assert.Nil(t, observer.Stats("/block"))
+
+	// Stats are copied
+	stats.StatusCodes[http.StatusOK] = 10
+	assert.Equal(t, 1, observer.Stats("/network/list").StatusCodes[http.StatusOK])
+
+	// Failed requests have no status code
+	ts.Close()
+	_, err = f.NetworkList(context.Background(), nil)
+	assert.NotNil(t, err)
+
+	stats = observer.Stats("/network/list")
+	assert.Equal(t, 4, stats.Requests)
+	assert.Equal(t, 3, stats.Errors)
+	assert.Len(t, stats.StatusCodes, 2)
+}
@@ -35,12 +35,19 @@ func (f *Fetcher) SearchTransactions(
 	}
 	defer f.connectionSemaphore.Release(semaphoreRequestWeight)
 
-	response, clientErr, err := f.rosettaClient.SearchAPI.SearchTransactions(ctx, request)
+	requestCtx, cancel := f.requestContext(ctx, SearchEndpoint)
+	defer cancel()
+
+	response, clientErr, err := f.rosettaClient.SearchAPI.SearchTransactions(
+		requestCtx,
+		request,
+	)
 	if err != nil {
 		return nil, nil, f.RequestFailedError(clientErr, err, "/search/transactions")
 	}
 
-	if err := f.Asserter.SearchTransactionsResponse(
+	if err := f.currentAsserter().SearchTransactionsResponse(
+		request,
 		response,
 	); err != nil {
 		fetcherErr := &Error{
@@ -61,10 +68,8 @@ func (f *Fetcher) SearchTransactionsRetry(
 	ctx context.Context,
 	request *types.SearchTransactionsRequest,
 ) (*int64, []*types.BlockTransaction, *Error) {
-	backoffRetries := backoffRetries(
-		f.retryElapsedTime,
-		f.maxRetries,
-	)
+	backoffRetries := f.backoffRetries()
+	ctx = contextWithBackoff(ctx, backoffRetries)
 
 	for {
 		nextOffset, transactions, err := f.SearchTransactions(
@@ -83,13 +88,15 @@ func (f *Fetcher) SearchTransactionsRetry(
 
 		if is, _ := asserter.Err(err.Err); is {
 			fetcherErr := &Error{
-				Err:       fmt.Errorf("%w: /search/transactions not attempting retry", err.Err),
-				ClientErr: err.ClientErr,
+				Err:        fmt.Errorf("%w: /search/transactions not attempting retry", err.Err),
+				ClientErr:  err.ClientErr,
+				StatusCode: err.StatusCode,
 			}
 			return nil, nil, fetcherErr
 		}
 
 		if err := tryAgain(
+			ctx,
 			fmt.Sprintf("/search/transactions %s", types.PrintStruct(request)),
 			backoffRetries,
 			err,
@@ -98,3 +105,39 @@ func (f *Fetcher) SearchTransactionsRetry(
 		}
 	}
 }
+
+// SearchTransactionsIterate fetches all *types.BlockTransaction
+// matching request (using SearchTransactionsRetry) and invokes
+// handler with each page of results until no NextOffset is
+// returned. If handler returns an error, iteration stops and
+// the error is returned.
+func (f *Fetcher) SearchTransactionsIterate(
+	ctx context.Context,
+	request *types.SearchTransactionsRequest,
+	handler func(transactions []*types.BlockTransaction) error,
+) *Error {
+	// Copy the request so that the caller's
+	// offset is not modified.
+	pageRequest := *request
+	for {
+		nextOffset, transactions, err := f.SearchTransactionsRetry(
+			ctx,
+			&pageRequest,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := handler(transactions); err != nil {
+			return &Error{
+				Err: fmt.Errorf("%w: /search/transactions handler failed", err),
+			}
+		}
+
+		if nextOffset == nil {
+			return nil
+		}
+
+		pageRequest.Offset = nextOffset
+	}
+}
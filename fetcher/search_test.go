@@ -17,6 +17,7 @@ package fetcher
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -174,6 +175,161 @@ func TestSearchTransactionsRetry(t *testing.T) {
 			assert.Equal(test.expectedNextOffset, off)
 			assert.Equal(test.expectedBlockTransactions, txs)
 			assert.True(checkError(err, test.expectedError))
+			assertStatusCode(t, err, test.expectedError, http.StatusInternalServerError)
+		})
+	}
+}
+
+func TestSearchTransactionsIterate(t *testing.T) {
+	var (
+		limit   = int64(2)
+		matches = []*types.BlockTransaction{}
+	)
+	for i := int64(0); i < 5; i++ {
+		matches = append(matches, &types.BlockTransaction{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: i,
+				Hash:  fmt.Sprintf("block %d", i),
+			},
+			Transaction: &types.Transaction{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: fmt.Sprintf("tx %d", i),
+				},
+			},
+		})
+	}
+
+	var tests = map[string]struct {
+		offset        *int64
+		stuckOffset   bool
+		handlerErr    error
+		invalidResult bool
+
+		expectedMatches []*types.BlockTransaction
+		expectedPages   int
+		expectedError   error
+	}{
+		"all matches": {
+			expectedMatches: matches,
+			expectedPages:   3,
+		},
+		"from offset": {
+			offset:          types.Int64(3),
+			expectedMatches: matches[3:],
+			expectedPages:   1,
+		},
+		"next offset not increasing": {
+			offset:          types.Int64(1),
+			stuckOffset:     true,
+			expectedMatches: []*types.BlockTransaction{},
+			expectedError:   asserter.ErrNextOffsetNotGreaterThanOffset,
+		},
+		"handler error": {
+			handlerErr:      errors.New("handler failed"),
+			expectedMatches: matches[:2],
+			expectedPages:   1,
+		},
+		"invalid result": {
+			invalidResult:   true,
+			expectedMatches: []*types.BlockTransaction{},
+			expectedError:   asserter.ErrBlockTransactionIsNil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				ctx     = context.Background()
+				request = &types.SearchTransactionsRequest{
+					NetworkIdentifier: basicNetwork,
+					Offset:            test.offset,
+					Limit:             &limit,
+				}
+			)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req *types.SearchTransactionsRequest
+				assert.NoError(json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(limit, *req.Limit)
+
+				start := int64(0)
+				if req.Offset != nil {
+					start = *req.Offset
+				}
+
+				page := []*types.BlockTransaction{}
+				for i := start; i < start+limit && i < int64(len(matches)); i++ {
+					page = append(page, matches[i])
+				}
+
+				if test.invalidResult {
+					page = append(page, nil)
+				}
+
+				var nextOffset *int64
+				if start+limit < int64(len(matches)) {
+					nextOffset = types.Int64(start + limit)
+				}
+
+				if test.stuckOffset {
+					nextOffset = types.Int64(start)
+				}
+
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, types.PrettyPrintStruct(
+					&types.SearchTransactionsResponse{
+						Transactions: page,
+						TotalCount:   int64(len(matches)),
+						NextOffset:   nextOffset,
+					},
+				))
+			}))
+
+			defer ts.Close()
+
+			a, aerr := asserter.NewClientWithOptions(
+				basicNetwork,
+				&types.BlockIdentifier{
+					Index: 0,
+					Hash:  "block 0",
+				},
+				basicNetworkOptions.Allow.OperationTypes,
+				basicNetworkOptions.Allow.OperationStatuses,
+				nil,
+				nil,
+			)
+			assert.NoError(aerr)
+
+			f := New(
+				ts.URL,
+				WithRetryElapsedTime(5*time.Second),
+				WithMaxRetries(1),
+				WithAsserter(a),
+			)
+
+			seen := []*types.BlockTransaction{}
+			pages := 0
+			err := f.SearchTransactionsIterate(
+				ctx,
+				request,
+				func(page []*types.BlockTransaction) error {
+					seen = append(seen, page...)
+					pages++
+
+					return test.handlerErr
+				},
+			)
+			assert.Equal(test.expectedMatches, seen)
+			assert.Equal(test.expectedPages, pages)
+			if test.handlerErr != nil {
+				assert.True(checkError(err, test.handlerErr))
+			} else {
+				assert.True(checkError(err, test.expectedError))
+			}
+
+			// The caller's request is not modified
+			assert.Equal(test.offset, request.Offset)
 		})
 	}
 }
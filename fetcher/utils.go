@@ -15,15 +15,17 @@
 package fetcher
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
+	"math/rand"
+	"net"
 	"strings"
 	"time"
 
-	"github.com/cenkalti/backoff"
-
 	"github.com/coinbase/rosetta-sdk-go/client"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -44,31 +46,133 @@ const (
 	serverClosedIdleConnection = "server closed idle connection"
 )
 
-// Backoff wraps backoff.BackOff so we can
-// access the retry count (which is private
-// on backoff.BackOff).
+// Backoff computes the delay before each retry of a
+// request using exponential backoff with full jitter (each
+// delay is chosen uniformly at random between 0 and
+// min(cap, base * multiplier^attempts)). A new Backoff is
+// created for each request.
 type Backoff struct {
-	backoff  backoff.BackOff
+	base           time.Duration
+	multiplier     float64
+	cap            time.Duration
+	maxRetries     uint64
+	maxElapsedTime time.Duration
+
+	clock  clock
+	jitter func(time.Duration) time.Duration
+
+	start    time.Time
 	attempts int
 }
 
-// backoffRetries creates the backoff.BackOff struct used by all
+// backoffRetries creates the *Backoff used by all
 // *Retry functions in the fetcher.
-func backoffRetries(
-	maxElapsedTime time.Duration,
-	maxRetries uint64,
-) *Backoff {
-	exponentialBackoff := backoff.NewExponentialBackOff()
-	exponentialBackoff.MaxElapsedTime = maxElapsedTime
-	return &Backoff{backoff: backoff.WithMaxRetries(exponentialBackoff, maxRetries)}
+func (f *Fetcher) backoffRetries() *Backoff {
+	return &Backoff{
+		base:           f.retryBase,
+		multiplier:     f.retryMultiplier,
+		cap:            f.retryCap,
+		maxRetries:     f.maxRetries,
+		maxElapsedTime: f.retryElapsedTime,
+		clock:          f.clock,
+		jitter:         f.jitter,
+		start:          f.clock.Now(),
+	}
+}
+
+// next returns the delay before the next retry
+// and a boolean indicating if another retry
+// should be attempted.
+func (b *Backoff) next() (time.Duration, bool) {
+	if uint64(b.attempts) >= b.maxRetries {
+		return 0, false
+	}
+
+	if b.maxElapsedTime > 0 && b.clock.Now().Sub(b.start) > b.maxElapsedTime {
+		return 0, false
+	}
+
+	ceiling := float64(b.base) * math.Pow(b.multiplier, float64(b.attempts))
+	if ceiling > float64(b.cap) {
+		ceiling = float64(b.cap)
+	}
+
+	return b.jitter(time.Duration(ceiling)), true
+}
+
+// fullJitter returns a random duration
+// in [0, max].
+func fullJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(max) + 1)) // #nosec G404
+}
+
+// clock is used to get the current time and to
+// sleep between retries (it is overridden in tests).
+type clock interface {
+	Now() time.Time
+
+	// Sleep returns ctx.Err() if ctx is
+	// done before d has elapsed.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// realClock is a clock that uses
+// the system time.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// requestContext returns a context to use for a single
+// request to an endpoint. If a timeout is set for
+// the endpoint (or a default timeout is set), the context
+// is derived using context.WithTimeout. The returned
+// context.CancelFunc must be called once the request
+// completes.
+func (f *Fetcher) requestContext(
+	ctx context.Context,
+	endpoint Endpoint,
+) (context.Context, context.CancelFunc) {
+	timeout, ok := f.endpointTimeouts[endpoint]
+	if !ok || timeout <= 0 {
+		timeout = f.defaultTimeout
+	}
+
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
 }
 
 // transientError returns a boolean indicating if a particular
 // error is considered transient (so the request should be
 // retried). Currently, we consider EOF, connection reset by
-// peer, and timeout to be transient.
+// peer, timeout (including an exceeded endpoint timeout),
+// and any other network-level (transport) failure to be
+// transient.
 func transientError(err error) bool {
+	var opErr *net.OpError
 	if errors.Is(err, client.ErrRetriable) ||
+		errors.As(err, &opErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
 		strings.Contains(err.Error(), io.EOF.Error()) ||
 		strings.Contains(err.Error(), connectionResetByPeer) ||
 		strings.Contains(err.Error(), serverClosedIdleConnection) ||
@@ -80,20 +184,29 @@ func transientError(err error) bool {
 }
 
 // tryAgain handles a backoff and prints error messages depending
-// on the fetchMsg.
-func tryAgain(fetchMsg string, thisBackoff *Backoff, err *Error) *Error {
+// on the fetchMsg. If ctx is done while waiting to retry, an
+// error is returned immediately.
+func tryAgain(
+	ctx context.Context,
+	fetchMsg string,
+	thisBackoff *Backoff,
+	err *Error,
+) *Error {
 	if !err.Retry {
 		return err
 	}
 
-	nextBackoff := thisBackoff.backoff.NextBackOff()
-	if nextBackoff == backoff.Stop {
+	nextBackoff, ok := thisBackoff.next()
+	if !ok {
 		return &Error{
 			Err: fmt.Errorf(
 				"%w: %s",
 				ErrExhaustedRetries,
 				fetchMsg,
 			),
+			ClientErr:          err.ClientErr,
+			StatusCode:         err.StatusCode,
+			ClientErrAssertion: err.ClientErrAssertion,
 		}
 	}
 
@@ -110,7 +223,10 @@ func tryAgain(fetchMsg string, thisBackoff *Backoff, err *Error) *Error {
 		nextBackoff.Seconds(),
 		thisBackoff.attempts,
 	)
-	time.Sleep(nextBackoff)
+
+	if sleepErr := thisBackoff.clock.Sleep(ctx, nextBackoff); sleepErr != nil {
+		return &Error{Err: sleepErr}
+	}
 
 	return nil
 }
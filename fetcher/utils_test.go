@@ -0,0 +1,196 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a clock that records sleeps
+// and advances time without waiting.
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func retryUntilError(ctx context.Context, b *Backoff) *Error {
+	for {
+		err := tryAgain(ctx, "test", b, &Error{
+			Err:   errors.New("transient"),
+			Retry: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	var tests = map[string]struct {
+		options []Option
+		jitter  func(time.Duration) time.Duration
+
+		expectedSleeps []time.Duration
+	}{
+		"max jitter": {
+			options: []Option{
+				WithRetryBackoff(100*time.Millisecond, 2, time.Second),
+				WithMaxRetries(6),
+			},
+			jitter: func(d time.Duration) time.Duration { return d },
+			expectedSleeps: []time.Duration{
+				100 * time.Millisecond,
+				200 * time.Millisecond,
+				400 * time.Millisecond,
+				800 * time.Millisecond,
+				time.Second,
+				time.Second,
+			},
+		},
+		"half jitter": {
+			options: []Option{
+				WithRetryBackoff(100*time.Millisecond, 3, time.Second),
+				WithMaxRetries(4),
+			},
+			jitter: func(d time.Duration) time.Duration { return d / 2 },
+			expectedSleeps: []time.Duration{
+				50 * time.Millisecond,
+				150 * time.Millisecond,
+				450 * time.Millisecond,
+				500 * time.Millisecond,
+			},
+		},
+		"max elapsed time": {
+			options: []Option{
+				WithRetryBackoff(100*time.Millisecond, 2, time.Second),
+				WithRetryElapsedTime(time.Second),
+			},
+			jitter: func(d time.Duration) time.Duration { return d },
+			expectedSleeps: []time.Duration{
+				100 * time.Millisecond,
+				200 * time.Millisecond,
+				400 * time.Millisecond,
+				800 * time.Millisecond,
+			},
+		},
+		"no retries": {
+			options: []Option{
+				WithMaxRetries(0),
+			},
+			jitter:         fullJitter,
+			expectedSleeps: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Unix(1000, 0)}
+			f := New("", test.options...)
+			f.clock = clock
+			f.jitter = test.jitter
+
+			err := retryUntilError(context.Background(), f.backoffRetries())
+			assert.True(t, errors.Is(err.Err, ErrExhaustedRetries))
+			assert.Equal(t, test.expectedSleeps, clock.sleeps)
+		})
+	}
+}
+
+func TestBackoff_PerRequest(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	f := New(
+		"",
+		WithRetryBackoff(100*time.Millisecond, 2, time.Second),
+		WithMaxRetries(2),
+	)
+	f.clock = clock
+	f.jitter = func(d time.Duration) time.Duration { return d }
+
+	// Each request starts with the base delay.
+	for i := 0; i < 2; i++ {
+		err := retryUntilError(context.Background(), f.backoffRetries())
+		assert.True(t, errors.Is(err.Err, ErrExhaustedRetries))
+	}
+
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+	}, clock.sleeps)
+}
+
+func TestBackoff_NotRetriable(t *testing.T) {
+	f := New("")
+	f.clock = &fakeClock{}
+
+	fetchErr := &Error{Err: errors.New("not retriable")}
+	err := tryAgain(context.Background(), "test", f.backoffRetries(), fetchErr)
+	assert.Equal(t, fetchErr, err)
+}
+
+func TestBackoff_Cancel(t *testing.T) {
+	f := New("", WithRetryBackoff(time.Minute, 2, time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	// The backoff sleep is interrupted immediately
+	// when the context is canceled.
+	f.jitter = func(d time.Duration) time.Duration { return d }
+	start := time.Now()
+	err := retryUntilError(ctx, f.backoffRetries())
+	assert.True(t, errors.Is(err.Err, context.Canceled))
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	// Canceled before waiting
+	clock := &fakeClock{}
+	f.clock = clock
+	err = retryUntilError(ctx, f.backoffRetries())
+	assert.True(t, errors.Is(err.Err, context.Canceled))
+	assert.Len(t, clock.sleeps, 0)
+}
+
+func TestFullJitter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), fullJitter(0))
+
+	for i := 0; i < 1000; i++ {
+		delay := fullJitter(time.Second)
+		assert.True(t, delay >= 0)
+		assert.True(t, delay <= time.Second)
+	}
+}
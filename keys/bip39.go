@@ -0,0 +1,257 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// MinMnemonicEntropyBits is the minimum amount of
+	// entropy (in bits) used to generate a mnemonic (12 words).
+	MinMnemonicEntropyBits = 128
+
+	// MaxMnemonicEntropyBits is the maximum amount of
+	// entropy (in bits) used to generate a mnemonic (24 words).
+	MaxMnemonicEntropyBits = 256
+
+	// mnemonicEntropyBitsMultiple is the multiple that
+	// all mnemonic entropy sizes must be.
+	mnemonicEntropyBitsMultiple = 32
+
+	// bitsPerByte is the number of bits in a byte.
+	bitsPerByte = 8
+
+	// mnemonicBitsPerWord is the number of bits encoded
+	// by each word in a mnemonic.
+	mnemonicBitsPerWord = 11
+
+	// mnemonicWordsMultiple is the multiple that the number
+	// of words in a mnemonic must be (each 3 words encode
+	// 32 bits of entropy and 1 checksum bit).
+	mnemonicWordsMultiple = 3
+
+	// mnemonicSaltPrefix is prepended to the passphrase
+	// to create the salt used in seed derivation.
+	mnemonicSaltPrefix = "mnemonic"
+
+	// mnemonicSeedIterations is the number of PBKDF2
+	// iterations used in seed derivation.
+	mnemonicSeedIterations = 2048
+
+	// mnemonicSeedBytesLen is the length of a seed
+	// derived from a mnemonic.
+	mnemonicSeedBytesLen = 64
+)
+
+// englishWordIndex maps each word in englishWordList
+// to its index.
+var englishWordIndex = func() map[string]int {
+	index := make(map[string]int, len(englishWordList))
+	for i, word := range englishWordList {
+		index[word] = i
+	}
+
+	return index
+}()
+
+// MnemonicError is returned when a mnemonic
+// cannot be decoded.
+type MnemonicError struct {
+	// Word is the word that could not be decoded
+	// (only populated when Err is ErrMnemonicWordInvalid).
+	Word string
+
+	// Index is the position of Word in the mnemonic
+	// (only populated when Err is ErrMnemonicWordInvalid).
+	Index int
+
+	Err error
+}
+
+// Error returns a description of why the
+// mnemonic could not be decoded.
+func (e *MnemonicError) Error() string {
+	if len(e.Word) > 0 {
+		return fmt.Sprintf("%s: %s at index %d", e.Err.Error(), e.Word, e.Index)
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error (so that
+// errors.Is can be used on a *MnemonicError).
+func (e *MnemonicError) Unwrap() error {
+	return e.Err
+}
+
+// GenerateMnemonic returns a new English mnemonic
+// encoding bits of random entropy (as specified in BIP39).
+func GenerateMnemonic(bits int) (string, error) {
+	if bits < MinMnemonicEntropyBits ||
+		bits > MaxMnemonicEntropyBits ||
+		bits%mnemonicEntropyBitsMultiple != 0 {
+		return "", fmt.Errorf("%w: %d bits", ErrMnemonicEntropyLengthInvalid, bits)
+	}
+
+	entropy := make([]byte, bits/bitsPerByte)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrMnemonicGenerationFailed, err.Error())
+	}
+
+	return NewMnemonic(entropy)
+}
+
+// NewMnemonic returns the English mnemonic
+// encoding entropy (as specified in BIP39).
+func NewMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * bitsPerByte
+	if bits < MinMnemonicEntropyBits ||
+		bits > MaxMnemonicEntropyBits ||
+		bits%mnemonicEntropyBitsMultiple != 0 {
+		return "", fmt.Errorf("%w: %d bits", ErrMnemonicEntropyLengthInvalid, bits)
+	}
+
+	// The checksum is the first bits/32 bits of
+	// the SHA256 of the entropy.
+	checksumBits := bits / mnemonicEntropyBitsMultiple
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, uint(checksumBits))
+	data.Or(data, checksum(entropy, checksumBits))
+
+	wordCount := (bits + checksumBits) / mnemonicBitsPerWord
+	words := make([]string, wordCount)
+	mask := big.NewInt(1<<mnemonicBitsPerWord - 1)
+	for i := wordCount - 1; i >= 0; i-- {
+		index := new(big.Int).And(data, mask)
+		words[i] = englishWordList[index.Int64()]
+		data.Rsh(data, mnemonicBitsPerWord)
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy returns the entropy encoded by an
+// English mnemonic, validating its checksum. If the mnemonic
+// cannot be decoded, a *MnemonicError is returned.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words)%mnemonicWordsMultiple != 0 ||
+		len(words)*mnemonicBitsPerWord < MinMnemonicEntropyBits ||
+		len(words)*mnemonicBitsPerWord > MaxMnemonicEntropyBits+
+			MaxMnemonicEntropyBits/mnemonicEntropyBitsMultiple {
+		return nil, &MnemonicError{Err: ErrMnemonicLengthInvalid}
+	}
+
+	data := new(big.Int)
+	for i, word := range words {
+		index, ok := englishWordIndex[word]
+		if !ok {
+			return nil, &MnemonicError{
+				Word:  word,
+				Index: i,
+				Err:   ErrMnemonicWordInvalid,
+			}
+		}
+
+		data.Lsh(data, mnemonicBitsPerWord)
+		data.Or(data, big.NewInt(int64(index)))
+	}
+
+	totalBits := len(words) * mnemonicBitsPerWord
+	checksumBits := totalBits / (mnemonicEntropyBitsMultiple + 1)
+	providedChecksum := new(big.Int).And(
+		data,
+		big.NewInt(1<<uint(checksumBits)-1),
+	)
+	data.Rsh(data, uint(checksumBits))
+
+	// Pad the entropy with leading zeros
+	entropyBytes := data.Bytes()
+	entropy := make([]byte, (totalBits-checksumBits)/bitsPerByte)
+	copy(entropy[len(entropy)-len(entropyBytes):], entropyBytes)
+
+	if checksum(entropy, checksumBits).Cmp(providedChecksum) != 0 {
+		return nil, &MnemonicError{Err: ErrMnemonicChecksumInvalid}
+	}
+
+	return entropy, nil
+}
+
+// ValidateMnemonic returns an error if an English mnemonic
+// contains an unknown word, has an invalid number of
+// words, or has an invalid checksum.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// MnemonicToSeed validates an English mnemonic and returns
+// the seed derived from it and passphrase (as specified in
+// BIP39). The passphrase is used as provided (callers
+// using non-ASCII passphrases must NFKD normalize them).
+func MnemonicToSeed(mnemonic string, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	return pbkdf2.Key(
+		[]byte(strings.Join(strings.Fields(mnemonic), " ")),
+		[]byte(mnemonicSaltPrefix+passphrase),
+		mnemonicSeedIterations,
+		mnemonicSeedBytesLen,
+		sha512.New,
+	), nil
+}
+
+// KeyPairFromMnemonic returns the KeyPair at path for
+// curve derived from the seed of an English mnemonic
+// and passphrase.
+func KeyPairFromMnemonic(
+	mnemonic string,
+	passphrase string,
+	path string,
+	curve types.CurveType,
+) (*KeyPair, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := MasterKeyFromSeed(seed, curve)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeriveChildKey(master, path)
+}
+
+// checksum returns the first bits of the SHA256
+// of entropy.
+func checksum(entropy []byte, bits int) *big.Int {
+	hash := sha256.Sum256(entropy)
+	sum := new(big.Int).SetBytes(hash[:])
+
+	return sum.Rsh(sum, uint(len(hash)*bitsPerByte-bits))
+}
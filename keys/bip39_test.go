@@ -0,0 +1,369 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+type mnemonicVector struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}
+
+// mnemonicPassphrase is used to derive all
+// seeds in mnemonicVectors.
+const mnemonicPassphrase = "TREZOR"
+
+// Official BIP39 test vectors
+// nolint:lll
+var mnemonicVectors = []*mnemonicVector{
+	{
+		entropy:  "00000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		entropy:  "80808080808080808080808080808080",
+		mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		seed:     "d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+	},
+	{
+		entropy:  "ffffffffffffffffffffffffffffffff",
+		mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		seed:     "ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+	{
+		entropy:  "000000000000000000000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon agent",
+		seed:     "035895f2f481b1b0f01fcf8c289c794660b289981a78f8106447707fdd9666ca06da5a9a565181599b79f53b844d8a71dd9f439c52a3d7b3e8a79c906ac845fa",
+	},
+	{
+		entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		mnemonic: "legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal will",
+		seed:     "f2b94508732bcbacbcc020faefecfc89feafa6649a5491b8c952cede496c214a0c7b3c392d168748f2d4a612bada0753b52a1c7ac53c1e93abd5c6320b9e95dd",
+	},
+	{
+		entropy:  "808080808080808080808080808080808080808080808080",
+		mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+		seed:     "107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+	},
+	{
+		entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffff",
+		mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo when",
+		seed:     "0cd6e5d827bb62eb8fc1e262254223817fd068a74b5b449cc2f667c3f1f985a76379b43348d952e2265b4cd129090758b3e3c2c49103b5051aac2eaeb890a528",
+	},
+	{
+		entropy:  "0000000000000000000000000000000000000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		seed:     "bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+	{
+		entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		mnemonic: "legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth title",
+		seed:     "bc09fca1804f7e69da93c2f2028eb238c227f2e9dda30cd63699232578480a4021b146ad717fbb7e451ce9eb835f43620bf5c514db0f8add49f5d121449d3e87",
+	},
+	{
+		entropy:  "8080808080808080808080808080808080808080808080808080808080808080",
+		mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic bless",
+		seed:     "c0c519bd0e91a2ed54357d9d1ebef6f5af218a153624cf4f2da911a0ed8f7a09e2ef61af0aca007096df430022f7a2b6fb91661a9589097069720d015e4e982f",
+	},
+	{
+		entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		seed:     "dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+	},
+	{
+		entropy:  "9e885d952ad362caeb4efe34a8e91bd2",
+		mnemonic: "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic",
+		seed:     "274ddc525802f7c828d8ef7ddbcdc5304e87ac3535913611fbbfa986d0c9e5476c91689f9c8a54fd55bd38606aa6a8595ad213d4c9c9f9aca3fb217069a41028",
+	},
+	{
+		entropy:  "6610b25967cdcca9d59875f5cb50b0ea75433311869e930b",
+		mnemonic: "gravity machine north sort system female filter attitude volume fold club stay feature office ecology stable narrow fog",
+		seed:     "628c3827a8823298ee685db84f55caa34b5cc195a778e52d45f59bcf75aba68e4d7590e101dc414bc1bbd5737666fbbef35d1f1903953b66624f910feef245ac",
+	},
+	{
+		entropy:  "68a79eaca2324873eacc50cb9c6eca8cc68ea5d936f98787c60c7ebc74e6ce7c",
+		mnemonic: "hamster diagram private dutch cause delay private meat slide toddler razor book happy fancy gospel tennis maple dilemma loan word shrug inflict delay length",
+		seed:     "64c87cde7e12ecf6704ab95bb1408bef047c22db4cc7491c4271d170a1b213d20b385bc1588d9c7b38f1b39d415665b8a9030c9ec653d75e65f847d8fc1fc440",
+	},
+	{
+		entropy:  "c0ba5a8e914111210f2bd131f3d5e08d",
+		mnemonic: "scheme spot photo card baby mountain device kick cradle pact join borrow",
+		seed:     "ea725895aaae8d4c1cf682c1bfd2d358d52ed9f0f0591131b559e2724bb234fca05aa9c02c57407e04ee9dc3b454aa63fbff483a8b11de949624b9f1831a9612",
+	},
+	{
+		entropy:  "6d9be1ee6ebd27a258115aad99b7317b9c8d28b6d76431c3",
+		mnemonic: "horn tenant knee talent sponsor spell gate clip pulse soap slush warm silver nephew swap uncle crack brave",
+		seed:     "fd579828af3da1d32544ce4db5c73d53fc8acc4ddb1e3b251a31179cdb71e853c56d2fcb11aed39898ce6c34b10b5382772db8796e52837b54468aeb312cfc3d",
+	},
+	{
+		entropy:  "9f6a2878b2520799a44ef18bc7df394e7061a224d2c33cd015b157d746869863",
+		mnemonic: "panda eyebrow bullet gorilla call smoke muffin taste mesh discover soft ostrich alcohol speed nation flash devote level hobby quick inner drive ghost inside",
+		seed:     "72be8e052fc4919d2adf28d5306b5474b0069df35b02303de8c1729c9538dbb6fc2d731d5f832193cd9fb6aeecbc469594a70e3dd50811b5067f3b88b28c3e8d",
+	},
+	{
+		entropy:  "23db8160a31d3e97dca3688e1b4e1b5e4a3f33ee8fcc85e0",
+		mnemonic: "cat swing flag economy stadium episode income home mix surprise man route physical okay riot wet magnet admit",
+		seed:     "a2a7b1cb334784b6197b043a7c0a2024dcd16a7a1bdce8c5d97d127ff6e9873b6006a7ebf005d12feb200de153b300ac7d84fc7750bbd595f2bccb19bbaea018",
+	},
+	{
+		entropy:  "f30f8c1da665478f49b001d94c5fc452",
+		mnemonic: "vessel ladder alter error federal sibling chat ability sun glass valve picture",
+		seed:     "2aaa9242daafcee6aa9d7269f17d4efe271e1b9a529178d7dc139cd18747090bf9d60295d0ce74309a78852a9caadf0af48aae1c6253839624076224374bc63f",
+	},
+	{
+		entropy:  "c10ec20dc3cd9f652c7fac2f1230f7a3c828389a14392f05",
+		mnemonic: "scissors invite lock maple supreme raw rapid void congress muscle digital elegant little brisk hair mango congress clump",
+		seed:     "7b4a10be9d98e6cba265566db7f136718e1398c71cb581e1b2f464cac1ceedf4f3e274dc270003c670ad8d02c4558b2f8e39edea2775c9e232c7cb798b069e88",
+	},
+	{
+		entropy:  "f585c11aec520db57dd353c69554b21a89b20fb0650966fa0a9d6f74fd989d8f",
+		mnemonic: "void come effort suffer camp survey warrior heavy shoot primary clutch crush open amazing screen patrol group space point ten exist slush involve unfold",
+		seed:     "01f5bced59dec48e362f2c45b5de68b9fd6c92c6634f44d6d40aab69056506f0e35524a518034ddc1192e1dacd32c1ed3eaa3c3b131c88ed8e7e54c49a5d0998",
+	},
+}
+
+func TestMnemonicVectors(t *testing.T) {
+	for _, vector := range mnemonicVectors {
+		t.Run(vector.entropy, func(t *testing.T) {
+			entropy, err := hex.DecodeString(vector.entropy)
+			assert.NoError(t, err)
+
+			mnemonic, err := NewMnemonic(entropy)
+			assert.NoError(t, err)
+			assert.Equal(t, vector.mnemonic, mnemonic)
+
+			decoded, err := MnemonicToEntropy(vector.mnemonic)
+			assert.NoError(t, err)
+			assert.Equal(t, entropy, decoded)
+
+			seed, err := MnemonicToSeed(vector.mnemonic, mnemonicPassphrase)
+			assert.NoError(t, err)
+			assert.Equal(t, vector.seed, hex.EncodeToString(seed))
+		})
+	}
+}
+
+func TestGenerateMnemonic(t *testing.T) {
+	var tests = map[string]struct {
+		bits  int
+		words int
+		err   error
+	}{
+		"128 bits": {
+			bits:  128,
+			words: 12,
+		},
+		"160 bits": {
+			bits:  160,
+			words: 15,
+		},
+		"256 bits": {
+			bits:  256,
+			words: 24,
+		},
+		"too few bits": {
+			bits: 96,
+			err:  ErrMnemonicEntropyLengthInvalid,
+		},
+		"too many bits": {
+			bits: 288,
+			err:  ErrMnemonicEntropyLengthInvalid,
+		},
+		"not a multiple of 32": {
+			bits: 136,
+			err:  ErrMnemonicEntropyLengthInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mnemonic, err := GenerateMnemonic(test.bits)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				assert.Empty(t, mnemonic)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, strings.Fields(mnemonic), test.words)
+			assert.NoError(t, ValidateMnemonic(mnemonic))
+
+			entropy, err := MnemonicToEntropy(mnemonic)
+			assert.NoError(t, err)
+			assert.Len(t, entropy, test.bits/8)
+
+			// Two mnemonics should never be the same
+			other, err := GenerateMnemonic(test.bits)
+			assert.NoError(t, err)
+			assert.NotEqual(t, mnemonic, other)
+		})
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	var tests = map[string]struct {
+		mnemonic string
+		err      error
+		word     string
+		index    int
+	}{
+		"valid": {
+			mnemonic: mnemonicVectors[0].mnemonic,
+		},
+		"extra whitespace": {
+			mnemonic: "  " + strings.Join(strings.Fields(mnemonicVectors[0].mnemonic), "   ") + "\n",
+		},
+		"invalid checksum": {
+			mnemonic: strings.Repeat("abandon ", 11) + "abandon",
+			err:      ErrMnemonicChecksumInvalid,
+		},
+		"unknown word": {
+			mnemonic: strings.Repeat("abandon ", 5) + "rosetta " + strings.Repeat("abandon ", 5) + "about",
+			err:      ErrMnemonicWordInvalid,
+			word:     "rosetta",
+			index:    5,
+		},
+		"uppercase word": {
+			mnemonic: "Abandon " + strings.Repeat("abandon ", 10) + "about",
+			err:      ErrMnemonicWordInvalid,
+			word:     "Abandon",
+			index:    0,
+		},
+		"too few words": {
+			mnemonic: strings.Repeat("abandon ", 8) + "about",
+			err:      ErrMnemonicLengthInvalid,
+		},
+		"too many words": {
+			mnemonic: strings.Repeat("abandon ", 26) + "about",
+			err:      ErrMnemonicLengthInvalid,
+		},
+		"not a multiple of 3": {
+			mnemonic: strings.Repeat("abandon ", 12) + "about",
+			err:      ErrMnemonicLengthInvalid,
+		},
+		"empty": {
+			mnemonic: "",
+			err:      ErrMnemonicLengthInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateMnemonic(test.mnemonic)
+			if test.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, test.err))
+			assert.True(t, Err(err))
+
+			var mnemonicErr *MnemonicError
+			assert.True(t, errors.As(err, &mnemonicErr))
+			assert.Equal(t, test.word, mnemonicErr.Word)
+			assert.Equal(t, test.index, mnemonicErr.Index)
+
+			seed, err := MnemonicToSeed(test.mnemonic, mnemonicPassphrase)
+			assert.True(t, errors.Is(err, test.err))
+			assert.Nil(t, seed)
+		})
+	}
+}
+
+func TestKeyPairFromMnemonic(t *testing.T) {
+	mnemonic := mnemonicVectors[0].mnemonic
+
+	var tests = map[string]struct {
+		curve types.CurveType
+		path  string
+
+		vector *derivationVector
+		err    error
+	}{
+		"secp256k1 (BIP44 bitcoin)": {
+			curve: types.Secp256k1,
+			path:  "m/44'/0'/0'/0/0",
+			vector: &derivationVector{
+				chainCode:  "213909708058e0ec4a99c19d8e041c014ae6c7dc21d2a1fac86772df7ca357a6",
+				privateKey: "e284129cc0922579a535bbf4d1a3b25773090d28c909bc0fed73b5e0222cc372",
+				publicKey:  "03aaeb52dd7494c361049de67cc680e83ebcbbbdbeb13637d92cd845f70308af5e",
+			},
+		},
+		"edwards25519": {
+			curve: types.Edwards25519,
+			path:  "m/44'/501'/0'/0'",
+			vector: &derivationVector{
+				chainCode:  "fb304a2a20f3d97f5240150cd6261a3f7d52e0981381f2c1f935d124d827de83",
+				privateKey: "37df573b3ac4ad5b522e064e25b63ea16bcbe79d449e81a0268d1047948bb445",
+				publicKey:  "f036276246a75b9de3349ed42b15e232f6518fc20f5fcd4f1d64e81f9bd258f7",
+			},
+		},
+		"edwards25519 not hardened": {
+			curve: types.Edwards25519,
+			path:  "m/44'/501'/0'/0",
+			err:   ErrDerivationNotHardened,
+		},
+		"unsupported curve": {
+			curve: types.Secp256r1,
+			path:  "m/44'/0'/0'/0/0",
+			err:   ErrCurveTypeNotSupported,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			keyPair, err := KeyPairFromMnemonic(mnemonic, "", test.path, test.curve)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				assert.Nil(t, keyPair)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NoError(t, keyPair.IsValid())
+			assert.Equal(t, test.vector.chainCode, hex.EncodeToString(keyPair.ChainCode))
+			assert.Equal(t, test.vector.privateKey, hex.EncodeToString(keyPair.PrivateKey))
+			assert.Equal(t, test.vector.publicKey, hex.EncodeToString(keyPair.PublicKey.Bytes))
+			assert.Equal(t, test.curve, keyPair.PublicKey.CurveType)
+		})
+	}
+
+	t.Run("invalid mnemonic", func(t *testing.T) {
+		keyPair, err := KeyPairFromMnemonic(
+			strings.Repeat("abandon ", 12),
+			"",
+			"m/44'/0'/0'/0/0",
+			types.Secp256k1,
+		)
+		assert.True(t, errors.Is(err, ErrMnemonicChecksumInvalid))
+		assert.Nil(t, keyPair)
+	})
+}
@@ -25,7 +25,9 @@ var (
 	ErrPrivKeyUndecodable   = errors.New("could not decode privkey")
 	ErrPrivKeyLengthInvalid = errors.New("invalid privkey length")
 	ErrPrivKeyZero          = errors.New("privkey cannot be 0")
+	ErrPrivKeyOutOfRange    = errors.New("privkey is not less than the curve order")
 	ErrPubKeyNotOnCurve     = errors.New("pubkey is not on the curve")
+	ErrPubKeyLengthInvalid  = errors.New("invalid pubkey length")
 
 	ErrKeyGenSecp256k1Failed = errors.New(
 		"keygen: error generating key pair for secp256k1 curve type",
@@ -38,6 +40,20 @@ var (
 	)
 	ErrCurveTypeNotSupported = errors.New("not a supported CurveType")
 
+	ErrChainCodeLengthInvalid = errors.New("invalid chain code length")
+	ErrChainCodeMissing       = errors.New("chain code is missing")
+	ErrSeedLengthInvalid      = errors.New("invalid seed length")
+	ErrDerivationPathInvalid  = errors.New("invalid derivation path")
+	ErrDerivationNotHardened  = errors.New("curve only supports hardened derivation")
+	ErrDerivedKeyInvalid      = errors.New("derived key is invalid")
+	ErrParentKeyInvalid       = errors.New("parent key is invalid")
+
+	ErrMnemonicEntropyLengthInvalid = errors.New("invalid mnemonic entropy length")
+	ErrMnemonicGenerationFailed     = errors.New("unable to generate mnemonic")
+	ErrMnemonicLengthInvalid        = errors.New("invalid number of mnemonic words")
+	ErrMnemonicWordInvalid          = errors.New("mnemonic word is not in word list")
+	ErrMnemonicChecksumInvalid      = errors.New("invalid mnemonic checksum")
+
 	ErrSignUnsupportedPayloadSignatureType = errors.New(
 		"sign: unexpected payload.SignatureType while signing",
 	)
@@ -62,11 +78,25 @@ func Err(err error) bool {
 		ErrPrivKeyUndecodable,
 		ErrPrivKeyLengthInvalid,
 		ErrPrivKeyZero,
+		ErrPrivKeyOutOfRange,
 		ErrPubKeyNotOnCurve,
+		ErrPubKeyLengthInvalid,
 		ErrKeyGenSecp256k1Failed,
 		ErrKeyGenSecp256r1Failed,
 		ErrKeyGenEdwards25519Failed,
 		ErrCurveTypeNotSupported,
+		ErrChainCodeLengthInvalid,
+		ErrChainCodeMissing,
+		ErrSeedLengthInvalid,
+		ErrDerivationPathInvalid,
+		ErrDerivationNotHardened,
+		ErrDerivedKeyInvalid,
+		ErrParentKeyInvalid,
+		ErrMnemonicEntropyLengthInvalid,
+		ErrMnemonicGenerationFailed,
+		ErrMnemonicLengthInvalid,
+		ErrMnemonicWordInvalid,
+		ErrMnemonicChecksumInvalid,
 		ErrSignUnsupportedPayloadSignatureType,
 		ErrSignUnsupportedSignatureType,
 		ErrSignFailed,
@@ -0,0 +1,283 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// ChainCodeBytesLen is the length of a chain code
+	// used in hierarchical key derivation.
+	ChainCodeBytesLen = 32
+
+	// HardenedKeyStart is the index of the first hardened
+	// child key (2^31).
+	HardenedKeyStart uint32 = 0x80000000
+
+	// MinSeedBytesLen is the minimum length of a seed
+	// used to create a master key (128 bits).
+	MinSeedBytesLen = 16
+
+	// MaxSeedBytesLen is the maximum length of a seed
+	// used to create a master key (512 bits).
+	MaxSeedBytesLen = 64
+
+	// derivationPathRoot is the first element of every
+	// derivation path (the master or parent key).
+	derivationPathRoot = "m"
+
+	// childDataBytesLen is the length of the data used to
+	// derive a child key (a 33-byte key and a 4-byte index).
+	childDataBytesLen = 37
+
+	// indexBytesLen is the length of an encoded child index.
+	indexBytesLen = 4
+)
+
+// masterKeySeeds are the HMAC keys used to create a master key
+// from a seed for each supported CurveType (as specified
+// in BIP32 and SLIP-10).
+var masterKeySeeds = map[types.CurveType][]byte{
+	types.Secp256k1:    []byte("Bitcoin seed"),
+	types.Edwards25519: []byte("ed25519 seed"),
+}
+
+// hmacSHA512 returns the left and right 32 bytes of
+// HMAC-SHA512(key, data).
+func hmacSHA512(key []byte, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	_, _ = mac.Write(data)
+	sum := mac.Sum(nil)
+
+	return sum[:32], sum[32:]
+}
+
+// MasterKeyFromSeed returns the master KeyPair (including its
+// ChainCode) for a seed using BIP32 for Secp256k1 and SLIP-10
+// for Edwards25519.
+func MasterKeyFromSeed(seed []byte, curve types.CurveType) (*KeyPair, error) {
+	if len(seed) < MinSeedBytesLen || len(seed) > MaxSeedBytesLen {
+		return nil, fmt.Errorf(
+			"%w: expected between %d and %d bytes but got %d",
+			ErrSeedLengthInvalid,
+			MinSeedBytesLen,
+			MaxSeedBytesLen,
+			len(seed),
+		)
+	}
+
+	hmacKey, ok := masterKeySeeds[curve]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCurveTypeNotSupported, curve)
+	}
+
+	privKey, chainCode := hmacSHA512(hmacKey, seed)
+	if curve == types.Secp256k1 && !secp256k1ScalarValid(new(big.Int).SetBytes(privKey)) {
+		return nil, ErrDerivedKeyInvalid
+	}
+
+	return keyPairWithChainCode(privKey, chainCode, curve)
+}
+
+// ParseDerivationPath parses a derivation path in standard notation
+// (ex: m/44'/60'/0'/0/0) into child indexes. Hardened indexes may be
+// denoted with ', h, or H.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if components[0] != derivationPathRoot {
+		return nil, fmt.Errorf(
+			"%w: %s must start with %s",
+			ErrDerivationPathInvalid,
+			path,
+			derivationPathRoot,
+		)
+	}
+
+	indexes := make([]uint32, len(components)-1)
+	for i, component := range components[1:] {
+		offset := uint32(0)
+		if trimmed := strings.TrimRight(component, "'hH"); trimmed != component {
+			if len(component)-len(trimmed) != 1 {
+				return nil, fmt.Errorf(
+					"%w: %s has invalid component %s",
+					ErrDerivationPathInvalid,
+					path,
+					component,
+				)
+			}
+
+			component = trimmed
+			offset = HardenedKeyStart
+		}
+
+		index, err := strconv.ParseUint(component, 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf(
+				"%w: %s has invalid component %s",
+				ErrDerivationPathInvalid,
+				path,
+				component,
+			)
+		}
+
+		indexes[i] = uint32(index) + offset
+	}
+
+	return indexes, nil
+}
+
+// DeriveChildKey derives a child KeyPair from a parent KeyPair
+// (which must have a ChainCode) using a derivation path relative
+// to the parent (ex: m/44'/60'/0'/0/0). Secp256k1 keys are derived
+// using BIP32 and Edwards25519 keys are derived using SLIP-10 (which
+// only supports hardened derivation).
+func DeriveChildKey(parent *KeyPair, path string) (*KeyPair, error) {
+	if parent == nil || parent.PublicKey == nil {
+		return nil, ErrParentKeyInvalid
+	}
+
+	if err := parent.IsValid(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParentKeyInvalid, err)
+	}
+
+	if len(parent.ChainCode) == 0 {
+		return nil, ErrChainCodeMissing
+	}
+
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := parent.PublicKey.CurveType
+	if _, ok := masterKeySeeds[curve]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCurveTypeNotSupported, curve)
+	}
+
+	child := parent
+	for _, index := range indexes {
+		var privKey, chainCode []byte
+		switch curve {
+		case types.Secp256k1:
+			privKey, chainCode, err = deriveSecp256k1(child, index)
+		case types.Edwards25519:
+			privKey, chainCode, err = deriveEdwards25519(child, index)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: at index %d of %s", err, index, path)
+		}
+
+		child, err = keyPairWithChainCode(privKey, chainCode, curve)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if child == parent {
+		return keyPairWithChainCode(parent.PrivateKey, parent.ChainCode, curve)
+	}
+
+	return child, nil
+}
+
+// deriveSecp256k1 returns the private key and chain code
+// of a child key using BIP32.
+func deriveSecp256k1(parent *KeyPair, index uint32) ([]byte, []byte, error) {
+	data := make([]byte, 0, childDataBytesLen)
+	if index >= HardenedKeyStart {
+		data = append(data, 0x0)
+		data = append(data, parent.PrivateKey...)
+	} else {
+		_, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), parent.PrivateKey)
+		data = append(data, pubKey.SerializeCompressed()...)
+	}
+	data = appendIndex(data, index)
+
+	il, chainCode := hmacSHA512(parent.ChainCode, data)
+	tweak := new(big.Int).SetBytes(il)
+	if tweak.Cmp(btcec.S256().N) >= 0 {
+		return nil, nil, ErrDerivedKeyInvalid
+	}
+
+	childKey := tweak.Add(tweak, new(big.Int).SetBytes(parent.PrivateKey))
+	childKey.Mod(childKey, btcec.S256().N)
+	if !secp256k1ScalarValid(childKey) {
+		return nil, nil, ErrDerivedKeyInvalid
+	}
+
+	privKey := make([]byte, PrivKeyBytesLen)
+	childKeyBytes := childKey.Bytes()
+	copy(privKey[PrivKeyBytesLen-len(childKeyBytes):], childKeyBytes)
+
+	return privKey, chainCode, nil
+}
+
+// deriveEdwards25519 returns the private key and chain code
+// of a child key using SLIP-10.
+func deriveEdwards25519(parent *KeyPair, index uint32) ([]byte, []byte, error) {
+	if index < HardenedKeyStart {
+		return nil, nil, ErrDerivationNotHardened
+	}
+
+	data := make([]byte, 0, childDataBytesLen)
+	data = append(data, 0x0)
+	data = append(data, parent.PrivateKey...)
+	data = appendIndex(data, index)
+
+	privKey, chainCode := hmacSHA512(parent.ChainCode, data)
+	return privKey, chainCode, nil
+}
+
+// appendIndex appends the big-endian encoding of
+// a child index to data.
+func appendIndex(data []byte, index uint32) []byte {
+	encodedIndex := make([]byte, indexBytesLen)
+	binary.BigEndian.PutUint32(encodedIndex, index)
+
+	return append(data, encodedIndex...)
+}
+
+// secp256k1ScalarValid returns a boolean indicating if a scalar
+// is a valid secp256k1 private key (0 < scalar < N).
+func secp256k1ScalarValid(scalar *big.Int) bool {
+	return scalar.Sign() > 0 && scalar.Cmp(btcec.S256().N) < 0
+}
+
+// keyPairWithChainCode returns a KeyPair with a ChainCode
+// from raw private key bytes.
+func keyPairWithChainCode(
+	privKey []byte,
+	chainCode []byte,
+	curve types.CurveType,
+) (*KeyPair, error) {
+	keyPair, err := keyPairFromPrivateKey(privKey, curve)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPair.ChainCode = append([]byte{}, chainCode...)
+	return keyPair, nil
+}
@@ -0,0 +1,286 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+type derivationVector struct {
+	path       string
+	chainCode  string
+	privateKey string
+	publicKey  string
+}
+
+// Test vector 1 from BIP32 and SLIP-10
+// (seed 000102030405060708090a0b0c0d0e0f)
+var (
+	derivationSeed = "000102030405060708090a0b0c0d0e0f"
+
+	secp256k1DerivationVectors = []*derivationVector{
+		{
+			path:       "m",
+			chainCode:  "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+			privateKey: "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+			publicKey:  "0339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2",
+		},
+		{
+			path:       "m/0'",
+			chainCode:  "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+			privateKey: "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+			publicKey:  "035a784662a4a20a65bf6aab9ae98a6c068a81c52e4b032c0fb5400c706cfccc56",
+		},
+		{
+			path:       "m/0'/1",
+			chainCode:  "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19",
+			privateKey: "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+			publicKey:  "03501e454bf00751f24b1b489aa925215d66af2234e3891c3b21a52bedb3cd711c",
+		},
+		{
+			path:       "m/0'/1/2'",
+			chainCode:  "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f",
+			privateKey: "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+			publicKey:  "0357bfe1e341d01c69fe5654309956cbea516822fba8a601743a012a7896ee8dc2",
+		},
+		{
+			path:       "m/0'/1/2'/2",
+			chainCode:  "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd",
+			privateKey: "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4",
+			publicKey:  "02e8445082a72f29b75ca48748a914df60622a609cacfce8ed0e35804560741d29",
+		},
+		{
+			path:       "m/0'/1/2'/2/1000000000",
+			chainCode:  "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e",
+			privateKey: "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+			publicKey:  "022a471424da5e657499d1ff51cb43c47481a03b1e77f951fe64cec9f5a48f7011",
+		},
+	}
+
+	// SLIP-10 prefixes Edwards25519 public keys with 0x00,
+	// which is omitted here.
+	edwards25519DerivationVectors = []*derivationVector{
+		{
+			path:       "m",
+			chainCode:  "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+			privateKey: "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+			publicKey:  "a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed",
+		},
+		{
+			path:       "m/0H",
+			chainCode:  "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+			privateKey: "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+			publicKey:  "8c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c",
+		},
+		{
+			path:       "m/0H/1H",
+			chainCode:  "a320425f77d1b5c2505a6b1b27382b37368ee640e3557c315416801243552f14",
+			privateKey: "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2",
+			publicKey:  "1932a5270f335bed617d5b935c80aedb1a35bd9fc1e31acafd5372c30f5c1187",
+		},
+		{
+			path:       "m/0H/1H/2H",
+			chainCode:  "2e69929e00b5ab250f49c3fb1c12f252de4fed2c1db88387094a0f8c4c9ccd6c",
+			privateKey: "92a5b23c0b8a99e37d07df3fb9966917f5d06e02ddbd909c7e184371463e9fc9",
+			publicKey:  "ae98736566d30ed0e9d2f4486a64bc95740d89c7db33f52121f8ea8f76ff0fc1",
+		},
+		{
+			path:       "m/0H/1H/2H/2H",
+			chainCode:  "8f6d87f93d750e0efccda017d662a1b31a266e4a6f5993b15f5c1f07f74dd5cc",
+			privateKey: "30d1dc7e5fc04c31219ab25a27ae00b50f6fd66622f6e9c913253d6511d1e662",
+			publicKey:  "8abae2d66361c879b900d204ad2cc4984fa2aa344dd7ddc46007329ac76c429c",
+		},
+		{
+			path:       "m/0H/1H/2H/2H/1000000000H",
+			chainCode:  "68789923a0cac2cd5a29172a475fe9e0fb14cd6adb5ad98a3fa70333e7afa230",
+			privateKey: "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793",
+			publicKey:  "3c24da049451555d51a7014a37337aa4e12d41e485abccfa46b47dfb2af54b7a",
+		},
+	}
+)
+
+func TestDeriveChildKey(t *testing.T) {
+	seed, err := hex.DecodeString(derivationSeed)
+	assert.NoError(t, err)
+
+	var tests = map[types.CurveType][]*derivationVector{
+		types.Secp256k1:    secp256k1DerivationVectors,
+		types.Edwards25519: edwards25519DerivationVectors,
+	}
+
+	for curve, vectors := range tests {
+		t.Run(string(curve), func(t *testing.T) {
+			master, err := MasterKeyFromSeed(seed, curve)
+			assert.NoError(t, err)
+
+			var previous *KeyPair
+			for i, vector := range vectors {
+				child, err := DeriveChildKey(master, vector.path)
+				assert.NoError(t, err)
+				assert.Equal(t, vector.chainCode, hex.EncodeToString(child.ChainCode))
+				assert.Equal(t, vector.privateKey, hex.EncodeToString(child.PrivateKey))
+				assert.Equal(t, vector.publicKey, hex.EncodeToString(child.PublicKey.Bytes))
+				assert.Equal(t, curve, child.PublicKey.CurveType)
+
+				// Deriving a single level from the previous key
+				// should produce the same key.
+				if previous != nil {
+					path := "m/" + vector.path[len(vectors[i-1].path)+1:]
+					fromPrevious, err := DeriveChildKey(previous, path)
+					assert.NoError(t, err)
+					assert.Equal(t, child, fromPrevious)
+				}
+				previous = child
+			}
+		})
+	}
+}
+
+func TestDerivedKeySigning(t *testing.T) {
+	seed, err := hex.DecodeString(derivationSeed)
+	assert.NoError(t, err)
+
+	var tests = map[types.CurveType]types.SignatureType{
+		types.Secp256k1:    types.Ecdsa,
+		types.Edwards25519: types.Ed25519,
+	}
+
+	for curve, signatureType := range tests {
+		t.Run(string(curve), func(t *testing.T) {
+			master, err := MasterKeyFromSeed(seed, curve)
+			assert.NoError(t, err)
+
+			child, err := DeriveChildKey(master, "m/44'/60'/0'")
+			assert.NoError(t, err)
+
+			// Derived keys should survive JSON encoding
+			encoded, err := json.Marshal(child)
+			assert.NoError(t, err)
+			var decoded KeyPair
+			assert.NoError(t, json.Unmarshal(encoded, &decoded))
+			assert.Equal(t, child, &decoded)
+
+			signer, err := decoded.Signer()
+			assert.NoError(t, err)
+
+			payload := &types.SigningPayload{
+				AccountIdentifier: &types.AccountIdentifier{Address: "test"},
+				Bytes:             []byte("12345678901234567890123456789012"),
+				SignatureType:     signatureType,
+			}
+			signature, err := signer.Sign(payload, signatureType)
+			assert.NoError(t, err)
+			assert.NoError(t, signer.Verify(signature))
+		})
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	var tests = map[string]struct {
+		path string
+
+		indexes []uint32
+		err     error
+	}{
+		"root": {
+			path:    "m",
+			indexes: []uint32{},
+		},
+		"bip44": {
+			path: "m/44'/60'/0'/0/0",
+			indexes: []uint32{
+				HardenedKeyStart + 44,
+				HardenedKeyStart + 60,
+				HardenedKeyStart,
+				0,
+				0,
+			},
+		},
+		"hardened notation": {
+			path:    "m/1h/2H/3'",
+			indexes: []uint32{HardenedKeyStart + 1, HardenedKeyStart + 2, HardenedKeyStart + 3},
+		},
+		"max index": {
+			path:    "m/2147483647'",
+			indexes: []uint32{0xffffffff},
+		},
+		"missing root": {
+			path: "44'/60'",
+			err:  ErrDerivationPathInvalid,
+		},
+		"empty component": {
+			path: "m/44'//0",
+			err:  ErrDerivationPathInvalid,
+		},
+		"index too large": {
+			path: "m/2147483648",
+			err:  ErrDerivationPathInvalid,
+		},
+		"double hardened": {
+			path: "m/1''",
+			err:  ErrDerivationPathInvalid,
+		},
+		"negative index": {
+			path: "m/-1",
+			err:  ErrDerivationPathInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			indexes, err := ParseDerivationPath(test.path)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				assert.Nil(t, indexes)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.indexes, indexes)
+		})
+	}
+}
+
+func TestDeriveChildKeyErrors(t *testing.T) {
+	seed, err := hex.DecodeString(derivationSeed)
+	assert.NoError(t, err)
+
+	edwards25519Master, err := MasterKeyFromSeed(seed, types.Edwards25519)
+	assert.NoError(t, err)
+
+	_, err = DeriveChildKey(edwards25519Master, "m/0'/1")
+	assert.True(t, errors.Is(err, ErrDerivationNotHardened))
+
+	generated, err := GenerateKeypair(types.Secp256k1)
+	assert.NoError(t, err)
+	_, err = DeriveChildKey(generated, "m/0")
+	assert.True(t, errors.Is(err, ErrChainCodeMissing))
+
+	_, err = DeriveChildKey(nil, "m/0")
+	assert.True(t, errors.Is(err, ErrParentKeyInvalid))
+
+	_, err = MasterKeyFromSeed(seed[:MinSeedBytesLen-1], types.Secp256k1)
+	assert.True(t, errors.Is(err, ErrSeedLengthInvalid))
+
+	_, err = MasterKeyFromSeed(seed, types.Secp256r1)
+	assert.True(t, errors.Is(err, ErrCurveTypeNotSupported))
+}
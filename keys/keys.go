@@ -22,6 +22,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/btcsuite/btcd/btcec"
 
@@ -52,13 +53,50 @@ func privateKeyValid(privateKey []byte) error {
 	return nil
 }
 
-// ImportPrivateKey returns a Keypair from a hex-encoded privkey string
+// privateKeyInRange returns an error if privateKey
+// is not a valid scalar for curve (in [1, N-1]). Any 32-byte
+// seed is a valid Edwards25519 private key.
+func privateKeyInRange(privateKey []byte, curve types.CurveType) error {
+	var order *big.Int
+	switch curve {
+	case types.Secp256k1:
+		order = btcec.S256().N
+	case types.Secp256r1:
+		order = elliptic.P256().Params().N
+	default:
+		return nil
+	}
+
+	if new(big.Int).SetBytes(privateKey).Cmp(order) >= 0 {
+		return fmt.Errorf("%w: %s", ErrPrivKeyOutOfRange, curve)
+	}
+
+	return nil
+}
+
+// ImportPrivateKey returns a Keypair from a hex-encoded privkey
+// string (with or without a 0x prefix). The private key must
+// be a valid scalar for Secp256k1 and Secp256r1 and a 32-byte
+// seed for Edwards25519.
 func ImportPrivateKey(privKeyHex string, curve types.CurveType) (*KeyPair, error) {
+	privKeyHex = strings.TrimSpace(privKeyHex)
+	if strings.HasPrefix(privKeyHex, "0x") || strings.HasPrefix(privKeyHex, "0X") {
+		privKeyHex = privKeyHex[2:]
+	}
+
+	// We intentionally omit the provided string from the
+	// error to avoid leaking private keys into logs.
 	privKey, err := hex.DecodeString(privKeyHex)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrPrivKeyUndecodable, privKeyHex)
+		return nil, fmt.Errorf("%w: %s", ErrPrivKeyUndecodable, err.Error())
 	}
 
+	return keyPairFromPrivateKey(privKey, curve)
+}
+
+// keyPairFromPrivateKey returns a KeyPair from raw private
+// key bytes.
+func keyPairFromPrivateKey(privKey []byte, curve types.CurveType) (*KeyPair, error) {
 	// We check the parsed private key length to ensure we don't panic (most
 	// crypto libraries panic with incorrect private key lengths instead of
 	// throwing an error).
@@ -66,6 +104,10 @@ func ImportPrivateKey(privKeyHex string, curve types.CurveType) (*KeyPair, error
 		return nil, err
 	}
 
+	if err := privateKeyInRange(privKey, curve); err != nil {
+		return nil, err
+	}
+
 	var keyPair *KeyPair
 	switch curve {
 	case types.Secp256k1:
@@ -103,20 +145,16 @@ func ImportPrivateKey(privKeyHex string, curve types.CurveType) (*KeyPair, error
 			return nil, ErrPubKeyNotOnCurve
 		}
 
-		rawPubKey := ecdsa.PublicKey{X: x, Y: y, Curve: crv}
-		rawPrivKey := ecdsa.PrivateKey{
-			PublicKey: rawPubKey,
-			D:         new(big.Int).SetBytes(privKey),
-		}
-
 		pubKey := &types.PublicKey{
-			Bytes:     elliptic.Marshal(crv, rawPubKey.X, rawPubKey.Y),
+			Bytes:     elliptic.Marshal(crv, x, y),
 			CurveType: curve,
 		}
 
+		// We use the provided bytes instead of D.Bytes()
+		// to preserve any leading zeros.
 		keyPair = &KeyPair{
 			PublicKey:  pubKey,
-			PrivateKey: rawPrivKey.D.Bytes(),
+			PrivateKey: privKey,
 		}
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrCurveTypeNotSupported, curve)
@@ -181,7 +219,7 @@ func GenerateKeypair(curve types.CurveType) (*KeyPair, error) {
 
 		keyPair = &KeyPair{
 			PublicKey:  pubKey,
-			PrivateKey: rawPrivKey.D.Bytes(),
+			PrivateKey: paddedBytes(rawPrivKey.D),
 		}
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrCurveTypeNotSupported, curve)
@@ -206,9 +244,42 @@ func (k *KeyPair) IsValid() error {
 		return err
 	}
 
+	if len(k.ChainCode) > 0 && len(k.ChainCode) != ChainCodeBytesLen {
+		return fmt.Errorf(
+			"%w: expected %d bytes but got %v",
+			ErrChainCodeLengthInvalid,
+			ChainCodeBytesLen,
+			len(k.ChainCode),
+		)
+	}
+
 	return nil
 }
 
+// Zeroize overwrites the private key (and chain code) of
+// the KeyPair with zeros. A zeroized KeyPair is no longer
+// valid and cannot be used to sign.
+func (k *KeyPair) Zeroize() {
+	zeroBytes(k.PrivateKey)
+	zeroBytes(k.ChainCode)
+}
+
+// zeroBytes overwrites b with zeros.
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// zeroInt overwrites the memory backing i with zeros.
+func zeroInt(i *big.Int) {
+	words := i.Bits()
+	for j := range words {
+		words[j] = 0
+	}
+	i.SetInt64(0)
+}
+
 // Signer returns the constructs a Signer
 // for the KeyPair.
 func (k *KeyPair) Signer() (Signer, error) {
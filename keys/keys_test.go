@@ -18,6 +18,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -137,6 +138,51 @@ func TestImportPrivateKey(t *testing.T) {
 			types.Edwards25519,
 			ErrPrivKeyLengthInvalid,
 		},
+		"0x prefix": {
+			"0x0b188af56b25d007fbc4bbf2176cd2a54d876ce4774bb5df38b7c83349405b7a",
+			types.Secp256k1,
+			nil,
+		},
+		"0X prefix with whitespace": {
+			" 0X0B188AF56B25D007FBC4BBF2176CD2A54D876CE4774BB5DF38B7C83349405B7A\n",
+			types.Secp256k1,
+			nil,
+		},
+		"invalid hex": {
+			"0xzz188af56b25d007fbc4bbf2176cd2a54d876ce4774bb5df38b7c83349405b7a",
+			types.Secp256k1,
+			ErrPrivKeyUndecodable,
+		},
+		"zero Secp256k1": {
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			types.Secp256k1,
+			ErrPrivKeyZero,
+		},
+		"Secp256k1 curve order": {
+			"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+			types.Secp256k1,
+			ErrPrivKeyOutOfRange,
+		},
+		"Secp256k1 above curve order": {
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			types.Secp256k1,
+			ErrPrivKeyOutOfRange,
+		},
+		"Secp256r1 curve order": {
+			"ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551",
+			types.Secp256r1,
+			ErrPrivKeyOutOfRange,
+		},
+		"Edwards25519 max seed": {
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			types.Edwards25519,
+			nil,
+		},
+		"unsupported curve": {
+			"0b188af56b25d007fbc4bbf2176cd2a54d876ce4774bb5df38b7c83349405b7a",
+			types.Tweedle,
+			ErrCurveTypeNotSupported,
+		},
 	}
 
 	for name, test := range importPrivKeyTests {
@@ -152,3 +198,104 @@ func TestImportPrivateKey(t *testing.T) {
 		})
 	}
 }
+
+func TestImportPrivateKeyVectors(t *testing.T) {
+	var tests = map[string]struct {
+		privKey   string
+		curveType types.CurveType
+		pubKey    string
+	}{
+		"Secp256k1 generator": {
+			privKey:   "0000000000000000000000000000000000000000000000000000000000000001",
+			curveType: types.Secp256k1,
+			pubKey:    "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+		"Secp256k1 curve order - 1": {
+			privKey:   "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+			curveType: types.Secp256k1,
+			pubKey:    "0379be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+		"Secp256k1 (BIP340 vector 1)": {
+			privKey:   "0xB7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+			curveType: types.Secp256k1,
+			pubKey:    "02dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659",
+		},
+		"Secp256r1 generator": {
+			privKey:   "0000000000000000000000000000000000000000000000000000000000000001",
+			curveType: types.Secp256r1,
+			pubKey:    "046b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c2964fe342e2fe1a7f9b8ee7eb4a7c0f9e162bce33576b315ececbb6406837bf51f5", // nolint:lll
+		},
+		"Secp256r1 leading zero": {
+			privKey:   "00c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f67",
+			curveType: types.Secp256r1,
+			pubKey:    "04686896ddb46ec3bc8579acf40094d683975fcd3271e948d4769dfd34f4ec22c868edc94466c7f23c821ed580baeaef86de6467fad273cb5cb896318e26e28477", // nolint:lll
+		},
+		"Edwards25519 (RFC 8032 test 1)": {
+			privKey:   "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+			curveType: types.Edwards25519,
+			pubKey:    "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			kp, err := ImportPrivateKey(test.privKey, test.curveType)
+			assert.NoError(t, err)
+			assert.NoError(t, kp.IsValid())
+			assert.Equal(t, test.pubKey, hex.EncodeToString(kp.PublicKey.Bytes))
+			assert.Equal(t, test.curveType, kp.PublicKey.CurveType)
+
+			// The private key should be preserved (including
+			// any leading zeros).
+			privKeyHex := strings.ToLower(strings.TrimPrefix(test.privKey, "0x"))
+			assert.Equal(t, privKeyHex, hex.EncodeToString(kp.PrivateKey))
+
+			// The imported key should be usable for signing
+			signer, err := kp.Signer()
+			assert.NoError(t, err)
+			assert.Equal(t, kp.PublicKey, signer.PublicKey())
+		})
+	}
+}
+
+func TestKeyPairZeroize(t *testing.T) {
+	var tests = map[types.CurveType]types.SignatureType{
+		types.Secp256k1:    types.Ecdsa,
+		types.Secp256r1:    types.Ecdsa,
+		types.Edwards25519: types.Ed25519,
+	}
+
+	for curve, signatureType := range tests {
+		t.Run(string(curve), func(t *testing.T) {
+			keyPair, err := GenerateKeypair(curve)
+			assert.NoError(t, err)
+			keyPair.ChainCode = make([]byte, ChainCodeBytesLen)
+			copy(keyPair.ChainCode, "chain code")
+
+			signer, err := keyPair.Signer()
+			assert.NoError(t, err)
+
+			payload := &types.SigningPayload{
+				AccountIdentifier: &types.AccountIdentifier{Address: "test"},
+				Bytes:             []byte("12345678901234567890123456789012"),
+				SignatureType:     signatureType,
+			}
+			_, err = signer.Sign(payload, signatureType)
+			assert.NoError(t, err)
+
+			// Zeroize should overwrite the existing bytes
+			// (instead of replacing the slices).
+			privateKey := keyPair.PrivateKey
+			chainCode := keyPair.ChainCode
+			keyPair.Zeroize()
+			assert.Equal(t, make([]byte, PrivKeyBytesLen), privateKey)
+			assert.Equal(t, make([]byte, ChainCodeBytesLen), chainCode)
+			assert.Len(t, keyPair.PrivateKey, PrivKeyBytesLen)
+
+			// Signing should fail cleanly after the key is zeroized
+			signature, err := signer.Sign(payload, signatureType)
+			assert.True(t, errors.Is(err, ErrPrivKeyZero))
+			assert.Nil(t, signature)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+const (
+	// SchnorrBip340SignatureLen is 64 bytes
+	SchnorrBip340SignatureLen = 64
+
+	// SchnorrBip340PublicKeyLen is the length of an
+	// x-only public key (as specified in BIP340).
+	SchnorrBip340PublicKeyLen = 32
+
+	// compressedPubKeyBytesLen is the length of a
+	// SEC compressed secp256k1 public key.
+	compressedPubKeyBytesLen = 33
+
+	// scalarBytesLen is the length of an encoded
+	// secp256k1 scalar or field element.
+	scalarBytesLen = 32
+
+	bip340AuxTag       = "BIP0340/aux"
+	bip340NonceTag     = "BIP0340/nonce"
+	bip340ChallengeTag = "BIP0340/challenge"
+)
+
+// XOnlyPublicKey returns the 32-byte x-only representation
+// of a secp256k1 public key (as specified in BIP340). Both
+// SEC compressed and x-only public keys are accepted.
+func XOnlyPublicKey(pubKey []byte) ([]byte, error) {
+	switch len(pubKey) {
+	case SchnorrBip340PublicKeyLen:
+		return pubKey, nil
+	case compressedPubKeyBytesLen:
+		return pubKey[1:], nil
+	default:
+		return nil, fmt.Errorf(
+			"%w: expected %d or %d bytes but got %d",
+			ErrPubKeyLengthInvalid,
+			SchnorrBip340PublicKeyLen,
+			compressedPubKeyBytesLen,
+			len(pubKey),
+		)
+	}
+}
+
+// signSchnorrBip340 signs message using privKey with
+// random auxiliary data (as specified in BIP340).
+func signSchnorrBip340(privKey []byte, message []byte) ([]byte, error) {
+	auxRand := make([]byte, scalarBytesLen)
+	if _, err := rand.Read(auxRand); err != nil {
+		return nil, err
+	}
+
+	return signSchnorrBip340WithAux(privKey, message, auxRand)
+}
+
+// signSchnorrBip340WithAux signs message using privKey and
+// auxRand (as specified in BIP340). The produced signature
+// is verified before it is returned.
+func signSchnorrBip340WithAux(privKey []byte, message []byte, auxRand []byte) ([]byte, error) {
+	curve := btcec.S256()
+
+	d := new(big.Int).SetBytes(privKey)
+	defer zeroInt(d)
+	if !secp256k1ScalarValid(d) {
+		return nil, fmt.Errorf("%w: private key is not a valid scalar", ErrSignFailed)
+	}
+
+	// Negate the private key if its public key
+	// has an odd y-coordinate.
+	px, py := curve.ScalarBaseMult(paddedBytes(d))
+	if py.Bit(0) == 1 {
+		d.Sub(curve.N, d)
+	}
+	pubKey := paddedBytes(px)
+
+	t := paddedBytes(d)
+	defer zeroBytes(t)
+	auxHash := taggedHash(bip340AuxTag, auxRand)
+	for i := range t {
+		t[i] ^= auxHash[i]
+	}
+
+	k := new(big.Int).SetBytes(taggedHash(bip340NonceTag, t, pubKey, message))
+	defer zeroInt(k)
+	k.Mod(k, curve.N)
+	if k.Sign() == 0 {
+		return nil, ErrSignFailed
+	}
+
+	// Negate the nonce if its point has an
+	// odd y-coordinate.
+	rx, ry := curve.ScalarBaseMult(paddedBytes(k))
+	if ry.Bit(0) == 1 {
+		k.Sub(curve.N, k)
+	}
+	r := paddedBytes(rx)
+
+	e := new(big.Int).SetBytes(taggedHash(bip340ChallengeTag, r, pubKey, message))
+	e.Mod(e, curve.N)
+
+	s := e.Mul(e, d)
+	s.Add(s, k)
+	s.Mod(s, curve.N)
+
+	sig := append(r, paddedBytes(s)...)
+	if !verifySchnorrBip340(pubKey, message, sig) {
+		return nil, ErrSignFailed
+	}
+
+	return sig, nil
+}
+
+// verifySchnorrBip340 returns a boolean indicating if sig
+// is a valid signature of message by the x-only pubKey (as
+// specified in BIP340).
+func verifySchnorrBip340(pubKey []byte, message []byte, sig []byte) bool {
+	if len(pubKey) != SchnorrBip340PublicKeyLen || len(sig) != SchnorrBip340SignatureLen {
+		return false
+	}
+
+	curve := btcec.S256()
+	px, py, ok := liftX(new(big.Int).SetBytes(pubKey))
+	if !ok {
+		return false
+	}
+
+	r := new(big.Int).SetBytes(sig[:scalarBytesLen])
+	s := new(big.Int).SetBytes(sig[scalarBytesLen:])
+	if r.Cmp(curve.P) >= 0 || s.Cmp(curve.N) >= 0 {
+		return false
+	}
+
+	e := new(big.Int).SetBytes(
+		taggedHash(bip340ChallengeTag, sig[:scalarBytesLen], pubKey, message),
+	)
+	e.Mod(e, curve.N)
+
+	// R = s*G - e*P
+	sx, sy := curve.ScalarBaseMult(paddedBytes(s))
+	ex, ey := curve.ScalarMult(px, py, paddedBytes(e))
+	if ey.Sign() != 0 {
+		ey.Sub(curve.P, ey)
+	}
+	rx, ry := curve.Add(sx, sy, ex, ey)
+
+	// The point at infinity is represented as (0, 0)
+	if rx.Sign() == 0 && ry.Sign() == 0 {
+		return false
+	}
+
+	return ry.Bit(0) == 0 && rx.Cmp(r) == 0
+}
+
+// liftX returns the point on secp256k1 with x-coordinate x
+// and an even y-coordinate (if one exists).
+func liftX(x *big.Int) (*big.Int, *big.Int, bool) {
+	curve := btcec.S256()
+	if x.Cmp(curve.P) >= 0 {
+		return nil, nil, false
+	}
+
+	// y^2 = x^3 + 7
+	ySquared := new(big.Int).Mul(x, x)
+	ySquared.Mul(ySquared, x)
+	ySquared.Add(ySquared, curve.B)
+	ySquared.Mod(ySquared, curve.P)
+
+	// P = 3 mod 4, so y = (y^2)^((P+1)/4)
+	y := new(big.Int).Exp(ySquared, curve.QPlus1Div4(), curve.P)
+	if check := new(big.Int).Mul(y, y); check.Mod(check, curve.P).Cmp(ySquared) != 0 {
+		return nil, nil, false
+	}
+
+	if y.Bit(0) == 1 {
+		y.Sub(curve.P, y)
+	}
+
+	return x, y, true
+}
+
+// taggedHash returns SHA256(SHA256(tag) || SHA256(tag) || msgs...)
+// (as specified in BIP340).
+func taggedHash(tag string, msgs ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+
+	h := sha256.New()
+	_, _ = h.Write(tagHash[:])
+	_, _ = h.Write(tagHash[:])
+	for _, msg := range msgs {
+		_, _ = h.Write(msg)
+	}
+
+	return h.Sum(nil)
+}
+
+// paddedBytes returns the 32-byte big-endian
+// encoding of a scalar or field element.
+func paddedBytes(i *big.Int) []byte {
+	return append(
+		bytes.Repeat([]byte{0}, scalarBytesLen-len(i.Bytes())),
+		i.Bytes()...,
+	)
+}
@@ -62,9 +62,11 @@ func (s *SignerEdwards25519) Sign(
 		)
 	}
 
-	privKeyBytes := s.KeyPair.PrivateKey
-	privKey := ed25519.NewKeyFromSeed(privKeyBytes)
+	// The expanded private key is only needed
+	// to sign, so we zero it before returning.
+	privKey := ed25519.NewKeyFromSeed(s.KeyPair.PrivateKey)
 	sig := ed25519.Sign(privKey, payload.Bytes)
+	zeroBytes(privKey)
 
 	return &types.Signature{
 		SigningPayload: payload,
@@ -76,6 +76,11 @@ func (s *SignerSecp256k1) Sign(
 		if err != nil {
 			return nil, fmt.Errorf("%w: %s", ErrSignFailed, err.Error())
 		}
+	case types.SchnorrBip340:
+		sig, err = signSchnorrBip340(privKeyBytes, payload.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrSignFailed, err.Error())
+		}
 	default:
 		return nil, fmt.Errorf("%w: %v", ErrSignUnsupportedSignatureType, err)
 	}
@@ -109,6 +114,12 @@ func (s *SignerSecp256k1) Verify(signature *types.Signature) error {
 		verify = secp256k1.VerifySignature(pubKey, message, normalizedSig)
 	case types.Schnorr1:
 		verify = zil_schnorr.VerifySignature(pubKey, message, sig)
+	case types.SchnorrBip340:
+		xOnlyPubKey, err := XOnlyPublicKey(pubKey)
+		if err != nil {
+			return err
+		}
+		verify = verifySchnorrBip340(xOnlyPubKey, message, sig)
 	default:
 		return fmt.Errorf("%w: %s", ErrVerifyUnsupportedSignatureType, signature.SignatureType)
 	}
@@ -20,6 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -55,6 +56,13 @@ func TestSignSecp256k1(t *testing.T) {
 			ErrSignUnsupportedSignatureType,
 		},
 		{mockPayload(hash("hello1234"), types.Schnorr1), types.Schnorr1, 64, false, nil},
+		{
+			mockPayload(hash("hello1234"), types.SchnorrBip340),
+			types.SchnorrBip340,
+			64,
+			false,
+			nil,
+		},
 	}
 
 	for _, test := range payloadTests {
@@ -113,7 +121,16 @@ func TestVerifySecp256k1(t *testing.T) {
 	}
 	testSignatureEcdsa, _ := signerSecp256k1.Sign(payloadEcdsa, types.Ecdsa)
 	testSignatureEcdsaRecovery, _ := signerSecp256k1.Sign(payloadEcdsaRecovery, types.EcdsaRecovery)
+	payloadSchnorrBip340 := &types.SigningPayload{
+		AccountIdentifier: &types.AccountIdentifier{Address: "test"},
+		Bytes:             hash("hello"),
+		SignatureType:     types.SchnorrBip340,
+	}
 	testSignatureSchnorr1, _ := signerSecp256k1.Sign(payloadSchnorr1, types.Schnorr1)
+	testSignatureSchnorrBip340, _ := signerSecp256k1.Sign(
+		payloadSchnorrBip340,
+		types.SchnorrBip340,
+	)
 
 	simpleBytes := make([]byte, 33)
 	copy(simpleBytes, "hello")
@@ -134,6 +151,23 @@ func TestVerifySecp256k1(t *testing.T) {
 			signerSecp256k1.PublicKey(),
 			hash("hello"),
 			simpleBytes), ErrVerifyFailed},
+		{mockSecpSignature(
+			types.SchnorrBip340,
+			signerSecp256k1.PublicKey(),
+			hash("hello"),
+			append(simpleBytes, simpleBytes[:31]...)), ErrVerifyFailed},
+		// schnorr_1 signatures are created with the Zilliqa
+		// Schnorr scheme, so they are not valid BIP340 signatures
+		{mockSecpSignature(
+			types.SchnorrBip340,
+			signerSecp256k1.PublicKey(),
+			hash("hello"),
+			testSignatureSchnorr1.Bytes), ErrVerifyFailed},
+		{mockSecpSignature(
+			types.SchnorrBip340,
+			signerSecp256k1.PublicKey(),
+			hash("hello"),
+			simpleBytes), asserter.ErrSignatureBytesLengthInvalid},
 	}
 
 	for _, test := range signatureTests {
@@ -156,7 +190,23 @@ func TestVerifySecp256k1(t *testing.T) {
 		signerSecp256k1.PublicKey(),
 		hash("hello"),
 		testSignatureSchnorr1.Bytes)
+	goodSchnorrBip340Signature := mockSecpSignature(
+		types.SchnorrBip340,
+		signerSecp256k1.PublicKey(),
+		hash("hello"),
+		testSignatureSchnorrBip340.Bytes)
 	assert.Equal(t, nil, signerSecp256k1.Verify(goodEcdsaSignature))
 	assert.Equal(t, nil, signerSecp256k1.Verify(goodEcdsaRecoverySignature))
 	assert.Equal(t, nil, signerSecp256k1.Verify(goodSchnorr1Signature))
+	assert.Equal(t, nil, signerSecp256k1.Verify(goodSchnorrBip340Signature))
+
+	// BIP340 signatures can also be verified with x-only public keys
+	xOnlyPubKey, err := XOnlyPublicKey(signerSecp256k1.PublicKey().Bytes)
+	assert.NoError(t, err)
+	goodSchnorrBip340XOnlySignature := mockSecpSignature(
+		types.SchnorrBip340,
+		&types.PublicKey{Bytes: xOnlyPubKey, CurveType: types.Secp256k1},
+		hash("hello"),
+		testSignatureSchnorrBip340.Bytes)
+	assert.Equal(t, nil, signerSecp256k1.Verify(goodSchnorrBip340XOnlySignature))
 }
@@ -89,6 +89,7 @@ func (s *SignerSecp256r1) Sign(
 	}
 
 	sigR, sigS, err := ecdsa.Sign(rand.Reader, &privKey, payload.Bytes)
+	zeroInt(privKey.D)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrSignFailed, err.Error())
 	}
@@ -21,10 +21,13 @@ import (
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
-// KeyPair contains a PrivateKey and its associated PublicKey
+// KeyPair contains a PrivateKey and its associated PublicKey.
+// KeyPairs created using hierarchical derivation also contain
+// the ChainCode used to derive child keys.
 type KeyPair struct {
 	PublicKey  *types.PublicKey `json:"public_key"`
 	PrivateKey []byte           `json:"private_key"`
+	ChainCode  []byte           `json:"chain_code,omitempty"`
 }
 
 // MarshalJSON overrides the default JSON marshaler
@@ -33,9 +36,11 @@ func (k *KeyPair) MarshalJSON() ([]byte, error) {
 	type Alias KeyPair
 	j, err := json.Marshal(struct {
 		PrivateKey string `json:"private_key"`
+		ChainCode  string `json:"chain_code,omitempty"`
 		*Alias
 	}{
 		PrivateKey: hex.EncodeToString(k.PrivateKey),
+		ChainCode:  hex.EncodeToString(k.ChainCode),
 		Alias:      (*Alias)(k),
 	})
 	if err != nil {
@@ -50,6 +55,7 @@ func (k *KeyPair) UnmarshalJSON(b []byte) error {
 	type Alias KeyPair
 	r := struct {
 		PrivateKey string `json:"private_key"`
+		ChainCode  string `json:"chain_code,omitempty"`
 		*Alias
 	}{
 		Alias: (*Alias)(k),
@@ -63,5 +69,15 @@ func (k *KeyPair) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	k.PrivateKey = bytes
+
+	if len(r.ChainCode) == 0 {
+		return nil
+	}
+
+	chainCode, err := hex.DecodeString(r.ChainCode)
+	if err != nil {
+		return err
+	}
+	k.ChainCode = chainCode
 	return nil
 }
@@ -16,6 +16,22 @@ type Database struct {
 	mock.Mock
 }
 
+// BulkWrite provides a mock function with given fields: _a0
+func (_m *Database) BulkWrite(_a0 context.Context) database.BulkWriter {
+	ret := _m.Called(_a0)
+
+	var r0 database.BulkWriter
+	if rf, ok := ret.Get(0).(func(context.Context) database.BulkWriter); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(database.BulkWriter)
+		}
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields: _a0
 func (_m *Database) Close(_a0 context.Context) error {
 	ret := _m.Called(_a0)
@@ -46,6 +62,29 @@ func (_m *Database) Encoder() *encoder.Encoder {
 	return r0
 }
 
+// GetDatabaseStats provides a mock function with given fields: _a0
+func (_m *Database) GetDatabaseStats(_a0 context.Context) (*database.DatabaseStats, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *database.DatabaseStats
+	if rf, ok := ret.Get(0).(func(context.Context) *database.DatabaseStats); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*database.DatabaseStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadTransaction provides a mock function with given fields: _a0
 func (_m *Database) ReadTransaction(_a0 context.Context) database.Transaction {
 	ret := _m.Called(_a0)
@@ -62,6 +101,20 @@ func (_m *Database) ReadTransaction(_a0 context.Context) database.Transaction {
 	return r0
 }
 
+// RunGarbageCollection provides a mock function with given fields: ctx, discardRatio
+func (_m *Database) RunGarbageCollection(ctx context.Context, discardRatio float64) error {
+	ret := _m.Called(ctx, discardRatio)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, float64) error); ok {
+		r0 = rf(ctx, discardRatio)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Transaction provides a mock function with given fields: _a0
 func (_m *Database) Transaction(_a0 context.Context) database.Transaction {
 	ret := _m.Called(_a0)
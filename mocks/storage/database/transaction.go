@@ -5,6 +5,8 @@ package database
 import (
 	context "context"
 
+	time "time"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -97,6 +99,50 @@ func (_m *Transaction) Scan(_a0 context.Context, _a1 []byte, _a2 []byte, _a3 fun
 	return r0, r1
 }
 
+// ScanKeys provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4, _a5
+func (_m *Transaction) ScanKeys(_a0 context.Context, _a1 []byte, _a2 []byte, _a3 func([]byte, []byte) error, _a4 bool, _a5 bool) (int, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4, _a5)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte, func([]byte, []byte) error, bool, bool) int); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, []byte, func([]byte, []byte) error, bool, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ScanPage provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4, _a5
+func (_m *Transaction) ScanPage(_a0 context.Context, _a1 []byte, _a2 []byte, _a3 int, _a4 func([]byte, []byte) error, _a5 bool) ([]byte, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4, _a5)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte, int, func([]byte, []byte) error, bool) []byte); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, []byte, int, func([]byte, []byte) error, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Set provides a mock function with given fields: _a0, _a1, _a2, _a3
 func (_m *Transaction) Set(_a0 context.Context, _a1 []byte, _a2 []byte, _a3 bool) error {
 	ret := _m.Called(_a0, _a1, _a2, _a3)
@@ -110,3 +156,17 @@ func (_m *Transaction) Set(_a0 context.Context, _a1 []byte, _a2 []byte, _a3 bool
 
 	return r0
 }
+
+// SetWithTTL provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *Transaction) SetWithTTL(_a0 context.Context, _a1 []byte, _a2 []byte, _a3 time.Duration, _a4 bool) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte, time.Duration, bool) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
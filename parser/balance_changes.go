@@ -17,6 +17,7 @@ package parser
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -30,15 +31,88 @@ type BalanceChange struct {
 	Difference string                   `json:"difference,omitempty"`
 }
 
+// BalanceChangeCategory is the cause of a balance change.
+type BalanceChangeCategory string
+
+const (
+	// FeeCategory is a balance change caused by paying
+	// (or receiving) a fee.
+	FeeCategory BalanceChangeCategory = "fee"
+
+	// RewardCategory is a balance change caused by a block
+	// reward (ex: coinbase or staking rewards).
+	RewardCategory BalanceChangeCategory = "reward"
+
+	// TransferCategory is any balance change not caused by
+	// a fee or a reward.
+	TransferCategory BalanceChangeCategory = "transfer"
+)
+
+// OperationClassifier is a function that returns the
+// BalanceChangeCategory of an operation with a given
+// operation.Type.
+type OperationClassifier func(operationType string) BalanceChangeCategory
+
+// DefaultOperationClassifier classifies operations using common
+// operation type names. Any operation type containing "FEE" is
+// considered a fee, any operation type containing "REWARD" or
+// "COINBASE" is considered a reward, and all other operation types
+// are considered transfers.
+func DefaultOperationClassifier(operationType string) BalanceChangeCategory {
+	normalized := strings.ToUpper(operationType)
+
+	switch {
+	case strings.Contains(normalized, "FEE"):
+		return FeeCategory
+	case strings.Contains(normalized, "REWARD"), strings.Contains(normalized, "COINBASE"):
+		return RewardCategory
+	default:
+		return TransferCategory
+	}
+}
+
+// BalanceChangeDetailed is a BalanceChange that also includes
+// the subtotal of each BalanceChangeCategory that contributed
+// to the net Difference.
+type BalanceChangeDetailed struct {
+	*BalanceChange
+
+	Subtotals map[BalanceChangeCategory]string `json:"subtotals,omitempty"`
+}
+
+// BalanceChangeFilter is a function that returns a boolean
+// indicating if balance changes for a *types.AccountIdentifier
+// and *types.Currency should be computed.
+type BalanceChangeFilter func(*types.AccountIdentifier, *types.Currency) bool
+
 // ExemptOperation is a function that returns a boolean indicating
 // if the operation should be skipped eventhough it passes other
 // checks indiciating it should be considered a balance change.
 type ExemptOperation func(*types.Operation) bool
 
+// ExemptOperationWithContext is a function that returns a boolean
+// indicating if the operation should be skipped. Unlike ExemptOperation,
+// it also receives the *types.Transaction containing the operation
+// (so that operations can be exempted based on transaction metadata).
+type ExemptOperationWithContext func(*types.Transaction, *types.Operation) bool
+
+// WithContext adapts an ExemptOperation to an ExemptOperationWithContext
+// (the transaction is ignored). If the ExemptOperation is nil, nil
+// is returned.
+func (e ExemptOperation) WithContext() ExemptOperationWithContext {
+	if e == nil {
+		return nil
+	}
+
+	return func(_ *types.Transaction, op *types.Operation) bool {
+		return e(op)
+	}
+}
+
 // skipOperation returns a boolean indicating whether
 // an operation should be processed. An operation will
 // not be processed if it is considered unsuccessful.
-func (p *Parser) skipOperation(op *types.Operation) (bool, error) {
+func (p *Parser) skipOperation(tx *types.Transaction, op *types.Operation) (bool, error) {
 	successful, err := p.Asserter.OperationSuccessful(op)
 	if err != nil {
 		// Should only occur if responses not validated
@@ -63,6 +137,10 @@ func (p *Parser) skipOperation(op *types.Operation) (bool, error) {
 		return true, nil
 	}
 
+	if p.ExemptWithContextFunc != nil && p.ExemptWithContextFunc(tx, op) {
+		return true, nil
+	}
+
 	return false, nil
 }
 
@@ -77,10 +155,63 @@ func (p *Parser) BalanceChanges(
 	block *types.Block,
 	blockRemoved bool,
 ) ([]*BalanceChange, error) {
-	balanceChanges := map[string]*BalanceChange{}
+	return p.BalanceChangesFiltered(ctx, block, blockRemoved, nil)
+}
+
+// BalanceChangesFiltered returns the balance changes for
+// a particular block (like BalanceChanges) but only for
+// the account and currency pairs where include returns
+// true. If include is nil, all balance changes are returned.
+func (p *Parser) BalanceChangesFiltered(
+	ctx context.Context,
+	block *types.Block,
+	blockRemoved bool,
+	include BalanceChangeFilter,
+) ([]*BalanceChange, error) {
+	detailedChanges, err := p.balanceChanges(ctx, block, blockRemoved, include, false)
+	if err != nil {
+		return nil, err
+	}
+
+	allChanges := make([]*BalanceChange, len(detailedChanges))
+	for i, change := range detailedChanges {
+		allChanges[i] = change.BalanceChange
+	}
+
+	return allChanges, nil
+}
+
+// BalanceChangesDetailed returns the same balance changes as
+// BalanceChanges but also populates the subtotal of each
+// BalanceChangeCategory that contributed to each change (as
+// determined by the Parser's OperationClassifier).
+func (p *Parser) BalanceChangesDetailed(
+	ctx context.Context,
+	block *types.Block,
+	blockRemoved bool,
+) ([]*BalanceChangeDetailed, error) {
+	return p.balanceChanges(ctx, block, blockRemoved, nil, true)
+}
+
+// balanceChanges sums all balance changes in a block by
+// account and currency (skipping any pairs excluded by include).
+// Subtotals are only populated if detailed is true.
+func (p *Parser) balanceChanges(
+	ctx context.Context,
+	block *types.Block,
+	blockRemoved bool,
+	include BalanceChangeFilter,
+	detailed bool,
+) ([]*BalanceChangeDetailed, error) {
+	classifier := p.Classifier
+	if classifier == nil {
+		classifier = DefaultOperationClassifier
+	}
+
+	balanceChanges := map[string]*BalanceChangeDetailed{}
 	for _, tx := range block.Transactions {
 		for _, op := range tx.Operations {
-			skip, err := p.skipOperation(op)
+			skip, err := p.skipOperation(tx, op)
 			if err != nil {
 				return nil, err
 			}
@@ -88,6 +219,10 @@ func (p *Parser) BalanceChanges(
 				continue
 			}
 
+			if include != nil && !include(op.Account, op.Amount.Currency) {
+				continue
+			}
+
 			// We create a copy of Amount.Value
 			// here to ensure we don't accidentally overwrite
 			// the value of op.Amount.
@@ -110,26 +245,48 @@ func (p *Parser) BalanceChanges(
 
 			val, ok := balanceChanges[key]
 			if !ok {
-				balanceChanges[key] = &BalanceChange{
-					Account:    op.Account,
-					Currency:   op.Amount.Currency,
-					Difference: amountValue,
-					Block:      blockIdentifier,
+				val = &BalanceChangeDetailed{
+					BalanceChange: &BalanceChange{
+						Account:    op.Account,
+						Currency:   op.Amount.Currency,
+						Difference: amountValue,
+						Block:      blockIdentifier,
+					},
 				}
+				balanceChanges[key] = val
+			} else {
+				newDifference, err := types.AddValues(val.Difference, amountValue)
+				if err != nil {
+					return nil, err
+				}
+				val.Difference = newDifference
+			}
+
+			if !detailed {
+				continue
+			}
+
+			if val.Subtotals == nil {
+				val.Subtotals = map[BalanceChangeCategory]string{}
+			}
+
+			category := classifier(op.Type)
+			subtotal, ok := val.Subtotals[category]
+			if !ok {
+				val.Subtotals[category] = amountValue
 				continue
 			}
 
-			newDifference, err := types.AddValues(val.Difference, amountValue)
+			newSubtotal, err := types.AddValues(subtotal, amountValue)
 			if err != nil {
 				return nil, err
 			}
-			val.Difference = newDifference
-			balanceChanges[key] = val
+			val.Subtotals[category] = newSubtotal
 		}
 	}
 
 	i := 0
-	allChanges := make([]*BalanceChange, len(balanceChanges))
+	allChanges := make([]*BalanceChangeDetailed, len(balanceChanges))
 	for _, change := range balanceChanges {
 		allChanges[i] = change
 		i++
@@ -279,6 +279,481 @@ func TestBalanceChanges(t *testing.T) {
 	}
 }
 
+func TestBalanceChangesDetailed(t *testing.T) {
+	currency := &types.Currency{
+		Symbol:   "Blah",
+		Decimals: 2,
+	}
+	miner := &types.AccountIdentifier{
+		Address: "miner",
+	}
+	recipient := &types.AccountIdentifier{
+		Address: "recipient",
+	}
+	blockIdentifier := &types.BlockIdentifier{
+		Hash:  "1",
+		Index: 1,
+	}
+
+	operation := func(
+		index int64,
+		opType string,
+		account *types.AccountIdentifier,
+		value string,
+	) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: index,
+			},
+			Type:    opType,
+			Status:  types.String("Success"),
+			Account: account,
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: currency,
+			},
+		}
+	}
+
+	block := &types.Block{
+		BlockIdentifier: blockIdentifier,
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Hash:  "0",
+			Index: 0,
+		},
+		Transactions: []*types.Transaction{
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "coinbase",
+				},
+				Operations: []*types.Operation{
+					operation(0, "BLOCK_REWARD", miner, "500"),
+				},
+			},
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx1",
+				},
+				Operations: []*types.Operation{
+					operation(0, "Transfer", miner, "-100"),
+					operation(1, "Transfer", recipient, "100"),
+					operation(2, "Fee", miner, "-3"),
+				},
+			},
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx2",
+				},
+				Operations: []*types.Operation{
+					operation(0, "Transfer", recipient, "-20"),
+					operation(1, "Transfer", miner, "20"),
+					operation(2, "FEE", recipient, "-2"),
+					operation(3, "FEE", miner, "2"),
+				},
+			},
+		},
+	}
+
+	var tests = map[string]struct {
+		orphan     bool
+		classifier OperationClassifier
+
+		changes []*BalanceChangeDetailed
+	}{
+		"mixed operations": {
+			changes: []*BalanceChangeDetailed{
+				{
+					BalanceChange: &BalanceChange{
+						Account:    miner,
+						Currency:   currency,
+						Block:      blockIdentifier,
+						Difference: "419",
+					},
+					Subtotals: map[BalanceChangeCategory]string{
+						RewardCategory:   "500",
+						TransferCategory: "-80",
+						FeeCategory:      "-1",
+					},
+				},
+				{
+					BalanceChange: &BalanceChange{
+						Account:    recipient,
+						Currency:   currency,
+						Block:      blockIdentifier,
+						Difference: "78",
+					},
+					Subtotals: map[BalanceChangeCategory]string{
+						TransferCategory: "80",
+						FeeCategory:      "-2",
+					},
+				},
+			},
+		},
+		"mixed operations orphan": {
+			orphan: true,
+			changes: []*BalanceChangeDetailed{
+				{
+					BalanceChange: &BalanceChange{
+						Account:    miner,
+						Currency:   currency,
+						Block:      blockIdentifier,
+						Difference: "-419",
+					},
+					Subtotals: map[BalanceChangeCategory]string{
+						RewardCategory:   "-500",
+						TransferCategory: "80",
+						FeeCategory:      "1",
+					},
+				},
+				{
+					BalanceChange: &BalanceChange{
+						Account:    recipient,
+						Currency:   currency,
+						Block:      blockIdentifier,
+						Difference: "-78",
+					},
+					Subtotals: map[BalanceChangeCategory]string{
+						TransferCategory: "-80",
+						FeeCategory:      "2",
+					},
+				},
+			},
+		},
+		"custom classifier": {
+			classifier: func(operationType string) BalanceChangeCategory {
+				return TransferCategory
+			},
+			changes: []*BalanceChangeDetailed{
+				{
+					BalanceChange: &BalanceChange{
+						Account:    miner,
+						Currency:   currency,
+						Block:      blockIdentifier,
+						Difference: "419",
+					},
+					Subtotals: map[BalanceChangeCategory]string{
+						TransferCategory: "419",
+					},
+				},
+				{
+					BalanceChange: &BalanceChange{
+						Account:    recipient,
+						Currency:   currency,
+						Block:      blockIdentifier,
+						Difference: "78",
+					},
+					Subtotals: map[BalanceChangeCategory]string{
+						TransferCategory: "78",
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+				{
+					Status:     "Success",
+					Successful: true,
+				},
+			})
+			assert.NoError(t, err)
+			assert.NotNil(t, asserter)
+
+			parser := New(asserter, nil, nil)
+			parser.Classifier = test.classifier
+
+			changes, err := parser.BalanceChangesDetailed(
+				context.Background(),
+				block,
+				test.orphan,
+			)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, test.changes, changes)
+
+			// BalanceChanges should only return the net difference
+			netChanges, err := parser.BalanceChanges(
+				context.Background(),
+				block,
+				test.orphan,
+			)
+			assert.NoError(t, err)
+
+			expectedNetChanges := make([]*BalanceChange, len(test.changes))
+			for i, change := range test.changes {
+				expectedNetChanges[i] = change.BalanceChange
+			}
+			assert.ElementsMatch(t, expectedNetChanges, netChanges)
+		})
+	}
+}
+
+func TestBalanceChangesFiltered(t *testing.T) {
+	currency := &types.Currency{
+		Symbol:   "Blah",
+		Decimals: 2,
+	}
+	otherCurrency := &types.Currency{
+		Symbol:   "Other",
+		Decimals: 2,
+	}
+	interesting := &types.AccountIdentifier{
+		Address: "interesting",
+	}
+	boring := &types.AccountIdentifier{
+		Address: "boring",
+	}
+	blockIdentifier := &types.BlockIdentifier{
+		Hash:  "1",
+		Index: 1,
+	}
+	block := &types.Block{
+		BlockIdentifier: blockIdentifier,
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Hash:  "0",
+			Index: 0,
+		},
+		Transactions: []*types.Transaction{
+			simpleTransactionFactory("tx1", "interesting", "100", currency),
+			simpleTransactionFactory("tx2", "boring", "200", currency),
+			simpleTransactionFactory("tx3", "interesting", "-5", currency),
+			simpleTransactionFactory("tx4", "interesting", "300", otherCurrency),
+		},
+	}
+
+	var tests = map[string]struct {
+		include BalanceChangeFilter
+		orphan  bool
+
+		changes []*BalanceChange
+	}{
+		"no filter": {
+			changes: []*BalanceChange{
+				{
+					Account:    interesting,
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "95",
+				},
+				{
+					Account:    boring,
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "200",
+				},
+				{
+					Account:    interesting,
+					Currency:   otherCurrency,
+					Block:      blockIdentifier,
+					Difference: "300",
+				},
+			},
+		},
+		"filter by account": {
+			include: func(account *types.AccountIdentifier, currency *types.Currency) bool {
+				return account.Address == "interesting"
+			},
+			changes: []*BalanceChange{
+				{
+					Account:    interesting,
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "95",
+				},
+				{
+					Account:    interesting,
+					Currency:   otherCurrency,
+					Block:      blockIdentifier,
+					Difference: "300",
+				},
+			},
+		},
+		"filter by account and currency (orphan)": {
+			include: func(account *types.AccountIdentifier, curr *types.Currency) bool {
+				return account.Address == "interesting" && types.Hash(curr) == types.Hash(currency)
+			},
+			orphan: true,
+			changes: []*BalanceChange{
+				{
+					Account:    interesting,
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "-95",
+				},
+			},
+		},
+		"exclude all": {
+			include: func(*types.AccountIdentifier, *types.Currency) bool {
+				return false
+			},
+			changes: []*BalanceChange{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+				{
+					Status:     "Success",
+					Successful: true,
+				},
+			})
+			assert.NoError(t, err)
+			assert.NotNil(t, asserter)
+
+			parser := New(asserter, nil, nil)
+			changes, err := parser.BalanceChangesFiltered(
+				context.Background(),
+				block,
+				test.orphan,
+				test.include,
+			)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, test.changes, changes)
+		})
+	}
+}
+
+func TestBalanceChangesExemptWithContext(t *testing.T) {
+	currency := &types.Currency{
+		Symbol:   "Blah",
+		Decimals: 2,
+	}
+	blockIdentifier := &types.BlockIdentifier{
+		Hash:  "1",
+		Index: 1,
+	}
+	synthetic := simpleTransactionFactory("synthetic", "acct1", "100", currency)
+	synthetic.Metadata = map[string]interface{}{
+		"synthetic": true,
+	}
+	block := &types.Block{
+		BlockIdentifier: blockIdentifier,
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Hash:  "0",
+			Index: 0,
+		},
+		Transactions: []*types.Transaction{
+			simpleTransactionFactory("tx1", "acct1", "50", currency),
+			synthetic,
+			simpleTransactionFactory("tx2", "acct2", "20", currency),
+		},
+	}
+	exemptSynthetic := func(tx *types.Transaction, op *types.Operation) bool {
+		isSynthetic, ok := tx.Metadata["synthetic"].(bool)
+		return ok && isSynthetic
+	}
+
+	var tests = map[string]struct {
+		exemptFunc            ExemptOperation
+		exemptWithContextFunc ExemptOperationWithContext
+
+		changes []*BalanceChange
+	}{
+		"no exemptions": {
+			changes: []*BalanceChange{
+				{
+					Account:    &types.AccountIdentifier{Address: "acct1"},
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "150",
+				},
+				{
+					Account:    &types.AccountIdentifier{Address: "acct2"},
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "20",
+				},
+			},
+		},
+		"exempt by transaction metadata": {
+			exemptWithContextFunc: exemptSynthetic,
+			changes: []*BalanceChange{
+				{
+					Account:    &types.AccountIdentifier{Address: "acct1"},
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "50",
+				},
+				{
+					Account:    &types.AccountIdentifier{Address: "acct2"},
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "20",
+				},
+			},
+		},
+		"exempt by transaction metadata and operation": {
+			exemptFunc: func(op *types.Operation) bool {
+				return op.Account.Address == "acct2"
+			},
+			exemptWithContextFunc: exemptSynthetic,
+			changes: []*BalanceChange{
+				{
+					Account:    &types.AccountIdentifier{Address: "acct1"},
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "50",
+				},
+			},
+		},
+		"adapted exempt operation": {
+			exemptWithContextFunc: ExemptOperation(func(op *types.Operation) bool {
+				return op.Account.Address == "acct1"
+			}).WithContext(),
+			changes: []*BalanceChange{
+				{
+					Account:    &types.AccountIdentifier{Address: "acct2"},
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "20",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+				{
+					Status:     "Success",
+					Successful: true,
+				},
+			})
+			assert.NoError(t, err)
+			assert.NotNil(t, asserter)
+
+			parser := New(asserter, test.exemptFunc, nil)
+			parser.ExemptWithContextFunc = test.exemptWithContextFunc
+
+			changes, err := parser.BalanceChanges(context.Background(), block, false)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, test.changes, changes)
+		})
+	}
+}
+
+func TestExemptOperationWithContext(t *testing.T) {
+	var nilExempt ExemptOperation
+	assert.Nil(t, nilExempt.WithContext())
+
+	exempt := ExemptOperation(func(op *types.Operation) bool {
+		return op.Type == "Fee"
+	}).WithContext()
+	assert.True(t, exempt(nil, &types.Operation{Type: "Fee"}))
+	assert.False(t, exempt(&types.Transaction{}, &types.Operation{Type: "Transfer"}))
+}
+
+func TestDefaultOperationClassifier(t *testing.T) {
+	assert.Equal(t, FeeCategory, DefaultOperationClassifier("FEE"))
+	assert.Equal(t, FeeCategory, DefaultOperationClassifier("gas_fee"))
+	assert.Equal(t, RewardCategory, DefaultOperationClassifier("BLOCK_REWARD"))
+	assert.Equal(t, RewardCategory, DefaultOperationClassifier("Coinbase"))
+	assert.Equal(t, TransferCategory, DefaultOperationClassifier("TRANSFER"))
+	assert.Equal(t, TransferCategory, DefaultOperationClassifier(""))
+}
+
 func simpleTransactionFactory(
 	hash string,
 	address string,
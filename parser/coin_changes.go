@@ -0,0 +1,166 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// skipCoinOperation returns a boolean indicating whether
+// an operation should be considered when determining coin
+// changes. Operations without a CoinChange or Amount and
+// unsuccessful operations are skipped.
+func (p *Parser) skipCoinOperation(op *types.Operation) (bool, error) {
+	if op.CoinChange == nil {
+		return true, nil
+	}
+
+	if op.Amount == nil {
+		return true, nil
+	}
+
+	successful, err := p.Asserter.OperationSuccessful(op)
+	if err != nil {
+		// Should only occur if responses not validated
+		return false, fmt.Errorf("%w: %v", ErrCoinChangeSuccessUnknown, err)
+	}
+
+	return !successful, nil
+}
+
+// AccountCoinChanges returns all coins created and spent in
+// a block (in the order they appear) along with the account
+// that owns each coin. The Amount of each coin is the Amount
+// of the operation that created or spent it.
+//
+// Coins that are both created and spent in the block are
+// omitted from both slices. If a block is being orphaned,
+// created coins are returned as spent and spent coins are
+// returned as created.
+func (p *Parser) AccountCoinChanges(
+	ctx context.Context,
+	block *types.Block,
+	blockRemoved bool,
+) ([]*types.AccountCoin, []*types.AccountCoin, error) {
+	created := []*types.AccountCoin{}
+	spent := []*types.AccountCoin{}
+	createdIdentifiers := map[string]struct{}{}
+	spentIdentifiers := map[string]struct{}{}
+
+	for _, tx := range block.Transactions {
+		for _, op := range tx.Operations {
+			skip, err := p.skipCoinOperation(op)
+			if err != nil {
+				return nil, nil, err
+			}
+			if skip {
+				continue
+			}
+
+			coinChange := op.CoinChange
+			if coinChange.CoinIdentifier == nil || len(coinChange.CoinIdentifier.Identifier) == 0 {
+				return nil, nil, fmt.Errorf(
+					"%w: %s",
+					ErrCoinChangeIdentifierMissing,
+					types.PrintStruct(op.OperationIdentifier),
+				)
+			}
+
+			var isCreated bool
+			switch coinChange.CoinAction {
+			case types.CoinCreated:
+				isCreated = !blockRemoved
+			case types.CoinSpent:
+				isCreated = blockRemoved
+			default:
+				return nil, nil, fmt.Errorf(
+					"%w: %s",
+					ErrCoinChangeActionInvalid,
+					coinChange.CoinAction,
+				)
+			}
+
+			identifier := coinChange.CoinIdentifier.Identifier
+			identifiers := spentIdentifiers
+			if isCreated {
+				identifiers = createdIdentifiers
+			}
+
+			if _, ok := identifiers[identifier]; ok {
+				return nil, nil, fmt.Errorf("%w: %s", ErrCoinChangeDuplicate, identifier)
+			}
+			identifiers[identifier] = struct{}{}
+
+			accountCoin := &types.AccountCoin{
+				Account: op.Account,
+				Coin: &types.Coin{
+					CoinIdentifier: coinChange.CoinIdentifier,
+					Amount:         op.Amount,
+				},
+			}
+			if isCreated {
+				created = append(created, accountCoin)
+			} else {
+				spent = append(spent, accountCoin)
+			}
+		}
+	}
+
+	return omitCoins(created, spentIdentifiers), omitCoins(spent, createdIdentifiers), nil
+}
+
+// omitCoins returns the coins whose identifiers are not
+// in omit.
+func omitCoins(coins []*types.AccountCoin, omit map[string]struct{}) []*types.AccountCoin {
+	filtered := []*types.AccountCoin{}
+	for _, coin := range coins {
+		if _, ok := omit[coin.Coin.CoinIdentifier.Identifier]; ok {
+			continue
+		}
+
+		filtered = append(filtered, coin)
+	}
+
+	return filtered
+}
+
+// CoinChanges returns all coins created and the identifiers
+// of all coins spent in a block. See AccountCoinChanges for
+// details on how coin changes are determined.
+func (p *Parser) CoinChanges(
+	ctx context.Context,
+	block *types.Block,
+	blockRemoved bool,
+) (created []*types.Coin, spent []*types.CoinIdentifier, err error) {
+	createdAccountCoins, spentAccountCoins, err := p.AccountCoinChanges(ctx, block, blockRemoved)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	created = make([]*types.Coin, len(createdAccountCoins))
+	for i, accountCoin := range createdAccountCoins {
+		created[i] = accountCoin.Coin
+	}
+
+	spent = make([]*types.CoinIdentifier, len(spentAccountCoins))
+	for i, accountCoin := range spentAccountCoins {
+		spent[i] = accountCoin.Coin.CoinIdentifier
+	}
+
+	return created, spent, nil
+}
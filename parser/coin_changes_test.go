@@ -0,0 +1,272 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestCoinChanges(t *testing.T) {
+	currency := &types.Currency{
+		Symbol:   "BTC",
+		Decimals: 8,
+	}
+	account := &types.AccountIdentifier{
+		Address: "addr1",
+	}
+	account2 := &types.AccountIdentifier{
+		Address: "addr2",
+	}
+
+	operation := func(
+		status string,
+		account *types.AccountIdentifier,
+		value string,
+		action types.CoinAction,
+		identifier string,
+	) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type:    "Transfer",
+			Status:  types.String(status),
+			Account: account,
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: currency,
+			},
+			CoinChange: &types.CoinChange{
+				CoinAction: action,
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: identifier,
+				},
+			},
+		}
+	}
+	block := func(ops ...*types.Operation) *types.Block {
+		return &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Hash:  "1",
+				Index: 1,
+			},
+			Transactions: []*types.Transaction{
+				{
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: "tx1",
+					},
+					Operations: ops,
+				},
+			},
+		}
+	}
+	coin := func(identifier string, value string) *types.Coin {
+		return &types.Coin{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: identifier,
+			},
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: currency,
+			},
+		}
+	}
+
+	spendAndCreate := block(
+		operation("Success", account, "-10", types.CoinSpent, "coin1"),
+		operation("Success", account2, "7", types.CoinCreated, "coin2"),
+		operation("Success", account, "3", types.CoinCreated, "coin3"),
+	)
+
+	var tests = map[string]struct {
+		block  *types.Block
+		orphan bool
+
+		created []*types.Coin
+		spent   []*types.CoinIdentifier
+		err     error
+	}{
+		"spend and create": {
+			block:   spendAndCreate,
+			created: []*types.Coin{coin("coin2", "7"), coin("coin3", "3")},
+			spent:   []*types.CoinIdentifier{{Identifier: "coin1"}},
+		},
+		"spend and create (orphan)": {
+			block:   spendAndCreate,
+			orphan:  true,
+			created: []*types.Coin{coin("coin1", "-10")},
+			spent: []*types.CoinIdentifier{
+				{Identifier: "coin2"},
+				{Identifier: "coin3"},
+			},
+		},
+		"failed operations ignored": {
+			block: block(
+				operation("Failure", account, "-10", types.CoinSpent, "coin1"),
+				operation("Success", account2, "7", types.CoinCreated, "coin2"),
+				operation("Failure", account, "3", types.CoinCreated, "coin3"),
+			),
+			created: []*types.Coin{coin("coin2", "7")},
+			spent:   []*types.CoinIdentifier{},
+		},
+		"operations without coin changes ignored": {
+			block: block(&types.Operation{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type:    "Transfer",
+				Status:  types.String("Success"),
+				Account: account,
+				Amount: &types.Amount{
+					Value:    "10",
+					Currency: currency,
+				},
+			}),
+			created: []*types.Coin{},
+			spent:   []*types.CoinIdentifier{},
+		},
+		"created and spent in block": {
+			block: block(
+				operation("Success", account, "5", types.CoinCreated, "coin1"),
+				operation("Success", account, "-5", types.CoinSpent, "coin1"),
+				operation("Success", account2, "5", types.CoinCreated, "coin2"),
+			),
+			created: []*types.Coin{coin("coin2", "5")},
+			spent:   []*types.CoinIdentifier{},
+		},
+		"spend missing identifier": {
+			block: block(
+				operation("Success", account, "-10", types.CoinSpent, ""),
+			),
+			err: ErrCoinChangeIdentifierMissing,
+		},
+		"duplicate spend": {
+			block: block(
+				operation("Success", account, "-10", types.CoinSpent, "coin1"),
+				operation("Success", account, "-10", types.CoinSpent, "coin1"),
+			),
+			err: ErrCoinChangeDuplicate,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+				{
+					Status:     "Success",
+					Successful: true,
+				},
+				{
+					Status:     "Failure",
+					Successful: false,
+				},
+			})
+			assert.NoError(t, err)
+			assert.NotNil(t, asserter)
+
+			parser := New(asserter, nil, nil)
+			created, spent, err := parser.CoinChanges(
+				context.Background(),
+				test.block,
+				test.orphan,
+			)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				assert.Nil(t, created)
+				assert.Nil(t, spent)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.created, created)
+			assert.Equal(t, test.spent, spent)
+		})
+	}
+}
+
+func TestAccountCoinChanges(t *testing.T) {
+	asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+		{
+			Status:     "Success",
+			Successful: true,
+		},
+	})
+	assert.NoError(t, err)
+
+	account := &types.AccountIdentifier{
+		Address: "addr1",
+	}
+	op := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: 0,
+		},
+		Type:    "Transfer",
+		Status:  types.String("Success"),
+		Account: account,
+		Amount: &types.Amount{
+			Value: "10",
+			Currency: &types.Currency{
+				Symbol:   "BTC",
+				Decimals: 8,
+			},
+		},
+		CoinChange: &types.CoinChange{
+			CoinAction: types.CoinCreated,
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: "coin1",
+			},
+		},
+	}
+	block := &types.Block{
+		Transactions: []*types.Transaction{
+			{
+				Operations: []*types.Operation{op},
+			},
+		},
+	}
+
+	parser := New(asserter, nil, nil)
+	created, spent, err := parser.AccountCoinChanges(context.Background(), block, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.AccountCoin{
+		{
+			Account: account,
+			Coin: &types.Coin{
+				CoinIdentifier: op.CoinChange.CoinIdentifier,
+				Amount:         op.Amount,
+			},
+		},
+	}, created)
+	assert.Equal(t, []*types.AccountCoin{}, spent)
+
+	created, spent, err = parser.AccountCoinChanges(context.Background(), block, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.AccountCoin{}, created)
+	assert.Equal(t, []*types.AccountCoin{
+		{
+			Account: account,
+			Coin: &types.Coin{
+				CoinIdentifier: op.CoinChange.CoinIdentifier,
+				Amount:         op.Amount,
+			},
+		},
+	}, spent)
+}
@@ -32,6 +32,7 @@ var (
 		"intended type did not match observed type",
 	)
 	ErrExpectedOperationsExtraOperation = errors.New("found extra operation")
+	ErrExpectedOperationsMismatch       = errors.New("observed operations did not match intent")
 	ErrExpectedSignerUnexpectedSigner   = errors.New("found unexpected signers")
 	ErrExpectedSignerMissing            = errors.New("missing expected signer")
 
@@ -40,6 +41,7 @@ var (
 		ErrExpectedOperationAmountMismatch,
 		ErrExpectedOperationTypeMismatch,
 		ErrExpectedOperationsExtraOperation,
+		ErrExpectedOperationsMismatch,
 		ErrExpectedSignerUnexpectedSigner,
 		ErrExpectedSignerMissing,
 	}
@@ -110,6 +112,21 @@ var (
 	}
 )
 
+// Coin Change Errors
+var (
+	ErrCoinChangeIdentifierMissing = errors.New("coin change is missing a coin identifier")
+	ErrCoinChangeActionInvalid     = errors.New("coin change has an invalid coin action")
+	ErrCoinChangeDuplicate         = errors.New("coin identifier changed multiple times")
+	ErrCoinChangeSuccessUnknown    = errors.New("unable to determine if operation is successful")
+
+	CoinChangeErrs = []error{
+		ErrCoinChangeIdentifierMissing,
+		ErrCoinChangeActionInvalid,
+		ErrCoinChangeDuplicate,
+		ErrCoinChangeSuccessUnknown,
+	}
+)
+
 // Err takes an error as an argument and returns
 // whether or not the error is one thrown by the parser
 // along with the specific source of the error
@@ -117,6 +134,7 @@ func Err(err error) (bool, string) {
 	parserErrs := map[string][]error{
 		"intent error":           IntentErrs,
 		"match operations error": MatchOpsErrs,
+		"coin change error":      CoinChangeErrs,
 	}
 
 	for key, val := range parserErrs {
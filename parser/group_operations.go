@@ -31,21 +31,9 @@ type OperationGroup struct {
 	NilAmountPresent bool
 }
 
-func containsInt(valid []int, value int) bool {
-	for _, v := range valid {
-		if v == value {
-			return true
-		}
-	}
-
-	return false
-}
-
 // addOperationToGroup appends a *types.Operation to an *OperationGroup.
 func addOperationToGroup(
 	destination *OperationGroup,
-	destinationIndex int,
-	assignments *[]int,
 	op *types.Operation,
 ) {
 	// Remove group type if different
@@ -53,9 +41,7 @@ func addOperationToGroup(
 		destination.Type = ""
 	}
 
-	// Update op assignment
 	destination.Operations = append(destination.Operations, op)
-	(*assignments)[op.OperationIdentifier.Index] = destinationIndex
 
 	// Handle nil currency
 	if op.Amount == nil {
@@ -100,8 +86,9 @@ func sortOperationGroups(opLen int, opGroups map[int]*OperationGroup) []*Operati
 // of each group of related operations (assuming transitive relatedness). This
 // should ONLY be called on operations that have already been asserted for
 // correctness. Assertion ensures there are no duplicate operation indexes,
-// operations are sorted, and that operations only reference operations with
-// an index less than theirs.
+// operations are sorted, and that operations only reference operations that
+// exist in the transaction. Operations may reference operations with a greater
+// index than theirs (when allowed by asserter.Validations).
 //
 // OperationGroups are returned in ascending order based on the lowest
 // OperationIdentifier.Index in the group. The operations in each OperationGroup
@@ -109,66 +96,54 @@ func sortOperationGroups(opLen int, opGroups map[int]*OperationGroup) []*Operati
 func GroupOperations(transaction *types.Transaction) []*OperationGroup {
 	ops := transaction.Operations
 
-	// We use a map of ints to keep track of *OperationGroup instead of a slice
-	// because merging groups involves removing and combing many items. While we
-	// could manipulate a slice (leaving holes where groups were merged), it
-	// seemed less complex to manipulate a map.
-	//
-	// Nonetheless, either solution avoids modifying up to `n` opAssignments
-	// whenever 2 groups merge (this occurs when merging groups in a slice without
-	// leaving holes).
-	opGroups := map[int]*OperationGroup{}
-	opAssignments := make([]int, len(ops))
+	// Related operations are merged into groups using a disjoint-set
+	// forest of operation indexes (the root of each set is always the
+	// lowest index in the set). This handles references in either
+	// direction without reassigning previously grouped operations.
+	roots := make([]int, len(ops))
+	for i := range roots {
+		roots[i] = i
+	}
+
+	find := func(i int) int {
+		for roots[i] != i {
+			roots[i] = roots[roots[i]]
+			i = roots[i]
+		}
+
+		return i
+	}
+
 	for i, op := range ops {
-		// Create new group
-		if len(op.RelatedOperations) == 0 {
-			key := len(opGroups)
-			opGroups[key] = &OperationGroup{
-				Type:       op.Type,
-				Operations: []*types.Operation{op},
+		for _, relatedOp := range op.RelatedOperations {
+			related := int(relatedOp.Index)
+			if related < 0 || related >= len(ops) {
+				continue
 			}
 
-			if op.Amount != nil {
-				opGroups[key].Currencies = []*types.Currency{op.Amount.Currency}
+			a, b := find(i), find(related)
+			if a < b {
+				roots[b] = a
 			} else {
-				opGroups[key].Currencies = []*types.Currency{}
-				opGroups[key].NilAmountPresent = true
+				roots[a] = b
 			}
-
-			opAssignments[i] = key
-			continue
 		}
+	}
 
-		// Find groups to merge
-		groupsToMerge := []int{}
-		for _, relatedOp := range op.RelatedOperations {
-			if !containsInt(groupsToMerge, opAssignments[relatedOp.Index]) {
-				groupsToMerge = append(groupsToMerge, opAssignments[relatedOp.Index])
+	opGroups := map[int]*OperationGroup{}
+	for i, op := range ops {
+		root := find(i)
+		group, ok := opGroups[root]
+		if !ok {
+			group = &OperationGroup{
+				Type:       op.Type,
+				Operations: []*types.Operation{},
+				Currencies: []*types.Currency{},
 			}
+			opGroups[root] = group
 		}
 
-		// Ensure first index is lowest because all other groups
-		// will be merged into it.
-		sort.Ints(groupsToMerge)
-
-		mergedGroupIndex := groupsToMerge[0]
-		mergedGroup := opGroups[mergedGroupIndex]
-
-		// Add op to unified group
-		addOperationToGroup(mergedGroup, mergedGroupIndex, &opAssignments, op)
-
-		// Merge Groups
-		for _, otherGroupIndex := range groupsToMerge[1:] {
-			otherGroup := opGroups[otherGroupIndex]
-
-			// Add otherGroup ops to mergedGroup
-			for _, otherOp := range otherGroup.Operations {
-				addOperationToGroup(mergedGroup, mergedGroupIndex, &opAssignments, otherOp)
-			}
-
-			// Delete otherGroup
-			delete(opGroups, otherGroupIndex)
-		}
+		addOperationToGroup(group, op)
 	}
 
 	return sortOperationGroups(len(ops), opGroups)
@@ -399,6 +399,102 @@ func TestGroupOperations(t *testing.T) {
 				},
 			},
 		},
+		"forward related ops": {
+			transaction: &types.Transaction{
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						RelatedOperations: []*types.OperationIdentifier{
+							{Index: 2},
+						},
+						Type: "type 0",
+					},
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 1,
+						},
+						Type: "type 1",
+					},
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 2,
+						},
+						Type: "type 0",
+					},
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 3,
+						},
+						RelatedOperations: []*types.OperationIdentifier{
+							{Index: 1},
+							{Index: 4},
+						},
+						Type: "type 1",
+					},
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 4,
+						},
+						Type: "type 1",
+					},
+				},
+			},
+			groups: []*OperationGroup{
+				{
+					Type:             "type 0",
+					NilAmountPresent: true,
+					Operations: []*types.Operation{
+						{
+							OperationIdentifier: &types.OperationIdentifier{
+								Index: 0,
+							},
+							RelatedOperations: []*types.OperationIdentifier{
+								{Index: 2},
+							},
+							Type: "type 0",
+						},
+						{
+							OperationIdentifier: &types.OperationIdentifier{
+								Index: 2,
+							},
+							Type: "type 0",
+						},
+					},
+					Currencies: []*types.Currency{},
+				},
+				{
+					Type:             "type 1",
+					NilAmountPresent: true,
+					Operations: []*types.Operation{
+						{
+							OperationIdentifier: &types.OperationIdentifier{
+								Index: 1,
+							},
+							Type: "type 1",
+						},
+						{
+							OperationIdentifier: &types.OperationIdentifier{
+								Index: 3,
+							},
+							RelatedOperations: []*types.OperationIdentifier{
+								{Index: 1},
+								{Index: 4},
+							},
+							Type: "type 1",
+						},
+						{
+							OperationIdentifier: &types.OperationIdentifier{
+								Index: 4,
+							},
+							Type: "type 1",
+						},
+					},
+					Currencies: []*types.Currency{},
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -407,3 +503,94 @@ func TestGroupOperations(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupOperationsTransitive(t *testing.T) {
+	currency := &types.Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+	}
+
+	// operations returns len(related) operations where the operation at
+	// index i references the operation indexes in related[i].
+	operations := func(related [][]int64) []*types.Operation {
+		ops := make([]*types.Operation, len(related))
+		for i, indexes := range related {
+			ops[i] = &types.Operation{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: int64(i),
+				},
+				Type: "CALL",
+				Amount: &types.Amount{
+					Value:    "1",
+					Currency: currency,
+				},
+			}
+
+			for _, index := range indexes {
+				ops[i].RelatedOperations = append(
+					ops[i].RelatedOperations,
+					&types.OperationIdentifier{Index: index},
+				)
+			}
+		}
+
+		return ops
+	}
+
+	var tests = map[string]struct {
+		related [][]int64
+
+		// groups contains the operation indexes in each
+		// expected group (in order).
+		groups [][]int64
+	}{
+		"deep chain": {
+			related: [][]int64{{}, {0}, {1}, {2}, {3}, {4}},
+			groups:  [][]int64{{0, 1, 2, 3, 4, 5}},
+		},
+		"deep chain with gaps": {
+			related: [][]int64{{}, {}, {0}, {}, {2}, {}, {4}},
+			groups:  [][]int64{{0, 2, 4, 6}, {1}, {3}, {5}},
+		},
+		"deep chain referencing forward": {
+			related: [][]int64{{1}, {2}, {3}, {}},
+			groups:  [][]int64{{0, 1, 2, 3}},
+		},
+		"diamond": {
+			related: [][]int64{{}, {0}, {0}, {1, 2}},
+			groups:  [][]int64{{0, 1, 2, 3}},
+		},
+		"diamond joined last": {
+			related: [][]int64{{}, {}, {0}, {1}, {2, 3}},
+			groups:  [][]int64{{0, 1, 2, 3, 4}},
+		},
+		"disjoint sets": {
+			related: [][]int64{{}, {}, {0}, {1}, {2}, {3}, {}},
+			groups:  [][]int64{{0, 2, 4}, {1, 3, 5}, {6}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ops := operations(test.related)
+			groups := GroupOperations(&types.Transaction{Operations: ops})
+
+			if !assert.Len(t, groups, len(test.groups)) {
+				return
+			}
+
+			for i, expected := range test.groups {
+				expectedOps := make([]*types.Operation, len(expected))
+				for j, index := range expected {
+					expectedOps[j] = ops[index]
+				}
+
+				assert.Equal(t, &OperationGroup{
+					Type:       "CALL",
+					Operations: expectedOps,
+					Currencies: []*types.Currency{currency},
+				}, groups[i])
+			}
+		})
+	}
+}
@@ -15,8 +15,8 @@
 package parser
 
 import (
-	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -56,11 +56,150 @@ func ExpectedOperation(intent *types.Operation, observed *types.Operation) error
 	return nil
 }
 
+// OperationField is a field of a *types.Operation
+// considered when comparing intent to observed operations.
+type OperationField string
+
+const (
+	// AccountField is operation.Account.
+	AccountField OperationField = "account"
+
+	// AmountField is operation.Amount.
+	AmountField OperationField = "amount"
+
+	// TypeField is operation.Type.
+	TypeField OperationField = "type"
+)
+
+// OperationMismatch is an unmatched intended operation and an
+// unmatched observed operation that only differ in a single
+// OperationField.
+type OperationMismatch struct {
+	IntentIndex   int
+	ObservedIndex int
+	Field         OperationField
+
+	// Err is the error returned by ExpectedOperation
+	// for the pair of operations.
+	Err error
+}
+
+// ExpectedOperationsError is returned by ExpectedOperations when
+// observed operations do not match intent. It can be inspected using
+// errors.As (errors.Is(err, ErrExpectedOperationsMismatch) also
+// returns true). All indexes refer to positions in Intent and Observed.
+type ExpectedOperationsError struct {
+	Intent   []*types.Operation
+	Observed []*types.Operation
+
+	// UnmatchedIntent are the indexes of intended operations
+	// that were not matched by any observed operation.
+	UnmatchedIntent []int
+
+	// Unexpected are the indexes of observed operations that
+	// did not match any intended operation. This is only
+	// populated when extra operations are considered an error.
+	Unexpected []int
+
+	// Unsuccessful are the indexes of observed operations that
+	// matched an intended operation but were not successful.
+	Unsuccessful []int
+
+	// NearMatches pair unmatched intended operations with
+	// unmatched observed operations that only differ in
+	// a single field.
+	NearMatches []*OperationMismatch
+}
+
+// Error returns a multi-line description of the mismatch.
+func (e *ExpectedOperationsError) Error() string {
+	lines := []string{ErrExpectedOperationsMismatch.Error()}
+
+	nearMatches := map[int]*OperationMismatch{}
+	for _, nearMatch := range e.NearMatches {
+		nearMatches[nearMatch.IntentIndex] = nearMatch
+	}
+
+	for _, index := range e.UnmatchedIntent {
+		lines = append(lines, fmt.Sprintf(
+			"  intent %d not matched: %s",
+			index,
+			types.PrintStruct(e.Intent[index]),
+		))
+
+		if nearMatch, ok := nearMatches[index]; ok {
+			lines = append(lines, fmt.Sprintf(
+				"    observed %d only differs in %s: %s",
+				nearMatch.ObservedIndex,
+				nearMatch.Field,
+				nearMatch.Err.Error(),
+			))
+		}
+	}
+
+	for _, index := range e.Unexpected {
+		lines = append(lines, fmt.Sprintf(
+			"  observed %d unexpected: %s",
+			index,
+			types.PrintStruct(e.Observed[index]),
+		))
+	}
+
+	for _, index := range e.Unsuccessful {
+		lines = append(lines, fmt.Sprintf(
+			"  observed %d matched intent but was unsuccessful: %s",
+			index,
+			types.PrintStruct(e.Observed[index]),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Is returns true for ErrExpectedOperationsMismatch and, if any
+// unexpected operations were observed, for
+// ErrExpectedOperationsExtraOperation.
+func (e *ExpectedOperationsError) Is(target error) bool {
+	if target == ErrExpectedOperationsMismatch {
+		return true
+	}
+
+	return target == ErrExpectedOperationsExtraOperation && len(e.Unexpected) > 0
+}
+
+// mismatchedField returns the only OperationField that differs
+// between an intended and observed operation. If no field or
+// more than one field differs, ok is false.
+func mismatchedField(
+	intent *types.Operation,
+	observed *types.Operation,
+) (field OperationField, ok bool) {
+	differences := []OperationField{}
+	if types.Hash(intent.Account) != types.Hash(observed.Account) {
+		differences = append(differences, AccountField)
+	}
+
+	if types.Hash(intent.Amount) != types.Hash(observed.Amount) {
+		differences = append(differences, AmountField)
+	}
+
+	if intent.Type != observed.Type {
+		differences = append(differences, TypeField)
+	}
+
+	if len(differences) != 1 {
+		return "", false
+	}
+
+	return differences[0], true
+}
+
 // ExpectedOperations returns an error if a slice of intended
 // operations differ from observed operations. Optionally,
 // it is possible to error if any extra observed opertions
 // are found or if operations matched are not considered
-// successful.
+// successful. Any returned mismatch is an
+// *ExpectedOperationsError.
 func (p *Parser) ExpectedOperations(
 	intent []*types.Operation,
 	observed []*types.Operation,
@@ -68,20 +207,23 @@ func (p *Parser) ExpectedOperations(
 	confirmSuccess bool,
 ) error {
 	matches := make(map[int]struct{})
-	failedMatches := []*types.Operation{}
+	unmatchedObserved := []int{}
+	mismatch := &ExpectedOperationsError{
+		Intent:   intent,
+		Observed: observed,
+	}
 
-	for _, obs := range observed {
+	for j, obs := range observed {
 		foundMatch := false
+		unsuccessful := false
 		for i, in := range intent {
 			if _, exists := matches[i]; exists {
 				continue
 			}
 
 			// Any error returned here only indicated that intent
-			// does not match observed. For ExpectedOperations,
-			// we don't care about the content of the error, we
-			// just care if it errors so we can evaluate the next
-			// operation for a match.
+			// does not match observed. Near matches are determined
+			// once all matches are found.
 			if err := ExpectedOperation(in, obs); err != nil {
 				continue
 			}
@@ -93,7 +235,7 @@ func (p *Parser) ExpectedOperations(
 				}
 
 				if !obsSuccess {
-					failedMatches = append(failedMatches, obs)
+					unsuccessful = true
 					continue
 				}
 			}
@@ -103,37 +245,52 @@ func (p *Parser) ExpectedOperations(
 			break
 		}
 
-		if !foundMatch && errExtra {
-			return fmt.Errorf(
-				"%w: %s",
-				ErrExpectedOperationsExtraOperation,
-				types.PrettyPrintStruct(obs),
-			)
+		// Unsuccessful operations are only reported as
+		// unsuccessful (not as unexpected or near matches).
+		switch {
+		case foundMatch:
+		case unsuccessful:
+			mismatch.Unsuccessful = append(mismatch.Unsuccessful, j)
+		default:
+			unmatchedObserved = append(unmatchedObserved, j)
 		}
 	}
 
-	missingIntent := []int{}
+	if errExtra && len(unmatchedObserved) > 0 {
+		mismatch.Unexpected = unmatchedObserved
+	}
+
+	nearMatched := make(map[int]struct{})
 	for i := 0; i < len(intent); i++ {
-		if _, exists := matches[i]; !exists {
-			missingIntent = append(missingIntent, i)
+		if _, exists := matches[i]; exists {
+			continue
 		}
-	}
 
-	if len(missingIntent) > 0 {
-		errString := fmt.Sprintf(
-			"could not intent match %v",
-			missingIntent,
-		)
+		mismatch.UnmatchedIntent = append(mismatch.UnmatchedIntent, i)
+		for _, j := range unmatchedObserved {
+			if _, exists := nearMatched[j]; exists {
+				continue
+			}
 
-		if len(failedMatches) > 0 {
-			errString += fmt.Sprintf(
-				"%s: found matching ops with unsuccessful status %s",
-				errString,
-				types.PrettyPrintStruct(failedMatches),
-			)
+			field, ok := mismatchedField(intent[i], observed[j])
+			if !ok {
+				continue
+			}
+
+			nearMatched[j] = struct{}{}
+			mismatch.NearMatches = append(mismatch.NearMatches, &OperationMismatch{
+				IntentIndex:   i,
+				ObservedIndex: j,
+				Field:         field,
+				Err:           ExpectedOperation(intent[i], observed[j]),
+			})
+			break
 		}
+	}
 
-		return errors.New(errString)
+	if len(mismatch.UnmatchedIntent) > 0 || len(mismatch.Unexpected) > 0 ||
+		(errExtra && len(mismatch.Unsuccessful) > 0) {
+		return mismatch
 	}
 
 	return nil
@@ -15,6 +15,7 @@
 package parser
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -562,6 +563,216 @@ func TestExpectedOperations(t *testing.T) {
 	}
 }
 
+func TestExpectedOperationsError(t *testing.T) {
+	operation := func(index int64, opType string, address string, value string) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: index,
+			},
+			Type: opType,
+			Account: &types.AccountIdentifier{
+				Address: address,
+			},
+			Amount: &types.Amount{
+				Value: value,
+			},
+			Status: types.String("success"),
+		}
+	}
+	intent := []*types.Operation{
+		operation(0, "transfer", "addr1", "-100"),
+		operation(1, "transfer", "addr2", "100"),
+	}
+
+	failedOperation := operation(0, "transfer", "addr1", "-100")
+	failedOperation.Status = types.String("failure")
+
+	var tests = map[string]struct {
+		observed       []*types.Operation
+		errExtra       bool
+		confirmSuccess bool
+
+		err          *ExpectedOperationsError
+		errExtraIs   bool
+		errorMessage string
+	}{
+		"amount off by one": {
+			observed: []*types.Operation{
+				operation(0, "transfer", "addr1", "-100"),
+				operation(1, "transfer", "addr2", "101"),
+			},
+			err: &ExpectedOperationsError{
+				UnmatchedIntent: []int{1},
+				NearMatches: []*OperationMismatch{
+					{
+						IntentIndex:   1,
+						ObservedIndex: 1,
+						Field:         AmountField,
+					},
+				},
+			},
+			errorMessage: "observed operations did not match intent\n" +
+				"  intent 1 not matched: " + types.PrintStruct(intent[1]) + "\n" +
+				"    observed 1 only differs in amount: " +
+				"intended amount did not match observed amount: " +
+				"expected " + types.PrettyPrintStruct(intent[1].Amount) +
+				" but got " + types.PrettyPrintStruct(&types.Amount{Value: "101"}),
+		},
+		"wrong account": {
+			observed: []*types.Operation{
+				operation(0, "transfer", "addr3", "-100"),
+				operation(1, "transfer", "addr2", "100"),
+			},
+			errExtra: true,
+			err: &ExpectedOperationsError{
+				UnmatchedIntent: []int{0},
+				Unexpected:      []int{0},
+				NearMatches: []*OperationMismatch{
+					{
+						IntentIndex:   0,
+						ObservedIndex: 0,
+						Field:         AccountField,
+					},
+				},
+			},
+			errExtraIs: true,
+		},
+		"extra fee": {
+			observed: []*types.Operation{
+				operation(0, "transfer", "addr1", "-100"),
+				operation(1, "fee", "addr1", "-1"),
+				operation(2, "transfer", "addr2", "100"),
+			},
+			errExtra: true,
+			err: &ExpectedOperationsError{
+				Unexpected: []int{1},
+			},
+			errExtraIs: true,
+			errorMessage: "observed operations did not match intent\n" +
+				"  observed 1 unexpected: " + types.PrintStruct(
+				operation(1, "fee", "addr1", "-1"),
+			),
+		},
+		"extra fee allowed": {
+			observed: []*types.Operation{
+				operation(0, "transfer", "addr1", "-100"),
+				operation(1, "fee", "addr1", "-1"),
+				operation(2, "transfer", "addr2", "100"),
+			},
+		},
+		"unsuccessful operation followed by a match": {
+			observed: []*types.Operation{
+				failedOperation,
+				operation(1, "transfer", "addr1", "-100"),
+				operation(2, "transfer", "addr2", "100"),
+			},
+			errExtra:       true,
+			confirmSuccess: true,
+			err: &ExpectedOperationsError{
+				Unsuccessful: []int{0},
+			},
+			errorMessage: "observed operations did not match intent\n" +
+				"  observed 0 matched intent but was unsuccessful: " +
+				types.PrintStruct(failedOperation),
+		},
+		"unsuccessful operation followed by a match allowed": {
+			observed: []*types.Operation{
+				failedOperation,
+				operation(1, "transfer", "addr1", "-100"),
+				operation(2, "transfer", "addr2", "100"),
+			},
+			confirmSuccess: true,
+		},
+		"unsuccessful operation without a match": {
+			observed: []*types.Operation{
+				failedOperation,
+				operation(2, "transfer", "addr2", "100"),
+			},
+			confirmSuccess: true,
+			err: &ExpectedOperationsError{
+				UnmatchedIntent: []int{0},
+				Unsuccessful:    []int{0},
+			},
+		},
+		"extra fee and wrong type": {
+			observed: []*types.Operation{
+				operation(0, "transfer", "addr1", "-100"),
+				operation(1, "fee", "addr1", "-1"),
+				operation(2, "deposit", "addr2", "100"),
+			},
+			err: &ExpectedOperationsError{
+				UnmatchedIntent: []int{1},
+				NearMatches: []*OperationMismatch{
+					{
+						IntentIndex:   1,
+						ObservedIndex: 2,
+						Field:         TypeField,
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+				{
+					Status:     "success",
+					Successful: true,
+				},
+				{
+					Status:     "failure",
+					Successful: false,
+				},
+			})
+			assert.NoError(t, err)
+
+			parser := New(asserter, nil, nil)
+			err = parser.ExpectedOperations(
+				intent,
+				test.observed,
+				test.errExtra,
+				test.confirmSuccess,
+			)
+			if test.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var mismatch *ExpectedOperationsError
+			if !assert.True(t, errors.As(err, &mismatch)) {
+				return
+			}
+
+			assert.True(t, errors.Is(err, ErrExpectedOperationsMismatch))
+			assert.Equal(t, test.errExtraIs, errors.Is(err, ErrExpectedOperationsExtraOperation))
+			assert.Equal(t, intent, mismatch.Intent)
+			assert.Equal(t, test.observed, mismatch.Observed)
+			assert.Equal(t, test.err.UnmatchedIntent, mismatch.UnmatchedIntent)
+			assert.Equal(t, test.err.Unexpected, mismatch.Unexpected)
+			assert.Equal(t, test.err.Unsuccessful, mismatch.Unsuccessful)
+
+			if assert.Len(t, mismatch.NearMatches, len(test.err.NearMatches)) {
+				for i, expected := range test.err.NearMatches {
+					nearMatch := mismatch.NearMatches[i]
+					assert.Equal(t, expected.IntentIndex, nearMatch.IntentIndex)
+					assert.Equal(t, expected.ObservedIndex, nearMatch.ObservedIndex)
+					assert.Equal(t, expected.Field, nearMatch.Field)
+					assert.True(t, errors.Is(nearMatch.Err, map[OperationField]error{
+						AccountField: ErrExpectedOperationAccountMismatch,
+						AmountField:  ErrExpectedOperationAmountMismatch,
+						TypeField:    ErrExpectedOperationTypeMismatch,
+					}[expected.Field]))
+				}
+			}
+
+			if len(test.errorMessage) > 0 {
+				assert.Equal(t, test.errorMessage, err.Error())
+			}
+		})
+	}
+}
+
 func TestExpectedSigners(t *testing.T) {
 	var tests = map[string]struct {
 		intent   []*types.SigningPayload
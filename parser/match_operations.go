@@ -115,7 +115,9 @@ type OperationDescription struct {
 	AllowRepeats bool
 
 	// Optional indicates that not finding any operations that meet
-	// the description should not trigger an error.
+	// the description should not trigger an error. When combined with
+	// AllowRepeats, a description matches zero or more operations (ex:
+	// a variable number of fee operations).
 	Optional bool
 
 	// CoinAction indicates that an operation should have a CoinChange
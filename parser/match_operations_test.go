@@ -1962,3 +1962,126 @@ func TestMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchOperationsVariableFees(t *testing.T) {
+	currency := &types.Currency{
+		Symbol:   "BTC",
+		Decimals: 8,
+	}
+	descriptions := &Descriptions{
+		OperationDescriptions: []*OperationDescription{
+			{
+				Type: "TRANSFER",
+				Account: &AccountDescription{
+					Exists: true,
+				},
+				Amount: &AmountDescription{
+					Exists:   true,
+					Sign:     NegativeAmountSign,
+					Currency: currency,
+				},
+			},
+			{
+				Type: "TRANSFER",
+				Account: &AccountDescription{
+					Exists: true,
+				},
+				Amount: &AmountDescription{
+					Exists:   true,
+					Sign:     PositiveAmountSign,
+					Currency: currency,
+				},
+			},
+			{
+				Type: "FEE",
+				Account: &AccountDescription{
+					Exists: true,
+				},
+				Amount: &AmountDescription{
+					Exists:   true,
+					Sign:     NegativeAmountSign,
+					Currency: currency,
+				},
+				AllowRepeats: true,
+				Optional:     true,
+			},
+		},
+		OppositeAmounts: [][]int{{0, 1}},
+		ErrUnmatched:    true,
+	}
+
+	operation := func(opType string, address string, value string) *types.Operation {
+		return &types.Operation{
+			Type: opType,
+			Account: &types.AccountIdentifier{
+				Address: address,
+			},
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: currency,
+			},
+		}
+	}
+	sender := operation("TRANSFER", "addr1", "-100")
+	recipient := operation("TRANSFER", "addr2", "100")
+	fee1 := operation("FEE", "addr1", "-1")
+	fee2 := operation("FEE", "addr1", "-2")
+	fee3 := operation("FEE", "addr3", "-3")
+
+	var tests = map[string]struct {
+		operations []*types.Operation
+
+		fees    []*types.Operation
+		amounts []*big.Int
+		err     bool
+	}{
+		"no fee operations": {
+			operations: []*types.Operation{sender, recipient},
+		},
+		"one fee operation": {
+			operations: []*types.Operation{sender, fee1, recipient},
+			fees:       []*types.Operation{fee1},
+			amounts:    []*big.Int{big.NewInt(-1)},
+		},
+		"three fee operations": {
+			operations: []*types.Operation{fee1, sender, fee2, recipient, fee3},
+			fees:       []*types.Operation{fee1, fee2, fee3},
+			amounts:    []*big.Int{big.NewInt(-1), big.NewInt(-2), big.NewInt(-3)},
+		},
+		"positive fee operation": {
+			operations: []*types.Operation{sender, recipient, operation("FEE", "addr1", "1")},
+			err:        true,
+		},
+		"missing recipient": {
+			operations: []*types.Operation{sender, fee1},
+			err:        true,
+		},
+		"repeated sender": {
+			operations: []*types.Operation{sender, sender, recipient},
+			err:        true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			matches, err := MatchOperations(descriptions, test.operations)
+			if test.err {
+				assert.Error(t, err)
+				assert.Nil(t, matches)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, matches, 3)
+			assert.Equal(t, []*types.Operation{sender}, matches[0].Operations)
+			assert.Equal(t, []*types.Operation{recipient}, matches[1].Operations)
+			if len(test.fees) == 0 {
+				assert.Nil(t, matches[2])
+				return
+			}
+
+			assert.Equal(t, test.fees, matches[2].Operations)
+			assert.Equal(t, test.amounts, matches[2].Amounts)
+		})
+	}
+}
@@ -24,6 +24,15 @@ type Parser struct {
 	Asserter          *asserter.Asserter
 	ExemptFunc        ExemptOperation
 	BalanceExemptions []*types.BalanceExemption
+
+	// ExemptWithContextFunc is used (in addition to ExemptFunc)
+	// to skip operations when computing balance changes.
+	ExemptWithContextFunc ExemptOperationWithContext
+
+	// Classifier is used to populate the subtotals returned
+	// by BalanceChangesDetailed. If it is nil,
+	// DefaultOperationClassifier is used.
+	Classifier OperationClassifier
 }
 
 // New creates a new Parser.
@@ -0,0 +1,93 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrors "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var _ CoinHelper = (*CoinStorageHelper)(nil)
+
+// CoinStorageHelper implements CoinHelper with a
+// CoinStorage (computed coins) and a CoinFetcher
+// (live coins from /account/coins).
+type CoinStorageHelper struct {
+	network     *types.NetworkIdentifier
+	coinStorage CoinStorage
+	blockHelper CurrentBlockHelper
+	fetcher     CoinFetcher
+}
+
+// NewCoinStorageHelper returns a new *CoinStorageHelper.
+func NewCoinStorageHelper(
+	network *types.NetworkIdentifier,
+	coinStorage CoinStorage,
+	blockHelper CurrentBlockHelper,
+	fetcher CoinFetcher,
+) *CoinStorageHelper {
+	return &CoinStorageHelper{
+		network:     network,
+		coinStorage: coinStorage,
+		blockHelper: blockHelper,
+		fetcher:     fetcher,
+	}
+}
+
+// ComputedCoins returns the unspent coins of an account in
+// the CoinStorage and the synced head. If no block has been
+// synced, a nil head is returned (coins are not yet computed).
+func (h *CoinStorageHelper) ComputedCoins(
+	ctx context.Context,
+	dbTx database.Transaction,
+	account *types.AccountIdentifier,
+) ([]*types.Coin, *types.BlockIdentifier, error) {
+	// CoinStorage wraps the error returned by the helper
+	// (so ErrHeadBlockNotFound can't be matched), so we
+	// check if any block has been synced first.
+	_, err := h.blockHelper.CurrentBlockIdentifier(ctx, dbTx)
+	if errors.Is(err, storageErrors.ErrHeadBlockNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return h.coinStorage.GetCoinsTransactional(ctx, dbTx, account)
+}
+
+// LiveCoins returns the unspent coins of an account
+// returned by /account/coins (excluding the mempool).
+func (h *CoinStorageHelper) LiveCoins(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+) ([]*types.Coin, *types.BlockIdentifier, error) {
+	block, coins, _, fetchErr := h.fetcher.AccountCoinsRetry(
+		ctx,
+		h.network,
+		account,
+		false,
+		nil,
+	)
+	if fetchErr != nil {
+		return nil, nil, fetchErr.Err
+	}
+
+	return coins, block, nil
+}
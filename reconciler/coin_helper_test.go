@@ -0,0 +1,138 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/fetcher"
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+	mockDatabase "github.com/coinbase/rosetta-sdk-go/mocks/storage/database"
+	storageErrors "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestCoinStorageHelper(t *testing.T) {
+	var (
+		network = &types.NetworkIdentifier{
+			Blockchain: "blockchain",
+			Network:    "network",
+		}
+		account = &types.AccountIdentifier{Address: "addr 1"}
+		block   = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		storageErr = errors.New("storage error")
+	)
+
+	var tests = map[string]struct {
+		headErr error
+		coins   []*types.Coin
+		coinErr error
+
+		expectedCoins []*types.Coin
+		expectedHead  *types.BlockIdentifier
+		expectedError error
+	}{
+		"computed coins": {
+			coins:         []*types.Coin{coin1, coin2},
+			expectedCoins: []*types.Coin{coin1, coin2},
+			expectedHead:  block,
+		},
+		"no synced blocks": {
+			headErr: storageErrors.ErrHeadBlockNotFound,
+		},
+		"head lookup error": {
+			headErr:       storageErr,
+			expectedError: storageErr,
+		},
+		"coin lookup error": {
+			coinErr:       storageErr,
+			expectedError: storageErr,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			dbTx := &mockDatabase.Transaction{}
+			mockStorage := &mocks.CoinStorage{}
+			mockBlockHelper := &mocks.CurrentBlockHelper{}
+			mockFetcher := &mocks.CoinFetcher{}
+			h := NewCoinStorageHelper(network, mockStorage, mockBlockHelper, mockFetcher)
+
+			mockBlockHelper.On("CurrentBlockIdentifier", ctx, dbTx).Return(block, test.headErr).Once()
+			if test.headErr == nil {
+				var head *types.BlockIdentifier
+				if test.coinErr == nil {
+					head = block
+				}
+				mockStorage.On(
+					"GetCoinsTransactional",
+					ctx,
+					dbTx,
+					account,
+				).Return(test.coins, head, test.coinErr).Once()
+			}
+
+			coins, head, err := h.ComputedCoins(ctx, dbTx, account)
+			assert.Equal(t, test.expectedCoins, coins)
+			assert.Equal(t, test.expectedHead, head)
+			assert.Equal(t, test.expectedError, err)
+			mockStorage.AssertExpectations(t)
+			mockBlockHelper.AssertExpectations(t)
+		})
+	}
+
+	t.Run("live coins", func(t *testing.T) {
+		ctx := context.Background()
+		mockFetcher := &mocks.CoinFetcher{}
+		h := NewCoinStorageHelper(network, &mocks.CoinStorage{}, &mocks.CurrentBlockHelper{}, mockFetcher)
+
+		var nilCurrencies []*types.Currency
+		mockFetcher.On(
+			"AccountCoinsRetry",
+			ctx,
+			network,
+			account,
+			false,
+			nilCurrencies,
+		).Return(block, []*types.Coin{coin1}, nil, nil).Once()
+		coins, liveBlock, err := h.LiveCoins(ctx, account)
+		assert.NoError(t, err)
+		assert.Equal(t, []*types.Coin{coin1}, coins)
+		assert.Equal(t, block, liveBlock)
+
+		fetchErr := &fetcher.Error{Err: fetcher.ErrRequestFailed}
+		mockFetcher.On(
+			"AccountCoinsRetry",
+			ctx,
+			network,
+			account,
+			false,
+			nilCurrencies,
+		).Return(nil, nil, nil, fetchErr).Once()
+		coins, liveBlock, err = h.LiveCoins(ctx, account)
+		assert.True(t, errors.Is(err, fetcher.ErrRequestFailed))
+		assert.Nil(t, coins)
+		assert.Nil(t, liveBlock)
+		mockFetcher.AssertExpectations(t)
+	})
+}
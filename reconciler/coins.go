@@ -0,0 +1,237 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// AddCoinAccount flags an account as UTXO-tracked so that
+// its coin set is compared during coin reconciliation. This
+// has no effect if coin reconciliation is not enabled.
+func (r *Reconciler) AddCoinAccount(account *types.AccountIdentifier) {
+	r.addCoinAccount(account)
+}
+
+func (r *Reconciler) addCoinAccount(account *types.AccountIdentifier) {
+	r.coinAccountsMutex.Lock()
+	defer r.coinAccountsMutex.Unlock()
+
+	key := types.Hash(account)
+	if _, ok := r.coinAccountsSet[key]; ok {
+		return
+	}
+
+	r.coinAccountsSet[key] = struct{}{}
+	r.coinAccounts = append(r.coinAccounts, account)
+}
+
+// nextCoinAccount returns the UTXO-tracked account
+// at position i (wrapping around) or nil if there
+// are no UTXO-tracked accounts.
+func (r *Reconciler) nextCoinAccount(i int) *types.AccountIdentifier {
+	r.coinAccountsMutex.Lock()
+	defer r.coinAccountsMutex.Unlock()
+
+	if len(r.coinAccounts) == 0 {
+		return nil
+	}
+
+	return r.coinAccounts[i%len(r.coinAccounts)]
+}
+
+// compareCoins returns the coins that are only in the live
+// set (missing), the coins that are only in the computed set
+// (extra), and the coins in both sets with different amounts.
+func compareCoins(
+	computed []*types.Coin,
+	live []*types.Coin,
+) ([]*types.Coin, []*types.Coin, []*CoinMismatch) {
+	computedCoins := map[string]*types.Coin{}
+	for _, coin := range computed {
+		computedCoins[coin.CoinIdentifier.Identifier] = coin
+	}
+
+	missing := []*types.Coin{}
+	mismatched := []*CoinMismatch{}
+	liveCoins := map[string]struct{}{}
+	for _, coin := range live {
+		liveCoins[coin.CoinIdentifier.Identifier] = struct{}{}
+
+		computedCoin, ok := computedCoins[coin.CoinIdentifier.Identifier]
+		if !ok {
+			missing = append(missing, coin)
+			continue
+		}
+
+		if types.Hash(computedCoin.Amount) != types.Hash(coin.Amount) {
+			mismatched = append(mismatched, &CoinMismatch{
+				Computed: computedCoin,
+				Live:     coin,
+			})
+		}
+	}
+
+	extra := []*types.Coin{}
+	for _, coin := range computed {
+		if _, ok := liveCoins[coin.CoinIdentifier.Identifier]; !ok {
+			extra = append(extra, coin)
+		}
+	}
+
+	sortCoins(missing)
+	sortCoins(extra)
+	sort.Slice(mismatched, func(i, j int) bool {
+		return mismatched[i].Live.CoinIdentifier.Identifier <
+			mismatched[j].Live.CoinIdentifier.Identifier
+	})
+
+	return missing, extra, mismatched
+}
+
+func sortCoins(coins []*types.Coin) {
+	sort.Slice(coins, func(i, j int) bool {
+		return coins[i].CoinIdentifier.Identifier < coins[j].CoinIdentifier.Identifier
+	})
+}
+
+// computedCoinsAt returns the computed coins of an account
+// at block. If the synced head is slightly behind block, we
+// wait for it to catch up. If the synced head is not at block
+// after waiting (or no block has been synced), nil is returned.
+func (r *Reconciler) computedCoinsAt(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	block *types.BlockIdentifier,
+) ([]*types.Coin, error) {
+	for {
+		dbTx := r.helper.DatabaseTransaction(ctx)
+		coins, head, err := r.coinHelper.ComputedCoins(ctx, dbTx, account)
+		dbTx.Discard(ctx)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w for %+v: %v",
+				ErrGetComputedCoinsFailed,
+				account,
+				err,
+			)
+		}
+
+		// Coins are not computed until the
+		// first block is synced.
+		if head == nil {
+			r.debugLog(
+				"skipping coin reconciliation for %s: coins not yet computed",
+				types.PrintStruct(account),
+			)
+			return nil, nil
+		}
+
+		if types.Hash(head) == types.Hash(block) {
+			return coins, nil
+		}
+
+		if head.Index >= block.Index || block.Index-head.Index > waitToCheckDiff {
+			r.debugLog(
+				"skipping coin reconciliation for %s: computed coins at %s but live coins at %s",
+				types.PrintStruct(account),
+				types.PrintStruct(head),
+				types.PrintStruct(block),
+			)
+			return nil, nil
+		}
+
+		if err := utils.ContextSleep(ctx, waitToCheckDiffSleep); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reconcileCoins compares the computed and live
+// coin sets of an account.
+func (r *Reconciler) reconcileCoins(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+) error {
+	// We don't halt if the live coins can't be fetched
+	// because the account will be checked again later.
+	liveCoins, liveBlock, err := r.coinHelper.LiveCoins(ctx, account)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		log.Printf(
+			"%s: unable to get live coins for %s\n",
+			err.Error(),
+			types.PrintStruct(account),
+		)
+		return nil
+	}
+
+	computedCoins, err := r.computedCoinsAt(ctx, account, liveBlock)
+	if err != nil {
+		return err
+	}
+
+	if computedCoins == nil {
+		return nil
+	}
+
+	missing, extra, mismatched := compareCoins(computedCoins, liveCoins)
+	if len(missing) == 0 && len(extra) == 0 && len(mismatched) == 0 {
+		return r.coinHandler.CoinReconciliationSucceeded(ctx, account, liveBlock)
+	}
+
+	return r.coinHandler.CoinReconciliationFailed(ctx, &CoinReconciliationFailure{
+		Account:    account,
+		Block:      liveBlock,
+		Missing:    missing,
+		Extra:      extra,
+		Mismatched: mismatched,
+	})
+}
+
+// reconcileCoinAccounts compares the coin set of one
+// UTXO-tracked account every coinInterval (in round-robin
+// order).
+func (r *Reconciler) reconcileCoinAccounts(ctx context.Context) error {
+	ticker := time.NewTicker(r.coinInterval)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		account := r.nextCoinAccount(i)
+		if account == nil {
+			continue
+		}
+
+		if err := r.reconcileCoins(ctx, account); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,337 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+	mockDatabase "github.com/coinbase/rosetta-sdk-go/mocks/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	coinCurrency = &types.Currency{
+		Symbol:   "BTC",
+		Decimals: 8,
+	}
+
+	coin1 = &types.Coin{
+		CoinIdentifier: &types.CoinIdentifier{Identifier: "tx1:0"},
+		Amount:         &types.Amount{Value: "10", Currency: coinCurrency},
+	}
+
+	coin2 = &types.Coin{
+		CoinIdentifier: &types.CoinIdentifier{Identifier: "tx2:1"},
+		Amount:         &types.Amount{Value: "20", Currency: coinCurrency},
+	}
+
+	coin2Changed = &types.Coin{
+		CoinIdentifier: &types.CoinIdentifier{Identifier: "tx2:1"},
+		Amount:         &types.Amount{Value: "21", Currency: coinCurrency},
+	}
+
+	coin3 = &types.Coin{
+		CoinIdentifier: &types.CoinIdentifier{Identifier: "tx3:0"},
+		Amount:         &types.Amount{Value: "30", Currency: coinCurrency},
+	}
+)
+
+func TestCompareCoins(t *testing.T) {
+	var tests = map[string]struct {
+		computed []*types.Coin
+		live     []*types.Coin
+
+		missing    []*types.Coin
+		extra      []*types.Coin
+		mismatched []*CoinMismatch
+	}{
+		"match": {
+			computed:   []*types.Coin{coin1, coin2},
+			live:       []*types.Coin{coin2, coin1},
+			missing:    []*types.Coin{},
+			extra:      []*types.Coin{},
+			mismatched: []*CoinMismatch{},
+		},
+		"missing coin": {
+			computed:   []*types.Coin{coin1},
+			live:       []*types.Coin{coin1, coin2},
+			missing:    []*types.Coin{coin2},
+			extra:      []*types.Coin{},
+			mismatched: []*CoinMismatch{},
+		},
+		"extra coin": {
+			computed:   []*types.Coin{coin1, coin3},
+			live:       []*types.Coin{coin1},
+			missing:    []*types.Coin{},
+			extra:      []*types.Coin{coin3},
+			mismatched: []*CoinMismatch{},
+		},
+		"value mismatch": {
+			computed: []*types.Coin{coin1, coin2},
+			live:     []*types.Coin{coin1, coin2Changed},
+			missing:  []*types.Coin{},
+			extra:    []*types.Coin{},
+			mismatched: []*CoinMismatch{
+				{Computed: coin2, Live: coin2Changed},
+			},
+		},
+		"balances match but coins do not": {
+			computed:   []*types.Coin{coin3},
+			live:       []*types.Coin{coin1, coin2},
+			missing:    []*types.Coin{coin1, coin2},
+			extra:      []*types.Coin{coin3},
+			mismatched: []*CoinMismatch{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			missing, extra, mismatched := compareCoins(test.computed, test.live)
+			assert.Equal(t, test.missing, missing)
+			assert.Equal(t, test.extra, extra)
+			assert.Equal(t, test.mismatched, mismatched)
+		})
+	}
+}
+
+// coinHandler is a CoinHandler that records
+// the results of coin reconciliation.
+type coinHandler struct {
+	failures  chan *CoinReconciliationFailure
+	successes chan *types.AccountIdentifier
+}
+
+func newCoinHandler() *coinHandler {
+	return &coinHandler{
+		failures:  make(chan *CoinReconciliationFailure, 10),
+		successes: make(chan *types.AccountIdentifier, 10),
+	}
+}
+
+func (h *coinHandler) CoinReconciliationFailed(
+	ctx context.Context,
+	failure *CoinReconciliationFailure,
+) error {
+	h.failures <- failure
+	return nil
+}
+
+func (h *coinHandler) CoinReconciliationSucceeded(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	block *types.BlockIdentifier,
+) error {
+	h.successes <- account
+	return nil
+}
+
+func TestReconcile_CoinReconciliation(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		otherBlock = &types.BlockIdentifier{
+			Hash:  "block 1a",
+			Index: 1,
+		}
+		account1 = &types.AccountIdentifier{Address: "addr 1"}
+		account2 = &types.AccountIdentifier{Address: "addr 2"}
+		account3 = &types.AccountIdentifier{Address: "addr 3"}
+	)
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockCoinHelper := &mocks.CoinHelper{}
+	handler := newCoinHandler()
+	r := New(
+		mockHelper,
+		mockHandler,
+		parser.New(nil, nil, nil),
+		WithActiveConcurrency(0),
+		WithInactiveConcurrency(0),
+		WithCoinReconciliation(
+			mockCoinHelper,
+			handler,
+			[]*types.AccountIdentifier{account1, account2, account1},
+			10*time.Millisecond,
+		),
+	)
+	r.AddCoinAccount(account3)
+	assert.Len(t, r.coinAccounts, 3)
+
+	mtxn := &mockDatabase.Transaction{}
+	mtxn.On("Discard", mock.Anything)
+	mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn)
+
+	// account1 is missing a coin
+	mockCoinHelper.On(
+		"LiveCoins",
+		mock.Anything,
+		account1,
+	).Return(
+		[]*types.Coin{coin1, coin2},
+		block,
+		nil,
+	).Once()
+	mockCoinHelper.On(
+		"ComputedCoins",
+		mock.Anything,
+		mtxn,
+		account1,
+	).Return(
+		[]*types.Coin{coin1},
+		block,
+		nil,
+	).Once()
+
+	// account2 has an extra coin
+	mockCoinHelper.On(
+		"LiveCoins",
+		mock.Anything,
+		account2,
+	).Return(
+		[]*types.Coin{coin1},
+		block,
+		nil,
+	).Once()
+	mockCoinHelper.On(
+		"ComputedCoins",
+		mock.Anything,
+		mtxn,
+		account2,
+	).Return(
+		[]*types.Coin{coin1, coin3},
+		block,
+		nil,
+	).Once()
+
+	// account3 is skipped because the
+	// live block was orphaned
+	mockCoinHelper.On(
+		"LiveCoins",
+		mock.Anything,
+		account3,
+	).Return(
+		[]*types.Coin{coin1},
+		otherBlock,
+		nil,
+	).Once()
+	mockCoinHelper.On(
+		"ComputedCoins",
+		mock.Anything,
+		mtxn,
+		account3,
+	).Return(
+		[]*types.Coin{coin3},
+		block,
+		nil,
+	).Once()
+
+	// account1 is checked again (and matches)
+	mockCoinHelper.On(
+		"LiveCoins",
+		mock.Anything,
+		account1,
+	).Return(
+		[]*types.Coin{coin1},
+		block,
+		nil,
+	).Once()
+	mockCoinHelper.On(
+		"ComputedCoins",
+		mock.Anything,
+		mtxn,
+		account1,
+	).Return(
+		[]*types.Coin{coin1},
+		block,
+		nil,
+	).Once()
+
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		err := r.Reconcile(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+	}()
+
+	failure := <-handler.failures
+	assert.Equal(t, &CoinReconciliationFailure{
+		Account:    account1,
+		Block:      block,
+		Missing:    []*types.Coin{coin2},
+		Extra:      []*types.Coin{},
+		Mismatched: []*CoinMismatch{},
+	}, failure)
+
+	failure = <-handler.failures
+	assert.Equal(t, &CoinReconciliationFailure{
+		Account:    account2,
+		Block:      block,
+		Missing:    []*types.Coin{},
+		Extra:      []*types.Coin{coin3},
+		Mismatched: []*CoinMismatch{},
+	}, failure)
+
+	assert.Equal(t, account1, <-handler.successes)
+	cancel()
+
+	assert.Len(t, handler.failures, 0)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockCoinHelper.AssertExpectations(t)
+}
+
+func TestComputedCoinsAt_NotComputed(t *testing.T) {
+	ctx := context.Background()
+	account := &types.AccountIdentifier{Address: "addr 1"}
+	block := &types.BlockIdentifier{
+		Hash:  "block 1",
+		Index: 1,
+	}
+
+	mockHelper := &mocks.Helper{}
+	mockCoinHelper := &mocks.CoinHelper{}
+	r := New(
+		mockHelper,
+		&mocks.Handler{},
+		parser.New(nil, nil, nil),
+		WithCoinReconciliation(mockCoinHelper, newCoinHandler(), nil, time.Second),
+	)
+
+	mtxn := &mockDatabase.Transaction{}
+	mtxn.On("Discard", ctx).Once()
+	mockHelper.On("DatabaseTransaction", ctx).Return(mtxn).Once()
+
+	// No block has been synced, so the
+	// computed coins have no head.
+	mockCoinHelper.On("ComputedCoins", ctx, mtxn, account).Return(nil, nil, nil).Once()
+
+	coins, err := r.computedCoinsAt(ctx, account, block)
+	assert.NoError(t, err)
+	assert.Nil(t, coins)
+	mockHelper.AssertExpectations(t)
+	mockCoinHelper.AssertExpectations(t)
+	mtxn.AssertExpectations(t)
+}
@@ -16,6 +16,7 @@ package reconciler
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -84,6 +85,18 @@ func WithInactiveFrequency(blocks int64) Option {
 	}
 }
 
+// WithLiveBalanceRetries overrides the number of times
+// a live balance lookup that failed with ErrLiveBalanceTransient
+// is retried and the time to wait before the first retry
+// (which doubles after each retry). Lookups that still fail
+// are handled like any other lookup failure.
+func WithLiveBalanceRetries(retries int, backoff time.Duration) Option {
+	return func(r *Reconciler) {
+		r.liveBalanceRetries = retries
+		r.liveBalanceBackoff = backoff
+	}
+}
+
 // WithDebugLogging determines if verbose logs should
 // be printed.
 func WithDebugLogging() Option {
@@ -110,3 +123,134 @@ func WithBacklogSize(size int) Option {
 		r.backlogSize = size
 	}
 }
+
+// WithQueueStorage persists the active reconciliation
+// queue using the provided QueueStorage. Any changes
+// that were not reconciled before a restart are restored
+// when Reconcile is called.
+func WithQueueStorage(queueStorage QueueStorage) Option {
+	return func(r *Reconciler) {
+		r.queueStorage = queueStorage
+	}
+}
+
+// WithPriorityAccounts marks accounts as priority. Changes
+// to priority accounts are reconciled before any other
+// changes in the active reconciliation backlog.
+func WithPriorityAccounts(priority []*types.AccountCurrency) Option {
+	return func(r *Reconciler) {
+		for _, acct := range priority {
+			r.priorityAccounts[types.Hash(acct)] = struct{}{}
+		}
+	}
+}
+
+// WithFailureDebugging looks up (at most maxOperations)
+// operations that affected an account since it was last
+// reconciled when a reconciliation fails. These operations
+// are provided to the Handler in a *ReconciliationFailure
+// if it implements FailureHandler. Operations are only
+// looked up when a reconciliation fails.
+func WithFailureDebugging(helper DebugHelper, maxOperations int) Option {
+	return func(r *Reconciler) {
+		r.debugHelper = helper
+		r.debugMaxOperations = maxOperations
+	}
+}
+
+// WithChangeDeduplication merges changes for an account
+// and currency that already has a change pending active
+// reconciliation into the pending change (which is then
+// reconciled at the newer block) instead of enqueueing
+// them separately. This reduces node queries when accounts
+// change in many consecutive blocks but means not every
+// height is checked.
+func WithChangeDeduplication() Option {
+	return func(r *Reconciler) {
+		r.deduplicateChanges = true
+	}
+}
+
+// WithShouldReconcile sets a ShouldReconcile function
+// that is consulted before enqueueing any account for
+// active or inactive reconciliation. Unlike balance
+// exemptions, accounts that should not be reconciled
+// are never looked up.
+func WithShouldReconcile(shouldReconcile ShouldReconcile) Option {
+	return func(r *Reconciler) {
+		r.shouldReconcile = shouldReconcile
+	}
+}
+
+// WithSkippedCurrencies prevents any balance of the
+// provided currencies from being reconciled. This can be
+// combined with WithShouldReconcile.
+func WithSkippedCurrencies(currencies []*types.Currency) Option {
+	return func(r *Reconciler) {
+		skipped := map[string]struct{}{}
+		for _, currency := range currencies {
+			skipped[types.Hash(currency)] = struct{}{}
+		}
+
+		existing := r.shouldReconcile
+		r.shouldReconcile = func(
+			account *types.AccountIdentifier,
+			currency *types.Currency,
+		) bool {
+			if _, ok := skipped[types.Hash(currency)]; ok {
+				return false
+			}
+
+			if existing != nil {
+				return existing(account, currency)
+			}
+
+			return true
+		}
+	}
+}
+
+// WithInactiveCoverageTarget paces inactive reconciliation
+// so that every account is reconciled once per target,
+// using the number of accounts returned by the AccountCounter.
+// When provided, inactive frequency is ignored.
+func WithInactiveCoverageTarget(counter AccountCounter, target time.Duration) Option {
+	return func(r *Reconciler) {
+		r.inactivePacer = newInactivePacer(counter, target)
+	}
+}
+
+// WithCoinReconciliation periodically compares the unspent
+// coins of each provided (UTXO-tracked) account computed
+// by the CoinHelper with the unspent coins returned by the
+// node. Because this is much more expensive than balance
+// reconciliation, at most one account is checked every
+// interval (defaultCoinReconciliationInterval if <= 0).
+func WithCoinReconciliation(
+	helper CoinHelper,
+	handler CoinHandler,
+	accounts []*types.AccountIdentifier,
+	interval time.Duration,
+) Option {
+	return func(r *Reconciler) {
+		r.coinHelper = helper
+		r.coinHandler = handler
+		if interval > 0 {
+			r.coinInterval = interval
+		}
+
+		for _, account := range accounts {
+			r.addCoinAccount(account)
+		}
+	}
+}
+
+// WithReport records the outcome of each reconciliation
+// so that a report of all accounts returned by the ReportHelper
+// can be exported with ExportReport.
+func WithReport(helper ReportHelper) Option {
+	return func(r *Reconciler) {
+		r.reportHelper = helper
+		r.reportRecords = map[string]*reportRecord{}
+	}
+}
@@ -34,10 +34,21 @@ var (
 	// that the block was orphaned.
 	ErrBlockGone = errors.New("block gone")
 
+	// ErrLiveBalanceTransient should be wrapped by
+	// Helper.LiveBalance when a lookup failed but may
+	// succeed if retried. Lookups that fail with any other
+	// error are not retried.
+	ErrLiveBalanceTransient = errors.New("transient live balance lookup failure")
+
 	ErrGetCurrentBlockFailed    = errors.New("unable to get current block for reconciliation")
 	ErrBlockExistsFailed        = errors.New("unable to check if block exists")
 	ErrGetComputedBalanceFailed = errors.New("unable to get computed balance")
 	ErrLiveBalanceLookupFailed  = errors.New("unable to lookup live balance")
+	ErrQueueStorageFailed       = errors.New("unable to update persisted reconciliation queue")
+	ErrGetComputedCoinsFailed   = errors.New("unable to get computed coins")
+	ErrReportNotEnabled         = errors.New("reconciliation report not enabled")
+	ErrExportReportFailed       = errors.New("unable to export reconciliation report")
+	ErrAccountCountFailed       = errors.New("unable to get account count")
 )
 
 // Err takes an error as an argument and returns
@@ -46,10 +57,16 @@ func Err(err error) bool {
 	reconcilerErrors := []error{
 		ErrHeadBlockBehindLive,
 		ErrBlockGone,
+		ErrLiveBalanceTransient,
 		ErrGetCurrentBlockFailed,
 		ErrBlockExistsFailed,
 		ErrGetComputedBalanceFailed,
 		ErrLiveBalanceLookupFailed,
+		ErrQueueStorageFailed,
+		ErrGetComputedCoinsFailed,
+		ErrReportNotEnabled,
+		ErrExportReportFailed,
+		ErrAccountCountFailed,
 	}
 
 	return utils.FindError(reconcilerErrors, err)
@@ -0,0 +1,106 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// lastReconciledCache stores the last block each
+// *types.AccountCurrency was reconciled successfully.
+// When the cache is full, the least recently
+// reconciled *types.AccountCurrency is evicted (and
+// operations are looked up from the oldest stored
+// block if its reconciliation later fails).
+type lastReconciledCache struct {
+	size int
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // most recently reconciled at the front
+}
+
+// lastReconciledEntry is the value of each
+// element in lastReconciledCache.order.
+type lastReconciledEntry struct {
+	key   string
+	block *types.BlockIdentifier
+}
+
+func newLastReconciledCache(size int) *lastReconciledCache {
+	return &lastReconciledCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// get returns the last block a *types.AccountCurrency
+// was reconciled successfully (nil if unknown).
+func (c *lastReconciledCache) get(accountCurrency *types.AccountCurrency) *types.BlockIdentifier {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[types.Hash(accountCurrency)]
+	if !ok {
+		return nil
+	}
+
+	return element.Value.(*lastReconciledEntry).block
+}
+
+// set records that a *types.AccountCurrency was
+// reconciled successfully at block (if block is
+// newer than the stored block).
+func (c *lastReconciledCache) set(
+	accountCurrency *types.AccountCurrency,
+	block *types.BlockIdentifier,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := types.Hash(accountCurrency)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lastReconciledEntry)
+		if entry.block.Index < block.Index {
+			entry.block = block
+		}
+
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lastReconciledEntry{
+		key:   key,
+		block: block,
+	})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lastReconciledEntry).key)
+	}
+}
+
+// len returns the number of entries in the cache.
+func (c *lastReconciledCache) len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.order.Len()
+}
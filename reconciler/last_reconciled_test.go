@@ -0,0 +1,56 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestLastReconciledCache(t *testing.T) {
+	cache := newLastReconciledCache(2)
+	accounts := make([]*types.AccountCurrency, 3)
+	for i := range accounts {
+		accounts[i] = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: fmt.Sprintf("addr %d", i)},
+			Currency: &types.Currency{Symbol: "BTC", Decimals: 8},
+		}
+	}
+	block1 := &types.BlockIdentifier{Index: 1, Hash: "block 1"}
+	block2 := &types.BlockIdentifier{Index: 2, Hash: "block 2"}
+
+	assert.Nil(t, cache.get(accounts[0]))
+
+	cache.set(accounts[0], block2)
+	cache.set(accounts[1], block1)
+	assert.Equal(t, block2, cache.get(accounts[0]))
+	assert.Equal(t, block1, cache.get(accounts[1]))
+
+	// Older blocks don't overwrite newer blocks (but
+	// still mark the account as recently reconciled).
+	cache.set(accounts[0], block1)
+	assert.Equal(t, block2, cache.get(accounts[0]))
+
+	// The least recently reconciled account is evicted.
+	cache.set(accounts[2], block2)
+	assert.Equal(t, 2, cache.len())
+	assert.Equal(t, block2, cache.get(accounts[0]))
+	assert.Nil(t, cache.get(accounts[1]))
+	assert.Equal(t, block2, cache.get(accounts[2]))
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// inactivePacer spaces inactive reconciliations (across
+// all inactive workers) so that every tracked account is
+// reconciled once per coverage target. The number of tracked
+// accounts is re-evaluated every refreshInterval.
+type inactivePacer struct {
+	counter         AccountCounter
+	target          time.Duration
+	refreshInterval time.Duration
+
+	// now is used to determine when the next
+	// reconciliation can occur (overridden in tests).
+	now func() time.Time
+
+	mutex       sync.Mutex
+	pace        time.Duration
+	lastRefresh time.Time
+	next        time.Time
+}
+
+func newInactivePacer(counter AccountCounter, target time.Duration) *inactivePacer {
+	return &inactivePacer{
+		counter:         counter,
+		target:          target,
+		refreshInterval: defaultPaceRefreshInterval,
+		now:             time.Now,
+	}
+}
+
+// computePace returns the delay between inactive
+// reconciliations required to reconcile all accounts
+// once every target.
+func computePace(target time.Duration, accounts int64) time.Duration {
+	if accounts <= 0 {
+		return 0
+	}
+
+	return target / time.Duration(accounts)
+}
+
+// refresh updates the pace if refreshInterval has
+// elapsed since the last update. The caller must
+// hold mutex.
+func (p *inactivePacer) refresh(ctx context.Context, now time.Time) error {
+	if !p.lastRefresh.IsZero() && now.Sub(p.lastRefresh) < p.refreshInterval {
+		return nil
+	}
+
+	accounts, err := p.counter.AccountCount(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAccountCountFailed, err)
+	}
+
+	p.pace = computePace(p.target, accounts)
+	p.lastRefresh = now
+	return nil
+}
+
+// reserve returns how long the caller must wait
+// before performing an inactive reconciliation.
+func (p *inactivePacer) reserve(ctx context.Context) (time.Duration, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+	if err := p.refresh(ctx, now); err != nil {
+		return 0, err
+	}
+
+	if p.next.Before(now) {
+		p.next = now
+	}
+
+	delay := p.next.Sub(now)
+	p.next = p.next.Add(p.pace)
+
+	return delay, nil
+}
+
+// wait blocks until an inactive reconciliation
+// can be performed or the context is canceled.
+func (p *inactivePacer) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay, err := p.reserve(ctx)
+	if err != nil {
+		return err
+	}
+
+	return utils.ContextSleep(ctx, delay)
+}
+
+// currentPace returns the last computed pace.
+func (p *inactivePacer) currentPace() time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.pace
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+)
+
+func TestComputePace(t *testing.T) {
+	var tests = map[string]struct {
+		target   time.Duration
+		accounts int64
+
+		pace time.Duration
+	}{
+		"no accounts": {
+			target:   24 * time.Hour,
+			accounts: 0,
+			pace:     0,
+		},
+		"small account count": {
+			target:   24 * time.Hour,
+			accounts: 1000,
+			pace:     86400 * time.Millisecond,
+		},
+		"huge account count": {
+			target:   24 * time.Hour,
+			accounts: 10000000,
+			pace:     8640 * time.Microsecond,
+		},
+		"huge account count short target": {
+			target:   1 * time.Hour,
+			accounts: 10000000,
+			pace:     360 * time.Microsecond,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.pace, computePace(test.target, test.accounts))
+		})
+	}
+}
+
+func TestInactivePacer(t *testing.T) {
+	ctx := context.Background()
+	mockCounter := &mocks.AccountCounter{}
+	pacer := newInactivePacer(mockCounter, 1*time.Second)
+
+	now := time.Unix(1000, 0)
+	pacer.now = func() time.Time {
+		return now
+	}
+	reserve := func() time.Duration {
+		delay, err := pacer.reserve(ctx)
+		assert.NoError(t, err)
+		return delay
+	}
+
+	t.Run("initial pace", func(t *testing.T) {
+		mockCounter.On("AccountCount", ctx).Return(int64(100), nil).Once()
+		assert.Equal(t, time.Duration(0), reserve())
+		assert.Equal(t, 10*time.Millisecond, pacer.currentPace())
+		assert.Equal(t, 10*time.Millisecond, reserve())
+		assert.Equal(t, 20*time.Millisecond, reserve())
+	})
+
+	t.Run("time elapsed", func(t *testing.T) {
+		now = now.Add(25 * time.Millisecond)
+		assert.Equal(t, 5*time.Millisecond, reserve())
+
+		now = now.Add(1 * time.Second)
+		assert.Equal(t, time.Duration(0), reserve())
+	})
+
+	t.Run("accounts added", func(t *testing.T) {
+		now = now.Add(defaultPaceRefreshInterval)
+		mockCounter.On("AccountCount", ctx).Return(int64(1000), nil).Once()
+		assert.Equal(t, time.Duration(0), reserve())
+		assert.Equal(t, 1*time.Millisecond, pacer.currentPace())
+		assert.Equal(t, 1*time.Millisecond, reserve())
+	})
+
+	t.Run("count fails", func(t *testing.T) {
+		now = now.Add(defaultPaceRefreshInterval)
+		mockCounter.On("AccountCount", ctx).Return(int64(-1), errors.New("count failed")).Once()
+		delay, err := pacer.reserve(ctx)
+		assert.Equal(t, time.Duration(0), delay)
+		assert.True(t, errors.Is(err, ErrAccountCountFailed))
+		assert.Equal(t, 1*time.Millisecond, pacer.currentPace())
+	})
+
+	t.Run("wait canceled", func(t *testing.T) {
+		pacer.pace = 1 * time.Hour
+		pacer.next = now.Add(1 * time.Hour)
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.True(t, errors.Is(pacer.wait(ctx), context.Canceled))
+	})
+
+	mockCounter.AssertExpectations(t)
+}
+
+func TestInactivePacerEmptyQueue(t *testing.T) {
+	mockCounter := &mocks.AccountCounter{}
+	r := New(
+		&mocks.Helper{},
+		&mocks.Handler{},
+		nil,
+		WithInactiveCoverageTarget(mockCounter, 1*time.Second),
+	)
+
+	// Polling an empty queue should not reserve
+	// a reconciliation (or refresh the pace).
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := r.reconcileInactiveAccounts(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, r.inactivePacer.next.IsZero())
+
+	mockCounter.AssertExpectations(t)
+}
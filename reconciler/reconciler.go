@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -45,6 +46,13 @@ func New(
 		InactiveConcurrency: defaultReconcilerConcurrency,
 		highWaterMark:       -1,
 		seenAccounts:        map[string]struct{}{},
+		priorityAccounts:    map[string]struct{}{},
+		lastReconciled:      newLastReconciledCache(defaultLastReconciledLimit),
+		pendingChanges:      map[string]*parser.BalanceChange{},
+		coinAccountsSet:     map[string]struct{}{},
+		coinInterval:        defaultCoinReconciliationInterval,
+		liveBalanceRetries:  defaultLiveBalanceRetries,
+		liveBalanceBackoff:  defaultLiveBalanceBackoff,
 		inactiveQueue:       []*InactiveEntry{},
 		inactiveQueueMutex:  new(utils.PriorityMutex),
 		backlogSize:         defaultBacklogSize,
@@ -56,13 +64,34 @@ func New(
 		opt(r)
 	}
 
-	// Create change queue
+	// Remove any seen accounts that should not be reconciled
+	inactiveQueue := []*InactiveEntry{}
+	for _, entry := range r.inactiveQueue {
+		if !r.reconcilable(entry.Entry.Account, entry.Entry.Currency) {
+			delete(r.seenAccounts, types.Hash(entry.Entry))
+			continue
+		}
+
+		inactiveQueue = append(inactiveQueue, entry)
+	}
+	r.inactiveQueue = inactiveQueue
+
+	// Create change queues
 	r.changeQueue = make(chan *parser.BalanceChange, r.backlogSize)
+	r.priorityQueue = make(chan *parser.BalanceChange, r.backlogSize)
 
-	// Create queueMap
+	// Create queueMap (with at least shardBuffer shards so that
+	// changes can still be queued when there are no workers)
 	desiredShardCount := shardBuffer * (r.ActiveConcurrency + r.InactiveConcurrency)
+	if desiredShardCount < shardBuffer {
+		desiredShardCount = shardBuffer
+	}
 	r.queueMap = utils.NewShardedMap(desiredShardCount)
 
+	// Create worker pools
+	r.activePool = newWorkerPool(r.ActiveConcurrency)
+	r.inactivePool = newWorkerPool(r.InactiveConcurrency)
+
 	return r
 }
 
@@ -76,19 +105,70 @@ func (r *Reconciler) debugLog(
 	}
 }
 
+// reconcilable returns a boolean indicating if an account
+// and currency should be reconciled. If not, the ignored
+// count is incremented.
+func (r *Reconciler) reconcilable(
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) bool {
+	if r.shouldReconcile == nil || r.shouldReconcile(account, currency) {
+		return true
+	}
+
+	atomic.AddInt64(&r.ignored, 1)
+	return false
+}
+
+// AddPriorityAccount marks an account as priority. Any
+// changes to the account enqueued after this call are
+// reconciled before the rest of the active backlog.
+func (r *Reconciler) AddPriorityAccount(accountCurrency *types.AccountCurrency) {
+	r.priorityAccountsMutex.Lock()
+	defer r.priorityAccountsMutex.Unlock()
+
+	r.priorityAccounts[types.Hash(accountCurrency)] = struct{}{}
+}
+
+// activeQueue returns the queue a *parser.BalanceChange
+// should be added to for active reconciliation.
+func (r *Reconciler) activeQueue(change *parser.BalanceChange) chan *parser.BalanceChange {
+	r.priorityAccountsMutex.RLock()
+	defer r.priorityAccountsMutex.RUnlock()
+
+	if _, ok := r.priorityAccounts[types.Hash(&types.AccountCurrency{
+		Account:  change.Account,
+		Currency: change.Currency,
+	})]; ok {
+		return r.priorityQueue
+	}
+
+	return r.changeQueue
+}
+
 func (r *Reconciler) wrappedActiveEnqueue(
 	ctx context.Context,
 	change *parser.BalanceChange,
 ) {
 	select {
-	case r.changeQueue <- change:
+	case r.activeQueue(change) <- change:
 	default:
 		r.debugLog(
 			"skipping active enqueue because backlog has %d items",
 			r.backlogSize,
 		)
 
-		if err := r.handler.ReconciliationSkipped(
+		r.claimChange(change)
+
+		// If the context is canceled, we leave the change
+		// in queueStorage so it is restored on restart.
+		if ctx.Err() == nil {
+			if err := r.unpersistChange(ctx, change); err != nil {
+				log.Printf("%s: unable to remove persisted change\n", err.Error())
+			}
+		}
+
+		if err := r.reconciliationSkipped(
 			ctx,
 			ActiveReconciliation,
 			change.Account,
@@ -113,6 +193,81 @@ func (r *Reconciler) wrappedInactiveEnqueue(
 	}
 }
 
+// persistChange stores a *parser.BalanceChange
+// in queueStorage (if provided).
+func (r *Reconciler) persistChange(
+	ctx context.Context,
+	change *parser.BalanceChange,
+) error {
+	if r.queueStorage == nil {
+		return nil
+	}
+
+	if err := r.queueStorage.Enqueue(ctx, change); err != nil {
+		return fmt.Errorf("%w: %v", ErrQueueStorageFailed, err)
+	}
+
+	return nil
+}
+
+// unpersistChange removes a *parser.BalanceChange
+// from queueStorage (if provided).
+func (r *Reconciler) unpersistChange(
+	ctx context.Context,
+	change *parser.BalanceChange,
+) error {
+	if r.queueStorage == nil {
+		return nil
+	}
+
+	if err := r.queueStorage.Dequeue(ctx, change); err != nil {
+		return fmt.Errorf("%w: %v", ErrQueueStorageFailed, err)
+	}
+
+	return nil
+}
+
+// restoreQueue adds all *parser.BalanceChange persisted
+// in queueStorage to the active reconciliation queue. Unlike
+// new changes, restored changes are never dropped if the
+// backlog is full.
+func (r *Reconciler) restoreQueue(ctx context.Context) error {
+	changes, err := r.queueStorage.Restore(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrQueueStorageFailed, err)
+	}
+
+	if len(changes) > 0 {
+		log.Printf("restoring %d changes for active reconciliation\n", len(changes))
+	}
+
+	for _, change := range changes {
+		if !r.reconcilable(change.Account, change.Currency) {
+			if err := r.unpersistChange(ctx, change); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		key := types.Hash(&types.AccountCurrency{
+			Account:  change.Account,
+			Currency: change.Currency,
+		})
+		m := r.queueMap.Lock(key, true)
+		r.addToQueueMap(m, key, change.Block.Index)
+		r.queueMap.Unlock(key)
+
+		select {
+		case r.activeQueue(change) <- change:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
 // addToQueueMap adds a *types.AccountCurrency
 // to the prune map at the provided index.
 func (r *Reconciler) addToQueueMap(
@@ -202,10 +357,15 @@ func (r *Reconciler) queueChanges(
 	}
 
 	for _, change := range balanceChanges {
+		// Skip changes that should never be reconciled
+		if !r.reconcilable(change.Account, change.Currency) {
+			continue
+		}
+
 		// All changes will have the same block. Continue
 		// if we are too far behind to start reconciling.
 		if block.Index < r.highWaterMark {
-			if err := r.handler.ReconciliationSkipped(
+			if err := r.reconciliationSkipped(
 				ctx,
 				ActiveReconciliation,
 				change.Account,
@@ -233,6 +393,21 @@ func (r *Reconciler) queueChanges(
 			return err
 		}
 
+		// Merge change into any pending change for the
+		// same account and currency.
+		if r.deduplicateChanges {
+			pending, err := r.coalesceChange(ctx, change)
+			if err != nil {
+				return err
+			}
+
+			if pending == nil {
+				continue
+			}
+
+			change = pending
+		}
+
 		// Add change to queueMap before enqueuing to ensure
 		// there is no possible race.
 		key := types.Hash(acctCurrency)
@@ -240,6 +415,12 @@ func (r *Reconciler) queueChanges(
 		r.addToQueueMap(m, key, change.Block.Index)
 		r.queueMap.Unlock(key)
 
+		// Persist change before enqueuing so that it is
+		// not lost if we exit before it is reconciled.
+		if err := r.persistChange(ctx, change); err != nil {
+			return err
+		}
+
 		// Add change to active queue
 		r.wrappedActiveEnqueue(ctx, change)
 	}
@@ -247,11 +428,129 @@ func (r *Reconciler) queueChanges(
 	return nil
 }
 
+// coalesceChange merges a *parser.BalanceChange into the
+// pending change for the same account and currency (if
+// one exists at an older block) and returns nil. Otherwise,
+// it returns a new pending change to enqueue.
+//
+// Changes are never merged into a pending change at the
+// same or a newer block (which can occur during a reorg)
+// so that a pending change is always reconciled at a block
+// greater than or equal to every change it replaced.
+func (r *Reconciler) coalesceChange(
+	ctx context.Context,
+	change *parser.BalanceChange,
+) (*parser.BalanceChange, error) {
+	r.pendingChangesMutex.Lock()
+	defer r.pendingChangesMutex.Unlock()
+
+	acctCurrency := &types.AccountCurrency{
+		Account:  change.Account,
+		Currency: change.Currency,
+	}
+	key := types.Hash(acctCurrency)
+	pending, ok := r.pendingChanges[key]
+	if !ok {
+		// We copy the change so that we never modify
+		// a *parser.BalanceChange we don't own.
+		pending = &parser.BalanceChange{
+			Account:    change.Account,
+			Currency:   change.Currency,
+			Block:      change.Block,
+			Difference: change.Difference,
+		}
+		r.pendingChanges[key] = pending
+
+		return pending, nil
+	}
+
+	if change.Block.Index <= pending.Block.Index {
+		return change, nil
+	}
+
+	// Move the pending change to the newer block
+	// in the queueMap (without pruning).
+	m := r.queueMap.Lock(key, true)
+	r.addToQueueMap(m, key, change.Block.Index)
+	r.queueMap.Unlock(key)
+
+	if err := r.updateQueueMap(ctx, acctCurrency, pending.Block.Index, false); err != nil {
+		return nil, err
+	}
+
+	// Replace the persisted pending change
+	if err := r.persistChange(ctx, change); err != nil {
+		return nil, err
+	}
+
+	if err := r.unpersistChange(ctx, pending); err != nil {
+		return nil, err
+	}
+
+	pending.Block = change.Block
+	pending.Difference = change.Difference
+	atomic.AddInt64(&r.coalesced, 1)
+
+	return nil, nil
+}
+
+// claimChange removes a *parser.BalanceChange from
+// pendingChanges so that no more changes can be merged
+// into it. This must be called before a pending change
+// is reconciled.
+func (r *Reconciler) claimChange(change *parser.BalanceChange) {
+	if !r.deduplicateChanges {
+		return
+	}
+
+	r.pendingChangesMutex.Lock()
+	defer r.pendingChangesMutex.Unlock()
+
+	key := types.Hash(&types.AccountCurrency{
+		Account:  change.Account,
+		Currency: change.Currency,
+	})
+	if r.pendingChanges[key] == change {
+		delete(r.pendingChanges, key)
+	}
+}
+
 // QueueSize is a helper that returns the total
 // number of items currently enqueued for active
-// reconciliation.
+// reconciliation (including priority items).
 func (r *Reconciler) QueueSize() int {
-	return len(r.changeQueue)
+	return len(r.changeQueue) + len(r.priorityQueue)
+}
+
+// PriorityQueueSize is a helper that returns the
+// number of items currently enqueued for active
+// reconciliation of priority accounts.
+func (r *Reconciler) PriorityQueueSize() int {
+	return len(r.priorityQueue)
+}
+
+// Stats returns the current *Stats
+// of the Reconciler.
+func (r *Reconciler) Stats() *Stats {
+	return &Stats{
+		QueueSize:           r.QueueSize(),
+		PriorityQueueSize:   r.PriorityQueueSize(),
+		ActiveConcurrency:   r.CurrentActiveConcurrency(),
+		InactiveConcurrency: r.CurrentInactiveConcurrency(),
+		Ignored:             atomic.LoadInt64(&r.ignored),
+		Coalesced:           atomic.LoadInt64(&r.coalesced),
+		InactivePace:        r.inactivePace(),
+	}
+}
+
+// inactivePace returns the current pace of
+// inactive reconciliation (if configured).
+func (r *Reconciler) inactivePace() time.Duration {
+	if r.inactivePacer == nil {
+		return 0
+	}
+
+	return r.inactivePacer.currentPace()
 }
 
 // LastIndexReconciled is the last block index
@@ -370,13 +669,55 @@ func (r *Reconciler) bestLiveBalance(
 		lookupIndex = index
 	}
 
-	amount, liveBlock, err := r.helper.LiveBalance(
+	var (
+		amount    *types.Amount
+		liveBlock *types.BlockIdentifier
+		attempt   int
+	)
+	err := utils.Retry(
 		ctx,
-		account,
-		currency,
-		lookupIndex,
+		r.liveBalanceRetries+1,
+		&utils.ExponentialBackoff{
+			Base:       r.liveBalanceBackoff,
+			Multiplier: liveBalanceBackoffMultiplier,
+			Max:        maxLiveBalanceBackoff,
+		},
+		func() error {
+			var err error
+			amount, liveBlock, err = r.helper.LiveBalance(
+				ctx,
+				account,
+				currency,
+				lookupIndex,
+			)
+			if err == nil {
+				return nil
+			}
+
+			// Only transient errors are retried. Any other
+			// error is returned immediately.
+			if !errors.Is(err, ErrLiveBalanceTransient) {
+				return utils.Permanent(err)
+			}
+
+			attempt++
+			r.debugLog(
+				"%s: retrying live balance lookup for %s %s at %d (prior attempts: %d)",
+				err.Error(),
+				types.PrintStruct(account),
+				types.PrintStruct(currency),
+				lookupIndex,
+				attempt,
+			)
+
+			return err
+		},
 	)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
 		return nil, nil, fmt.Errorf(
 			"%w: unable to get live balance for %s %s at %d",
 			err,
@@ -409,6 +750,8 @@ func (r *Reconciler) handleBalanceMismatch(
 		difference,
 	)
 	if exemption != nil {
+		r.recordExemption(account, currency)
+
 		// Return handler result (regardless if error) so that we don't invoke the handler for
 		// a failed reconciliation as well.
 		return r.handler.ReconciliationExempt(
@@ -426,6 +769,28 @@ func (r *Reconciler) handleBalanceMismatch(
 	// If we didn't find a matching exemption,
 	// we should consider the reconciliation
 	// a failure.
+	r.recordFailure(account, currency, &ReportFailure{
+		ReconciliationType: reconciliationType,
+		ComputedBalance:    computedBalance,
+		LiveBalance:        liveBalance,
+		Block:              block,
+	})
+
+	if failureHandler, ok := r.handler.(FailureHandler); ok && r.debugHelper != nil {
+		return failureHandler.ReconciliationFailedWithDetails(
+			ctx,
+			r.reconciliationFailure(
+				ctx,
+				reconciliationType,
+				account,
+				currency,
+				computedBalance,
+				liveBalance,
+				block,
+			),
+		)
+	}
+
 	err := r.handler.ReconciliationFailed(
 		ctx,
 		reconciliationType,
@@ -442,6 +807,73 @@ func (r *Reconciler) handleBalanceMismatch(
 	return nil
 }
 
+// setLastReconciled records the last block an account
+// was reconciled successfully (only when failure debugging
+// is enabled). If more than defaultLastReconciledLimit
+// accounts have been reconciled, the least recently
+// reconciled account is forgotten.
+func (r *Reconciler) setLastReconciled(
+	accountCurrency *types.AccountCurrency,
+	block *types.BlockIdentifier,
+) {
+	if r.debugHelper == nil {
+		return
+	}
+
+	r.lastReconciled.set(accountCurrency, block)
+}
+
+// reconciliationFailure constructs a *ReconciliationFailure,
+// looking up all operations that affected the account since
+// it was last reconciled.
+func (r *Reconciler) reconciliationFailure(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	computedBalance string,
+	liveBalance string,
+	block *types.BlockIdentifier,
+) *ReconciliationFailure {
+	lastReconciled := r.lastReconciled.get(&types.AccountCurrency{
+		Account:  account,
+		Currency: currency,
+	})
+
+	failure := &ReconciliationFailure{
+		ReconciliationType: reconciliationType,
+		Account:            account,
+		Currency:           currency,
+		ComputedBalance:    computedBalance,
+		LiveBalance:        liveBalance,
+		Block:              block,
+		LastReconciled:     lastReconciled,
+	}
+
+	// We request one more operation than the limit
+	// to determine if the operations were truncated.
+	operations, err := r.debugHelper.BalanceOperations(
+		ctx,
+		account,
+		currency,
+		lastReconciled,
+		block,
+		r.debugMaxOperations+1,
+	)
+	if err != nil {
+		failure.DebugError = err.Error()
+		return failure
+	}
+
+	if len(operations) > r.debugMaxOperations {
+		operations = operations[:r.debugMaxOperations]
+		failure.Truncated = true
+	}
+
+	failure.Operations = operations
+	return failure
+}
+
 // accountReconciliation returns an error if the provided
 // AccountAndCurrency's live balance cannot be reconciled
 // with the computed balance.
@@ -479,7 +911,9 @@ func (r *Reconciler) accountReconciliation(
 				// lookupBalanceByBlock is enabled.
 				diff := liveBlock.Index - headIndex
 				if diff < waitToCheckDiff {
-					time.Sleep(waitToCheckDiffSleep)
+					if err := utils.ContextSleep(ctx, waitToCheckDiffSleep); err != nil {
+						return err
+					}
 					continue
 				}
 
@@ -495,7 +929,7 @@ func (r *Reconciler) accountReconciliation(
 				// after this new highWaterMark.
 				r.highWaterMark = liveBlock.Index
 
-				return r.handler.ReconciliationSkipped(
+				return r.reconciliationSkipped(
 					ctx,
 					reconciliationType,
 					account,
@@ -512,7 +946,7 @@ func (r *Reconciler) accountReconciliation(
 					types.PrintStruct(liveBlock),
 				)
 
-				return r.handler.ReconciliationSkipped(
+				return r.reconciliationSkipped(
 					ctx,
 					reconciliationType,
 					account,
@@ -534,7 +968,7 @@ func (r *Reconciler) accountReconciliation(
 					types.PrintStruct(account),
 				)
 
-				return r.handler.ReconciliationSkipped(
+				return r.reconciliationSkipped(
 					ctx,
 					reconciliationType,
 					account,
@@ -559,6 +993,9 @@ func (r *Reconciler) accountReconciliation(
 			)
 		}
 
+		r.setLastReconciled(accountCurrency, liveBlock)
+		r.recordSuccess(accountCurrency.Account, accountCurrency.Currency, liveBlock)
+
 		return r.handler.ReconciliationSucceeded(
 			ctx,
 			reconciliationType,
@@ -642,7 +1079,7 @@ func (r *Reconciler) skipAndPrune(
 	change *parser.BalanceChange,
 	skipCause string,
 ) error {
-	if err := r.handler.ReconciliationSkipped(
+	if err := r.reconciliationSkipped(
 		ctx,
 		ActiveReconciliation,
 		change.Account,
@@ -652,6 +1089,10 @@ func (r *Reconciler) skipAndPrune(
 		return err
 	}
 
+	if err := r.unpersistChange(ctx, change); err != nil {
+		return err
+	}
+
 	return r.updateQueueMap(
 		ctx,
 		&types.AccountCurrency{
@@ -711,93 +1152,138 @@ func (r *Reconciler) updateQueueMap(
 	return r.pruneBalances(ctx, acctCurrency, index)
 }
 
+// nextActiveChange returns the next *parser.BalanceChange
+// to reconcile actively. Changes in the priorityQueue are
+// always returned before changes in the changeQueue. If
+// active concurrency is changed while waiting, nil is
+// returned.
+func (r *Reconciler) nextActiveChange(ctx context.Context) (*parser.BalanceChange, error) {
+	select {
+	case balanceChange := <-r.priorityQueue:
+		r.claimChange(balanceChange)
+		return balanceChange, nil
+	default:
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-r.activePool.wait():
+		return nil, nil
+	case balanceChange := <-r.priorityQueue:
+		r.claimChange(balanceChange)
+		return balanceChange, nil
+	case balanceChange := <-r.changeQueue:
+		r.claimChange(balanceChange)
+		return balanceChange, nil
+	}
+}
+
 // reconcileActiveAccounts selects an account
 // from the Reconciler account queue and
 // reconciles the balance. This is useful
 // for detecting if balance changes in operations
 // were correct.
-func (r *Reconciler) reconcileActiveAccounts(ctx context.Context) error { // nolint:gocognit
+func (r *Reconciler) reconcileActiveAccounts(ctx context.Context) error {
 	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case balanceChange := <-r.changeQueue:
-			if balanceChange.Block.Index < r.highWaterMark {
-				r.debugLog(
-					"waiting to continue active reconciliation until reaching high water mark...",
-				)
-
-				if err := r.skipAndPrune(ctx, balanceChange, HeadBehind); err != nil {
-					return err
-				}
+		if r.activePool.retire() {
+			return nil
+		}
 
-				continue
-			}
+		balanceChange, err := r.nextActiveChange(ctx)
+		if err != nil {
+			return err
+		}
 
-			amount, block, err := r.bestLiveBalance(
-				ctx,
-				balanceChange.Account,
-				balanceChange.Currency,
-				balanceChange.Block.Index,
-			)
-			if err != nil {
-				// Ensure we don't leak reconciliations if
-				// context is canceled.
-				if errors.Is(err, context.Canceled) {
-					r.wrappedActiveEnqueue(ctx, balanceChange)
-					return err
-				}
+		if balanceChange == nil {
+			continue
+		}
 
-				tip, tErr := r.helper.IndexAtTip(ctx, balanceChange.Block.Index)
-				switch {
-				case tErr == nil && tip:
-					if err := r.skipAndPrune(ctx, balanceChange, TipFailure); err != nil {
-						return err
-					}
+		if err := r.reconcileActiveChange(ctx, balanceChange); err != nil {
+			return err
+		}
+	}
+}
 
-					continue
-				case tErr != nil:
-					fmt.Printf("%v: could not determine if at tip\n", tErr)
-				}
+// reconcileActiveChange reconciles a single
+// *parser.BalanceChange dequeued for active
+// reconciliation.
+func (r *Reconciler) reconcileActiveChange(
+	ctx context.Context,
+	balanceChange *parser.BalanceChange,
+) error {
+	if balanceChange.Block.Index < r.highWaterMark {
+		r.debugLog(
+			"waiting to continue active reconciliation until reaching high water mark...",
+		)
 
-				return fmt.Errorf("%w: %v", ErrLiveBalanceLookupFailed, err)
-			}
+		return r.skipAndPrune(ctx, balanceChange, HeadBehind)
+	}
 
-			err = r.accountReconciliation(
-				ctx,
-				balanceChange.Account,
-				balanceChange.Currency,
-				amount.Value,
-				block,
-				false,
-			)
-			if err != nil {
-				// Ensure we don't leak reconciliations if
-				// context is canceled.
-				if errors.Is(err, context.Canceled) {
-					r.wrappedActiveEnqueue(ctx, balanceChange)
-				}
+	amount, block, err := r.bestLiveBalance(
+		ctx,
+		balanceChange.Account,
+		balanceChange.Currency,
+		balanceChange.Block.Index,
+	)
+	if err != nil {
+		// Ensure we don't leak reconciliations if
+		// context is canceled.
+		if errors.Is(err, context.Canceled) {
+			r.wrappedActiveEnqueue(ctx, balanceChange)
+			return err
+		}
 
-				return err
-			}
+		tip, tErr := r.helper.IndexAtTip(ctx, balanceChange.Block.Index)
+		switch {
+		case tErr == nil && tip:
+			return r.skipAndPrune(ctx, balanceChange, TipFailure)
+		case tErr != nil:
+			fmt.Printf("%v: could not determine if at tip\n", tErr)
+		}
 
-			// Attempt to prune historical balances that will not be used
-			// anymore.
-			if err := r.updateQueueMap(
-				ctx,
-				&types.AccountCurrency{
-					Account:  balanceChange.Account,
-					Currency: balanceChange.Currency,
-				},
-				balanceChange.Block.Index,
-				pruneActiveReconciliation,
-			); err != nil {
-				return err
-			}
+		return fmt.Errorf("%w: %v", ErrLiveBalanceLookupFailed, err)
+	}
 
-			r.updateLastChecked(balanceChange.Block.Index)
+	err = r.accountReconciliation(
+		ctx,
+		balanceChange.Account,
+		balanceChange.Currency,
+		amount.Value,
+		block,
+		false,
+	)
+	if err != nil {
+		// Ensure we don't leak reconciliations if
+		// context is canceled.
+		if errors.Is(err, context.Canceled) {
+			r.wrappedActiveEnqueue(ctx, balanceChange)
 		}
+
+		return err
 	}
+
+	if err := r.unpersistChange(ctx, balanceChange); err != nil {
+		return err
+	}
+
+	// Attempt to prune historical balances that will not be used
+	// anymore.
+	if err := r.updateQueueMap(
+		ctx,
+		&types.AccountCurrency{
+			Account:  balanceChange.Account,
+			Currency: balanceChange.Currency,
+		},
+		balanceChange.Block.Index,
+		pruneActiveReconciliation,
+	); err != nil {
+		return err
+	}
+
+	r.updateLastChecked(balanceChange.Block.Index)
+
+	return nil
 }
 
 // shouldAttemptInactiveReconciliation returns a boolean indicating whether
@@ -836,6 +1322,10 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 	ctx context.Context,
 ) error {
 	for ctx.Err() == nil {
+		if r.inactivePool.retire() {
+			return nil
+		}
+
 		r.inactiveQueueMutex.Lock(false)
 		queueLen := len(r.inactiveQueue)
 		if queueLen == 0 {
@@ -843,7 +1333,9 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 			r.debugLog(
 				"no accounts ready for inactive reconciliation (0 accounts in queue)",
 			)
-			time.Sleep(inactiveReconciliationSleep)
+			if err := utils.ContextSleep(ctx, inactiveReconciliationSleep); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -860,7 +1352,9 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 		if !shouldAttempt {
 			r.queueMap.Unlock(key)
 			r.inactiveQueueMutex.Unlock()
-			time.Sleep(inactiveReconciliationSleep)
+			if err := utils.ContextSleep(ctx, inactiveReconciliationSleep); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -869,7 +1363,10 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 			nextValidIndex = nextAcct.LastCheck.Index + r.inactiveFrequency
 		}
 
-		if nextValidIndex <= head.Index ||
+		// When pacing inactive reconciliation, accounts
+		// are always ready to reconcile.
+		if r.inactivePacer != nil ||
+			nextValidIndex <= head.Index ||
 			r.helper.ForceInactiveReconciliation(
 				ctx,
 				nextAcct.Entry.Account,
@@ -884,6 +1381,17 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 			r.addToQueueMap(m, key, head.Index)
 			r.queueMap.Unlock(key)
 
+			// We only reserve a reconciliation from the pacer
+			// once an account is dequeued so that polling an
+			// empty queue does not consume the pace.
+			if r.inactivePacer != nil {
+				if err := r.inactivePacer.wait(ctx); err != nil {
+					// Ensure we don't leak reconciliations
+					r.wrappedInactiveEnqueue(nextAcct.Entry, nextAcct.LastCheck)
+					return err
+				}
+			}
+
 			amount, block, err := r.bestLiveBalance(
 				ctx,
 				nextAcct.Entry.Account,
@@ -900,7 +1408,7 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 				tip, tErr := r.helper.IndexAtTip(ctx, head.Index)
 				switch {
 				case tErr == nil && tip:
-					if err := r.handler.ReconciliationSkipped(
+					if err := r.reconciliationSkipped(
 						ctx,
 						InactiveReconciliation,
 						nextAcct.Entry.Account,
@@ -968,13 +1476,67 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 				queueLen,
 				nextValidIndex,
 			)
-			time.Sleep(inactiveReconciliationSleep)
+			if err := utils.ContextSleep(ctx, inactiveReconciliationSleep); err != nil {
+				return err
+			}
 		}
 	}
 
 	return ctx.Err()
 }
 
+// spawnWorkers starts count workers in the
+// workerGroup. The caller must hold workerMutex.
+func (r *Reconciler) spawnWorkers(
+	count int,
+	worker func(context.Context) error,
+) {
+	for j := 0; j < count; j++ {
+		g, ctx := r.workerGroup, r.workerCtx
+		g.Go(func() error {
+			return worker(ctx)
+		})
+	}
+}
+
+// SetActiveConcurrency changes the number of goroutines
+// used for active reconciliation. This can be called while
+// Reconcile is running. When concurrency is reduced, workers
+// finish any in-flight reconciliation before exiting.
+func (r *Reconciler) SetActiveConcurrency(concurrency int) {
+	r.workerMutex.Lock()
+	defer r.workerMutex.Unlock()
+
+	r.spawnWorkers(r.activePool.resize(concurrency), r.reconcileActiveAccounts)
+}
+
+// SetInactiveConcurrency changes the number of goroutines
+// used for inactive reconciliation. This can be called while
+// Reconcile is running. When concurrency is reduced, workers
+// finish any in-flight reconciliation before exiting.
+func (r *Reconciler) SetInactiveConcurrency(concurrency int) {
+	r.workerMutex.Lock()
+	defer r.workerMutex.Unlock()
+
+	r.spawnWorkers(r.inactivePool.resize(concurrency), r.reconcileInactiveAccounts)
+}
+
+// CurrentActiveConcurrency returns the number of goroutines
+// currently performing active reconciliation. After reducing
+// concurrency, this may be larger than the requested value
+// until in-flight reconciliations complete.
+func (r *Reconciler) CurrentActiveConcurrency() int {
+	return r.activePool.size()
+}
+
+// CurrentInactiveConcurrency returns the number of goroutines
+// currently performing inactive reconciliation. After reducing
+// concurrency, this may be larger than the requested value
+// until in-flight reconciliations complete.
+func (r *Reconciler) CurrentInactiveConcurrency() int {
+	return r.inactivePool.size()
+}
+
 // Reconcile starts the active and inactive Reconciler goroutines.
 // If any goroutine errors, the function will return an error.
 func (r *Reconciler) Reconcile(ctx context.Context) error {
@@ -983,18 +1545,25 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		return r.queueWorker(ctx)
 	})
 
-	for j := 0; j < r.ActiveConcurrency; j++ {
+	if r.queueStorage != nil {
 		g.Go(func() error {
-			return r.reconcileActiveAccounts(ctx)
+			return r.restoreQueue(ctx)
 		})
 	}
 
-	for j := 0; j < r.InactiveConcurrency; j++ {
+	if r.coinHelper != nil {
 		g.Go(func() error {
-			return r.reconcileInactiveAccounts(ctx)
+			return r.reconcileCoinAccounts(ctx)
 		})
 	}
 
+	r.workerMutex.Lock()
+	r.workerGroup = g
+	r.workerCtx = ctx
+	r.spawnWorkers(r.activePool.start(), r.reconcileActiveAccounts)
+	r.spawnWorkers(r.inactivePool.start(), r.reconcileInactiveAccounts)
+	r.workerMutex.Unlock()
+
 	if err := g.Wait(); err != nil {
 		return err
 	}
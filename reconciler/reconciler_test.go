@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"testing"
 	"time"
 
@@ -27,7 +28,9 @@ import (
 	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
 	mockDatabase "github.com/coinbase/rosetta-sdk-go/mocks/storage/database"
 	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
 	storageErrors "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/storage/modules"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
@@ -100,6 +103,21 @@ func TestNewReconciler(t *testing.T) {
 				return r
 			}(),
 		},
+		"with priority accounts": {
+			options: []Option{
+				WithPriorityAccounts([]*types.AccountCurrency{
+					accountCurrency,
+				}),
+			},
+			expected: func() *Reconciler {
+				r := New(nil, nil, nil)
+				r.priorityAccounts = map[string]struct{}{
+					types.Hash(accountCurrency): {},
+				}
+
+				return r
+			}(),
+		},
 		"without lookupBalanceByBlock": {
 			options: []Option{},
 			expected: func() *Reconciler {
@@ -122,6 +140,7 @@ func TestNewReconciler(t *testing.T) {
 			assert.Equal(t, test.expected.ActiveConcurrency, result.ActiveConcurrency)
 			assert.Equal(t, test.expected.lookupBalanceByBlock, result.lookupBalanceByBlock)
 			assert.Equal(t, cap(test.expected.changeQueue), cap(result.changeQueue))
+			assert.Equal(t, test.expected.priorityAccounts, result.priorityAccounts)
 		})
 	}
 }
@@ -3441,3 +3460,898 @@ func TestReconcile_SuccessOnlyInactiveOverride(t *testing.T) {
 	err := r.Reconcile(ctx)
 	assert.Contains(t, context.Canceled.Error(), err.Error())
 }
+
+func TestReconcile_PersistedQueue(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		headBlock = &types.BlockIdentifier{
+			Hash:  "block 2",
+			Index: 2,
+		}
+		accountCurrencies = []*types.AccountCurrency{}
+		changes           = []*parser.BalanceChange{}
+	)
+
+	for i := 0; i < 5; i++ {
+		accountCurrency := &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: fmt.Sprintf("addr %d", i),
+			},
+			Currency: &types.Currency{
+				Symbol:   "BTC",
+				Decimals: 8,
+			},
+		}
+		accountCurrencies = append(accountCurrencies, accountCurrency)
+		changes = append(changes, &parser.BalanceChange{
+			Account:    accountCurrency.Account,
+			Currency:   accountCurrency.Currency,
+			Block:      block,
+			Difference: "100",
+		})
+	}
+
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		newDir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	defer db.Close(ctx)
+
+	queueStorage := modules.NewReconcilerQueueStorage(db)
+
+	t.Run("enqueue and exit", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		mockHandler := &mocks.Handler{}
+		r := New(
+			mockHelper,
+			mockHandler,
+			nil,
+			WithActiveConcurrency(0),
+			WithInactiveConcurrency(0),
+			WithQueueStorage(queueStorage),
+		)
+		ctx, cancel := context.WithCancel(ctx)
+
+		go func() {
+			err := r.Reconcile(ctx)
+			assert.Contains(t, context.Canceled.Error(), err.Error())
+		}()
+
+		assert.NoError(t, r.QueueChanges(ctx, block, changes))
+		for r.QueueSize() < len(changes) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		cancel()
+
+		// Nothing was reconciled before exit, so all
+		// changes should remain persisted.
+		restored, err := queueStorage.Restore(context.Background())
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, changes, restored)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("restore and reconcile", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		mockHandler := &mocks.Handler{}
+		r := New(
+			mockHelper,
+			mockHandler,
+			nil,
+			WithActiveConcurrency(1),
+			WithInactiveConcurrency(0),
+			WithQueueStorage(queueStorage),
+		)
+		ctx, cancel := context.WithCancel(ctx)
+
+		mtxn := &mockDatabase.Transaction{}
+		mtxn.On("Discard", mock.Anything).Times(len(changes))
+		mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn).Times(len(changes))
+		for _, accountCurrency := range accountCurrencies {
+			mockReconcilerCalls(
+				mockHelper,
+				mockHandler,
+				mtxn,
+				false,
+				accountCurrency,
+				"100",
+				"100",
+				headBlock,
+				block,
+				true,
+				ActiveReconciliation,
+				nil,
+				false,
+				false,
+			)
+		}
+
+		go func() {
+			err := r.Reconcile(ctx)
+			assert.Contains(t, context.Canceled.Error(), err.Error())
+		}()
+
+		time.Sleep(1 * time.Second)
+		cancel()
+
+		restored, err := queueStorage.Restore(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, restored, 0)
+
+		assert.Equal(t, block.Index, r.LastIndexReconciled())
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+		mtxn.AssertExpectations(t)
+	})
+}
+
+func TestReconcile_PriorityAccounts(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		block2 = &types.BlockIdentifier{
+			Hash:  "block 2",
+			Index: 2,
+		}
+		currency = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 8,
+		}
+		priorityAccount = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "priority",
+			},
+			Currency: currency,
+		}
+		runtimePriorityAccount = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "runtime priority",
+			},
+			Currency: currency,
+		}
+		backlog = 1000
+	)
+
+	ctx := context.Background()
+	r := New(
+		&mocks.Helper{},
+		&mocks.Handler{},
+		nil,
+		WithActiveConcurrency(0),
+		WithInactiveConcurrency(0),
+		WithPriorityAccounts([]*types.AccountCurrency{priorityAccount}),
+	)
+
+	changes := []*parser.BalanceChange{}
+	for i := 0; i < backlog; i++ {
+		changes = append(changes, &parser.BalanceChange{
+			Account: &types.AccountIdentifier{
+				Address: fmt.Sprintf("addr %d", i),
+			},
+			Currency:   currency,
+			Block:      block,
+			Difference: "100",
+		})
+
+		// Add priority change in the middle of the backlog
+		if i == backlog/2 {
+			changes = append(changes, &parser.BalanceChange{
+				Account:    priorityAccount.Account,
+				Currency:   priorityAccount.Currency,
+				Block:      block,
+				Difference: "100",
+			})
+		}
+	}
+
+	assert.NoError(t, r.queueChanges(ctx, block, changes))
+	assert.Equal(t, backlog+1, r.QueueSize())
+	assert.Equal(t, 1, r.PriorityQueueSize())
+
+	// Mark an account as priority at runtime
+	r.AddPriorityAccount(runtimePriorityAccount)
+	assert.NoError(t, r.queueChanges(ctx, block2, []*parser.BalanceChange{
+		{
+			Account:    runtimePriorityAccount.Account,
+			Currency:   runtimePriorityAccount.Currency,
+			Block:      block2,
+			Difference: "100",
+		},
+	}))
+	assert.Equal(t, backlog+2, r.QueueSize())
+	assert.Equal(t, 2, r.PriorityQueueSize())
+
+	// Priority changes should be dequeued before
+	// any change in the backlog.
+	change, err := r.nextActiveChange(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, priorityAccount.Account, change.Account)
+
+	change, err = r.nextActiveChange(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, runtimePriorityAccount.Account, change.Account)
+
+	assert.Equal(t, backlog, r.QueueSize())
+	assert.Equal(t, 0, r.PriorityQueueSize())
+
+	change, err = r.nextActiveChange(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "addr 0", change.Account.Address)
+
+	// Dequeue should exit when the context is canceled
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	for i := 0; i < backlog-1; i++ {
+		<-r.changeQueue
+	}
+	_, err = r.nextActiveChange(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+// failureHandler is a Handler that also
+// implements FailureHandler.
+type failureHandler struct {
+	*mocks.Handler
+
+	failures []*ReconciliationFailure
+}
+
+func (h *failureHandler) ReconciliationFailedWithDetails(
+	ctx context.Context,
+	failure *ReconciliationFailure,
+) error {
+	h.failures = append(h.failures, failure)
+	return errors.New("reconciliation failed")
+}
+
+func TestReconcile_FailureDebugging(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		block2 = &types.BlockIdentifier{
+			Hash:  "block 2",
+			Index: 2,
+		}
+		accountCurrency = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "addr 1",
+			},
+			Currency: &types.Currency{
+				Symbol:   "BTC",
+				Decimals: 8,
+			},
+		}
+		operations = []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{Index: 0},
+				Type:                "transfer",
+				Account:             accountCurrency.Account,
+				Amount: &types.Amount{
+					Value:    "-10",
+					Currency: accountCurrency.Currency,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{Index: 1},
+				Type:                "fee",
+				Account:             accountCurrency.Account,
+				Amount: &types.Amount{
+					Value:    "-1",
+					Currency: accountCurrency.Currency,
+				},
+			},
+		}
+	)
+
+	mockHelper := &mocks.Helper{}
+	mockDebugHelper := &mocks.DebugHelper{}
+	handler := &failureHandler{Handler: &mocks.Handler{}}
+	r := New(
+		mockHelper,
+		handler,
+		parser.New(nil, nil, nil),
+		WithActiveConcurrency(1),
+		WithInactiveConcurrency(0),
+		WithLookupBalanceByBlock(),
+		WithFailureDebugging(mockDebugHelper, 1),
+	)
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+
+	// The successful reconciliation should not
+	// trigger any operation lookups.
+	mtxn := &mockDatabase.Transaction{}
+	mtxn.On("Discard", mock.Anything).Once()
+	mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn).Once()
+	mockReconcilerCalls(
+		mockHelper,
+		handler.Handler,
+		mtxn,
+		true,
+		accountCurrency,
+		"100",
+		"100",
+		block,
+		block,
+		true,
+		ActiveReconciliation,
+		nil,
+		false,
+		false,
+	)
+
+	mtxn2 := &mockDatabase.Transaction{}
+	mtxn2.On("Discard", mock.Anything).Once()
+	mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn2).Once()
+	mockHelper.On("CurrentBlock", mock.Anything, mtxn2).Return(block2, nil).Once()
+	mockHelper.On(
+		"LiveBalance",
+		mock.Anything,
+		accountCurrency.Account,
+		accountCurrency.Currency,
+		block2.Index,
+	).Return(
+		&types.Amount{Value: "90", Currency: accountCurrency.Currency},
+		block2,
+		nil,
+	).Once()
+	mockHelper.On("CanonicalBlock", mock.Anything, mtxn2, block2).Return(true, nil).Once()
+	mockHelper.On(
+		"ComputedBalance",
+		mock.Anything,
+		mtxn2,
+		accountCurrency.Account,
+		accountCurrency.Currency,
+		block2.Index,
+	).Return(
+		&types.Amount{Value: "89", Currency: accountCurrency.Currency},
+		nil,
+	).Once()
+	mockDebugHelper.On(
+		"BalanceOperations",
+		mock.Anything,
+		accountCurrency.Account,
+		accountCurrency.Currency,
+		block,
+		block2,
+		2,
+	).Return(
+		operations,
+		nil,
+	).Once()
+
+	go func() {
+		err := r.Reconcile(ctx)
+		assert.Error(t, err)
+		assert.Contains(t, "reconciliation failed", err.Error())
+	}()
+
+	err := r.QueueChanges(ctx, block, []*parser.BalanceChange{
+		{
+			Account:  accountCurrency.Account,
+			Currency: accountCurrency.Currency,
+			Block:    block,
+		},
+	})
+	assert.NoError(t, err)
+	err = r.QueueChanges(ctx, block2, []*parser.BalanceChange{
+		{
+			Account:  accountCurrency.Account,
+			Currency: accountCurrency.Currency,
+			Block:    block2,
+		},
+	})
+	assert.NoError(t, err)
+
+	time.Sleep(1 * time.Second)
+	cancel()
+
+	assert.Equal(t, []*ReconciliationFailure{
+		{
+			ReconciliationType: ActiveReconciliation,
+			Account:            accountCurrency.Account,
+			Currency:           accountCurrency.Currency,
+			ComputedBalance:    "89",
+			LiveBalance:        "90",
+			Block:              block2,
+			LastReconciled:     block,
+			Operations:         operations[:1],
+			Truncated:          true,
+		},
+	}, handler.failures)
+	mockHelper.AssertExpectations(t)
+	mockDebugHelper.AssertExpectations(t)
+	handler.Handler.AssertExpectations(t)
+	mtxn.AssertExpectations(t)
+	mtxn2.AssertExpectations(t)
+}
+
+func TestReconcile_SetConcurrency(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		changes = []*parser.BalanceChange{}
+		count   = 12
+	)
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	r := New(
+		mockHelper,
+		mockHandler,
+		nil,
+		WithActiveConcurrency(4),
+		WithInactiveConcurrency(0),
+		WithLookupBalanceByBlock(),
+	)
+	assert.Equal(t, 4, r.CurrentActiveConcurrency())
+	assert.Equal(t, 0, r.CurrentInactiveConcurrency())
+
+	// Each reconciliation takes some time so that
+	// there are always comparisons in-flight when
+	// concurrency is changed.
+	mtxn := &mockDatabase.Transaction{}
+	mtxn.On("Discard", mock.Anything).Times(count)
+	mockHelper.On(
+		"DatabaseTransaction",
+		mock.Anything,
+	).Return(
+		mtxn,
+	).After(
+		100 * time.Millisecond,
+	).Times(count)
+	for i := 0; i < count; i++ {
+		accountCurrency := &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: fmt.Sprintf("addr %d", i),
+			},
+			Currency: &types.Currency{
+				Symbol:   "BTC",
+				Decimals: 8,
+			},
+		}
+		changes = append(changes, &parser.BalanceChange{
+			Account:  accountCurrency.Account,
+			Currency: accountCurrency.Currency,
+			Block:    block,
+		})
+
+		mockReconcilerCalls(
+			mockHelper,
+			mockHandler,
+			mtxn,
+			true,
+			accountCurrency,
+			"100",
+			"100",
+			block,
+			block,
+			true,
+			ActiveReconciliation,
+			nil,
+			false,
+			false,
+		)
+	}
+
+	baseline := runtime.NumGoroutine()
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		err := r.Reconcile(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+		close(done)
+	}()
+
+	assert.NoError(t, r.QueueChanges(ctx, block, changes))
+	time.Sleep(50 * time.Millisecond)
+
+	// Shrink concurrency while reconciliations are in-flight
+	r.SetActiveConcurrency(1)
+	assert.Eventually(t, func() bool {
+		return r.CurrentActiveConcurrency() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Greater(t, r.QueueSize(), 0)
+
+	// Grow concurrency to drain the remaining backlog
+	r.SetActiveConcurrency(2)
+	assert.Equal(t, 2, r.CurrentActiveConcurrency())
+
+	assert.Eventually(t, func() bool {
+		return r.QueueSize() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+
+	cancel()
+	<-done
+
+	// All workers should exit when Reconcile returns (we don't
+	// use assert.Eventually because it evaluates the condition
+	// in another goroutine)
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), baseline)
+
+	assert.Equal(t, block.Index, r.LastIndexReconciled())
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mtxn.AssertExpectations(t)
+}
+
+func TestReconcile_SkippedCurrencies(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		skippedCurrency = &types.Currency{
+			Symbol:   "REBASE",
+			Decimals: 18,
+		}
+		accountCurrency = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "addr 1",
+			},
+			Currency: skippedCurrency,
+		}
+		interestingAccount = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "addr 2",
+			},
+			Currency: skippedCurrency,
+		}
+		skippedAccount = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "addr 3",
+			},
+			Currency: &types.Currency{
+				Symbol:   "BTC",
+				Decimals: 8,
+			},
+		}
+	)
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	r := New(
+		mockHelper,
+		mockHandler,
+		nil,
+		WithActiveConcurrency(1),
+		WithInactiveConcurrency(1),
+		WithSeenAccounts([]*types.AccountCurrency{accountCurrency}),
+		WithInterestingAccounts([]*types.AccountCurrency{interestingAccount}),
+		WithShouldReconcile(func(account *types.AccountIdentifier, currency *types.Currency) bool {
+			return account.Address != skippedAccount.Account.Address
+		}),
+		WithSkippedCurrencies([]*types.Currency{skippedCurrency}),
+	)
+	assert.Len(t, r.inactiveQueue, 0)
+	assert.Equal(t, int64(1), r.Stats().Ignored)
+
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		err := r.Reconcile(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+	}()
+
+	err := r.QueueChanges(ctx, block, []*parser.BalanceChange{
+		{
+			Account:    accountCurrency.Account,
+			Currency:   accountCurrency.Currency,
+			Block:      block,
+			Difference: "100",
+		},
+		{
+			Account:    skippedAccount.Account,
+			Currency:   skippedAccount.Currency,
+			Block:      block,
+			Difference: "100",
+		},
+	})
+	assert.NoError(t, err)
+
+	time.Sleep(1 * time.Second)
+	cancel()
+
+	assert.Equal(t, &Stats{
+		ActiveConcurrency:   1,
+		InactiveConcurrency: 1,
+		Ignored:             4,
+	}, r.Stats())
+	assert.Len(t, r.seenAccounts, 0)
+	mockHelper.AssertNotCalled(t, "DatabaseTransaction", mock.Anything)
+	mockHelper.AssertNotCalled(
+		t,
+		"LiveBalance",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestReconcile_ChangeDeduplication(t *testing.T) {
+	var (
+		block1 = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		block1a = &types.BlockIdentifier{
+			Hash:  "block 1a",
+			Index: 1,
+		}
+		block2 = &types.BlockIdentifier{
+			Hash:  "block 2",
+			Index: 2,
+		}
+		block3 = &types.BlockIdentifier{
+			Hash:  "block 3",
+			Index: 3,
+		}
+		currency = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 8,
+		}
+		account1  = &types.AccountIdentifier{Address: "addr 1"}
+		account2  = &types.AccountIdentifier{Address: "addr 2"}
+		newChange = func(
+			account *types.AccountIdentifier,
+			block *types.BlockIdentifier,
+			difference string,
+		) *parser.BalanceChange {
+			return &parser.BalanceChange{
+				Account:    account,
+				Currency:   currency,
+				Block:      block,
+				Difference: difference,
+			}
+		}
+	)
+
+	ctx := context.Background()
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockQueueStorage := &mocks.QueueStorage{}
+	r := New(
+		mockHelper,
+		mockHandler,
+		parser.New(nil, nil, nil),
+		WithActiveConcurrency(1),
+		WithInactiveConcurrency(0),
+		WithQueueStorage(mockQueueStorage),
+		WithChangeDeduplication(),
+	)
+
+	t.Run("first changes are enqueued", func(t *testing.T) {
+		mockQueueStorage.On("Enqueue", ctx, newChange(account1, block1, "100")).Return(nil).Once()
+		mockQueueStorage.On("Enqueue", ctx, newChange(account2, block1, "50")).Return(nil).Once()
+		assert.NoError(t, r.queueChanges(ctx, block1, []*parser.BalanceChange{
+			newChange(account1, block1, "100"),
+			newChange(account2, block1, "50"),
+		}))
+		assert.Equal(t, 2, r.QueueSize())
+		assert.Equal(t, int64(0), r.Stats().Coalesced)
+	})
+
+	t.Run("newer change is merged", func(t *testing.T) {
+		mockQueueStorage.On("Enqueue", ctx, newChange(account1, block2, "-10")).Return(nil).Once()
+		mockQueueStorage.On("Dequeue", ctx, newChange(account1, block1, "100")).Return(nil).Once()
+		assert.NoError(t, r.queueChanges(ctx, block2, []*parser.BalanceChange{
+			newChange(account1, block2, "-10"),
+		}))
+		assert.Equal(t, 2, r.QueueSize())
+		assert.Equal(t, int64(1), r.Stats().Coalesced)
+
+		key := types.Hash(&types.AccountCurrency{Account: account1, Currency: currency})
+		m := r.queueMap.Lock(key, false)
+		bst := m[key].(*utils.BST)
+		assert.Nil(t, bst.Get(block1.Index))
+		assert.Equal(t, 1, bst.Get(block2.Index).Value)
+		r.queueMap.Unlock(key)
+	})
+
+	t.Run("change at same index is not merged", func(t *testing.T) {
+		mockQueueStorage.On("Enqueue", ctx, newChange(account1, block1a, "5")).Return(nil).Once()
+		assert.NoError(t, r.queueChanges(ctx, block1a, []*parser.BalanceChange{
+			newChange(account1, block1a, "5"),
+		}))
+		assert.Equal(t, 3, r.QueueSize())
+		assert.Equal(t, int64(1), r.Stats().Coalesced)
+	})
+
+	t.Run("claimed change is not merged", func(t *testing.T) {
+		change, err := r.nextActiveChange(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, newChange(account1, block2, "-10"), change)
+
+		mockQueueStorage.On("Enqueue", ctx, newChange(account1, block3, "1")).Return(nil).Once()
+		assert.NoError(t, r.queueChanges(ctx, block3, []*parser.BalanceChange{
+			newChange(account1, block3, "1"),
+		}))
+		assert.Equal(t, 3, r.QueueSize())
+		assert.Equal(t, int64(1), r.Stats().Coalesced)
+
+		// The claimed change is not modified
+		assert.Equal(t, block2, change.Block)
+	})
+
+	t.Run("queue order is preserved", func(t *testing.T) {
+		for _, expected := range []*parser.BalanceChange{
+			newChange(account2, block1, "50"),
+			newChange(account1, block1a, "5"),
+			newChange(account1, block3, "1"),
+		} {
+			change, err := r.nextActiveChange(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, change)
+		}
+	})
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockQueueStorage.AssertExpectations(t)
+}
+
+func TestBestLiveBalance_Retries(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		account = &types.AccountIdentifier{
+			Address: "addr 1",
+		}
+		currency = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 8,
+		}
+		amount = &types.Amount{
+			Value:    "100",
+			Currency: currency,
+		}
+		transientErr = fmt.Errorf("%w: 503 service unavailable", ErrLiveBalanceTransient)
+	)
+
+	ctx := context.Background()
+
+	t.Run("fails twice then succeeds", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		r := New(
+			mockHelper,
+			&mocks.Handler{},
+			nil,
+			WithLookupBalanceByBlock(),
+			WithLiveBalanceRetries(2, 1*time.Millisecond),
+		)
+
+		mockHelper.On(
+			"LiveBalance",
+			ctx,
+			account,
+			currency,
+			block.Index,
+		).Return(nil, nil, transientErr).Twice()
+		mockHelper.On(
+			"LiveBalance",
+			ctx,
+			account,
+			currency,
+			block.Index,
+		).Return(amount, block, nil).Once()
+
+		liveAmount, liveBlock, err := r.bestLiveBalance(ctx, account, currency, block.Index)
+		assert.NoError(t, err)
+		assert.Equal(t, amount, liveAmount)
+		assert.Equal(t, block, liveBlock)
+		mockHelper.AssertExpectations(t)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		r := New(
+			mockHelper,
+			&mocks.Handler{},
+			nil,
+			WithLookupBalanceByBlock(),
+			WithLiveBalanceRetries(2, 1*time.Millisecond),
+		)
+
+		mockHelper.On(
+			"LiveBalance",
+			ctx,
+			account,
+			currency,
+			block.Index,
+		).Return(nil, nil, transientErr).Times(3)
+
+		liveAmount, liveBlock, err := r.bestLiveBalance(ctx, account, currency, block.Index)
+		assert.True(t, errors.Is(err, ErrLiveBalanceTransient))
+		assert.True(t, errors.Is(err, utils.ErrRetriesExhausted))
+		assert.Nil(t, liveAmount)
+		assert.Nil(t, liveBlock)
+		mockHelper.AssertExpectations(t)
+	})
+
+	t.Run("deterministic error is not retried", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		r := New(
+			mockHelper,
+			&mocks.Handler{},
+			nil,
+			WithLookupBalanceByBlock(),
+			WithLiveBalanceRetries(2, 1*time.Millisecond),
+		)
+
+		mockHelper.On(
+			"LiveBalance",
+			ctx,
+			account,
+			currency,
+			block.Index,
+		).Return(nil, nil, errors.New("account not found")).Once()
+
+		liveAmount, liveBlock, err := r.bestLiveBalance(ctx, account, currency, block.Index)
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrLiveBalanceTransient))
+		assert.False(t, errors.Is(err, utils.ErrRetriesExhausted))
+		assert.Nil(t, liveAmount)
+		assert.Nil(t, liveBlock)
+		mockHelper.AssertExpectations(t)
+	})
+
+	t.Run("context canceled during backoff", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		r := New(
+			mockHelper,
+			&mocks.Handler{},
+			nil,
+			WithLookupBalanceByBlock(),
+			WithLiveBalanceRetries(2, 1*time.Hour),
+		)
+
+		ctx, cancel := context.WithCancel(ctx)
+		mockHelper.On(
+			"LiveBalance",
+			ctx,
+			account,
+			currency,
+			block.Index,
+		).Return(nil, nil, transientErr).Run(
+			func(args mock.Arguments) {
+				cancel()
+			},
+		).Once()
+
+		liveAmount, liveBlock, err := r.bestLiveBalance(ctx, account, currency, block.Index)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Nil(t, liveAmount)
+		assert.Nil(t, liveBlock)
+		mockHelper.AssertExpectations(t)
+	})
+}
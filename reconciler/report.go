@@ -0,0 +1,265 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// reportRecord is the outcome of all reconciliations
+// of an *types.AccountCurrency since the Reconciler
+// was created. Only the most recent reportFailureLimit
+// failures are kept.
+type reportRecord struct {
+	accountCurrency *types.AccountCurrency
+	lastReconciled  *types.BlockIdentifier
+	comparisons     int64
+	exemptions      int64
+	failureCount    int64
+	failures        []*ReportFailure
+	skips           map[string]int64
+
+	// exportID is the ID of the last export that
+	// included this record. It is used to find records
+	// that were not returned by the ReportHelper without
+	// keeping a set of all exported keys.
+	exportID uint64
+}
+
+// updateReportRecord invokes update with the reportRecord
+// of an account and currency (if reporting is enabled).
+func (r *Reconciler) updateReportRecord(
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	update func(record *reportRecord),
+) {
+	if r.reportHelper == nil {
+		return
+	}
+
+	accountCurrency := &types.AccountCurrency{
+		Account:  account,
+		Currency: currency,
+	}
+	key := types.Hash(accountCurrency)
+
+	r.reportMutex.Lock()
+	defer r.reportMutex.Unlock()
+
+	record, ok := r.reportRecords[key]
+	if !ok {
+		record = &reportRecord{
+			accountCurrency: accountCurrency,
+			skips:           map[string]int64{},
+		}
+		r.reportRecords[key] = record
+	}
+
+	update(record)
+}
+
+// recordSuccess records a successful reconciliation
+// at a block.
+func (r *Reconciler) recordSuccess(
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	block *types.BlockIdentifier,
+) {
+	r.updateReportRecord(account, currency, func(record *reportRecord) {
+		record.comparisons++
+		if record.lastReconciled == nil || record.lastReconciled.Index < block.Index {
+			record.lastReconciled = block
+		}
+	})
+}
+
+// recordExemption records an exempt reconciliation.
+func (r *Reconciler) recordExemption(
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) {
+	r.updateReportRecord(account, currency, func(record *reportRecord) {
+		record.comparisons++
+		record.exemptions++
+	})
+}
+
+// recordFailure records a failed reconciliation.
+func (r *Reconciler) recordFailure(
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	failure *ReportFailure,
+) {
+	r.updateReportRecord(account, currency, func(record *reportRecord) {
+		record.comparisons++
+		record.failureCount++
+		record.failures = append(record.failures, failure)
+		if len(record.failures) > reportFailureLimit {
+			record.failures = record.failures[len(record.failures)-reportFailureLimit:]
+		}
+	})
+}
+
+// reconciliationSkipped records a skipped reconciliation
+// and invokes the ReconciliationSkipped handler.
+func (r *Reconciler) reconciliationSkipped(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	cause string,
+) error {
+	r.updateReportRecord(account, currency, func(record *reportRecord) {
+		record.skips[cause]++
+	})
+
+	return r.handler.ReconciliationSkipped(
+		ctx,
+		reconciliationType,
+		account,
+		currency,
+		cause,
+	)
+}
+
+// exportRecord returns the *ReportRecord of an account and
+// currency with the provided last reconciled index (-1 if
+// never reconciled) and marks the recorded outcomes (if any)
+// as included in export exportID. If accountCurrency is nil,
+// it is populated from the recorded outcomes.
+func (r *Reconciler) exportRecord(
+	exportID uint64,
+	key string,
+	accountCurrency *types.AccountCurrency,
+	lastReconciled int64,
+) *ReportRecord {
+	report := &ReportRecord{
+		Failures: []*ReportFailure{},
+		Skips:    map[string]int64{},
+	}
+	if accountCurrency != nil {
+		report.Account = accountCurrency.Account
+		report.Currency = accountCurrency.Currency
+	}
+	if lastReconciled >= 0 {
+		report.LastReconciled = &types.PartialBlockIdentifier{Index: &lastReconciled}
+	}
+
+	r.reportMutex.Lock()
+	defer r.reportMutex.Unlock()
+
+	record, ok := r.reportRecords[key]
+	if !ok {
+		return report
+	}
+
+	record.exportID = exportID
+	report.Account = record.accountCurrency.Account
+	report.Currency = record.accountCurrency.Currency
+	report.Comparisons = record.comparisons
+	report.Exemptions = record.exemptions
+	report.FailureCount = record.failureCount
+	report.Failures = append(report.Failures, record.failures...)
+	for cause, count := range record.skips {
+		report.Skips[cause] = count
+	}
+
+	// We prefer the recorded block (which
+	// includes a hash) if it is not stale.
+	if record.lastReconciled != nil && record.lastReconciled.Index >= lastReconciled {
+		report.LastReconciled = types.ConstructPartialBlockIdentifier(record.lastReconciled)
+	}
+
+	return report
+}
+
+// unexportedRecords returns the sorted keys of all
+// records not included in export exportID.
+func (r *Reconciler) unexportedRecords(exportID uint64) []string {
+	r.reportMutex.Lock()
+	defer r.reportMutex.Unlock()
+
+	remaining := []string{}
+	for key, record := range r.reportRecords {
+		if record.exportID != exportID {
+			remaining = append(remaining, key)
+		}
+	}
+
+	// Sort remaining records so that
+	// the report is deterministic.
+	sort.Strings(remaining)
+	return remaining
+}
+
+// ExportReport writes a *ReportRecord (as newline-delimited
+// JSON) for each account returned by the ReportHelper, followed
+// by a *ReportRecord for each account with a recorded outcome
+// that the ReportHelper did not return (ex: an interesting
+// account that was never seen). Records are written as accounts
+// are returned by the ReportHelper, so the report is never
+// buffered in memory.
+//
+// Comparisons, exemptions, failures, and skips only include
+// reconciliations performed since the Reconciler was created.
+// Only the most recent failures of each account are included
+// (FailureCount is the total number of failures).
+func (r *Reconciler) ExportReport(ctx context.Context, w io.Writer) error {
+	if r.reportHelper == nil {
+		return ErrReportNotEnabled
+	}
+
+	r.reportMutex.Lock()
+	r.reportExports++
+	exportID := r.reportExports
+	r.reportMutex.Unlock()
+
+	encoder := json.NewEncoder(w)
+	err := r.reportHelper.AccountReconciliations(
+		ctx,
+		func(accountCurrency *types.AccountCurrency, lastReconciled int64) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			record := r.exportRecord(
+				exportID,
+				types.Hash(accountCurrency),
+				accountCurrency,
+				lastReconciled,
+			)
+
+			return encoder.Encode(record)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrExportReportFailed, err)
+	}
+
+	for _, key := range r.unexportedRecords(exportID) {
+		record := r.exportRecord(exportID, key, nil, -1)
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("%w: %v", ErrExportReportFailed, err)
+		}
+	}
+
+	return nil
+}
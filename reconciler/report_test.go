@@ -0,0 +1,399 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+	mockDatabase "github.com/coinbase/rosetta-sdk-go/mocks/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	storageErrors "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	reportCurrency = &types.Currency{
+		Symbol:   "BTC",
+		Decimals: 8,
+	}
+	reportExemptCurrency = &types.Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+	}
+
+	// reportSucceeded is reconciled successfully twice.
+	reportSucceeded = &types.AccountCurrency{
+		Account:  &types.AccountIdentifier{Address: "succeeded"},
+		Currency: reportCurrency,
+	}
+
+	// reportFailed is reconciled successfully
+	// and then fails reconciliation.
+	reportFailed = &types.AccountCurrency{
+		Account:  &types.AccountIdentifier{Address: "failed"},
+		Currency: reportCurrency,
+	}
+
+	// reportExempt has an exempt mismatch.
+	reportExempt = &types.AccountCurrency{
+		Account:  &types.AccountIdentifier{Address: "exempt"},
+		Currency: reportExemptCurrency,
+	}
+
+	// reportSkipped is skipped because its block is gone.
+	reportSkipped = &types.AccountCurrency{
+		Account:  &types.AccountIdentifier{Address: "skipped"},
+		Currency: reportCurrency,
+	}
+
+	// reportUnchecked was reconciled by a previous
+	// Reconciler but not by this one.
+	reportUnchecked = &types.AccountCurrency{
+		Account:  &types.AccountIdentifier{Address: "unchecked"},
+		Currency: reportCurrency,
+	}
+
+	// reportMissing is an interesting account that is
+	// not returned by the ReportHelper.
+	reportMissing = &types.AccountCurrency{
+		Account:  &types.AccountIdentifier{Address: "missing"},
+		Currency: reportCurrency,
+	}
+
+	reportBlock1 = &types.BlockIdentifier{Index: 1, Hash: "block 1"}
+	reportBlock2 = &types.BlockIdentifier{Index: 2, Hash: "block 2"}
+)
+
+// reportFixture reconciles an account (using
+// accountReconciliation) with the provided computed balance
+// (or error) and live balance at block.
+func reportFixture(
+	ctx context.Context,
+	t *testing.T,
+	r *Reconciler,
+	mockHelper *mocks.Helper,
+	accountCurrency *types.AccountCurrency,
+	computed string,
+	computedErr error,
+	live string,
+	block *types.BlockIdentifier,
+) {
+	mtxn := &mockDatabase.Transaction{}
+	mtxn.On("Discard", ctx).Once()
+	mockHelper.On("DatabaseTransaction", ctx).Return(mtxn).Once()
+	mockHelper.On("CurrentBlock", ctx, mtxn).Return(reportBlock2, nil).Once()
+
+	if errors.Is(computedErr, ErrBlockGone) {
+		mockHelper.On("CanonicalBlock", ctx, mtxn, block).Return(false, nil).Once()
+	} else {
+		mockHelper.On("CanonicalBlock", ctx, mtxn, block).Return(true, nil).Once()
+
+		var computedAmount *types.Amount
+		if computedErr == nil {
+			computedAmount = &types.Amount{
+				Value:    computed,
+				Currency: accountCurrency.Currency,
+			}
+		}
+		mockHelper.On(
+			"ComputedBalance",
+			ctx,
+			mtxn,
+			accountCurrency.Account,
+			accountCurrency.Currency,
+			block.Index,
+		).Return(computedAmount, computedErr).Once()
+	}
+
+	err := r.accountReconciliation(
+		ctx,
+		accountCurrency.Account,
+		accountCurrency.Currency,
+		live,
+		block,
+		false,
+	)
+	assert.NoError(t, err)
+}
+
+func TestExportReport(t *testing.T) {
+	ctx := context.Background()
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockReportHelper := &mocks.ReportHelper{}
+	p := parser.New(nil, nil, []*types.BalanceExemption{
+		{
+			Currency:      reportExemptCurrency,
+			ExemptionType: types.BalanceDynamic,
+		},
+	})
+
+	t.Run("report not enabled", func(t *testing.T) {
+		r := New(mockHelper, mockHandler, p)
+		err := r.ExportReport(ctx, &bytes.Buffer{})
+		assert.True(t, errors.Is(err, ErrReportNotEnabled))
+	})
+
+	r := New(mockHelper, mockHandler, p, WithReport(mockReportHelper))
+
+	mockHandler.On(
+		"ReconciliationSucceeded",
+		ctx,
+		ActiveReconciliation,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(nil)
+	mockHandler.On(
+		"ReconciliationFailed",
+		ctx,
+		ActiveReconciliation,
+		reportFailed.Account,
+		reportFailed.Currency,
+		"100",
+		"90",
+		reportBlock2,
+	).Return(nil).Once()
+	mockHandler.On(
+		"ReconciliationExempt",
+		ctx,
+		ActiveReconciliation,
+		reportExempt.Account,
+		reportExempt.Currency,
+		"100",
+		"110",
+		reportBlock2,
+		mock.Anything,
+	).Return(nil).Once()
+	mockHandler.On(
+		"ReconciliationSkipped",
+		ctx,
+		ActiveReconciliation,
+		reportSkipped.Account,
+		reportSkipped.Currency,
+		BlockGone,
+	).Return(nil).Twice()
+	mockHandler.On(
+		"ReconciliationSkipped",
+		ctx,
+		ActiveReconciliation,
+		reportMissing.Account,
+		reportMissing.Currency,
+		AccountMissing,
+	).Return(nil).Once()
+
+	reportFixture(ctx, t, r, mockHelper, reportSucceeded, "100", nil, "100", reportBlock1)
+	reportFixture(ctx, t, r, mockHelper, reportSucceeded, "100", nil, "100", reportBlock2)
+	reportFixture(ctx, t, r, mockHelper, reportFailed, "100", nil, "100", reportBlock1)
+	reportFixture(ctx, t, r, mockHelper, reportFailed, "100", nil, "90", reportBlock2)
+	reportFixture(ctx, t, r, mockHelper, reportExempt, "100", nil, "110", reportBlock2)
+	reportFixture(ctx, t, r, mockHelper, reportSkipped, "", ErrBlockGone, "100", reportBlock2)
+	reportFixture(ctx, t, r, mockHelper, reportSkipped, "", ErrBlockGone, "100", reportBlock2)
+	reportFixture(
+		ctx,
+		t,
+		r,
+		mockHelper,
+		reportMissing,
+		"",
+		storageErrors.ErrAccountMissing,
+		"100",
+		reportBlock2,
+	)
+
+	// The ReportHelper returns the last reconciled index
+	// stored by the Handler (which may be from a previous
+	// run).
+	tracked := []struct {
+		accountCurrency *types.AccountCurrency
+		lastReconciled  int64
+	}{
+		{accountCurrency: reportSucceeded, lastReconciled: reportBlock2.Index},
+		{accountCurrency: reportFailed, lastReconciled: reportBlock1.Index},
+		{accountCurrency: reportExempt, lastReconciled: -1},
+		{accountCurrency: reportSkipped, lastReconciled: -1},
+		{accountCurrency: reportUnchecked, lastReconciled: 100},
+	}
+	mockReportHelper.On("AccountReconciliations", ctx, mock.Anything).Return(
+		func(
+			ctx context.Context,
+			worker func(*types.AccountCurrency, int64) error,
+		) error {
+			for _, account := range tracked {
+				if err := worker(account.accountCurrency, account.lastReconciled); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	)
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.ExportReport(ctx, &buf))
+
+	unchecked := int64(100)
+	expected := []*ReportRecord{
+		{
+			Account:        reportSucceeded.Account,
+			Currency:       reportSucceeded.Currency,
+			LastReconciled: types.ConstructPartialBlockIdentifier(reportBlock2),
+			Comparisons:    2,
+			Failures:       []*ReportFailure{},
+			Skips:          map[string]int64{},
+		},
+		{
+			Account:        reportFailed.Account,
+			Currency:       reportFailed.Currency,
+			LastReconciled: types.ConstructPartialBlockIdentifier(reportBlock1),
+			Comparisons:    2,
+			FailureCount:   1,
+			Failures: []*ReportFailure{
+				{
+					ReconciliationType: ActiveReconciliation,
+					ComputedBalance:    "100",
+					LiveBalance:        "90",
+					Block:              reportBlock2,
+				},
+			},
+			Skips: map[string]int64{},
+		},
+		{
+			Account:     reportExempt.Account,
+			Currency:    reportExempt.Currency,
+			Comparisons: 1,
+			Exemptions:  1,
+			Failures:    []*ReportFailure{},
+			Skips:       map[string]int64{},
+		},
+		{
+			Account:  reportSkipped.Account,
+			Currency: reportSkipped.Currency,
+			Failures: []*ReportFailure{},
+			Skips:    map[string]int64{BlockGone: 2},
+		},
+		{
+			Account:        reportUnchecked.Account,
+			Currency:       reportUnchecked.Currency,
+			LastReconciled: &types.PartialBlockIdentifier{Index: &unchecked},
+			Failures:       []*ReportFailure{},
+			Skips:          map[string]int64{},
+		},
+		{
+			Account:  reportMissing.Account,
+			Currency: reportMissing.Currency,
+			Failures: []*ReportFailure{},
+			Skips:    map[string]int64{AccountMissing: 1},
+		},
+	}
+
+	// Each line must be a single record that only
+	// contains fields in the schema.
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, len(expected))
+	for i, line := range lines {
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+
+		var record ReportRecord
+		assert.NoError(t, decoder.Decode(&record))
+		assert.Equal(t, expected[i], &record)
+
+		var fields map[string]interface{}
+		assert.NoError(t, json.Unmarshal(line, &fields))
+		for _, field := range []string{
+			"account_identifier",
+			"currency",
+			"comparisons",
+			"exemptions",
+			"failure_count",
+			"failures",
+			"skips",
+		} {
+			assert.Contains(t, fields, field)
+		}
+	}
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockReportHelper.AssertExpectations(t)
+
+	t.Run("helper error", func(t *testing.T) {
+		helperErr := errors.New("helper error")
+		failingHelper := &mocks.ReportHelper{}
+		failingHelper.On("AccountReconciliations", ctx, mock.Anything).Return(helperErr)
+
+		r := New(mockHelper, mockHandler, p, WithReport(failingHelper))
+		err := r.ExportReport(ctx, &bytes.Buffer{})
+		assert.True(t, errors.Is(err, ErrExportReportFailed))
+	})
+}
+
+func TestReportFailureLimit(t *testing.T) {
+	ctx := context.Background()
+	mockReportHelper := &mocks.ReportHelper{}
+	mockReportHelper.On("AccountReconciliations", ctx, mock.Anything).Return(nil)
+
+	r := New(
+		&mocks.Helper{},
+		&mocks.Handler{},
+		parser.New(nil, nil, nil),
+		WithReport(mockReportHelper),
+	)
+
+	failures := reportFailureLimit + 5
+	for i := 0; i < failures; i++ {
+		r.recordFailure(reportFailed.Account, reportFailed.Currency, &ReportFailure{
+			ReconciliationType: ActiveReconciliation,
+			ComputedBalance:    "100",
+			LiveBalance:        "90",
+			Block: &types.BlockIdentifier{
+				Index: int64(i),
+				Hash:  fmt.Sprintf("block %d", i),
+			},
+		})
+	}
+
+	// Records that are not returned by the ReportHelper
+	// must be included in every export.
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		assert.NoError(t, r.ExportReport(ctx, &buf))
+
+		var record ReportRecord
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, reportFailed.Account, record.Account)
+		assert.Equal(t, int64(failures), record.Comparisons)
+		assert.Equal(t, int64(failures), record.FailureCount)
+		assert.Len(t, record.Failures, reportFailureLimit)
+
+		// Only the most recent failures are kept.
+		for j, failure := range record.Failures {
+			assert.Equal(t, int64(failures-reportFailureLimit+j), failure.Block.Index)
+		}
+	}
+
+	mockReportHelper.AssertExpectations(t)
+}
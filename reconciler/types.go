@@ -19,6 +19,9 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
+	"github.com/coinbase/rosetta-sdk-go/fetcher"
 	"github.com/coinbase/rosetta-sdk-go/parser"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -129,6 +132,46 @@ const (
 	// processQueueBacklog is the maximum number of blocks
 	// we can get behind the syncing loop without blocking.
 	processQueueBacklog = 1000
+
+	// defaultPaceRefreshInterval is how often the number of
+	// tracked accounts is re-evaluated when pacing inactive
+	// reconciliation to meet a coverage target.
+	defaultPaceRefreshInterval = 1 * time.Minute
+
+	// defaultCoinReconciliationInterval is the minimum
+	// amount of time between coin reconciliations (of
+	// any account) if no interval is provided.
+	defaultCoinReconciliationInterval = 10 * time.Second
+
+	// defaultLiveBalanceRetries is the number of times
+	// a live balance lookup is retried if the Helper
+	// returns ErrLiveBalanceTransient.
+	defaultLiveBalanceRetries = 3
+
+	// defaultLiveBalanceBackoff is the time to wait
+	// before the first live balance lookup retry. This
+	// is doubled after each retry (up to maxLiveBalanceBackoff).
+	defaultLiveBalanceBackoff = 500 * time.Millisecond
+
+	// defaultLastReconciledLimit is the maximum number
+	// of *types.AccountCurrency for which the last
+	// successfully reconciled block is kept in memory
+	// when failure debugging is enabled.
+	defaultLastReconciledLimit = 100000
+
+	// reportFailureLimit is the maximum number of
+	// failures of each *types.AccountCurrency kept
+	// in memory for ExportReport.
+	reportFailureLimit = 10
+
+	// liveBalanceBackoffMultiplier is the factor the time
+	// to wait before retrying a live balance lookup is
+	// multiplied by after each retry.
+	liveBalanceBackoffMultiplier = 2
+
+	// maxLiveBalanceBackoff is the maximum time to
+	// wait between live balance lookup retries.
+	maxLiveBalanceBackoff = 10 * time.Second
 )
 
 // Helper functions are used by Reconciler to compare
@@ -163,6 +206,10 @@ type Helper interface {
 		index int64,
 	) (*types.Amount, error)
 
+	// LiveBalance should wrap any error that may not
+	// occur if the lookup is retried (network errors,
+	// 5xx responses, or a node that is not synced) with
+	// ErrLiveBalanceTransient.
 	LiveBalance(
 		ctx context.Context,
 		account *types.AccountIdentifier,
@@ -238,6 +285,278 @@ type Handler interface {
 	) error
 }
 
+// DebugHelper is used by Reconciler to look up the
+// operations that contributed to a failed reconciliation
+// when failure debugging is enabled.
+type DebugHelper interface {
+	// BalanceOperations returns up to limit balance-changing
+	// operations affecting an account and currency after
+	// startBlock (exclusive) and up to endBlock (inclusive).
+	// startBlock is nil if the account has not been
+	// reconciled successfully.
+	BalanceOperations(
+		ctx context.Context,
+		account *types.AccountIdentifier,
+		currency *types.Currency,
+		startBlock *types.BlockIdentifier,
+		endBlock *types.BlockIdentifier,
+		limit int,
+	) ([]*types.Operation, error)
+}
+
+// FailureHandler can optionally be implemented by a Handler
+// to receive a *ReconciliationFailure instead of a call to
+// ReconciliationFailed when failure debugging is enabled.
+type FailureHandler interface {
+	ReconciliationFailedWithDetails(
+		ctx context.Context,
+		failure *ReconciliationFailure,
+	) error
+}
+
+// ReconciliationFailure contains the details of a
+// failed reconciliation, including the operations
+// that contributed to the computed balance since
+// the account was last reconciled.
+type ReconciliationFailure struct {
+	ReconciliationType string                   `json:"reconciliation_type"`
+	Account            *types.AccountIdentifier `json:"account_identifier"`
+	Currency           *types.Currency          `json:"currency"`
+	ComputedBalance    string                   `json:"computed_balance"`
+	LiveBalance        string                   `json:"live_balance"`
+	Block              *types.BlockIdentifier   `json:"block_identifier"`
+
+	// LastReconciled is the last block where the
+	// account was reconciled successfully (if any).
+	LastReconciled *types.BlockIdentifier `json:"last_reconciled,omitempty"`
+
+	// Operations are the balance-changing operations
+	// after LastReconciled. If there were more than the
+	// configured limit, Truncated is set to true.
+	Operations []*types.Operation `json:"operations"`
+	Truncated  bool               `json:"truncated"`
+
+	// DebugError is populated if Operations
+	// could not be retrieved.
+	DebugError string `json:"debug_error,omitempty"`
+}
+
+// QueueStorage is used by Reconciler to persist
+// *parser.BalanceChange enqueued for active reconciliation
+// so that they are not lost when the process restarts.
+type QueueStorage interface {
+	// Enqueue is invoked when a *parser.BalanceChange
+	// is added to the active reconciliation queue.
+	Enqueue(ctx context.Context, change *parser.BalanceChange) error
+
+	// Dequeue is invoked when a *parser.BalanceChange
+	// has been reconciled (or skipped).
+	Dequeue(ctx context.Context, change *parser.BalanceChange) error
+
+	// Restore returns all *parser.BalanceChange that
+	// were enqueued but never dequeued.
+	Restore(ctx context.Context) ([]*parser.BalanceChange, error)
+}
+
+// AccountCounter is used by Reconciler to determine
+// the number of tracked accounts when pacing inactive
+// reconciliation to meet a coverage target.
+type AccountCounter interface {
+	AccountCount(ctx context.Context) (int64, error)
+}
+
+// ReportHelper is used by Reconciler to look up all
+// tracked accounts when exporting a report (see ExportReport).
+type ReportHelper interface {
+	// AccountReconciliations invokes worker with each tracked
+	// *types.AccountCurrency and the index of the last block
+	// where it was reconciled successfully (-1 if it has never
+	// been reconciled). Accounts should be streamed to worker
+	// instead of loaded into memory at once.
+	AccountReconciliations(
+		ctx context.Context,
+		worker func(accountCurrency *types.AccountCurrency, lastReconciled int64) error,
+	) error
+}
+
+// ReportRecord is the reconciliation status of an
+// account and currency written by ExportReport.
+type ReportRecord struct {
+	Account  *types.AccountIdentifier `json:"account_identifier"`
+	Currency *types.Currency          `json:"currency"`
+
+	// LastReconciled is the last block where the account
+	// was reconciled successfully (nil if it was never
+	// reconciled). The hash is only populated if the account
+	// was reconciled at this block since the Reconciler
+	// was created.
+	LastReconciled *types.PartialBlockIdentifier `json:"last_reconciled,omitempty"`
+
+	// Comparisons is the number of times the computed
+	// balance was compared with the live balance (including
+	// exempt and failed comparisons).
+	Comparisons int64 `json:"comparisons"`
+	Exemptions  int64 `json:"exemptions"`
+
+	// FailureCount is the number of failed reconciliations.
+	// Only the most recent failures are included in Failures
+	// (see reportFailureLimit).
+	FailureCount int64            `json:"failure_count"`
+	Failures     []*ReportFailure `json:"failures"`
+
+	// Skips is the number of skipped reconciliations
+	// for each cause (ex: HEAD_BEHIND).
+	Skips map[string]int64 `json:"skips"`
+}
+
+// ReportFailure is a failed reconciliation
+// included in a ReportRecord.
+type ReportFailure struct {
+	ReconciliationType string                 `json:"reconciliation_type"`
+	ComputedBalance    string                 `json:"computed_balance"`
+	LiveBalance        string                 `json:"live_balance"`
+	Block              *types.BlockIdentifier `json:"block_identifier"`
+}
+
+// CoinHelper is used by Reconciler to compare the
+// unspent coins of UTXO-tracked accounts computed from
+// synced blocks with the unspent coins reported by the node.
+type CoinHelper interface {
+	// ComputedCoins returns the unspent coins of an
+	// account and the block they were computed at (nil
+	// if no block has been synced).
+	ComputedCoins(
+		ctx context.Context,
+		dbTx database.Transaction,
+		account *types.AccountIdentifier,
+	) ([]*types.Coin, *types.BlockIdentifier, error)
+
+	// LiveCoins returns the unspent coins of an account
+	// (usually from /account/coins) and the block they
+	// were returned at.
+	LiveCoins(
+		ctx context.Context,
+		account *types.AccountIdentifier,
+	) ([]*types.Coin, *types.BlockIdentifier, error)
+}
+
+// CoinStorage is the subset of *modules.CoinStorage
+// used by CoinStorageHelper to look up computed coins.
+type CoinStorage interface {
+	GetCoinsTransactional(
+		ctx context.Context,
+		dbTx database.Transaction,
+		account *types.AccountIdentifier,
+	) ([]*types.Coin, *types.BlockIdentifier, error)
+}
+
+// CurrentBlockHelper is used by CoinStorageHelper to look
+// up the synced head (this is usually the same helper
+// provided to modules.NewCoinStorage).
+type CurrentBlockHelper interface {
+	// CurrentBlockIdentifier returns the synced head or
+	// storageErrors.ErrHeadBlockNotFound if no block
+	// has been synced.
+	CurrentBlockIdentifier(
+		ctx context.Context,
+		dbTx database.Transaction,
+	) (*types.BlockIdentifier, error)
+}
+
+// CoinFetcher is the subset of *fetcher.Fetcher
+// used by CoinStorageHelper to look up live coins.
+type CoinFetcher interface {
+	AccountCoinsRetry(
+		ctx context.Context,
+		network *types.NetworkIdentifier,
+		account *types.AccountIdentifier,
+		includeMempool bool,
+		currencies []*types.Currency,
+	) (*types.BlockIdentifier, []*types.Coin, map[string]interface{}, *fetcher.Error)
+}
+
+// CoinHandler is called by Reconciler after a coin
+// reconciliation is performed. Like Handler, returning
+// an error halts reconciliation.
+type CoinHandler interface {
+	CoinReconciliationFailed(
+		ctx context.Context,
+		failure *CoinReconciliationFailure,
+	) error
+
+	CoinReconciliationSucceeded(
+		ctx context.Context,
+		account *types.AccountIdentifier,
+		block *types.BlockIdentifier,
+	) error
+}
+
+// CoinMismatch is a coin present in both the computed
+// and live coin sets with a different amount.
+type CoinMismatch struct {
+	Computed *types.Coin `json:"computed"`
+	Live     *types.Coin `json:"live"`
+}
+
+// CoinReconciliationFailure contains the differences
+// between the computed and live coin sets of an account
+// at a block.
+type CoinReconciliationFailure struct {
+	Account *types.AccountIdentifier `json:"account_identifier"`
+	Block   *types.BlockIdentifier   `json:"block_identifier"`
+
+	// Missing are coins returned by the node
+	// that are not in the computed coin set.
+	Missing []*types.Coin `json:"missing"`
+
+	// Extra are coins in the computed coin set
+	// that are not returned by the node.
+	Extra []*types.Coin `json:"extra"`
+
+	Mismatched []*CoinMismatch `json:"mismatched"`
+}
+
+// ShouldReconcile returns a boolean indicating if an
+// account and currency should be reconciled. Accounts
+// and currencies that should not be reconciled are never
+// enqueued for active or inactive reconciliation.
+type ShouldReconcile func(
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) bool
+
+// Stats contains information about the
+// current state of a Reconciler.
+type Stats struct {
+	// QueueSize is the number of changes enqueued
+	// for active reconciliation (including priority
+	// changes).
+	QueueSize int `json:"queue_size"`
+
+	// PriorityQueueSize is the number of changes
+	// to priority accounts enqueued for active
+	// reconciliation.
+	PriorityQueueSize int `json:"priority_queue_size"`
+
+	ActiveConcurrency   int `json:"active_concurrency"`
+	InactiveConcurrency int `json:"inactive_concurrency"`
+
+	// Ignored is the number of changes that were
+	// not enqueued because ShouldReconcile returned
+	// false.
+	Ignored int64 `json:"ignored"`
+
+	// Coalesced is the number of changes that were
+	// merged into a pending change for the same
+	// account and currency (if deduplication is enabled).
+	Coalesced int64 `json:"coalesced"`
+
+	// InactivePace is the delay between inactive
+	// reconciliations computed to meet the coverage
+	// target (if configured).
+	InactivePace time.Duration `json:"inactive_pace"`
+}
+
 // InactiveEntry is used to track the last
 // time that an *types.AccountCurrency was reconciled.
 type InactiveEntry struct {
@@ -256,6 +575,16 @@ type blockRequest struct {
 // types.AccountIdentifiers returned in types.Operations
 // by a Rosetta Server.
 type Reconciler struct {
+	// ignored is the number of changes skipped by
+	// shouldReconcile. It is accessed atomically, so it
+	// must be first in the struct to be 64-bit aligned
+	// on 32-bit platforms.
+	ignored int64
+
+	// coalesced is the number of changes merged into
+	// pendingChanges (and is accessed atomically).
+	coalesced int64
+
 	helper  Helper
 	handler Handler
 	parser  *parser.Parser
@@ -276,9 +605,24 @@ type Reconciler struct {
 	// it is useful to allocate more resources to
 	// active reconciliation as it is synchronous
 	// (when lookupBalanceByBlock is enabled).
+	//
+	// These values are only used to initialize
+	// concurrency. Use SetActiveConcurrency and
+	// SetInactiveConcurrency to adjust concurrency
+	// at runtime.
 	ActiveConcurrency   int
 	InactiveConcurrency int
 
+	// activePool and inactivePool track the number of
+	// running workers. workerGroup and workerCtx are
+	// populated when Reconcile is called so that workers
+	// can be added at runtime.
+	activePool   *workerPool
+	inactivePool *workerPool
+	workerMutex  sync.Mutex
+	workerGroup  *errgroup.Group
+	workerCtx    context.Context
+
 	// highWaterMark is used to skip requests when
 	// we are very far behind the live head.
 	highWaterMark int64
@@ -312,4 +656,68 @@ type Reconciler struct {
 	// blocks asynchronously so that we don't slow down the sync
 	// loop.
 	processQueue chan *blockRequest
+
+	// queueStorage is used to persist the active
+	// reconciliation queue (if provided).
+	queueStorage QueueStorage
+
+	// priorityAccounts are *types.AccountCurrency whose
+	// changes are added to priorityQueue instead of
+	// changeQueue. priorityQueue is always drained
+	// before changeQueue.
+	priorityAccounts      map[string]struct{}
+	priorityAccountsMutex sync.RWMutex
+	priorityQueue         chan *parser.BalanceChange
+
+	// debugHelper is used to look up operations that
+	// contributed to a failed reconciliation. When set,
+	// the last successfully reconciled block of (at most
+	// defaultLastReconciledLimit) *types.AccountCurrency
+	// is stored in lastReconciled.
+	debugHelper        DebugHelper
+	debugMaxOperations int
+	lastReconciled     *lastReconciledCache
+
+	// shouldReconcile is consulted before any
+	// *types.AccountCurrency is enqueued.
+	shouldReconcile ShouldReconcile
+
+	// inactivePacer is used to pace inactive reconciliation
+	// instead of inactiveFrequency (if provided).
+	inactivePacer *inactivePacer
+
+	// pendingChanges are the *parser.BalanceChange in
+	// the active reconciliation queue that newer changes
+	// can be merged into (if deduplicateChanges is enabled).
+	deduplicateChanges  bool
+	pendingChanges      map[string]*parser.BalanceChange
+	pendingChangesMutex sync.Mutex
+
+	// liveBalanceRetries is the number of times a live
+	// balance lookup is retried (with exponential backoff
+	// starting at liveBalanceBackoff) if the Helper returns
+	// ErrLiveBalanceTransient.
+	liveBalanceRetries int
+	liveBalanceBackoff time.Duration
+
+	// coinHelper and coinHandler are used to compare
+	// the coin sets of coinAccounts (if provided). Coin
+	// reconciliation is performed by a single goroutine
+	// at most once every coinInterval.
+	coinHelper        CoinHelper
+	coinHandler       CoinHandler
+	coinInterval      time.Duration
+	coinAccounts      []*types.AccountIdentifier
+	coinAccountsSet   map[string]struct{}
+	coinAccountsMutex sync.Mutex
+
+	// reportHelper is used to export a report of all
+	// tracked accounts (if provided). The outcome of each
+	// reconciliation is stored in reportRecords so it can
+	// be included in the report. reportExports is the
+	// number of reports exported.
+	reportHelper  ReportHelper
+	reportRecords map[string]*reportRecord
+	reportExports uint64
+	reportMutex   sync.Mutex
 }
@@ -0,0 +1,117 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"sync"
+)
+
+// workerPool tracks the desired and running number
+// of workers of a single reconciliation type. Workers
+// are never interrupted. Instead, they call retire
+// between reconciliations and exit when the pool
+// has shrunk.
+type workerPool struct {
+	mutex   sync.Mutex
+	started bool
+	desired int
+	running int
+
+	// changed is closed (and replaced) whenever
+	// the desired number of workers changes so that
+	// idle workers can check if they should retire.
+	changed chan struct{}
+}
+
+func newWorkerPool(desired int) *workerPool {
+	return &workerPool{
+		desired: desired,
+		changed: make(chan struct{}),
+	}
+}
+
+// start marks the pool as started and returns
+// the number of workers to spawn.
+func (p *workerPool) start() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.started = true
+	p.running = p.desired
+
+	return p.running
+}
+
+// resize sets the desired number of workers and returns
+// the number of workers that must be spawned to reach it.
+// No workers are spawned if the pool has not been started.
+func (p *workerPool) resize(desired int) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if desired < 0 {
+		desired = 0
+	}
+
+	p.desired = desired
+	close(p.changed)
+	p.changed = make(chan struct{})
+
+	if !p.started || p.running >= p.desired {
+		return 0
+	}
+
+	spawn := p.desired - p.running
+	p.running = p.desired
+
+	return spawn
+}
+
+// retire returns a boolean indicating if the calling
+// worker should exit. If true, the worker is no longer
+// counted as running.
+func (p *workerPool) retire() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.running <= p.desired {
+		return false
+	}
+
+	p.running--
+	return true
+}
+
+// wait returns a channel that is closed when
+// the desired number of workers changes.
+func (p *workerPool) wait() <-chan struct{} {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.changed
+}
+
+// size returns the number of running workers (or the
+// desired number of workers if the pool has not started).
+func (p *workerPool) size() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !p.started {
+		return p.desired
+	}
+
+	return p.running
+}
@@ -30,16 +30,19 @@ import (
 type AccountAPIController struct {
 	service  AccountAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewAccountAPIController creates a default api controller
 func NewAccountAPIController(
 	s AccountAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &AccountAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig("AccountAPI", options),
 	}
 }
 
@@ -50,13 +53,13 @@ func (c *AccountAPIController) Routes() Routes {
 			"AccountBalance",
 			strings.ToUpper("Post"),
 			"/account/balance",
-			c.AccountBalance,
+			c.config.handler(c.AccountBalance),
 		},
 		{
 			"AccountCoins",
 			strings.ToUpper("Post"),
 			"/account/coins",
-			c.AccountCoins,
+			c.config.handler(c.AccountCoins),
 		},
 	}
 }
@@ -73,11 +76,15 @@ func (c *AccountAPIController) AccountBalance(w http.ResponseWriter, r *http.Req
 	}
 
 	// Assert that AccountBalanceRequest is correct
-	if err := c.asserter.AccountBalanceRequest(accountBalanceRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.AccountBalanceRequest(accountBalanceRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, accountBalanceRequest) {
 		return
 	}
 
@@ -103,11 +110,15 @@ func (c *AccountAPIController) AccountCoins(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Assert that AccountCoinsRequest is correct
-	if err := c.asserter.AccountCoinsRequest(accountCoinsRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.AccountCoinsRequest(accountCoinsRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, accountCoinsRequest) {
 		return
 	}
 
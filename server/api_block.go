@@ -30,16 +30,19 @@ import (
 type BlockAPIController struct {
 	service  BlockAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewBlockAPIController creates a default api controller
 func NewBlockAPIController(
 	s BlockAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &BlockAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig("BlockAPI", options),
 	}
 }
 
@@ -50,13 +53,13 @@ func (c *BlockAPIController) Routes() Routes {
 			"Block",
 			strings.ToUpper("Post"),
 			"/block",
-			c.Block,
+			c.config.handler(c.Block),
 		},
 		{
 			"BlockTransaction",
 			strings.ToUpper("Post"),
 			"/block/transaction",
-			c.BlockTransaction,
+			c.config.handler(c.BlockTransaction),
 		},
 	}
 }
@@ -73,11 +76,15 @@ func (c *BlockAPIController) Block(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Assert that BlockRequest is correct
-	if err := c.asserter.BlockRequest(blockRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.BlockRequest(blockRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, blockRequest) {
 		return
 	}
 
@@ -103,11 +110,15 @@ func (c *BlockAPIController) BlockTransaction(w http.ResponseWriter, r *http.Req
 	}
 
 	// Assert that BlockTransactionRequest is correct
-	if err := c.asserter.BlockTransactionRequest(blockTransactionRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.BlockTransactionRequest(blockTransactionRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, blockTransactionRequest) {
 		return
 	}
 
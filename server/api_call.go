@@ -30,16 +30,19 @@ import (
 type CallAPIController struct {
 	service  CallAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewCallAPIController creates a default api controller
 func NewCallAPIController(
 	s CallAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &CallAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig("CallAPI", options),
 	}
 }
 
@@ -50,7 +53,7 @@ func (c *CallAPIController) Routes() Routes {
 			"Call",
 			strings.ToUpper("Post"),
 			"/call",
-			c.Call,
+			c.config.handler(c.Call),
 		},
 	}
 }
@@ -67,11 +70,15 @@ func (c *CallAPIController) Call(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Assert that CallRequest is correct
-	if err := c.asserter.CallRequest(callRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.CallRequest(callRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, callRequest) {
 		return
 	}
 
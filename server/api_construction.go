@@ -30,16 +30,19 @@ import (
 type ConstructionAPIController struct {
 	service  ConstructionAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewConstructionAPIController creates a default api controller
 func NewConstructionAPIController(
 	s ConstructionAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &ConstructionAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig("ConstructionAPI", options),
 	}
 }
 
@@ -50,49 +53,49 @@ func (c *ConstructionAPIController) Routes() Routes {
 			"ConstructionCombine",
 			strings.ToUpper("Post"),
 			"/construction/combine",
-			c.ConstructionCombine,
+			c.config.handler(c.ConstructionCombine),
 		},
 		{
 			"ConstructionDerive",
 			strings.ToUpper("Post"),
 			"/construction/derive",
-			c.ConstructionDerive,
+			c.config.handler(c.ConstructionDerive),
 		},
 		{
 			"ConstructionHash",
 			strings.ToUpper("Post"),
 			"/construction/hash",
-			c.ConstructionHash,
+			c.config.handler(c.ConstructionHash),
 		},
 		{
 			"ConstructionMetadata",
 			strings.ToUpper("Post"),
 			"/construction/metadata",
-			c.ConstructionMetadata,
+			c.config.handler(c.ConstructionMetadata),
 		},
 		{
 			"ConstructionParse",
 			strings.ToUpper("Post"),
 			"/construction/parse",
-			c.ConstructionParse,
+			c.config.handler(c.ConstructionParse),
 		},
 		{
 			"ConstructionPayloads",
 			strings.ToUpper("Post"),
 			"/construction/payloads",
-			c.ConstructionPayloads,
+			c.config.handler(c.ConstructionPayloads),
 		},
 		{
 			"ConstructionPreprocess",
 			strings.ToUpper("Post"),
 			"/construction/preprocess",
-			c.ConstructionPreprocess,
+			c.config.handler(c.ConstructionPreprocess),
 		},
 		{
 			"ConstructionSubmit",
 			strings.ToUpper("Post"),
 			"/construction/submit",
-			c.ConstructionSubmit,
+			c.config.handler(c.ConstructionSubmit),
 		},
 	}
 }
@@ -109,11 +112,15 @@ func (c *ConstructionAPIController) ConstructionCombine(w http.ResponseWriter, r
 	}
 
 	// Assert that ConstructionCombineRequest is correct
-	if err := c.asserter.ConstructionCombineRequest(constructionCombineRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.ConstructionCombineRequest(constructionCombineRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, constructionCombineRequest) {
 		return
 	}
 
@@ -139,11 +146,15 @@ func (c *ConstructionAPIController) ConstructionDerive(w http.ResponseWriter, r
 	}
 
 	// Assert that ConstructionDeriveRequest is correct
-	if err := c.asserter.ConstructionDeriveRequest(constructionDeriveRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.ConstructionDeriveRequest(constructionDeriveRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, constructionDeriveRequest) {
 		return
 	}
 
@@ -169,11 +180,15 @@ func (c *ConstructionAPIController) ConstructionHash(w http.ResponseWriter, r *h
 	}
 
 	// Assert that ConstructionHashRequest is correct
-	if err := c.asserter.ConstructionHashRequest(constructionHashRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.ConstructionHashRequest(constructionHashRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, constructionHashRequest) {
 		return
 	}
 
@@ -199,11 +214,15 @@ func (c *ConstructionAPIController) ConstructionMetadata(w http.ResponseWriter,
 	}
 
 	// Assert that ConstructionMetadataRequest is correct
-	if err := c.asserter.ConstructionMetadataRequest(constructionMetadataRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.ConstructionMetadataRequest(constructionMetadataRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, constructionMetadataRequest) {
 		return
 	}
 
@@ -229,11 +248,15 @@ func (c *ConstructionAPIController) ConstructionParse(w http.ResponseWriter, r *
 	}
 
 	// Assert that ConstructionParseRequest is correct
-	if err := c.asserter.ConstructionParseRequest(constructionParseRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.ConstructionParseRequest(constructionParseRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, constructionParseRequest) {
 		return
 	}
 
@@ -259,11 +282,15 @@ func (c *ConstructionAPIController) ConstructionPayloads(w http.ResponseWriter,
 	}
 
 	// Assert that ConstructionPayloadsRequest is correct
-	if err := c.asserter.ConstructionPayloadsRequest(constructionPayloadsRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.ConstructionPayloadsRequest(constructionPayloadsRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, constructionPayloadsRequest) {
 		return
 	}
 
@@ -289,11 +316,15 @@ func (c *ConstructionAPIController) ConstructionPreprocess(w http.ResponseWriter
 	}
 
 	// Assert that ConstructionPreprocessRequest is correct
-	if err := c.asserter.ConstructionPreprocessRequest(constructionPreprocessRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.ConstructionPreprocessRequest(constructionPreprocessRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, constructionPreprocessRequest) {
 		return
 	}
 
@@ -322,11 +353,15 @@ func (c *ConstructionAPIController) ConstructionSubmit(w http.ResponseWriter, r
 	}
 
 	// Assert that ConstructionSubmitRequest is correct
-	if err := c.asserter.ConstructionSubmitRequest(constructionSubmitRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.ConstructionSubmitRequest(constructionSubmitRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, constructionSubmitRequest) {
 		return
 	}
 
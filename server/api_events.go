@@ -30,16 +30,19 @@ import (
 type EventsAPIController struct {
 	service  EventsAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewEventsAPIController creates a default api controller
 func NewEventsAPIController(
 	s EventsAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &EventsAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig("EventsAPI", options),
 	}
 }
 
@@ -50,7 +53,7 @@ func (c *EventsAPIController) Routes() Routes {
 			"EventsBlocks",
 			strings.ToUpper("Post"),
 			"/events/blocks",
-			c.EventsBlocks,
+			c.config.handler(c.EventsBlocks),
 		},
 	}
 }
@@ -67,11 +70,15 @@ func (c *EventsAPIController) EventsBlocks(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Assert that EventsBlocksRequest is correct
-	if err := c.asserter.EventsBlocksRequest(eventsBlocksRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.EventsBlocksRequest(eventsBlocksRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, eventsBlocksRequest) {
 		return
 	}
 
@@ -30,16 +30,19 @@ import (
 type MempoolAPIController struct {
 	service  MempoolAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewMempoolAPIController creates a default api controller
 func NewMempoolAPIController(
 	s MempoolAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &MempoolAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig("MempoolAPI", options),
 	}
 }
 
@@ -50,13 +53,13 @@ func (c *MempoolAPIController) Routes() Routes {
 			"Mempool",
 			strings.ToUpper("Post"),
 			"/mempool",
-			c.Mempool,
+			c.config.handler(c.Mempool),
 		},
 		{
 			"MempoolTransaction",
 			strings.ToUpper("Post"),
 			"/mempool/transaction",
-			c.MempoolTransaction,
+			c.config.handler(c.MempoolTransaction),
 		},
 	}
 }
@@ -73,11 +76,15 @@ func (c *MempoolAPIController) Mempool(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Assert that NetworkRequest is correct
-	if err := c.asserter.NetworkRequest(networkRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.NetworkRequest(networkRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, networkRequest) {
 		return
 	}
 
@@ -103,11 +110,15 @@ func (c *MempoolAPIController) MempoolTransaction(w http.ResponseWriter, r *http
 	}
 
 	// Assert that MempoolTransactionRequest is correct
-	if err := c.asserter.MempoolTransactionRequest(mempoolTransactionRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.MempoolTransactionRequest(mempoolTransactionRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, mempoolTransactionRequest) {
 		return
 	}
 
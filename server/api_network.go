@@ -30,16 +30,19 @@ import (
 type NetworkAPIController struct {
 	service  NetworkAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewNetworkAPIController creates a default api controller
 func NewNetworkAPIController(
 	s NetworkAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &NetworkAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig("NetworkAPI", options),
 	}
 }
 
@@ -50,19 +53,19 @@ func (c *NetworkAPIController) Routes() Routes {
 			"NetworkList",
 			strings.ToUpper("Post"),
 			"/network/list",
-			c.NetworkList,
+			c.config.handler(c.NetworkList),
 		},
 		{
 			"NetworkOptions",
 			strings.ToUpper("Post"),
 			"/network/options",
-			c.NetworkOptions,
+			c.config.handler(c.NetworkOptions),
 		},
 		{
 			"NetworkStatus",
 			strings.ToUpper("Post"),
 			"/network/status",
-			c.NetworkStatus,
+			c.config.handler(c.NetworkStatus),
 		},
 	}
 }
@@ -79,11 +82,15 @@ func (c *NetworkAPIController) NetworkList(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Assert that MetadataRequest is correct
-	if err := c.asserter.MetadataRequest(metadataRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.MetadataRequest(metadataRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, metadataRequest) {
 		return
 	}
 
@@ -109,11 +116,15 @@ func (c *NetworkAPIController) NetworkOptions(w http.ResponseWriter, r *http.Req
 	}
 
 	// Assert that NetworkRequest is correct
-	if err := c.asserter.NetworkRequest(networkRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.NetworkRequest(networkRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
 
+			return
+		}
+	}
+
+	if c.config.stream(w, r, c.service, networkRequest) {
 		return
 	}
 
@@ -139,11 +150,15 @@ func (c *NetworkAPIController) NetworkStatus(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Assert that NetworkRequest is correct
-	if err := c.asserter.NetworkRequest(networkRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.NetworkRequest(networkRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, networkRequest) {
 		return
 	}
 
@@ -30,16 +30,19 @@ import (
 type SearchAPIController struct {
 	service  SearchAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewSearchAPIController creates a default api controller
 func NewSearchAPIController(
 	s SearchAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &SearchAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig("SearchAPI", options),
 	}
 }
 
@@ -50,7 +53,7 @@ func (c *SearchAPIController) Routes() Routes {
 			"SearchTransactions",
 			strings.ToUpper("Post"),
 			"/search/transactions",
-			c.SearchTransactions,
+			c.config.handler(c.SearchTransactions),
 		},
 	}
 }
@@ -67,11 +70,15 @@ func (c *SearchAPIController) SearchTransactions(w http.ResponseWriter, r *http.
 	}
 
 	// Assert that SearchTransactionsRequest is correct
-	if err := c.asserter.SearchTransactionsRequest(searchTransactionsRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if c.config.assertRequests() {
+		if err := c.asserter.SearchTransactionsRequest(searchTransactionsRequest); err != nil {
+			EncodeJSONResponse(c.config.invalidRequest(err), http.StatusInternalServerError, w)
+
+			return
+		}
+	}
 
+	if c.config.stream(w, r, c.service, searchTransactionsRequest) {
 		return
 	}
 
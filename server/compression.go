@@ -0,0 +1,179 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// gzipEncoding is the value of the Accept-Encoding
+	// and Content-Encoding headers for gzip.
+	gzipEncoding = "gzip"
+)
+
+// gzipWriters is a pool of *gzip.Writer so that
+// compression buffers are reused across requests.
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// WithCompression enables gzip compression in a controller.
+// Responses are compressed when the client sends
+// "Accept-Encoding: gzip" and requests sent with
+// "Content-Encoding: gzip" are transparently decompressed.
+//
+// Responses are compressed as they are written (instead of
+// buffering the entire response in memory).
+func WithCompression() ControllerOption {
+	return func(c *controllerConfig) {
+		c.compression = true
+	}
+}
+
+// handler wraps h with any handlers required by the
+// configuration of the controller.
+func (c *controllerConfig) handler(h http.HandlerFunc) http.HandlerFunc {
+	if !c.compression {
+		return h
+	}
+
+	return compressionHandler(h)
+}
+
+// compressionHandler decompresses gzip encoded requests and
+// compresses responses for clients that accept gzip.
+func compressionHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Content-Encoding"), gzipEncoding) {
+			reader, err := gzip.NewReader(r.Body)
+			if err != nil {
+				EncodeJSONResponse(&types.Error{
+					Message: err.Error(),
+				}, http.StatusInternalServerError, w)
+
+				return
+			}
+
+			r.Body = &gzipReadCloser{Reader: reader, body: r.Body}
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+		}
+
+		if !acceptsGzip(r) {
+			next(w, r)
+			return
+		}
+
+		writer := gzipWriters.Get().(*gzip.Writer)
+		writer.Reset(w)
+		defer func() {
+			// The writer must be closed to flush
+			// the gzip footer.
+			_ = writer.Close()
+			gzipWriters.Put(writer)
+		}()
+
+		w.Header().Set("Content-Encoding", gzipEncoding)
+		w.Header().Add("Vary", "Accept-Encoding")
+		next(&gzipResponseWriter{ResponseWriter: w, writer: writer}, r)
+	}
+}
+
+// acceptsGzip returns a boolean indicating if
+// r accepts gzip encoded responses.
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		params := strings.Split(encoding, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), gzipEncoding) {
+			continue
+		}
+
+		// gzip is not acceptable if q=0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			if q, err := strconv.ParseFloat(param[2:], 64); err == nil && q == 0 {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// gzipReadCloser closes both the *gzip.Reader
+// and the underlying request body.
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close closes the *gzip.Reader and the
+// underlying request body.
+func (g *gzipReadCloser) Close() error {
+	if err := g.Reader.Close(); err != nil {
+		_ = g.body.Close()
+		return err
+	}
+
+	return g.body.Close()
+}
+
+// gzipResponseWriter is an http.ResponseWriter
+// that compresses all writes.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer *gzip.Writer
+}
+
+// WriteHeader removes any Content-Length (which
+// would refer to the uncompressed body) before
+// writing the status code.
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	g.Header().Del("Content-Length")
+	g.ResponseWriter.WriteHeader(status)
+}
+
+// Write compresses b before writing it to
+// the underlying http.ResponseWriter.
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	return g.writer.Write(b)
+}
+
+// Flush writes any pending compressed data
+// to the underlying http.ResponseWriter and
+// flushes it (if supported).
+func (g *gzipResponseWriter) Flush() {
+	_ = g.writer.Flush()
+	if flusher, ok := g.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
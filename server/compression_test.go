@@ -0,0 +1,278 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/client"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	largeBlockTransactions = 10000
+)
+
+var compressionNetwork = &types.NetworkIdentifier{
+	Blockchain: "bitcoin",
+	Network:    "mainnet",
+}
+
+type largeBlockServicer struct {
+	BlockAPIServicer
+
+	block *types.Block
+}
+
+func (s *largeBlockServicer) Block(
+	context.Context,
+	*types.BlockRequest,
+) (*types.BlockResponse, *types.Error) {
+	return &types.BlockResponse{Block: s.block}, nil
+}
+
+func largeBlock() *types.Block {
+	block := &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: 1,
+			Hash:  "block 1",
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		Timestamp: asserter.MinUnixEpoch + 1,
+	}
+
+	for i := 0; i < largeBlockTransactions; i++ {
+		block.Transactions = append(block.Transactions, &types.Transaction{
+			TransactionIdentifier: &types.TransactionIdentifier{
+				Hash: fmt.Sprintf("tx %d", i),
+			},
+			Operations: []*types.Operation{
+				{
+					OperationIdentifier: &types.OperationIdentifier{
+						Index: 0,
+					},
+					Type:   "Transfer",
+					Status: types.String("Success"),
+					Account: &types.AccountIdentifier{
+						Address: fmt.Sprintf("addr %d", i),
+					},
+					Amount: &types.Amount{
+						Value: "-100",
+						Currency: &types.Currency{
+							Symbol:   "BTC",
+							Decimals: 8,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return block
+}
+
+// countingTransport records the response headers and
+// the number of bytes received over the wire.
+type countingTransport struct {
+	mutex   sync.Mutex
+	headers http.Header
+	bytes   int64
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// DisableCompression prevents the transport from
+	// requesting (and decompressing) gzip responses
+	transport := &http.Transport{DisableCompression: true}
+	resp, err := transport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.headers = resp.Header.Clone()
+	c.bytes = int64(len(body))
+	c.mutex.Unlock()
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func TestCompression(t *testing.T) {
+	a, err := asserter.NewServer(
+		[]string{"Transfer"},
+		false,
+		[]*types.NetworkIdentifier{compressionNetwork},
+		nil,
+		false,
+	)
+	assert.NoError(t, err)
+
+	block := largeBlock()
+	uncompressed, err := json.Marshal(&types.BlockResponse{Block: block})
+	assert.NoError(t, err)
+
+	var tests = map[string]struct {
+		serverCompression bool
+		clientCompression bool
+
+		compressed bool
+	}{
+		"no compression": {},
+		"server compression": {
+			serverCompression: true,
+		},
+		"client compression": {
+			clientCompression: true,
+		},
+		"compression": {
+			serverCompression: true,
+			clientCompression: true,
+			compressed:        true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			options := []ControllerOption{}
+			if test.serverCompression {
+				options = append(options, WithCompression())
+			}
+
+			ts := httptest.NewServer(NewRouter(
+				NewBlockAPIController(&largeBlockServicer{block: block}, a, options...),
+			))
+			defer ts.Close()
+
+			transport := &countingTransport{}
+			cfg := client.NewConfiguration(ts.URL, "test", &http.Client{Transport: transport})
+			cfg.Compression = test.clientCompression
+			c := client.NewAPIClient(cfg)
+
+			response, clientErr, err := c.BlockAPI.Block(context.Background(), &types.BlockRequest{
+				NetworkIdentifier: compressionNetwork,
+				BlockIdentifier: &types.PartialBlockIdentifier{
+					Index: types.Int64(1),
+				},
+			})
+			assert.NoError(t, err)
+			assert.Nil(t, clientErr)
+			assert.Equal(t, block, response.Block)
+
+			if test.compressed {
+				assert.Equal(t, "gzip", transport.headers.Get("Content-Encoding"))
+				assert.True(t, transport.bytes < int64(len(uncompressed))/2)
+			} else {
+				assert.Empty(t, transport.headers.Get("Content-Encoding"))
+				assert.True(t, transport.bytes >= int64(len(uncompressed)))
+			}
+		})
+	}
+}
+
+func TestCompressedRequest(t *testing.T) {
+	a, err := asserter.NewServer(
+		[]string{"Transfer"},
+		false,
+		[]*types.NetworkIdentifier{compressionNetwork},
+		nil,
+		false,
+	)
+	assert.NoError(t, err)
+
+	request, err := json.Marshal(&types.BlockRequest{
+		NetworkIdentifier: compressionNetwork,
+		BlockIdentifier: &types.PartialBlockIdentifier{
+			Index: types.Int64(1),
+		},
+	})
+	assert.NoError(t, err)
+
+	var body bytes.Buffer
+	writer := gzip.NewWriter(&body)
+	_, err = writer.Write(request)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	post := func(handler http.Handler, body io.Reader) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/block", body)
+		r.Header.Set("Content-Encoding", "gzip")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, r)
+		return rr
+	}
+
+	// Compressed requests are decompressed when
+	// compression is enabled
+	servicer := &largeBlockServicer{block: largeBlock()}
+	router := NewRouter(NewBlockAPIController(servicer, a, WithCompression()))
+	rr := post(router, bytes.NewReader(body.Bytes()))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+
+	// Invalid gzip is rejected
+	rr = post(router, bytes.NewReader(request))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	// Compressed requests are not decompressed
+	// when compression is disabled
+	router = NewRouter(NewBlockAPIController(servicer, a))
+	rr = post(router, bytes.NewReader(body.Bytes()))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	var tests = map[string]bool{
+		"":                   false,
+		"gzip":               true,
+		"GZIP":               true,
+		"deflate, gzip":      true,
+		"gzip;q=0.5, br":     true,
+		"br, gzip; q=0":      false,
+		"gzip;q=0.000":       false,
+		"identity, deflate":  false,
+		"x-gzip":             false,
+		"gzip;level=1;q=1.0": true,
+	}
+
+	for header, accepts := range tests {
+		t.Run(header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/block", nil)
+			r.Header.Set("Accept-Encoding", header)
+			assert.Equal(t, accepts, acceptsGzip(r))
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// defaultIndexerLimit is the number of results
+	// returned by the memoryIndexer when no limit is
+	// provided.
+	defaultIndexerLimit = 2
+)
+
+var indexerNetwork = &types.NetworkIdentifier{
+	Blockchain: "bitcoin",
+	Network:    "mainnet",
+}
+
+// memoryIndexer is a reference in-memory implementation
+// of the EventsAPIServicer and SearchAPIServicer that
+// paginates results using offset and limit.
+type memoryIndexer struct {
+	events       []*types.BlockEvent
+	transactions []*types.BlockTransaction
+}
+
+func newMemoryIndexer(blocks int64) *memoryIndexer {
+	m := &memoryIndexer{}
+	for i := int64(0); i < blocks; i++ {
+		blockIdentifier := &types.BlockIdentifier{
+			Index: i,
+			Hash:  fmt.Sprintf("block %d", i),
+		}
+
+		m.events = append(m.events, &types.BlockEvent{
+			Sequence:        i,
+			BlockIdentifier: blockIdentifier,
+			Type:            types.ADDED,
+		})
+
+		// Transactions are sorted by most recent block
+		m.transactions = append([]*types.BlockTransaction{
+			{
+				BlockIdentifier: blockIdentifier,
+				Transaction: &types.Transaction{
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: fmt.Sprintf("tx %d", i),
+					},
+					Operations: []*types.Operation{},
+				},
+			},
+		}, m.transactions...)
+	}
+
+	return m
+}
+
+// page returns the bounds of the page of a collection
+// of size total described by offset and limit.
+func page(total int64, offset *int64, limit *int64) (int64, int64) {
+	start := int64(0)
+	if offset != nil {
+		start = *offset
+	}
+	if start > total {
+		start = total
+	}
+
+	size := int64(defaultIndexerLimit)
+	if limit != nil {
+		size = *limit
+	}
+
+	end := start + size
+	if end > total {
+		end = total
+	}
+
+	return start, end
+}
+
+func (m *memoryIndexer) EventsBlocks(
+	ctx context.Context,
+	request *types.EventsBlocksRequest,
+) (*types.EventsBlocksResponse, *types.Error) {
+	start, end := page(int64(len(m.events)), request.Offset, request.Limit)
+
+	return &types.EventsBlocksResponse{
+		MaxSequence: m.events[len(m.events)-1].Sequence,
+		Events:      m.events[start:end],
+	}, nil
+}
+
+func (m *memoryIndexer) SearchTransactions(
+	ctx context.Context,
+	request *types.SearchTransactionsRequest,
+) (*types.SearchTransactionsResponse, *types.Error) {
+	total := int64(len(m.transactions))
+	start, end := page(total, request.Offset, request.Limit)
+
+	response := &types.SearchTransactionsResponse{
+		Transactions: m.transactions[start:end],
+		TotalCount:   total,
+	}
+	if end < total {
+		response.NextOffset = &end
+	}
+
+	return response, nil
+}
+
+func postIndexer(
+	t *testing.T,
+	router http.Handler,
+	path string,
+	request interface{},
+	response interface{},
+) int {
+	body, err := json.Marshal(request)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(
+		rr,
+		httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(body))),
+	)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), response))
+
+	return rr.Code
+}
+
+func newIndexerRouter(t *testing.T) http.Handler {
+	a, err := asserter.NewServer(
+		[]string{"Transfer"},
+		false,
+		[]*types.NetworkIdentifier{indexerNetwork},
+		nil,
+		false,
+	)
+	assert.NoError(t, err)
+
+	indexer := newMemoryIndexer(5)
+
+	return NewRouter(
+		NewEventsAPIController(indexer, a),
+		NewSearchAPIController(indexer, a),
+	)
+}
+
+func TestEventsBlocks(t *testing.T) {
+	router := newIndexerRouter(t)
+
+	// Paginate through all events
+	offset := int64(0)
+	limit := int64(2)
+	sequences := []int64{}
+	for {
+		var response types.EventsBlocksResponse
+		status := postIndexer(t, router, "/events/blocks", &types.EventsBlocksRequest{
+			NetworkIdentifier: indexerNetwork,
+			Offset:            &offset,
+			Limit:             &limit,
+		}, &response)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, int64(4), response.MaxSequence)
+
+		for _, event := range response.Events {
+			sequences = append(sequences, event.Sequence)
+		}
+
+		offset += int64(len(response.Events))
+		if offset > response.MaxSequence {
+			break
+		}
+	}
+	assert.Equal(t, []int64{0, 1, 2, 3, 4}, sequences)
+
+	// Negative limit is rejected by the asserter
+	negative := int64(-1)
+	var invalidResponse types.Error
+	status := postIndexer(t, router, "/events/blocks", &types.EventsBlocksRequest{
+		NetworkIdentifier: indexerNetwork,
+		Limit:             &negative,
+	}, &invalidResponse)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, asserter.ErrLimitIsNegative.Error(), invalidResponse.Details["error"])
+
+	// Unsupported network is rejected by the asserter
+	invalidResponse = types.Error{}
+	status = postIndexer(t, router, "/events/blocks", &types.EventsBlocksRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{
+			Blockchain: "bitcoin",
+			Network:    "testnet",
+		},
+	}, &invalidResponse)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Contains(
+		t,
+		invalidResponse.Details["error"],
+		asserter.ErrRequestedNetworkNotSupported.Error(),
+	)
+}
+
+func TestSearchTransactions(t *testing.T) {
+	router := newIndexerRouter(t)
+
+	// Paginate through all transactions using next_offset
+	var offset *int64
+	hashes := []string{}
+	for {
+		var response types.SearchTransactionsResponse
+		status := postIndexer(t, router, "/search/transactions", &types.SearchTransactionsRequest{
+			NetworkIdentifier: indexerNetwork,
+			Offset:            offset,
+		}, &response)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, int64(5), response.TotalCount)
+		assert.True(t, len(response.Transactions) <= defaultIndexerLimit)
+
+		for _, tx := range response.Transactions {
+			hashes = append(hashes, tx.Transaction.TransactionIdentifier.Hash)
+		}
+
+		if response.NextOffset == nil {
+			break
+		}
+		offset = response.NextOffset
+	}
+	assert.Equal(t, []string{"tx 4", "tx 3", "tx 2", "tx 1", "tx 0"}, hashes)
+
+	// Negative offset is rejected by the asserter
+	negative := int64(-1)
+	var invalidResponse types.Error
+	status := postIndexer(t, router, "/search/transactions", &types.SearchTransactionsRequest{
+		NetworkIdentifier: indexerNetwork,
+		Offset:            &negative,
+	}, &invalidResponse)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, asserter.ErrOffsetIsNegative.Error(), invalidResponse.Details["error"])
+}
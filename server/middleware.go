@@ -0,0 +1,179 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// defaultInternalError is returned by the middleware created with
+// NewRecoveryMiddleware when a handler panics (if no error
+// is provided).
+var defaultInternalError = &types.Error{
+	Code:    0,
+	Message: "internal server error",
+}
+
+// RequestLogger is used by the middleware created with
+// NewLoggingMiddleware to log each request.
+type RequestLogger interface {
+	LogRequest(method string, route string, status int, latency time.Duration)
+}
+
+// statusRecorder is an http.ResponseWriter that
+// records the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader records the status code before
+// writing it to the underlying http.ResponseWriter.
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes to the underlying http.ResponseWriter
+// (implicitly writing http.StatusOK if no status code
+// has been written).
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.status = http.StatusOK
+		r.wroteHeader = true
+	}
+
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush flushes the underlying http.ResponseWriter
+// (if supported).
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// recordStatus wraps w in a *statusRecorder (if
+// it isn't one already).
+func recordStatus(w http.ResponseWriter) *statusRecorder {
+	if recorder, ok := w.(*statusRecorder); ok {
+		return recorder
+	}
+
+	return &statusRecorder{ResponseWriter: w}
+}
+
+// NewLoggingMiddleware returns a Middleware that logs the
+// method, route name, status code, and latency of each request
+// using logger.
+func NewLoggingMiddleware(logger RequestLogger) Middleware {
+	return func(next http.Handler, routeName string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := recordStatus(w)
+
+			// Log the request even if next panics (so that it
+			// is logged when used inside of the recovery
+			// middleware).
+			defer func() {
+				p := recover()
+
+				status := recorder.status
+				switch {
+				case recorder.wroteHeader:
+				case p != nil:
+					status = http.StatusInternalServerError
+				default:
+					status = http.StatusOK
+				}
+
+				logger.LogRequest(r.Method, routeName, status, time.Since(start))
+				if p != nil {
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+// NewRecoveryMiddleware returns a Middleware that recovers
+// from any panic in a route handler and responds with
+// internalError (and status code 500) instead of terminating
+// the process. The panic value and route name are populated
+// in the details of the returned error. If internalError is
+// nil, an error with code 0 is returned.
+func NewRecoveryMiddleware(internalError *types.Error) Middleware {
+	if internalError == nil {
+		internalError = defaultInternalError
+	}
+
+	return func(next http.Handler, routeName string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := recordStatus(w)
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+
+				// http.ErrAbortHandler is used to abort
+				// a partially written response.
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+
+				log.Printf("panic in %s: %v\n%s", routeName, p, debug.Stack())
+
+				// If the handler already started writing a
+				// response, we can't return an error.
+				if recorder.wroteHeader {
+					return
+				}
+
+				details := map[string]interface{}{}
+				for k, v := range internalError.Details {
+					details[k] = v
+				}
+				details["route"] = routeName
+				details["panic"] = fmt.Sprintf("%v", p)
+
+				EncodeJSONResponse(&types.Error{
+					Code:        internalError.Code,
+					Message:     internalError.Message,
+					Description: internalError.Description,
+					Retriable:   internalError.Retriable,
+					Details:     details,
+				}, http.StatusInternalServerError, recorder)
+			}()
+
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+type testRouter struct{}
+
+func (testRouter) Routes() Routes {
+	return Routes{
+		{
+			"Block",
+			http.MethodPost,
+			"/block",
+			func(w http.ResponseWriter, r *http.Request) {
+				EncodeJSONResponse(&types.BlockResponse{}, http.StatusOK, w)
+			},
+		},
+		{
+			"BlockTransaction",
+			http.MethodPost,
+			"/block/transaction",
+			func(w http.ResponseWriter, r *http.Request) {
+				panic("block transaction failed")
+			},
+		},
+		{
+			"Mempool",
+			http.MethodPost,
+			"/mempool",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+				panic("mempool failed")
+			},
+		},
+	}
+}
+
+type loggedRequest struct {
+	method string
+	route  string
+	status int
+}
+
+type testRequestLogger struct {
+	mutex    sync.Mutex
+	requests []*loggedRequest
+}
+
+func (l *testRequestLogger) LogRequest(
+	method string,
+	route string,
+	status int,
+	latency time.Duration,
+) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.requests = append(l.requests, &loggedRequest{
+		method: method,
+		route:  route,
+		status: status,
+	})
+}
+
+func post(t *testing.T, handler http.Handler, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestNewRouterWithMiddleware(t *testing.T) {
+	calls := []string{}
+	labeler := func(label string) Middleware {
+		return func(next http.Handler, routeName string) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, label+":"+routeName)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	router := NewRouterWithMiddleware(
+		[]Middleware{labeler("outer"), labeler("inner")},
+		testRouter{},
+	)
+
+	w := post(t, router, "/block")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"outer:Block", "inner:Block"}, calls)
+
+	// Unknown routes are not wrapped
+	w = post(t, router, "/account/balance")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Len(t, calls, 2)
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	logger := &testRequestLogger{}
+	description := "unexpected error"
+	router := NewRouterWithMiddleware(
+		[]Middleware{
+			NewLoggingMiddleware(logger),
+			NewRecoveryMiddleware(&types.Error{
+				Code:        500,
+				Message:     "internal error",
+				Description: &description,
+				Details: map[string]interface{}{
+					"version": "1.0",
+				},
+			}),
+		},
+		testRouter{},
+	)
+
+	t.Run("no panic", func(t *testing.T) {
+		w := post(t, router, "/block")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, types.PrintStruct(&types.BlockResponse{}), w.Body.String())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		w := post(t, router, "/block/transaction")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response types.Error
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, types.Error{
+			Code:        500,
+			Message:     "internal error",
+			Description: &description,
+			Details: map[string]interface{}{
+				"version": "1.0",
+				"route":   "BlockTransaction",
+				"panic":   "block transaction failed",
+			},
+		}, response)
+	})
+
+	t.Run("panic after writing header", func(t *testing.T) {
+		w := post(t, router, "/mempool")
+		assert.Equal(t, http.StatusTeapot, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	// The server remains usable after panics
+	w := post(t, router, "/block")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, []*loggedRequest{
+		{method: http.MethodPost, route: "Block", status: http.StatusOK},
+		{method: http.MethodPost, route: "BlockTransaction", status: http.StatusInternalServerError},
+		{method: http.MethodPost, route: "Mempool", status: http.StatusTeapot},
+		{method: http.MethodPost, route: "Block", status: http.StatusOK},
+	}, logger.requests)
+}
+
+func TestRecoveryMiddlewareDefaultError(t *testing.T) {
+	router := NewRouterWithMiddleware(
+		[]Middleware{NewRecoveryMiddleware(nil)},
+		testRouter{},
+	)
+
+	w := post(t, router, "/block/transaction")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var response types.Error
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, types.Error{
+		Code:    0,
+		Message: "internal server error",
+		Details: map[string]interface{}{
+			"route": "BlockTransaction",
+			"panic": "block transaction failed",
+		},
+	}, response)
+}
+
+func TestLoggingMiddlewarePanic(t *testing.T) {
+	logger := &testRequestLogger{}
+
+	// When the logging middleware is inside of the recovery
+	// middleware, the panic is logged as a 500 and then
+	// propagated to the recovery middleware.
+	router := NewRouterWithMiddleware(
+		[]Middleware{NewRecoveryMiddleware(nil), NewLoggingMiddleware(logger)},
+		testRouter{},
+	)
+
+	w := post(t, router, "/block/transaction")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, []*loggedRequest{
+		{method: http.MethodPost, route: "BlockTransaction", status: http.StatusInternalServerError},
+	}, logger.requests)
+}
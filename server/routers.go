@@ -62,16 +62,33 @@ func CorsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// Middleware wraps the http.Handler of a single route. The
+// name of the route (the Rosetta operation, ex: "Block") is
+// provided so that it can be used for labeling.
+type Middleware func(next http.Handler, routeName string) http.Handler
+
 // NewRouter creates a new router for any number of api routers
 func NewRouter(routers ...Router) http.Handler {
+	return NewRouterWithMiddleware(nil, routers...)
+}
+
+// NewRouterWithMiddleware creates a new router for any number of
+// api routers where each route is wrapped by middlewares. The
+// first middleware is the outermost (it is invoked first).
+func NewRouterWithMiddleware(middlewares []Middleware, routers ...Router) http.Handler {
 	router := mux.NewRouter().StrictSlash(true)
 	for _, api := range routers {
 		for _, route := range api.Routes() {
+			var handler http.Handler = route.HandlerFunc
+			for i := len(middlewares) - 1; i >= 0; i-- {
+				handler = middlewares[i](handler, route.Name)
+			}
+
 			router.
 				Methods(route.Method).
 				Path(route.Pattern).
 				Name(route.Name).
-				Handler(route.HandlerFunc)
+				Handler(handler)
 		}
 	}
 
@@ -0,0 +1,199 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// TransactionIterator iterates over the transactions
+// in a block.
+type TransactionIterator interface {
+	// Next returns the next transaction in the block. When
+	// there are no more transactions, Next returns nil, nil.
+	Next(ctx context.Context) (*types.Transaction, error)
+}
+
+// StreamingBlockAPIServicer can be implemented by a
+// BlockAPIServicer to stream the transactions in a block
+// to the client as they are encoded (instead of holding
+// all transactions in memory while encoding the
+// *types.BlockResponse returned by Block).
+//
+// If a BlockAPIServicer implements StreamingBlockAPIServicer,
+// BlockStream is invoked for /block requests instead of Block.
+type StreamingBlockAPIServicer interface {
+	// BlockStream returns a *types.BlockResponse and a
+	// TransactionIterator. Any transactions populated in the
+	// block are encoded before transactions returned by the
+	// TransactionIterator. If the TransactionIterator is nil,
+	// the *types.BlockResponse is encoded as is.
+	BlockStream(
+		context.Context,
+		*types.BlockRequest,
+	) (*types.BlockResponse, TransactionIterator, *types.Error)
+}
+
+// WithFlushInterval sets the minimum interval between flushes
+// of the response while streaming transactions (by default,
+// responses are only flushed when the buffer of the
+// http.ResponseWriter is full).
+func WithFlushInterval(interval time.Duration) ControllerOption {
+	return func(c *controllerConfig) {
+		c.flushInterval = interval
+	}
+}
+
+// streamedBlock is encoded instead of a *types.Block when
+// transactions are streamed. The Transactions field shadows
+// the Transactions field of the embedded *types.Block, so
+// transactions are omitted when it is nil.
+type streamedBlock struct {
+	*types.Block
+
+	Transactions []*types.Transaction `json:"transactions,omitempty"`
+}
+
+// stream returns a boolean indicating if the response
+// to request was streamed to w (which is only possible
+// if service supports streaming responses to request).
+func (c *controllerConfig) stream(
+	w http.ResponseWriter,
+	r *http.Request,
+	service interface{},
+	request interface{},
+) bool {
+	switch request := request.(type) {
+	case *types.BlockRequest:
+		streamer, ok := service.(StreamingBlockAPIServicer)
+		if !ok {
+			return false
+		}
+
+		c.streamBlock(w, r, streamer, request)
+		return true
+	default:
+		return false
+	}
+}
+
+// streamBlock writes the *types.BlockResponse returned by
+// streamer to w, encoding each transaction as it is returned
+// by the TransactionIterator.
+//
+// If the TransactionIterator returns an error after the
+// response has been partially written, the response is
+// aborted (an error can no longer be returned).
+func (c *controllerConfig) streamBlock(
+	w http.ResponseWriter,
+	r *http.Request,
+	streamer StreamingBlockAPIServicer,
+	request *types.BlockRequest,
+) {
+	ctx := r.Context()
+	result, iterator, serviceErr := streamer.BlockStream(ctx, request)
+	if serviceErr != nil {
+		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+
+		return
+	}
+
+	if iterator == nil || result.Block == nil {
+		EncodeJSONResponse(result, http.StatusOK, w)
+
+		return
+	}
+
+	// Encode the block without transactions and remove
+	// the closing brace so that transactions can be
+	// appended.
+	header, err := json.Marshal(&streamedBlock{Block: result.Block})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	lastFlush := time.Now()
+	write := func(b []byte) {
+		if _, err := w.Write(b); err != nil {
+			panic(http.ErrAbortHandler)
+		}
+	}
+
+	write([]byte(`{"block":`))
+	write(header[:len(header)-1])
+	write([]byte(`,"transactions":[`))
+
+	encoder := json.NewEncoder(w)
+	count := 0
+	encode := func(tx *types.Transaction) {
+		if count > 0 {
+			write([]byte(","))
+		}
+		count++
+
+		if err := encoder.Encode(tx); err != nil {
+			panic(http.ErrAbortHandler)
+		}
+
+		if flusher != nil && c.flushInterval > 0 && time.Since(lastFlush) >= c.flushInterval {
+			flusher.Flush()
+			lastFlush = time.Now()
+		}
+	}
+
+	for _, tx := range result.Block.Transactions {
+		encode(tx)
+	}
+
+	for {
+		tx, err := iterator.Next(ctx)
+		if err != nil {
+			log.Printf("aborting /block response: %s", err.Error())
+			panic(http.ErrAbortHandler)
+		}
+
+		if tx == nil {
+			break
+		}
+
+		encode(tx)
+	}
+
+	write([]byte("]}"))
+
+	if len(result.OtherTransactions) > 0 {
+		otherTransactions, err := json.Marshal(result.OtherTransactions)
+		if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+
+		write([]byte(`,"other_transactions":`))
+		write(otherTransactions)
+	}
+
+	write([]byte("}\n"))
+}
@@ -0,0 +1,341 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/client"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	benchmarkBlockTransactions = 100000
+)
+
+var streamingNetwork = &types.NetworkIdentifier{
+	Blockchain: "bitcoin",
+	Network:    "mainnet",
+}
+
+func streamingTransaction(i int) *types.Transaction {
+	return &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: fmt.Sprintf("tx %d", i),
+		},
+		Operations: []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type:   "Transfer",
+				Status: types.String("Success"),
+				Account: &types.AccountIdentifier{
+					Address: fmt.Sprintf("addr %d", i),
+				},
+				Amount: &types.Amount{
+					Value: "-100",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+			},
+		},
+	}
+}
+
+func streamingBlock() *types.Block {
+	return &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: 1,
+			Hash:  "block 1",
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "block 0",
+		},
+		Timestamp: asserter.MinUnixEpoch + 1,
+		Metadata: map[string]interface{}{
+			"size": "large",
+		},
+	}
+}
+
+// generatedTransactions is a TransactionIterator that
+// generates transactions (so they are never all held
+// in memory).
+type generatedTransactions struct {
+	next  int
+	count int
+	err   error
+}
+
+func (g *generatedTransactions) Next(context.Context) (*types.Transaction, error) {
+	if g.next == g.count {
+		return nil, g.err
+	}
+
+	tx := streamingTransaction(g.next)
+	g.next++
+	return tx, nil
+}
+
+// streamingBlockServicer implements StreamingBlockAPIServicer
+// using a block that contains the first transaction and
+// generates the remaining transactions.
+type streamingBlockServicer struct {
+	BlockAPIServicer
+
+	transactions int
+	err          error
+}
+
+func (s *streamingBlockServicer) BlockStream(
+	context.Context,
+	*types.BlockRequest,
+) (*types.BlockResponse, TransactionIterator, *types.Error) {
+	block := streamingBlock()
+	block.Transactions = []*types.Transaction{streamingTransaction(0)}
+
+	return &types.BlockResponse{
+		Block: block,
+		OtherTransactions: []*types.TransactionIdentifier{
+			{Hash: "other tx"},
+		},
+	}, &generatedTransactions{
+		next:  1,
+		count: s.transactions,
+		err:   s.err,
+	}, nil
+}
+
+// structBlockServicer returns the entire block
+// in the *types.BlockResponse.
+type structBlockServicer struct {
+	BlockAPIServicer
+
+	transactions int
+}
+
+func (s *structBlockServicer) Block(
+	context.Context,
+	*types.BlockRequest,
+) (*types.BlockResponse, *types.Error) {
+	block := streamingBlock()
+	for i := 0; i < s.transactions; i++ {
+		block.Transactions = append(block.Transactions, streamingTransaction(i))
+	}
+
+	return &types.BlockResponse{
+		Block: block,
+		OtherTransactions: []*types.TransactionIdentifier{
+			{Hash: "other tx"},
+		},
+	}, nil
+}
+
+func newStreamingAsserter(t assert.TestingT) *asserter.Asserter {
+	a, err := asserter.NewServer(
+		[]string{"Transfer"},
+		false,
+		[]*types.NetworkIdentifier{streamingNetwork},
+		nil,
+		false,
+	)
+	assert.NoError(t, err)
+
+	return a
+}
+
+func TestStreamBlock(t *testing.T) {
+	a := newStreamingAsserter(t)
+	blockRequest := &types.BlockRequest{
+		NetworkIdentifier: streamingNetwork,
+		BlockIdentifier: &types.PartialBlockIdentifier{
+			Index: types.Int64(1),
+		},
+	}
+
+	expected, _ := (&structBlockServicer{transactions: 100}).Block(
+		context.Background(),
+		blockRequest,
+	)
+	expectedJSON, err := json.Marshal(expected)
+	assert.NoError(t, err)
+
+	var tests = map[string][]ControllerOption{
+		"default": nil,
+		"flush": {
+			WithFlushInterval(time.Nanosecond),
+		},
+		"flush with compression": {
+			WithFlushInterval(time.Nanosecond),
+			WithCompression(),
+		},
+	}
+
+	for name, options := range tests {
+		t.Run(name, func(t *testing.T) {
+			servicer := &streamingBlockServicer{transactions: 100}
+			ts := httptest.NewServer(NewRouterWithMiddleware(
+				[]Middleware{NewRecoveryMiddleware(nil)},
+				NewBlockAPIController(servicer, a, options...),
+			))
+			defer ts.Close()
+
+			// The streamed response is identical to
+			// the encoded *types.BlockResponse
+			resp, err := http.Post(
+				ts.URL+"/block",
+				"application/json",
+				strings.NewReader(types.PrintStruct(blockRequest)),
+			)
+			assert.NoError(t, err)
+			body, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, resp.Body.Close())
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.JSONEq(t, string(expectedJSON), string(body))
+
+			// Ensure the response can be decoded by the client
+			c := client.NewAPIClient(client.NewConfiguration(ts.URL, "test", nil))
+			response, clientErr, err := c.BlockAPI.Block(context.Background(), blockRequest)
+			assert.NoError(t, err)
+			assert.Nil(t, clientErr)
+			assert.Equal(t, expected, response)
+		})
+	}
+}
+
+func TestStreamBlockError(t *testing.T) {
+	a := newStreamingAsserter(t)
+	servicer := &streamingBlockServicer{
+		transactions: 100,
+		err:          errors.New("database closed"),
+	}
+	ts := httptest.NewServer(NewRouterWithMiddleware(
+		[]Middleware{NewRecoveryMiddleware(nil)},
+		NewBlockAPIController(servicer, a),
+	))
+	defer ts.Close()
+
+	// The response is aborted if the iterator fails
+	c := client.NewAPIClient(client.NewConfiguration(ts.URL, "test", nil))
+	response, clientErr, err := c.BlockAPI.Block(context.Background(), &types.BlockRequest{
+		NetworkIdentifier: streamingNetwork,
+		BlockIdentifier: &types.PartialBlockIdentifier{
+			Index: types.Int64(1),
+		},
+	})
+	assert.Error(t, err)
+	assert.Nil(t, clientErr)
+	assert.Nil(t, response)
+}
+
+// discardResponseWriter is an http.ResponseWriter
+// that discards all writes.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (d *discardResponseWriter) Header() http.Header {
+	return d.header
+}
+
+func (d *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (d *discardResponseWriter) WriteHeader(int) {}
+
+// samplePeakHeap samples the heap size until the returned
+// func is called and then reports the peak heap size.
+func samplePeakHeap(b *testing.B) func() {
+	done := make(chan struct{})
+	peak := make(chan uint64)
+	go func() {
+		var max uint64
+		var stats runtime.MemStats
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			runtime.ReadMemStats(&stats)
+			if stats.HeapAlloc > max {
+				max = stats.HeapAlloc
+			}
+
+			select {
+			case <-done:
+				peak <- max
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		b.ReportMetric(float64(<-peak), "peak-heap-B")
+	}
+}
+
+// BenchmarkBlockResponse compares encoding a block with
+// benchmarkBlockTransactions transactions using the
+// *types.BlockResponse returned by a BlockAPIServicer
+// (struct) and using a StreamingBlockAPIServicer (stream).
+func BenchmarkBlockResponse(b *testing.B) {
+	a := newStreamingAsserter(b)
+	request := types.PrintStruct(&types.BlockRequest{
+		NetworkIdentifier: streamingNetwork,
+		BlockIdentifier: &types.PartialBlockIdentifier{
+			Index: types.Int64(1),
+		},
+	})
+
+	var benchmarks = map[string]BlockAPIServicer{
+		"struct": &structBlockServicer{transactions: benchmarkBlockTransactions},
+		"stream": &streamingBlockServicer{transactions: benchmarkBlockTransactions},
+	}
+
+	for name, servicer := range benchmarks {
+		router := NewRouter(NewBlockAPIController(servicer, a))
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			runtime.GC()
+			stop := samplePeakHeap(b)
+			for i := 0; i < b.N; i++ {
+				router.ServeHTTP(
+					&discardResponseWriter{header: http.Header{}},
+					httptest.NewRequest(http.MethodPost, "/block", strings.NewReader(request)),
+				)
+			}
+			stop()
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// EndpointGroup is a group of Rosetta API endpoints
+// that share request assertion configuration.
+type EndpointGroup string
+
+const (
+	// DataEndpoints are the /network, /account, /block,
+	// and /mempool endpoints.
+	DataEndpoints EndpointGroup = "data"
+
+	// ConstructionEndpoints are the /construction/*
+	// endpoints.
+	ConstructionEndpoints EndpointGroup = "construction"
+
+	// IndexerEndpoints are the /events/* and /search/*
+	// endpoints.
+	IndexerEndpoints EndpointGroup = "indexer"
+
+	// CallEndpoints are the /call endpoints.
+	CallEndpoints EndpointGroup = "call"
+)
+
+// controllerGroups maps the name of each
+// controller to its EndpointGroup.
+var controllerGroups = map[string]EndpointGroup{
+	"NetworkAPI":      DataEndpoints,
+	"AccountAPI":      DataEndpoints,
+	"BlockAPI":        DataEndpoints,
+	"MempoolAPI":      DataEndpoints,
+	"ConstructionAPI": ConstructionEndpoints,
+	"EventsAPI":       IndexerEndpoints,
+	"SearchAPI":       IndexerEndpoints,
+	"CallAPI":         CallEndpoints,
+}
+
+// defaultInvalidRequestError is returned by controllers when
+// a request fails assertion (if no error is provided
+// using WithInvalidRequestError).
+var defaultInvalidRequestError = &types.Error{
+	Code:    0,
+	Message: "invalid request",
+}
+
+// ControllerOption is used to overwrite default values in
+// controller construction. Any ControllerOption not provided
+// falls back to the default value. The same options can be
+// provided to all controllers.
+type ControllerOption func(c *controllerConfig)
+
+// WithoutRequestAssertion disables the assertion of requests
+// to the endpoints in groups (by default, all requests are
+// asserted before they are passed to the servicer). Servicers
+// of relaxed endpoints are responsible for handling any
+// malformed request.
+func WithoutRequestAssertion(groups ...EndpointGroup) ControllerOption {
+	return func(c *controllerConfig) {
+		for _, group := range groups {
+			c.relaxedGroups[group] = struct{}{}
+		}
+	}
+}
+
+// WithInvalidRequestError overrides the *types.Error returned
+// when a request fails assertion. The assertion message is
+// populated in the "error" key of the details of a copy
+// of invalidRequestError.
+func WithInvalidRequestError(invalidRequestError *types.Error) ControllerOption {
+	return func(c *controllerConfig) {
+		c.invalidRequestError = invalidRequestError
+	}
+}
+
+// controllerConfig contains the configuration
+// of a controller.
+type controllerConfig struct {
+	group               EndpointGroup
+	relaxedGroups       map[EndpointGroup]struct{}
+	invalidRequestError *types.Error
+	compression         bool
+	flushInterval       time.Duration
+}
+
+// newControllerConfig returns the *controllerConfig
+// for the controller named controller.
+func newControllerConfig(controller string, options []ControllerOption) *controllerConfig {
+	c := &controllerConfig{
+		group:               controllerGroups[controller],
+		relaxedGroups:       map[EndpointGroup]struct{}{},
+		invalidRequestError: defaultInvalidRequestError,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// assertRequests returns a boolean indicating if the
+// controller should assert requests.
+func (c *controllerConfig) assertRequests() bool {
+	_, relaxed := c.relaxedGroups[c.group]
+	return !relaxed
+}
+
+// invalidRequest returns the *types.Error to return
+// when a request fails assertion with err.
+func (c *controllerConfig) invalidRequest(err error) *types.Error {
+	details := map[string]interface{}{}
+	for k, v := range c.invalidRequestError.Details {
+		details[k] = v
+	}
+	details["error"] = err.Error()
+
+	return &types.Error{
+		Code:        c.invalidRequestError.Code,
+		Message:     c.invalidRequestError.Message,
+		Description: c.invalidRequestError.Description,
+		Retriable:   c.invalidRequestError.Retriable,
+		Details:     details,
+	}
+}
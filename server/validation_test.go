@@ -0,0 +1,149 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+type testConstructionServicer struct {
+	ConstructionAPIServicer
+}
+
+func (testConstructionServicer) ConstructionPreprocess(
+	context.Context,
+	*types.ConstructionPreprocessRequest,
+) (*types.ConstructionPreprocessResponse, *types.Error) {
+	return &types.ConstructionPreprocessResponse{}, nil
+}
+
+type testBlockServicer struct {
+	BlockAPIServicer
+}
+
+func (testBlockServicer) Block(
+	context.Context,
+	*types.BlockRequest,
+) (*types.BlockResponse, *types.Error) {
+	return &types.BlockResponse{}, nil
+}
+
+func TestRequestAssertion(t *testing.T) {
+	a, err := asserter.NewServer(
+		[]string{"Transfer"},
+		false,
+		[]*types.NetworkIdentifier{
+			{
+				Blockchain: "bitcoin",
+				Network:    "mainnet",
+			},
+		},
+		nil,
+		false,
+	)
+	assert.NoError(t, err)
+
+	// Both requests reference an unsupported network
+	preprocessRequest := `{"network_identifier":{"blockchain":"bitcoin","network":"testnet"},"operations":[]}`
+	blockRequest := `{"network_identifier":{"blockchain":"bitcoin","network":"testnet"},"block_identifier":{"index":1}}`
+
+	customError := &types.Error{
+		Code:      10,
+		Message:   "bad request",
+		Retriable: true,
+		Details: map[string]interface{}{
+			"hello": "world",
+		},
+	}
+
+	var tests = map[string]struct {
+		options []ControllerOption
+
+		preprocessStatus int
+		blockError       *types.Error
+	}{
+		"default": {
+			preprocessStatus: http.StatusInternalServerError,
+			blockError:       defaultInvalidRequestError,
+		},
+		"relaxed construction": {
+			options: []ControllerOption{
+				WithoutRequestAssertion(ConstructionEndpoints),
+			},
+			preprocessStatus: http.StatusOK,
+			blockError:       defaultInvalidRequestError,
+		},
+		"relaxed construction with custom error": {
+			options: []ControllerOption{
+				WithoutRequestAssertion(ConstructionEndpoints, IndexerEndpoints),
+				WithInvalidRequestError(customError),
+			},
+			preprocessStatus: http.StatusOK,
+			blockError:       customError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := NewRouter(
+				NewConstructionAPIController(testConstructionServicer{}, a, test.options...),
+				NewBlockAPIController(testBlockServicer{}, a, test.options...),
+			)
+
+			// Check /construction/preprocess
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(
+				rr,
+				httptest.NewRequest(
+					http.MethodPost,
+					"/construction/preprocess",
+					strings.NewReader(preprocessRequest),
+				),
+			)
+			assert.Equal(t, test.preprocessStatus, rr.Code)
+
+			// Check /block
+			rr = httptest.NewRecorder()
+			router.ServeHTTP(
+				rr,
+				httptest.NewRequest(http.MethodPost, "/block", strings.NewReader(blockRequest)),
+			)
+			assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+			var blockErr types.Error
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &blockErr))
+			assert.Equal(t, test.blockError.Code, blockErr.Code)
+			assert.Equal(t, test.blockError.Message, blockErr.Message)
+			assert.Equal(t, test.blockError.Retriable, blockErr.Retriable)
+			assert.Contains(t, blockErr.Details["error"], asserter.ErrRequestedNetworkNotSupported.Error())
+			for k, v := range test.blockError.Details {
+				assert.Equal(t, v, blockErr.Details[k])
+			}
+
+			// Ensure the configured error is not modified
+			assert.NotContains(t, test.blockError.Details, "error")
+		})
+	}
+}
@@ -17,12 +17,12 @@ package database
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"sync"
@@ -30,6 +30,7 @@ import (
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/dgraph-io/badger/v2/options"
+	"golang.org/x/crypto/scrypt"
 
 	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
 	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
@@ -69,6 +70,55 @@ const (
 	// compression setting.
 	DefaultCompressionMode = options.None
 
+	// minValueLogFileSize and maxValueLogFileSize are the
+	// bounds on ValueLogFileSize enforced by BadgerDB.
+	minValueLogFileSize = 1 << 20
+	maxValueLogFileSize = 2 << 30
+
+	// minZSTDCompressionLevel and maxZSTDCompressionLevel
+	// are the bounds on ZSTDCompressionLevel.
+	minZSTDCompressionLevel = 1
+	maxZSTDCompressionLevel = 22
+
+	// tableMemoryMultiplier is the approximate number of bytes of
+	// RAM used for each byte of MaxTableSize and ValueLogFileSize.
+	tableMemoryMultiplier = 10
+
+	// recommendedMinMaxTableSize and recommendedMinLogValueSize are
+	// the smallest sizes returned by RecommendedOptions (smaller
+	// sizes significantly limit the size of database transactions).
+	recommendedMinMaxTableSize = 8 << 20
+	recommendedMinLogValueSize = 2 << 20
+
+	// recommendedMinCompactors and recommendedMaxCompactors are
+	// the number of compactors RecommendedOptions uses for machines
+	// with less and more than recommendedCompactorMemoryMB of memory.
+	recommendedMinCompactors     = 2
+	recommendedMaxCompactors     = 4
+	recommendedCompactorMemoryMB = 4096
+
+	// sstExtension and valueLogExtension are the extensions
+	// of LSM tree and value log files written by BadgerDB.
+	sstExtension      = ".sst"
+	valueLogExtension = ".vlog"
+
+	// encryptionSaltFile is the name of the file in the database
+	// directory that stores the salt used to derive the
+	// encryption key from a passphrase.
+	encryptionSaltFile = "ENCRYPTIONSALT"
+
+	// encryptionSaltLength is the length
+	// of a new encryption salt.
+	encryptionSaltLength = 32
+
+	// scrypt parameters used to derive an encryption key
+	// from a passphrase (recommended for interactive logins
+	// in https://godoc.org/golang.org/x/crypto/scrypt).
+	scryptN         = 1 << 15
+	scryptR         = 8
+	scryptP         = 1
+	scryptKeyLength = 32
+
 	// logModulo determines how often we should print
 	// logs while scanning data.
 	logModulo = 5000
@@ -76,7 +126,7 @@ const (
 	// Default GC settings for reclaiming
 	// space in value logs.
 	defaultGCInterval     = 1 * time.Minute
-	defualtGCDiscardRatio = 0.1
+	defaultGCDiscardRatio = 0.1
 	defaultGCSleep        = 10 * time.Second
 )
 
@@ -85,6 +135,7 @@ const (
 type BadgerDatabase struct {
 	badgerOptions     badger.Options
 	compressorEntries []*encoder.CompressorEntry
+	encoderOptions    []encoder.Option
 
 	pool     *encoder.BufferPool
 	db       *badger.DB
@@ -94,6 +145,25 @@ type BadgerDatabase struct {
 	writer       *utils.MutexMap
 	writerShards int
 
+	// statsNamespaces are the namespaces
+	// broken down in GetDatabaseStats.
+	statsNamespaces  []string
+	statsSampleLimit int
+
+	// encryptionPassphrase is used to derive
+	// the encryption key (if provided).
+	encryptionPassphrase string
+
+	// gcInterval and gcDiscardRatio configure the
+	// periodic garbage collection of value logs (which
+	// is disabled if gcInterval is 0).
+	gcInterval     time.Duration
+	gcDiscardRatio float64
+
+	// gcLock ensures only one garbage collection
+	// runs at a time.
+	gcLock sync.Mutex
+
 	// Track the closed status to ensure we exit garbage
 	// collection when the db closes.
 	closed chan struct{}
@@ -197,6 +267,158 @@ func PerformanceBadgerOptions(dir string) badger.Options {
 	return opts
 }
 
+// EstimatedMemoryUsage returns the approximate amount of RAM (in bytes)
+// used by a BadgerDB with the provided options:
+//
+//	10 * (MaxTableSize + ValueLogFileSize) + IndexCacheSize + BlockCacheSize
+//
+// This formula is derived from our own research (see DefaultBadgerOptions)
+// and is only an approximation.
+func EstimatedMemoryUsage(opts badger.Options) int64 {
+	return tableMemoryMultiplier*(opts.MaxTableSize+opts.ValueLogFileSize) +
+		opts.IndexCacheSize +
+		opts.BlockCacheSize
+}
+
+// RecommendedOptions returns a profile of BadgerOptions for a machine
+// with availableMemoryMB of RAM. The profile targets using half of
+// the available memory (as estimated by EstimatedMemoryUsage):
+// 1/4 for the index cache, 1/8 for the block cache, and the
+// rest for tables (with MaxTableSize 4 times ValueLogFileSize).
+//
+// On machines with very little memory, the estimated usage
+// may exceed this target because table sizes are never set
+// below recommendedMinMaxTableSize and recommendedMinLogValueSize.
+func RecommendedOptions(availableMemoryMB int) []BadgerOption {
+	budget := int64(availableMemoryMB) << 20 / 2 // nolint:gomnd
+	indexCacheSize := budget / 4                 // nolint:gomnd
+	blockCacheSize := budget / 8                 // nolint:gomnd
+
+	// MaxTableSize + ValueLogFileSize == 5 * ValueLogFileSize
+	tableBudget := (budget - indexCacheSize - blockCacheSize) / tableMemoryMultiplier
+	logValueSize := tableBudget / 5 // nolint:gomnd
+	if logValueSize < recommendedMinLogValueSize {
+		logValueSize = recommendedMinLogValueSize
+	}
+	if logValueSize > PerformanceLogValueSize {
+		logValueSize = PerformanceLogValueSize
+	}
+
+	maxTableSize := logValueSize * 4 // nolint:gomnd
+	if maxTableSize < recommendedMinMaxTableSize {
+		maxTableSize = recommendedMinMaxTableSize
+	}
+
+	compactors := recommendedMinCompactors
+	if availableMemoryMB >= recommendedCompactorMemoryMB {
+		compactors = recommendedMaxCompactors
+	}
+
+	return []BadgerOption{
+		WithIndexCacheSize(indexCacheSize),
+		WithBlockCacheSize(blockCacheSize),
+		WithMemTableSize(maxTableSize),
+		WithValueLogFileSize(logValueSize),
+		WithNumCompactors(compactors),
+	}
+}
+
+// validateBadgerOptions returns an error if the provided
+// options contain an invalid or incompatible combination
+// of settings.
+func validateBadgerOptions(opts badger.Options) error {
+	if opts.InMemory && (len(opts.Dir) > 0 || len(opts.ValueDir) > 0) {
+		return errors.New("cannot set directory when running in memory")
+	}
+
+	if opts.InMemory && opts.ReadOnly {
+		return errors.New("cannot open in-memory database in read-only mode")
+	}
+
+	if opts.MaxTableSize <= 0 {
+		return fmt.Errorf("max table size %d must be positive", opts.MaxTableSize)
+	}
+
+	if opts.ValueLogFileSize < minValueLogFileSize || opts.ValueLogFileSize >= maxValueLogFileSize {
+		return fmt.Errorf(
+			"value log file size %d must be in [%d, %d)",
+			opts.ValueLogFileSize,
+			minValueLogFileSize,
+			maxValueLogFileSize,
+		)
+	}
+
+	if opts.NumCompactors < 0 {
+		return fmt.Errorf("number of compactors %d cannot be negative", opts.NumCompactors)
+	}
+
+	if opts.BlockCacheSize < 0 || opts.IndexCacheSize < 0 {
+		return errors.New("cache sizes cannot be negative")
+	}
+
+	if opts.Compression != options.None && opts.BlockCacheSize == 0 {
+		return errors.New("block cache size must be set when using block compression")
+	}
+
+	switch len(opts.EncryptionKey) {
+	case 0, 16, 24, 32: // nolint:gomnd
+	default:
+		return fmt.Errorf(
+			"encryption key must be 16, 24, or 32 bytes (got %d bytes)",
+			len(opts.EncryptionKey),
+		)
+	}
+
+	if len(opts.EncryptionKey) > 0 && opts.BlockCacheSize == 0 {
+		return errors.New("block cache size must be set when using encryption")
+	}
+
+	if opts.EncryptionKeyRotationDuration < 0 {
+		return errors.New("encryption key rotation duration cannot be negative")
+	}
+
+	if opts.Compression == options.ZSTD &&
+		(opts.ZSTDCompressionLevel < minZSTDCompressionLevel ||
+			opts.ZSTDCompressionLevel > maxZSTDCompressionLevel) {
+		return fmt.Errorf(
+			"zstd compression level %d must be in [%d, %d]",
+			opts.ZSTDCompressionLevel,
+			minZSTDCompressionLevel,
+			maxZSTDCompressionLevel,
+		)
+	}
+
+	return nil
+}
+
+// deriveEncryptionKey derives an encryption key from a passphrase
+// using the salt stored in the database directory. If no salt
+// exists, a new salt is created.
+func deriveEncryptionKey(opts badger.Options, passphrase string) ([]byte, error) {
+	saltPath := path.Join(opts.Dir, encryptionSaltFile)
+	salt, err := ioutil.ReadFile(saltPath) // #nosec G304
+	switch {
+	case os.IsNotExist(err) && !opts.ReadOnly:
+		salt = make([]byte, encryptionSaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("%w: unable to generate salt", err)
+		}
+
+		if err := utils.EnsurePathExists(opts.Dir); err != nil {
+			return nil, err
+		}
+
+		err := ioutil.WriteFile(saltPath, salt, os.FileMode(utils.DefaultFilePermissions))
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to store salt", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("%w: unable to load salt", err)
+	}
+
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLength)
+}
+
 // NewBadgerDatabase creates a new BadgerDatabase.
 func NewBadgerDatabase(
 	ctx context.Context,
@@ -211,30 +433,77 @@ func NewBadgerDatabase(
 		pool:          encoder.NewBufferPool(),
 		compress:      true,
 		writerShards:  utils.DefaultShards,
+
+		gcInterval:     defaultGCInterval,
+		gcDiscardRatio: defaultGCDiscardRatio,
 	}
 	for _, opt := range storageOptions {
 		opt(b)
 	}
 
+	if b.gcInterval < 0 {
+		return nil, fmt.Errorf(
+			"%w: garbage collection interval %s cannot be negative",
+			storageErrs.ErrInvalidBadgerOptions,
+			b.gcInterval,
+		)
+	}
+
+	if b.gcInterval > 0 {
+		if err := validateDiscardRatio(b.gcDiscardRatio); err != nil {
+			return nil, fmt.Errorf("%w: %v", storageErrs.ErrInvalidBadgerOptions, err)
+		}
+	}
+
+	if len(b.encryptionPassphrase) > 0 {
+		if len(b.badgerOptions.EncryptionKey) > 0 || b.badgerOptions.InMemory {
+			return nil, fmt.Errorf(
+				"%w: cannot use encryption passphrase with encryption key or in memory",
+				storageErrs.ErrInvalidBadgerOptions,
+			)
+		}
+
+		key, err := deriveEncryptionKey(b.badgerOptions, b.encryptionPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", storageErrs.ErrDeriveEncryptionKeyFailed, err)
+		}
+		b.badgerOptions.EncryptionKey = key
+	}
+
+	if err := validateBadgerOptions(b.badgerOptions); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrInvalidBadgerOptions, err)
+	}
+
 	// Initialize utis.MutexMap used to track granular
 	// write transactions.
 	b.writer = utils.NewMutexMap(b.writerShards)
 
 	db, err := badger.Open(b.badgerOptions)
+	if errors.Is(err, badger.ErrEncryptionKeyMismatch) {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrEncryptionKeyMismatch, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", storageErrs.ErrDatabaseOpenFailed, err)
 	}
 	b.db = db
 
-	encoder, err := encoder.NewEncoder(b.compressorEntries, b.pool, b.compress)
+	encoder, err := encoder.NewEncoder(
+		b.compressorEntries,
+		b.pool,
+		b.compress,
+		b.encoderOptions...,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", storageErrs.ErrCompressorLoadFailed, err)
 	}
 	b.encoder = encoder
 
 	// Start periodic ValueGC goroutine (up to user of BadgerDB to call
-	// periodically to reclaim value logs on-disk).
-	go b.periodicGC(ctx)
+	// periodically to reclaim value logs on-disk). Value logs cannot
+	// be rewritten in read-only mode.
+	if !b.badgerOptions.ReadOnly && b.gcInterval > 0 {
+		go b.periodicGC(ctx)
+	}
 
 	return b, nil
 }
@@ -253,7 +522,7 @@ func (b *BadgerDatabase) Close(ctx context.Context) error {
 }
 
 // periodicGC attempts to reclaim storage every
-// defaultGCInterval.
+// gcInterval.
 //
 // Inspired by:
 // https://github.com/ipfs/go-ds-badger/blob/a69f1020ba3954680900097e0c9d0181b88930ad/datastore.go#L173-L199
@@ -274,13 +543,15 @@ func (b *BadgerDatabase) periodicGC(ctx context.Context) {
 			return
 		case <-gcTimeout.C:
 			start := time.Now()
-			err := b.db.RunValueLogGC(defualtGCDiscardRatio)
+			b.gcLock.Lock()
+			err := b.db.RunValueLogGC(b.gcDiscardRatio)
+			b.gcLock.Unlock()
 			switch err {
 			case badger.ErrNoRewrite, badger.ErrRejected:
 				// No rewrite means we've fully garbage collected.
 				// Rejected means someone else is running a GC
 				// or we're closing.
-				gcTimeout.Reset(defaultGCInterval)
+				gcTimeout.Reset(b.gcInterval)
 			case nil:
 				// Nil error means that we've successfully garbage
 				// collected. We should sleep instead of waiting
@@ -291,17 +562,160 @@ func (b *BadgerDatabase) periodicGC(ctx context.Context) {
 			default:
 				// Not much we can do on a random error but log it and continue.
 				log.Printf("error during a GC cycle: %s\n", err.Error())
-				gcTimeout.Reset(defaultGCInterval)
+				gcTimeout.Reset(b.gcInterval)
 			}
 		}
 	}
 }
 
+// validateDiscardRatio returns an error if a
+// discard ratio is not in (0, 1).
+func validateDiscardRatio(discardRatio float64) error {
+	if discardRatio <= 0 || discardRatio >= 1 {
+		return fmt.Errorf("%w: %f", storageErrs.ErrInvalidDiscardRatio, discardRatio)
+	}
+
+	return nil
+}
+
+// RunGarbageCollection reclaims space in the value logs by
+// rewriting each value log file where at least discardRatio of
+// the file can be discarded (deleted, expired, or overwritten
+// values). BadgerDB never reclaims this space when values are
+// deleted, so this should be called after pruning large amounts
+// of data. It returns once there are no more files to rewrite.
+//
+// It is safe to call RunGarbageCollection while transactions
+// are active (and while periodic garbage collection is enabled).
+func (b *BadgerDatabase) RunGarbageCollection(
+	ctx context.Context,
+	discardRatio float64,
+) error {
+	if err := validateDiscardRatio(discardRatio); err != nil {
+		return err
+	}
+
+	if b.badgerOptions.ReadOnly {
+		return storageErrs.ErrReadOnlyDatabase
+	}
+
+	b.gcLock.Lock()
+	defer b.gcLock.Unlock()
+
+	for ctx.Err() == nil {
+		start := time.Now()
+		err := b.db.RunValueLogGC(discardRatio)
+		if errors.Is(err, badger.ErrNoRewrite) {
+			// No rewrite means there is nothing
+			// (else) to garbage collect.
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrGarbageCollectionFailed, err)
+		}
+
+		log.Printf("successful value log garbage collection (%s)", time.Since(start))
+	}
+
+	return ctx.Err()
+}
+
 // Encoder returns the BadgerDatabase encoder.
 func (b *BadgerDatabase) Encoder() *encoder.Encoder {
 	return b.encoder
 }
 
+// filesSize returns the total size of all files
+// in a directory with a particular extension.
+func filesSize(dir string, extension string) (int64, error) {
+	matches, err := filepath.Glob(path.Join(dir, "*"+extension))
+	if err != nil {
+		return -1, err
+	}
+
+	size := int64(0)
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			// The file may have been removed by compaction
+			// or garbage collection since we listed it.
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return -1, err
+		}
+
+		size += info.Size()
+	}
+
+	return size, nil
+}
+
+// GetDatabaseStats returns the size of all LSM tree (.sst)
+// and value log (.vlog) files on disk. When running in memory,
+// the sizes reported by BadgerDB are returned instead (these
+// are only refreshed periodically).
+//
+// If stats namespaces are configured, the keys in each namespace
+// are scanned without reading any values (using the size estimated
+// by BadgerDB for each entry).
+func (b *BadgerDatabase) GetDatabaseStats(ctx context.Context) (*DatabaseStats, error) {
+	lsmSize, valueLogSize := b.db.Size()
+	if !b.badgerOptions.InMemory {
+		var err error
+		lsmSize, err = filesSize(b.badgerOptions.Dir, sstExtension)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", storageErrs.ErrDatabaseStatsFailed, err)
+		}
+
+		valueLogSize, err = filesSize(b.badgerOptions.ValueDir, valueLogExtension)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", storageErrs.ErrDatabaseStatsFailed, err)
+		}
+	}
+
+	namespaces, err := namespaceStats(
+		ctx,
+		b.statsNamespaces,
+		b.statsSampleLimit,
+		b.scanNamespace,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrDatabaseStatsFailed, err)
+	}
+
+	return &DatabaseStats{
+		LSMSize:      lsmSize,
+		ValueLogSize: valueLogSize,
+		Namespaces:   namespaces,
+	}, nil
+}
+
+// scanNamespace is a namespaceScanner that only
+// iterates over keys.
+func (b *BadgerDatabase) scanNamespace(
+	ctx context.Context,
+	prefix []byte,
+	visit func(size int64) bool,
+) error {
+	txn := b.db.NewTransaction(false)
+	defer txn.Discard()
+
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	opts.Prefix = prefix
+	it := txn.NewIterator(opts)
+	defer it.Close()
+	for it.Rewind(); it.Valid(); it.Next() {
+		if !visit(it.Item().EstimatedSize()) {
+			break
+		}
+	}
+
+	return nil
+}
+
 // BadgerTransaction is a wrapper around a Badger
 // DB transaction that implements the DatabaseTransaction
 // interface.
@@ -427,17 +841,43 @@ func (b *BadgerTransaction) Set(
 	value []byte,
 	reclaimValue bool,
 ) error {
+	return b.setEntry(badger.NewEntry(key, value), reclaimValue)
+}
+
+// SetWithTTL changes the value of the key to the value within
+// a transaction and deletes the key once the ttl has elapsed.
+// BadgerDB stores expiration times with second precision, so
+// a key may remain visible for up to a second after its ttl.
+func (b *BadgerTransaction) SetWithTTL(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	ttl time.Duration,
+	reclaimValue bool,
+) error {
+	if ttl <= 0 {
+		return fmt.Errorf("%w: %s", storageErrs.ErrInvalidTTL, ttl)
+	}
+
+	return b.setEntry(badger.NewEntry(key, value).WithTTL(ttl), reclaimValue)
+}
+
+func (b *BadgerTransaction) setEntry(entry *badger.Entry, reclaimValue bool) error {
 	b.rwLock.Lock()
 	defer b.rwLock.Unlock()
 
+	if b.db.badgerOptions.ReadOnly {
+		return storageErrs.ErrReadOnlyDatabase
+	}
+
 	if reclaimValue {
 		b.buffersToReclaim = append(
 			b.buffersToReclaim,
-			bytes.NewBuffer(value),
+			bytes.NewBuffer(entry.Value),
 		)
 	}
 
-	return b.txn.Set(key, value)
+	return b.txn.SetEntry(entry)
 }
 
 // Get accesses the value of the key within a transaction.
@@ -473,6 +913,10 @@ func (b *BadgerTransaction) Delete(ctx context.Context, key []byte) error {
 	b.rwLock.Lock()
 	defer b.rwLock.Unlock()
 
+	if b.db.badgerOptions.ReadOnly {
+		return storageErrs.ErrReadOnlyDatabase
+	}
+
 	return b.txn.Delete(key)
 }
 
@@ -485,6 +929,31 @@ func (b *BadgerTransaction) Scan(
 	worker func([]byte, []byte) error,
 	logEntries bool,
 	reverse bool, // reverse == true means greatest to least
+) (int, error) {
+	return b.scan(ctx, prefix, seekStart, worker, logEntries, reverse, false)
+}
+
+// ScanKeys calls a worker for each key in a scan
+// without reading any values (from the value log).
+func (b *BadgerTransaction) ScanKeys(
+	ctx context.Context,
+	prefix []byte,
+	seekStart []byte,
+	worker func([]byte, []byte) error,
+	logEntries bool,
+	reverse bool, // reverse == true means greatest to least
+) (int, error) {
+	return b.scan(ctx, prefix, seekStart, worker, logEntries, reverse, true)
+}
+
+func (b *BadgerTransaction) scan(
+	ctx context.Context,
+	prefix []byte,
+	seekStart []byte,
+	worker func([]byte, []byte) error,
+	logEntries bool,
+	reverse bool, // reverse == true means greatest to least
+	keysOnly bool,
 ) (int, error) {
 	b.rwLock.RLock()
 	defer b.rwLock.RUnlock()
@@ -492,18 +961,27 @@ func (b *BadgerTransaction) Scan(
 	entries := 0
 	opts := badger.DefaultIteratorOptions
 	opts.Reverse = reverse
+	opts.PrefetchValues = !keysOnly
 	it := b.txn.NewIterator(opts)
 	defer it.Close()
 	for it.Seek(seekStart); it.ValidForPrefix(prefix); it.Next() {
 		item := it.Item()
 		k := item.Key()
-		err := item.Value(func(v []byte) error {
-			if err := worker(k, v); err != nil {
-				return fmt.Errorf("%w: worker failed for key %s", err, string(k))
-			}
 
-			return nil
-		})
+		var err error
+		if keysOnly {
+			if err = worker(k, nil); err != nil {
+				err = fmt.Errorf("%w: worker failed for key %s", err, string(k))
+			}
+		} else {
+			err = item.Value(func(v []byte) error {
+				if err := worker(k, v); err != nil {
+					return fmt.Errorf("%w: worker failed for key %s", err, string(k))
+				}
+
+				return nil
+			})
+		}
 		if err != nil {
 			return -1, fmt.Errorf("%w: unable to get value for key %s", err, string(k))
 		}
@@ -517,47 +995,157 @@ func (b *BadgerTransaction) Scan(
 	return entries, nil
 }
 
-func decompressAndSave(
-	encoder *encoder.Encoder,
-	namespace string,
-	tmpDir string,
-	k []byte,
-	v []byte,
-) (float64, float64, error) {
-	// We use a compressor.DecodeRaw here because the v may be
-	// encoded using dictionary compression.
-	decompressed, err := encoder.DecodeRaw(namespace, v)
-	if err != nil {
-		return -1, -1, fmt.Errorf("%w %s: %v", storageErrs.ErrDecompressFailed, string(k), err)
+// ScanPage calls a worker for each item in a
+// page of a scan.
+func (b *BadgerTransaction) ScanPage(
+	ctx context.Context,
+	prefix []byte,
+	cursor []byte,
+	limit int,
+	worker func([]byte, []byte) error,
+	reverse bool, // reverse == true means greatest to least
+) ([]byte, error) {
+	return scanPage(ctx, b, prefix, cursor, limit, worker, reverse)
+}
+
+// BadgerBulkWriter is a wrapper around a Badger
+// DB WriteBatch that implements the BulkWriter
+// interface.
+type BadgerBulkWriter struct {
+	db    *BadgerDatabase
+	batch *badger.WriteBatch
+
+	lock   sync.Mutex
+	closed bool
+
+	// Like a BadgerTransaction, we MUST wait
+	// to reclaim any memory until the WriteBatch
+	// is flushed or canceled.
+	buffersToReclaim []*bytes.Buffer
+}
+
+// BulkWrite creates a new BadgerBulkWriter.
+func (b *BadgerDatabase) BulkWrite(
+	ctx context.Context,
+) BulkWriter {
+	b.writer.GLock()
+
+	writer := &BadgerBulkWriter{
+		db:               b,
+		buffersToReclaim: []*bytes.Buffer{},
 	}
 
-	err = ioutil.WriteFile(
-		path.Join(tmpDir, types.Hash(string(k))),
-		decompressed,
-		os.FileMode(utils.DefaultFilePermissions),
-	)
+	// We can't create a WriteBatch in read-only mode
+	// (all writes will return ErrReadOnlyDatabase).
+	if !b.badgerOptions.ReadOnly {
+		writer.batch = b.db.NewWriteBatch()
+	}
+
+	return writer
+}
+
+// close reclaims all allocated buffers and releases
+// the global write lock. This must be called while
+// holding the lock.
+func (b *BadgerBulkWriter) close() {
+	for _, buf := range b.buffersToReclaim {
+		b.db.pool.Put(buf)
+	}
+
+	// Ensure we don't attempt to reclaim twice.
+	b.buffersToReclaim = nil
+	b.closed = true
+	b.db.writer.GUnlock()
+}
+
+// Set adds a key-value pair to the WriteBatch.
+func (b *BadgerBulkWriter) Set(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	reclaimValue bool,
+) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		return storageErrs.ErrBulkWriterClosed
+	}
+
+	if b.db.badgerOptions.ReadOnly {
+		return storageErrs.ErrReadOnlyDatabase
+	}
+
+	if reclaimValue {
+		b.buffersToReclaim = append(
+			b.buffersToReclaim,
+			bytes.NewBuffer(value),
+		)
+	}
+
+	return b.batch.Set(key, value)
+}
+
+// Delete removes the key in the WriteBatch.
+func (b *BadgerBulkWriter) Delete(ctx context.Context, key []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		return storageErrs.ErrBulkWriterClosed
+	}
+
+	if b.db.badgerOptions.ReadOnly {
+		return storageErrs.ErrReadOnlyDatabase
+	}
+
+	return b.batch.Delete(key)
+}
+
+// Flush commits all writes in the WriteBatch.
+func (b *BadgerBulkWriter) Flush(context.Context) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		return storageErrs.ErrBulkWriterClosed
+	}
+
+	var err error
+	if b.batch != nil {
+		err = b.batch.Flush()
+	}
+	b.close()
 	if err != nil {
-		return -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrDecompressSaveUnsuccessful, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrBulkWriteFailed, err)
+	}
+
+	return nil
+}
+
+// Cancel stops the WriteBatch.
+func (b *BadgerBulkWriter) Cancel(context.Context) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		return
 	}
 
-	return float64(len(decompressed)), float64(len(v)), nil
+	if b.batch != nil {
+		b.batch.Cancel()
+	}
+	b.close()
 }
 
-func decompressAndEncode(
-	path string,
+// compressSample returns the size of a decompressed
+// sample and its size when compressed without and with
+// the dictionary for namespace.
+func compressSample(
+	decompressed []byte,
 	namespace string,
 	encoder *encoder.Encoder,
 ) (float64, float64, float64, error) {
-	decompressed, err := ioutil.ReadFile(path) // #nosec G304
-	if err != nil {
-		return -1, -1, -1, fmt.Errorf(
-			"%w for file %s: %v",
-			storageErrs.ErrLoadFileUnsuccessful,
-			path,
-			err,
-		)
-	}
-
 	normalCompress, err := encoder.EncodeRaw("", decompressed)
 	if err != nil {
 		return -1, -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrCompressNormalFailed, err)
@@ -655,90 +1243,52 @@ func BadgerTrain(
 	}
 	defer badgerDb.Close(ctx)
 
-	// Create directory to store uncompressed files for training
-	tmpDir, err := utils.CreateTempDir()
+	samples, err := SampleRecords(ctx, badgerDb, namespace, maxEntries)
 	if err != nil {
-		return -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrCreateTempDirectoryFailed, err)
+		return -1, -1, err
 	}
-	defer utils.RemoveTempDir(tmpDir)
-
-	// We must use a restricted namespace or we will inadvertently
-	// fetch all namespaces that contain the namespace we care about.
-	restrictedNamespace := fmt.Sprintf("%s/", namespace)
 
 	totalUncompressedSize := float64(0)
 	totalDiskSize := float64(0)
-	entriesSeen := 0
-	txn := badgerDb.ReadTransaction(ctx)
-	defer txn.Discard(ctx)
-	_, err = txn.Scan(
-		ctx,
-		[]byte(restrictedNamespace),
-		[]byte(restrictedNamespace),
-		func(k []byte, v []byte) error {
-			decompressedSize, diskSize, err := decompressAndSave(
-				badgerDb.Encoder(),
-				namespace,
-				tmpDir,
-				k,
-				v,
-			)
-			if err != nil {
-				return fmt.Errorf("%w: unable to decompress and save", err)
-			}
-
-			totalUncompressedSize += decompressedSize
-			totalDiskSize += diskSize
-			entriesSeen++
-
-			if entriesSeen > maxEntries-1 && maxEntries != -1 {
-				return storageErrs.ErrMaxEntries
-			}
-
-			return nil
-		},
-		true,
-		false,
-	)
-	if err != nil && !errors.Is(err, storageErrs.ErrMaxEntries) {
-		return -1, -1, fmt.Errorf("%w for %s: %v", storageErrs.ErrScanFailed, namespace, err)
-	}
+	for _, sample := range samples {
+		// We re-compress each sample with the existing
+		// encoder to determine its size on disk.
+		compressed, err := badgerDb.Encoder().EncodeRaw(namespace, sample)
+		if err != nil {
+			return -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrCompressWithDictFailed, err)
+		}
 
-	if entriesSeen == 0 {
-		return -1, -1, fmt.Errorf("%w %s", storageErrs.ErrNoEntriesFoundInNamespace, namespace)
+		totalUncompressedSize += float64(len(sample))
+		totalDiskSize += float64(len(compressed))
 	}
 
 	log.Printf(
 		"found %d entries for %s (average uncompressed size: %fB)\n",
-		entriesSeen,
+		len(samples),
 		namespace,
-		totalUncompressedSize/float64(entriesSeen),
+		totalUncompressedSize/float64(len(samples)),
 	)
 
 	log.Printf(
 		"found %d entries for %s (average disk size: %fB)\n",
-		entriesSeen,
+		len(samples),
 		namespace,
-		totalDiskSize/float64(entriesSeen),
+		totalDiskSize/float64(len(samples)),
 	)
 
-	// Invoke ZSTD
+	dict, err := encoder.TrainDictionary(ctx, namespace, samples, 0)
+	if err != nil {
+		return -1, -1, err
+	}
+
 	dictPath := path.Clean(output)
 	log.Printf("creating dictionary %s\n", dictPath)
-	cmd := exec.Command(
-		"zstd",
-		"--train",
-		"-r",
-		tmpDir,
-		"-o",
+	if err := ioutil.WriteFile(
 		dictPath,
-	) // #nosec G204
-	if err := cmd.Start(); err != nil {
-		return -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrInvokeZSTDFailed, err)
-	}
-
-	if err := cmd.Wait(); err != nil {
-		return -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrTrainZSTDFailed, err)
+		dict,
+		os.FileMode(utils.DefaultFilePermissions),
+	); err != nil {
+		return -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrSaveDictFailed, err)
 	}
 
 	encoder, err := encoder.NewEncoder([]*encoder.CompressorEntry{
@@ -754,34 +1304,20 @@ func BadgerTrain(
 	sizeUncompressed := float64(0)
 	sizeNormal := float64(0)
 	sizeDictionary := float64(0)
-	err = filepath.Walk(tmpDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if info.IsDir() {
-			return nil
-		}
-
-		decompressed, normalCompress, dictCompress, err := decompressAndEncode(
-			path,
+	for _, sample := range samples {
+		decompressed, normalCompress, dictCompress, err := compressSample(
+			sample,
 			namespace,
 			encoder,
 		)
 		if err != nil {
-			return fmt.Errorf("%w: unable to decompress and encode", err)
+			return -1, -1, fmt.Errorf("%w: unable to compress sample", err)
 		}
 
 		sizeUncompressed += decompressed
 		sizeNormal += normalCompress
 		sizeDictionary += dictCompress
-
-		return nil
-	})
-	if err != nil {
-		return -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrWalkFilesFailed, err)
 	}
-
 	log.Printf(
 		"[IN SAMPLE] Total Size Uncompressed: %fMB",
 		utils.BtoMb(sizeUncompressed),
@@ -814,7 +1350,7 @@ func BadgerTrain(
 		ctx,
 		badgerDb,
 		namespace,
-		restrictedNamespace,
+		fmt.Sprintf("%s%s", namespace, namespaceSeparator),
 		encoder,
 	)
 }
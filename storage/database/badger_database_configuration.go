@@ -15,7 +15,10 @@
 package database
 
 import (
+	"time"
+
 	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/options"
 
 	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
 )
@@ -41,6 +44,37 @@ func WithoutCompression() BadgerOption {
 	}
 }
 
+// WithDictionary enables zstd compression and sets the
+// dictionary used to compress records in a namespace (see
+// encoder.WithDictionary).
+func WithDictionary(namespace string, dict []byte) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.compress = true
+		b.encoderOptions = append(b.encoderOptions, encoder.WithDictionary(namespace, dict))
+	}
+}
+
+// WithCodec encodes all records in a namespace with the
+// provided encoder.Codec. Records previously written to the
+// namespace can still be decoded after the Codec is changed.
+func WithCodec(namespace string, codec encoder.Codec) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.encoderOptions = append(b.encoderOptions, encoder.WithCodec(namespace, codec))
+	}
+}
+
+// WithGarbageCollection overrides how often value log
+// garbage collection is run in the background and the
+// discardRatio used (see RunGarbageCollection). If interval
+// is 0, garbage collection is only run when
+// RunGarbageCollection is called.
+func WithGarbageCollection(interval time.Duration, discardRatio float64) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.gcInterval = interval
+		b.gcDiscardRatio = discardRatio
+	}
+}
+
 // WithIndexCacheSize override the DefaultIndexCacheSize
 // setting for the BadgerDB. The size here is in bytes.
 // If you provide custom BadgerDB settings, do not use this
@@ -68,3 +102,117 @@ func WithWriterShards(shards int) BadgerOption {
 		b.writerShards = shards
 	}
 }
+
+// WithBlockCompression sets the compression used for
+// SSTable blocks (and the level used if the compression
+// is options.ZSTD). This is distinct from the zstd compression
+// of values configured with WithCompressorEntries. Block
+// compression requires a BlockCacheSize > 0.
+func WithBlockCompression(compression options.CompressionType, level int) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.badgerOptions.Compression = compression
+		b.badgerOptions.ZSTDCompressionLevel = level
+	}
+}
+
+// WithMemTableSize overrides the size of each memtable
+// (and SSTable) in bytes. The largest transaction that
+// can be committed is ~15% of this size.
+func WithMemTableSize(size int64) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.badgerOptions.MaxTableSize = size
+	}
+}
+
+// WithValueLogFileSize overrides the size of each
+// value log file in bytes. This must be in [1 MB, 2 GB).
+func WithValueLogFileSize(size int64) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.badgerOptions.ValueLogFileSize = size
+	}
+}
+
+// WithNumCompactors overrides the number of
+// goroutines used to run compactions.
+func WithNumCompactors(compactors int) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.badgerOptions.NumCompactors = compactors
+	}
+}
+
+// WithBlockCacheSize overrides the DefaultBlockCacheSize
+// setting for the BadgerDB. The size here is in bytes.
+func WithBlockCacheSize(size int64) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.badgerOptions.BlockCacheSize = size
+	}
+}
+
+// WithInMemory stores all data in memory instead of in the
+// provided directory. All data is lost when the database is closed.
+func WithInMemory() BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.badgerOptions.InMemory = true
+		b.badgerOptions.Dir = ""
+		b.badgerOptions.ValueDir = ""
+	}
+}
+
+// WithStatsNamespaces enables counting the number of keys
+// (and their size) in each namespace when calling
+// GetDatabaseStats. At most sampleLimit keys are counted in
+// each namespace (0 means there is no limit), larger
+// namespaces are reported as Truncated.
+func WithStatsNamespaces(namespaces []string, sampleLimit int) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.statsNamespaces = namespaces
+		b.statsSampleLimit = sampleLimit
+	}
+}
+
+// WithReadOnly opens an existing BadgerDB in read-only
+// mode. All reads work as usual but any write returns
+// ErrReadOnlyDatabase.
+//
+// Multiple processes can open the same directory in
+// read-only mode at once. However, BadgerDB does not
+// allow opening a directory in read-only mode while
+// another process holds it open in read-write mode
+// (opening will fail until that process exits). Read-only
+// mode is not supported on Windows.
+func WithReadOnly() BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.badgerOptions.ReadOnly = true
+	}
+}
+
+// WithEncryptionKey encrypts all data at rest with the provided
+// AES key (which must be 16, 24, or 32 bytes long). Encryption
+// requires a BlockCacheSize > 0. Once a database is created
+// with an encryption key, it can only be opened with the
+// same key.
+func WithEncryptionKey(key []byte) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.badgerOptions.EncryptionKey = key
+	}
+}
+
+// WithEncryptionPassphrase encrypts all data at rest with a
+// 32-byte AES key derived from the provided passphrase (using
+// scrypt and a random salt stored in the database directory).
+// This cannot be used with WithEncryptionKey or WithInMemory.
+func WithEncryptionPassphrase(passphrase string) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.encryptionPassphrase = passphrase
+	}
+}
+
+// WithEncryptionKeyRotationDuration overrides how often
+// BadgerDB rotates the data keys used to encrypt data (data
+// keys are encrypted with the key provided by WithEncryptionKey
+// or WithEncryptionPassphrase).
+func WithEncryptionKeyRotationDuration(duration time.Duration) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.badgerOptions.EncryptionKeyRotationDuration = duration
+	}
+}
@@ -16,14 +16,19 @@ package database
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"path"
 	"testing"
 
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/options"
 	"github.com/lucasjones/reggen"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
 
@@ -366,3 +371,729 @@ func TestBadgerTrain_Limit(t *testing.T) {
 	assert.True(t, oldSize2 > newSize2)
 	assert.True(t, newSize > newSize2)
 }
+
+func TestBadgerOptions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("options applied", func(t *testing.T) {
+		newDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+		defer utils.RemoveTempDir(newDir)
+
+		database, err := NewBadgerDatabase(
+			ctx,
+			newDir,
+			WithIndexCacheSize(TinyIndexCacheSize),
+			WithBlockCacheSize(16<<20),
+			WithBlockCompression(options.ZSTD, 3),
+			WithMemTableSize(32<<20),
+			WithValueLogFileSize(8<<20),
+			WithNumCompactors(3),
+		)
+		assert.NoError(t, err)
+		defer database.Close(ctx)
+
+		opts := database.(*BadgerDatabase).badgerOptions
+		assert.Equal(t, options.ZSTD, opts.Compression)
+		assert.Equal(t, 3, opts.ZSTDCompressionLevel)
+		assert.Equal(t, int64(16<<20), opts.BlockCacheSize)
+		assert.Equal(t, int64(32<<20), opts.MaxTableSize)
+		assert.Equal(t, int64(8<<20), opts.ValueLogFileSize)
+		assert.Equal(t, 3, opts.NumCompactors)
+		assert.Equal(t, int64(TinyIndexCacheSize), opts.IndexCacheSize)
+
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("hola"), true))
+		assert.NoError(t, txn.Commit(ctx))
+
+		txn = database.ReadTransaction(ctx)
+		exists, value, err := txn.Get(ctx, []byte("hello"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []byte("hola"), value)
+		txn.Discard(ctx)
+	})
+
+	t.Run("in memory", func(t *testing.T) {
+		database, err := NewBadgerDatabase(
+			ctx,
+			"",
+			WithIndexCacheSize(TinyIndexCacheSize),
+			WithInMemory(),
+		)
+		assert.NoError(t, err)
+		defer database.Close(ctx)
+
+		opts := database.(*BadgerDatabase).badgerOptions
+		assert.True(t, opts.InMemory)
+		assert.Empty(t, opts.Dir)
+		assert.Empty(t, opts.ValueDir)
+
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("hola"), true))
+		assert.NoError(t, txn.Commit(ctx))
+
+		txn = database.ReadTransaction(ctx)
+		exists, value, err := txn.Get(ctx, []byte("hello"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []byte("hola"), value)
+		txn.Discard(ctx)
+	})
+
+	var tests = map[string]struct {
+		options []BadgerOption
+	}{
+		"in memory with custom dir": {
+			options: []BadgerOption{
+				WithInMemory(),
+				WithCustomSettings(badger.DefaultOptions("/tmp").WithInMemory(true)),
+			},
+		},
+		"compression without block cache": {
+			options: []BadgerOption{
+				WithBlockCompression(options.Snappy, 0),
+			},
+		},
+		"invalid zstd level": {
+			options: []BadgerOption{
+				WithBlockCacheSize(16 << 20),
+				WithBlockCompression(options.ZSTD, 23),
+			},
+		},
+		"value log file too small": {
+			options: []BadgerOption{
+				WithValueLogFileSize(1 << 10),
+			},
+		},
+		"value log file too large": {
+			options: []BadgerOption{
+				WithValueLogFileSize(2 << 30),
+			},
+		},
+		"invalid mem table size": {
+			options: []BadgerOption{
+				WithMemTableSize(0),
+			},
+		},
+		"negative compactors": {
+			options: []BadgerOption{
+				WithNumCompactors(-1),
+			},
+		},
+		"negative block cache size": {
+			options: []BadgerOption{
+				WithBlockCacheSize(-1),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			newDir, err := utils.CreateTempDir()
+			assert.NoError(t, err)
+			defer utils.RemoveTempDir(newDir)
+
+			database, err := NewBadgerDatabase(ctx, newDir, test.options...)
+			assert.Nil(t, database)
+			assert.True(t, errors.Is(err, storageErrs.ErrInvalidBadgerOptions))
+		})
+	}
+}
+
+func TestRecommendedOptions(t *testing.T) {
+	var tests = map[string]struct {
+		availableMemoryMB int
+
+		maxTableSize     int64
+		valueLogFileSize int64
+		numCompactors    int
+	}{
+		"tiny": {
+			availableMemoryMB: 64,
+			maxTableSize:      recommendedMinMaxTableSize,
+			valueLogFileSize:  recommendedMinLogValueSize,
+			numCompactors:     recommendedMinCompactors,
+		},
+		"medium": {
+			availableMemoryMB: 2000,
+			maxTableSize:      (((1000 << 20) * 5 / 8) / 10 / 5) * 4,
+			valueLogFileSize:  ((1000 << 20) * 5 / 8) / 10 / 5,
+			numCompactors:     recommendedMinCompactors,
+		},
+		"large": {
+			availableMemoryMB: 64000,
+			maxTableSize:      PerformanceLogValueSize * 4,
+			valueLogFileSize:  PerformanceLogValueSize,
+			numCompactors:     recommendedMaxCompactors,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &BadgerDatabase{badgerOptions: DefaultBadgerOptions("")}
+			for _, opt := range RecommendedOptions(test.availableMemoryMB) {
+				opt(b)
+			}
+
+			opts := b.badgerOptions
+			assert.NoError(t, validateBadgerOptions(opts))
+			assert.Equal(t, test.maxTableSize, opts.MaxTableSize)
+			assert.Equal(t, test.valueLogFileSize, opts.ValueLogFileSize)
+			assert.Equal(t, test.numCompactors, opts.NumCompactors)
+
+			// All profiles other than the tiny profile should
+			// fit within half of the available memory.
+			if test.availableMemoryMB > 64 {
+				assert.LessOrEqual(
+					t,
+					EstimatedMemoryUsage(opts),
+					int64(test.availableMemoryMB)<<20/2,
+				)
+			}
+		})
+	}
+}
+
+func TestRecommendedOptions_TinySync(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := NewBadgerDatabase(ctx, newDir, RecommendedOptions(64)...)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	// Simulate a small sync where each block (and its
+	// transactions) is stored in a single database transaction.
+	blocks := 500
+	transactionsPerBlock := 20
+	for i := 0; i < blocks; i++ {
+		txn := database.Transaction(ctx)
+		blockKey := []byte(fmt.Sprintf("block/%06d", i))
+		assert.NoError(t, txn.Set(ctx, blockKey, []byte(fmt.Sprintf("hash-%d", i)), true))
+		for j := 0; j < transactionsPerBlock; j++ {
+			key := []byte(fmt.Sprintf("transaction/%06d/%03d", i, j))
+			value := make([]byte, 512)
+			for k := range value {
+				value[k] = byte(i + j + k)
+			}
+
+			assert.NoError(t, txn.Set(ctx, key, value, true))
+		}
+		assert.NoError(t, txn.Commit(ctx))
+	}
+
+	txn := database.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+
+	count, err := txn.Scan(
+		ctx,
+		[]byte("block/"),
+		[]byte("block/"),
+		func(k []byte, v []byte) error {
+			return nil
+		},
+		false,
+		false,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks, count)
+
+	count, err = txn.Scan(
+		ctx,
+		[]byte("transaction/"),
+		[]byte("transaction/"),
+		func(k []byte, v []byte) error {
+			return nil
+		},
+		false,
+		false,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks*transactionsPerBlock, count)
+
+	exists, value, err := txn.Get(ctx, []byte(fmt.Sprintf("block/%06d", blocks-1)))
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, []byte(fmt.Sprintf("hash-%d", blocks-1)), value)
+}
+
+const benchmarkInserts = 100000
+
+func benchmarkBadgerInserts(
+	b *testing.B,
+	insert func(ctx context.Context, database Database, keys [][]byte, value []byte) error,
+) {
+	ctx := context.Background()
+
+	keys := make([][]byte, benchmarkInserts)
+	for i := 0; i < benchmarkInserts; i++ {
+		keys[i] = []byte(fmt.Sprintf("bal/%020d", i))
+	}
+	value := make([]byte, 32)
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		newDir, err := utils.CreateTempDir()
+		assert.NoError(b, err)
+
+		database, err := newTestBadgerDatabase(ctx, newDir)
+		assert.NoError(b, err)
+		b.StartTimer()
+
+		assert.NoError(b, insert(ctx, database, keys, value))
+
+		b.StopTimer()
+		assert.NoError(b, database.Close(ctx))
+		utils.RemoveTempDir(newDir)
+		b.StartTimer()
+	}
+}
+
+func BenchmarkBadgerTransactionInserts(b *testing.B) {
+	benchmarkBadgerInserts(
+		b,
+		func(ctx context.Context, database Database, keys [][]byte, value []byte) error {
+			txn := database.Transaction(ctx)
+			defer txn.Discard(ctx)
+
+			for _, key := range keys {
+				if err := txn.Set(ctx, key, value, false); err != nil {
+					return err
+				}
+			}
+
+			return txn.Commit(ctx)
+		},
+	)
+}
+
+func BenchmarkBadgerBulkWriteInserts(b *testing.B) {
+	benchmarkBadgerInserts(
+		b,
+		func(ctx context.Context, database Database, keys [][]byte, value []byte) error {
+			writer := database.BulkWrite(ctx)
+			defer writer.Cancel(ctx)
+
+			for _, key := range keys {
+				if err := writer.Set(ctx, key, value, false); err != nil {
+					return err
+				}
+			}
+
+			return writer.Flush(ctx)
+		},
+	)
+}
+
+const benchmarkDeletes = 50000
+
+type benchmarkScan func(
+	ctx context.Context,
+	txn Transaction,
+	prefix []byte,
+	worker func([]byte, []byte) error,
+) (int, error)
+
+// benchmarkBadgerDeletes benchmarks a deletion pass (like
+// removing historical balances) that scans for all keys
+// with a prefix and deletes them.
+func benchmarkBadgerDeletes(b *testing.B, scan benchmarkScan) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(b, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(b, err)
+	defer database.Close(ctx)
+
+	writer := database.BulkWrite(ctx)
+	value := make([]byte, 32)
+	for i := 0; i < benchmarkDeletes; i++ {
+		key := []byte(fmt.Sprintf("bal/addr/BTC/%020d", i))
+		assert.NoError(b, writer.Set(ctx, key, value, false))
+	}
+	assert.NoError(b, writer.Flush(ctx))
+
+	prefix := []byte("bal/addr/BTC/")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		txn := database.Transaction(ctx)
+		keys := [][]byte{}
+		_, err := scan(ctx, txn, prefix, func(k []byte, v []byte) error {
+			keys = append(keys, k)
+			return nil
+		})
+		assert.NoError(b, err)
+
+		for _, key := range keys {
+			assert.NoError(b, txn.Delete(ctx, key))
+		}
+		assert.Len(b, keys, benchmarkDeletes)
+
+		// Discard so that each iteration deletes
+		// the same records.
+		txn.Discard(ctx)
+	}
+}
+
+func BenchmarkBadgerScanDeletes(b *testing.B) {
+	benchmarkBadgerDeletes(
+		b,
+		func(
+			ctx context.Context,
+			txn Transaction,
+			prefix []byte,
+			worker func([]byte, []byte) error,
+		) (int, error) {
+			return txn.Scan(ctx, prefix, prefix, worker, false, false)
+		},
+	)
+}
+
+func BenchmarkBadgerScanKeysDeletes(b *testing.B) {
+	benchmarkBadgerDeletes(
+		b,
+		func(
+			ctx context.Context,
+			txn Transaction,
+			prefix []byte,
+			worker func([]byte, []byte) error,
+		) (int, error) {
+			return txn.ScanKeys(ctx, prefix, prefix, worker, false, false)
+		},
+	)
+}
+
+func TestBadgerGarbageCollection(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	// We use small value log files (and disable periodic
+	// garbage collection) so that pruned values are spread
+	// across many files that can only be reclaimed by
+	// RunGarbageCollection.
+	database, err := NewBadgerDatabase(
+		ctx,
+		newDir,
+		WithIndexCacheSize(TinyIndexCacheSize),
+		WithValueLogFileSize(minValueLogFileSize),
+		WithGarbageCollection(0, 0),
+	)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	t.Run("invalid discard ratio", func(t *testing.T) {
+		for _, ratio := range []float64{0, 1, -0.5} {
+			err := database.RunGarbageCollection(ctx, ratio)
+			assert.True(t, errors.Is(err, storageErrs.ErrInvalidDiscardRatio))
+		}
+	})
+
+	t.Run("nothing to collect", func(t *testing.T) {
+		assert.NoError(t, database.RunGarbageCollection(ctx, 0.5))
+	})
+
+	// Values must be larger than the ValueThreshold
+	// to be stored in the value log.
+	value := make([]byte, 4<<10)
+	_, err = rand.Read(value)
+	assert.NoError(t, err)
+
+	keys := make([][]byte, 4000)
+	writer := database.BulkWrite(ctx)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("bal/%020d", i))
+		assert.NoError(t, writer.Set(ctx, keys[i], value, false))
+	}
+	assert.NoError(t, writer.Flush(ctx))
+
+	before, err := database.GetDatabaseStats(ctx)
+	assert.NoError(t, err)
+	assert.True(t, before.ValueLogSize > int64(len(keys)*len(value)))
+
+	writer = database.BulkWrite(ctx)
+	for _, key := range keys {
+		assert.NoError(t, writer.Delete(ctx, key))
+	}
+	assert.NoError(t, writer.Flush(ctx))
+
+	// Garbage collection is safe while
+	// transactions are active.
+	txn := database.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+
+	// BadgerDB only discards a value once compaction has
+	// removed all older versions of its key from the LSM
+	// tree, so we can't assert how much space is reclaimed.
+	// RunGarbageCollection only returns once there is nothing
+	// left to rewrite, so running it again must succeed.
+	assert.NoError(t, database.RunGarbageCollection(ctx, 0.5))
+	assert.NoError(t, database.RunGarbageCollection(ctx, 0.5))
+
+	exists, _, err := txn.Get(ctx, keys[0])
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	t.Run("canceled context", func(t *testing.T) {
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := database.RunGarbageCollection(canceledCtx, 0.5)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
+
+func TestBadgerReadOnly(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+
+	txn := database.Transaction(ctx)
+	assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("hola"), true))
+	assert.NoError(t, txn.Set(ctx, []byte("hello2"), []byte("hola2"), true))
+	assert.NoError(t, txn.Commit(ctx))
+
+	t.Run("cannot open while held in read-write mode", func(t *testing.T) {
+		readOnly, err := NewBadgerDatabase(
+			ctx,
+			newDir,
+			WithIndexCacheSize(TinyIndexCacheSize),
+			WithReadOnly(),
+		)
+		assert.Nil(t, readOnly)
+		assert.True(t, errors.Is(err, storageErrs.ErrDatabaseOpenFailed))
+	})
+
+	assert.NoError(t, database.Close(ctx))
+
+	readOnly, err := NewBadgerDatabase(
+		ctx,
+		newDir,
+		WithIndexCacheSize(TinyIndexCacheSize),
+		WithReadOnly(),
+	)
+	assert.NoError(t, err)
+	defer readOnly.Close(ctx)
+
+	t.Run("reads", func(t *testing.T) {
+		txn := readOnly.ReadTransaction(ctx)
+		defer txn.Discard(ctx)
+
+		exists, value, err := txn.Get(ctx, []byte("hello"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []byte("hola"), value)
+
+		count, err := txn.Scan(
+			ctx,
+			[]byte("hello"),
+			[]byte("hello"),
+			func(k []byte, v []byte) error {
+				return nil
+			},
+			false,
+			false,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("writes are rejected", func(t *testing.T) {
+		for _, newTransaction := range []func() Transaction{
+			func() Transaction { return readOnly.Transaction(ctx) },
+			func() Transaction { return readOnly.WriteTransaction(ctx, "hello", true) },
+		} {
+			txn := newTransaction()
+			err := txn.Set(ctx, []byte("hello"), []byte("adios"), true)
+			assert.True(t, errors.Is(err, storageErrs.ErrReadOnlyDatabase))
+
+			err = txn.Delete(ctx, []byte("hello"))
+			assert.True(t, errors.Is(err, storageErrs.ErrReadOnlyDatabase))
+
+			// Reads are still allowed in write transactions
+			exists, value, err := txn.Get(ctx, []byte("hello"))
+			assert.NoError(t, err)
+			assert.True(t, exists)
+			assert.Equal(t, []byte("hola"), value)
+			assert.NoError(t, txn.Commit(ctx))
+		}
+
+		writer := readOnly.BulkWrite(ctx)
+		err := writer.Set(ctx, []byte("hello"), []byte("adios"), true)
+		assert.True(t, errors.Is(err, storageErrs.ErrReadOnlyDatabase))
+		err = writer.Delete(ctx, []byte("hello"))
+		assert.True(t, errors.Is(err, storageErrs.ErrReadOnlyDatabase))
+		assert.NoError(t, writer.Flush(ctx))
+
+		err = readOnly.RunGarbageCollection(ctx, 0.5)
+		assert.True(t, errors.Is(err, storageErrs.ErrReadOnlyDatabase))
+
+		txn := readOnly.ReadTransaction(ctx)
+		defer txn.Discard(ctx)
+		exists, value, err := txn.Get(ctx, []byte("hello"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []byte("hola"), value)
+	})
+
+	t.Run("multiple read-only opens", func(t *testing.T) {
+		readOnly2, err := NewBadgerDatabase(
+			ctx,
+			newDir,
+			WithIndexCacheSize(TinyIndexCacheSize),
+			WithReadOnly(),
+		)
+		assert.NoError(t, err)
+		assert.NoError(t, readOnly2.Close(ctx))
+	})
+
+	t.Run("in memory", func(t *testing.T) {
+		database, err := NewBadgerDatabase(
+			ctx,
+			"",
+			WithInMemory(),
+			WithReadOnly(),
+		)
+		assert.Nil(t, database)
+		assert.True(t, errors.Is(err, storageErrs.ErrInvalidBadgerOptions))
+	})
+}
+
+func TestBadgerEncryption(t *testing.T) {
+	ctx := context.Background()
+
+	open := func(dir string, opts ...BadgerOption) (Database, error) {
+		return NewBadgerDatabase(
+			ctx,
+			dir,
+			append([]BadgerOption{
+				WithIndexCacheSize(TinyIndexCacheSize),
+				WithBlockCacheSize(16 << 20),
+			}, opts...)...,
+		)
+	}
+
+	writeAndClose := func(t *testing.T, database Database) {
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("hola"), true))
+		assert.NoError(t, txn.Commit(ctx))
+		assert.NoError(t, database.Close(ctx))
+	}
+
+	assertRead := func(t *testing.T, database Database) {
+		txn := database.ReadTransaction(ctx)
+		defer txn.Discard(ctx)
+
+		exists, value, err := txn.Get(ctx, []byte("hello"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []byte("hola"), value)
+	}
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	wrongKey := []byte("fedcba9876543210fedcba9876543210")
+
+	t.Run("encryption key", func(t *testing.T) {
+		newDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+		defer utils.RemoveTempDir(newDir)
+
+		database, err := open(newDir, WithEncryptionKey(key))
+		assert.NoError(t, err)
+		writeAndClose(t, database)
+
+		database, err = open(newDir, WithEncryptionKey(key))
+		assert.NoError(t, err)
+		assertRead(t, database)
+		assert.NoError(t, database.Close(ctx))
+
+		database, err = open(newDir, WithEncryptionKey(wrongKey))
+		assert.Nil(t, database)
+		assert.True(t, errors.Is(err, storageErrs.ErrEncryptionKeyMismatch))
+
+		database, err = open(newDir)
+		assert.Nil(t, database)
+		assert.True(t, errors.Is(err, storageErrs.ErrEncryptionKeyMismatch))
+	})
+
+	t.Run("encryption passphrase", func(t *testing.T) {
+		newDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+		defer utils.RemoveTempDir(newDir)
+
+		database, err := open(newDir, WithEncryptionPassphrase("hello"))
+		assert.NoError(t, err)
+		writeAndClose(t, database)
+
+		database, err = open(newDir, WithEncryptionPassphrase("hello"))
+		assert.NoError(t, err)
+		assertRead(t, database)
+		assert.NoError(t, database.Close(ctx))
+
+		database, err = open(newDir, WithEncryptionPassphrase("goodbye"))
+		assert.Nil(t, database)
+		assert.True(t, errors.Is(err, storageErrs.ErrEncryptionKeyMismatch))
+	})
+
+	var tests = map[string]struct {
+		options []BadgerOption
+		err     error
+	}{
+		"invalid key length": {
+			options: []BadgerOption{WithEncryptionKey([]byte("hello"))},
+			err:     storageErrs.ErrInvalidBadgerOptions,
+		},
+		"no block cache": {
+			options: []BadgerOption{WithEncryptionKey(key), WithBlockCacheSize(0)},
+			err:     storageErrs.ErrInvalidBadgerOptions,
+		},
+		"negative rotation duration": {
+			options: []BadgerOption{
+				WithEncryptionKey(key),
+				WithEncryptionKeyRotationDuration(-1),
+			},
+			err: storageErrs.ErrInvalidBadgerOptions,
+		},
+		"key and passphrase": {
+			options: []BadgerOption{
+				WithEncryptionKey(key),
+				WithEncryptionPassphrase("hello"),
+			},
+			err: storageErrs.ErrInvalidBadgerOptions,
+		},
+		"read-only passphrase without salt": {
+			options: []BadgerOption{
+				WithEncryptionPassphrase("hello"),
+				WithReadOnly(),
+			},
+			err: storageErrs.ErrDeriveEncryptionKeyFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			newDir, err := utils.CreateTempDir()
+			assert.NoError(t, err)
+			defer utils.RemoveTempDir(newDir)
+
+			database, err := open(newDir, test.options...)
+			assert.Nil(t, database)
+			assert.True(t, errors.Is(err, test.err))
+		})
+	}
+}
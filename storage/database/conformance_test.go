@@ -0,0 +1,841 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// databaseConstructor creates a new, empty Database
+// and returns a function to clean it up.
+type databaseConstructor func(ctx context.Context, t *testing.T) (Database, func())
+
+func TestBadgerDatabaseConformance(t *testing.T) {
+	testDatabaseConformance(t, func(ctx context.Context, t *testing.T) (Database, func()) {
+		newDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+
+		database, err := newTestBadgerDatabase(ctx, newDir)
+		assert.NoError(t, err)
+
+		return database, func() {
+			database.Close(ctx)
+			utils.RemoveTempDir(newDir)
+		}
+	})
+}
+
+func TestMemoryDatabaseConformance(t *testing.T) {
+	testDatabaseConformance(t, func(ctx context.Context, t *testing.T) (Database, func()) {
+		database, err := NewMemoryDatabase(ctx)
+		assert.NoError(t, err)
+
+		return database, func() {
+			database.Close(ctx)
+		}
+	})
+}
+
+type conformanceItem struct {
+	Key   string
+	Value string
+}
+
+func scanItems(
+	ctx context.Context,
+	t *testing.T,
+	txn Transaction,
+	prefix string,
+	seekStart string,
+	reverse bool,
+) []*conformanceItem {
+	items := []*conformanceItem{}
+	entries, err := txn.Scan(
+		ctx,
+		[]byte(prefix),
+		[]byte(seekStart),
+		func(k []byte, v []byte) error {
+			items = append(items, &conformanceItem{
+				Key:   string(k),
+				Value: string(v),
+			})
+
+			return nil
+		},
+		false,
+		reverse,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, len(items), entries)
+
+	return items
+}
+
+func scanKeys(
+	ctx context.Context,
+	t *testing.T,
+	txn Transaction,
+	prefix string,
+	seekStart string,
+	reverse bool,
+) []string {
+	keys := []string{}
+	entries, err := txn.ScanKeys(
+		ctx,
+		[]byte(prefix),
+		[]byte(seekStart),
+		func(k []byte, v []byte) error {
+			assert.Nil(t, v)
+			keys = append(keys, string(k))
+
+			return nil
+		},
+		false,
+		reverse,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, len(keys), entries)
+
+	return keys
+}
+
+func itemKeys(items []*conformanceItem) []string {
+	keys := []string{}
+	for _, item := range items {
+		keys = append(keys, item.Key)
+	}
+
+	return keys
+}
+
+func setItems(
+	ctx context.Context,
+	t *testing.T,
+	database Database,
+	items ...*conformanceItem,
+) {
+	txn := database.Transaction(ctx)
+	for _, item := range items {
+		assert.NoError(t, txn.Set(ctx, []byte(item.Key), []byte(item.Value), true))
+	}
+	assert.NoError(t, txn.Commit(ctx))
+}
+
+// testDatabaseConformance ensures a Database implementation
+// behaves the same as all other implementations.
+func testDatabaseConformance(t *testing.T, newDatabase databaseConstructor) { // nolint:gocognit
+	ctx := context.Background()
+
+	t.Run("set, get, and delete", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		txn := database.ReadTransaction(ctx)
+		exists, value, err := txn.Get(ctx, []byte("hello"))
+		assert.False(t, exists)
+		assert.Nil(t, value)
+		assert.NoError(t, err)
+		txn.Discard(ctx)
+
+		setItems(ctx, t, database, &conformanceItem{Key: "hello", Value: "hola"})
+		setItems(ctx, t, database, &conformanceItem{Key: "hello", Value: "bonjour"})
+
+		txn = database.ReadTransaction(ctx)
+		exists, value, err = txn.Get(ctx, []byte("hello"))
+		assert.True(t, exists)
+		assert.Equal(t, []byte("bonjour"), value)
+		assert.NoError(t, err)
+		txn.Discard(ctx)
+
+		txn = database.Transaction(ctx)
+		assert.NoError(t, txn.Delete(ctx, []byte("hello")))
+		assert.NoError(t, txn.Delete(ctx, []byte("missing")))
+		assert.NoError(t, txn.Commit(ctx))
+
+		txn = database.ReadTransaction(ctx)
+		exists, value, err = txn.Get(ctx, []byte("hello"))
+		assert.False(t, exists)
+		assert.Nil(t, value)
+		assert.NoError(t, err)
+		txn.Discard(ctx)
+	})
+
+	t.Run("invalid writes", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		txn := database.ReadTransaction(ctx)
+		assert.Error(t, txn.Set(ctx, []byte("hello"), []byte("hola"), true))
+		assert.Error(t, txn.Delete(ctx, []byte("hello")))
+		txn.Discard(ctx)
+
+		txn = database.Transaction(ctx)
+		assert.Error(t, txn.Set(ctx, []byte{}, []byte("hola"), true))
+		txn.Discard(ctx)
+	})
+
+	t.Run("returned values are copies", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		value := []byte("hola")
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("hello"), value, false))
+		assert.NoError(t, txn.Commit(ctx))
+		value[0] = 'x'
+
+		txn = database.ReadTransaction(ctx)
+		exists, retrieved, err := txn.Get(ctx, []byte("hello"))
+		assert.True(t, exists)
+		assert.Equal(t, []byte("hola"), retrieved)
+		assert.NoError(t, err)
+		txn.Discard(ctx)
+	})
+
+	t.Run("uncommitted writes", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		setItems(ctx, t, database, &conformanceItem{Key: "a/1", Value: "1"})
+
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("a/2"), []byte("2"), true))
+		assert.NoError(t, txn.Delete(ctx, []byte("a/1")))
+
+		// Writes are visible within the transaction
+		exists, value, err := txn.Get(ctx, []byte("a/2"))
+		assert.True(t, exists)
+		assert.Equal(t, []byte("2"), value)
+		assert.NoError(t, err)
+
+		exists, _, err = txn.Get(ctx, []byte("a/1"))
+		assert.False(t, exists)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []*conformanceItem{
+			{Key: "a/2", Value: "2"},
+		}, scanItems(ctx, t, txn, "a/", "a/", false))
+
+		// Writes are not visible outside of the transaction
+		readTxn := database.ReadTransaction(ctx)
+		assert.Equal(t, []*conformanceItem{
+			{Key: "a/1", Value: "1"},
+		}, scanItems(ctx, t, readTxn, "a/", "a/", false))
+		readTxn.Discard(ctx)
+
+		// Discarded writes are never visible
+		txn.Discard(ctx)
+
+		readTxn = database.ReadTransaction(ctx)
+		assert.Equal(t, []*conformanceItem{
+			{Key: "a/1", Value: "1"},
+		}, scanItems(ctx, t, readTxn, "a/", "a/", false))
+		readTxn.Discard(ctx)
+	})
+
+	t.Run("snapshot isolation", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		setItems(ctx, t, database, &conformanceItem{Key: "hello", Value: "hola"})
+
+		oldTxn := database.ReadTransaction(ctx)
+		defer oldTxn.Discard(ctx)
+
+		setItems(ctx, t, database,
+			&conformanceItem{Key: "hello", Value: "bonjour"},
+			&conformanceItem{Key: "hello2", Value: "hallo"},
+		)
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Delete(ctx, []byte("hello")))
+		assert.NoError(t, txn.Commit(ctx))
+
+		// Old transactions see the database as of
+		// when they were created.
+		exists, value, err := oldTxn.Get(ctx, []byte("hello"))
+		assert.True(t, exists)
+		assert.Equal(t, []byte("hola"), value)
+		assert.NoError(t, err)
+		assert.Equal(t, []*conformanceItem{
+			{Key: "hello", Value: "hola"},
+		}, scanItems(ctx, t, oldTxn, "hello", "hello", false))
+
+		newTxn := database.ReadTransaction(ctx)
+		defer newTxn.Discard(ctx)
+		exists, _, err = newTxn.Get(ctx, []byte("hello"))
+		assert.False(t, exists)
+		assert.NoError(t, err)
+		assert.Equal(t, []*conformanceItem{
+			{Key: "hello2", Value: "hallo"},
+		}, scanItems(ctx, t, newTxn, "hello", "hello", false))
+	})
+
+	t.Run("conflicting transactions", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		setItems(ctx, t, database, &conformanceItem{Key: "balance", Value: "10"})
+
+		txn1 := database.WriteTransaction(ctx, "1", false)
+		txn2 := database.WriteTransaction(ctx, "2", false)
+
+		exists, value, err := txn1.Get(ctx, []byte("balance"))
+		assert.True(t, exists)
+		assert.Equal(t, []byte("10"), value)
+		assert.NoError(t, err)
+
+		assert.NoError(t, txn2.Set(ctx, []byte("balance"), []byte("20"), true))
+		assert.NoError(t, txn2.Commit(ctx))
+
+		// txn1 read a key modified after it started
+		assert.NoError(t, txn1.Set(ctx, []byte("other"), []byte("10"), true))
+		assert.Error(t, txn1.Commit(ctx))
+
+		txn := database.ReadTransaction(ctx)
+		exists, _, err = txn.Get(ctx, []byte("other"))
+		assert.False(t, exists)
+		assert.NoError(t, err)
+		txn.Discard(ctx)
+
+		// Blind writes never conflict
+		txn1 = database.WriteTransaction(ctx, "1", false)
+		txn2 = database.WriteTransaction(ctx, "2", false)
+		assert.NoError(t, txn1.Set(ctx, []byte("balance"), []byte("30"), true))
+		assert.NoError(t, txn2.Set(ctx, []byte("balance"), []byte("40"), true))
+		assert.NoError(t, txn2.Commit(ctx))
+		assert.NoError(t, txn1.Commit(ctx))
+
+		txn = database.ReadTransaction(ctx)
+		exists, value, err = txn.Get(ctx, []byte("balance"))
+		assert.True(t, exists)
+		assert.Equal(t, []byte("30"), value)
+		assert.NoError(t, err)
+		txn.Discard(ctx)
+	})
+
+	t.Run("scan", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		setItems(ctx, t, database,
+			&conformanceItem{Key: "a", Value: "a"},
+			&conformanceItem{Key: "b/1", Value: "1"},
+			&conformanceItem{Key: "b/2", Value: "2"},
+			&conformanceItem{Key: "b/3", Value: "3"},
+			&conformanceItem{Key: "b/4", Value: "4"},
+			&conformanceItem{Key: "c", Value: "c"},
+		)
+
+		tests := map[string]struct {
+			prefix    string
+			seekStart string
+			reverse   bool
+
+			expected []*conformanceItem
+		}{
+			"forward": {
+				prefix:    "b/",
+				seekStart: "b/",
+				expected: []*conformanceItem{
+					{Key: "b/1", Value: "1"},
+					{Key: "b/2", Value: "2"},
+					{Key: "b/3", Value: "3"},
+					{Key: "b/4", Value: "4"},
+				},
+			},
+			"forward from seek": {
+				prefix:    "b/",
+				seekStart: "b/3",
+				expected: []*conformanceItem{
+					{Key: "b/3", Value: "3"},
+					{Key: "b/4", Value: "4"},
+				},
+			},
+			"forward with seek before prefix": {
+				prefix:    "b/",
+				seekStart: "a",
+				expected:  []*conformanceItem{},
+			},
+			"reverse": {
+				prefix:    "b/",
+				seekStart: "b/\xff",
+				reverse:   true,
+				expected: []*conformanceItem{
+					{Key: "b/4", Value: "4"},
+					{Key: "b/3", Value: "3"},
+					{Key: "b/2", Value: "2"},
+					{Key: "b/1", Value: "1"},
+				},
+			},
+			"reverse from seek": {
+				prefix:    "b/",
+				seekStart: "b/2",
+				reverse:   true,
+				expected: []*conformanceItem{
+					{Key: "b/2", Value: "2"},
+					{Key: "b/1", Value: "1"},
+				},
+			},
+			"reverse with seek after prefix": {
+				prefix:    "b/",
+				seekStart: "d",
+				reverse:   true,
+				expected:  []*conformanceItem{},
+			},
+			"missing prefix": {
+				prefix:    "d/",
+				seekStart: "d/",
+				expected:  []*conformanceItem{},
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				txn := database.ReadTransaction(ctx)
+				defer txn.Discard(ctx)
+
+				assert.Equal(
+					t,
+					test.expected,
+					scanItems(ctx, t, txn, test.prefix, test.seekStart, test.reverse),
+				)
+				assert.Equal(
+					t,
+					itemKeys(test.expected),
+					scanKeys(ctx, t, txn, test.prefix, test.seekStart, test.reverse),
+				)
+			})
+		}
+	})
+
+	t.Run("scan with pending writes", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		setItems(ctx, t, database,
+			&conformanceItem{Key: "b/1", Value: "1"},
+			&conformanceItem{Key: "b/3", Value: "3"},
+		)
+
+		txn := database.Transaction(ctx)
+		defer txn.Discard(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("b/2"), []byte("2"), true))
+		assert.NoError(t, txn.Set(ctx, []byte("b/3"), []byte("33"), true))
+		assert.NoError(t, txn.Delete(ctx, []byte("b/1")))
+		assert.NoError(t, txn.Set(ctx, []byte("b/4"), []byte("4"), true))
+
+		assert.Equal(t, []*conformanceItem{
+			{Key: "b/2", Value: "2"},
+			{Key: "b/3", Value: "33"},
+			{Key: "b/4", Value: "4"},
+		}, scanItems(ctx, t, txn, "b/", "b/", false))
+		assert.Equal(t, []*conformanceItem{
+			{Key: "b/4", Value: "4"},
+			{Key: "b/3", Value: "33"},
+			{Key: "b/2", Value: "2"},
+		}, scanItems(ctx, t, txn, "b/", "b/\xff", true))
+		assert.Equal(
+			t,
+			[]string{"b/2", "b/3", "b/4"},
+			scanKeys(ctx, t, txn, "b/", "b/", false),
+		)
+		assert.Equal(
+			t,
+			[]string{"b/4", "b/3", "b/2"},
+			scanKeys(ctx, t, txn, "b/", "b/\xff", true),
+		)
+	})
+
+	t.Run("scan worker error", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		setItems(ctx, t, database,
+			&conformanceItem{Key: "b/1", Value: "1"},
+			&conformanceItem{Key: "b/2", Value: "2"},
+		)
+
+		workerErr := errors.New("worker error")
+		txn := database.ReadTransaction(ctx)
+		defer txn.Discard(ctx)
+		entries, err := txn.Scan(
+			ctx,
+			[]byte("b/"),
+			[]byte("b/"),
+			func(k []byte, v []byte) error {
+				return workerErr
+			},
+			false,
+			false,
+		)
+		assert.Equal(t, -1, entries)
+		assert.True(t, errors.Is(err, workerErr))
+	})
+
+	t.Run("many keys", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		expected := []*conformanceItem{}
+		txn := database.Transaction(ctx)
+		for i := 0; i < 1000; i++ {
+			item := &conformanceItem{
+				Key:   fmt.Sprintf("blah/%04d", i),
+				Value: fmt.Sprintf("%d", i),
+			}
+			assert.NoError(t, txn.Set(ctx, []byte(item.Key), []byte(item.Value), true))
+			expected = append(expected, item)
+		}
+		assert.NoError(t, txn.Commit(ctx))
+
+		// Delete every other key
+		txn = database.Transaction(ctx)
+		remaining := []*conformanceItem{}
+		for i, item := range expected {
+			if i%2 == 0 {
+				assert.NoError(t, txn.Delete(ctx, []byte(item.Key)))
+				continue
+			}
+
+			remaining = append(remaining, item)
+		}
+		assert.NoError(t, txn.Commit(ctx))
+
+		readTxn := database.ReadTransaction(ctx)
+		defer readTxn.Discard(ctx)
+		assert.Equal(t, remaining, scanItems(ctx, t, readTxn, "blah/", "blah/", false))
+	})
+
+	t.Run("scan pages", func(t *testing.T) {
+		testScanPages(ctx, t, newDatabase)
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		txn := database.Transaction(ctx)
+		err := txn.SetWithTTL(ctx, []byte("ttl/invalid"), []byte("1"), 0, true)
+		assert.True(t, errors.Is(err, storageErrs.ErrInvalidTTL))
+		assert.NoError(t, txn.SetWithTTL(ctx, []byte("ttl/1"), []byte("1"), time.Second, true))
+		assert.NoError(t, txn.SetWithTTL(ctx, []byte("ttl/2"), []byte("2"), time.Second, true))
+		assert.NoError(t, txn.SetWithTTL(ctx, []byte("ttl/3"), []byte("3"), time.Hour, true))
+		assert.NoError(t, txn.Set(ctx, []byte("ttl/4"), []byte("4"), true))
+		assert.NoError(t, txn.Commit(ctx))
+
+		// Setting a key without a ttl removes its ttl
+		txn = database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("ttl/2"), []byte("2"), true))
+		assert.NoError(t, txn.Commit(ctx))
+
+		readTxn := database.ReadTransaction(ctx)
+		assert.Equal(t, []*conformanceItem{
+			{Key: "ttl/1", Value: "1"},
+			{Key: "ttl/2", Value: "2"},
+			{Key: "ttl/3", Value: "3"},
+			{Key: "ttl/4", Value: "4"},
+		}, scanItems(ctx, t, readTxn, "ttl/", "ttl/", false))
+		readTxn.Discard(ctx)
+
+		// BadgerDB stores expiration times in seconds
+		time.Sleep(2 * time.Second)
+
+		readTxn = database.ReadTransaction(ctx)
+		exists, value, err := readTxn.Get(ctx, []byte("ttl/1"))
+		assert.False(t, exists)
+		assert.Nil(t, value)
+		assert.NoError(t, err)
+		assert.Equal(t, []*conformanceItem{
+			{Key: "ttl/2", Value: "2"},
+			{Key: "ttl/3", Value: "3"},
+			{Key: "ttl/4", Value: "4"},
+		}, scanItems(ctx, t, readTxn, "ttl/", "ttl/", false))
+		readTxn.Discard(ctx)
+
+		// Expired keys can be set again
+		setItems(ctx, t, database, &conformanceItem{Key: "ttl/1", Value: "5"})
+		readTxn = database.ReadTransaction(ctx)
+		exists, value, err = readTxn.Get(ctx, []byte("ttl/1"))
+		assert.True(t, exists)
+		assert.Equal(t, []byte("5"), value)
+		assert.NoError(t, err)
+		readTxn.Discard(ctx)
+	})
+
+	t.Run("bulk write", func(t *testing.T) {
+		database, cleanup := newDatabase(ctx, t)
+		defer cleanup()
+
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("blah/0000"), []byte("old"), true))
+		assert.NoError(t, txn.Set(ctx, []byte("blah/deleted"), []byte("old"), true))
+		assert.NoError(t, txn.Commit(ctx))
+
+		expected := []*conformanceItem{}
+		writer := database.BulkWrite(ctx)
+		for i := 0; i < 1000; i++ {
+			item := &conformanceItem{
+				Key:   fmt.Sprintf("blah/%04d", i),
+				Value: fmt.Sprintf("%d", i),
+			}
+			assert.NoError(t, writer.Set(ctx, []byte(item.Key), []byte(item.Value), true))
+			expected = append(expected, item)
+		}
+		assert.NoError(t, writer.Delete(ctx, []byte("blah/deleted")))
+		assert.NoError(t, writer.Flush(ctx))
+
+		readTxn := database.ReadTransaction(ctx)
+		assert.Equal(t, expected, scanItems(ctx, t, readTxn, "blah/", "blah/", false))
+		readTxn.Discard(ctx)
+
+		// The writer cannot be used after it is flushed
+		err := writer.Set(ctx, []byte("blah/1000"), []byte("1000"), true)
+		assert.True(t, errors.Is(err, storageErrs.ErrBulkWriterClosed))
+		err = writer.Flush(ctx)
+		assert.True(t, errors.Is(err, storageErrs.ErrBulkWriterClosed))
+		writer.Cancel(ctx)
+
+		// Canceling a writer releases the write lock
+		writer = database.BulkWrite(ctx)
+		writer.Cancel(ctx)
+		err = writer.Delete(ctx, []byte("blah/0000"))
+		assert.True(t, errors.Is(err, storageErrs.ErrBulkWriterClosed))
+
+		txn = database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("blah/1000"), []byte("1000"), true))
+		assert.NoError(t, txn.Commit(ctx))
+	})
+}
+
+// scanPageKeys reads a single page in a new ReadTransaction
+// and returns the keys in the page and the next cursor.
+func scanPageKeys(
+	ctx context.Context,
+	t *testing.T,
+	database Database,
+	cursor string,
+	limit int,
+	reverse bool,
+) ([]string, string) {
+	txn := database.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+
+	var cursorBytes []byte
+	if len(cursor) > 0 {
+		cursorBytes = []byte(cursor)
+	}
+
+	keys := []string{}
+	next, err := txn.ScanPage(
+		ctx,
+		[]byte("page/"),
+		cursorBytes,
+		limit,
+		func(k []byte, v []byte) error {
+			assert.Equal(t, string(k), string(v))
+			keys = append(keys, string(k))
+			return nil
+		},
+		reverse,
+	)
+	assert.NoError(t, err)
+
+	return keys, string(next)
+}
+
+func testScanPages(ctx context.Context, t *testing.T, newDatabase databaseConstructor) {
+	setup := func(t *testing.T) (Database, func()) {
+		database, cleanup := newDatabase(ctx, t)
+
+		keys := []string{"pag", "pagex", "pagf"}
+		for i := 0; i < 10; i++ {
+			keys = append(keys, fmt.Sprintf("page/%02d", i))
+		}
+		setPageKeys(ctx, t, database, keys...)
+
+		return database, cleanup
+	}
+
+	t.Run("forward", func(t *testing.T) {
+		database, cleanup := setup(t)
+		defer cleanup()
+
+		keys, cursor := scanPageKeys(ctx, t, database, "", 3, false)
+		assert.Equal(t, []string{"page/00", "page/01", "page/02"}, keys)
+		assert.Equal(t, "page/03", cursor)
+
+		// Keys added before the cursor are skipped
+		setPageKeys(ctx, t, database, "page/025", "page/055")
+
+		keys, cursor = scanPageKeys(ctx, t, database, cursor, 3, false)
+		assert.Equal(t, []string{"page/03", "page/04", "page/05"}, keys)
+		assert.Equal(t, "page/055", cursor)
+
+		keys, cursor = scanPageKeys(ctx, t, database, cursor, 3, false)
+		assert.Equal(t, []string{"page/055", "page/06", "page/07"}, keys)
+		assert.Equal(t, "page/08", cursor)
+
+		keys, cursor = scanPageKeys(ctx, t, database, cursor, 3, false)
+		assert.Equal(t, []string{"page/08", "page/09"}, keys)
+		assert.Equal(t, "", cursor)
+	})
+
+	t.Run("reverse", func(t *testing.T) {
+		database, cleanup := setup(t)
+		defer cleanup()
+
+		keys, cursor := scanPageKeys(ctx, t, database, "", 3, true)
+		assert.Equal(t, []string{"page/09", "page/08", "page/07"}, keys)
+		assert.Equal(t, "page/06", cursor)
+
+		// Keys added before the cursor are skipped
+		setPageKeys(ctx, t, database, "page/065", "page/015")
+
+		keys, cursor = scanPageKeys(ctx, t, database, cursor, 3, true)
+		assert.Equal(t, []string{"page/06", "page/05", "page/04"}, keys)
+		assert.Equal(t, "page/03", cursor)
+
+		keys, cursor = scanPageKeys(ctx, t, database, cursor, 3, true)
+		assert.Equal(t, []string{"page/03", "page/02", "page/015"}, keys)
+		assert.Equal(t, "page/01", cursor)
+
+		keys, cursor = scanPageKeys(ctx, t, database, cursor, 3, true)
+		assert.Equal(t, []string{"page/01", "page/00"}, keys)
+		assert.Equal(t, "", cursor)
+	})
+
+	t.Run("deleted cursor", func(t *testing.T) {
+		database, cleanup := setup(t)
+		defer cleanup()
+
+		keys, cursor := scanPageKeys(ctx, t, database, "", 5, false)
+		assert.Equal(t, []string{"page/00", "page/01", "page/02", "page/03", "page/04"}, keys)
+		assert.Equal(t, "page/05", cursor)
+
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Delete(ctx, []byte("page/05")))
+		assert.NoError(t, txn.Commit(ctx))
+
+		keys, cursor = scanPageKeys(ctx, t, database, cursor, 5, false)
+		assert.Equal(t, []string{"page/06", "page/07", "page/08", "page/09"}, keys)
+		assert.Equal(t, "", cursor)
+
+		keys, cursor = scanPageKeys(ctx, t, database, "page/05", 5, true)
+		assert.Equal(t, []string{"page/04", "page/03", "page/02", "page/01", "page/00"}, keys)
+		assert.Equal(t, "", cursor)
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		database, cleanup := setup(t)
+		defer cleanup()
+
+		keys, cursor := scanPageKeys(ctx, t, database, "", 0, false)
+		assert.Len(t, keys, 10)
+		assert.Equal(t, "", cursor)
+
+		keys, cursor = scanPageKeys(ctx, t, database, "", 10, true)
+		assert.Len(t, keys, 10)
+		assert.Equal(t, "page/09", keys[0])
+		assert.Equal(t, "", cursor)
+	})
+
+	t.Run("pending writes", func(t *testing.T) {
+		database, cleanup := setup(t)
+		defer cleanup()
+
+		txn := database.Transaction(ctx)
+		defer txn.Discard(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("page/005"), []byte("page/005"), true))
+		assert.NoError(t, txn.Delete(ctx, []byte("page/01")))
+
+		keys := []string{}
+		cursor, err := txn.ScanPage(
+			ctx,
+			[]byte("page/"),
+			nil,
+			3,
+			func(k []byte, v []byte) error {
+				keys = append(keys, string(k))
+				return nil
+			},
+			false,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"page/00", "page/005", "page/02"}, keys)
+		assert.Equal(t, []byte("page/03"), cursor)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		database, cleanup := setup(t)
+		defer cleanup()
+
+		txn := database.ReadTransaction(ctx)
+		defer txn.Discard(ctx)
+
+		cursor, err := txn.ScanPage(
+			ctx,
+			[]byte("page/"),
+			[]byte("pagex"),
+			3,
+			func(k []byte, v []byte) error {
+				return nil
+			},
+			false,
+		)
+		assert.Nil(t, cursor)
+		assert.True(t, errors.Is(err, storageErrs.ErrInvalidScanCursor))
+	})
+
+	t.Run("worker error", func(t *testing.T) {
+		database, cleanup := setup(t)
+		defer cleanup()
+
+		txn := database.ReadTransaction(ctx)
+		defer txn.Discard(ctx)
+
+		errWorker := errors.New("worker failed")
+		cursor, err := txn.ScanPage(
+			ctx,
+			[]byte("page/"),
+			nil,
+			3,
+			func(k []byte, v []byte) error {
+				return errWorker
+			},
+			false,
+		)
+		assert.Nil(t, cursor)
+		assert.True(t, errors.Is(err, errWorker))
+	})
+}
+
+// setPageKeys stores each key with
+// itself as the value.
+func setPageKeys(ctx context.Context, t *testing.T, database Database, keys ...string) {
+	txn := database.Transaction(ctx)
+	for _, key := range keys {
+		assert.NoError(t, txn.Set(ctx, []byte(key), []byte(key), true))
+	}
+	assert.NoError(t, txn.Commit(ctx))
+}
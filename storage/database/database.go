@@ -16,6 +16,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
 )
@@ -48,6 +49,57 @@ type Database interface {
 	// in the database. This *Encoder often performs some
 	// form of compression on data.
 	Encoder() *encoder.Encoder
+
+	// BulkWrite acquires an exclusive write lock on the database
+	// and returns a BulkWriter. All other calls to Transaction and
+	// WriteTransaction will block until the returned BulkWriter is
+	// flushed or canceled.
+	BulkWrite(context.Context) BulkWriter
+
+	// GetDatabaseStats returns the size of the database. If
+	// any stats namespaces were configured, it also returns
+	// an estimate of the number of keys and bytes in each
+	// namespace (computed until the context deadline).
+	GetDatabaseStats(context.Context) (*DatabaseStats, error)
+
+	// RunGarbageCollection reclaims space used by deleted,
+	// expired, or overwritten values (which is not always
+	// reclaimed automatically). discardRatio must be in (0, 1)
+	// and determines how much of a file must be reclaimable
+	// before it is rewritten (if applicable). This is useful
+	// to call after pruning large amounts of data.
+	RunGarbageCollection(ctx context.Context, discardRatio float64) error
+}
+
+// DatabaseStats contains information about the
+// size of a Database.
+type DatabaseStats struct {
+	// LSMSize is the size (in bytes) of all keys
+	// (and values stored with keys).
+	LSMSize int64 `json:"lsm_size"`
+
+	// ValueLogSize is the size (in bytes) of all
+	// values stored outside of the LSM tree.
+	ValueLogSize int64 `json:"value_log_size"`
+
+	// Namespaces is only populated if stats
+	// namespaces are configured.
+	Namespaces []*NamespaceStats `json:"namespaces,omitempty"`
+}
+
+// NamespaceStats is the number of keys (and their size)
+// stored in a namespace. Stats are not extrapolated,
+// so Keys is exact unless the scan is Truncated.
+type NamespaceStats struct {
+	Namespace string `json:"namespace"`
+	Keys      int64  `json:"keys"`
+	Bytes     int64  `json:"bytes"`
+
+	// Truncated is true if the scan of the namespace
+	// stopped early (because of the sample limit or the
+	// context deadline). In this case, Keys and Bytes
+	// are lower bounds.
+	Truncated bool `json:"truncated"`
 }
 
 // Transaction is an interface that provides
@@ -59,6 +111,13 @@ type Database interface {
 // any data retrieved, make sure to make a copy!
 type Transaction interface {
 	Set(context.Context, []byte, []byte, bool) error
+
+	// SetWithTTL is like Set but the key is no longer
+	// returned by Get or Scan once the ttl (which must be
+	// positive) has elapsed. Setting the key again without
+	// a ttl removes the ttl.
+	SetWithTTL(context.Context, []byte, []byte, time.Duration, bool) error
+
 	Get(context.Context, []byte) (bool, []byte, error)
 	Delete(context.Context, []byte) error
 
@@ -71,10 +130,65 @@ type Transaction interface {
 		bool, // reverse == true means greatest to least
 	) (int, error)
 
+	// ScanKeys is like Scan but only iterates over keys
+	// (the worker is called with a nil value). This is much
+	// faster than Scan when values are not needed (like when
+	// collecting keys to delete).
+	ScanKeys(
+		context.Context,
+		[]byte, // prefix restriction
+		[]byte, // seek start
+		func([]byte, []byte) error,
+		bool, // log entries
+		bool, // reverse == true means greatest to least
+	) (int, error)
+
+	// ScanPage calls a worker for at most limit items with some
+	// prefix, starting at the cursor (inclusive). If the cursor is
+	// nil, the scan starts at the first item (or the last item
+	// if reverse == true). If more items remain, the key of the
+	// next item is returned as the cursor for the next page (otherwise
+	// the returned cursor is nil).
+	//
+	// Cursors may be used across transactions. Items added
+	// before the cursor (in scan order) after a page was
+	// returned are not included in subsequent pages.
+	ScanPage(
+		context.Context,
+		[]byte, // prefix restriction
+		[]byte, // cursor
+		int, // limit (<= 0 means no limit)
+		func([]byte, []byte) error,
+		bool, // reverse == true means greatest to least
+	) ([]byte, error)
+
 	Commit(context.Context) error
 	Discard(context.Context)
 }
 
+// BulkWriter is an interface that provides fast, write-only
+// access to a KV store for loading many keys at once.
+//
+// Unlike a Transaction, writes are not checked for conflicts
+// and are not applied atomically (some writes may be persisted
+// before Flush is called or even if Flush fails). It is only safe
+// to use a BulkWriter during offline loads (when no other process
+// is reading and modifying the keys being written).
+type BulkWriter interface {
+	Set(context.Context, []byte, []byte, bool) error
+	Delete(context.Context, []byte) error
+
+	// Flush waits for all writes to be persisted
+	// and releases the exclusive write lock.
+	Flush(context.Context) error
+
+	// Cancel stops the BulkWriter and releases the
+	// exclusive write lock without waiting for pending
+	// writes to be persisted. Cancel is a no-op if
+	// Flush has already been called.
+	Cancel(context.Context)
+}
+
 // CommitWorker is returned by a module to be called after
 // changes have been committed. It is common to put logging activities
 // in here (that shouldn't be printed until the block is committed).
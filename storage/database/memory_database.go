@@ -0,0 +1,848 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// MemoryDatabase is an in-memory implementation of
+// the Database interface. It provides the same
+// snapshot-isolated transactions as BadgerDatabase
+// but never writes to disk, which makes it useful for
+// tests and ephemeral runs.
+type MemoryDatabase struct {
+	compressorEntries []*encoder.CompressorEntry
+	encoderOptions    []encoder.Option
+
+	pool     *encoder.BufferPool
+	encoder  *encoder.Encoder
+	compress bool
+
+	writer       *utils.MutexMap
+	writerShards int
+
+	// statsNamespaces are the namespaces
+	// broken down in GetDatabaseStats.
+	statsNamespaces  []string
+	statsSampleLimit int
+
+	// now is used to determine if a version
+	// has expired (overridden in tests).
+	now func() time.Time
+
+	// mutex protects all fields below.
+	mutex sync.RWMutex
+
+	// keys contains all keys with at least one
+	// stored version in sorted order.
+	keys     []string
+	versions map[string][]*memoryVersion
+
+	// commitTs is the timestamp of the last
+	// committed transaction.
+	commitTs uint64
+
+	// activeReads tracks the number of open transactions
+	// at each read timestamp so that versions no longer
+	// visible to any transaction can be pruned.
+	activeReads map[uint64]int
+}
+
+// memoryVersion is the value of a key
+// at some commit timestamp.
+type memoryVersion struct {
+	ts      uint64
+	value   []byte
+	deleted bool
+
+	// expiresAt is zero if the version
+	// does not expire.
+	expiresAt time.Time
+}
+
+// visible returns a boolean indicating if a version
+// exists, is not deleted, and has not expired at now.
+// Expired versions are only removed when a key is
+// written again, garbage is collected, or the database
+// is closed.
+func (v *memoryVersion) visible(now time.Time) bool {
+	if v == nil || v.deleted {
+		return false
+	}
+
+	return v.expiresAt.IsZero() || now.Before(v.expiresAt)
+}
+
+// NewMemoryDatabase creates a new MemoryDatabase.
+func NewMemoryDatabase(
+	ctx context.Context,
+	storageOptions ...MemoryOption,
+) (Database, error) {
+	m := &MemoryDatabase{
+		pool:         encoder.NewBufferPool(),
+		compress:     true,
+		writerShards: utils.DefaultShards,
+		keys:         []string{},
+		versions:     map[string][]*memoryVersion{},
+		activeReads:  map[uint64]int{},
+		now:          time.Now,
+	}
+	for _, opt := range storageOptions {
+		opt(m)
+	}
+
+	// Initialize utis.MutexMap used to track granular
+	// write transactions.
+	m.writer = utils.NewMutexMap(m.writerShards)
+
+	encoder, err := encoder.NewEncoder(
+		m.compressorEntries,
+		m.pool,
+		m.compress,
+		m.encoderOptions...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrCompressorLoadFailed, err)
+	}
+	m.encoder = encoder
+
+	return m, nil
+}
+
+// Close releases all data stored in the database.
+func (m *MemoryDatabase) Close(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.keys = []string{}
+	m.versions = map[string][]*memoryVersion{}
+
+	return nil
+}
+
+// RunGarbageCollection removes all versions of all keys
+// that are not visible to any open transaction (including
+// expired versions). Versions of a key are otherwise only
+// removed when the key is written. discardRatio is only
+// validated (all unreachable versions are always removed).
+func (m *MemoryDatabase) RunGarbageCollection(
+	ctx context.Context,
+	discardRatio float64,
+) error {
+	if err := validateDiscardRatio(discardRatio); err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	minTs := m.minReadTs()
+	now := m.now()
+
+	// prune modifies m.keys, so we iterate over a copy.
+	keys := make([]string, len(m.keys))
+	copy(keys, m.keys)
+	for _, key := range keys {
+		m.prune(key, minTs, now)
+	}
+
+	return nil
+}
+
+// Encoder returns the MemoryDatabase encoder.
+func (m *MemoryDatabase) Encoder() *encoder.Encoder {
+	return m.encoder
+}
+
+// GetDatabaseStats returns the size of all stored
+// versions of all keys as the LSMSize (values are
+// never stored separately).
+func (m *MemoryDatabase) GetDatabaseStats(ctx context.Context) (*DatabaseStats, error) {
+	m.mutex.RLock()
+	size := int64(0)
+	for key, versions := range m.versions {
+		for _, version := range versions {
+			size += int64(len(key) + len(version.value))
+		}
+	}
+	m.mutex.RUnlock()
+
+	namespaces, err := namespaceStats(
+		ctx,
+		m.statsNamespaces,
+		m.statsSampleLimit,
+		m.scanNamespace,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrDatabaseStatsFailed, err)
+	}
+
+	return &DatabaseStats{
+		LSMSize:    size,
+		Namespaces: namespaces,
+	}, nil
+}
+
+// scanNamespace is a namespaceScanner over the
+// latest committed version of each key.
+func (m *MemoryDatabase) scanNamespace(
+	ctx context.Context,
+	prefix []byte,
+	visit func(size int64) bool,
+) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for i := sort.SearchStrings(m.keys, string(prefix)); i < len(m.keys); i++ {
+		key := m.keys[i]
+		if !strings.HasPrefix(key, string(prefix)) {
+			break
+		}
+
+		version := m.get(key, m.commitTs)
+		if !version.visible(m.now()) {
+			continue
+		}
+
+		if !visit(int64(len(key) + len(version.value))) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// MemoryTransaction is a transaction on a MemoryDatabase
+// that implements the DatabaseTransaction interface.
+type MemoryTransaction struct {
+	db     *MemoryDatabase
+	rwLock sync.RWMutex
+
+	readTs uint64
+	update bool
+	done   bool
+
+	// released indicates that readTs is no
+	// longer tracked in activeReads.
+	released bool
+
+	// pending contains all writes that are
+	// not yet committed.
+	pending map[string]*memoryVersion
+
+	// reads contains all keys read by an update
+	// transaction. If any of these keys are modified
+	// by another transaction before this transaction
+	// commits, the commit fails.
+	readsLock sync.Mutex
+	reads     map[string]struct{}
+
+	holdGlobal bool
+	identifier string
+}
+
+func (m *MemoryDatabase) newTransaction(update bool) *MemoryTransaction {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.activeReads[m.commitTs]++
+
+	return &MemoryTransaction{
+		db:      m,
+		readTs:  m.commitTs,
+		update:  update,
+		pending: map[string]*memoryVersion{},
+		reads:   map[string]struct{}{},
+	}
+}
+
+// Transaction creates a new exclusive write MemoryTransaction.
+func (m *MemoryDatabase) Transaction(
+	ctx context.Context,
+) Transaction {
+	m.writer.GLock()
+
+	txn := m.newTransaction(true)
+	txn.holdGlobal = true
+
+	return txn
+}
+
+// ReadTransaction creates a new read MemoryTransaction.
+func (m *MemoryDatabase) ReadTransaction(
+	ctx context.Context,
+) Transaction {
+	return m.newTransaction(false)
+}
+
+// WriteTransaction creates a new write MemoryTransaction
+// for a particular identifier.
+func (m *MemoryDatabase) WriteTransaction(
+	ctx context.Context,
+	identifier string,
+	priority bool,
+) Transaction {
+	m.writer.Lock(identifier, priority)
+
+	txn := m.newTransaction(true)
+	txn.identifier = identifier
+
+	return txn
+}
+
+// get returns the version of a key visible
+// at readTs (or nil if none exists).
+func (m *MemoryDatabase) get(key string, readTs uint64) *memoryVersion {
+	versions := m.versions[key]
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].ts <= readTs {
+			return versions[i]
+		}
+	}
+
+	return nil
+}
+
+// minReadTs returns the lowest read timestamp
+// of any open transaction.
+func (m *MemoryDatabase) minReadTs() uint64 {
+	minTs := m.commitTs
+	for ts := range m.activeReads {
+		if ts < minTs {
+			minTs = ts
+		}
+	}
+
+	return minTs
+}
+
+// prune removes all versions of a key that are
+// not visible to any open transaction.
+func (m *MemoryDatabase) prune(key string, minTs uint64, now time.Time) {
+	versions := m.versions[key]
+
+	// Find the latest version visible at minTs. All
+	// versions before it can never be read again.
+	start := 0
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].ts <= minTs {
+			start = i
+			break
+		}
+	}
+	versions = versions[start:]
+
+	if len(versions) == 1 && !versions[0].visible(now) && versions[0].ts <= minTs {
+		delete(m.versions, key)
+
+		i := sort.SearchStrings(m.keys, key)
+		m.keys = append(m.keys[:i], m.keys[i+1:]...)
+		return
+	}
+
+	m.versions[key] = versions
+}
+
+// release stops tracking the read timestamp
+// of a transaction. This must be called while
+// holding the mutex.
+func (m *MemoryDatabase) release(txn *MemoryTransaction) {
+	if txn.released {
+		return
+	}
+
+	txn.released = true
+	m.activeReads[txn.readTs]--
+	if m.activeReads[txn.readTs] <= 0 {
+		delete(m.activeReads, txn.readTs)
+	}
+}
+
+// commit atomically applies all pending writes of
+// a transaction. It returns an error if any key read
+// by the transaction was modified after it started.
+func (m *MemoryDatabase) commit(txn *MemoryTransaction) error {
+	if !txn.update || len(txn.pending) == 0 {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// The committing transaction no longer needs
+	// any versions to be retained.
+	m.release(txn)
+
+	for key := range txn.reads {
+		versions := m.versions[key]
+		if len(versions) > 0 && versions[len(versions)-1].ts > txn.readTs {
+			return fmt.Errorf("%w: key %s", storageErrs.ErrTransactionConflict, key)
+		}
+	}
+
+	m.commitTs++
+	for key, write := range txn.pending {
+		if _, ok := m.versions[key]; !ok {
+			i := sort.SearchStrings(m.keys, key)
+			m.keys = append(m.keys, "")
+			copy(m.keys[i+1:], m.keys[i:])
+			m.keys[i] = key
+		}
+
+		m.versions[key] = append(m.versions[key], &memoryVersion{
+			ts:        m.commitTs,
+			value:     write.value,
+			deleted:   write.deleted,
+			expiresAt: write.expiresAt,
+		})
+	}
+
+	minTs := m.minReadTs()
+	now := m.now()
+	for key := range txn.pending {
+		m.prune(key, minTs, now)
+	}
+
+	return nil
+}
+
+// finish marks the transaction as done and
+// releases any held locks.
+func (t *MemoryTransaction) finish() {
+	t.done = true
+	t.pending = nil
+
+	t.db.mutex.Lock()
+	t.db.release(t)
+	t.db.mutex.Unlock()
+
+	if t.holdGlobal {
+		t.holdGlobal = false
+		t.db.writer.GUnlock()
+	}
+	if len(t.identifier) > 0 {
+		t.db.writer.Unlock(t.identifier)
+		t.identifier = ""
+	}
+}
+
+// Commit attempts to commit and discard the transaction.
+func (t *MemoryTransaction) Commit(context.Context) error {
+	t.rwLock.Lock()
+	defer t.rwLock.Unlock()
+
+	if t.done {
+		return fmt.Errorf("%w: %v", storageErrs.ErrCommitFailed, storageErrs.ErrTransactionDiscarded)
+	}
+
+	err := t.db.commit(t)
+	t.finish()
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrCommitFailed, err)
+	}
+
+	return nil
+}
+
+// Discard discards an open transaction. All transactions
+// must be either discarded or committed.
+func (t *MemoryTransaction) Discard(context.Context) {
+	t.rwLock.Lock()
+	defer t.rwLock.Unlock()
+
+	if t.done {
+		return
+	}
+
+	t.finish()
+}
+
+// Set changes the value of the key to the value within a transaction.
+// The value is copied, so reclaimValue has no effect.
+func (t *MemoryTransaction) Set(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	reclaimValue bool,
+) error {
+	t.rwLock.Lock()
+	defer t.rwLock.Unlock()
+
+	if err := t.checkWrite(key); err != nil {
+		return err
+	}
+
+	t.pending[string(key)] = &memoryVersion{
+		value: append([]byte{}, value...),
+	}
+
+	return nil
+}
+
+// SetWithTTL changes the value of the key to the value within
+// a transaction and deletes the key once the ttl has elapsed.
+// The value is copied, so reclaimValue has no effect.
+func (t *MemoryTransaction) SetWithTTL(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	ttl time.Duration,
+	reclaimValue bool,
+) error {
+	t.rwLock.Lock()
+	defer t.rwLock.Unlock()
+
+	if err := t.checkWrite(key); err != nil {
+		return err
+	}
+
+	if ttl <= 0 {
+		return fmt.Errorf("%w: %s", storageErrs.ErrInvalidTTL, ttl)
+	}
+
+	t.pending[string(key)] = &memoryVersion{
+		value:     append([]byte{}, value...),
+		expiresAt: t.db.now().Add(ttl),
+	}
+
+	return nil
+}
+
+// Get accesses the value of the key within a transaction.
+func (t *MemoryTransaction) Get(
+	ctx context.Context,
+	key []byte,
+) (bool, []byte, error) {
+	t.rwLock.RLock()
+	defer t.rwLock.RUnlock()
+
+	if t.done {
+		return false, nil, storageErrs.ErrTransactionDiscarded
+	}
+
+	k := string(key)
+	version, ok := t.pending[k]
+	if !ok {
+		t.recordRead(k)
+
+		t.db.mutex.RLock()
+		version = t.db.get(k, t.readTs)
+		t.db.mutex.RUnlock()
+	}
+
+	if !version.visible(t.db.now()) {
+		return false, nil, nil
+	}
+
+	return true, append([]byte{}, version.value...), nil
+}
+
+// Delete removes the key and its value within the transaction.
+func (t *MemoryTransaction) Delete(ctx context.Context, key []byte) error {
+	t.rwLock.Lock()
+	defer t.rwLock.Unlock()
+
+	if err := t.checkWrite(key); err != nil {
+		return err
+	}
+
+	t.pending[string(key)] = &memoryVersion{
+		deleted: true,
+	}
+
+	return nil
+}
+
+func (t *MemoryTransaction) checkWrite(key []byte) error {
+	if t.done {
+		return storageErrs.ErrTransactionDiscarded
+	}
+
+	if !t.update {
+		return storageErrs.ErrReadOnlyTransaction
+	}
+
+	if len(key) == 0 {
+		return storageErrs.ErrEmptyKey
+	}
+
+	return nil
+}
+
+func (t *MemoryTransaction) recordRead(key string) {
+	if !t.update {
+		return
+	}
+
+	t.readsLock.Lock()
+	t.reads[key] = struct{}{}
+	t.readsLock.Unlock()
+}
+
+// scanCandidates returns all committed and pending keys that could
+// be visited by a scan (in scan order). Like a badger iterator, a scan
+// starts at seekStart and stops at the first visible key without the
+// prefix, so keys between seekStart and the prefix are candidates.
+func (t *MemoryTransaction) scanCandidates(
+	prefix string,
+	seekStart string,
+	reverse bool,
+) []string {
+	// inRange returns false once a key is beyond
+	// the keys with prefix (in scan order).
+	inRange := func(k string) bool {
+		if reverse {
+			return k >= prefix
+		}
+
+		return k < prefix || strings.HasPrefix(k, prefix)
+	}
+
+	// fromSeek returns true if a key would be
+	// visited after seeking to seekStart.
+	fromSeek := func(k string) bool {
+		if reverse {
+			return k <= seekStart
+		}
+
+		return k >= seekStart
+	}
+
+	candidates := map[string]struct{}{}
+	t.db.mutex.RLock()
+	if reverse {
+		i := sort.Search(len(t.db.keys), func(i int) bool {
+			return t.db.keys[i] > seekStart
+		}) - 1
+		for ; i >= 0 && inRange(t.db.keys[i]); i-- {
+			candidates[t.db.keys[i]] = struct{}{}
+		}
+	} else {
+		i := sort.SearchStrings(t.db.keys, seekStart)
+		for ; i < len(t.db.keys) && inRange(t.db.keys[i]); i++ {
+			candidates[t.db.keys[i]] = struct{}{}
+		}
+	}
+	t.db.mutex.RUnlock()
+
+	for k := range t.pending {
+		if fromSeek(k) && inRange(k) {
+			candidates[k] = struct{}{}
+		}
+	}
+
+	keys := make([]string, 0, len(candidates))
+	for k := range candidates {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if reverse {
+			return keys[i] > keys[j]
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
+// Scan calls a worker for each item in a scan instead
+// of reading all items into memory.
+func (t *MemoryTransaction) Scan(
+	ctx context.Context,
+	prefix []byte,
+	seekStart []byte,
+	worker func([]byte, []byte) error,
+	logEntries bool,
+	reverse bool, // reverse == true means greatest to least
+) (int, error) {
+	return t.scan(ctx, prefix, seekStart, worker, logEntries, reverse, false)
+}
+
+// ScanKeys calls a worker for each key in a scan
+// without copying any values.
+func (t *MemoryTransaction) ScanKeys(
+	ctx context.Context,
+	prefix []byte,
+	seekStart []byte,
+	worker func([]byte, []byte) error,
+	logEntries bool,
+	reverse bool, // reverse == true means greatest to least
+) (int, error) {
+	return t.scan(ctx, prefix, seekStart, worker, logEntries, reverse, true)
+}
+
+func (t *MemoryTransaction) scan(
+	ctx context.Context,
+	prefix []byte,
+	seekStart []byte,
+	worker func([]byte, []byte) error,
+	logEntries bool,
+	reverse bool, // reverse == true means greatest to least
+	keysOnly bool,
+) (int, error) {
+	t.rwLock.RLock()
+	defer t.rwLock.RUnlock()
+
+	if t.done {
+		return -1, storageErrs.ErrTransactionDiscarded
+	}
+
+	entries := 0
+	for _, k := range t.scanCandidates(string(prefix), string(seekStart), reverse) {
+		version, ok := t.pending[k]
+		if !ok {
+			t.db.mutex.RLock()
+			version = t.db.get(k, t.readTs)
+			t.db.mutex.RUnlock()
+		}
+
+		// Skip keys that are not visible to
+		// this transaction.
+		if !version.visible(t.db.now()) {
+			continue
+		}
+
+		key := []byte(k)
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+
+		var value []byte
+		if !keysOnly {
+			value = append([]byte{}, version.value...)
+		}
+
+		t.recordRead(k)
+		if err := worker(key, value); err != nil {
+			return -1, fmt.Errorf("%w: worker failed for key %s", err, k)
+		}
+
+		entries++
+		if logEntries && entries%logModulo == 0 {
+			log.Printf("scanned %d entries for %s\n", entries, string(prefix))
+		}
+	}
+
+	return entries, nil
+}
+
+// ScanPage calls a worker for each item in a
+// page of a scan.
+func (t *MemoryTransaction) ScanPage(
+	ctx context.Context,
+	prefix []byte,
+	cursor []byte,
+	limit int,
+	worker func([]byte, []byte) error,
+	reverse bool, // reverse == true means greatest to least
+) ([]byte, error) {
+	return scanPage(ctx, t, prefix, cursor, limit, worker, reverse)
+}
+
+// MemoryBulkWriter is a BulkWriter on a MemoryDatabase.
+// Writes are buffered in a MemoryTransaction that never
+// reads any keys (so committing it never conflicts).
+type MemoryBulkWriter struct {
+	txn *MemoryTransaction
+
+	lock   sync.Mutex
+	closed bool
+}
+
+// BulkWrite creates a new MemoryBulkWriter.
+func (m *MemoryDatabase) BulkWrite(
+	ctx context.Context,
+) BulkWriter {
+	m.writer.GLock()
+
+	txn := m.newTransaction(true)
+	txn.holdGlobal = true
+
+	return &MemoryBulkWriter{txn: txn}
+}
+
+// Set adds a key-value pair to the MemoryBulkWriter.
+func (w *MemoryBulkWriter) Set(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	reclaimValue bool,
+) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return storageErrs.ErrBulkWriterClosed
+	}
+
+	return w.txn.Set(ctx, key, value, reclaimValue)
+}
+
+// Delete removes the key in the MemoryBulkWriter.
+func (w *MemoryBulkWriter) Delete(ctx context.Context, key []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return storageErrs.ErrBulkWriterClosed
+	}
+
+	return w.txn.Delete(ctx, key)
+}
+
+// Flush commits all writes in the MemoryBulkWriter.
+func (w *MemoryBulkWriter) Flush(ctx context.Context) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return storageErrs.ErrBulkWriterClosed
+	}
+
+	w.closed = true
+	if err := w.txn.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBulkWriteFailed, err)
+	}
+
+	return nil
+}
+
+// Cancel discards all writes in the MemoryBulkWriter.
+func (w *MemoryBulkWriter) Cancel(ctx context.Context) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return
+	}
+
+	w.closed = true
+	w.txn.Discard(ctx)
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
+)
+
+// MemoryOption is used to overwrite default values in
+// MemoryDatabase construction. Any Option not provided
+// falls back to the default value.
+type MemoryOption func(m *MemoryDatabase)
+
+// WithMemoryCompressorEntries provides zstd dictionaries
+// for given namespaces.
+func WithMemoryCompressorEntries(entries []*encoder.CompressorEntry) MemoryOption {
+	return func(m *MemoryDatabase) {
+		m.compress = true
+		m.compressorEntries = entries
+	}
+}
+
+// WithoutMemoryCompression disables zstd compression.
+func WithoutMemoryCompression() MemoryOption {
+	return func(m *MemoryDatabase) {
+		m.compress = false
+	}
+}
+
+// WithMemoryDictionary enables zstd compression and sets
+// the dictionary used to compress records in a namespace (see
+// encoder.WithDictionary).
+func WithMemoryDictionary(namespace string, dict []byte) MemoryOption {
+	return func(m *MemoryDatabase) {
+		m.compress = true
+		m.encoderOptions = append(m.encoderOptions, encoder.WithDictionary(namespace, dict))
+	}
+}
+
+// WithMemoryCodec encodes all records in a namespace
+// with the provided encoder.Codec.
+func WithMemoryCodec(namespace string, codec encoder.Codec) MemoryOption {
+	return func(m *MemoryDatabase) {
+		m.encoderOptions = append(m.encoderOptions, encoder.WithCodec(namespace, codec))
+	}
+}
+
+// WithMemoryWriterShards overrides the default shards used
+// in the writer utils.MutexMap. It is recommended
+// to set this value to your write concurrency to prevent
+// lock contention.
+func WithMemoryWriterShards(shards int) MemoryOption {
+	return func(m *MemoryDatabase) {
+		m.writerShards = shards
+	}
+}
+
+// WithMemoryStatsNamespaces enables counting the number of keys
+// (and their size) in each namespace when calling
+// GetDatabaseStats. At most sampleLimit keys are counted in
+// each namespace (0 means there is no limit), larger
+// namespaces are reported as Truncated.
+func WithMemoryStatsNamespaces(namespaces []string, sampleLimit int) MemoryOption {
+	return func(m *MemoryDatabase) {
+		m.statsNamespaces = namespaces
+		m.statsSampleLimit = sampleLimit
+	}
+}
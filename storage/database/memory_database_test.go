@@ -0,0 +1,152 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+func TestMemoryDatabase_Prune(t *testing.T) {
+	ctx := context.Background()
+
+	database, err := NewMemoryDatabase(ctx)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	m := database.(*MemoryDatabase)
+
+	t.Run("overwrite without open transactions", func(t *testing.T) {
+		for _, value := range []string{"1", "2", "3"} {
+			txn := database.Transaction(ctx)
+			assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte(value), true))
+			assert.NoError(t, txn.Commit(ctx))
+		}
+
+		assert.Equal(t, []string{"hello"}, m.keys)
+		assert.Len(t, m.versions["hello"], 1)
+		assert.Len(t, m.activeReads, 0)
+	})
+
+	t.Run("retain versions for open transactions", func(t *testing.T) {
+		readTxn := database.ReadTransaction(ctx)
+
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("4"), true))
+		assert.NoError(t, txn.Commit(ctx))
+		assert.Len(t, m.versions["hello"], 2)
+
+		exists, value, err := readTxn.Get(ctx, []byte("hello"))
+		assert.True(t, exists)
+		assert.Equal(t, []byte("3"), value)
+		assert.NoError(t, err)
+		readTxn.Discard(ctx)
+
+		// Old versions are pruned on the next write
+		txn = database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("5"), true))
+		assert.NoError(t, txn.Commit(ctx))
+		assert.Len(t, m.versions["hello"], 1)
+	})
+
+	t.Run("delete without open transactions", func(t *testing.T) {
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Delete(ctx, []byte("hello")))
+		assert.NoError(t, txn.Commit(ctx))
+
+		assert.Len(t, m.keys, 0)
+		assert.Len(t, m.versions, 0)
+	})
+
+	t.Run("use after commit", func(t *testing.T) {
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Commit(ctx))
+
+		assert.Error(t, txn.Set(ctx, []byte("hello"), []byte("1"), true))
+		_, _, err := txn.Get(ctx, []byte("hello"))
+		assert.Error(t, err)
+		assert.Error(t, txn.Commit(ctx))
+		txn.Discard(ctx)
+	})
+}
+
+func TestMemoryDatabase_TTL(t *testing.T) {
+	ctx := context.Background()
+
+	database, err := NewMemoryDatabase(ctx)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	m := database.(*MemoryDatabase)
+	now := time.Unix(1600000000, 0)
+	m.now = func() time.Time {
+		return now
+	}
+
+	txn := database.Transaction(ctx)
+	assert.NoError(t, txn.SetWithTTL(ctx, []byte("ttl/1"), []byte("1"), time.Minute, true))
+
+	// Pending writes expire as well
+	now = now.Add(time.Minute)
+	exists, _, err := txn.Get(ctx, []byte("ttl/1"))
+	assert.False(t, exists)
+	assert.NoError(t, err)
+	assert.NoError(t, txn.SetWithTTL(ctx, []byte("ttl/1"), []byte("1"), time.Minute, true))
+	assert.NoError(t, txn.Commit(ctx))
+
+	readTxn := database.ReadTransaction(ctx)
+	defer readTxn.Discard(ctx)
+
+	now = now.Add(time.Minute - time.Nanosecond)
+	exists, value, err := readTxn.Get(ctx, []byte("ttl/1"))
+	assert.True(t, exists)
+	assert.Equal(t, []byte("1"), value)
+	assert.NoError(t, err)
+
+	now = now.Add(time.Nanosecond)
+	exists, value, err = readTxn.Get(ctx, []byte("ttl/1"))
+	assert.False(t, exists)
+	assert.Nil(t, value)
+	assert.NoError(t, err)
+
+	entries, err := readTxn.Scan(
+		ctx,
+		[]byte("ttl/"),
+		[]byte("ttl/"),
+		func(k []byte, v []byte) error {
+			return nil
+		},
+		false,
+		false,
+	)
+	assert.Equal(t, 0, entries)
+	assert.NoError(t, err)
+
+	// Expired versions are retained until the key is written
+	// again or garbage is collected
+	assert.Len(t, m.versions["ttl/1"], 1)
+	assert.NoError(t, database.RunGarbageCollection(ctx, 0.5))
+	assert.NotContains(t, m.versions, "ttl/1")
+	assert.NotContains(t, m.keys, "ttl/1")
+
+	err = database.RunGarbageCollection(ctx, 1)
+	assert.True(t, errors.Is(err, storageErrs.ErrInvalidDiscardRatio))
+}
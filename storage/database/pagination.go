@@ -0,0 +1,91 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+const (
+	// reverseSeekSuffixLength is the number of 0xff bytes
+	// appended to a prefix to seek to the last key with
+	// that prefix in a reverse scan.
+	reverseSeekSuffixLength = 32
+)
+
+// errPageFull is returned by the Scan worker
+// in scanPage once the page is full.
+var errPageFull = errors.New("page full")
+
+// scanPage implements ScanPage for any Transaction
+// using Scan.
+func scanPage(
+	ctx context.Context,
+	txn Transaction,
+	prefix []byte,
+	cursor []byte,
+	limit int,
+	worker func([]byte, []byte) error,
+	reverse bool,
+) ([]byte, error) {
+	seekStart := cursor
+	switch {
+	case len(cursor) > 0 && !bytes.HasPrefix(cursor, prefix):
+		return nil, storageErrs.ErrInvalidScanCursor
+	case len(cursor) == 0 && reverse:
+		// Reverse scans start at the greatest key less
+		// than or equal to seekStart, so we must seek past
+		// all keys with the prefix.
+		seekStart = append(
+			append([]byte{}, prefix...),
+			bytes.Repeat([]byte{0xff}, reverseSeekSuffixLength)...,
+		)
+	case len(cursor) == 0:
+		seekStart = prefix
+	}
+
+	var next []byte
+	entries := 0
+	_, err := txn.Scan(
+		ctx,
+		prefix,
+		seekStart,
+		func(k []byte, v []byte) error {
+			if limit > 0 && entries >= limit {
+				next = make([]byte, len(k))
+				copy(next, k)
+
+				return errPageFull
+			}
+
+			entries++
+			return worker(k, v)
+		},
+		false,
+		reverse,
+	)
+	if errors.Is(err, errPageFull) {
+		return next, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
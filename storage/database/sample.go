@@ -0,0 +1,91 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+// SampleRecords returns up to maxSamples decompressed records
+// from a namespace of a database (with compression enabled),
+// for use as training data for encoder.TrainDictionary. Records
+// are selected uniformly at random from the entire namespace,
+// so all records in the namespace are scanned (in a single
+// read transaction). If maxSamples is not positive, all
+// records are returned.
+func SampleRecords(
+	ctx context.Context,
+	db Database,
+	namespace string,
+	maxSamples int,
+) ([][]byte, error) {
+	// We must use a restricted namespace or we will inadvertently
+	// fetch all namespaces that contain the namespace we care about.
+	restrictedNamespace := []byte(fmt.Sprintf("%s%s", namespace, namespaceSeparator))
+	random := rand.New(rand.NewSource(time.Now().UnixNano())) // #nosec G404
+
+	samples := [][]byte{}
+	seen := 0
+	txn := db.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+	_, err := txn.Scan(
+		ctx,
+		restrictedNamespace,
+		restrictedNamespace,
+		func(k []byte, v []byte) error {
+			seen++
+
+			// Reservoir sampling ensures each record is
+			// selected with the same probability without
+			// knowing the size of the namespace.
+			index := len(samples)
+			if maxSamples > 0 && len(samples) >= maxSamples {
+				index = random.Intn(seen)
+				if index >= maxSamples {
+					return nil
+				}
+			}
+
+			decompressed, err := db.Encoder().DecodeRaw(namespace, v)
+			if err != nil {
+				return fmt.Errorf("%w %s: %v", storageErrs.ErrDecompressFailed, string(k), err)
+			}
+
+			if index == len(samples) {
+				samples = append(samples, decompressed)
+			} else {
+				samples[index] = decompressed
+			}
+
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s: %v", storageErrs.ErrScanFailed, namespace, err)
+	}
+
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("%w %s", storageErrs.ErrNoEntriesFoundInNamespace, namespace)
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestSampleRecords(t *testing.T) {
+	ctx := context.Background()
+	database, err := NewMemoryDatabase(ctx)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	namespace := "block"
+	records := map[string]bool{}
+	txn := database.Transaction(ctx)
+	for i := 0; i < 500; i++ {
+		value, err := database.Encoder().Encode(namespace, &types.BlockIdentifier{
+			Index: int64(i),
+			Hash:  fmt.Sprintf("block %d", i),
+		})
+		assert.NoError(t, err)
+
+		decompressed, err := database.Encoder().DecodeRaw(namespace, value)
+		assert.NoError(t, err)
+		records[string(decompressed)] = true
+
+		key := []byte(fmt.Sprintf("%s/%d", namespace, i))
+		assert.NoError(t, txn.Set(ctx, key, value, false))
+	}
+
+	// Records in namespaces that share a prefix
+	// with namespace should not be sampled.
+	assert.NoError(t, txn.Set(ctx, []byte("block-index/1"), []byte("1"), false))
+	assert.NoError(t, txn.Commit(ctx))
+
+	tests := map[string]struct {
+		namespace  string
+		maxSamples int
+		samples    int
+		err        error
+	}{
+		"limited": {
+			namespace:  namespace,
+			maxSamples: 100,
+			samples:    100,
+		},
+		"no limit": {
+			namespace: namespace,
+			samples:   500,
+		},
+		"limit larger than namespace": {
+			namespace:  namespace,
+			maxSamples: 1000,
+			samples:    500,
+		},
+		"empty namespace": {
+			namespace:  "coin",
+			maxSamples: 100,
+			err:        storageErrs.ErrNoEntriesFoundInNamespace,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			samples, err := SampleRecords(ctx, database, test.namespace, test.maxSamples)
+			if test.err != nil {
+				assert.Nil(t, samples)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, samples, test.samples)
+
+			unique := map[string]bool{}
+			for _, sample := range samples {
+				assert.True(t, records[string(sample)])
+				unique[string(sample)] = true
+			}
+			assert.Len(t, unique, test.samples)
+		})
+	}
+
+	t.Run("train dictionary", func(t *testing.T) {
+		samples, err := SampleRecords(ctx, database, namespace, 0)
+		assert.NoError(t, err)
+
+		dict, err := encoder.TrainDictionary(ctx, namespace, samples, 0)
+		assert.NoError(t, err)
+
+		newDatabase, err := NewMemoryDatabase(ctx, WithMemoryDictionary(namespace, dict))
+		assert.NoError(t, err)
+		defer newDatabase.Close(ctx)
+
+		block := &types.BlockIdentifier{Index: 1000, Hash: "block 1000"}
+		value, err := newDatabase.Encoder().Encode(namespace, block)
+		assert.NoError(t, err)
+
+		var decoded types.BlockIdentifier
+		assert.NoError(t, newDatabase.Encoder().Decode(namespace, value, &decoded, false))
+		assert.Equal(t, block, &decoded)
+	})
+}
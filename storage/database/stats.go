@@ -0,0 +1,99 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultStatsTimeout is the maximum amount of time
+	// spent counting namespace stats if the context
+	// provided to GetDatabaseStats has no deadline.
+	DefaultStatsTimeout = 30 * time.Second
+
+	// namespaceSeparator separates a namespace
+	// from the rest of a key.
+	namespaceSeparator = "/"
+)
+
+// namespaceScanner invokes visit with the estimated
+// size of each key (and value) with some prefix until
+// visit returns false.
+type namespaceScanner func(
+	ctx context.Context,
+	prefix []byte,
+	visit func(size int64) bool,
+) error
+
+// namespaceStats counts the number of keys (and their size)
+// in each namespace. Each namespace is scanned until all keys
+// are visited, more than sampleLimit keys are found (if
+// sampleLimit > 0), or the context deadline is reached. In
+// the latter cases, the counts of the keys visited are
+// returned (without extrapolation) and marked as Truncated.
+func namespaceStats(
+	ctx context.Context,
+	namespaces []string,
+	sampleLimit int,
+	scan namespaceScanner,
+) ([]*NamespaceStats, error) {
+	if len(namespaces) == 0 {
+		return nil, nil
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultStatsTimeout)
+		defer cancel()
+	}
+
+	stats := make([]*NamespaceStats, len(namespaces))
+	for i, namespace := range namespaces {
+		current := &NamespaceStats{Namespace: namespace}
+		stats[i] = current
+
+		if ctx.Err() != nil {
+			current.Truncated = true
+			continue
+		}
+
+		prefix := []byte(namespace + namespaceSeparator)
+		err := scan(ctx, prefix, func(size int64) bool {
+			if ctx.Err() != nil ||
+				(sampleLimit > 0 && current.Keys >= int64(sampleLimit)) {
+				current.Truncated = true
+				return false
+			}
+
+			current.Keys++
+			current.Bytes += size
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Reaching the deadline only truncates the stats
+	// but cancellation should be surfaced to the caller.
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return nil, ctx.Err()
+	}
+
+	return stats, nil
+}
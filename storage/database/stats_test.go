@@ -0,0 +1,305 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+const (
+	// statsTolerance is the maximum relative difference
+	// between estimated and actual namespace sizes.
+	statsTolerance = 0.1
+)
+
+type statsNamespace struct {
+	namespace string
+	keys      int
+	valueSize int
+}
+
+var testStatsNamespaces = []*statsNamespace{
+	{namespace: "acc", keys: 300, valueSize: 100},
+	{namespace: "bal", keys: 150, valueSize: 200},
+	{namespace: "block", keys: 50, valueSize: 600},
+	{namespace: "block-index", keys: 20, valueSize: 10},
+	{namespace: "coin", keys: 0, valueSize: 0},
+}
+
+func statsKey(namespace string, i int) []byte {
+	return []byte(fmt.Sprintf("%s/%06d", namespace, i))
+}
+
+// statsDatabaseConstructor creates a new, empty Database that
+// breaks down stats by namespace and returns a function to
+// clean it up.
+type statsDatabaseConstructor func(
+	ctx context.Context,
+	t *testing.T,
+	namespaces []string,
+	sampleLimit int,
+) (Database, func())
+
+func TestGetDatabaseStats(t *testing.T) {
+	var constructors = map[string]statsDatabaseConstructor{
+		"badger": func(
+			ctx context.Context,
+			t *testing.T,
+			namespaces []string,
+			sampleLimit int,
+		) (Database, func()) {
+			newDir, err := utils.CreateTempDir()
+			assert.NoError(t, err)
+
+			database, err := NewBadgerDatabase(
+				ctx,
+				newDir,
+				WithIndexCacheSize(TinyIndexCacheSize),
+				WithStatsNamespaces(namespaces, sampleLimit),
+			)
+			assert.NoError(t, err)
+
+			return database, func() {
+				database.Close(ctx)
+				utils.RemoveTempDir(newDir)
+			}
+		},
+		"memory": func(
+			ctx context.Context,
+			t *testing.T,
+			namespaces []string,
+			sampleLimit int,
+		) (Database, func()) {
+			database, err := NewMemoryDatabase(
+				ctx,
+				WithMemoryStatsNamespaces(namespaces, sampleLimit),
+			)
+			assert.NoError(t, err)
+
+			return database, func() {
+				database.Close(ctx)
+			}
+		},
+	}
+
+	for name, newDatabase := range constructors {
+		t.Run(name, func(t *testing.T) {
+			testGetDatabaseStats(t, newDatabase)
+		})
+	}
+}
+
+func populateStatsNamespaces(ctx context.Context, t *testing.T, database Database) {
+	txn := database.Transaction(ctx)
+	for _, namespace := range testStatsNamespaces {
+		for i := 0; i < namespace.keys; i++ {
+			value := make([]byte, namespace.valueSize)
+			assert.NoError(t, txn.Set(ctx, statsKey(namespace.namespace, i), value, true))
+		}
+	}
+
+	// Deleted keys should not be counted
+	assert.NoError(t, txn.Set(ctx, statsKey("acc", 1000), []byte("hello"), true))
+	assert.NoError(t, txn.Commit(ctx))
+
+	txn = database.Transaction(ctx)
+	assert.NoError(t, txn.Delete(ctx, statsKey("acc", 1000)))
+	assert.NoError(t, txn.Commit(ctx))
+}
+
+func testGetDatabaseStats(t *testing.T, newDatabase statsDatabaseConstructor) {
+	namespaces := []string{}
+	for _, namespace := range testStatsNamespaces {
+		namespaces = append(namespaces, namespace.namespace)
+	}
+
+	t.Run("no namespaces", func(t *testing.T) {
+		ctx := context.Background()
+		database, cleanup := newDatabase(ctx, t, nil, 0)
+		defer cleanup()
+
+		populateStatsNamespaces(ctx, t, database)
+
+		stats, err := database.GetDatabaseStats(ctx)
+		assert.NoError(t, err)
+		assert.Greater(t, stats.LSMSize+stats.ValueLogSize, int64(0))
+		assert.Nil(t, stats.Namespaces)
+	})
+
+	t.Run("namespace breakdown", func(t *testing.T) {
+		ctx := context.Background()
+		database, cleanup := newDatabase(ctx, t, namespaces, 0)
+		defer cleanup()
+
+		populateStatsNamespaces(ctx, t, database)
+
+		stats, err := database.GetDatabaseStats(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, stats.Namespaces, len(testStatsNamespaces))
+		for i, namespace := range testStatsNamespaces {
+			namespaceStats := stats.Namespaces[i]
+			assert.Equal(t, namespace.namespace, namespaceStats.Namespace)
+			assert.Equal(t, int64(namespace.keys), namespaceStats.Keys)
+			assert.False(t, namespaceStats.Truncated)
+
+			expectedBytes := float64(
+				namespace.keys * (len(statsKey(namespace.namespace, 0)) + namespace.valueSize),
+			)
+			assert.InDelta(t, expectedBytes, float64(namespaceStats.Bytes), expectedBytes*statsTolerance)
+		}
+	})
+
+	t.Run("sample limit", func(t *testing.T) {
+		ctx := context.Background()
+		database, cleanup := newDatabase(ctx, t, namespaces, 100)
+		defer cleanup()
+
+		populateStatsNamespaces(ctx, t, database)
+
+		stats, err := database.GetDatabaseStats(ctx)
+		assert.NoError(t, err)
+		for i, namespace := range testStatsNamespaces {
+			namespaceStats := stats.Namespaces[i]
+			keys := namespace.keys
+			if namespace.keys > 100 {
+				keys = 100
+				assert.True(t, namespaceStats.Truncated)
+			} else {
+				assert.False(t, namespaceStats.Truncated)
+			}
+
+			// Truncated stats only include the keys counted
+			assert.Equal(t, int64(keys), namespaceStats.Keys)
+			expectedBytes := float64(
+				keys * (len(statsKey(namespace.namespace, 0)) + namespace.valueSize),
+			)
+			assert.InDelta(t, expectedBytes, float64(namespaceStats.Bytes), expectedBytes*statsTolerance)
+		}
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		ctx := context.Background()
+		database, cleanup := newDatabase(ctx, t, namespaces, 0)
+		defer cleanup()
+
+		populateStatsNamespaces(ctx, t, database)
+
+		deadlineCtx, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+		defer cancel()
+
+		stats, err := database.GetDatabaseStats(deadlineCtx)
+		assert.NoError(t, err)
+		assert.Len(t, stats.Namespaces, len(testStatsNamespaces))
+		for _, namespaceStats := range stats.Namespaces {
+			assert.Equal(t, int64(0), namespaceStats.Keys)
+			assert.True(t, namespaceStats.Truncated)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx := context.Background()
+		database, cleanup := newDatabase(ctx, t, namespaces, 0)
+		defer cleanup()
+
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		stats, err := database.GetDatabaseStats(canceledCtx)
+		assert.Nil(t, stats)
+		assert.True(t, errors.Is(err, storageErrs.ErrDatabaseStatsFailed))
+	})
+}
+
+// knownSizeScanner returns a namespaceScanner over a
+// namespace with keys entries of size bytes each.
+func knownSizeScanner(keys int, size int64) namespaceScanner {
+	return func(ctx context.Context, prefix []byte, visit func(size int64) bool) error {
+		for i := 0; i < keys; i++ {
+			if !visit(size) {
+				return nil
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestNamespaceStats(t *testing.T) {
+	var tests = map[string]struct {
+		keys        int
+		sampleLimit int
+
+		expectedKeys      int64
+		expectedBytes     int64
+		expectedTruncated bool
+	}{
+		"no limit": {
+			keys:          250,
+			expectedKeys:  250,
+			expectedBytes: 2500,
+		},
+		"limit above size": {
+			keys:          250,
+			sampleLimit:   300,
+			expectedKeys:  250,
+			expectedBytes: 2500,
+		},
+		"limit equal to size": {
+			keys:          250,
+			sampleLimit:   250,
+			expectedKeys:  250,
+			expectedBytes: 2500,
+		},
+		"limit below size": {
+			keys:              250,
+			sampleLimit:       100,
+			expectedKeys:      100,
+			expectedBytes:     1000,
+			expectedTruncated: true,
+		},
+		"empty": {
+			sampleLimit: 100,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			stats, err := namespaceStats(
+				context.Background(),
+				[]string{"known"},
+				test.sampleLimit,
+				knownSizeScanner(test.keys, 10),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, []*NamespaceStats{
+				{
+					Namespace: "known",
+					Keys:      test.expectedKeys,
+					Bytes:     test.expectedBytes,
+					Truncated: test.expectedTruncated,
+				},
+			}, stats)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	// codecMarker is prefixed to all records encoded with
+	// a Codec (followed by the ID of the Codec) before they are
+	// compressed. 0xc1 is never used in msgpack, so it can never
+	// be confused with a record written without a Codec (these
+	// are always plain msgpack).
+	codecMarker = byte(0xc1)
+
+	// codecHeaderSize is the number of bytes prefixed to
+	// each record encoded with a Codec.
+	codecHeaderSize = 2
+
+	// MsgpackCodecID is the ID of the MsgpackCodec.
+	MsgpackCodecID = byte(1)
+
+	// JSONCodecID is the ID of the JSONCodec.
+	JSONCodecID = byte(2)
+)
+
+// Codec is used to serialize objects before they
+// are (optionally) compressed. The ID of the Codec is
+// written with each record so that records written
+// by different Codecs can be stored in the same namespace.
+//
+// NOTE: The ID of a Codec must never change, otherwise
+// records previously written with it can no longer be decoded.
+type Codec interface {
+	ID() byte
+	Encode(w io.Writer, object interface{}) error
+	Decode(r io.Reader, object interface{}) error
+}
+
+// MsgpackCodec encodes objects using msgpack
+// (respecting json struct tags). This is the same
+// format used for records written without a Codec.
+type MsgpackCodec struct{}
+
+// ID returns MsgpackCodecID.
+func (c *MsgpackCodec) ID() byte {
+	return MsgpackCodecID
+}
+
+// Encode writes the msgpack encoding of object to w.
+func (c *MsgpackCodec) Encode(w io.Writer, object interface{}) error {
+	return getEncoder(w).Encode(object)
+}
+
+// Decode reads a msgpack-encoded object from r.
+func (c *MsgpackCodec) Decode(r io.Reader, object interface{}) error {
+	return getDecoder(r).Decode(object)
+}
+
+// JSONCodec encodes objects using encoding/json. It is
+// slower and larger than the MsgpackCodec but can be useful
+// when stored records must be readable by other tools.
+type JSONCodec struct{}
+
+// ID returns JSONCodecID.
+func (c *JSONCodec) ID() byte {
+	return JSONCodecID
+}
+
+// Encode writes the JSON encoding of object to w.
+func (c *JSONCodec) Encode(w io.Writer, object interface{}) error {
+	return json.NewEncoder(w).Encode(object)
+}
+
+// Decode reads a JSON-encoded object from r.
+func (c *JSONCodec) Decode(r io.Reader, object interface{}) error {
+	return json.NewDecoder(r).Decode(object)
+}
+
+// defaultCodecs returns all Codecs that can be
+// decoded without any configuration.
+func defaultCodecs() map[byte]Codec {
+	return map[byte]Codec{
+		MsgpackCodecID: &MsgpackCodec{},
+		JSONCodecID:    &JSONCodec{},
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+const (
+	// zstdFrameMagic and zstdDictionaryMagic are the
+	// (little-endian) magic numbers at the start of
+	// each zstd frame and zstd dictionary.
+	zstdFrameMagic      = 0xFD2FB528
+	zstdDictionaryMagic = 0xEC30A437
+
+	// zstdFrameHeaderDescriptor is the offset of the
+	// Frame_Header_Descriptor in a zstd frame.
+	zstdFrameHeaderDescriptor = 4
+
+	// samplePermissions are the permissions of
+	// samples written to disk for training.
+	samplePermissions = 0600
+
+	// sampleDirectory and dictionaryFile are the names
+	// of the files written by TrainDictionary in its
+	// temporary directory.
+	sampleDirectory = "samples"
+	dictionaryFile  = "dictionary"
+)
+
+// DictionaryID returns the ID of a zstd dictionary. Raw
+// content dictionaries (that don't use the zstd dictionary
+// format) do not have an ID, so 0 is returned.
+func DictionaryID(dict []byte) uint32 {
+	if len(dict) < 8 || binary.LittleEndian.Uint32(dict) != zstdDictionaryMagic {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint32(dict[4:8])
+}
+
+// frameDictionaryID returns the ID of the dictionary stored in
+// the header of a zstd frame (0 if the frame was compressed
+// without a dictionary or with a raw content dictionary). You can
+// read more about the frame header here:
+// https://github.com/facebook/zstd/blob/dev/doc/zstd_compression_format.md#frame_header
+func frameDictionaryID(frame []byte) uint32 {
+	if len(frame) <= zstdFrameHeaderDescriptor ||
+		binary.LittleEndian.Uint32(frame) != zstdFrameMagic {
+		return 0
+	}
+
+	descriptor := frame[zstdFrameHeaderDescriptor]
+	offset := zstdFrameHeaderDescriptor + 1
+
+	// The Window_Descriptor is omitted when the
+	// Single_Segment_flag is set.
+	if descriptor&0x20 == 0 {
+		offset++
+	}
+
+	var size int
+	switch descriptor & 0x3 {
+	case 0:
+		return 0
+	case 1:
+		size = 1
+	case 2:
+		size = 2
+	case 3:
+		size = 4
+	}
+
+	if len(frame) < offset+size {
+		return 0
+	}
+
+	var id uint32
+	for i := 0; i < size; i++ {
+		id |= uint32(frame[offset+i]) << (8 * i)
+	}
+
+	return id
+}
+
+// addDictionary sets the dictionary used to compress
+// records in a namespace. Dictionaries with an ID are
+// also retained to decompress records written with them,
+// even if another dictionary is later set for the namespace.
+func (e *Encoder) addDictionary(namespace string, dict []byte) {
+	e.compressionDicts[namespace] = dict
+
+	if id := DictionaryID(dict); id != 0 {
+		e.dictionaries[id] = dict
+	}
+}
+
+// decompressionDict returns the dictionary to use
+// to decompress input, using the dictionary ID in the
+// zstd frame header.
+func (e *Encoder) decompressionDict(namespace string, input []byte) ([]byte, error) {
+	id := frameDictionaryID(input)
+	if id != 0 {
+		dict, ok := e.dictionaries[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", errors.ErrUnknownDictionary, id)
+		}
+
+		return dict, nil
+	}
+
+	// Frames compressed with a zstd dictionary always
+	// include its ID, so input without an ID was either
+	// compressed without a dictionary or with a raw content
+	// dictionary.
+	dict := e.compressionDicts[namespace]
+	if DictionaryID(dict) != 0 {
+		return nil, nil
+	}
+
+	return dict, nil
+}
+
+// TrainDictionary trains a zstd dictionary for a namespace
+// using the zstd CLI (which must be installed). Samples should
+// be uncompressed records (like those returned by DecodeRaw). If
+// maxDictSize is not positive, the default size of zstd is used.
+//
+// The returned dictionary can be provided to WithDictionary.
+func TrainDictionary(
+	ctx context.Context,
+	namespace string,
+	samples [][]byte,
+	maxDictSize int,
+) ([]byte, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("%w for %s", errors.ErrNoSamples, namespace)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrCreateTempDirectoryFailed, err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sampleDir := path.Join(tmpDir, sampleDirectory)
+	if err := os.Mkdir(sampleDir, os.FileMode(0700)); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrCreateTempDirectoryFailed, err)
+	}
+
+	for i, sample := range samples {
+		err := ioutil.WriteFile(
+			path.Join(sampleDir, strconv.Itoa(i)),
+			sample,
+			os.FileMode(samplePermissions),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrBufferWriteFailed, err)
+		}
+	}
+
+	dictPath := path.Join(tmpDir, dictionaryFile)
+	args := []string{"--train", "-q", "-r", sampleDir, "-o", dictPath}
+	if maxDictSize > 0 {
+		args = append(args, fmt.Sprintf("--maxdict=%d", maxDictSize))
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "zstd", args...) // #nosec G204
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvokeZSTDFailed, err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf(
+			"%w for %s: %v %s",
+			errors.ErrTrainZSTDFailed,
+			namespace,
+			err,
+			stderr.String(),
+		)
+	}
+
+	dict, err := ioutil.ReadFile(path.Clean(dictPath))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrLoadDictFailed, err)
+	}
+
+	log.Printf(
+		"trained %dB zstd dictionary for %s from %d samples\n",
+		len(dict),
+		namespace,
+		len(samples),
+	)
+
+	return dict, nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	trainingNamespace = "balance"
+	trainingSamples   = 1000
+	trainingDictSize  = 16 << 10
+)
+
+// syntheticRecords returns msgpack-encoded records that
+// are highly repetitive (like most records of a namespace).
+func syntheticRecords(t *testing.T, symbol string, count int) [][]byte {
+	e, err := NewEncoder(nil, NewBufferPool(), false)
+	assert.NoError(t, err)
+
+	records := make([][]byte, count)
+	for i := 0; i < count; i++ {
+		record, err := e.Encode("", &types.AccountBalanceResponse{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: int64(i),
+				Hash:  fmt.Sprintf("0x%064x", i),
+			},
+			Balances: []*types.Amount{
+				{
+					Value: fmt.Sprintf("%d", i*100000),
+					Currency: &types.Currency{
+						Symbol:   symbol,
+						Decimals: 18,
+						Metadata: map[string]interface{}{
+							"contract_address": "0x6b175474e89094c44da98b954eedeac495271d0f",
+						},
+					},
+				},
+			},
+		})
+		assert.NoError(t, err)
+
+		records[i] = record
+	}
+
+	return records
+}
+
+func compressedSize(t *testing.T, e *Encoder, records [][]byte) int {
+	size := 0
+	for _, record := range records {
+		compressed, err := e.EncodeRaw(trainingNamespace, record)
+		assert.NoError(t, err)
+		size += len(compressed)
+	}
+
+	return size
+}
+
+func TestTrainDictionary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no samples", func(t *testing.T) {
+		dict, err := TrainDictionary(ctx, trainingNamespace, nil, trainingDictSize)
+		assert.Nil(t, dict)
+		assert.True(t, errors.Is(err, storageErrs.ErrNoSamples))
+	})
+
+	t.Run("ratio improvement", func(t *testing.T) {
+		records := syntheticRecords(t, "DAI", trainingSamples)
+		dict, err := TrainDictionary(ctx, trainingNamespace, records, trainingDictSize)
+		assert.NoError(t, err)
+		assert.True(t, len(dict) <= trainingDictSize)
+		assert.NotEqual(t, uint32(0), DictionaryID(dict))
+
+		normal, err := NewEncoder(nil, NewBufferPool(), true)
+		assert.NoError(t, err)
+		withDict, err := NewEncoder(
+			nil,
+			NewBufferPool(),
+			true,
+			WithDictionary(trainingNamespace, dict),
+		)
+		assert.NoError(t, err)
+
+		uncompressedSize := 0
+		for _, record := range records {
+			uncompressedSize += len(record)
+		}
+		normalSize := compressedSize(t, normal, records)
+		dictSize := compressedSize(t, withDict, records)
+		fmt.Printf(
+			"Uncompressed: %d, Compressed: %d, Compressed with Dictionary: %d\n",
+			uncompressedSize,
+			normalSize,
+			dictSize,
+		)
+
+		// Small, repetitive records barely compress without
+		// a dictionary.
+		assert.True(t, dictSize*2 < normalSize)
+	})
+}
+
+func TestDictionaryMigration(t *testing.T) {
+	ctx := context.Background()
+	oldRecords := syntheticRecords(t, "DAI", trainingSamples)
+	oldDict, err := TrainDictionary(ctx, trainingNamespace, oldRecords, trainingDictSize)
+	assert.NoError(t, err)
+
+	newRecords := syntheticRecords(t, "USDC", trainingSamples)
+	newDict, err := TrainDictionary(ctx, trainingNamespace, newRecords, trainingDictSize)
+	assert.NoError(t, err)
+	assert.NotEqual(t, DictionaryID(oldDict), DictionaryID(newDict))
+
+	noDictEncoder, err := NewEncoder(nil, NewBufferPool(), true)
+	assert.NoError(t, err)
+	oldEncoder, err := NewEncoder(
+		nil,
+		NewBufferPool(),
+		true,
+		WithDictionary(trainingNamespace, oldDict),
+	)
+	assert.NoError(t, err)
+	newEncoder, err := NewEncoder(
+		nil,
+		NewBufferPool(),
+		true,
+		WithDictionary(trainingNamespace, oldDict),
+		WithDictionary(trainingNamespace, newDict),
+	)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		encoder *Encoder
+		dictID  uint32
+	}{
+		"no dictionary": {
+			encoder: noDictEncoder,
+		},
+		"old dictionary": {
+			encoder: oldEncoder,
+			dictID:  DictionaryID(oldDict),
+		},
+		"new dictionary": {
+			encoder: newEncoder,
+			dictID:  DictionaryID(newDict),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			block := &types.BlockIdentifier{
+				Index: 100,
+				Hash:  name,
+			}
+			encoded, err := test.encoder.Encode(trainingNamespace, block)
+			assert.NoError(t, err)
+			assert.Equal(t, test.dictID, frameDictionaryID(encoded))
+
+			// The new encoder can read records written
+			// with any dictionary.
+			var decoded types.BlockIdentifier
+			assert.NoError(t, newEncoder.Decode(trainingNamespace, encoded, &decoded, false))
+			assert.Equal(t, block, &decoded)
+
+			// The old encoder can't read records written
+			// with the new dictionary.
+			_, err = oldEncoder.DecodeRaw(trainingNamespace, encoded)
+			if test.dictID == DictionaryID(newDict) {
+				assert.True(t, errors.Is(err, storageErrs.ErrUnknownDictionary))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
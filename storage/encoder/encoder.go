@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"log"
 	"path"
+	"reflect"
 	"strconv"
 
 	"github.com/DataDog/zstd"
@@ -41,13 +42,22 @@ const (
 // can be used by zstd. You can read more about these "dicts" here:
 // https://github.com/facebook/zstd#the-case-for-small-data-compression.
 //
-// NOTE: If you change these dicts, you will not be able
-// to decode previously encoded data. For many users, providing
-// no dicts is sufficient!
+// Records compressed with a zstd dictionary store its ID
+// in the zstd frame header, so a namespace can contain records
+// compressed with different dicts (as long as all of them are
+// provided on initialization).
+//
+// NOTE: If you remove a dict, you will not be able
+// to decode data previously encoded with it. For many users,
+// providing no dicts is sufficient!
 type Encoder struct {
 	compressionDicts map[string][]byte
+	dictionaries     map[uint32][]byte
 	pool             *BufferPool
 	compress         bool
+
+	namespaceCodecs map[string]Codec
+	codecs          map[byte]Codec
 }
 
 // CompressorEntry is used to initialize a dictionary compression.
@@ -63,8 +73,17 @@ func NewEncoder(
 	entries []*CompressorEntry,
 	pool *BufferPool,
 	compress bool,
+	options ...Option,
 ) (*Encoder, error) {
-	dicts := map[string][]byte{}
+	e := &Encoder{
+		compressionDicts: map[string][]byte{},
+		dictionaries:     map[uint32][]byte{},
+		pool:             pool,
+		compress:         compress,
+		namespaceCodecs:  map[string]Codec{},
+		codecs:           defaultCodecs(),
+	}
+
 	for _, entry := range entries {
 		b, err := ioutil.ReadFile(path.Clean(entry.DictionaryPath))
 		if err != nil {
@@ -77,14 +96,37 @@ func NewEncoder(
 		}
 
 		log.Printf("loaded zstd dictionary for %s\n", entry.Namespace)
-		dicts[entry.Namespace] = b
+		e.addDictionary(entry.Namespace, b)
 	}
 
-	return &Encoder{
-		compressionDicts: dicts,
-		pool:             pool,
-		compress:         compress,
-	}, nil
+	for _, opt := range options {
+		opt(e)
+	}
+
+	for namespace, codec := range e.namespaceCodecs {
+		if codec == nil || codec.ID() == 0 {
+			return nil, fmt.Errorf(
+				"%w: codec for %s must have a non-zero ID",
+				errors.ErrInvalidCodec,
+				namespace,
+			)
+		}
+
+		existing, ok := e.codecs[codec.ID()]
+		if ok && reflect.TypeOf(existing) != reflect.TypeOf(codec) {
+			return nil, fmt.Errorf(
+				"%w: codec for %s has ID %d, which is already used by %T",
+				errors.ErrInvalidCodec,
+				namespace,
+				codec.ID(),
+				existing,
+			)
+		}
+
+		e.codecs[codec.ID()] = codec
+	}
+
+	return e, nil
 }
 
 func getEncoder(w io.Writer) *msgpack.Encoder {
@@ -98,8 +140,7 @@ func getEncoder(w io.Writer) *msgpack.Encoder {
 // one exists for the namespace.
 func (e *Encoder) Encode(namespace string, object interface{}) ([]byte, error) {
 	buf := e.pool.Get()
-	err := getEncoder(buf).Encode(object)
-	if err != nil {
+	if err := e.encodeObject(buf, namespace, object); err != nil {
 		return nil, fmt.Errorf("%w: %v", errors.ErrObjectEncodeFailed, err)
 	}
 
@@ -116,6 +157,47 @@ func (e *Encoder) Encode(namespace string, object interface{}) ([]byte, error) {
 	return output, nil
 }
 
+// encodeObject writes object to buf using the Codec
+// of the namespace (prefixed with a codec header), if one
+// was provided. Otherwise, object is written with msgpack.
+func (e *Encoder) encodeObject(buf *bytes.Buffer, namespace string, object interface{}) error {
+	codec, ok := e.namespaceCodecs[namespace]
+	if !ok {
+		return getEncoder(buf).Encode(object)
+	}
+
+	if _, err := buf.Write([]byte{codecMarker, codec.ID()}); err != nil {
+		return err
+	}
+
+	return codec.Encode(buf, object)
+}
+
+// decodeObject decodes input into object using the Codec
+// in its codec header. Input without a codec header is
+// decoded with msgpack. ErrUnknownCodec is returned (instead
+// of ErrRawDecodeFailed) if the codec header is not recognized.
+func (e *Encoder) decodeObject(input []byte, object interface{}) error {
+	if len(input) < codecHeaderSize || input[0] != codecMarker {
+		if err := getDecoder(bytes.NewReader(input)).Decode(&object); err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrRawDecodeFailed, err)
+		}
+
+		return nil
+	}
+
+	codec, ok := e.codecs[input[1]]
+	if !ok {
+		return fmt.Errorf("%w: %d", errors.ErrUnknownCodec, input[1])
+	}
+
+	if err := codec.Decode(bytes.NewReader(input[codecHeaderSize:]), object); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrRawDecodeFailed, err)
+	}
+
+	return nil
+}
+
 // EncodeRaw only compresses an input, leaving encoding to the caller.
 // This is particularly useful for training a compressor.
 func (e *Encoder) EncodeRaw(namespace string, input []byte) ([]byte, error) {
@@ -143,14 +225,14 @@ func (e *Encoder) Decode(
 			return fmt.Errorf("%w: %v", errors.ErrRawDecompressFailed, err)
 		}
 
-		if err := getDecoder(bytes.NewReader(decompressed)).Decode(&object); err != nil {
-			return fmt.Errorf("%w: %v", errors.ErrRawDecodeFailed, err)
+		if err := e.decodeObject(decompressed, object); err != nil {
+			return err
 		}
 
 		e.pool.PutByteSlice(decompressed)
 	} else { // nolint:gocritic
-		if err := getDecoder(bytes.NewReader(input)).Decode(&object); err != nil {
-			return fmt.Errorf("%w: %v", errors.ErrRawDecodeFailed, err)
+		if err := e.decodeObject(input, object); err != nil {
+			return err
 		}
 	}
 
@@ -164,7 +246,12 @@ func (e *Encoder) Decode(
 // DecodeRaw only decompresses an input, leaving decoding to the caller.
 // This is particularly useful for training a compressor.
 func (e *Encoder) DecodeRaw(namespace string, input []byte) ([]byte, error) {
-	return e.decode(input, e.compressionDicts[namespace])
+	dict, err := e.decompressionDict(namespace, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.decode(input, dict)
 }
 
 func (e *Encoder) encode(input []byte, zstdDict []byte) ([]byte, error) {
@@ -0,0 +1,47 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+// Option is used to overwrite default values in
+// Encoder construction. Any Option not provided
+// falls back to the default value.
+type Option func(e *Encoder)
+
+// WithCodec encodes all records in a namespace with
+// the provided Codec. Records already stored in the
+// namespace (with or without a Codec) can still be
+// decoded, so it is safe to change the Codec of a
+// namespace in an existing database.
+//
+// Namespaces without a Codec are encoded with msgpack
+// (without a codec header) so that they can be read by
+// versions of this package without Codec support.
+func WithCodec(namespace string, codec Codec) Option {
+	return func(e *Encoder) {
+		e.namespaceCodecs[namespace] = codec
+	}
+}
+
+// WithDictionary sets the zstd dictionary used to compress
+// records in a namespace (overriding any CompressorEntry).
+// Records compressed with any dictionary previously provided
+// for the namespace can still be decompressed, so providing
+// the old and then the new dictionary migrates a namespace
+// to the new dictionary.
+func WithDictionary(namespace string, dict []byte) Option {
+	return func(e *Encoder) {
+		e.addDictionary(namespace, dict)
+	}
+}
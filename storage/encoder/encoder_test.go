@@ -16,12 +16,14 @@ package encoder
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/sync/errgroup"
 
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -329,3 +331,261 @@ func TestEncodeDecodeAccountCurrency(t *testing.T) {
 		})
 	}
 }
+
+type zeroIDCodec struct {
+	JSONCodec
+}
+
+func (c *zeroIDCodec) ID() byte {
+	return 0
+}
+
+type conflictingCodec struct {
+	MsgpackCodec
+}
+
+func (c *conflictingCodec) ID() byte {
+	return JSONCodecID
+}
+
+func TestNewEncoderCodecs(t *testing.T) {
+	tests := map[string]struct {
+		codec Codec
+		err   error
+	}{
+		"msgpack": {
+			codec: &MsgpackCodec{},
+		},
+		"json": {
+			codec: &JSONCodec{},
+		},
+		"nil codec": {
+			err: storageErrs.ErrInvalidCodec,
+		},
+		"zero ID": {
+			codec: &zeroIDCodec{},
+			err:   storageErrs.ErrInvalidCodec,
+		},
+		"conflicting ID": {
+			codec: &conflictingCodec{},
+			err:   storageErrs.ErrInvalidCodec,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, err := NewEncoder(nil, NewBufferPool(), true, WithCodec("ns", test.codec))
+			if test.err != nil {
+				assert.Nil(t, e)
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NotNil(t, e)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCodecMigration(t *testing.T) {
+	namespace := "ns"
+	tests := map[string]struct {
+		compress bool
+		codecs   []Codec
+	}{
+		"compressed": {
+			compress: true,
+			codecs:   []Codec{&JSONCodec{}, &MsgpackCodec{}},
+		},
+		"uncompressed": {
+			compress: false,
+			codecs:   []Codec{&JSONCodec{}, &MsgpackCodec{}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pool := NewBufferPool()
+			legacy, err := NewEncoder(nil, pool, test.compress)
+			assert.NoError(t, err)
+
+			// Write records without a codec and then with each
+			// codec so that all formats exist in the namespace.
+			encoders := []*Encoder{legacy}
+			for _, codec := range test.codecs {
+				e, err := NewEncoder(nil, pool, test.compress, WithCodec(namespace, codec))
+				assert.NoError(t, err)
+				encoders = append(encoders, e)
+			}
+
+			records := [][]byte{}
+			blocks := []*types.Block{}
+			for i, e := range encoders {
+				block := &types.Block{
+					BlockIdentifier: &types.BlockIdentifier{
+						Index: int64(i),
+						Hash:  fmt.Sprintf("block %d", i),
+					},
+					ParentBlockIdentifier: &types.BlockIdentifier{
+						Index: int64(i),
+						Hash:  fmt.Sprintf("block %d", i),
+					},
+					Metadata: map[string]interface{}{
+						"encoder": fmt.Sprintf("%d", i),
+					},
+				}
+
+				record, err := e.Encode(namespace, block)
+				assert.NoError(t, err)
+
+				if !test.compress {
+					// Only records written with a codec
+					// have a codec header.
+					assert.Equal(t, i > 0, record[0] == codecMarker)
+				}
+
+				records = append(records, record)
+				blocks = append(blocks, block)
+			}
+
+			// Every encoder (including the legacy encoder for
+			// records written with a built-in codec) can
+			// read all records in the namespace.
+			for _, e := range encoders {
+				for i, record := range records {
+					input := make([]byte, len(record))
+					copy(input, record)
+
+					var decoded types.Block
+					assert.NoError(t, e.Decode(namespace, input, &decoded, false))
+					assert.Equal(t, types.Hash(blocks[i]), types.Hash(decoded))
+				}
+			}
+
+			// Other namespaces are still written without a codec.
+			other, err := encoders[1].Encode("other", blocks[0])
+			assert.NoError(t, err)
+
+			var decoded types.Block
+			assert.NoError(t, legacy.Decode("other", other, &decoded, false))
+			assert.Equal(t, types.Hash(blocks[0]), types.Hash(decoded))
+		})
+	}
+}
+
+func TestDecodeUnknownCodec(t *testing.T) {
+	e, err := NewEncoder(nil, NewBufferPool(), false)
+	assert.NoError(t, err)
+
+	var decoded types.Block
+	err = e.Decode("", []byte{codecMarker, 100, 0x80}, &decoded, false)
+	assert.True(t, errors.Is(err, storageErrs.ErrUnknownCodec))
+}
+
+var (
+	benchmarkBalance = &types.AccountBalanceResponse{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: 1000,
+			Hash:  "block 1000",
+		},
+		Balances: []*types.Amount{
+			{
+				Value: "1000000000",
+				Currency: &types.Currency{
+					Symbol:   "BTC",
+					Decimals: 8,
+				},
+			},
+		},
+	}
+
+	benchmarkCodecs = map[string]Codec{
+		"none":    nil,
+		"msgpack": &MsgpackCodec{},
+		"json":    &JSONCodec{},
+	}
+)
+
+func benchmarkLargeBlock(operations int) *types.Block {
+	ops := make([]*types.Operation, operations)
+	for i := 0; i < operations; i++ {
+		ops[i] = &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: int64(i),
+			},
+			Type:   "Transfer",
+			Status: types.String("Success"),
+			Account: &types.AccountIdentifier{
+				Address: fmt.Sprintf("addr %d", i),
+			},
+			Amount: &types.Amount{
+				Value: fmt.Sprintf("%d", i),
+				Currency: &types.Currency{
+					Symbol:   "BTC",
+					Decimals: 8,
+				},
+			},
+		}
+	}
+
+	return &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: 1000,
+			Hash:  "block 1000",
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Index: 999,
+			Hash:  "block 999",
+		},
+		Timestamp: 1600000000000,
+		Transactions: []*types.Transaction{
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: ops,
+			},
+		},
+	}
+}
+
+func benchmarkCodec(
+	b *testing.B,
+	object interface{},
+	decode func(e *Encoder, input []byte) error,
+) {
+	for name, codec := range benchmarkCodecs {
+		options := []Option{}
+		if codec != nil {
+			options = append(options, WithCodec("", codec))
+		}
+
+		e, err := NewEncoder(nil, NewBufferPool(), true, options...)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				// encode
+				result, _ := e.Encode("", object)
+
+				// decode
+				_ = decode(e, result)
+			}
+		})
+	}
+}
+
+func BenchmarkCodecBalance(b *testing.B) {
+	benchmarkCodec(b, benchmarkBalance, func(e *Encoder, input []byte) error {
+		var decoded types.AccountBalanceResponse
+		return e.Decode("", input, &decoded, true)
+	})
+}
+
+func BenchmarkCodecBlock(b *testing.B) {
+	benchmarkCodec(b, benchmarkLargeBlock(1000), func(e *Encoder, input []byte) error {
+		var decoded types.Block
+		return e.Decode("", input, &decoded, true)
+	})
+}
@@ -16,8 +16,10 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 
 	utils "github.com/coinbase/rosetta-sdk-go/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 // Badger Storage Errors
@@ -43,6 +45,19 @@ var (
 	ErrInvokeZSTDFailed           = errors.New("unable to start zstd")
 	ErrTrainZSTDFailed            = errors.New("unable to train zstd")
 	ErrWalkFilesFailed            = errors.New("unable to walk files")
+	ErrInvalidBadgerOptions       = errors.New("invalid badger options")
+	ErrDatabaseStatsFailed        = errors.New("unable to get database stats")
+	ErrBulkWriteFailed            = errors.New("unable to flush bulk write")
+	ErrBulkWriterClosed           = errors.New("bulk writer already flushed or canceled")
+	ErrBulkWriteTTLUnsupported    = errors.New("bulk writes do not support ttls")
+	ErrInvalidScanCursor          = errors.New("scan cursor does not have prefix")
+	ErrReadOnlyDatabase           = errors.New("cannot write to read-only database")
+	ErrEncryptionKeyMismatch      = errors.New("encryption key does not match database")
+	ErrDeriveEncryptionKeyFailed  = errors.New("unable to derive encryption key")
+	ErrInvalidTTL                 = errors.New("ttl must be positive")
+	ErrInvalidDiscardRatio        = errors.New("discard ratio must be in (0, 1)")
+	ErrGarbageCollectionFailed    = errors.New("unable to run garbage collection")
+	ErrSaveDictFailed             = errors.New("unable to save dictionary")
 
 	BadgerStorageErrs = []error{
 		ErrDatabaseOpenFailed,
@@ -66,6 +81,45 @@ var (
 		ErrInvokeZSTDFailed,
 		ErrTrainZSTDFailed,
 		ErrWalkFilesFailed,
+		ErrInvalidBadgerOptions,
+		ErrDatabaseStatsFailed,
+		ErrBulkWriteFailed,
+		ErrBulkWriterClosed,
+		ErrBulkWriteTTLUnsupported,
+		ErrInvalidScanCursor,
+		ErrReadOnlyDatabase,
+		ErrEncryptionKeyMismatch,
+		ErrDeriveEncryptionKeyFailed,
+		ErrInvalidTTL,
+		ErrInvalidDiscardRatio,
+		ErrGarbageCollectionFailed,
+		ErrSaveDictFailed,
+	}
+)
+
+// Memory Storage Errors
+var (
+	// ErrTransactionDiscarded is returned when a transaction
+	// is used after it has been committed or discarded.
+	ErrTransactionDiscarded = errors.New("transaction already committed or discarded")
+
+	// ErrReadOnlyTransaction is returned when attempting to
+	// modify data in a read-only transaction.
+	ErrReadOnlyTransaction = errors.New("cannot modify data in read-only transaction")
+
+	// ErrEmptyKey is returned when attempting to set an empty key.
+	ErrEmptyKey = errors.New("key cannot be empty")
+
+	// ErrTransactionConflict is returned when a transaction
+	// read a key that was modified by another transaction
+	// committed after it started.
+	ErrTransactionConflict = errors.New("transaction conflict")
+
+	MemoryStorageErrs = []error{
+		ErrTransactionDiscarded,
+		ErrReadOnlyTransaction,
+		ErrEmptyKey,
+		ErrTransactionConflict,
 	}
 )
 
@@ -107,6 +161,37 @@ var (
 	ErrBroadcastCommitDeleteFailed        = errors.New("unable to commit broadcast delete")
 	ErrBroadcastPerformFailed             = errors.New("unable to perform broadcast")
 
+	ErrBroadcastHandleExpirationUnsuccessful = errors.New("unable to handle broadcast expiration")
+
+	// ErrBroadcastNotFound is returned when there is no broadcast
+	// status for a *types.TransactionIdentifier.
+	ErrBroadcastNotFound = errors.New("broadcast not found")
+
+	ErrBroadcastStatusGetFailed    = errors.New("unable to get broadcast status")
+	ErrBroadcastStatusEncodeFailed = errors.New("unable to encode broadcast status")
+	ErrBroadcastStatusDecodeFailed = errors.New("unable to decode broadcast status")
+	ErrBroadcastStatusUpdateFailed = errors.New("unable to update broadcast status")
+	ErrBroadcastStatusScanFailed   = errors.New("unable to scan for broadcast statuses")
+
+	// ErrBroadcastLimitExceeded, ErrBroadcastAttemptsExceeded,
+	// ErrBroadcastCleared, and ErrBroadcastExpired are provided to
+	// BroadcastFailureHandler.BroadcastFailedWithCause to describe
+	// why a broadcast failed. ErrBroadcastExpired is only provided
+	// when the handler does not implement BroadcastExpirationHandler.
+	ErrBroadcastLimitExceeded    = errors.New("exceeded broadcast limit")
+	ErrBroadcastAttemptsExceeded = errors.New("exceeded maximum rejected broadcast attempts")
+	ErrBroadcastCleared          = errors.New("broadcast cleared")
+	ErrBroadcastExpired          = errors.New("broadcast expired")
+
+	// ErrBroadcastPrerequisiteFailed is provided to
+	// BroadcastFailureHandler.BroadcastFailedWithCause when a
+	// prerequisite of a broadcast fails or expires.
+	ErrBroadcastPrerequisiteFailed = errors.New("prerequisite broadcast failed")
+
+	// ErrBroadcastRequeueConfirmed is returned when attempting
+	// to requeue a broadcast that has already been confirmed.
+	ErrBroadcastRequeueConfirmed = errors.New("cannot requeue confirmed broadcast")
+
 	BroadcastStorageErrs = []error{
 		ErrBroadcastTxStale,
 		ErrBroadcastTxConfirmed,
@@ -131,6 +216,19 @@ var (
 		ErrBroadcastHandleFailureUnsuccessful,
 		ErrBroadcastCommitDeleteFailed,
 		ErrBroadcastPerformFailed,
+		ErrBroadcastHandleExpirationUnsuccessful,
+		ErrBroadcastNotFound,
+		ErrBroadcastStatusGetFailed,
+		ErrBroadcastStatusEncodeFailed,
+		ErrBroadcastStatusDecodeFailed,
+		ErrBroadcastStatusUpdateFailed,
+		ErrBroadcastStatusScanFailed,
+		ErrBroadcastLimitExceeded,
+		ErrBroadcastAttemptsExceeded,
+		ErrBroadcastCleared,
+		ErrBroadcastExpired,
+		ErrBroadcastPrerequisiteFailed,
+		ErrBroadcastRequeueConfirmed,
 	}
 )
 
@@ -151,6 +249,7 @@ var (
 		"unable to to determine if should skip operation",
 	)
 	ErrDuplicateCoinFound           = errors.New("duplicate coin found")
+	ErrCoinChangesParseFailed       = errors.New("unable to parse coin changes")
 	ErrCoinRemoveFailed             = errors.New("unable to remove coin")
 	ErrAccountIdentifierQueryFailed = errors.New("unable to query account identifier")
 	ErrCurrentBlockGetFailed        = errors.New("unable to get current block identifier")
@@ -174,6 +273,7 @@ var (
 		ErrOperationParseFailed,
 		ErrUnableToDetermineIfSkipOperation,
 		ErrDuplicateCoinFound,
+		ErrCoinChangesParseFailed,
 		ErrCoinRemoveFailed,
 		ErrAccountIdentifierQueryFailed,
 		ErrCurrentBlockGetFailed,
@@ -197,6 +297,10 @@ var (
 	ErrObjectDecodeFailed  = errors.New("unable to decode object")
 	ErrReaderCloseFailed   = errors.New("unable to close reader")
 	ErrCopyBlockFailed     = errors.New("unable to copy block")
+	ErrUnknownCodec        = errors.New("unknown codec")
+	ErrInvalidCodec        = errors.New("invalid codec")
+	ErrUnknownDictionary   = errors.New("unknown dictionary")
+	ErrNoSamples           = errors.New("no samples provided")
 
 	CompressorErrs = []error{
 		ErrLoadDictFailed,
@@ -209,6 +313,10 @@ var (
 		ErrObjectDecodeFailed,
 		ErrReaderCloseFailed,
 		ErrCopyBlockFailed,
+		ErrUnknownCodec,
+		ErrInvalidCodec,
+		ErrUnknownDictionary,
+		ErrNoSamples,
 	}
 )
 
@@ -232,6 +340,9 @@ var (
 	ErrJobMetadataUpdateFailed       = errors.New("unable to update metadata")
 	ErrJobDoesNotExist               = errors.New("job does not exist")
 	ErrJobDecodeFailed               = errors.New("unable to decode job")
+	ErrJobIndexReadFailed            = errors.New("unable to read job index status")
+	ErrJobIndexBackfillFailed        = errors.New("unable to backfill job index")
+	ErrJobFindFailed                 = errors.New("unable to find jobs")
 
 	JobStorageErrs = []error{
 		ErrJobsGetAllFailed,
@@ -252,6 +363,9 @@ var (
 		ErrJobMetadataUpdateFailed,
 		ErrJobDoesNotExist,
 		ErrJobDecodeFailed,
+		ErrJobIndexReadFailed,
+		ErrJobIndexBackfillFailed,
+		ErrJobFindFailed,
 	}
 )
 
@@ -280,6 +394,37 @@ var (
 	ErrPrefundedAcctStoreFailed = errors.New("unable to store prefunded account")
 	ErrRandomAddress            = errors.New("cannot select random address")
 
+	// ErrAddrConflict is returned when a prefunded account
+	// is imported with a different key than the one already
+	// stored for its address.
+	ErrAddrConflict = errors.New("conflicting key already stored for address")
+
+	// ErrPrefundedAcctMismatch is returned when the account derived
+	// from an imported private key does not match the provided
+	// AccountIdentifier.
+	ErrPrefundedAcctMismatch = errors.New("derived account does not match prefunded account")
+
+	// ErrPrefundedAcctDeriveFailed is returned when the
+	// KeyStorageHelper cannot derive an account from the
+	// public key of an imported private key.
+	ErrPrefundedAcctDeriveFailed = errors.New("unable to derive prefunded account")
+
+	// ErrPrefundedAcctSubAccount is returned when a prefunded
+	// account specifies a SubAccount (keys can only be
+	// associated with an address).
+	ErrPrefundedAcctSubAccount = errors.New("prefunded account cannot specify a sub-account")
+
+	// ErrPrefundedAcctMissingAccount is returned when a
+	// prefunded account does not specify an AccountIdentifier.
+	ErrPrefundedAcctMissingAccount = errors.New("prefunded account identifier is missing")
+
+	ErrDeleteKeyFailed = errors.New("unable to delete key")
+
+	// ErrKeyCompactionFailed is returned when a key is deleted
+	// but the KeyStorageCompactionHelper cannot compact the
+	// database.
+	ErrKeyCompactionFailed = errors.New("unable to compact deleted keys")
+
 	KeyStorageErrs = []error{
 		ErrAddrExists,
 		ErrAddrCheckIfExistsFailed,
@@ -300,9 +445,69 @@ var (
 		ErrAddrImportFailed,
 		ErrPrefundedAcctStoreFailed,
 		ErrRandomAddress,
+		ErrAddrConflict,
+		ErrPrefundedAcctMismatch,
+		ErrPrefundedAcctDeriveFailed,
+		ErrPrefundedAcctSubAccount,
+		ErrPrefundedAcctMissingAccount,
+		ErrDeleteKeyFailed,
+		ErrKeyCompactionFailed,
 	}
 )
 
+// SignPayloadError is returned when a payload cannot
+// be signed by KeyStorage.SignPayloads. It identifies the
+// payload (by index) and the account that should have
+// signed it.
+type SignPayloadError struct {
+	Index   int
+	Account *types.AccountIdentifier
+	Err     error
+}
+
+// Error returns a description of why the
+// payload could not be signed.
+func (e *SignPayloadError) Error() string {
+	return fmt.Sprintf(
+		"%s: payload %d for %s",
+		e.Err.Error(),
+		e.Index,
+		types.PrintStruct(e.Account),
+	)
+}
+
+// Unwrap returns the error that caused
+// signing to fail.
+func (e *SignPayloadError) Unwrap() error {
+	return e.Err
+}
+
+// AddrImportError is returned when the private key of
+// a prefunded account cannot be imported. It matches
+// ErrAddrImportFailed and unwraps to the keys error that
+// caused the import to fail.
+type AddrImportError struct {
+	Err error
+}
+
+// Error returns a description of why the
+// private key could not be imported.
+func (e *AddrImportError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrAddrImportFailed.Error(), e.Err.Error())
+}
+
+// Unwrap returns the keys error that caused
+// the import to fail.
+func (e *AddrImportError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is ErrAddrImportFailed (so that
+// errors.Is can be used on an *AddrImportError).
+func (e *AddrImportError) Is(target error) bool {
+	return target == ErrAddrImportFailed
+}
+
 // Balance Storage Errors
 var (
 	// ErrNegativeBalance is returned when an account
@@ -336,6 +541,11 @@ var (
 
 	ErrHelperHandlerMissing = errors.New("balance storage helper or handler is missing")
 
+	// ErrBlockHelperMissing is returned when operations are
+	// looked up but the helper does not implement
+	// BalanceStorageBlockHelper.
+	ErrBlockHelperMissing = errors.New("balance storage block helper is missing")
+
 	BalanceStorageErrs = []error{
 		ErrNegativeBalance,
 		ErrInvalidLiveBalance,
@@ -345,6 +555,32 @@ var (
 		ErrInvalidChangeValue,
 		ErrInvalidValue,
 		ErrHelperHandlerMissing,
+		ErrBlockHelperMissing,
+	}
+)
+
+// Reconciler Queue Storage Errors
+var (
+	// ErrReconcilerQueueEncodeFailed is returned when a
+	// *parser.BalanceChange cannot be encoded.
+	ErrReconcilerQueueEncodeFailed = errors.New("unable to encode queued balance change")
+
+	// ErrReconcilerQueueDecodeFailed is returned when a
+	// *parser.BalanceChange cannot be decoded.
+	ErrReconcilerQueueDecodeFailed = errors.New("unable to decode queued balance change")
+
+	ErrReconcilerQueueSetFailed    = errors.New("unable to store queued balance change")
+	ErrReconcilerQueueDeleteFailed = errors.New("unable to delete queued balance change")
+	ErrReconcilerQueueScanFailed   = errors.New("unable to scan queued balance changes")
+	ErrReconcilerQueueCommitFailed = errors.New("unable to commit reconciler queue update")
+
+	ReconcilerQueueStorageErrs = []error{
+		ErrReconcilerQueueEncodeFailed,
+		ErrReconcilerQueueDecodeFailed,
+		ErrReconcilerQueueSetFailed,
+		ErrReconcilerQueueDeleteFailed,
+		ErrReconcilerQueueScanFailed,
+		ErrReconcilerQueueCommitFailed,
 	}
 )
 
@@ -455,9 +691,11 @@ func Err(err error) (bool, string) {
 		"coin storage error":      CoinStorageErrs,
 		"key storage error":       KeyStorageErrs,
 		"badger storage error":    BadgerStorageErrs,
+		"memory storage error":    MemoryStorageErrs,
 		"compressor error":        CompressorErrs,
 		"job storage error":       JobStorageErrs,
 		"broadcast storage error": BroadcastStorageErrs,
+		"reconciler queue error":  ReconcilerQueueStorageErrs,
 	}
 
 	for key, val := range storageErrs {
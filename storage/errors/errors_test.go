@@ -47,6 +47,11 @@ func TestErr(t *testing.T) {
 			is:     true,
 			source: "badger storage error",
 		},
+		"memory storage error": {
+			err:    ErrTransactionConflict,
+			is:     true,
+			source: "memory storage error",
+		},
 		"broadcast storage error": {
 			err:    ErrBroadcastTxStale,
 			is:     true,
@@ -67,6 +72,11 @@ func TestErr(t *testing.T) {
 			is:     true,
 			source: "key storage error",
 		},
+		"reconciler queue error": {
+			err:    ErrReconcilerQueueScanFailed,
+			is:     true,
+			source: "reconciler queue error",
+		},
 		"not a storage error": {
 			err:    errors.New("blah"),
 			is:     false,
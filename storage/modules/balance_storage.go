@@ -15,12 +15,14 @@
 package modules
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"log"
 	"math/big"
 	"runtime"
+	"strconv"
 	"sync"
 
 	"github.com/neilotoole/errgroup"
@@ -119,6 +121,23 @@ type BalanceStorageHandler interface {
 	AccountsSeen(ctx context.Context, dbTx database.Transaction, count int) error
 }
 
+// BalanceStorageDetailedHandler is an optional extension of BalanceStorageHandler.
+// If the handler provided to BalanceStorage implements it, BlockAddedDetailed
+// and BlockRemovedDetailed are invoked instead of BlockAdded and BlockRemoved
+// with the per-category subtotals of each balance change.
+type BalanceStorageDetailedHandler interface {
+	BlockAddedDetailed(
+		ctx context.Context,
+		block *types.Block,
+		changes []*parser.BalanceChangeDetailed,
+	) error
+	BlockRemovedDetailed(
+		ctx context.Context,
+		block *types.Block,
+		changes []*parser.BalanceChangeDetailed,
+	) error
+}
+
 // BalanceStorageHelper functions are used by BalanceStorage to process balances. Defining an
 // interface allows the client to determine if they wish to query the node for
 // certain information or use another datastore.
@@ -138,6 +157,35 @@ type BalanceStorageHelper interface {
 	AccountsSeen(ctx context.Context, dbTx database.Transaction) (*big.Int, error)
 }
 
+// BalanceStorageFilterHelper is an optional extension of BalanceStorageHelper.
+// If the helper provided to BalanceStorage implements it and InterestingAccounts
+// returns a non-nil filter, balance changes are only computed and stored for
+// the account and currency pairs included by the filter.
+type BalanceStorageFilterHelper interface {
+	InterestingAccounts() parser.BalanceChangeFilter
+}
+
+// BalanceStorageExemptionHelper is an optional extension of BalanceStorageHelper.
+// If the helper provided to BalanceStorage implements it, operations exempted by
+// ExemptFuncWithContext (which also receives the enclosing transaction) are skipped
+// in addition to those exempted by ExemptFunc.
+type BalanceStorageExemptionHelper interface {
+	ExemptFuncWithContext() parser.ExemptOperationWithContext
+}
+
+// BalanceStorageBlockHelper is an optional extension of BalanceStorageHelper.
+// If the helper provided to BalanceStorage implements it, BalanceOperations
+// can look up the operations that changed a balance (which allows BalanceStorage
+// to be used as a reconciler.DebugHelper). It is common to implement this
+// interface using BlockStorage.GetBlockTransactional.
+type BalanceStorageBlockHelper interface {
+	Block(
+		ctx context.Context,
+		dbTx database.Transaction,
+		blockIdentifier *types.PartialBlockIdentifier,
+	) (*types.Block, error)
+}
+
 // BalanceStorage implements block specific storage methods
 // on top of a database.Database and database.Transaction interface.
 type BalanceStorage struct {
@@ -152,7 +200,8 @@ type BalanceStorage struct {
 	pendingReconciliations     int
 	pendingReconciliationMutex *utils.PriorityMutex
 
-	parser *parser.Parser
+	parser  *parser.Parser
+	include parser.BalanceChangeFilter
 }
 
 // NewBalanceStorage returns a new BalanceStorage.
@@ -179,6 +228,47 @@ func (b *BalanceStorage) Initialize(
 		helper.ExemptFunc(),
 		helper.BalanceExemptions(),
 	)
+
+	if exemptionHelper, ok := helper.(BalanceStorageExemptionHelper); ok {
+		b.parser.ExemptWithContextFunc = exemptionHelper.ExemptFuncWithContext()
+	}
+
+	if filterHelper, ok := helper.(BalanceStorageFilterHelper); ok {
+		b.include = filterHelper.InterestingAccounts()
+	}
+}
+
+// balanceChanges returns the net balance changes in a block (only
+// for interesting accounts, if a filter was provided). If the
+// handler implements BalanceStorageDetailedHandler, the detailed
+// balance changes are also returned.
+func (b *BalanceStorage) balanceChanges(
+	ctx context.Context,
+	block *types.Block,
+	blockRemoved bool,
+) ([]*parser.BalanceChange, []*parser.BalanceChangeDetailed, error) {
+	if _, ok := b.handler.(BalanceStorageDetailedHandler); !ok {
+		changes, err := b.parser.BalanceChangesFiltered(ctx, block, blockRemoved, b.include)
+		return changes, nil, err
+	}
+
+	allDetailedChanges, err := b.parser.BalanceChangesDetailed(ctx, block, blockRemoved)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes := []*parser.BalanceChange{}
+	detailedChanges := []*parser.BalanceChangeDetailed{}
+	for _, change := range allDetailedChanges {
+		if b.include != nil && !b.include(change.Account, change.Currency) {
+			continue
+		}
+
+		changes = append(changes, change.BalanceChange)
+		detailedChanges = append(detailedChanges, change)
+	}
+
+	return changes, detailedChanges, nil
 }
 
 // AddingBlock is called by BlockStorage when adding a block to storage.
@@ -192,7 +282,7 @@ func (b *BalanceStorage) AddingBlock(
 		return nil, storageErrs.ErrHelperHandlerMissing
 	}
 
-	changes, err := b.parser.BalanceChanges(ctx, block, false)
+	changes, detailedChanges, err := b.balanceChanges(ctx, block, false)
 	if err != nil {
 		return nil, fmt.Errorf("%w: unable to calculate balance changes", err)
 	}
@@ -237,6 +327,10 @@ func (b *BalanceStorage) AddingBlock(
 	}
 
 	return func(ctx context.Context) error {
+		if detailedHandler, ok := b.handler.(BalanceStorageDetailedHandler); ok {
+			return detailedHandler.BlockAddedDetailed(ctx, block, detailedChanges)
+		}
+
 		return b.handler.BlockAdded(ctx, block, changes)
 	}, nil
 }
@@ -252,7 +346,7 @@ func (b *BalanceStorage) RemovingBlock(
 		return nil, storageErrs.ErrHelperHandlerMissing
 	}
 
-	changes, err := b.parser.BalanceChanges(ctx, block, true)
+	changes, detailedChanges, err := b.balanceChanges(ctx, block, true)
 	if err != nil {
 		return nil, fmt.Errorf("%w: unable to calculate balance changes", err)
 	}
@@ -294,7 +388,11 @@ func (b *BalanceStorage) RemovingBlock(
 	}
 
 	return func(ctx context.Context) error {
-		if err := b.handler.BlockRemoved(ctx, block, changes); err != nil {
+		if detailedHandler, ok := b.handler.(BalanceStorageDetailedHandler); ok {
+			if err := detailedHandler.BlockRemovedDetailed(ctx, block, detailedChanges); err != nil {
+				return err
+			}
+		} else if err := b.handler.BlockRemoved(ctx, block, changes); err != nil {
 			return err
 		}
 
@@ -460,6 +558,26 @@ func (b *BalanceStorage) EstimatedReconciliationCoverage(ctx context.Context) (f
 	return float64(reconciled.Int64()) / float64(accounts.Int64()), nil
 }
 
+// AccountCount returns the number of accounts (account and
+// currency pairs) tracked by BalanceStorage. Like
+// EstimatedReconciliationCoverage, this uses the accounts seen
+// counter instead of an expensive DB scan across all accounts.
+func (b *BalanceStorage) AccountCount(ctx context.Context) (int64, error) {
+	if b.helper == nil {
+		return -1, storageErrs.ErrHelperHandlerMissing
+	}
+
+	dbTx := b.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	accounts, err := b.helper.AccountsSeen(ctx, dbTx)
+	if err != nil {
+		return -1, err
+	}
+
+	return accounts.Int64(), nil
+}
+
 // ReconciliationCoverage returns the proportion of accounts [0.0, 1.0] that
 // have been reconciled at an index >= to a minimumIndex.
 func (b *BalanceStorage) ReconciliationCoverage(
@@ -502,6 +620,181 @@ func (b *BalanceStorage) ReconciliationCoverage(
 	return float64(validCoverage) / float64(seen), nil
 }
 
+// AccountReconciliations invokes worker with each account
+// (and currency) tracked by BalanceStorage and the index of
+// the last block where it was reconciled (-1 if it has never
+// been reconciled). All accounts are scanned in a single
+// database.Transaction and are never loaded into memory at
+// once, so this can be used to export reports of very
+// large numbers of accounts.
+func (b *BalanceStorage) AccountReconciliations(
+	ctx context.Context,
+	worker func(accountCurrency *types.AccountCurrency, lastReconciled int64) error,
+) error {
+	err := b.getAllAccountEntries(
+		ctx,
+		func(txn database.Transaction, entry *types.AccountCurrency) error {
+			key := GetAccountKey(reconciliationNamepace, entry.Account, entry.Currency)
+			exists, lastReconciled, err := BigIntGet(ctx, key, txn)
+			if err != nil {
+				return err
+			}
+
+			if !exists {
+				return worker(entry, -1)
+			}
+
+			return worker(entry, lastReconciled.Int64())
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%w: unable to get all account entries", err)
+	}
+
+	return nil
+}
+
+// BalanceOperations returns up to limit operations that changed
+// the balance of an account and currency after startBlock
+// (exclusive) and up to endBlock (inclusive). If startBlock is
+// nil, operations are returned from the oldest stored (unpruned)
+// balance. Blocks with a balance change are found using the stored
+// historical balances and are retrieved using the helper (which
+// must implement BalanceStorageBlockHelper).
+func (b *BalanceStorage) BalanceOperations(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	startBlock *types.BlockIdentifier,
+	endBlock *types.BlockIdentifier,
+	limit int,
+) ([]*types.Operation, error) {
+	if b.helper == nil {
+		return nil, storageErrs.ErrHelperHandlerMissing
+	}
+
+	blockHelper, ok := b.helper.(BalanceStorageBlockHelper)
+	if !ok {
+		return nil, storageErrs.ErrBlockHelperMissing
+	}
+
+	dbTx := b.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	startIndex := int64(0)
+	if startBlock != nil {
+		startIndex = startBlock.Index + 1
+	}
+
+	// Each block with a balance change contains at least
+	// one operation, so we never need more than limit blocks.
+	indexes := []int64{}
+	_, err := dbTx.ScanKeys(
+		ctx,
+		GetHistoricalBalancePrefix(account, currency),
+		GetHistoricalBalanceKey(account, currency, startIndex),
+		func(k []byte, v []byte) error {
+			index, err := historicalBalanceIndex(k)
+			if err != nil {
+				return err
+			}
+
+			if index > endBlock.Index || len(indexes) >= limit {
+				return errTooManyKeys
+			}
+
+			indexes = append(indexes, index)
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil && !errors.Is(err, errTooManyKeys) {
+		return nil, fmt.Errorf("%w: database scan failed", err)
+	}
+
+	operations := []*types.Operation{}
+	for _, index := range indexes {
+		blockIndex := index
+		block, err := blockHelper.Block(
+			ctx,
+			dbTx,
+			&types.PartialBlockIdentifier{Index: &blockIndex},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to get block %d", err, blockIndex)
+		}
+
+		for _, tx := range block.Transactions {
+			for _, op := range tx.Operations {
+				affected, err := b.operationAffects(tx, op, account, currency)
+				if err != nil {
+					return nil, err
+				}
+
+				if !affected {
+					continue
+				}
+
+				operations = append(operations, op)
+				if len(operations) >= limit {
+					return operations, nil
+				}
+			}
+		}
+	}
+
+	return operations, nil
+}
+
+// operationAffects returns a boolean indicating if an operation
+// changes the computed balance of an account and currency.
+func (b *BalanceStorage) operationAffects(
+	tx *types.Transaction,
+	op *types.Operation,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) (bool, error) {
+	if op.Account == nil || op.Amount == nil {
+		return false, nil
+	}
+
+	if types.Hash(op.Account) != types.Hash(account) ||
+		types.Hash(op.Amount.Currency) != types.Hash(currency) {
+		return false, nil
+	}
+
+	successful, err := b.parser.Asserter.OperationSuccessful(op)
+	if err != nil {
+		return false, err
+	}
+
+	if !successful {
+		return false, nil
+	}
+
+	if b.parser.ExemptFunc != nil && b.parser.ExemptFunc(op) {
+		return false, nil
+	}
+
+	if b.parser.ExemptWithContextFunc != nil && b.parser.ExemptWithContextFunc(tx, op) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// historicalBalanceIndex returns the block index
+// of a key created with GetHistoricalBalanceKey.
+func historicalBalanceIndex(key []byte) (int64, error) {
+	separator := bytes.LastIndexByte(key, '/')
+	if separator == -1 {
+		return -1, fmt.Errorf("invalid historical balance key %s", string(key))
+	}
+
+	return strconv.ParseInt(string(key[separator+1:]), 10, 64)
+}
+
 // existingValue finds the existing value for
 // a given *types.AccountIdentifier and *types.Currency.
 //
@@ -1082,6 +1375,8 @@ type BootstrapBalance struct {
 // any number of AccountIdentifiers at the genesis blocks.
 // This is particularly useful for setting the value of
 // accounts that received an allocation in the genesis block.
+// The bootstrap balances file can be a local path or an
+// http(s):// URL and may be gzip-compressed.
 func (b *BalanceStorage) BootstrapBalances(
 	ctx context.Context,
 	bootstrapBalancesFile string,
@@ -1089,15 +1384,12 @@ func (b *BalanceStorage) BootstrapBalances(
 ) error {
 	// Read bootstrap file
 	balances := []*BootstrapBalance{}
-	if err := utils.LoadAndParse(bootstrapBalancesFile, &balances); err != nil {
+	if err := utils.LoadAndParseFrom(ctx, bootstrapBalancesFile, &balances); err != nil {
 		return err
 	}
 
-	// Update balances in database
-	dbTransaction := b.db.Transaction(ctx)
-	defer dbTransaction.Discard(ctx)
-
-	for _, balance := range balances {
+	accountBalances := make([]*utils.AccountBalance, len(balances))
+	for i, balance := range balances {
 		// Ensure change.Difference is valid
 		amountValue, ok := new(big.Int).SetString(balance.Value, 10)
 		if !ok {
@@ -1108,34 +1400,120 @@ func (b *BalanceStorage) BootstrapBalances(
 			return fmt.Errorf("cannot bootstrap zero or negative balance %s", amountValue.String())
 		}
 
+		accountBalances[i] = &utils.AccountBalance{
+			Account: balance.Account,
+			Amount: &types.Amount{
+				Value:    balance.Value,
+				Currency: balance.Currency,
+			},
+			Block: genesisBlockIdentifier,
+		}
+	}
+
+	// Update balances in database
+	if err := b.setBalances(ctx, accountBalances); err != nil {
+		return err
+	}
+
+	log.Printf("%d Balances Bootstrapped\n", len(balances))
+	return nil
+}
+
+// setBalances sets the balance of many accounts (equivalent to
+// calling SetBalance for each balance in a single transaction).
+// If no block has been processed, balances are written with a
+// database.BulkWriter (which is much faster than writing each
+// balance in a transaction).
+func (b *BalanceStorage) setBalances(
+	ctx context.Context,
+	accountBalances []*utils.AccountBalance,
+) error {
+	if b.handler == nil {
+		return storageErrs.ErrHelperHandlerMissing
+	}
+
+	// Validate all balances before making any writes (a
+	// database.BulkWriter may persist writes even if it
+	// is canceled).
+	for _, accountBalance := range accountBalances {
+		if _, ok := new(big.Int).SetString(accountBalance.Amount.Value, 10); !ok {
+			return storageErrs.ErrInvalidValue
+		}
+
 		log.Printf(
 			"Setting account %s balance to %s %+v\n",
-			balance.Account.Address,
-			balance.Value,
-			balance.Currency,
+			accountBalance.Account.Address,
+			accountBalance.Amount.Value,
+			accountBalance.Amount.Currency,
 		)
+	}
+
+	// If the same account is set multiple times, only the
+	// last balance is stored (so we only set it once). This
+	// is required when using a database.BulkWriter because
+	// pending writes are not included in scans.
+	latest := map[string]int{}
+	for i, accountBalance := range accountBalances {
+		latest[types.Hash(&types.AccountCurrency{
+			Account:  accountBalance.Account,
+			Currency: accountBalance.Amount.Currency,
+		})] = i
+	}
+
+	dbTx, err := b.balancesTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbTx.Discard(ctx)
+
+	for i, accountBalance := range accountBalances {
+		if latest[types.Hash(&types.AccountCurrency{
+			Account:  accountBalance.Account,
+			Currency: accountBalance.Amount.Currency,
+		})] != i {
+			continue
+		}
 
 		err := b.SetBalance(
 			ctx,
-			dbTransaction,
-			balance.Account,
-			&types.Amount{
-				Value:    balance.Value,
-				Currency: balance.Currency,
-			},
-			genesisBlockIdentifier,
+			dbTx,
+			accountBalance.Account,
+			accountBalance.Amount,
+			accountBalance.Block,
 		)
 		if err != nil {
 			return err
 		}
 	}
 
-	if err := dbTransaction.Commit(ctx); err != nil {
-		return err
+	return dbTx.Commit(ctx)
+}
+
+// balancesTransaction returns a database.Transaction for
+// setBalances. The exclusive write lock is acquired before
+// checking if any block has been processed (by looking up the
+// head block in the database) and before any existing records
+// are read. If no block has been processed, the returned
+// transaction writes with a database.BulkWriter.
+func (b *BalanceStorage) balancesTransaction(
+	ctx context.Context,
+) (database.Transaction, error) {
+	writer := b.db.BulkWrite(ctx)
+	readTxn := b.db.ReadTransaction(ctx)
+	processed, _, err := readTxn.Get(ctx, getHeadBlockKey())
+	if err != nil {
+		readTxn.Discard(ctx)
+		writer.Cancel(ctx)
+		return nil, err
 	}
 
-	log.Printf("%d Balances Bootstrapped\n", len(balances))
-	return nil
+	if !processed {
+		return newBulkTransaction(readTxn, writer), nil
+	}
+
+	readTxn.Discard(ctx)
+	writer.Cancel(ctx)
+	return b.db.Transaction(ctx), nil
 }
 
 func (b *BalanceStorage) getAllAccountEntries(
@@ -1200,30 +1578,7 @@ func (b *BalanceStorage) SetBalanceImported(
 	accountBalances []*utils.AccountBalance,
 ) error {
 	// Update balances in database
-	transaction := b.db.Transaction(ctx)
-	defer transaction.Discard(ctx)
-
-	for _, accountBalance := range accountBalances {
-		log.Printf(
-			"Setting account %s balance to %s %+v\n",
-			accountBalance.Account.Address,
-			accountBalance.Amount.Value,
-			accountBalance.Amount.Currency,
-		)
-
-		err := b.SetBalance(
-			ctx,
-			transaction,
-			accountBalance.Account,
-			accountBalance.Amount,
-			accountBalance.Block,
-		)
-		if err != nil {
-			return err
-		}
-	}
-
-	if err := transaction.Commit(ctx); err != nil {
+	if err := b.setBalances(ctx, accountBalances); err != nil {
 		return err
 	}
 
@@ -1277,7 +1632,7 @@ func (b *BalanceStorage) removeHistoricalBalances(
 	orphan bool,
 ) error {
 	foundKeys := [][]byte{}
-	_, err := dbTx.Scan(
+	_, err := dbTx.ScanKeys(
 		ctx,
 		GetHistoricalBalancePrefix(account, currency),
 		GetHistoricalBalanceKey(account, currency, index),
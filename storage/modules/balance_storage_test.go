@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"math/big"
 	"path"
@@ -190,7 +191,7 @@ func TestBalance(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -958,7 +959,7 @@ func TestSetBalanceImported(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -998,6 +999,93 @@ func TestSetBalanceImported(t *testing.T) {
 		assert.Equal(t, amount2.Value, amountBalance.Value)
 	})
 
+	t.Run("Overwrite existing balances", func(t *testing.T) {
+		// Mark an account as reconciled
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(
+			ctx,
+			GetAccountKey(reconciliationNamepace, accountBalance, currency),
+			[]byte("1"),
+			false,
+		))
+		assert.NoError(t, txn.Commit(ctx))
+
+		newAmount := &types.Amount{
+			Value:    "200",
+			Currency: currency,
+		}
+		// The account is only set once (with the last
+		// balance provided).
+		mockHandler.On("AccountsSeen", ctx, mock.Anything, -1).Return(nil).Once()
+		mockHandler.On("AccountsReconciled", ctx, mock.Anything, -1).Return(nil).Once()
+		mockHandler.On("AccountsSeen", ctx, mock.Anything, 1).Return(nil).Once()
+		err = storage.SetBalanceImported(
+			ctx,
+			nil,
+			[]*utils.AccountBalance{
+				accBalance2,
+				{
+					Account: accountBalance,
+					Amount:  newAmount,
+					Block:   blockIdentifier,
+				},
+			},
+		)
+		assert.NoError(t, err)
+
+		amount, err := storage.GetOrSetBalance(
+			ctx,
+			accountBalance,
+			currency,
+			blockIdentifier,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, newAmount.Value, amount.Value)
+
+		txn = database.ReadTransaction(ctx)
+		exists, _, err := txn.Get(
+			ctx,
+			GetAccountKey(reconciliationNamepace, accountBalance, currency),
+		)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+		txn.Discard(ctx)
+	})
+
+	t.Run("Set balance after processing blocks", func(t *testing.T) {
+		// Once a block is processed, balances are
+		// set in a transaction.
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, getHeadBlockKey(), []byte("head"), false))
+		assert.NoError(t, txn.Commit(ctx))
+
+		newAccount := &types.AccountIdentifier{
+			Address: "test3",
+		}
+		mockHandler.On("AccountsSeen", ctx, mock.Anything, 1).Return(nil).Once()
+		err = storage.SetBalanceImported(
+			ctx,
+			nil,
+			[]*utils.AccountBalance{
+				{
+					Account: newAccount,
+					Amount:  amountBalance,
+					Block:   blockIdentifier,
+				},
+			},
+		)
+		assert.NoError(t, err)
+
+		amount, err := storage.GetOrSetBalance(
+			ctx,
+			newAccount,
+			currency,
+			blockIdentifier,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, amountBalance.Value, amount.Value)
+	})
+
 	mockHelper.AssertExpectations(t)
 	mockHandler.AssertExpectations(t)
 }
@@ -1025,7 +1113,7 @@ func TestBootstrapBalances(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -1248,7 +1336,7 @@ func TestBalanceReconciliation(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -1265,6 +1353,12 @@ func TestBalanceReconciliation(t *testing.T) {
 		assert.True(t, errors.Is(err, storageErrs.ErrHelperHandlerMissing))
 	})
 
+	t.Run("test account count before helper/handler", func(t *testing.T) {
+		count, err := storage.AccountCount(ctx)
+		assert.Equal(t, int64(-1), count)
+		assert.True(t, errors.Is(err, storageErrs.ErrHelperHandlerMissing))
+	})
+
 	storage.Initialize(mockHelper, mockHandler)
 	t.Run("attempt to store reconciliation for non-existent account", func(t *testing.T) {
 		err := storage.Reconciled(ctx, account, currency, genesisBlock)
@@ -1375,6 +1469,32 @@ func TestBalanceReconciliation(t *testing.T) {
 		assert.Equal(t, float64(1)/float64(3), coverage)
 	})
 
+	t.Run("account reconciliations", func(t *testing.T) {
+		lastReconciled := map[string]int64{}
+		err := storage.AccountReconciliations(
+			ctx,
+			func(accountCurrency *types.AccountCurrency, index int64) error {
+				lastReconciled[types.Hash(accountCurrency)] = index
+				return nil
+			},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int64{
+			types.Hash(&types.AccountCurrency{
+				Account:  account,
+				Currency: currency,
+			}): newBlock.Index,
+			types.Hash(&types.AccountCurrency{
+				Account:  account,
+				Currency: currency2,
+			}): -1,
+			types.Hash(&types.AccountCurrency{
+				Account:  subAccountMetadata2,
+				Currency: currency2,
+			}): -1,
+		}, lastReconciled)
+	})
+
 	t.Run("test estimated no reconciliations", func(t *testing.T) {
 		mockHelper.On("AccountsReconciled", ctx, mock.Anything).Return(big.NewInt(0), nil).Once()
 		mockHelper.On("AccountsSeen", ctx, mock.Anything).Return(big.NewInt(0), nil).Once()
@@ -1383,6 +1503,13 @@ func TestBalanceReconciliation(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("test account count", func(t *testing.T) {
+		mockHelper.On("AccountsSeen", ctx, mock.Anything).Return(big.NewInt(3), nil).Once()
+		count, err := storage.AccountCount(ctx)
+		assert.Equal(t, int64(3), count)
+		assert.NoError(t, err)
+	})
+
 	t.Run("test estimated some reconciliations", func(t *testing.T) {
 		mockHelper.On("AccountsReconciled", ctx, mock.Anything).Return(big.NewInt(1), nil).Once()
 		mockHelper.On("AccountsSeen", ctx, mock.Anything).Return(big.NewInt(2), nil).Once()
@@ -1402,7 +1529,7 @@ func TestBlockSyncing(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -1848,3 +1975,591 @@ func TestBlockSyncing(t *testing.T) {
 	mockHelper.AssertExpectations(t)
 	mockHandler.AssertExpectations(t)
 }
+
+type detailedBalanceStorageHandler struct {
+	*mocks.BalanceStorageHandler
+	*mocks.BalanceStorageDetailedHandler
+}
+
+func TestBlockSyncingDetailed(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBalanceStorage(database)
+	mockHelper := &mocks.BalanceStorageHelper{}
+	mockHandler := &mocks.BalanceStorageHandler{}
+	mockDetailedHandler := &mocks.BalanceStorageDetailedHandler{}
+	mockHelper.On("Asserter").Return(baseAsserter())
+	mockHelper.On("ExemptFunc").Return(exemptFunc())
+	mockHelper.On("BalanceExemptions").Return([]*types.BalanceExemption{})
+	storage.Initialize(mockHelper, &detailedBalanceStorageHandler{
+		BalanceStorageHandler:         mockHandler,
+		BalanceStorageDetailedHandler: mockDetailedHandler,
+	})
+
+	addr1 := &types.AccountIdentifier{
+		Address: "addr1",
+	}
+	curr := &types.Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+	}
+	operation := func(index int64, opType string, value string) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: index,
+			},
+			Account: addr1,
+			Status:  types.String("Success"),
+			Type:    opType,
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: curr,
+			},
+		}
+	}
+
+	b1 := &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: 1,
+			Hash:  "1",
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "0",
+		},
+		Transactions: []*types.Transaction{
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "1_0",
+				},
+				Operations: []*types.Operation{
+					operation(0, "Reward", "100"),
+					operation(1, "Transfer", "-10"),
+					operation(2, "Fee", "-1"),
+				},
+			},
+		},
+	}
+
+	t.Run("add block", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		mockHelper.On(
+			"AccountBalance",
+			gctx,
+			addr1,
+			curr,
+			b1.ParentBlockIdentifier,
+		).Return(
+			&types.Amount{Value: "0", Currency: curr},
+			nil,
+		).Once()
+		mockHandler.On("AccountsSeen", gctx, dbTx, 1).Return(nil).Once()
+		commitWorker, err := storage.AddingBlock(gctx, g, b1, dbTx)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		mockDetailedHandler.On(
+			"BlockAddedDetailed",
+			ctx,
+			b1,
+			[]*parser.BalanceChangeDetailed{
+				{
+					BalanceChange: &parser.BalanceChange{
+						Account:    addr1,
+						Currency:   curr,
+						Block:      b1.BlockIdentifier,
+						Difference: "89",
+					},
+					Subtotals: map[parser.BalanceChangeCategory]string{
+						parser.RewardCategory:   "100",
+						parser.TransferCategory: "-10",
+						parser.FeeCategory:      "-1",
+					},
+				},
+			},
+		).Return(nil).Once()
+		assert.NoError(t, commitWorker(ctx))
+
+		amount, err := storage.GetBalance(ctx, addr1, curr, b1.BlockIdentifier.Index)
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Amount{
+			Value:    "89",
+			Currency: curr,
+		}, amount)
+	})
+
+	t.Run("orphan block", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		commitWorker, err := storage.RemovingBlock(gctx, g, b1, dbTx)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		mockDetailedHandler.On(
+			"BlockRemovedDetailed",
+			ctx,
+			b1,
+			[]*parser.BalanceChangeDetailed{
+				{
+					BalanceChange: &parser.BalanceChange{
+						Account:    addr1,
+						Currency:   curr,
+						Block:      b1.BlockIdentifier,
+						Difference: "-89",
+					},
+					Subtotals: map[parser.BalanceChangeCategory]string{
+						parser.RewardCategory:   "-100",
+						parser.TransferCategory: "10",
+						parser.FeeCategory:      "1",
+					},
+				},
+			},
+		).Return(nil).Once()
+		mockHandler.On("AccountsSeen", ctx, mock.Anything, -1).Return(nil).Once()
+		assert.NoError(t, commitWorker(ctx))
+
+		amount, err := storage.GetBalance(ctx, addr1, curr, b1.BlockIdentifier.Index)
+		assert.True(t, errors.Is(err, storageErrs.ErrAccountMissing))
+		assert.Nil(t, amount)
+	})
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockDetailedHandler.AssertExpectations(t)
+}
+
+type filterBalanceStorageHelper struct {
+	*mocks.BalanceStorageHelper
+	*mocks.BalanceStorageFilterHelper
+}
+
+func TestBlockSyncingFiltered(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	addr1 := &types.AccountIdentifier{
+		Address: "addr1",
+	}
+	addr2 := &types.AccountIdentifier{
+		Address: "addr2",
+	}
+	curr := &types.Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+	}
+
+	storage := NewBalanceStorage(database)
+	mockHelper := &mocks.BalanceStorageHelper{}
+	mockFilterHelper := &mocks.BalanceStorageFilterHelper{}
+	mockHandler := &mocks.BalanceStorageHandler{}
+	mockHelper.On("Asserter").Return(baseAsserter())
+	mockHelper.On("ExemptFunc").Return(exemptFunc())
+	mockHelper.On("BalanceExemptions").Return([]*types.BalanceExemption{})
+	mockFilterHelper.On("InterestingAccounts").Return(
+		parser.BalanceChangeFilter(func(account *types.AccountIdentifier, _ *types.Currency) bool {
+			return types.Hash(account) == types.Hash(addr1)
+		}),
+	).Once()
+	storage.Initialize(&filterBalanceStorageHelper{
+		BalanceStorageHelper:       mockHelper,
+		BalanceStorageFilterHelper: mockFilterHelper,
+	}, mockHandler)
+
+	b1 := &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: 1,
+			Hash:  "1",
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "0",
+		},
+		Transactions: []*types.Transaction{
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "1_0",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						Account: addr1,
+						Status:  types.String("Success"),
+						Type:    "Transfer",
+						Amount: &types.Amount{
+							Value:    "-10",
+							Currency: curr,
+						},
+					},
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 1,
+						},
+						Account: addr2,
+						Status:  types.String("Success"),
+						Type:    "Transfer",
+						Amount: &types.Amount{
+							Value:    "10",
+							Currency: curr,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("add block", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+
+		// No balance lookup or write should occur for addr2
+		mockHelper.On(
+			"AccountBalance",
+			gctx,
+			addr1,
+			curr,
+			b1.ParentBlockIdentifier,
+		).Return(
+			&types.Amount{Value: "100", Currency: curr},
+			nil,
+		).Once()
+		mockHandler.On("AccountsSeen", gctx, dbTx, 1).Return(nil).Once()
+		commitWorker, err := storage.AddingBlock(gctx, g, b1, dbTx)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		mockHandler.On("BlockAdded", ctx, b1, []*parser.BalanceChange{
+			{
+				Account:    addr1,
+				Currency:   curr,
+				Block:      b1.BlockIdentifier,
+				Difference: "-10",
+			},
+		}).Return(nil).Once()
+		assert.NoError(t, commitWorker(ctx))
+
+		amount, err := storage.GetBalance(ctx, addr1, curr, b1.BlockIdentifier.Index)
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Amount{
+			Value:    "90",
+			Currency: curr,
+		}, amount)
+
+		amount, err = storage.GetBalance(ctx, addr2, curr, b1.BlockIdentifier.Index)
+		assert.True(t, errors.Is(err, storageErrs.ErrAccountMissing))
+		assert.Nil(t, amount)
+
+		accounts, err := storage.GetAllAccountCurrency(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*types.AccountCurrency{
+			{
+				Account:  addr1,
+				Currency: curr,
+			},
+		}, accounts)
+	})
+
+	mockHelper.AssertExpectations(t)
+	mockFilterHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+type exemptionBalanceStorageHelper struct {
+	*mocks.BalanceStorageHelper
+	*mocks.BalanceStorageExemptionHelper
+}
+
+func TestBlockSyncingExemptWithContext(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	addr1 := &types.AccountIdentifier{
+		Address: "addr1",
+	}
+	curr := &types.Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+	}
+
+	storage := NewBalanceStorage(database)
+	mockHelper := &mocks.BalanceStorageHelper{}
+	mockExemptionHelper := &mocks.BalanceStorageExemptionHelper{}
+	mockHandler := &mocks.BalanceStorageHandler{}
+	mockHelper.On("Asserter").Return(baseAsserter())
+	mockHelper.On("ExemptFunc").Return(exemptFunc())
+	mockHelper.On("BalanceExemptions").Return([]*types.BalanceExemption{})
+	mockExemptionHelper.On("ExemptFuncWithContext").Return(
+		parser.ExemptOperationWithContext(func(tx *types.Transaction, _ *types.Operation) bool {
+			synthetic, ok := tx.Metadata["synthetic"].(bool)
+			return ok && synthetic
+		}),
+	).Once()
+	storage.Initialize(&exemptionBalanceStorageHelper{
+		BalanceStorageHelper:          mockHelper,
+		BalanceStorageExemptionHelper: mockExemptionHelper,
+	}, mockHandler)
+
+	operation := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: 0,
+		},
+		Account: addr1,
+		Status:  types.String("Success"),
+		Type:    "Transfer",
+		Amount: &types.Amount{
+			Value:    "10",
+			Currency: curr,
+		},
+	}
+	b1 := &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: 1,
+			Hash:  "1",
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "0",
+		},
+		Transactions: []*types.Transaction{
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "1_0",
+				},
+				Operations: []*types.Operation{operation},
+			},
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "1_1",
+				},
+				Operations: []*types.Operation{operation},
+				Metadata: map[string]interface{}{
+					"synthetic": true,
+				},
+			},
+		},
+	}
+
+	dbTx := database.Transaction(ctx)
+	g, gctx := errgroup.WithContext(ctx)
+	mockHelper.On(
+		"AccountBalance",
+		gctx,
+		addr1,
+		curr,
+		b1.ParentBlockIdentifier,
+	).Return(
+		&types.Amount{Value: "0", Currency: curr},
+		nil,
+	).Once()
+	mockHandler.On("AccountsSeen", gctx, dbTx, 1).Return(nil).Once()
+	_, err = storage.AddingBlock(gctx, g, b1, dbTx)
+	assert.NoError(t, err)
+	assert.NoError(t, g.Wait())
+	assert.NoError(t, dbTx.Commit(ctx))
+
+	// Only the operation in the non-synthetic transaction
+	// should be applied.
+	amount, err := storage.GetBalance(ctx, addr1, curr, b1.BlockIdentifier.Index)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.Amount{
+		Value:    "10",
+		Currency: curr,
+	}, amount)
+
+	mockHelper.AssertExpectations(t)
+	mockExemptionHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+type blockBalanceStorageHelper struct {
+	*mocks.BalanceStorageHelper
+	*mocks.BalanceStorageBlockHelper
+}
+
+func TestBalanceOperations(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	addr1 := &types.AccountIdentifier{
+		Address: "addr1",
+	}
+	addr2 := &types.AccountIdentifier{
+		Address: "addr2",
+	}
+	curr := &types.Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+	}
+	otherCurr := &types.Currency{
+		Symbol:   "BTC",
+		Decimals: 8,
+	}
+
+	operation := func(index int64, account *types.AccountIdentifier, currency *types.Currency) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: index,
+			},
+			Account: account,
+			Status:  types.String("Success"),
+			Type:    "Transfer",
+			Amount: &types.Amount{
+				Value:    "10",
+				Currency: currency,
+			},
+		}
+	}
+	block := func(index int64, ops ...*types.Operation) *types.Block {
+		return &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: index,
+				Hash:  fmt.Sprintf("%d", index),
+			},
+			Transactions: []*types.Transaction{
+				{
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: fmt.Sprintf("%d_0", index),
+					},
+					Operations: ops,
+				},
+			},
+		}
+	}
+
+	// The balance of addr1 changes in blocks 1, 2, and 4.
+	op1 := operation(0, addr1, curr)
+	op2 := operation(1, addr1, curr)
+	op3 := operation(2, addr1, curr)
+	op4 := operation(0, addr1, curr)
+	blocks := []*types.Block{
+		block(1, op1, operation(1, addr2, curr)),
+		block(2, operation(0, addr1, otherCurr), op2, op3),
+		block(4, op4),
+	}
+
+	dbTx := database.Transaction(ctx)
+	for _, b := range blocks {
+		key := GetHistoricalBalanceKey(addr1, curr, b.BlockIdentifier.Index)
+		assert.NoError(t, dbTx.Set(ctx, key, big.NewInt(b.BlockIdentifier.Index).Bytes(), true))
+	}
+	assert.NoError(t, dbTx.Commit(ctx))
+
+	storage := NewBalanceStorage(database)
+	mockHelper := &mocks.BalanceStorageHelper{}
+	mockBlockHelper := &mocks.BalanceStorageBlockHelper{}
+	mockHandler := &mocks.BalanceStorageHandler{}
+	mockHelper.On("Asserter").Return(baseAsserter())
+	mockHelper.On("ExemptFunc").Return(exemptFunc())
+	mockHelper.On("BalanceExemptions").Return([]*types.BalanceExemption{})
+	storage.Initialize(&blockBalanceStorageHelper{
+		BalanceStorageHelper:      mockHelper,
+		BalanceStorageBlockHelper: mockBlockHelper,
+	}, mockHandler)
+
+	for _, b := range blocks {
+		mockBlockHelper.On(
+			"Block",
+			ctx,
+			mock.Anything,
+			&types.PartialBlockIdentifier{Index: &b.BlockIdentifier.Index},
+		).Return(b, nil)
+	}
+
+	tests := map[string]struct {
+		startBlock *types.BlockIdentifier
+		endBlock   *types.BlockIdentifier
+		limit      int
+		operations []*types.Operation
+	}{
+		"never reconciled": {
+			endBlock:   blocks[2].BlockIdentifier,
+			limit:      10,
+			operations: []*types.Operation{op1, op2, op3, op4},
+		},
+		"since last reconciled": {
+			startBlock: blocks[0].BlockIdentifier,
+			endBlock:   &types.BlockIdentifier{Index: 3, Hash: "3"},
+			limit:      10,
+			operations: []*types.Operation{op2, op3},
+		},
+		"limited": {
+			endBlock:   blocks[2].BlockIdentifier,
+			limit:      2,
+			operations: []*types.Operation{op1, op2},
+		},
+		"no changes": {
+			startBlock: blocks[2].BlockIdentifier,
+			endBlock:   &types.BlockIdentifier{Index: 5, Hash: "5"},
+			limit:      10,
+			operations: []*types.Operation{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			operations, err := storage.BalanceOperations(
+				ctx,
+				addr1,
+				curr,
+				test.startBlock,
+				test.endBlock,
+				test.limit,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.operations, operations)
+		})
+	}
+
+	t.Run("block helper missing", func(t *testing.T) {
+		storage := NewBalanceStorage(database)
+		storage.Initialize(mockHelper, mockHandler)
+
+		operations, err := storage.BalanceOperations(
+			ctx,
+			addr1,
+			curr,
+			nil,
+			blocks[2].BlockIdentifier,
+			10,
+		)
+		assert.Nil(t, operations)
+		assert.True(t, errors.Is(err, storageErrs.ErrBlockHelperMissing))
+	})
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
@@ -1277,7 +1277,7 @@ func (b *BlockStorage) getForwardRelatedTransactions(
 	}
 
 	// scan db for all transactions where tx appears as a backward relation
-	_, err := db.Scan(
+	_, err := db.ScanKeys(
 		ctx,
 		getBackwardRelationKey(tx.TransactionIdentifier, nil),
 		getBackwardRelationKey(tx.TransactionIdentifier, nil),
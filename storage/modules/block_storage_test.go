@@ -50,7 +50,7 @@ func TestHeadBlockIdentifier(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -324,7 +324,7 @@ func TestBlock(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -730,7 +730,7 @@ func TestManyBlocks(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -782,7 +782,7 @@ func TestCreateBlockCache(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -851,7 +851,7 @@ func TestAtTip(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -941,7 +941,7 @@ func TestRelatedTransactions(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -16,21 +16,32 @@ package modules
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/neilotoole/errgroup"
 
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
-	"github.com/coinbase/rosetta-sdk-go/storage/errors"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
 )
 
 var _ BlockWorker = (*BroadcastStorage)(nil)
 
 const (
 	transactionBroadcastNamespace = "transaction-broadcast"
+	broadcastStatusNamespace      = "broadcast-status"
+	archivedBroadcastNamespace    = "archived-broadcast"
+
+	// DefaultStatusRetention is the default amount of time
+	// the records of a finished broadcast are retained.
+	DefaultStatusRetention = 7 * 24 * time.Hour
 
 	// depthOffset is used for adjusting depth checks because
 	// depth is "indexed by 1". Meaning, if a transaction is in
@@ -44,6 +55,18 @@ func getBroadcastKey(transactionIdentifier *types.TransactionIdentifier) (string
 	)
 }
 
+func getBroadcastStatusKey(transactionIdentifier *types.TransactionIdentifier) (string, []byte) {
+	return broadcastStatusNamespace, []byte(
+		fmt.Sprintf("%s/%s", broadcastStatusNamespace, transactionIdentifier.Hash),
+	)
+}
+
+func getArchivedBroadcastKey(transactionIdentifier *types.TransactionIdentifier) (string, []byte) {
+	return archivedBroadcastNamespace, []byte(
+		fmt.Sprintf("%s/%s", archivedBroadcastNamespace, transactionIdentifier.Hash),
+	)
+}
+
 // BroadcastStorage implements storage methods for managing
 // transaction broadcast.
 type BroadcastStorage struct {
@@ -57,8 +80,29 @@ type BroadcastStorage struct {
 	broadcastBehindTip  bool
 	blockBroadcastLimit int
 
+	confirmationDepth  int64
+	expirationDepth    int64
+	expirationDuration time.Duration
+
+	backoffInitialDelay int64
+	backoffMultiplier   float64
+	backoffMaxDelay     int64
+	backoffMaxAttempts  int
+
+	// statusRetention is how long the records of a
+	// finished broadcast are retained (0 means forever).
+	statusRetention time.Duration
+
+	// now is used to determine if a broadcast
+	// has expired (overridden in tests).
+	now func() time.Time
+
 	// Running BroadcastAll concurrently
-	// could cause corruption.
+	// could cause corruption. This mutex must
+	// always be acquired before any database
+	// transaction (BroadcastAll is run as a
+	// CommitWorker after the block transaction
+	// is committed).
 	broadcastAllMutex sync.Mutex
 }
 
@@ -120,7 +164,8 @@ type BroadcastStorageHandler interface {
 	) error // log in counter (rebroadcast should occur here)
 
 	// BroadcastFailed is called when another transaction broadcast would
-	// put it over the provided broadcast limit.
+	// put it over the provided broadcast limit or when the maximum number
+	// of rejected submission attempts is exceeded.
 	BroadcastFailed(
 		context.Context,
 		database.Transaction,
@@ -130,6 +175,36 @@ type BroadcastStorageHandler interface {
 	) error
 }
 
+// BroadcastFailureHandler is an optional extension of BroadcastStorageHandler.
+// If the handler provided to BroadcastStorage implements it,
+// BroadcastFailedWithCause is invoked instead of BroadcastFailed with an
+// error describing why the broadcast failed.
+type BroadcastFailureHandler interface {
+	BroadcastFailedWithCause(
+		context.Context,
+		database.Transaction,
+		string, // identifier
+		*types.TransactionIdentifier,
+		[]*types.Operation,
+		error,
+	) error
+}
+
+// BroadcastExpirationHandler is an optional extension of
+// BroadcastStorageHandler. If the handler provided to BroadcastStorage
+// implements it, BroadcastExpired is invoked when a transaction has not
+// been seen on-chain before its expiration depth or expiration time is
+// reached. Otherwise, the expired broadcast is handled as a failure.
+type BroadcastExpirationHandler interface {
+	BroadcastExpired(
+		context.Context,
+		database.Transaction,
+		string, // identifier
+		*types.TransactionIdentifier,
+		[]*types.Operation,
+	) error
+}
+
 // Broadcast is persisted to the db to track transaction broadcast.
 type Broadcast struct {
 	Identifier            string                       `json:"identifier"`
@@ -140,6 +215,96 @@ type Broadcast struct {
 	Payload               string                       `json:"payload"`
 	LastBroadcast         *types.BlockIdentifier       `json:"broadcast_at"`
 	Broadcasts            int                          `json:"broadcasts"`
+
+	// ExpirationDepth is the number of blocks after FirstBroadcast
+	// that the broadcast expires if not yet seen on-chain.
+	ExpirationDepth int64                  `json:"expiration_depth,omitempty"`
+	FirstBroadcast  *types.BlockIdentifier `json:"first_broadcast,omitempty"`
+
+	// ExpirationTime is the unix timestamp (in milliseconds)
+	// after which the broadcast expires if not yet seen on-chain.
+	ExpirationTime int64 `json:"expiration_time,omitempty"`
+
+	// NextBroadcast is the block index at which a rejected
+	// broadcast may be attempted again and SubmissionErrors
+	// are the errors returned by each rejected attempt. These
+	// are only populated when a backoff policy is configured.
+	NextBroadcast    int64    `json:"next_broadcast,omitempty"`
+	SubmissionErrors []string `json:"submission_errors,omitempty"`
+
+	// Prerequisites are transactions that must be seen
+	// on-chain before this broadcast is submitted.
+	Prerequisites []*types.TransactionIdentifier `json:"prerequisites,omitempty"`
+
+	// expirationDuration is populated by BroadcastOption
+	// and used to compute ExpirationTime.
+	expirationDuration time.Duration
+}
+
+// BroadcastState is the state of a broadcast in BroadcastStorage.
+type BroadcastState string
+
+const (
+	// BroadcastStateWaiting is the state of a broadcast that
+	// is waiting for its prerequisite transactions to be
+	// seen on-chain before it is broadcast.
+	BroadcastStateWaiting BroadcastState = "waiting"
+
+	// BroadcastStatePending is the state of a broadcast that
+	// has not yet been seen on-chain.
+	BroadcastStatePending BroadcastState = "pending"
+
+	// BroadcastStateSeen is the state of a broadcast that has
+	// been seen on-chain but has not yet reached its
+	// confirmation depth.
+	BroadcastStateSeen BroadcastState = "seen"
+
+	// BroadcastStateConfirmed is the state of a broadcast that
+	// has reached its confirmation depth.
+	BroadcastStateConfirmed BroadcastState = "confirmed"
+
+	// BroadcastStateFailed is the state of a broadcast that
+	// will no longer be attempted.
+	BroadcastStateFailed BroadcastState = "failed"
+
+	// BroadcastStateExpired is the state of a broadcast that
+	// was not seen on-chain before it expired.
+	BroadcastStateExpired BroadcastState = "expired"
+)
+
+// finished returns a boolean indicating if a broadcast
+// in a BroadcastState will no longer be attempted or tracked.
+func (s BroadcastState) finished() bool {
+	switch s {
+	case BroadcastStateConfirmed, BroadcastStateFailed, BroadcastStateExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// BroadcastStatus is persisted to the db to track the
+// state of a broadcast. Unlike a Broadcast, a BroadcastStatus
+// is not removed when a broadcast is confirmed, fails,
+// or expires (unless WithStatusRetention is provided).
+type BroadcastStatus struct {
+	Identifier            string                       `json:"identifier"`
+	TransactionIdentifier *types.TransactionIdentifier `json:"transaction_identifier"`
+	State                 BroadcastState               `json:"state"`
+
+	// Block is the most recent block the transaction
+	// was seen in and Depth is the depth of that
+	// block (1 if the transaction is in tip).
+	Block *types.BlockIdentifier `json:"block,omitempty"`
+	Depth int64                  `json:"depth,omitempty"`
+
+	// Reason is populated when a broadcast fails.
+	Reason string `json:"reason,omitempty"`
+
+	// LastBroadcastTime is the unix timestamp (in milliseconds)
+	// of the last broadcast attempt.
+	LastBroadcastTime int64 `json:"last_broadcast_time,omitempty"`
+	Broadcasts        int   `json:"broadcasts"`
 }
 
 // NewBroadcastStorage returns a new BroadcastStorage.
@@ -150,15 +315,24 @@ func NewBroadcastStorage(
 	tipDelay int64,
 	broadcastBehindTip bool,
 	blockBroadcastLimit int,
+	options ...BroadcastStorageOption,
 ) *BroadcastStorage {
-	return &BroadcastStorage{
+	b := &BroadcastStorage{
 		db:                  db,
 		staleDepth:          staleDepth,
 		broadcastLimit:      broadcastLimit,
 		tipDelay:            tipDelay,
 		broadcastBehindTip:  broadcastBehindTip,
 		blockBroadcastLimit: blockBroadcastLimit,
+		statusRetention:     DefaultStatusRetention,
+		now:                 time.Now,
+	}
+
+	for _, opt := range options {
+		opt(b)
 	}
+
+	return b
 }
 
 // Initialize adds a BroadcastStorageHelper and BroadcastStorageHandler to BroadcastStorage.
@@ -175,6 +349,7 @@ func (b *BroadcastStorage) invokeAddBlockHandlers(
 	ctx context.Context,
 	dbTx database.Transaction,
 	staleBroadcasts []*Broadcast,
+	expiredBroadcasts []*Broadcast,
 	confirmedTransactions []*Broadcast,
 	foundBlocks []*types.BlockIdentifier,
 	foundTransactions []*types.Transaction,
@@ -183,13 +358,24 @@ func (b *BroadcastStorage) invokeAddBlockHandlers(
 		if err := b.handler.TransactionStale(ctx, dbTx, stale.Identifier, stale.TransactionIdentifier); err != nil {
 			return fmt.Errorf(
 				"%w %s: %v",
-				errors.ErrBroadcastTxStale,
+				storageErrs.ErrBroadcastTxStale,
 				stale.TransactionIdentifier.Hash,
 				err,
 			)
 		}
 	}
 
+	for _, expired := range expiredBroadcasts {
+		if err := b.handleExpiration(ctx, dbTx, expired); err != nil {
+			return fmt.Errorf(
+				"%w %s: %v",
+				storageErrs.ErrBroadcastHandleExpirationUnsuccessful,
+				expired.TransactionIdentifier.Hash,
+				err,
+			)
+		}
+	}
+
 	for i, broadcast := range confirmedTransactions {
 		err := b.handler.TransactionConfirmed(
 			ctx,
@@ -202,7 +388,7 @@ func (b *BroadcastStorage) invokeAddBlockHandlers(
 		if err != nil {
 			return fmt.Errorf(
 				"%w %s: %v",
-				errors.ErrBroadcastTxConfirmed,
+				storageErrs.ErrBroadcastTxConfirmed,
 				broadcast.TransactionIdentifier.Hash,
 				err,
 			)
@@ -212,6 +398,183 @@ func (b *BroadcastStorage) invokeAddBlockHandlers(
 	return nil
 }
 
+// expired returns a boolean indicating if a broadcast has
+// exceeded its expiration depth or expiration time at
+// the provided block index.
+func (b *BroadcastStorage) expired(broadcast *Broadcast, index int64) bool {
+	if broadcast.ExpirationDepth > 0 && broadcast.FirstBroadcast != nil &&
+		index-broadcast.FirstBroadcast.Index >= broadcast.ExpirationDepth {
+		return true
+	}
+
+	if broadcast.ExpirationTime > 0 &&
+		b.now().UnixNano()/utils.NanosecondsInMillisecond >= broadcast.ExpirationTime {
+		return true
+	}
+
+	return false
+}
+
+func (b *BroadcastStorage) getBroadcastStatus(
+	ctx context.Context,
+	dbTx database.Transaction,
+	transactionIdentifier *types.TransactionIdentifier,
+) (*BroadcastStatus, error) {
+	namespace, key := getBroadcastStatusKey(transactionIdentifier)
+	exists, val, err := dbTx.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastStatusGetFailed, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", storageErrs.ErrBroadcastNotFound, transactionIdentifier.Hash)
+	}
+
+	var status BroadcastStatus
+	if err := b.db.Encoder().Decode(namespace, val, &status, true); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastStatusDecodeFailed, err)
+	}
+
+	return &status, nil
+}
+
+// updateBroadcastStatus applies changes to the BroadcastStatus
+// of a broadcast in a database.Transaction. If no BroadcastStatus
+// exists, a new one is created. An existing BroadcastStatus is
+// only written if it was modified.
+func (b *BroadcastStorage) updateBroadcastStatus(
+	ctx context.Context,
+	dbTx database.Transaction,
+	broadcast *Broadcast,
+	update func(*BroadcastStatus),
+) error {
+	status, err := b.getBroadcastStatus(ctx, dbTx, broadcast.TransactionIdentifier)
+	created := false
+	switch {
+	case errors.Is(err, storageErrs.ErrBroadcastNotFound):
+		status = &BroadcastStatus{
+			Identifier:            broadcast.Identifier,
+			TransactionIdentifier: broadcast.TransactionIdentifier,
+			State:                 BroadcastStatePending,
+		}
+		created = true
+	case err != nil:
+		return err
+	}
+
+	original := types.Hash(status)
+	status.Broadcasts = broadcast.Broadcasts
+	update(status)
+	if !created && types.Hash(status) == original {
+		return nil
+	}
+
+	namespace, key := getBroadcastStatusKey(broadcast.TransactionIdentifier)
+	bytes, err := b.db.Encoder().Encode(namespace, status)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastStatusEncodeFailed, err)
+	}
+
+	if err := b.setRecord(ctx, dbTx, key, bytes, status.State.finished()); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastStatusUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// setRecord stores a record of a broadcast. If the
+// broadcast is finished, the record is deleted after
+// the status retention (if one is configured).
+func (b *BroadcastStorage) setRecord(
+	ctx context.Context,
+	dbTx database.Transaction,
+	key []byte,
+	value []byte,
+	finished bool,
+) error {
+	if finished && b.statusRetention > 0 {
+		return dbTx.SetWithTTL(ctx, key, value, b.statusRetention, true)
+	}
+
+	return dbTx.Set(ctx, key, value, true)
+}
+
+// setBroadcastState is a convenience wrapper around updateBroadcastStatus
+// for transitions that only modify the state of a broadcast.
+func (b *BroadcastStorage) setBroadcastState(
+	ctx context.Context,
+	dbTx database.Transaction,
+	broadcast *Broadcast,
+	state BroadcastState,
+	reason string,
+) error {
+	return b.updateBroadcastStatus(ctx, dbTx, broadcast, func(status *BroadcastStatus) {
+		status.State = state
+		status.Reason = reason
+		if state == BroadcastStatePending || state == BroadcastStateWaiting {
+			status.Block = nil
+			status.Depth = 0
+		}
+	})
+}
+
+// GetBroadcastStatus returns the *BroadcastStatus of
+// a *types.TransactionIdentifier, if it exists.
+func (b *BroadcastStorage) GetBroadcastStatus(
+	ctx context.Context,
+	transactionIdentifier *types.TransactionIdentifier,
+) (*BroadcastStatus, error) {
+	dbTx := b.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	return b.getBroadcastStatus(ctx, dbTx, transactionIdentifier)
+}
+
+// ListBroadcasts returns the *BroadcastStatus of all broadcasts
+// in any of the provided states. If no states are provided,
+// all statuses are returned.
+func (b *BroadcastStorage) ListBroadcasts(
+	ctx context.Context,
+	states ...BroadcastState,
+) ([]*BroadcastStatus, error) {
+	dbTx := b.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	stateFilter := map[BroadcastState]struct{}{}
+	for _, state := range states {
+		stateFilter[state] = struct{}{}
+	}
+
+	namespace := broadcastStatusNamespace
+	statuses := []*BroadcastStatus{}
+	_, err := dbTx.Scan(
+		ctx,
+		[]byte(namespace),
+		[]byte(namespace),
+		func(k []byte, v []byte) error {
+			var status BroadcastStatus
+			// We should not reclaim memory during a scan!!
+			if err := b.db.Encoder().Decode(namespace, v, &status, false); err != nil {
+				return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastStatusDecodeFailed, err)
+			}
+
+			if _, ok := stateFilter[status.State]; len(stateFilter) > 0 && !ok {
+				return nil
+			}
+
+			statuses = append(statuses, &status)
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastStatusScanFailed, err)
+	}
+
+	return statuses, nil
+}
+
 // AddingBlock is called by BlockStorage when adding a block.
 func (b *BroadcastStorage) AddingBlock(
 	ctx context.Context,
@@ -219,23 +582,42 @@ func (b *BroadcastStorage) AddingBlock(
 	block *types.Block,
 	transaction database.Transaction,
 ) (database.CommitWorker, error) {
-	broadcasts, err := b.GetAllBroadcasts(ctx)
+	broadcasts, err := b.getAllBroadcasts(ctx, transaction)
 	if err != nil {
 		return nil, fmt.Errorf("%w: unable to get all broadcasts", err)
 	}
 
 	staleBroadcasts := []*Broadcast{}
+	expiredBroadcasts := []*Broadcast{}
 	confirmedTransactions := []*Broadcast{}
 	foundTransactions := []*types.Transaction{}
 	foundBlocks := []*types.BlockIdentifier{}
 
 	for _, broadcast := range broadcasts {
+		namespace, key := getBroadcastKey(broadcast.TransactionIdentifier)
+
 		if broadcast.LastBroadcast == nil {
+			// Broadcasts waiting to be rebroadcast can still expire.
+			if b.expired(broadcast, block.BlockIdentifier.Index) {
+				expiredBroadcasts = append(expiredBroadcasts, broadcast)
+				if err := b.archiveBroadcast(ctx, transaction, broadcast); err != nil {
+					return nil, err
+				}
+
+				if err := b.setBroadcastState(
+					ctx,
+					transaction,
+					broadcast,
+					BroadcastStateExpired,
+					"",
+				); err != nil {
+					return nil, err
+				}
+			}
+
 			continue
 		}
 
-		namespace, key := getBroadcastKey(broadcast.TransactionIdentifier)
-
 		// We perform the FindTransaction search in the context of the block database
 		// transaction so we can access any transactions of depth 1 (in the current
 		// block).
@@ -245,7 +627,27 @@ func (b *BroadcastStorage) AddingBlock(
 			transaction,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastFindTxFailed, err)
+			return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastFindTxFailed, err)
+		}
+
+		// Check if we should mark the broadcast as expired
+		if foundBlock == nil && b.expired(broadcast, block.BlockIdentifier.Index) {
+			expiredBroadcasts = append(expiredBroadcasts, broadcast)
+			if err := b.archiveBroadcast(ctx, transaction, broadcast); err != nil {
+				return nil, err
+			}
+
+			if err := b.setBroadcastState(
+				ctx,
+				transaction,
+				broadcast,
+				BroadcastStateExpired,
+				"",
+			); err != nil {
+				return nil, err
+			}
+
+			continue
 		}
 
 		// Check if we should mark the broadcast as stale
@@ -255,47 +657,73 @@ func (b *BroadcastStorage) AddingBlock(
 			broadcast.LastBroadcast = nil
 			bytes, err := b.db.Encoder().Encode(namespace, broadcast)
 			if err != nil {
-				return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastEncodeUpdateFailed, err)
+				return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastEncodeUpdateFailed, err)
 			}
 
 			if err := transaction.Set(ctx, key, bytes, true); err != nil {
-				return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastUpdateFailed, err)
+				return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastUpdateFailed, err)
 			}
 
 			continue
 		}
 
-		// Continue if we are still waiting for a broadcast to appear and it isn't stale
+		// Continue if we are still waiting for a broadcast to appear and it isn't stale.
+		// The state of a broadcast is already pending unless it was seen in a
+		// block that was removed (which RemovingBlock resets to pending).
 		if foundBlock == nil {
 			continue
 		}
 
+		state := BroadcastStateSeen
+		depth := block.BlockIdentifier.Index - foundBlock.Index + depthOffset
+
 		// Check if we should mark the transaction as confirmed
-		if block.BlockIdentifier.Index-foundBlock.Index >= broadcast.ConfirmationDepth-depthOffset {
+		if depth >= broadcast.ConfirmationDepth {
+			state = BroadcastStateConfirmed
 			confirmedTransactions = append(confirmedTransactions, broadcast)
 			foundTransactions = append(foundTransactions, foundTransaction)
 			foundBlocks = append(foundBlocks, foundBlock)
 
 			if err := transaction.Delete(ctx, key); err != nil {
-				return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastDeleteConfirmedTxFailed, err)
+				return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDeleteConfirmedTxFailed, err)
 			}
 		}
+
+		if err := b.updateBroadcastStatus(
+			ctx,
+			transaction,
+			broadcast,
+			func(status *BroadcastStatus) {
+				status.State = state
+				status.Block = foundBlock
+				status.Depth = depth
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	// Broadcasts that depend on an expired broadcast
+	// can never succeed.
+	if err := b.failDependents(ctx, transaction, expiredBroadcasts); err != nil {
+		return nil, err
 	}
 
 	if err := b.invokeAddBlockHandlers(
 		ctx,
 		transaction,
 		staleBroadcasts,
+		expiredBroadcasts,
 		confirmedTransactions,
 		foundBlocks,
 		foundTransactions,
 	); err != nil {
-		return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastInvokeBlockHandlersFailed, err)
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastInvokeBlockHandlersFailed, err)
 	}
 
 	return func(ctx context.Context) error {
 		if err := b.BroadcastAll(ctx, true); err != nil {
-			return fmt.Errorf("%w: %v", errors.ErrBroadcastFailed, err)
+			return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastFailed, err)
 		}
 
 		return nil
@@ -303,6 +731,10 @@ func (b *BroadcastStorage) AddingBlock(
 }
 
 // RemovingBlock is called by BlockStorage when removing a block.
+// Any broadcast seen in the removed block is moved back to pending
+// (the status of a broadcast confirmed in the removed block is deleted)
+// and any broadcast with a prerequisite transaction in the removed block
+// is moved back to waiting.
 // TODO: error if transaction removed after confirmed (means confirmation depth not deep enough)
 func (b *BroadcastStorage) RemovingBlock(
 	ctx context.Context,
@@ -310,11 +742,125 @@ func (b *BroadcastStorage) RemovingBlock(
 	block *types.Block,
 	transaction database.Transaction,
 ) (database.CommitWorker, error) {
+	removed := map[string]struct{}{}
+	for _, tx := range block.Transactions {
+		removed[types.Hash(tx.TransactionIdentifier)] = struct{}{}
+	}
+
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	if err := b.orphanStatuses(ctx, transaction, block); err != nil {
+		return nil, err
+	}
+
+	broadcasts, err := b.getAllBroadcasts(ctx, transaction)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get all broadcasts", err)
+	}
+
+	for _, broadcast := range broadcasts {
+		orphaned := false
+		for _, prerequisite := range broadcast.Prerequisites {
+			if _, ok := removed[types.Hash(prerequisite)]; ok {
+				orphaned = true
+				break
+			}
+		}
+
+		if !orphaned {
+			continue
+		}
+
+		// Clearing LastBroadcast ensures the broadcast is attempted
+		// again once all prerequisites are seen on-chain.
+		broadcast.LastBroadcast = nil
+		namespace, key := getBroadcastKey(broadcast.TransactionIdentifier)
+		bytes, err := b.db.Encoder().Encode(namespace, broadcast)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastEncodeUpdateFailed, err)
+		}
+
+		if err := transaction.Set(ctx, key, bytes, true); err != nil {
+			return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastUpdateFailed, err)
+		}
+
+		if err := b.setBroadcastState(
+			ctx,
+			transaction,
+			broadcast,
+			BroadcastStateWaiting,
+			"",
+		); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, nil
 }
 
+// orphanStatuses rolls back the BroadcastStatus of any broadcast
+// seen in a removed block. Active broadcasts are moved back to
+// pending while the status of a broadcast confirmed in the removed
+// block is deleted (its broadcast is no longer tracked).
+func (b *BroadcastStorage) orphanStatuses(
+	ctx context.Context,
+	dbTx database.Transaction,
+	block *types.Block,
+) error {
+	blockHash := types.Hash(block.BlockIdentifier)
+	for _, tx := range block.Transactions {
+		status, err := b.getBroadcastStatus(ctx, dbTx, tx.TransactionIdentifier)
+		if errors.Is(err, storageErrs.ErrBroadcastNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if status.Block == nil || types.Hash(status.Block) != blockHash {
+			continue
+		}
+
+		if status.State == BroadcastStateConfirmed {
+			_, key := getBroadcastStatusKey(tx.TransactionIdentifier)
+			if err := dbTx.Delete(ctx, key); err != nil {
+				return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastStatusUpdateFailed, err)
+			}
+
+			continue
+		}
+
+		broadcast, active, err := b.findBroadcast(ctx, dbTx, tx.TransactionIdentifier)
+		if err != nil {
+			return err
+		}
+
+		if broadcast == nil || !active {
+			continue
+		}
+
+		if err := b.setBroadcastState(
+			ctx,
+			dbTx,
+			broadcast,
+			BroadcastStatePending,
+			"",
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Broadcast is called when a caller wants a transaction to be broadcast and tracked.
 // The caller SHOULD NOT broadcast the transaction before calling this function.
+// If confirmationDepth is 0, the default confirmation depth of BroadcastStorage
+// is used. Any BroadcastOption provided overrides the BroadcastStorage defaults
+// for this broadcast.
 func (b *BroadcastStorage) Broadcast(
 	ctx context.Context,
 	dbTx database.Transaction,
@@ -324,19 +870,24 @@ func (b *BroadcastStorage) Broadcast(
 	transactionIdentifier *types.TransactionIdentifier,
 	payload string,
 	confirmationDepth int64,
+	options ...BroadcastOption,
 ) error {
 	namespace, broadcastKey := getBroadcastKey(transactionIdentifier)
 
 	exists, _, err := dbTx.Get(ctx, broadcastKey)
 	if err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrBroadcastDBGetFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDBGetFailed, err)
 	}
 
 	if exists {
-		return fmt.Errorf("%w %s", errors.ErrBroadcastAlreadyExists, transactionIdentifier.Hash)
+		return fmt.Errorf("%w %s", storageErrs.ErrBroadcastAlreadyExists, transactionIdentifier.Hash)
+	}
+
+	if confirmationDepth == 0 {
+		confirmationDepth = b.confirmationDepth
 	}
 
-	bytes, err := b.db.Encoder().Encode(namespace, Broadcast{
+	broadcast := &Broadcast{
 		Identifier:            identifier,
 		NetworkIdentifier:     network,
 		TransactionIdentifier: transactionIdentifier,
@@ -344,16 +895,35 @@ func (b *BroadcastStorage) Broadcast(
 		Payload:               payload,
 		Broadcasts:            0,
 		ConfirmationDepth:     confirmationDepth,
-	})
+		ExpirationDepth:       b.expirationDepth,
+		expirationDuration:    b.expirationDuration,
+	}
+
+	for _, opt := range options {
+		opt(broadcast)
+	}
+
+	if broadcast.expirationDuration > 0 {
+		broadcast.ExpirationTime = b.now().Add(
+			broadcast.expirationDuration,
+		).UnixNano() / utils.NanosecondsInMillisecond
+	}
+
+	bytes, err := b.db.Encoder().Encode(namespace, broadcast)
 	if err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrBroadcastEncodeFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastEncodeFailed, err)
 	}
 
 	if err := dbTx.Set(ctx, broadcastKey, bytes, true); err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrBroadcastSetFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastSetFailed, err)
 	}
 
-	return nil
+	state := BroadcastStatePending
+	if len(broadcast.Prerequisites) > 0 {
+		state = BroadcastStateWaiting
+	}
+
+	return b.setBroadcastState(ctx, dbTx, broadcast, state, "")
 }
 
 func (b *BroadcastStorage) getAllBroadcasts(
@@ -370,7 +940,7 @@ func (b *BroadcastStorage) getAllBroadcasts(
 			var broadcast Broadcast
 			// We should not reclaim memory during a scan!!
 			if err := b.db.Encoder().Decode(namespace, v, &broadcast, false); err != nil {
-				return fmt.Errorf("%w: %v", errors.ErrBroadcastDecodeFailed, err)
+				return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDecodeFailed, err)
 			}
 
 			broadcasts = append(broadcasts, &broadcast)
@@ -380,40 +950,344 @@ func (b *BroadcastStorage) getAllBroadcasts(
 		false,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastScanFailed, err)
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastScanFailed, err)
 	}
 
 	return broadcasts, nil
 }
 
-// GetAllBroadcasts returns all currently in-process broadcasts.
-func (b *BroadcastStorage) GetAllBroadcasts(ctx context.Context) ([]*Broadcast, error) {
-	dbTx := b.db.ReadTransaction(ctx)
-	defer dbTx.Discard(ctx)
-
-	return b.getAllBroadcasts(ctx, dbTx)
-}
-
-func (b *BroadcastStorage) performBroadcast(
+// ExpireBroadcasts flags all in-process broadcasts associated
+// with an identifier to expire. Flagged broadcasts that are
+// not seen on-chain are expired (and the handler is
+// notified) when the next block is added.
+func (b *BroadcastStorage) ExpireBroadcasts(
 	ctx context.Context,
-	broadcast *Broadcast,
-	onlyEligible bool,
+	dbTx database.Transaction,
+	identifier string,
 ) error {
-	namespace, key := getBroadcastKey(broadcast.TransactionIdentifier)
-	bytes, err := b.db.Encoder().Encode(namespace, broadcast)
+	broadcasts, err := b.getAllBroadcasts(ctx, dbTx)
 	if err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrBroadcastEncodeFailed, err)
+		return fmt.Errorf("%w: unable to get all broadcasts", err)
 	}
 
-	txn := b.db.Transaction(ctx)
-	defer txn.Discard(ctx)
+	expirationTime := b.now().UnixNano() / utils.NanosecondsInMillisecond
+	for _, broadcast := range broadcasts {
+		if broadcast.Identifier != identifier {
+			continue
+		}
+
+		broadcast.ExpirationTime = expirationTime
+		namespace, key := getBroadcastKey(broadcast.TransactionIdentifier)
+		bytes, err := b.db.Encoder().Encode(namespace, broadcast)
+		if err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastEncodeUpdateFailed, err)
+		}
+
+		if err := dbTx.Set(ctx, key, bytes, true); err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastUpdateFailed, err)
+		}
+	}
+
+	return nil
+}
+
+// GetAllBroadcasts returns all currently in-process broadcasts.
+func (b *BroadcastStorage) GetAllBroadcasts(ctx context.Context) ([]*Broadcast, error) {
+	dbTx := b.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	return b.getAllBroadcasts(ctx, dbTx)
+}
+
+// failBroadcast removes a broadcast from BroadcastStorage
+// and invokes the BroadcastFailed handler with the cause
+// of the failure.
+func (b *BroadcastStorage) failBroadcast(
+	ctx context.Context,
+	dbTx database.Transaction,
+	broadcast *Broadcast,
+	cause error,
+) error {
+	_, key := getBroadcastKey(broadcast.TransactionIdentifier)
+
+	// The broadcast may have already failed if
+	// one of its prerequisites failed.
+	exists, _, err := dbTx.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDBGetFailed, err)
+	}
+
+	if !exists {
+		return nil
+	}
+
+	if err := b.archiveBroadcast(ctx, dbTx, broadcast); err != nil {
+		return err
+	}
+
+	if err := b.setBroadcastState(
+		ctx,
+		dbTx,
+		broadcast,
+		BroadcastStateFailed,
+		cause.Error(),
+	); err != nil {
+		return err
+	}
+
+	if err := b.handleFailure(ctx, dbTx, broadcast, cause); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastHandleFailureUnsuccessful, err)
+	}
+
+	return b.failDependents(ctx, dbTx, []*Broadcast{broadcast})
+}
+
+// handleFailure invokes BroadcastFailedWithCause if the
+// handler implements BroadcastFailureHandler, otherwise
+// BroadcastFailed is invoked.
+func (b *BroadcastStorage) handleFailure(
+	ctx context.Context,
+	dbTx database.Transaction,
+	broadcast *Broadcast,
+	cause error,
+) error {
+	if failureHandler, ok := b.handler.(BroadcastFailureHandler); ok {
+		return failureHandler.BroadcastFailedWithCause(
+			ctx,
+			dbTx,
+			broadcast.Identifier,
+			broadcast.TransactionIdentifier,
+			broadcast.Intent,
+			cause,
+		)
+	}
+
+	return b.handler.BroadcastFailed(
+		ctx,
+		dbTx,
+		broadcast.Identifier,
+		broadcast.TransactionIdentifier,
+		broadcast.Intent,
+	)
+}
+
+// handleExpiration invokes BroadcastExpired if the handler
+// implements BroadcastExpirationHandler, otherwise the
+// broadcast is handled as a failure (with ErrBroadcastExpired).
+func (b *BroadcastStorage) handleExpiration(
+	ctx context.Context,
+	dbTx database.Transaction,
+	broadcast *Broadcast,
+) error {
+	if expirationHandler, ok := b.handler.(BroadcastExpirationHandler); ok {
+		return expirationHandler.BroadcastExpired(
+			ctx,
+			dbTx,
+			broadcast.Identifier,
+			broadcast.TransactionIdentifier,
+			broadcast.Intent,
+		)
+	}
+
+	return b.handleFailure(ctx, dbTx, broadcast, storageErrs.ErrBroadcastExpired)
+}
+
+// archiveBroadcast removes a broadcast from the active set
+// and stores it so that it can later be requeued.
+func (b *BroadcastStorage) archiveBroadcast(
+	ctx context.Context,
+	dbTx database.Transaction,
+	broadcast *Broadcast,
+) error {
+	_, key := getBroadcastKey(broadcast.TransactionIdentifier)
+	if err := dbTx.Delete(ctx, key); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDeleteFailed, err)
+	}
+
+	namespace, archivedKey := getArchivedBroadcastKey(broadcast.TransactionIdentifier)
+	bytes, err := b.db.Encoder().Encode(namespace, broadcast)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastEncodeUpdateFailed, err)
+	}
+
+	if err := b.setRecord(ctx, dbTx, archivedKey, bytes, true); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastSetFailed, err)
+	}
+
+	return nil
+}
+
+// failDependents fails all broadcasts that have any of
+// the provided broadcasts as a prerequisite.
+func (b *BroadcastStorage) failDependents(
+	ctx context.Context,
+	dbTx database.Transaction,
+	parents []*Broadcast,
+) error {
+	if len(parents) == 0 {
+		return nil
+	}
+
+	broadcasts, err := b.getAllBroadcasts(ctx, dbTx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastGetAllFailed, err)
+	}
+
+	for _, broadcast := range broadcasts {
+		for _, parent := range parents {
+			if !broadcast.dependsOn(parent.TransactionIdentifier) {
+				continue
+			}
+
+			if err := b.failBroadcast(
+				ctx,
+				dbTx,
+				broadcast,
+				fmt.Errorf(
+					"%w: %s",
+					storageErrs.ErrBroadcastPrerequisiteFailed,
+					parent.TransactionIdentifier.Hash,
+				),
+			); err != nil {
+				return err
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// dependsOn returns a boolean indicating if a
+// *types.TransactionIdentifier is a prerequisite
+// of a Broadcast.
+func (b *Broadcast) dependsOn(transactionIdentifier *types.TransactionIdentifier) bool {
+	for _, prerequisite := range b.Prerequisites {
+		if types.Hash(prerequisite) == types.Hash(transactionIdentifier) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prerequisitesSeen returns a boolean indicating if all
+// prerequisites of a Broadcast have been seen on-chain.
+func (b *BroadcastStorage) prerequisitesSeen(
+	ctx context.Context,
+	dbTx database.Transaction,
+	broadcast *Broadcast,
+) (bool, error) {
+	for _, prerequisite := range broadcast.Prerequisites {
+		foundBlock, _, err := b.helper.FindTransaction(ctx, prerequisite, dbTx)
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastFindTxFailed, err)
+		}
+
+		if foundBlock == nil {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// backoffDelay returns the number of blocks to wait before
+// attempting a broadcast that has been rejected attempts times.
+func (b *BroadcastStorage) backoffDelay(attempts int) int64 {
+	delay := float64(b.backoffInitialDelay) *
+		math.Pow(b.backoffMultiplier, float64(attempts-1))
+	if b.backoffMaxDelay > 0 && delay > float64(b.backoffMaxDelay) {
+		return b.backoffMaxDelay
+	}
+
+	return int64(delay)
+}
+
+// handleRejectedBroadcast schedules the next attempt of a broadcast
+// rejected by the Rosetta implementation or fails the broadcast
+// if the maximum number of attempts has been reached.
+func (b *BroadcastStorage) handleRejectedBroadcast(
+	ctx context.Context,
+	broadcast *Broadcast,
+	submissionErr error,
+) error {
+	txn := b.db.Transaction(ctx)
+	defer txn.Discard(ctx)
+
+	broadcast.SubmissionErrors = append(broadcast.SubmissionErrors, submissionErr.Error())
+	attempts := len(broadcast.SubmissionErrors)
+	if b.backoffMaxAttempts > 0 && attempts >= b.backoffMaxAttempts {
+		if err := b.failBroadcast(
+			ctx,
+			txn,
+			broadcast,
+			fmt.Errorf(
+				"%w: %s",
+				storageErrs.ErrBroadcastAttemptsExceeded,
+				strings.Join(broadcast.SubmissionErrors, "; "),
+			),
+		); err != nil {
+			return err
+		}
+
+		if err := txn.Commit(ctx); err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastCommitDeleteFailed, err)
+		}
+
+		return nil
+	}
+
+	// Clearing LastBroadcast makes the broadcast eligible
+	// for another attempt once NextBroadcast is reached.
+	broadcast.NextBroadcast = broadcast.LastBroadcast.Index + b.backoffDelay(attempts)
+	broadcast.LastBroadcast = nil
+
+	namespace, key := getBroadcastKey(broadcast.TransactionIdentifier)
+	bytes, err := b.db.Encoder().Encode(namespace, broadcast)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastEncodeUpdateFailed, err)
+	}
+
+	if err := txn.Set(ctx, key, bytes, true); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastUpdateFailed, err)
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastCommitUpdateFailed, err)
+	}
+
+	return nil
+}
+
+func (b *BroadcastStorage) performBroadcast(
+	ctx context.Context,
+	broadcast *Broadcast,
+	onlyEligible bool,
+) error {
+	namespace, key := getBroadcastKey(broadcast.TransactionIdentifier)
+	bytes, err := b.db.Encoder().Encode(namespace, broadcast)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastEncodeFailed, err)
+	}
+
+	txn := b.db.Transaction(ctx)
+	defer txn.Discard(ctx)
 
 	if err := txn.Set(ctx, key, bytes, true); err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrBroadcastUpdateFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastUpdateFailed, err)
+	}
+
+	if err := b.updateBroadcastStatus(ctx, txn, broadcast, func(status *BroadcastStatus) {
+		status.State = BroadcastStatePending
+		status.LastBroadcastTime = b.now().UnixNano() / utils.NanosecondsInMillisecond
+	}); err != nil {
+		return err
 	}
 
 	if err := txn.Commit(ctx); err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrBroadcastCommitUpdateFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastCommitUpdateFailed, err)
 	}
 
 	if !onlyEligible {
@@ -433,13 +1307,17 @@ func (b *BroadcastStorage) performBroadcast(
 			broadcast.TransactionIdentifier.Hash,
 		)
 
+		if b.backoffInitialDelay > 0 {
+			return b.handleRejectedBroadcast(ctx, broadcast, err)
+		}
+
 		return nil
 	}
 
 	if types.Hash(broadcastIdentifier) != types.Hash(broadcast.TransactionIdentifier) {
 		return fmt.Errorf(
 			"%w: expected %s but got %s",
-			errors.ErrBroadcastIdentifierMismatch,
+			storageErrs.ErrBroadcastIdentifierMismatch,
 			broadcast.TransactionIdentifier.Hash,
 			broadcastIdentifier.Hash,
 		)
@@ -458,7 +1336,7 @@ func (b *BroadcastStorage) BroadcastAll(ctx context.Context, onlyEligible bool)
 
 	currBlock, err := b.helper.CurrentBlockIdentifier(ctx)
 	if err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrBroadcastGetCurrentBlockIdentifierFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastGetCurrentBlockIdentifierFailed, err)
 	}
 
 	// We have not yet synced a block and should wait to broadcast
@@ -470,7 +1348,7 @@ func (b *BroadcastStorage) BroadcastAll(ctx context.Context, onlyEligible bool)
 	// Wait to broadcast transaction until close to tip
 	atTip, err := b.helper.AtTip(ctx, b.tipDelay)
 	if err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrBroadcastAtTipFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastAtTipFailed, err)
 	}
 
 	if (!atTip && !b.broadcastBehindTip) && onlyEligible {
@@ -479,7 +1357,7 @@ func (b *BroadcastStorage) BroadcastAll(ctx context.Context, onlyEligible bool)
 
 	broadcasts, err := b.GetAllBroadcasts(ctx)
 	if err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrBroadcastGetAllFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastGetAllFailed, err)
 	}
 
 	attemptedBroadcasts := 0
@@ -490,27 +1368,42 @@ func (b *BroadcastStorage) BroadcastAll(ctx context.Context, onlyEligible bool)
 			continue
 		}
 
+		// Wait to re-attempt rejected broadcasts until
+		// their backoff has elapsed.
+		if broadcast.NextBroadcast > currBlock.Index && onlyEligible {
+			continue
+		}
+
+		// Never broadcast a transaction before all of its
+		// prerequisites are seen on-chain.
+		if len(broadcast.Prerequisites) > 0 {
+			dbTx := b.db.ReadTransaction(ctx)
+			seen, err := b.prerequisitesSeen(ctx, dbTx, broadcast)
+			dbTx.Discard(ctx)
+			if err != nil {
+				return err
+			}
+
+			if !seen {
+				continue
+			}
+		}
+
 		if broadcast.Broadcasts >= b.broadcastLimit {
 			txn := b.db.Transaction(ctx)
 			defer txn.Discard(ctx)
 
-			_, key := getBroadcastKey(broadcast.TransactionIdentifier)
-			if err := txn.Delete(ctx, key); err != nil {
-				return fmt.Errorf("%w: %v", errors.ErrBroadcastDeleteFailed, err)
-			}
-
-			if err := b.handler.BroadcastFailed(
+			if err := b.failBroadcast(
 				ctx,
 				txn,
-				broadcast.Identifier,
-				broadcast.TransactionIdentifier,
-				broadcast.Intent,
+				broadcast,
+				fmt.Errorf("%w of %d", storageErrs.ErrBroadcastLimitExceeded, b.broadcastLimit),
 			); err != nil {
-				return fmt.Errorf("%w: %v", errors.ErrBroadcastHandleFailureUnsuccessful, err)
+				return err
 			}
 
 			if err := txn.Commit(ctx); err != nil {
-				return fmt.Errorf("%w: %v", errors.ErrBroadcastCommitDeleteFailed, err)
+				return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastCommitDeleteFailed, err)
 			}
 
 			continue
@@ -529,9 +1422,12 @@ func (b *BroadcastStorage) BroadcastAll(ctx context.Context, onlyEligible bool)
 		// it will be rebroadcast when it is considered stale!
 		broadcast.LastBroadcast = currBlock
 		broadcast.Broadcasts++
+		if broadcast.ExpirationDepth > 0 && broadcast.FirstBroadcast == nil {
+			broadcast.FirstBroadcast = currBlock
+		}
 
 		if err := b.performBroadcast(ctx, broadcast, onlyEligible); err != nil {
-			return fmt.Errorf("%w: %v", errors.ErrBroadcastPerformFailed, err)
+			return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastPerformFailed, err)
 		}
 	}
 
@@ -547,7 +1443,7 @@ func (b *BroadcastStorage) LockedAccounts(
 ) ([]*types.AccountIdentifier, error) {
 	broadcasts, err := b.getAllBroadcasts(ctx, dbTx)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastGetAllFailed, err)
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastGetAllFailed, err)
 	}
 
 	// De-duplicate accounts present in broadcast storage.
@@ -576,42 +1472,206 @@ func (b *BroadcastStorage) LockedAccounts(
 func (b *BroadcastStorage) ClearBroadcasts(ctx context.Context) ([]*Broadcast, error) {
 	broadcasts, err := b.GetAllBroadcasts(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastGetAllFailed, err)
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastGetAllFailed, err)
 	}
 
 	txn := b.db.Transaction(ctx)
 	for _, broadcast := range broadcasts {
-		_, key := getBroadcastKey(broadcast.TransactionIdentifier)
-		if err := txn.Delete(ctx, key); err != nil {
-			return nil, fmt.Errorf(
-				"%w %s: %v",
-				errors.ErrBroadcastDeleteFailed,
-				broadcast.Identifier,
-				err,
-			)
-		}
-
 		// When clearing broadcasts, make sure to invoke the handler
 		// so other services can be updated.
-		if err := b.handler.BroadcastFailed(
+		if err := b.failBroadcast(
 			ctx,
 			txn,
-			broadcast.Identifier,
-			broadcast.TransactionIdentifier,
-			broadcast.Intent,
+			broadcast,
+			storageErrs.ErrBroadcastCleared,
 		); err != nil {
-			return nil, fmt.Errorf(
-				"%w %s: %v",
-				errors.ErrBroadcastHandleFailureUnsuccessful,
-				broadcast.Identifier,
-				err,
-			)
+			return nil, fmt.Errorf("%w: unable to clear %s", err, broadcast.Identifier)
 		}
 	}
 
 	if err := txn.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastCommitDeleteFailed, err)
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastCommitDeleteFailed, err)
 	}
 
 	return broadcasts, nil
 }
+
+// ClearBroadcast deletes all records of a broadcast, including
+// its status. If the broadcast is still active, the BroadcastFailed
+// handler is invoked (and any dependent broadcasts are failed) before
+// it is deleted. This can be called while blocks are being processed.
+func (b *BroadcastStorage) ClearBroadcast(
+	ctx context.Context,
+	transactionIdentifier *types.TransactionIdentifier,
+) error {
+	b.broadcastAllMutex.Lock()
+	defer b.broadcastAllMutex.Unlock()
+
+	// We use the same write lock as BlockStorage so that
+	// we never modify a broadcast while AddingBlock or
+	// RemovingBlock (and their handlers) are running.
+	txn := b.db.WriteTransaction(ctx, blockSyncIdentifier, false)
+	defer txn.Discard(ctx)
+
+	// Confirmed broadcasts only have a status record.
+	_, statusErr := b.getBroadcastStatus(ctx, txn, transactionIdentifier)
+	if statusErr != nil && !errors.Is(statusErr, storageErrs.ErrBroadcastNotFound) {
+		return statusErr
+	}
+
+	broadcast, active, err := b.findBroadcast(ctx, txn, transactionIdentifier)
+	if err != nil {
+		return err
+	}
+
+	if broadcast == nil && statusErr != nil {
+		return statusErr
+	}
+
+	if active {
+		if err := b.failBroadcast(
+			ctx,
+			txn,
+			broadcast,
+			storageErrs.ErrBroadcastCleared,
+		); err != nil {
+			return fmt.Errorf("%w: unable to clear %s", err, broadcast.Identifier)
+		}
+	}
+
+	_, archivedKey := getArchivedBroadcastKey(transactionIdentifier)
+	if err := txn.Delete(ctx, archivedKey); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDeleteFailed, err)
+	}
+
+	_, statusKey := getBroadcastStatusKey(transactionIdentifier)
+	if err := txn.Delete(ctx, statusKey); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDeleteFailed, err)
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastCommitDeleteFailed, err)
+	}
+
+	return nil
+}
+
+// RequeueBroadcast resets the attempt counters of a broadcast
+// and moves it back to pending (or waiting, if it has prerequisites).
+// Failed and expired broadcasts are restored to the active set. Because
+// per-broadcast expiration durations are not persisted, a requeued
+// broadcast uses the expiration duration of BroadcastStorage. This can
+// be called while blocks are being processed.
+func (b *BroadcastStorage) RequeueBroadcast(
+	ctx context.Context,
+	transactionIdentifier *types.TransactionIdentifier,
+) error {
+	b.broadcastAllMutex.Lock()
+	defer b.broadcastAllMutex.Unlock()
+
+	// We use the same write lock as BlockStorage so that
+	// we never modify a broadcast while AddingBlock or
+	// RemovingBlock (and their handlers) are running.
+	txn := b.db.WriteTransaction(ctx, blockSyncIdentifier, false)
+	defer txn.Discard(ctx)
+
+	status, err := b.getBroadcastStatus(ctx, txn, transactionIdentifier)
+	if err != nil {
+		return err
+	}
+
+	if status.State == BroadcastStateConfirmed {
+		return fmt.Errorf(
+			"%w: %s",
+			storageErrs.ErrBroadcastRequeueConfirmed,
+			transactionIdentifier.Hash,
+		)
+	}
+
+	broadcast, _, err := b.findBroadcast(ctx, txn, transactionIdentifier)
+	if err != nil {
+		return err
+	}
+
+	if broadcast == nil {
+		return fmt.Errorf("%w: %s", storageErrs.ErrBroadcastNotFound, transactionIdentifier.Hash)
+	}
+
+	broadcast.LastBroadcast = nil
+	broadcast.Broadcasts = 0
+	broadcast.FirstBroadcast = nil
+	broadcast.NextBroadcast = 0
+	broadcast.SubmissionErrors = nil
+	broadcast.ExpirationTime = 0
+	if b.expirationDuration > 0 {
+		broadcast.ExpirationTime = b.now().Add(
+			b.expirationDuration,
+		).UnixNano() / utils.NanosecondsInMillisecond
+	}
+
+	namespace, key := getBroadcastKey(transactionIdentifier)
+	bytes, err := b.db.Encoder().Encode(namespace, broadcast)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastEncodeUpdateFailed, err)
+	}
+
+	if err := txn.Set(ctx, key, bytes, true); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastSetFailed, err)
+	}
+
+	_, archivedKey := getArchivedBroadcastKey(transactionIdentifier)
+	if err := txn.Delete(ctx, archivedKey); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDeleteFailed, err)
+	}
+
+	state := BroadcastStatePending
+	if len(broadcast.Prerequisites) > 0 {
+		state = BroadcastStateWaiting
+	}
+
+	if err := b.setBroadcastState(ctx, txn, broadcast, state, ""); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBroadcastCommitUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// findBroadcast returns the stored *Broadcast for a
+// *types.TransactionIdentifier and a boolean indicating
+// if it is active (as opposed to failed or expired). If
+// no broadcast is stored, a nil *Broadcast is returned.
+func (b *BroadcastStorage) findBroadcast(
+	ctx context.Context,
+	dbTx database.Transaction,
+	transactionIdentifier *types.TransactionIdentifier,
+) (*Broadcast, bool, error) {
+	namespace, key := getBroadcastKey(transactionIdentifier)
+	exists, val, err := dbTx.Get(ctx, key)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDBGetFailed, err)
+	}
+
+	active := exists
+	if !exists {
+		namespace, key = getArchivedBroadcastKey(transactionIdentifier)
+		exists, val, err = dbTx.Get(ctx, key)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDBGetFailed, err)
+		}
+	}
+
+	if !exists {
+		return nil, false, nil
+	}
+
+	var broadcast Broadcast
+	if err := b.db.Encoder().Decode(namespace, val, &broadcast, true); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", storageErrs.ErrBroadcastDecodeFailed, err)
+	}
+
+	return &broadcast, active, nil
+}
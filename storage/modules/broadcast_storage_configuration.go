@@ -0,0 +1,116 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// BroadcastStorageOption is used to overwrite default values in
+// BroadcastStorage construction. Any Option not provided
+// falls back to the default value.
+type BroadcastStorageOption func(b *BroadcastStorage)
+
+// WithConfirmationDepth sets the default confirmation depth
+// used for broadcasts enqueued with a confirmation depth of 0.
+func WithConfirmationDepth(depth int64) BroadcastStorageOption {
+	return func(b *BroadcastStorage) {
+		b.confirmationDepth = depth
+	}
+}
+
+// WithExpirationDepth sets the default number of blocks after
+// the first broadcast attempt that a transaction must appear
+// on-chain before it is considered expired. A value of 0
+// disables depth-based expiration.
+func WithExpirationDepth(depth int64) BroadcastStorageOption {
+	return func(b *BroadcastStorage) {
+		b.expirationDepth = depth
+	}
+}
+
+// WithExpirationDuration sets the default amount of time after
+// a broadcast is enqueued that its transaction must appear
+// on-chain before it is considered expired. A value of 0
+// disables time-based expiration.
+func WithExpirationDuration(duration time.Duration) BroadcastStorageOption {
+	return func(b *BroadcastStorage) {
+		b.expirationDuration = duration
+	}
+}
+
+// WithStatusRetention deletes the BroadcastStatus of a broadcast
+// (and the stored broadcast used by RequeueBroadcast) once duration
+// has elapsed after it is confirmed, fails, or expires. If not
+// provided, DefaultStatusRetention is used. A value of 0 means
+// these records are retained until ClearBroadcast is called.
+func WithStatusRetention(duration time.Duration) BroadcastStorageOption {
+	return func(b *BroadcastStorage) {
+		b.statusRetention = duration
+	}
+}
+
+// WithBroadcastBackoff configures an exponential backoff policy
+// for broadcasts rejected by the Rosetta implementation. After
+// the nth rejection, a broadcast is not attempted again for
+// initialDelay * multiplier^(n-1) blocks (capped at maxDelay,
+// if maxDelay is not 0). Once a broadcast is rejected maxAttempts
+// times (if maxAttempts is not 0), it is considered failed.
+func WithBroadcastBackoff(
+	initialDelay int64,
+	multiplier float64,
+	maxDelay int64,
+	maxAttempts int,
+) BroadcastStorageOption {
+	return func(b *BroadcastStorage) {
+		b.backoffInitialDelay = initialDelay
+		b.backoffMultiplier = multiplier
+		b.backoffMaxDelay = maxDelay
+		b.backoffMaxAttempts = maxAttempts
+	}
+}
+
+// BroadcastOption is used to overwrite the BroadcastStorage
+// defaults for a single broadcast.
+type BroadcastOption func(b *Broadcast)
+
+// WithBroadcastExpirationDepth overrides the default
+// expiration depth of a broadcast.
+func WithBroadcastExpirationDepth(depth int64) BroadcastOption {
+	return func(b *Broadcast) {
+		b.ExpirationDepth = depth
+	}
+}
+
+// WithBroadcastExpirationDuration overrides the default
+// expiration duration of a broadcast.
+func WithBroadcastExpirationDuration(duration time.Duration) BroadcastOption {
+	return func(b *Broadcast) {
+		b.expirationDuration = duration
+	}
+}
+
+// WithBroadcastPrerequisites sets transactions that must be
+// seen on-chain before a broadcast is submitted. If any
+// prerequisite broadcast fails, the broadcast also fails.
+func WithBroadcastPrerequisites(
+	prerequisites ...*types.TransactionIdentifier,
+) BroadcastOption {
+	return func(b *Broadcast) {
+		b.Prerequisites = prerequisites
+	}
+}
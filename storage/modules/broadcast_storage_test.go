@@ -19,12 +19,14 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/neilotoole/errgroup"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	mocks "github.com/coinbase/rosetta-sdk-go/mocks/storage/modules"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
@@ -76,6 +78,20 @@ func opFiller(sender string, opNumber int) []*types.Operation {
 	return ops
 }
 
+// broadcastHandlerWithCause is a BroadcastStorageHandler
+// that also implements BroadcastFailureHandler.
+type broadcastHandlerWithCause struct {
+	*mocks.BroadcastStorageHandler
+	*mocks.BroadcastFailureHandler
+}
+
+// broadcastHandlerWithExpiration is a BroadcastStorageHandler
+// that also implements BroadcastExpirationHandler.
+type broadcastHandlerWithExpiration struct {
+	*mocks.BroadcastStorageHandler
+	*mocks.BroadcastExpirationHandler
+}
+
 func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 	ctx := context.Background()
 
@@ -83,7 +99,7 @@ func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -671,7 +687,7 @@ func TestBroadcastStorageBroadcastFailure(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -914,7 +930,7 @@ func TestBroadcastStorageBehindTip(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -1162,7 +1178,7 @@ func TestBroadcastStorageClearBroadcasts(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -1320,3 +1336,1750 @@ func TestBroadcastStorageClearBroadcasts(t *testing.T) {
 		mockHandler.AssertExpectations(t)
 	})
 }
+
+func TestBroadcastStorageExpiration(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBroadcastStorage(
+		database,
+		100, // ensure broadcasts do not become stale
+		broadcastLimit,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+		WithExpirationDepth(3),
+	)
+	currentTime := time.Unix(1000, 0)
+	storage.now = func() time.Time {
+		return currentTime
+	}
+
+	send1 := opFiller("addr 1", 11)
+	send2 := opFiller("addr 2", 13)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+
+	t.Run("broadcast", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		storage.Initialize(mockHelper, mockHandler)
+
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		err := storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 1",
+			network,
+			send1,
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			"payload 1",
+			confirmationDepth,
+		)
+		assert.NoError(t, err)
+
+		err = storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 2",
+			network,
+			send2,
+			&types.TransactionIdentifier{Hash: "tx 2"},
+			"payload 2",
+			confirmationDepth,
+			WithBroadcastExpirationDepth(0),
+			WithBroadcastExpirationDuration(time.Minute),
+		)
+		assert.NoError(t, err)
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*Broadcast{
+			{
+				Identifier:            "broadcast 1",
+				NetworkIdentifier:     network,
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+				Intent:                send1,
+				Payload:               "payload 1",
+				ConfirmationDepth:     confirmationDepth,
+				ExpirationDepth:       3,
+			},
+			{
+				Identifier:            "broadcast 2",
+				NetworkIdentifier:     network,
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"},
+				Intent:                send2,
+				Payload:               "payload 2",
+				ConfirmationDepth:     confirmationDepth,
+				ExpirationTime:        1060000,
+			},
+		}, broadcasts)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+
+	blocks := blockFiller(0, 10)
+	t.Run("expire by depth", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		mockExpirationHandler := &mocks.BroadcastExpirationHandler{}
+		storage.Initialize(
+			mockHelper,
+			&broadcastHandlerWithExpiration{mockHandler, mockExpirationHandler},
+		)
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil)
+		mockHelper.On(
+			"BroadcastTransaction",
+			ctx,
+			network,
+			"payload 1",
+		).Return(
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			nil,
+		).Once()
+		mockHelper.On(
+			"BroadcastTransaction",
+			ctx,
+			network,
+			"payload 2",
+		).Return(
+			&types.TransactionIdentifier{Hash: "tx 2"},
+			nil,
+		).Once()
+		mockHelper.On(
+			"FindTransaction",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(
+			nil,
+			nil,
+			nil,
+		)
+		mockExpirationHandler.On(
+			"BroadcastExpired",
+			mock.Anything,
+			mock.Anything,
+			"broadcast 1",
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			send1,
+		).Return(
+			nil,
+		).Once()
+
+		for _, block := range blocks[:6] {
+			mockHelper.On("CurrentBlockIdentifier", ctx).Return(block.BlockIdentifier, nil).Once()
+			txn := storage.db.Transaction(ctx)
+			g, gctx := errgroup.WithContext(ctx)
+			commitWorker, err := storage.AddingBlock(gctx, g, block, txn)
+			assert.NoError(t, err)
+			assert.NoError(t, g.Wait())
+			assert.NoError(t, txn.Commit(ctx))
+			assert.NoError(t, commitWorker(ctx))
+		}
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*Broadcast{
+			{
+				Identifier:            "broadcast 2",
+				NetworkIdentifier:     network,
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"},
+				Intent:                send2,
+				Payload:               "payload 2",
+				LastBroadcast:         blocks[0].BlockIdentifier,
+				Broadcasts:            1,
+				ConfirmationDepth:     confirmationDepth,
+				ExpirationTime:        1060000,
+			},
+		}, broadcasts)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+		mockExpirationHandler.AssertExpectations(t)
+	})
+
+	t.Run("expire by time", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		storage.Initialize(mockHelper, mockHandler)
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil)
+		mockHelper.On(
+			"FindTransaction",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(
+			nil,
+			nil,
+			nil,
+		)
+		// Handlers that do not implement BroadcastExpirationHandler
+		// are notified of expired broadcasts as failures.
+		mockHandler.On(
+			"BroadcastFailed",
+			mock.Anything,
+			mock.Anything,
+			"broadcast 2",
+			&types.TransactionIdentifier{Hash: "tx 2"},
+			send2,
+		).Return(
+			nil,
+		).Once()
+
+		currentTime = currentTime.Add(2 * time.Minute)
+		for _, block := range blocks[6:] {
+			mockHelper.On("CurrentBlockIdentifier", ctx).Return(block.BlockIdentifier, nil).Once()
+			txn := storage.db.Transaction(ctx)
+			g, gctx := errgroup.WithContext(ctx)
+			commitWorker, err := storage.AddingBlock(gctx, g, block, txn)
+			assert.NoError(t, err)
+			assert.NoError(t, g.Wait())
+			assert.NoError(t, txn.Commit(ctx))
+			assert.NoError(t, commitWorker(ctx))
+		}
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, broadcasts, 0)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+}
+
+func TestBroadcastStorageExpireBroadcasts(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBroadcastStorage(
+		database,
+		100, // ensure broadcasts do not become stale
+		broadcastLimit,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+	)
+	currentTime := time.Unix(1000, 0)
+	storage.now = func() time.Time {
+		return currentTime
+	}
+
+	send1 := opFiller("addr 1", 11)
+	send2 := opFiller("addr 2", 13)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+
+	t.Run("broadcast and flag", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		storage.Initialize(mockHelper, mockHandler)
+
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		err := storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 1",
+			network,
+			send1,
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			"payload 1",
+			confirmationDepth,
+		)
+		assert.NoError(t, err)
+
+		err = storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 2",
+			network,
+			send2,
+			&types.TransactionIdentifier{Hash: "tx 2"},
+			"payload 2",
+			confirmationDepth,
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, storage.ExpireBroadcasts(ctx, dbTx, "broadcast 1"))
+		assert.NoError(t, storage.ExpireBroadcasts(ctx, dbTx, "unknown"))
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*Broadcast{
+			{
+				Identifier:            "broadcast 1",
+				NetworkIdentifier:     network,
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+				Intent:                send1,
+				Payload:               "payload 1",
+				ConfirmationDepth:     confirmationDepth,
+				ExpirationTime:        1000000,
+			},
+			{
+				Identifier:            "broadcast 2",
+				NetworkIdentifier:     network,
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"},
+				Intent:                send2,
+				Payload:               "payload 2",
+				ConfirmationDepth:     confirmationDepth,
+			},
+		}, broadcasts)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("expire on next block", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		mockExpirationHandler := &mocks.BroadcastExpirationHandler{}
+		storage.Initialize(
+			mockHelper,
+			&broadcastHandlerWithExpiration{mockHandler, mockExpirationHandler},
+		)
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil)
+		mockHelper.On(
+			"BroadcastTransaction",
+			ctx,
+			network,
+			"payload 2",
+		).Return(
+			&types.TransactionIdentifier{Hash: "tx 2"},
+			nil,
+		).Once()
+		mockExpirationHandler.On(
+			"BroadcastExpired",
+			mock.Anything,
+			mock.Anything,
+			"broadcast 1",
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			send1,
+		).Return(
+			nil,
+		).Once()
+
+		block := blockFiller(0, 1)[0]
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(block.BlockIdentifier, nil).Once()
+		txn := storage.db.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		commitWorker, err := storage.AddingBlock(gctx, g, block, txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+		assert.NoError(t, commitWorker(ctx))
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*Broadcast{
+			{
+				Identifier:            "broadcast 2",
+				NetworkIdentifier:     network,
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"},
+				Intent:                send2,
+				Payload:               "payload 2",
+				LastBroadcast:         block.BlockIdentifier,
+				Broadcasts:            1,
+				ConfirmationDepth:     confirmationDepth,
+			},
+		}, broadcasts)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+		mockExpirationHandler.AssertExpectations(t)
+	})
+}
+
+func TestBroadcastStorageConfirmationDepth(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBroadcastStorage(
+		database,
+		staleDepth,
+		broadcastLimit,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+		WithConfirmationDepth(4),
+	)
+
+	send1 := opFiller("addr 1", 11)
+	send2 := opFiller("addr 2", 13)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+
+	t.Run("broadcast", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		storage.Initialize(mockHelper, mockHandler)
+
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		err := storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 1",
+			network,
+			send1,
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			"payload 1",
+			1,
+		)
+		assert.NoError(t, err)
+
+		// Falls back to the storage-wide confirmation depth
+		err = storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 2",
+			network,
+			send2,
+			&types.TransactionIdentifier{Hash: "tx 2"},
+			"payload 2",
+			0,
+		)
+		assert.NoError(t, err)
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*Broadcast{
+			{
+				Identifier:            "broadcast 1",
+				NetworkIdentifier:     network,
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+				Intent:                send1,
+				Payload:               "payload 1",
+				ConfirmationDepth:     1,
+			},
+			{
+				Identifier:            "broadcast 2",
+				NetworkIdentifier:     network,
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"},
+				Intent:                send2,
+				Payload:               "payload 2",
+				ConfirmationDepth:     4,
+			},
+		}, broadcasts)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+
+	blocks := blockFiller(0, 6)
+	tx1 := &types.Transaction{TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"}}
+	tx2 := &types.Transaction{TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"}}
+	t.Run("confirm at different depths", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		storage.Initialize(mockHelper, mockHandler)
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil)
+		mockHelper.On(
+			"BroadcastTransaction",
+			ctx,
+			network,
+			"payload 1",
+		).Return(
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			nil,
+		).Once()
+		mockHelper.On(
+			"BroadcastTransaction",
+			ctx,
+			network,
+			"payload 2",
+		).Return(
+			&types.TransactionIdentifier{Hash: "tx 2"},
+			nil,
+		).Once()
+
+		for i, block := range blocks {
+			mockHelper.On("CurrentBlockIdentifier", ctx).Return(block.BlockIdentifier, nil).Once()
+			txn := storage.db.Transaction(ctx)
+			g, gctx := errgroup.WithContext(ctx)
+
+			// Both transactions are included in block 1
+			switch {
+			case i == 1:
+				mockHelper.On(
+					"FindTransaction",
+					gctx,
+					&types.TransactionIdentifier{Hash: "tx 1"},
+					txn,
+				).Return(
+					blocks[1].BlockIdentifier,
+					tx1,
+					nil,
+				).Once()
+				mockHelper.On(
+					"FindTransaction",
+					gctx,
+					&types.TransactionIdentifier{Hash: "tx 2"},
+					txn,
+				).Return(
+					blocks[1].BlockIdentifier,
+					tx2,
+					nil,
+				).Once()
+				mockHandler.On(
+					"TransactionConfirmed",
+					gctx,
+					txn,
+					"broadcast 1",
+					blocks[1].BlockIdentifier,
+					tx1,
+					send1,
+				).Return(
+					nil,
+				).Once()
+			case i > 1 && i <= 4:
+				// "broadcast 2" uses the default confirmation
+				// depth of 4, so it is only confirmed (and stops
+				// being looked up) at block 4.
+				mockHelper.On(
+					"FindTransaction",
+					gctx,
+					&types.TransactionIdentifier{Hash: "tx 2"},
+					txn,
+				).Return(
+					blocks[1].BlockIdentifier,
+					tx2,
+					nil,
+				).Once()
+			}
+
+			if i == 4 {
+				mockHandler.On(
+					"TransactionConfirmed",
+					gctx,
+					txn,
+					"broadcast 2",
+					blocks[1].BlockIdentifier,
+					tx2,
+					send2,
+				).Return(
+					nil,
+				).Once()
+			}
+
+			commitWorker, err := storage.AddingBlock(gctx, g, block, txn)
+			assert.NoError(t, err)
+			assert.NoError(t, g.Wait())
+			assert.NoError(t, txn.Commit(ctx))
+			assert.NoError(t, commitWorker(ctx))
+
+			broadcasts, err := storage.GetAllBroadcasts(ctx)
+			assert.NoError(t, err)
+			switch {
+			case i < 1:
+				assert.Len(t, broadcasts, 2)
+			case i < 4:
+				assert.Len(t, broadcasts, 1)
+				assert.Equal(t, "broadcast 2", broadcasts[0].Identifier)
+			default:
+				assert.Len(t, broadcasts, 0)
+			}
+		}
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+}
+
+func TestBroadcastStorageStatus(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBroadcastStorage(
+		database,
+		100, // ensure broadcasts do not become stale
+		broadcastLimit,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+	)
+	assert.Equal(t, DefaultStatusRetention, storage.statusRetention)
+	storage.now = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+
+	send1 := opFiller("addr 1", 11)
+	send2 := opFiller("addr 2", 13)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	tx1 := &types.Transaction{TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"}}
+
+	t.Run("unknown broadcast", func(t *testing.T) {
+		status, err := storage.GetBroadcastStatus(ctx, &types.TransactionIdentifier{Hash: "tx 1"})
+		assert.True(t, errors.Is(err, storageErrs.ErrBroadcastNotFound))
+		assert.Nil(t, status)
+
+		statuses, err := storage.ListBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 0)
+	})
+
+	t.Run("broadcast", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 1",
+			network,
+			send1,
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			"payload 1",
+			3,
+		))
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 2",
+			network,
+			send2,
+			&types.TransactionIdentifier{Hash: "tx 2"},
+			"payload 2",
+			3,
+		))
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		status, err := storage.GetBroadcastStatus(ctx, &types.TransactionIdentifier{Hash: "tx 1"})
+		assert.NoError(t, err)
+		assert.Equal(t, &BroadcastStatus{
+			Identifier:            "broadcast 1",
+			TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+			State:                 BroadcastStatePending,
+		}, status)
+	})
+
+	blocks := blockFiller(0, 4)
+	orphanedBlock := &types.Block{
+		BlockIdentifier:       blocks[1].BlockIdentifier,
+		ParentBlockIdentifier: blocks[1].ParentBlockIdentifier,
+		Transactions:          []*types.Transaction{tx1},
+	}
+	removeBlock := func() {
+		txn := storage.db.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		_, err := storage.RemovingBlock(gctx, g, orphanedBlock, txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+	}
+
+	for i, block := range blocks {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		storage.Initialize(mockHelper, mockHandler)
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil)
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(block.BlockIdentifier, nil)
+
+		txn := storage.db.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		if i == 0 {
+			mockHelper.On(
+				"BroadcastTransaction",
+				ctx,
+				network,
+				"payload 1",
+			).Return(
+				&types.TransactionIdentifier{Hash: "tx 1"},
+				nil,
+			).Once()
+			mockHelper.On(
+				"BroadcastTransaction",
+				ctx,
+				network,
+				"payload 2",
+			).Return(
+				&types.TransactionIdentifier{Hash: "tx 2"},
+				nil,
+			).Once()
+		} else {
+			mockHelper.On(
+				"FindTransaction",
+				gctx,
+				&types.TransactionIdentifier{Hash: "tx 1"},
+				txn,
+			).Return(
+				blocks[1].BlockIdentifier,
+				tx1,
+				nil,
+			).Once()
+			mockHelper.On(
+				"FindTransaction",
+				gctx,
+				&types.TransactionIdentifier{Hash: "tx 2"},
+				txn,
+			).Return(
+				nil,
+				nil,
+				nil,
+			).Once()
+		}
+		if i == 3 {
+			mockHandler.On(
+				"TransactionConfirmed",
+				gctx,
+				txn,
+				"broadcast 1",
+				blocks[1].BlockIdentifier,
+				tx1,
+				send1,
+			).Return(
+				nil,
+			).Once()
+		}
+
+		commitWorker, err := storage.AddingBlock(gctx, g, block, txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+		assert.NoError(t, commitWorker(ctx))
+
+		if i == 2 {
+			status, err := storage.GetBroadcastStatus(
+				ctx,
+				&types.TransactionIdentifier{Hash: "tx 1"},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, BroadcastStateSeen, status.State)
+			assert.Equal(t, blocks[1].BlockIdentifier, status.Block)
+			assert.Equal(t, int64(2), status.Depth)
+
+			// Broadcasts seen in an orphaned block are pending again
+			removeBlock()
+			status, err = storage.GetBroadcastStatus(
+				ctx,
+				&types.TransactionIdentifier{Hash: "tx 1"},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, BroadcastStatePending, status.State)
+			assert.Nil(t, status.Block)
+			assert.Equal(t, int64(0), status.Depth)
+			assert.Equal(t, 1, status.Broadcasts)
+		}
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	}
+
+	t.Run("confirmed and pending statuses", func(t *testing.T) {
+		status, err := storage.GetBroadcastStatus(ctx, &types.TransactionIdentifier{Hash: "tx 1"})
+		assert.NoError(t, err)
+		assert.Equal(t, &BroadcastStatus{
+			Identifier:            "broadcast 1",
+			TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+			State:                 BroadcastStateConfirmed,
+			Block:                 blocks[1].BlockIdentifier,
+			Depth:                 3,
+			LastBroadcastTime:     1000000,
+			Broadcasts:            1,
+		}, status)
+
+		statuses, err := storage.ListBroadcasts(ctx, BroadcastStatePending, BroadcastStateSeen)
+		assert.NoError(t, err)
+		assert.Equal(t, []*BroadcastStatus{
+			{
+				Identifier:            "broadcast 2",
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"},
+				State:                 BroadcastStatePending,
+				LastBroadcastTime:     1000000,
+				Broadcasts:            1,
+			},
+		}, statuses)
+
+		statuses, err = storage.ListBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 2)
+	})
+
+	t.Run("confirmed status orphaned", func(t *testing.T) {
+		removeBlock()
+
+		status, err := storage.GetBroadcastStatus(ctx, &types.TransactionIdentifier{Hash: "tx 1"})
+		assert.True(t, errors.Is(err, storageErrs.ErrBroadcastNotFound))
+		assert.Nil(t, status)
+
+		statuses, err := storage.ListBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 1)
+		assert.Equal(t, "broadcast 2", statuses[0].Identifier)
+	})
+}
+
+func TestBroadcastStorageBackoff(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBroadcastStorage(
+		database,
+		100, // ensure broadcasts do not become stale
+		10,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+		WithBroadcastBackoff(1, 2, 4, 4),
+	)
+
+	send1 := opFiller("addr 1", 11)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+
+	t.Run("broadcast", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 1",
+			network,
+			send1,
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			"payload 1",
+			confirmationDepth,
+		))
+		assert.NoError(t, dbTx.Commit(ctx))
+	})
+
+	t.Run("repeated rejections", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		mockFailureHandler := &mocks.BroadcastFailureHandler{}
+		storage.Initialize(
+			mockHelper,
+			&broadcastHandlerWithCause{mockHandler, mockFailureHandler},
+		)
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil)
+
+		// Attempts should be spaced 1, 2, and 4 blocks apart
+		attempts := map[int]struct{}{0: {}, 1: {}, 3: {}, 7: {}}
+		blocks := blockFiller(0, 10)
+		for i, block := range blocks {
+			mockHelper.On("CurrentBlockIdentifier", ctx).Return(block.BlockIdentifier, nil).Once()
+			if _, ok := attempts[i]; ok {
+				mockHelper.On(
+					"BroadcastTransaction",
+					ctx,
+					network,
+					"payload 1",
+				).Return(
+					nil,
+					fmt.Errorf("rejected %d", i),
+				).Once()
+			}
+
+			if i == 7 {
+				mockFailureHandler.On(
+					"BroadcastFailedWithCause",
+					ctx,
+					mock.Anything,
+					"broadcast 1",
+					&types.TransactionIdentifier{Hash: "tx 1"},
+					send1,
+					mock.MatchedBy(func(err error) bool {
+						return errors.Is(err, storageErrs.ErrBroadcastAttemptsExceeded) &&
+							err.Error() == fmt.Sprintf(
+								"%s: rejected 0; rejected 1; rejected 3; rejected 7",
+								storageErrs.ErrBroadcastAttemptsExceeded.Error(),
+							)
+					}),
+				).Return(
+					nil,
+				).Once()
+			}
+
+			txn := storage.db.Transaction(ctx)
+			g, gctx := errgroup.WithContext(ctx)
+			commitWorker, err := storage.AddingBlock(gctx, g, block, txn)
+			assert.NoError(t, err)
+			assert.NoError(t, g.Wait())
+			assert.NoError(t, txn.Commit(ctx))
+			assert.NoError(t, commitWorker(ctx))
+
+			if i == 3 {
+				broadcasts, err := storage.GetAllBroadcasts(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, []*Broadcast{
+					{
+						Identifier:            "broadcast 1",
+						NetworkIdentifier:     network,
+						TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+						Intent:                send1,
+						Payload:               "payload 1",
+						Broadcasts:            3,
+						ConfirmationDepth:     confirmationDepth,
+						NextBroadcast:         7,
+						SubmissionErrors: []string{
+							"rejected 0",
+							"rejected 1",
+							"rejected 3",
+						},
+					},
+				}, broadcasts)
+			}
+		}
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, broadcasts, 0)
+
+		status, err := storage.GetBroadcastStatus(ctx, &types.TransactionIdentifier{Hash: "tx 1"})
+		assert.NoError(t, err)
+		assert.Equal(t, BroadcastStateFailed, status.State)
+		assert.Equal(t, 4, status.Broadcasts)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+		mockFailureHandler.AssertExpectations(t)
+	})
+}
+
+func TestBroadcastStoragePrerequisites(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBroadcastStorage(
+		database,
+		100, // ensure broadcasts do not become stale
+		10,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+	)
+
+	send1 := opFiller("addr 1", 11)
+	send2 := opFiller("addr 2", 13)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	tx1 := &types.TransactionIdentifier{Hash: "tx 1"}
+	tx2 := &types.TransactionIdentifier{Hash: "tx 2"}
+
+	blocks := blockFiller(0, 2)
+	blocks[1].Transactions = []*types.Transaction{{TransactionIdentifier: tx1}}
+
+	t.Run("broadcast", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 1",
+			network,
+			send1,
+			tx1,
+			"payload 1",
+			confirmationDepth,
+		))
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 2",
+			network,
+			send2,
+			tx2,
+			"payload 2",
+			confirmationDepth,
+			WithBroadcastPrerequisites(tx1),
+		))
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		status, err := storage.GetBroadcastStatus(ctx, tx2)
+		assert.NoError(t, err)
+		assert.Equal(t, BroadcastStateWaiting, status.State)
+	})
+
+	t.Run("prerequisite not seen", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		storage.Initialize(mockHelper, mockHandler)
+
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil).Once()
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(blocks[0].BlockIdentifier, nil).Once()
+		mockHelper.On("BroadcastTransaction", ctx, network, "payload 1").Return(tx1, nil).Once()
+		mockHelper.On(
+			"FindTransaction",
+			ctx,
+			tx1,
+			mock.Anything,
+		).Return(
+			nil,
+			nil,
+			nil,
+		).Once()
+
+		txn := storage.db.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		commitWorker, err := storage.AddingBlock(gctx, g, blocks[0], txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+		assert.NoError(t, commitWorker(ctx))
+
+		status, err := storage.GetBroadcastStatus(ctx, tx2)
+		assert.NoError(t, err)
+		assert.Equal(t, BroadcastStateWaiting, status.State)
+		assert.Equal(t, 0, status.Broadcasts)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("prerequisite seen", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		storage.Initialize(mockHelper, mockHandler)
+
+		txn := storage.db.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil).Once()
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(blocks[1].BlockIdentifier, nil).Once()
+		mockHelper.On(
+			"FindTransaction",
+			gctx,
+			tx1,
+			txn,
+		).Return(
+			blocks[1].BlockIdentifier,
+			blocks[1].Transactions[0],
+			nil,
+		).Once()
+		mockHelper.On(
+			"FindTransaction",
+			ctx,
+			tx1,
+			mock.Anything,
+		).Return(
+			blocks[1].BlockIdentifier,
+			blocks[1].Transactions[0],
+			nil,
+		).Once()
+		mockHelper.On("BroadcastTransaction", ctx, network, "payload 2").Return(tx2, nil).Once()
+
+		commitWorker, err := storage.AddingBlock(gctx, g, blocks[1], txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+		assert.NoError(t, commitWorker(ctx))
+
+		status, err := storage.GetBroadcastStatus(ctx, tx2)
+		assert.NoError(t, err)
+		assert.Equal(t, BroadcastStatePending, status.State)
+		assert.Equal(t, 1, status.Broadcasts)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("prerequisite orphaned", func(t *testing.T) {
+		txn := storage.db.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		_, err := storage.RemovingBlock(gctx, g, blocks[1], txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, broadcasts, 2)
+		assert.Equal(t, tx2, broadcasts[1].TransactionIdentifier)
+		assert.Nil(t, broadcasts[1].LastBroadcast)
+
+		status, err := storage.GetBroadcastStatus(ctx, tx2)
+		assert.NoError(t, err)
+		assert.Equal(t, BroadcastStateWaiting, status.State)
+	})
+}
+
+func TestBroadcastStoragePrerequisiteFailure(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBroadcastStorage(
+		database,
+		100, // ensure broadcasts do not become stale
+		10,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+		WithBroadcastBackoff(1, 1, 0, 1),
+	)
+
+	send1 := opFiller("addr 1", 11)
+	send2 := opFiller("addr 2", 13)
+	send3 := opFiller("addr 3", 15)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	tx1 := &types.TransactionIdentifier{Hash: "tx 1"}
+	tx2 := &types.TransactionIdentifier{Hash: "tx 2"}
+	tx3 := &types.TransactionIdentifier{Hash: "tx 3"}
+
+	t.Run("broadcast", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 1",
+			network,
+			send1,
+			tx1,
+			"payload 1",
+			confirmationDepth,
+		))
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 2",
+			network,
+			send2,
+			tx2,
+			"payload 2",
+			confirmationDepth,
+			WithBroadcastPrerequisites(tx1),
+		))
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 3",
+			network,
+			send3,
+			tx3,
+			"payload 3",
+			confirmationDepth,
+			WithBroadcastPrerequisites(tx2),
+		))
+		assert.NoError(t, dbTx.Commit(ctx))
+	})
+
+	t.Run("prerequisite rejected", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		mockFailureHandler := &mocks.BroadcastFailureHandler{}
+		storage.Initialize(
+			mockHelper,
+			&broadcastHandlerWithCause{mockHandler, mockFailureHandler},
+		)
+
+		block := blockFiller(0, 1)[0]
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil).Once()
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(block.BlockIdentifier, nil).Once()
+		mockHelper.On(
+			"BroadcastTransaction",
+			ctx,
+			network,
+			"payload 1",
+		).Return(
+			nil,
+			errors.New("rejected"),
+		).Once()
+		mockHelper.On(
+			"FindTransaction",
+			ctx,
+			tx1,
+			mock.Anything,
+		).Return(
+			nil,
+			nil,
+			nil,
+		).Once()
+		mockHelper.On(
+			"FindTransaction",
+			ctx,
+			tx2,
+			mock.Anything,
+		).Return(
+			nil,
+			nil,
+			nil,
+		).Once()
+		mockFailureHandler.On(
+			"BroadcastFailedWithCause",
+			ctx,
+			mock.Anything,
+			"broadcast 1",
+			tx1,
+			send1,
+			mock.MatchedBy(func(err error) bool {
+				return errors.Is(err, storageErrs.ErrBroadcastAttemptsExceeded)
+			}),
+		).Return(
+			nil,
+		).Once()
+		mockFailureHandler.On(
+			"BroadcastFailedWithCause",
+			ctx,
+			mock.Anything,
+			"broadcast 2",
+			tx2,
+			send2,
+			mock.MatchedBy(func(err error) bool {
+				return errors.Is(err, storageErrs.ErrBroadcastPrerequisiteFailed)
+			}),
+		).Return(
+			nil,
+		).Once()
+		mockFailureHandler.On(
+			"BroadcastFailedWithCause",
+			ctx,
+			mock.Anything,
+			"broadcast 3",
+			tx3,
+			send3,
+			mock.MatchedBy(func(err error) bool {
+				return errors.Is(err, storageErrs.ErrBroadcastPrerequisiteFailed)
+			}),
+		).Return(
+			nil,
+		).Once()
+
+		txn := storage.db.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		commitWorker, err := storage.AddingBlock(gctx, g, block, txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+		assert.NoError(t, commitWorker(ctx))
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, broadcasts, 0)
+
+		for _, tx := range []*types.TransactionIdentifier{tx2, tx3} {
+			status, err := storage.GetBroadcastStatus(ctx, tx)
+			assert.NoError(t, err)
+			assert.Equal(t, BroadcastStateFailed, status.State)
+			assert.Equal(t, 0, status.Broadcasts)
+		}
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+		mockFailureHandler.AssertExpectations(t)
+	})
+}
+
+func TestBroadcastStorageRequeueAndClear(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBroadcastStorage(
+		database,
+		100, // ensure broadcasts do not become stale
+		10,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+		WithBroadcastBackoff(1, 1, 0, 1),
+	)
+
+	send1 := opFiller("addr 1", 11)
+	send2 := opFiller("addr 2", 13)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	tx1 := &types.TransactionIdentifier{Hash: "tx 1"}
+	tx2 := &types.TransactionIdentifier{Hash: "tx 2"}
+	blocks := blockFiller(0, 2)
+	confirmedTx := &types.Transaction{TransactionIdentifier: tx2}
+
+	t.Run("unknown broadcast", func(t *testing.T) {
+		err := storage.RequeueBroadcast(ctx, tx1)
+		assert.True(t, errors.Is(err, storageErrs.ErrBroadcastNotFound))
+
+		err = storage.ClearBroadcast(ctx, tx1)
+		assert.True(t, errors.Is(err, storageErrs.ErrBroadcastNotFound))
+	})
+
+	t.Run("broadcast", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 1",
+			network,
+			send1,
+			tx1,
+			"payload 1",
+			confirmationDepth,
+		))
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 2",
+			network,
+			send2,
+			tx2,
+			"payload 2",
+			1,
+		))
+		assert.NoError(t, dbTx.Commit(ctx))
+	})
+
+	t.Run("fail and confirm", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		mockFailureHandler := &mocks.BroadcastFailureHandler{}
+		storage.Initialize(
+			mockHelper,
+			&broadcastHandlerWithCause{mockHandler, mockFailureHandler},
+		)
+
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil).Twice()
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(blocks[0].BlockIdentifier, nil).Once()
+		mockHelper.On(
+			"BroadcastTransaction",
+			ctx,
+			network,
+			"payload 1",
+		).Return(
+			nil,
+			errors.New("rejected"),
+		).Once()
+		mockHelper.On("BroadcastTransaction", ctx, network, "payload 2").Return(tx2, nil).Once()
+		mockFailureHandler.On(
+			"BroadcastFailedWithCause",
+			ctx,
+			mock.Anything,
+			"broadcast 1",
+			tx1,
+			send1,
+			mock.MatchedBy(func(err error) bool {
+				return errors.Is(err, storageErrs.ErrBroadcastAttemptsExceeded)
+			}),
+		).Return(
+			nil,
+		).Once()
+
+		txn := storage.db.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		commitWorker, err := storage.AddingBlock(gctx, g, blocks[0], txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+		assert.NoError(t, commitWorker(ctx))
+
+		txn = storage.db.Transaction(ctx)
+		g, gctx = errgroup.WithContext(ctx)
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(blocks[1].BlockIdentifier, nil).Once()
+		mockHelper.On(
+			"FindTransaction",
+			gctx,
+			tx2,
+			txn,
+		).Return(
+			blocks[1].BlockIdentifier,
+			confirmedTx,
+			nil,
+		).Once()
+		mockHandler.On(
+			"TransactionConfirmed",
+			gctx,
+			txn,
+			"broadcast 2",
+			blocks[1].BlockIdentifier,
+			confirmedTx,
+			send2,
+		).Return(
+			nil,
+		).Once()
+		commitWorker, err = storage.AddingBlock(gctx, g, blocks[1], txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+		assert.NoError(t, commitWorker(ctx))
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, broadcasts, 0)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+		mockFailureHandler.AssertExpectations(t)
+	})
+
+	t.Run("requeue confirmed broadcast", func(t *testing.T) {
+		err := storage.RequeueBroadcast(ctx, tx2)
+		assert.True(t, errors.Is(err, storageErrs.ErrBroadcastRequeueConfirmed))
+	})
+
+	t.Run("requeue failed broadcast", func(t *testing.T) {
+		assert.NoError(t, storage.RequeueBroadcast(ctx, tx1))
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*Broadcast{
+			{
+				Identifier:            "broadcast 1",
+				NetworkIdentifier:     network,
+				TransactionIdentifier: tx1,
+				Intent:                send1,
+				Payload:               "payload 1",
+				ConfirmationDepth:     confirmationDepth,
+			},
+		}, broadcasts)
+
+		status, err := storage.GetBroadcastStatus(ctx, tx1)
+		assert.NoError(t, err)
+		assert.Equal(t, BroadcastStatePending, status.State)
+		assert.Equal(t, "", status.Reason)
+		assert.Equal(t, 0, status.Broadcasts)
+	})
+
+	t.Run("clear active broadcast", func(t *testing.T) {
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		mockFailureHandler := &mocks.BroadcastFailureHandler{}
+		storage.Initialize(
+			&mocks.BroadcastStorageHelper{},
+			&broadcastHandlerWithCause{mockHandler, mockFailureHandler},
+		)
+		mockFailureHandler.On(
+			"BroadcastFailedWithCause",
+			ctx,
+			mock.Anything,
+			"broadcast 1",
+			tx1,
+			send1,
+			storageErrs.ErrBroadcastCleared,
+		).Return(
+			nil,
+		).Once()
+
+		assert.NoError(t, storage.ClearBroadcast(ctx, tx1))
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, broadcasts, 0)
+
+		_, err = storage.GetBroadcastStatus(ctx, tx1)
+		assert.True(t, errors.Is(err, storageErrs.ErrBroadcastNotFound))
+
+		err = storage.RequeueBroadcast(ctx, tx1)
+		assert.True(t, errors.Is(err, storageErrs.ErrBroadcastNotFound))
+
+		mockHandler.AssertExpectations(t)
+		mockFailureHandler.AssertExpectations(t)
+	})
+
+	t.Run("clear confirmed broadcast", func(t *testing.T) {
+		assert.NoError(t, storage.ClearBroadcast(ctx, tx2))
+
+		statuses, err := storage.ListBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 0)
+	})
+}
+
+func TestBroadcastStorageRequeueAndClearConcurrent(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBroadcastStorage(
+		database,
+		1, // ensure broadcasts become stale at each block
+		100,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+	)
+
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	blocks := blockFiller(0, 20)
+	mockHelper := &mocks.BroadcastStorageHelper{}
+	mockHandler := &mocks.BroadcastStorageHandler{}
+	mockFailureHandler := &mocks.BroadcastFailureHandler{}
+	storage.Initialize(
+		mockHelper,
+		&broadcastHandlerWithCause{mockHandler, mockFailureHandler},
+	)
+
+	mockHelper.On("AtTip", mock.Anything, mock.Anything).Return(true, nil)
+	mockHelper.On("CurrentBlockIdentifier", mock.Anything).Return(blocks[0].BlockIdentifier, nil)
+	mockHelper.On(
+		"FindTransaction",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(
+		nil,
+		nil,
+		nil,
+	)
+	mockHandler.On(
+		"TransactionStale",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(
+		nil,
+	)
+	mockFailureHandler.On(
+		"BroadcastFailedWithCause",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		storageErrs.ErrBroadcastCleared,
+	).Return(
+		nil,
+	)
+
+	txs := make([]*types.TransactionIdentifier, 10)
+	dbTx := database.Transaction(ctx)
+	for i := range txs {
+		txs[i] = &types.TransactionIdentifier{Hash: fmt.Sprintf("tx %d", i)}
+		payload := fmt.Sprintf("payload %d", i)
+		mockHelper.On(
+			"BroadcastTransaction",
+			mock.Anything,
+			network,
+			payload,
+		).Return(
+			txs[i],
+			nil,
+		)
+
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			fmt.Sprintf("broadcast %d", i),
+			network,
+			opFiller(fmt.Sprintf("addr %d", i), 1),
+			txs[i],
+			payload,
+			confirmationDepth,
+		))
+	}
+	assert.NoError(t, dbTx.Commit(ctx))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		for _, block := range blocks {
+			// Mirror the transaction used by BlockStorage.
+			txn := storage.db.WriteTransaction(gctx, blockSyncIdentifier, true)
+			workerG, workerCtx := errgroup.WithContext(gctx)
+			commitWorker, err := storage.AddingBlock(workerCtx, workerG, block, txn)
+			if err != nil {
+				txn.Discard(gctx)
+				return err
+			}
+
+			if err := workerG.Wait(); err != nil {
+				txn.Discard(gctx)
+				return err
+			}
+
+			if err := txn.Commit(gctx); err != nil {
+				return err
+			}
+
+			if err := commitWorker(gctx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	g.Go(func() error {
+		for i, tx := range txs {
+			if i%2 == 0 {
+				if err := storage.ClearBroadcast(gctx, tx); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := storage.RequeueBroadcast(gctx, tx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	assert.NoError(t, g.Wait())
+
+	broadcasts, err := storage.GetAllBroadcasts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, broadcasts, len(txs)/2)
+	for i, tx := range txs {
+		_, err := storage.GetBroadcastStatus(ctx, tx)
+		if i%2 == 0 {
+			assert.True(t, errors.Is(err, storageErrs.ErrBroadcastNotFound))
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestBroadcastStorageStatusRetention(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBroadcastStorage(
+		database,
+		100, // ensure broadcasts do not become stale
+		broadcastLimit,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+		WithStatusRetention(time.Second),
+	)
+	storage.now = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+
+	send1 := opFiller("addr 1", 11)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	tx1 := &types.TransactionIdentifier{Hash: "tx 1"}
+
+	mockHelper := &mocks.BroadcastStorageHelper{}
+	mockHandler := &mocks.BroadcastStorageHandler{}
+	mockExpirationHandler := &mocks.BroadcastExpirationHandler{}
+	storage.Initialize(
+		mockHelper,
+		&broadcastHandlerWithExpiration{mockHandler, mockExpirationHandler},
+	)
+
+	dbTx := database.Transaction(ctx)
+	assert.NoError(t, storage.Broadcast(
+		ctx,
+		dbTx,
+		"broadcast 1",
+		network,
+		send1,
+		tx1,
+		"payload 1",
+		confirmationDepth,
+	))
+	assert.NoError(t, storage.ExpireBroadcasts(ctx, dbTx, "broadcast 1"))
+	assert.NoError(t, dbTx.Commit(ctx))
+
+	// Active broadcasts are retained
+	time.Sleep(2 * time.Second)
+	status, err := storage.GetBroadcastStatus(ctx, tx1)
+	assert.NoError(t, err)
+	assert.Equal(t, BroadcastStatePending, status.State)
+
+	mockExpirationHandler.On(
+		"BroadcastExpired",
+		mock.Anything,
+		mock.Anything,
+		"broadcast 1",
+		tx1,
+		send1,
+	).Return(
+		nil,
+	).Once()
+
+	block := blockFiller(0, 1)[0]
+	mockHelper.On("CurrentBlockIdentifier", ctx).Return(block.BlockIdentifier, nil).Once()
+	mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil).Once()
+	txn := storage.db.Transaction(ctx)
+	g, gctx := errgroup.WithContext(ctx)
+	commitWorker, err := storage.AddingBlock(gctx, g, block, txn)
+	assert.NoError(t, err)
+	assert.NoError(t, g.Wait())
+	assert.NoError(t, txn.Commit(ctx))
+	assert.NoError(t, commitWorker(ctx))
+
+	status, err = storage.GetBroadcastStatus(ctx, tx1)
+	assert.NoError(t, err)
+	assert.Equal(t, BroadcastStateExpired, status.State)
+
+	// Finished broadcasts are deleted after the retention
+	// (BadgerDB stores expiration times in seconds).
+	time.Sleep(2 * time.Second)
+	status, err = storage.GetBroadcastStatus(ctx, tx1)
+	assert.True(t, errors.Is(err, storageErrs.ErrBroadcastNotFound))
+	assert.Nil(t, status)
+
+	statuses, err := storage.ListBroadcasts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 0)
+
+	err = storage.RequeueBroadcast(ctx, tx1)
+	assert.True(t, errors.Is(err, storageErrs.ErrBroadcastNotFound))
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockExpirationHandler.AssertExpectations(t)
+}
@@ -16,6 +16,7 @@ package modules
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"runtime"
@@ -24,8 +25,9 @@ import (
 	"github.com/neilotoole/errgroup"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/parser"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
-	"github.com/coinbase/rosetta-sdk-go/storage/errors"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
@@ -45,6 +47,7 @@ type CoinStorage struct {
 
 	helper   CoinStorageHelper
 	asserter *asserter.Asserter
+	parser   *parser.Parser
 }
 
 // CoinStorageHelper is used by CoinStorage to determine
@@ -70,6 +73,7 @@ func NewCoinStorage(
 		numCPU:   runtime.NumCPU(),
 		helper:   helper,
 		asserter: asserter,
+		parser:   parser.New(asserter, nil, nil),
 	}
 }
 
@@ -98,7 +102,7 @@ func (c *CoinStorage) getAndDecodeCoin(
 	key := getCoinKey(coinIdentifier)
 	exists, val, err := transaction.Get(ctx, key)
 	if err != nil {
-		return false, nil, nil, fmt.Errorf("%w: %v", errors.ErrCoinQueryFailed, err)
+		return false, nil, nil, fmt.Errorf("%w: %v", storageErrs.ErrCoinQueryFailed, err)
 	}
 
 	if !exists { // this could occur if coin was created before we started syncing
@@ -107,7 +111,7 @@ func (c *CoinStorage) getAndDecodeCoin(
 
 	var accountCoin types.AccountCoin
 	if err := c.db.Encoder().DecodeAccountCoin(val, &accountCoin, true); err != nil {
-		return false, nil, nil, fmt.Errorf("%w: %v", errors.ErrCoinDecodeFailed, err)
+		return false, nil, nil, fmt.Errorf("%w: %v", storageErrs.ErrCoinDecodeFailed, err)
 	}
 
 	return true, accountCoin.Coin, accountCoin.Account, nil
@@ -125,7 +129,7 @@ func (c *CoinStorage) AddCoins(
 	for _, accountCoin := range accountCoins {
 		exists, _, _, err := c.getAndDecodeCoin(ctx, dbTransaction, accountCoin.Coin.CoinIdentifier)
 		if err != nil {
-			return fmt.Errorf("%w: %v", errors.ErrCoinGetFailed, err)
+			return fmt.Errorf("%w: %v", storageErrs.ErrCoinGetFailed, err)
 		}
 
 		if exists {
@@ -134,12 +138,12 @@ func (c *CoinStorage) AddCoins(
 
 		err = c.addCoin(ctx, accountCoin.Account, accountCoin.Coin, dbTransaction)
 		if err != nil {
-			return fmt.Errorf("%w: %v", errors.ErrCoinAddFailed, err)
+			return fmt.Errorf("%w: %v", storageErrs.ErrCoinAddFailed, err)
 		}
 	}
 
 	if err := dbTransaction.Commit(ctx); err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrReconciliationUpdateCommitFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrReconciliationUpdateCommitFailed, err)
 	}
 
 	return nil
@@ -157,11 +161,11 @@ func (c *CoinStorage) addCoin(
 		Coin:    coin,
 	})
 	if err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrCoinDataEncodeFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrCoinDataEncodeFailed, err)
 	}
 
 	if err := storeUniqueKey(ctx, transaction, key, encodedResult, true); err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrCoinStoreFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrCoinStoreFailed, err)
 	}
 
 	if err := storeUniqueKey(
@@ -171,7 +175,7 @@ func (c *CoinStorage) addCoin(
 		[]byte(""),
 		false,
 	); err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrAccountCoinStoreFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrAccountCoinStoreFailed, err)
 	}
 
 	return nil
@@ -183,7 +187,7 @@ func getAndDecodeCoins(
 	accountIdentifier *types.AccountIdentifier,
 ) (map[string]struct{}, error) {
 	coins := map[string]struct{}{}
-	_, err := transaction.Scan(
+	_, err := transaction.ScanKeys(
 		ctx,
 		getCoinAccountPrefix(accountIdentifier),
 		getCoinAccountPrefix(accountIdentifier),
@@ -197,7 +201,7 @@ func getAndDecodeCoins(
 		false,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errors.ErrAccountCoinQueryFailed, err)
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrAccountCoinQueryFailed, err)
 	}
 
 	return coins, nil
@@ -212,7 +216,7 @@ func (c *CoinStorage) removeCoin(
 	key := getCoinKey(coinIdentifier)
 	exists, _, err := transaction.Get(ctx, key)
 	if err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrCoinQueryFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrCoinQueryFailed, err)
 	}
 
 	if !exists { // this could occur if coin was created before we started syncing
@@ -220,42 +224,45 @@ func (c *CoinStorage) removeCoin(
 	}
 
 	if err := transaction.Delete(ctx, key); err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrCoinDeleteFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrCoinDeleteFailed, err)
 	}
 
 	if err := transaction.Delete(ctx, getCoinAccountCoin(account, coinIdentifier)); err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrCoinDeleteFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrCoinDeleteFailed, err)
 	}
 
 	return nil
 }
 
-func (c *CoinStorage) skipOperation(
-	operation *types.Operation,
-) (bool, error) {
-	if operation.CoinChange == nil {
-		return true, nil
-	}
-
-	if operation.Amount == nil {
-		return true, nil
-	}
-
-	success, err := c.asserter.OperationSuccessful(operation)
-	if err != nil {
-		return false, fmt.Errorf("%w: %v", errors.ErrOperationParseFailed, err)
-	}
-
-	if !success {
-		return true, nil
+// coinChangesError wraps an error returned by the
+// parser when determining coin changes in
+// ErrCoinChangesParseFailed (preserving the CoinStorage
+// error associated with its cause).
+func coinChangesError(err error) error {
+	switch {
+	case errors.Is(err, parser.ErrCoinChangeDuplicate):
+		return fmt.Errorf(
+			"%s: %w: %v",
+			storageErrs.ErrCoinChangesParseFailed.Error(),
+			storageErrs.ErrDuplicateCoinFound,
+			err,
+		)
+	case errors.Is(err, parser.ErrCoinChangeSuccessUnknown):
+		return fmt.Errorf(
+			"%s: %w: %s: %v",
+			storageErrs.ErrCoinChangesParseFailed.Error(),
+			storageErrs.ErrUnableToDetermineIfSkipOperation,
+			storageErrs.ErrOperationParseFailed.Error(),
+			err,
+		)
+	default:
+		return fmt.Errorf("%w: %v", storageErrs.ErrCoinChangesParseFailed, err)
 	}
-
-	return false, nil
 }
 
-// updateCoins iterates through the transactions
-// in a block to determine which coins to add
-// and remove from storage.
+// updateCoins determines which coins to add and
+// remove from storage using the coin changes in
+// a block.
 //
 // If a coin is created and spent in the same block,
 // it is skipped (i.e. never added/removed from storage).
@@ -263,85 +270,50 @@ func (c *CoinStorage) skipOperation(
 // Alternatively, we could add all coins to the database
 // (regardless of whether they are spent in the same block),
 // however, this would put a larger strain on the db.
-func (c *CoinStorage) updateCoins( // nolint:gocognit
+func (c *CoinStorage) updateCoins(
 	ctx context.Context,
 	g *errgroup.Group,
 	block *types.Block,
-	addCoinCreated bool,
+	blockRemoved bool,
 	dbTx database.Transaction,
 ) error {
-	addCoins := map[string]*types.Operation{}
-	removeCoins := map[string]*types.Operation{}
-
-	for _, txn := range block.Transactions {
-		for _, operation := range txn.Operations {
-			skip, err := c.skipOperation(operation)
-			if err != nil {
-				return fmt.Errorf("%w: %v", errors.ErrUnableToDetermineIfSkipOperation, err)
-			}
-			if skip {
-				continue
-			}
-
-			coinChange := operation.CoinChange
-			identifier := coinChange.CoinIdentifier.Identifier
-			coinDict := removeCoins
-			if addCoinCreated && coinChange.CoinAction == types.CoinCreated ||
-				!addCoinCreated && coinChange.CoinAction == types.CoinSpent {
-				coinDict = addCoins
-			}
-
-			if _, ok := coinDict[identifier]; ok {
-				return fmt.Errorf("%w %s", errors.ErrDuplicateCoinFound, identifier)
-			}
-
-			coinDict[identifier] = operation
-		}
+	addCoins, removeCoins, err := c.parser.AccountCoinChanges(ctx, block, blockRemoved)
+	if err != nil {
+		return coinChangesError(err)
 	}
 
-	for identifier, val := range addCoins {
-		if _, ok := removeCoins[identifier]; ok {
-			continue
-		}
-
+	for _, val := range addCoins {
 		// We need to set variable before calling goroutine
 		// to avoid getting an updated pointer as loop iteration
 		// continues.
-		op := val
+		accountCoin := val
 		g.Go(func() error {
 			if err := c.addCoin(
 				ctx,
-				op.Account,
-				&types.Coin{
-					CoinIdentifier: op.CoinChange.CoinIdentifier,
-					Amount:         op.Amount,
-				},
+				accountCoin.Account,
+				accountCoin.Coin,
 				dbTx,
 			); err != nil {
-				return fmt.Errorf("%w: %v", errors.ErrCoinAddFailed, err)
+				return fmt.Errorf("%w: %v", storageErrs.ErrCoinAddFailed, err)
 			}
 
 			return nil
 		})
 	}
 
-	for identifier, val := range removeCoins {
-		if _, ok := addCoins[identifier]; ok {
-			continue
-		}
-
+	for _, val := range removeCoins {
 		// We need to set variable before calling goroutine
 		// to avoid getting an updated pointer as loop iteration
 		// continues.
-		op := val
+		accountCoin := val
 		g.Go(func() error {
 			if err := c.removeCoin(
 				ctx,
-				op.Account,
-				op.CoinChange.CoinIdentifier,
+				accountCoin.Account,
+				accountCoin.Coin.CoinIdentifier,
 				dbTx,
 			); err != nil {
-				return fmt.Errorf("%w: %v", errors.ErrCoinRemoveFailed, err)
+				return fmt.Errorf("%w: %v", storageErrs.ErrCoinRemoveFailed, err)
 			}
 
 			return nil
@@ -358,7 +330,7 @@ func (c *CoinStorage) AddingBlock(
 	block *types.Block,
 	transaction database.Transaction,
 ) (database.CommitWorker, error) {
-	return nil, c.updateCoins(ctx, g, block, true, transaction)
+	return nil, c.updateCoins(ctx, g, block, false, transaction)
 }
 
 // RemovingBlock is called by BlockStorage when removing a block.
@@ -368,7 +340,7 @@ func (c *CoinStorage) RemovingBlock(
 	block *types.Block,
 	transaction database.Transaction,
 ) (database.CommitWorker, error) {
-	return nil, c.updateCoins(ctx, g, block, false, transaction)
+	return nil, c.updateCoins(ctx, g, block, true, transaction)
 }
 
 // GetCoinsTransactional returns all unspent coins for a provided *types.AccountIdentifier.
@@ -379,12 +351,12 @@ func (c *CoinStorage) GetCoinsTransactional(
 ) ([]*types.Coin, *types.BlockIdentifier, error) {
 	coins, err := getAndDecodeCoins(ctx, dbTx, accountIdentifier)
 	if err != nil {
-		return nil, nil, fmt.Errorf("%w: %v", errors.ErrAccountIdentifierQueryFailed, err)
+		return nil, nil, fmt.Errorf("%w: %v", storageErrs.ErrAccountIdentifierQueryFailed, err)
 	}
 
 	headBlockIdentifier, err := c.helper.CurrentBlockIdentifier(ctx, dbTx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("%w: %v", errors.ErrCurrentBlockGetFailed, err)
+		return nil, nil, fmt.Errorf("%w: %v", storageErrs.ErrCurrentBlockGetFailed, err)
 	}
 
 	coinArr := []*types.Coin{}
@@ -395,11 +367,11 @@ func (c *CoinStorage) GetCoinsTransactional(
 			&types.CoinIdentifier{Identifier: coinIdentifier},
 		)
 		if err != nil {
-			return nil, nil, fmt.Errorf("%w: %v", errors.ErrCoinQueryFailed, err)
+			return nil, nil, fmt.Errorf("%w: %v", storageErrs.ErrCoinQueryFailed, err)
 		}
 
 		if !exists {
-			return nil, nil, fmt.Errorf("%w %s: %v", errors.ErrCoinGetFailed, coinIdentifier, err)
+			return nil, nil, fmt.Errorf("%w %s: %v", storageErrs.ErrCoinGetFailed, coinIdentifier, err)
 		}
 
 		coinArr = append(coinArr, coin)
@@ -428,11 +400,11 @@ func (c *CoinStorage) GetCoinTransactional(
 ) (*types.Coin, *types.AccountIdentifier, error) {
 	exists, coin, owner, err := c.getAndDecodeCoin(ctx, dbTx, coinIdentifier)
 	if err != nil {
-		return nil, nil, fmt.Errorf("%w: %v", errors.ErrCoinLookupFailed, err)
+		return nil, nil, fmt.Errorf("%w: %v", storageErrs.ErrCoinLookupFailed, err)
 	}
 
 	if !exists {
-		return nil, nil, errors.ErrCoinNotFound
+		return nil, nil, storageErrs.ErrCoinNotFound
 	}
 
 	return coin, owner, nil
@@ -461,7 +433,7 @@ func (c *CoinStorage) GetLargestCoin(
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf(
 			"%w for %s: %v",
-			errors.ErrUTXOBalanceGetFailed,
+			storageErrs.ErrUTXOBalanceGetFailed,
 			accountIdentifier.Address,
 			err,
 		)
@@ -482,7 +454,7 @@ func (c *CoinStorage) GetLargestCoin(
 		if !ok {
 			return nil, nil, nil, fmt.Errorf(
 				"%w %s",
-				errors.ErrCoinParseFailed,
+				storageErrs.ErrCoinParseFailed,
 				coin.CoinIdentifier.Identifier,
 			)
 		}
@@ -516,7 +488,7 @@ func (c *CoinStorage) SetCoinsImported(
 	}
 
 	if err := c.AddCoins(ctx, accountCoins); err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrCoinImportFailed, err)
+		return fmt.Errorf("%w: %v", storageErrs.ErrCoinImportFailed, err)
 	}
 
 	return nil
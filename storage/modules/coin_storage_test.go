@@ -395,7 +395,7 @@ func TestCoinStorage(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -594,7 +594,8 @@ func TestCoinStorage(t *testing.T) {
 		g, gctx := errgroup.WithContext(ctx)
 		commitFunc, err := c.AddingBlock(gctx, g, coinBlockRepeat, tx)
 		assert.Nil(t, commitFunc)
-		assert.Error(t, err)
+		assert.True(t, errors.Is(err, storageErrs.ErrDuplicateCoinFound))
+		assert.Contains(t, err.Error(), storageErrs.ErrCoinChangesParseFailed.Error())
 		assert.NoError(t, g.Wait())
 		tx.Discard(ctx)
 
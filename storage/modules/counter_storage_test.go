@@ -16,11 +16,14 @@ package modules
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
 
@@ -31,7 +34,7 @@ func TestCounterStorage(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -69,3 +72,45 @@ func TestCounterStorage(t *testing.T) {
 		assert.Equal(t, v, big.NewInt(0))
 	})
 }
+
+func TestCounterStorage_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		newDir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+
+	c := NewCounterStorage(db)
+	_, err = c.Update(ctx, "blah", big.NewInt(100))
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close(ctx))
+
+	readOnly, err := database.NewBadgerDatabase(
+		ctx,
+		newDir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+		database.WithReadOnly(),
+	)
+	assert.NoError(t, err)
+	defer readOnly.Close(ctx)
+
+	c = NewCounterStorage(readOnly)
+	v, err := c.Get(ctx, "blah")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), v)
+
+	v, err = c.Update(ctx, "blah", big.NewInt(100))
+	assert.Nil(t, v)
+	assert.True(t, errors.Is(err, storageErrs.ErrReadOnlyDatabase))
+
+	v, err = c.Get(ctx, "blah")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), v)
+}
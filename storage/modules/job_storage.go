@@ -17,22 +17,35 @@ package modules
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/constructor/job"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
 	"github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
 )
 
 const (
 	jobNamespace         = "job"
 	jobMetadataNamespace = "job-metadata"
+	jobIndexNamespace    = "job-index"
 
 	readyKey        = "ready"
 	broadcastingKey = "broadcasting"
 	processingKey   = "processing"
 	completedKey    = "completed"
 	failedKey       = "failed"
+
+	// indexedKey is stored once all jobs
+	// are in the job index.
+	indexedKey = "indexed"
+
+	// jobIndexKeyComponents is the minimum number of
+	// components in a job index key (after the namespace).
+	jobIndexKeyComponents = 4
 )
 
 func getJobKey(identifier string) []byte {
@@ -59,6 +72,75 @@ func getJobCompletedKey(workflow string) string {
 	return fmt.Sprintf("%s/%s", completedKey, workflow)
 }
 
+// getJobIndexPrefix returns the prefix of all job index
+// keys with a particular status (and workflow, if populated).
+func getJobIndexPrefix(status job.Status, workflow string) []byte {
+	if len(workflow) == 0 {
+		return []byte(fmt.Sprintf("%s/%s/", jobIndexNamespace, status))
+	}
+
+	return []byte(fmt.Sprintf("%s/%s/%s/", jobIndexNamespace, status, workflow))
+}
+
+// getJobIndexKey returns the key used to index a *job.Job by
+// status, workflow, and creation time. We pad the creation time
+// so that keys with the same status and workflow sort by age.
+func getJobIndexKey(j *job.Job) []byte {
+	return []byte(fmt.Sprintf(
+		"%s%020d/%s",
+		getJobIndexPrefix(j.Status, j.Workflow),
+		j.CreatedAt,
+		j.Identifier,
+	))
+}
+
+// parseJobIndexKey returns the workflow, creation time, and
+// identifier encoded in a job index key. We parse from the end
+// of the key because workflow names may contain "/".
+func parseJobIndexKey(k []byte) (string, int64, string, error) {
+	remaining := strings.TrimPrefix(string(k), jobIndexNamespace+"/")
+
+	// status/workflow/created_at/identifier
+	components := strings.Split(remaining, "/")
+	if len(components) < jobIndexKeyComponents {
+		return "", -1, "", fmt.Errorf("%s is not a valid job index key", string(k))
+	}
+
+	identifier := components[len(components)-1]
+	createdAt, err := strconv.ParseInt(components[len(components)-2], 10, 64)
+	if err != nil {
+		return "", -1, "", fmt.Errorf("%w: unable to parse created at", err)
+	}
+
+	workflow := strings.Join(components[1:len(components)-2], "/")
+	return workflow, createdAt, identifier, nil
+}
+
+// JobFilter is used to find *job.Jobs in JobStorage.
+// Any field that is not populated is not used to
+// filter results.
+type JobFilter struct {
+	// Workflow restricts results to jobs executing
+	// a particular workflow.
+	Workflow string
+
+	// Statuses restricts results to jobs in any
+	// of the provided statuses.
+	Statuses []job.Status
+
+	// CreatedBefore restricts results to jobs created
+	// strictly before some time.
+	CreatedBefore time.Time
+
+	// CreatedAfter restricts results to jobs created
+	// strictly after some time.
+	CreatedAfter time.Time
+
+	// Limit is the maximum number of jobs to return. If
+	// Limit is <= 0, all matching jobs are returned.
+	Limit int
+}
+
 // JobStorage implements storage methods for managing
 // jobs.
 type JobStorage struct {
@@ -294,7 +376,9 @@ func getAssociatedKeys(j *job.Job) [][]byte {
 	case job.Ready:
 		keys = append(keys, getJobMetadataKey(readyKey))
 		isProcessing = true
-	case job.Completed:
+	case job.Completed, job.CompletedWithoutBroadcast:
+		// Jobs completed in dry-run mode are considered completed
+		// so that end conditions are evaluated the same way.
 		keys = append(keys, getJobMetadataKey(getJobCompletedKey(j.Workflow)))
 		keys = append(keys, getJobMetadataKey(completedKey))
 	case job.Failed:
@@ -335,6 +419,118 @@ func (j *JobStorage) updateMetadata(
 	return nil
 }
 
+func (j *JobStorage) updateIndex(
+	ctx context.Context,
+	dbTx database.Transaction,
+	oldJob *job.Job,
+	newJob *job.Job,
+) error {
+	if oldJob != nil {
+		if err := dbTx.Delete(ctx, getJobIndexKey(oldJob)); err != nil {
+			return fmt.Errorf(
+				"%w %s from job index: %v",
+				errors.ErrJobRemoveFailed,
+				oldJob.Identifier,
+				err,
+			)
+		}
+	}
+
+	if err := dbTx.Set(ctx, getJobIndexKey(newJob), []byte{}, true); err != nil {
+		return fmt.Errorf(
+			"%w %s to job index: %v",
+			errors.ErrJobAddFailed,
+			newJob.Identifier,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// indexed returns a boolean indicating if all jobs
+// are in the job index. Jobs stored before the job
+// index was introduced are not indexed until the
+// index is backfilled.
+func (j *JobStorage) indexed(
+	ctx context.Context,
+	dbTx database.Transaction,
+) (bool, error) {
+	exists, _, err := dbTx.Get(ctx, getJobMetadataKey(indexedKey))
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", errors.ErrJobIndexReadFailed, err)
+	}
+
+	return exists, nil
+}
+
+// allJobs returns all stored *job.Job (regardless
+// of status).
+func (j *JobStorage) allJobs(
+	ctx context.Context,
+	dbTx database.Transaction,
+) ([]*job.Job, error) {
+	prefix := []byte(jobNamespace + "/")
+	jobs := []*job.Job{}
+	_, err := dbTx.Scan(
+		ctx,
+		prefix,
+		prefix,
+		func(k []byte, v []byte) error {
+			var output job.Job
+			// We should not reclaim memory during a scan!!
+			if err := j.db.Encoder().Decode("", v, &output, false); err != nil {
+				return fmt.Errorf("%w: %v", errors.ErrJobDecodeFailed, err)
+			}
+
+			jobs = append(jobs, &output)
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrJobsGetAllFailed, err)
+	}
+
+	return jobs, nil
+}
+
+// backfillIndex adds all stored jobs to the job index if
+// it has not been done already. This is called on the first
+// Update after opening a database created before the job
+// index was introduced (and is a no-op afterwards).
+func (j *JobStorage) backfillIndex(
+	ctx context.Context,
+	dbTx database.Transaction,
+) error {
+	indexed, err := j.indexed(ctx, dbTx)
+	if err != nil {
+		return err
+	}
+
+	if indexed {
+		return nil
+	}
+
+	jobs, err := j.allJobs(ctx, dbTx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrJobIndexBackfillFailed, err)
+	}
+
+	for _, v := range jobs {
+		if err := dbTx.Set(ctx, getJobIndexKey(v), []byte{}, true); err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrJobIndexBackfillFailed, err)
+		}
+	}
+
+	if err := dbTx.Set(ctx, getJobMetadataKey(indexedKey), []byte{}, true); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrJobIndexBackfillFailed, err)
+	}
+
+	return nil
+}
+
 // Update overwrites an existing *job.Job or creates a new one (and assigns an identifier).
 func (j *JobStorage) Update(
 	ctx context.Context,
@@ -349,6 +545,10 @@ func (j *JobStorage) Update(
 		}
 
 		v.Identifier = newIdentifier
+
+		if v.CreatedAt == 0 {
+			v.CreatedAt = utils.Milliseconds()
+		}
 	} else {
 		var err error
 		oldJob, err = j.Get(ctx, dbTx, v.Identifier)
@@ -357,7 +557,9 @@ func (j *JobStorage) Update(
 		}
 	}
 
-	if oldJob != nil && (oldJob.Status == job.Completed || oldJob.Status == job.Failed) {
+	if oldJob != nil && (oldJob.Status == job.Completed ||
+		oldJob.Status == job.CompletedWithoutBroadcast ||
+		oldJob.Status == job.Failed) {
 		return "", fmt.Errorf("%w %s", errors.ErrJobUpdateOldFailed, v.Identifier)
 	}
 
@@ -375,6 +577,14 @@ func (j *JobStorage) Update(
 		return "", fmt.Errorf("%w: %v", errors.ErrJobMetadataUpdateFailed, err)
 	}
 
+	if err := j.backfillIndex(ctx, dbTx); err != nil {
+		return "", err
+	}
+
+	if err := j.updateIndex(ctx, dbTx, oldJob, v); err != nil {
+		return "", err
+	}
+
 	return v.Identifier, nil
 }
 
@@ -402,3 +612,173 @@ func (j *JobStorage) Get(
 
 	return &output, nil
 }
+
+// jobIndexEntry is a *job.Job found in the
+// job index.
+type jobIndexEntry struct {
+	identifier string
+	createdAt  int64
+}
+
+// matches returns a boolean indicating if a job
+// with a workflow and creation time satisfies the
+// JobFilter (ignoring its statuses and limit).
+func (f JobFilter) matches(workflow string, createdAt int64) bool {
+	// The prefix for some workflow may also match
+	// workflows that contain it (ex: "a" and "a/b").
+	if len(f.Workflow) > 0 && workflow != f.Workflow {
+		return false
+	}
+
+	if !f.CreatedBefore.IsZero() &&
+		createdAt >= f.CreatedBefore.UnixNano()/int64(time.Millisecond) {
+		return false
+	}
+
+	if !f.CreatedAfter.IsZero() &&
+		createdAt <= f.CreatedAfter.UnixNano()/int64(time.Millisecond) {
+		return false
+	}
+
+	return true
+}
+
+// findIndexEntries returns all entries in the job
+// index matching a JobFilter.
+func (j *JobStorage) findIndexEntries(
+	ctx context.Context,
+	dbTx database.Transaction,
+	filter JobFilter,
+	statuses []job.Status,
+) ([]*jobIndexEntry, error) {
+	entries := []*jobIndexEntry{}
+	for _, status := range statuses {
+		prefix := getJobIndexPrefix(status, filter.Workflow)
+		_, err := dbTx.Scan(
+			ctx,
+			prefix,
+			prefix,
+			func(k []byte, v []byte) error {
+				workflow, createdAt, identifier, err := parseJobIndexKey(k)
+				if err != nil {
+					return err
+				}
+
+				if !filter.matches(workflow, createdAt) {
+					return nil
+				}
+
+				entries = append(entries, &jobIndexEntry{
+					identifier: identifier,
+					createdAt:  createdAt,
+				})
+
+				return nil
+			},
+			false,
+			false,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrJobFindFailed, err)
+		}
+	}
+
+	return entries, nil
+}
+
+// findUnindexedEntries returns all stored jobs matching a
+// JobFilter by scanning every job. This is only used before
+// the job index has been backfilled.
+func (j *JobStorage) findUnindexedEntries(
+	ctx context.Context,
+	dbTx database.Transaction,
+	filter JobFilter,
+	statuses []job.Status,
+) ([]*jobIndexEntry, error) {
+	jobs, err := j.allJobs(ctx, dbTx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrJobFindFailed, err)
+	}
+
+	entries := []*jobIndexEntry{}
+	for _, v := range jobs {
+		if !containsStatus(statuses, v.Status) || !filter.matches(v.Workflow, v.CreatedAt) {
+			continue
+		}
+
+		entries = append(entries, &jobIndexEntry{
+			identifier: v.Identifier,
+			createdAt:  v.CreatedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+func containsStatus(statuses []job.Status, status job.Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindJobs returns all *job.Job matching a JobFilter,
+// sorted from oldest to newest.
+func (j *JobStorage) FindJobs(
+	ctx context.Context,
+	dbTx database.Transaction,
+	filter JobFilter,
+) ([]*job.Job, error) {
+	statuses := filter.Statuses
+	if len(statuses) == 0 {
+		statuses = []job.Status{
+			job.Ready,
+			job.Broadcasting,
+			job.Failed,
+			job.Completed,
+			job.CompletedWithoutBroadcast,
+		}
+	}
+
+	indexed, err := j.indexed(ctx, dbTx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrJobFindFailed, err)
+	}
+
+	var entries []*jobIndexEntry
+	if indexed {
+		entries, err = j.findIndexEntries(ctx, dbTx, filter, statuses)
+	} else {
+		entries, err = j.findUnindexedEntries(ctx, dbTx, filter, statuses)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(a, b int) bool {
+		if entries[a].createdAt != entries[b].createdAt {
+			return entries[a].createdAt < entries[b].createdAt
+		}
+
+		return entries[a].identifier < entries[b].identifier
+	})
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+
+	jobs := make([]*job.Job, len(entries))
+	for i, entry := range entries {
+		v, err := j.Get(ctx, dbTx, entry.identifier)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %v", errors.ErrJobGetFailed, entry.identifier, err)
+		}
+
+		jobs[i] = v
+	}
+
+	return jobs, nil
+}
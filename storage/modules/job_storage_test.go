@@ -17,10 +17,12 @@ package modules
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
 
@@ -31,7 +33,7 @@ func TestJobStorage(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -331,3 +333,358 @@ func TestJobStorage(t *testing.T) {
 		assert.Equal(t, "", jobIdentifier)
 	})
 }
+
+func TestJobStorage_CompletedWithoutBroadcast(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewJobStorage(database)
+
+	newJob := &job.Job{
+		Workflow: "blah",
+		Status:   job.Broadcasting,
+	}
+	t.Run("add job", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		jobIdentifier, err := storage.Update(ctx, dbTx, newJob)
+		assert.NoError(t, err)
+		assert.Equal(t, "0", jobIdentifier)
+		assert.NoError(t, dbTx.Commit(ctx))
+	})
+
+	t.Run("complete job without broadcast", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		newJob.Status = job.CompletedWithoutBroadcast
+		newJob.Identifier = "0"
+
+		jobIdentifier, err := storage.Update(ctx, dbTx, newJob)
+		assert.NoError(t, err)
+		assert.Equal(t, "0", jobIdentifier)
+
+		jobs, err := storage.Broadcasting(ctx, dbTx)
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 0)
+
+		jobs, err = storage.Processing(ctx, dbTx, "blah")
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 0)
+
+		jobs, err = storage.FindJobs(ctx, dbTx, JobFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{newJob}, jobs)
+
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		jobs, err = storage.Completed(ctx, "blah")
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*job.Job{newJob}, jobs)
+	})
+
+	t.Run("attempt to update job", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		newJob.Status = job.Ready
+
+		jobIdentifier, err := storage.Update(ctx, dbTx, newJob)
+		assert.Error(t, err)
+		assert.Equal(t, "", jobIdentifier)
+	})
+}
+
+func countJobIndexKeys(
+	ctx context.Context,
+	t *testing.T,
+	dbTx database.Transaction,
+) int {
+	entries, err := dbTx.Scan(
+		ctx,
+		[]byte(jobIndexNamespace),
+		[]byte(jobIndexNamespace),
+		func(k []byte, v []byte) error {
+			return nil
+		},
+		false,
+		false,
+	)
+	assert.NoError(t, err)
+
+	return entries
+}
+
+func TestJobStorage_FindJobs(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewJobStorage(database)
+
+	start := time.Unix(1000, 0)
+	milliseconds := func(d time.Duration) int64 {
+		return start.Add(d).UnixNano() / int64(time.Millisecond)
+	}
+
+	transferA := &job.Job{
+		Workflow:  "transfer",
+		Status:    job.Ready,
+		CreatedAt: milliseconds(0),
+	}
+	transferB := &job.Job{
+		Workflow:  "transfer",
+		Status:    job.Ready,
+		CreatedAt: milliseconds(time.Hour),
+	}
+	transferNested := &job.Job{
+		Workflow:  "transfer/nested",
+		Status:    job.Ready,
+		CreatedAt: milliseconds(time.Minute),
+	}
+	stake := &job.Job{
+		Workflow:  "stake",
+		Status:    job.Broadcasting,
+		CreatedAt: milliseconds(2 * time.Hour),
+	}
+
+	t.Run("find with no jobs", func(t *testing.T) {
+		dbTx := database.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		jobs, err := storage.FindJobs(ctx, dbTx, JobFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 0)
+	})
+
+	t.Run("add jobs", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		for _, j := range []*job.Job{transferA, transferB, transferNested, stake} {
+			_, err := storage.Update(ctx, dbTx, j)
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, 4, countJobIndexKeys(ctx, t, dbTx))
+		assert.NoError(t, dbTx.Commit(ctx))
+	})
+
+	t.Run("find all jobs", func(t *testing.T) {
+		dbTx := database.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		jobs, err := storage.FindJobs(ctx, dbTx, JobFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{transferA, transferNested, transferB, stake}, jobs)
+
+		jobs, err = storage.FindJobs(ctx, dbTx, JobFilter{Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{transferA, transferNested}, jobs)
+	})
+
+	t.Run("find by workflow", func(t *testing.T) {
+		dbTx := database.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		jobs, err := storage.FindJobs(ctx, dbTx, JobFilter{Workflow: "transfer"})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{transferA, transferB}, jobs)
+
+		jobs, err = storage.FindJobs(ctx, dbTx, JobFilter{Workflow: "transfer/nested"})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{transferNested}, jobs)
+
+		jobs, err = storage.FindJobs(ctx, dbTx, JobFilter{Workflow: "unknown"})
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 0)
+	})
+
+	t.Run("find by status", func(t *testing.T) {
+		dbTx := database.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		jobs, err := storage.FindJobs(ctx, dbTx, JobFilter{
+			Statuses: []job.Status{job.Broadcasting},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{stake}, jobs)
+
+		jobs, err = storage.FindJobs(ctx, dbTx, JobFilter{
+			Statuses: []job.Status{job.Broadcasting, job.Failed},
+			Workflow: "transfer",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 0)
+	})
+
+	t.Run("find by age", func(t *testing.T) {
+		dbTx := database.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		jobs, err := storage.FindJobs(ctx, dbTx, JobFilter{
+			CreatedBefore: start.Add(time.Hour),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{transferA, transferNested}, jobs)
+
+		jobs, err = storage.FindJobs(ctx, dbTx, JobFilter{
+			CreatedAfter: start,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{transferNested, transferB, stake}, jobs)
+
+		jobs, err = storage.FindJobs(ctx, dbTx, JobFilter{
+			CreatedAfter:  start,
+			CreatedBefore: start.Add(2 * time.Hour),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{transferNested, transferB}, jobs)
+	})
+
+	t.Run("transition jobs", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		transferA.Status = job.Broadcasting
+		_, err := storage.Update(ctx, dbTx, transferA)
+		assert.NoError(t, err)
+
+		transferA.Status = job.Failed
+		_, err = storage.Update(ctx, dbTx, transferA)
+		assert.NoError(t, err)
+
+		transferB.Status = job.Completed
+		_, err = storage.Update(ctx, dbTx, transferB)
+		assert.NoError(t, err)
+
+		stake.Status = job.Ready
+		_, err = storage.Update(ctx, dbTx, stake)
+		assert.NoError(t, err)
+
+		// Each job should only be indexed once, regardless
+		// of how many transitions it has gone through.
+		assert.Equal(t, 4, countJobIndexKeys(ctx, t, dbTx))
+		assert.NoError(t, dbTx.Commit(ctx))
+	})
+
+	t.Run("find after transitions", func(t *testing.T) {
+		dbTx := database.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		jobs, err := storage.FindJobs(ctx, dbTx, JobFilter{
+			Workflow:      "transfer",
+			Statuses:      []job.Status{job.Failed},
+			CreatedBefore: start.Add(time.Hour),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{transferA}, jobs)
+
+		jobs, err = storage.FindJobs(ctx, dbTx, JobFilter{
+			Statuses: []job.Status{job.Ready, job.Broadcasting},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{transferNested, stake}, jobs)
+
+		jobs, err = storage.FindJobs(ctx, dbTx, JobFilter{
+			Statuses: []job.Status{job.Completed},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{transferB}, jobs)
+	})
+}
+
+func TestJobStorage_BackfillIndex(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewJobStorage(database)
+
+	existing := &job.Job{
+		Identifier: "existing",
+		Workflow:   "transfer",
+		Status:     job.Ready,
+		CreatedAt:  1000,
+	}
+	newJob := &job.Job{
+		Workflow:  "transfer",
+		Status:    job.Ready,
+		CreatedAt: 2000,
+	}
+
+	t.Run("store job without index", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		// Simulate a job stored before the job index existed.
+		encoded, err := database.Encoder().Encode("", existing)
+		assert.NoError(t, err)
+		assert.NoError(t, dbTx.Set(ctx, getJobKey(existing.Identifier), encoded, true))
+		assert.NoError(t, storage.updateMetadata(ctx, dbTx, nil, existing))
+		assert.NoError(t, dbTx.Commit(ctx))
+	})
+
+	t.Run("find before backfill", func(t *testing.T) {
+		dbTx := database.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		assert.Equal(t, 0, countJobIndexKeys(ctx, t, dbTx))
+
+		jobs, err := storage.FindJobs(ctx, dbTx, JobFilter{
+			Workflow: "transfer",
+			Statuses: []job.Status{job.Ready},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{existing}, jobs)
+
+		jobs, err = storage.FindJobs(ctx, dbTx, JobFilter{
+			Statuses: []job.Status{job.Failed},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 0)
+	})
+
+	t.Run("backfill on update", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		_, err := storage.Update(ctx, dbTx, newJob)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, countJobIndexKeys(ctx, t, dbTx))
+		assert.NoError(t, dbTx.Commit(ctx))
+	})
+
+	t.Run("find after backfill", func(t *testing.T) {
+		dbTx := database.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		indexed, err := storage.indexed(ctx, dbTx)
+		assert.NoError(t, err)
+		assert.True(t, indexed)
+
+		jobs, err := storage.FindJobs(ctx, dbTx, JobFilter{Workflow: "transfer"})
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{existing, newJob}, jobs)
+	})
+}
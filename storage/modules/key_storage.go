@@ -15,10 +15,14 @@
 package modules
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"runtime"
+
+	"github.com/neilotoole/errgroup"
 
 	"github.com/coinbase/rosetta-sdk-go/keys"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
@@ -48,10 +52,34 @@ func getAccountKey(account *types.AccountIdentifier) []byte {
 	)
 }
 
+// KeyStorageHelper is used by KeyStorage to derive the
+// AccountIdentifier associated with a *types.PublicKey.
+type KeyStorageHelper interface {
+	Derive(
+		context.Context,
+		*types.PublicKey,
+	) (*types.AccountIdentifier, error)
+}
+
+// KeyStorageCompactionHelper is an optional extension of
+// KeyStorageHelper. Deleting a key from a database.Database
+// (like BadgerDB) only deletes it logically (the key material
+// remains on disk until the database is compacted). When the
+// KeyStorageHelper provided to KeyStorage implements this
+// interface, CompactKeys is invoked after each key deletion
+// is committed so that callers can trigger compaction.
+type KeyStorageCompactionHelper interface {
+	CompactKeys(context.Context) error
+}
+
 // KeyStorage implements key storage methods
 // on top of a database.Database and database.Transaction interface.
+//
+// KeyStorage does not cache keys in memory. Any *keys.KeyPair loaded
+// to sign payloads is zeroized before signing returns.
 type KeyStorage struct {
-	db database.Database
+	db     database.Database
+	helper KeyStorageHelper
 }
 
 // NewKeyStorage returns a new KeyStorage.
@@ -63,6 +91,14 @@ func NewKeyStorage(
 	}
 }
 
+// Initialize adds a KeyStorageHelper to KeyStorage. When
+// a KeyStorageHelper is provided, imported prefunded accounts
+// are validated against the account derived from their
+// public key.
+func (k *KeyStorage) Initialize(helper KeyStorageHelper) {
+	k.helper = helper
+}
+
 // Key is the struct stored in key storage. This
 // is public so that accounts can be loaded from
 // a configuration file.
@@ -218,6 +254,10 @@ func (k *KeyStorage) Sign(
 ) ([]*types.Signature, error) {
 	signatures := make([]*types.Signature, len(payloads))
 	for i, payload := range payloads {
+		if len(payload.SignatureType) == 0 {
+			return nil, fmt.Errorf("%w %d", storageErrs.ErrDetermineSigTypeFailed, i)
+		}
+
 		keyPair, err := k.Get(ctx, payload.AccountIdentifier)
 		if err != nil {
 			return nil, fmt.Errorf(
@@ -228,16 +268,16 @@ func (k *KeyStorage) Sign(
 			)
 		}
 
+		// The loaded KeyPair is only used to sign this
+		// payload, so we zero it as soon as we are done.
 		signer, err := keyPair.Signer()
 		if err != nil {
+			keyPair.Zeroize()
 			return nil, fmt.Errorf("%w: %v", storageErrs.ErrSignerCreateFailed, err)
 		}
 
-		if len(payload.SignatureType) == 0 {
-			return nil, fmt.Errorf("%w %d", storageErrs.ErrDetermineSigTypeFailed, i)
-		}
-
 		signature, err := signer.Sign(payload, payload.SignatureType)
+		keyPair.Zeroize()
 		if err != nil {
 			return nil, fmt.Errorf("%w for %d: %v", storageErrs.ErrSignPayloadFailed, i, err)
 		}
@@ -248,6 +288,184 @@ func (k *KeyStorage) Sign(
 	return signatures, nil
 }
 
+// SignPayloads signs a slice of *types.SigningPayload with the keys in
+// KeyStorage. Each signer's key is loaded once and payloads are signed
+// concurrently (bounded by the number of CPUs). Signatures are returned
+// in the same order as payloads. If any payload cannot be signed, a
+// *storageErrs.SignPayloadError identifying the payload is returned.
+func (k *KeyStorage) SignPayloads(
+	ctx context.Context,
+	payloads []*types.SigningPayload,
+) ([]*types.Signature, error) {
+	signers, keyPairs, err := k.loadSigners(ctx, payloads)
+	if err != nil {
+		return nil, err
+	}
+
+	// Loaded keys are only used to sign payloads, so we
+	// zero them once all signing has completed.
+	defer zeroizeKeyPairs(keyPairs)
+
+	signatures := make([]*types.Signature, len(payloads))
+	concurrency := runtime.NumCPU()
+	g, gctx := errgroup.WithContextN(ctx, concurrency, concurrency)
+	for i := range payloads {
+		// We need to set variables before calling goroutine
+		// to avoid getting an updated pointer as loop iteration
+		// continues.
+		index := i
+		payload := payloads[i]
+		signer := signers[string(getAccountKey(payload.AccountIdentifier))]
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+
+			signature, err := signer.Sign(payload, payload.SignatureType)
+			if err != nil {
+				return &storageErrs.SignPayloadError{
+					Index:   index,
+					Account: payload.AccountIdentifier,
+					Err:     fmt.Errorf("%w: %v", storageErrs.ErrSignPayloadFailed, err),
+				}
+			}
+
+			signatures[index] = signature
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return signatures, nil
+}
+
+// loadSigners validates payloads and returns a keys.Signer
+// for each unique account in payloads (keyed by the account's
+// storage key) loaded in a single database transaction. The
+// loaded *keys.KeyPairs are also returned so that they can be
+// zeroized when they are no longer needed.
+func (k *KeyStorage) loadSigners(
+	ctx context.Context,
+	payloads []*types.SigningPayload,
+) (map[string]keys.Signer, []*keys.KeyPair, error) {
+	dbTx := k.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	signers := map[string]keys.Signer{}
+	keyPairs := []*keys.KeyPair{}
+	for i, payload := range payloads {
+		if len(payload.SignatureType) == 0 {
+			zeroizeKeyPairs(keyPairs)
+			return nil, nil, &storageErrs.SignPayloadError{
+				Index:   i,
+				Account: payload.AccountIdentifier,
+				Err:     storageErrs.ErrDetermineSigTypeFailed,
+			}
+		}
+
+		key := string(getAccountKey(payload.AccountIdentifier))
+		if _, ok := signers[key]; ok {
+			continue
+		}
+
+		keyPair, err := k.GetTransactional(ctx, dbTx, payload.AccountIdentifier)
+		if err != nil {
+			zeroizeKeyPairs(keyPairs)
+			return nil, nil, &storageErrs.SignPayloadError{
+				Index:   i,
+				Account: payload.AccountIdentifier,
+				Err:     fmt.Errorf("%w: %v", storageErrs.ErrKeyGetFailed, err),
+			}
+		}
+
+		keyPairs = append(keyPairs, keyPair)
+		signer, err := keyPair.Signer()
+		if err != nil {
+			zeroizeKeyPairs(keyPairs)
+			return nil, nil, &storageErrs.SignPayloadError{
+				Index:   i,
+				Account: payload.AccountIdentifier,
+				Err:     fmt.Errorf("%w: %v", storageErrs.ErrSignerCreateFailed, err),
+			}
+		}
+
+		signers[key] = signer
+	}
+
+	return signers, keyPairs, nil
+}
+
+// zeroizeKeyPairs zeroizes each *keys.KeyPair in keyPairs.
+func zeroizeKeyPairs(keyPairs []*keys.KeyPair) {
+	for _, keyPair := range keyPairs {
+		keyPair.Zeroize()
+	}
+}
+
+// DeleteKeyTransactional deletes the key stored for an
+// AccountIdentifier in a database.Transaction. If no key
+// is stored for the AccountIdentifier, ErrAddrNotFound
+// is returned.
+func (k *KeyStorage) DeleteKeyTransactional(
+	ctx context.Context,
+	dbTx database.Transaction,
+	account *types.AccountIdentifier,
+) error {
+	// We load the existing key to ensure it exists and
+	// zeroize the loaded copy once it is deleted.
+	keyPair, err := k.GetTransactional(ctx, dbTx, account)
+	if err != nil {
+		return err
+	}
+	defer keyPair.Zeroize()
+
+	if err := dbTx.Delete(ctx, getAccountKey(account)); err != nil {
+		return fmt.Errorf(
+			"%w: %s %v",
+			storageErrs.ErrDeleteKeyFailed,
+			types.PrintStruct(account),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// DeleteKey deletes the key stored for an AccountIdentifier.
+// The key is only deleted logically by the underlying database
+// (it remains on disk until the database is compacted). If the
+// KeyStorageHelper implements KeyStorageCompactionHelper,
+// CompactKeys is invoked once the deletion is committed.
+func (k *KeyStorage) DeleteKey(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+) error {
+	dbTx := k.db.Transaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	if err := k.DeleteKeyTransactional(ctx, dbTx, account); err != nil {
+		return fmt.Errorf("%w: unable to delete key", err)
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrCommitKeyFailed, err)
+	}
+
+	compactor, ok := k.helper.(KeyStorageCompactionHelper)
+	if !ok {
+		return nil
+	}
+
+	if err := compactor.CompactKeys(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrKeyCompactionFailed, err)
+	}
+
+	return nil
+}
+
 // RandomAccount returns a random account from all accounts.
 func (k *KeyStorage) RandomAccount(ctx context.Context) (*types.AccountIdentifier, error) {
 	accounts, err := k.GetAllAccounts(ctx)
@@ -272,7 +490,7 @@ func (k *KeyStorage) ImportAccounts(ctx context.Context, accounts []*PrefundedAc
 	for _, acc := range accounts {
 		keyPair, err := keys.ImportPrivateKey(acc.PrivateKeyHex, acc.CurveType)
 		if err != nil {
-			return fmt.Errorf("%w: %v", storageErrs.ErrAddrImportFailed, err)
+			return &storageErrs.AddrImportError{Err: err}
 		}
 
 		// Skip if key already exists
@@ -286,3 +504,101 @@ func (k *KeyStorage) ImportAccounts(ctx context.Context, accounts []*PrefundedAc
 	}
 	return nil
 }
+
+// importPrefundedAccount parses the private key of a *PrefundedAccount,
+// ensures it matches the provided AccountIdentifier (if a KeyStorageHelper
+// is available), and stores it in a database.Transaction. Prefunded
+// accounts without an AccountIdentifier or with a SubAccount are rejected.
+func (k *KeyStorage) importPrefundedAccount(
+	ctx context.Context,
+	dbTx database.Transaction,
+	acc *PrefundedAccount,
+) error {
+	if acc.AccountIdentifier == nil {
+		return storageErrs.ErrPrefundedAcctMissingAccount
+	}
+
+	if acc.AccountIdentifier.SubAccount != nil {
+		return fmt.Errorf(
+			"%w: %s",
+			storageErrs.ErrPrefundedAcctSubAccount,
+			types.PrintStruct(acc.AccountIdentifier.SubAccount),
+		)
+	}
+
+	keyPair, err := keys.ImportPrivateKey(acc.PrivateKeyHex, acc.CurveType)
+	if err != nil {
+		return &storageErrs.AddrImportError{Err: err}
+	}
+
+	if k.helper != nil {
+		derived, err := k.helper.Derive(ctx, keyPair.PublicKey)
+		if err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrPrefundedAcctDeriveFailed, err)
+		}
+
+		if derived == nil || derived.Address != acc.AccountIdentifier.Address {
+			return fmt.Errorf(
+				"%w: derived %s but expected %s",
+				storageErrs.ErrPrefundedAcctMismatch,
+				types.PrintStruct(derived),
+				types.PrintStruct(acc.AccountIdentifier),
+			)
+		}
+	}
+
+	existing, err := k.GetTransactional(ctx, dbTx, acc.AccountIdentifier)
+	switch {
+	case err == nil:
+		// Importing an identical key is a no-op.
+		if existing.PublicKey.CurveType == keyPair.PublicKey.CurveType &&
+			bytes.Equal(existing.PrivateKey, keyPair.PrivateKey) {
+			return nil
+		}
+
+		return fmt.Errorf(
+			"%w: %s",
+			storageErrs.ErrAddrConflict,
+			types.PrintStruct(acc.AccountIdentifier),
+		)
+	case !errors.Is(err, storageErrs.ErrAddrNotFound):
+		return err
+	}
+
+	if err := k.StoreTransactional(ctx, acc.AccountIdentifier, keyPair, dbTx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrPrefundedAcctStoreFailed, err)
+	}
+
+	return nil
+}
+
+// ImportPrefundedAccounts loads a set of prefunded accounts into key storage
+// in a single database transaction. Unlike ImportAccounts, a key that
+// conflicts with one already stored for the same account (or an account
+// with a SubAccount) is considered an error. If any account cannot be
+// imported, none are stored and the returned error indicates which entry
+// failed.
+func (k *KeyStorage) ImportPrefundedAccounts(
+	ctx context.Context,
+	accounts []*PrefundedAccount,
+) error {
+	dbTx := k.db.Transaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	for i, acc := range accounts {
+		if err := k.importPrefundedAccount(ctx, dbTx, acc); err != nil {
+			return fmt.Errorf(
+				"%w: unable to import prefunded account %d (%s)",
+				err,
+				i,
+				types.PrintStruct(acc.AccountIdentifier),
+			)
+		}
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrCommitKeyFailed, err)
+	}
+
+	return nil
+}
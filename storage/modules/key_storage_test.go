@@ -16,12 +16,17 @@ package modules
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/coinbase/rosetta-sdk-go/keys"
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/storage/modules"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
@@ -38,7 +43,7 @@ func TestKeyStorage(t *testing.T) {
 	assert.NoError(t, err)
 	defer utils.RemoveTempDir(newDir)
 
-	database, err := newTestBadgerDatabase(ctx, newDir)
+	database, err := newTestDatabase(ctx, newDir)
 	assert.NoError(t, err)
 	defer database.Close(ctx)
 
@@ -217,3 +222,553 @@ func TestKeyStorage(t *testing.T) {
 		assert.Equal(t, endLen, startingLen)
 	})
 }
+
+func TestKeyStorageDerivedKeys(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	k := NewKeyStorage(database)
+
+	seed := []byte("0123456789abcdef0123456789abcdef")
+	secp256k1Master, err := keys.MasterKeyFromSeed(seed, types.Secp256k1)
+	assert.NoError(t, err)
+	secp256k1Child, err := keys.DeriveChildKey(secp256k1Master, "m/44'/60'/0'/0/0")
+	assert.NoError(t, err)
+
+	edwards25519Master, err := keys.MasterKeyFromSeed(seed, types.Edwards25519)
+	assert.NoError(t, err)
+	edwards25519Child, err := keys.DeriveChildKey(edwards25519Master, "m/44'/501'/0'/0'")
+	assert.NoError(t, err)
+
+	account1 := &types.AccountIdentifier{Address: "derived1"}
+	account2 := &types.AccountIdentifier{Address: "derived2"}
+	assert.NoError(t, k.Store(ctx, account1, secp256k1Child))
+	assert.NoError(t, k.Store(ctx, account2, edwards25519Child))
+
+	v, err := k.Get(ctx, account1)
+	assert.NoError(t, err)
+	assert.Equal(t, secp256k1Child, v)
+
+	v, err = k.Get(ctx, account2)
+	assert.NoError(t, err)
+	assert.Equal(t, edwards25519Child, v)
+
+	sigs, err := k.Sign(ctx, []*types.SigningPayload{
+		{
+			AccountIdentifier: account1,
+			Bytes:             hash("msg1"),
+			SignatureType:     types.Ecdsa,
+		},
+		{
+			AccountIdentifier: account2,
+			Bytes:             hash("msg2"),
+			SignatureType:     types.Ed25519,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sigs, 2)
+	assert.NoError(t, (&keys.SignerSecp256k1{}).Verify(sigs[0]))
+	assert.NoError(t, (&keys.SignerEdwards25519{}).Verify(sigs[1]))
+}
+
+func TestImportPrefundedAccounts(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	k := NewKeyStorage(database)
+
+	acc1 := &PrefundedAccount{
+		PrivateKeyHex:     "0e842a16b2d39a4dff5c63688513cb2109e30c3c30bc4eb502cc54f4614493f6",
+		AccountIdentifier: &types.AccountIdentifier{Address: "addr1"},
+		CurveType:         types.Edwards25519,
+	}
+	acc2 := &PrefundedAccount{
+		PrivateKeyHex:     "42efc44bdf7b2d4d45ddd6ddb727ed498c91e7070914c9ed0d80af680ff42b3e",
+		AccountIdentifier: &types.AccountIdentifier{Address: "addr2"},
+		CurveType:         types.Secp256k1,
+	}
+	acc3 := &PrefundedAccount{
+		PrivateKeyHex:     "01ea48249742650907004331e85536f868e2d3959434ba751d8aa230138a9707",
+		AccountIdentifier: &types.AccountIdentifier{Address: "addr3"},
+		CurveType:         types.Secp256r1,
+	}
+
+	t.Run("import accounts", func(t *testing.T) {
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{acc1, acc2})
+		assert.NoError(t, err)
+
+		accounts, err := k.GetAllAccounts(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*types.AccountIdentifier{
+			acc1.AccountIdentifier,
+			acc2.AccountIdentifier,
+		}, accounts)
+
+		kp, err := k.Get(ctx, acc2.AccountIdentifier)
+		assert.NoError(t, err)
+		assert.Equal(t, types.Secp256k1, kp.PublicKey.CurveType)
+	})
+
+	t.Run("identical key is a no-op", func(t *testing.T) {
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{acc1, acc1})
+		assert.NoError(t, err)
+
+		accounts, err := k.GetAllAccounts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, accounts, 2)
+	})
+
+	t.Run("conflicting key", func(t *testing.T) {
+		conflict := &PrefundedAccount{
+			PrivateKeyHex:     acc3.PrivateKeyHex,
+			AccountIdentifier: acc1.AccountIdentifier,
+			CurveType:         types.Edwards25519,
+		}
+
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{acc3, conflict})
+		assert.True(t, errors.Is(err, storageErrs.ErrAddrConflict))
+		assert.Contains(t, err.Error(), "prefunded account 1")
+
+		// acc3 should not be stored because the import is atomic
+		_, err = k.Get(ctx, acc3.AccountIdentifier)
+		assert.True(t, errors.Is(err, storageErrs.ErrAddrNotFound))
+	})
+
+	t.Run("invalid private key", func(t *testing.T) {
+		invalid := &PrefundedAccount{
+			PrivateKeyHex:     "hello",
+			AccountIdentifier: &types.AccountIdentifier{Address: "addr4"},
+			CurveType:         types.Secp256k1,
+		}
+
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{invalid})
+		assert.True(t, errors.Is(err, storageErrs.ErrAddrImportFailed))
+		assert.True(t, errors.Is(err, keys.ErrPrivKeyUndecodable))
+		assert.Contains(t, err.Error(), "prefunded account 0")
+	})
+
+	t.Run("out of range private key", func(t *testing.T) {
+		invalid := &PrefundedAccount{
+			PrivateKeyHex:     "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+			AccountIdentifier: &types.AccountIdentifier{Address: "addr4"},
+			CurveType:         types.Secp256k1,
+		}
+
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{invalid})
+		assert.True(t, errors.Is(err, storageErrs.ErrAddrImportFailed))
+		assert.True(t, errors.Is(err, keys.ErrPrivKeyOutOfRange))
+	})
+
+	t.Run("0x prefixed key", func(t *testing.T) {
+		prefixed := &PrefundedAccount{
+			PrivateKeyHex:     "0x" + acc2.PrivateKeyHex,
+			AccountIdentifier: acc2.AccountIdentifier,
+			CurveType:         acc2.CurveType,
+		}
+
+		// The prefixed key is identical to the stored key
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{prefixed})
+		assert.NoError(t, err)
+	})
+
+	t.Run("derived account mismatch", func(t *testing.T) {
+		helper := &mocks.KeyStorageHelper{}
+		k.Initialize(helper)
+		defer k.Initialize(nil)
+
+		helper.On(
+			"Derive",
+			ctx,
+			mock.Anything,
+		).Return(
+			acc3.AccountIdentifier,
+			nil,
+		).Once()
+		helper.On(
+			"Derive",
+			ctx,
+			mock.Anything,
+		).Return(
+			&types.AccountIdentifier{Address: "addr5"},
+			nil,
+		).Once()
+
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{acc3, {
+			PrivateKeyHex:     "17d08f5fe8c77af811caa0c9a187e668ce3b74a99acc3f6d976f075fa8e0be55",
+			AccountIdentifier: &types.AccountIdentifier{Address: "addr4"},
+			CurveType:         types.Edwards25519,
+		}})
+		assert.True(t, errors.Is(err, storageErrs.ErrPrefundedAcctMismatch))
+		assert.Contains(t, err.Error(), "prefunded account 1")
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("derive failed", func(t *testing.T) {
+		helper := &mocks.KeyStorageHelper{}
+		k.Initialize(helper)
+		defer k.Initialize(nil)
+
+		helper.On(
+			"Derive",
+			ctx,
+			mock.Anything,
+		).Return(
+			nil,
+			errors.New("unsupported curve"),
+		).Once()
+
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{acc3})
+		assert.True(t, errors.Is(err, storageErrs.ErrPrefundedAcctDeriveFailed))
+		assert.Contains(t, err.Error(), "prefunded account 0")
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("sub-account", func(t *testing.T) {
+		subAccount := &PrefundedAccount{
+			PrivateKeyHex: acc3.PrivateKeyHex,
+			AccountIdentifier: &types.AccountIdentifier{
+				Address:    acc3.AccountIdentifier.Address,
+				SubAccount: &types.SubAccountIdentifier{Address: "staking"},
+			},
+			CurveType: acc3.CurveType,
+		}
+
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{subAccount})
+		assert.True(t, errors.Is(err, storageErrs.ErrPrefundedAcctSubAccount))
+		assert.Contains(t, err.Error(), "prefunded account 0")
+
+		_, err = k.Get(ctx, subAccount.AccountIdentifier)
+		assert.True(t, errors.Is(err, storageErrs.ErrAddrNotFound))
+	})
+
+	t.Run("missing account identifier", func(t *testing.T) {
+		helper := &mocks.KeyStorageHelper{}
+		k.Initialize(helper)
+		defer k.Initialize(nil)
+
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{{
+			PrivateKeyHex: acc3.PrivateKeyHex,
+			CurveType:     acc3.CurveType,
+		}})
+		assert.True(t, errors.Is(err, storageErrs.ErrPrefundedAcctMissingAccount))
+		assert.Contains(t, err.Error(), "prefunded account 0")
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("derived account matches", func(t *testing.T) {
+		helper := &mocks.KeyStorageHelper{}
+		k.Initialize(helper)
+		defer k.Initialize(nil)
+
+		helper.On(
+			"Derive",
+			ctx,
+			mock.Anything,
+		).Return(
+			acc3.AccountIdentifier,
+			nil,
+		).Once()
+
+		err := k.ImportPrefundedAccounts(ctx, []*PrefundedAccount{acc3})
+		assert.NoError(t, err)
+
+		kp, err := k.Get(ctx, acc3.AccountIdentifier)
+		assert.NoError(t, err)
+		assert.Equal(t, types.Secp256r1, kp.PublicKey.CurveType)
+		helper.AssertExpectations(t)
+	})
+}
+
+func TestKeyStorageSignPayloads(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	k := NewKeyStorage(database)
+
+	accounts := []*types.AccountIdentifier{
+		{Address: "addr1"},
+		{Address: "addr2"},
+		{Address: "addr3"},
+	}
+	curves := []types.CurveType{types.Edwards25519, types.Secp256k1, types.Secp256r1}
+	sigTypes := []types.SignatureType{types.Ed25519, types.Ecdsa, types.Ecdsa}
+	for i, account := range accounts {
+		kp, err := keys.GenerateKeypair(curves[i])
+		assert.NoError(t, err)
+		assert.NoError(t, k.Store(ctx, account, kp))
+	}
+
+	payloads := make([]*types.SigningPayload, 30)
+	for i := range payloads {
+		payloads[i] = &types.SigningPayload{
+			AccountIdentifier: accounts[i%len(accounts)],
+			Bytes:             hash(fmt.Sprintf("msg%d", i)),
+			SignatureType:     sigTypes[i%len(accounts)],
+		}
+	}
+
+	t.Run("sign payloads in order", func(t *testing.T) {
+		sigs, err := k.SignPayloads(ctx, payloads)
+		assert.NoError(t, err)
+		assert.Len(t, sigs, len(payloads))
+
+		for i, sig := range sigs {
+			assert.Equal(t, payloads[i], sig.SigningPayload)
+
+			kp, err := k.Get(ctx, payloads[i].AccountIdentifier)
+			assert.NoError(t, err)
+			assert.Equal(t, kp.PublicKey, sig.PublicKey)
+
+			signer, err := kp.Signer()
+			assert.NoError(t, err)
+			assert.NoError(t, signer.Verify(sig))
+		}
+	})
+
+	t.Run("no payloads", func(t *testing.T) {
+		sigs, err := k.SignPayloads(ctx, []*types.SigningPayload{})
+		assert.NoError(t, err)
+		assert.Len(t, sigs, 0)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		missing := &types.AccountIdentifier{Address: "addr4"}
+		invalid := append([]*types.SigningPayload{}, payloads[:5]...)
+		invalid = append(invalid, &types.SigningPayload{
+			AccountIdentifier: missing,
+			Bytes:             hash("msg"),
+			SignatureType:     types.Ed25519,
+		})
+
+		sigs, err := k.SignPayloads(ctx, invalid)
+		assert.Nil(t, sigs)
+		assert.True(t, errors.Is(err, storageErrs.ErrKeyGetFailed))
+
+		var signErr *storageErrs.SignPayloadError
+		assert.True(t, errors.As(err, &signErr))
+		assert.Equal(t, 5, signErr.Index)
+		assert.Equal(t, missing, signErr.Account)
+	})
+
+	t.Run("missing signature type", func(t *testing.T) {
+		invalid := append([]*types.SigningPayload{}, payloads[:2]...)
+		invalid = append(invalid, &types.SigningPayload{
+			AccountIdentifier: accounts[0],
+			Bytes:             hash("msg"),
+		})
+
+		sigs, err := k.SignPayloads(ctx, invalid)
+		assert.Nil(t, sigs)
+		assert.True(t, errors.Is(err, storageErrs.ErrDetermineSigTypeFailed))
+
+		var signErr *storageErrs.SignPayloadError
+		assert.True(t, errors.As(err, &signErr))
+		assert.Equal(t, 2, signErr.Index)
+		assert.Equal(t, accounts[0], signErr.Account)
+	})
+
+	t.Run("unsupported signature type", func(t *testing.T) {
+		invalid := append([]*types.SigningPayload{}, payloads[:3]...)
+		invalid = append(invalid, &types.SigningPayload{
+			AccountIdentifier: accounts[1],
+			Bytes:             hash("msg"),
+			SignatureType:     types.Ed25519,
+		})
+
+		sigs, err := k.SignPayloads(ctx, invalid)
+		assert.Nil(t, sigs)
+		assert.True(t, errors.Is(err, storageErrs.ErrSignPayloadFailed))
+
+		var signErr *storageErrs.SignPayloadError
+		assert.True(t, errors.As(err, &signErr))
+		assert.Equal(t, 3, signErr.Index)
+		assert.Equal(t, accounts[1], signErr.Account)
+	})
+}
+
+// benchmarkKeyStorageSign stores a key for each of
+// 10 accounts and returns KeyStorage with 100 payloads
+// signed by those accounts.
+func benchmarkKeyStorageSign(
+	b *testing.B,
+) (*KeyStorage, []*types.SigningPayload, func()) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(b, err)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(b, err)
+
+	k := NewKeyStorage(database)
+	payloads := make([]*types.SigningPayload, 100)
+	for i := range payloads {
+		account := &types.AccountIdentifier{Address: fmt.Sprintf("addr%d", i%10)}
+		if i < 10 {
+			kp, err := keys.GenerateKeypair(types.Secp256k1)
+			assert.NoError(b, err)
+			assert.NoError(b, k.Store(ctx, account, kp))
+		}
+
+		payloads[i] = &types.SigningPayload{
+			AccountIdentifier: account,
+			Bytes:             hash(fmt.Sprintf("msg%d", i)),
+			SignatureType:     types.Ecdsa,
+		}
+	}
+
+	return k, payloads, func() {
+		database.Close(ctx)
+		utils.RemoveTempDir(newDir)
+	}
+}
+
+func BenchmarkKeyStorageSign(b *testing.B) {
+	ctx := context.Background()
+	k, payloads, cleanup := benchmarkKeyStorageSign(b)
+	defer cleanup()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = k.Sign(ctx, payloads)
+	}
+}
+
+func BenchmarkKeyStorageSignPayloads(b *testing.B) {
+	ctx := context.Background()
+	k, payloads, cleanup := benchmarkKeyStorageSign(b)
+	defer cleanup()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = k.SignPayloads(ctx, payloads)
+	}
+}
+
+type compactionKeyStorageHelper struct {
+	*mocks.KeyStorageHelper
+	*mocks.KeyStorageCompactionHelper
+}
+
+func TestKeyStorageDeleteKey(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	k := NewKeyStorage(database)
+
+	account1 := &types.AccountIdentifier{Address: "addr1"}
+	account2 := &types.AccountIdentifier{Address: "addr2"}
+	for _, account := range []*types.AccountIdentifier{account1, account2} {
+		kp, err := keys.GenerateKeypair(types.Secp256k1)
+		assert.NoError(t, err)
+		assert.NoError(t, k.Store(ctx, account, kp))
+	}
+
+	payload := &types.SigningPayload{
+		AccountIdentifier: account1,
+		Bytes:             hash("msg1"),
+		SignatureType:     types.Ecdsa,
+	}
+
+	t.Run("delete key", func(t *testing.T) {
+		sigs, err := k.Sign(ctx, []*types.SigningPayload{payload})
+		assert.NoError(t, err)
+		assert.Len(t, sigs, 1)
+
+		assert.NoError(t, k.DeleteKey(ctx, account1))
+
+		kp, err := k.Get(ctx, account1)
+		assert.True(t, errors.Is(err, storageErrs.ErrAddrNotFound))
+		assert.Nil(t, kp)
+
+		accounts, err := k.GetAllAccounts(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*types.AccountIdentifier{account2}, accounts)
+	})
+
+	t.Run("sign after delete", func(t *testing.T) {
+		sigs, err := k.Sign(ctx, []*types.SigningPayload{payload})
+		assert.True(t, errors.Is(err, storageErrs.ErrKeyGetFailed))
+		assert.Nil(t, sigs)
+
+		sigs, err = k.SignPayloads(ctx, []*types.SigningPayload{payload})
+		assert.True(t, errors.Is(err, storageErrs.ErrKeyGetFailed))
+		assert.Nil(t, sigs)
+	})
+
+	t.Run("delete missing key", func(t *testing.T) {
+		err := k.DeleteKey(ctx, account1)
+		assert.True(t, errors.Is(err, storageErrs.ErrAddrNotFound))
+	})
+
+	t.Run("store after delete", func(t *testing.T) {
+		kp, err := keys.GenerateKeypair(types.Edwards25519)
+		assert.NoError(t, err)
+		assert.NoError(t, k.Store(ctx, account1, kp))
+
+		stored, err := k.Get(ctx, account1)
+		assert.NoError(t, err)
+		assert.Equal(t, kp, stored)
+	})
+
+	t.Run("compaction hook", func(t *testing.T) {
+		helper := &compactionKeyStorageHelper{
+			KeyStorageHelper:           &mocks.KeyStorageHelper{},
+			KeyStorageCompactionHelper: &mocks.KeyStorageCompactionHelper{},
+		}
+		k.Initialize(helper)
+		defer k.Initialize(nil)
+
+		helper.KeyStorageCompactionHelper.On("CompactKeys", ctx).Return(nil).Once()
+		assert.NoError(t, k.DeleteKey(ctx, account1))
+
+		// The key is deleted even if compaction fails
+		helper.KeyStorageCompactionHelper.On(
+			"CompactKeys",
+			ctx,
+		).Return(
+			errors.New("compaction failed"),
+		).Once()
+		err := k.DeleteKey(ctx, account2)
+		assert.True(t, errors.Is(err, storageErrs.ErrKeyCompactionFailed))
+
+		accounts, err := k.GetAllAccounts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, accounts, 0)
+
+		// Compaction is not attempted if no key is deleted
+		err = k.DeleteKey(ctx, account2)
+		assert.True(t, errors.Is(err, storageErrs.ErrAddrNotFound))
+
+		helper.KeyStorageHelper.AssertExpectations(t)
+		helper.KeyStorageCompactionHelper.AssertExpectations(t)
+	})
+}
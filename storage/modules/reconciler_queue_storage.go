@@ -0,0 +1,153 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	reconcilerQueueNamespace = "reconciler-queue"
+)
+
+func getReconcilerQueueKey(change *parser.BalanceChange) (string, []byte) {
+	return reconcilerQueueNamespace, []byte(
+		fmt.Sprintf("%s/%s", reconcilerQueueNamespace, types.Hash(change)),
+	)
+}
+
+// ReconcilerQueueStorage persists *parser.BalanceChange
+// enqueued for active reconciliation so that they are
+// not lost when the process restarts.
+type ReconcilerQueueStorage struct {
+	db database.Database
+
+	// entryTTL is how long an enqueued change is
+	// retained (0 means until it is dequeued).
+	entryTTL time.Duration
+}
+
+// NewReconcilerQueueStorage returns a new ReconcilerQueueStorage.
+func NewReconcilerQueueStorage(
+	db database.Database,
+	options ...ReconcilerQueueStorageOption,
+) *ReconcilerQueueStorage {
+	r := &ReconcilerQueueStorage{
+		db: db,
+	}
+
+	for _, opt := range options {
+		opt(r)
+	}
+
+	return r
+}
+
+// Enqueue stores a *parser.BalanceChange.
+func (r *ReconcilerQueueStorage) Enqueue(
+	ctx context.Context,
+	change *parser.BalanceChange,
+) error {
+	namespace, key := getReconcilerQueueKey(change)
+	dbTx := r.db.WriteTransaction(ctx, string(key), false)
+	defer dbTx.Discard(ctx)
+
+	bytes, err := r.db.Encoder().Encode(namespace, change)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrReconcilerQueueEncodeFailed, err)
+	}
+
+	if r.entryTTL > 0 {
+		err = dbTx.SetWithTTL(ctx, key, bytes, r.entryTTL, true)
+	} else {
+		err = dbTx.Set(ctx, key, bytes, true)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrReconcilerQueueSetFailed, err)
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrReconcilerQueueCommitFailed, err)
+	}
+
+	return nil
+}
+
+// Dequeue removes a stored *parser.BalanceChange. It is
+// not an error to dequeue a change that is not stored.
+func (r *ReconcilerQueueStorage) Dequeue(
+	ctx context.Context,
+	change *parser.BalanceChange,
+) error {
+	_, key := getReconcilerQueueKey(change)
+	dbTx := r.db.WriteTransaction(ctx, string(key), false)
+	defer dbTx.Discard(ctx)
+
+	if err := dbTx.Delete(ctx, key); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrReconcilerQueueDeleteFailed, err)
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrReconcilerQueueCommitFailed, err)
+	}
+
+	return nil
+}
+
+// Restore returns all stored *parser.BalanceChange
+// sorted by block index.
+func (r *ReconcilerQueueStorage) Restore(
+	ctx context.Context,
+) ([]*parser.BalanceChange, error) {
+	dbTx := r.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	namespace := reconcilerQueueNamespace
+	changes := []*parser.BalanceChange{}
+	_, err := dbTx.Scan(
+		ctx,
+		[]byte(namespace),
+		[]byte(namespace),
+		func(k []byte, v []byte) error {
+			var change parser.BalanceChange
+			// We should not reclaim memory during a scan!!
+			if err := r.db.Encoder().Decode(namespace, v, &change, false); err != nil {
+				return fmt.Errorf("%w: %v", errors.ErrReconcilerQueueDecodeFailed, err)
+			}
+
+			changes = append(changes, &change)
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrReconcilerQueueScanFailed, err)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Block.Index < changes[j].Block.Index
+	})
+
+	return changes, nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"time"
+)
+
+// ReconcilerQueueStorageOption is used to overwrite default values in
+// ReconcilerQueueStorage construction. Any Option not provided
+// falls back to the default value.
+type ReconcilerQueueStorageOption func(r *ReconcilerQueueStorage)
+
+// WithQueueEntryTTL deletes an enqueued *parser.BalanceChange
+// once ttl has elapsed (even if it is never dequeued), so that
+// changes that are no longer worth reconciling are not restored.
+// A value of 0 (the default) means changes are retained until
+// they are dequeued.
+func WithQueueEntryTTL(ttl time.Duration) ReconcilerQueueStorageOption {
+	return func(r *ReconcilerQueueStorage) {
+		r.entryTTL = ttl
+	}
+}
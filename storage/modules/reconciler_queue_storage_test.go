@@ -0,0 +1,120 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+func TestReconcilerQueueStorage(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewReconcilerQueueStorage(database)
+
+	account := &types.AccountIdentifier{Address: "addr 1"}
+	currency := &types.Currency{Symbol: "BTC", Decimals: 8}
+	change1 := &parser.BalanceChange{
+		Account:    account,
+		Currency:   currency,
+		Block:      &types.BlockIdentifier{Index: 2, Hash: "block 2"},
+		Difference: "100",
+	}
+	change2 := &parser.BalanceChange{
+		Account:    account,
+		Currency:   currency,
+		Block:      &types.BlockIdentifier{Index: 1, Hash: "block 1"},
+		Difference: "-10",
+	}
+
+	t.Run("restore empty", func(t *testing.T) {
+		changes, err := storage.Restore(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, changes, 0)
+	})
+
+	t.Run("enqueue", func(t *testing.T) {
+		assert.NoError(t, storage.Enqueue(ctx, change1))
+		assert.NoError(t, storage.Enqueue(ctx, change2))
+
+		// Enqueuing the same change twice should not
+		// result in duplicates.
+		assert.NoError(t, storage.Enqueue(ctx, change1))
+
+		changes, err := storage.Restore(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*parser.BalanceChange{change2, change1}, changes)
+	})
+
+	t.Run("dequeue", func(t *testing.T) {
+		assert.NoError(t, storage.Dequeue(ctx, change2))
+
+		changes, err := storage.Restore(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*parser.BalanceChange{change1}, changes)
+	})
+
+	t.Run("dequeue missing", func(t *testing.T) {
+		assert.NoError(t, storage.Dequeue(ctx, change2))
+	})
+}
+
+func TestReconcilerQueueStorage_TTL(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewReconcilerQueueStorage(database, WithQueueEntryTTL(time.Second))
+
+	change := &parser.BalanceChange{
+		Account:    &types.AccountIdentifier{Address: "addr 1"},
+		Currency:   &types.Currency{Symbol: "BTC", Decimals: 8},
+		Block:      &types.BlockIdentifier{Index: 1, Hash: "block 1"},
+		Difference: "100",
+	}
+	assert.NoError(t, storage.Enqueue(ctx, change))
+
+	changes, err := storage.Restore(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*parser.BalanceChange{change}, changes)
+
+	// BadgerDB stores expiration times in seconds
+	time.Sleep(2 * time.Second)
+
+	changes, err = storage.Restore(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 0)
+}
@@ -17,11 +17,42 @@ package modules
 import (
 	"context"
 	"fmt"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
 	"github.com/coinbase/rosetta-sdk-go/storage/errors"
 )
 
+// Namespaces returns the namespaces of all keys
+// stored by modules. This can be provided to
+// database.WithStatsNamespaces to break down
+// database usage by module.
+func Namespaces() []string {
+	return []string{
+		accountNamespace,
+		balanceNamespace,
+		historicalBalanceNamespace,
+		reconciliationNamepace,
+		pruneNamespace,
+		blockNamespace,
+		blockIndexNamespace,
+		transactionNamespace,
+		transactionBroadcastNamespace,
+		broadcastStatusNamespace,
+		archivedBroadcastNamespace,
+		coinNamespace,
+		coinAccountNamespace,
+		counterNamespace,
+		jobNamespace,
+		jobMetadataNamespace,
+		jobIndexNamespace,
+		keyNamespace,
+		reconcilerQueueNamespace,
+	}
+}
+
 func storeUniqueKey(
 	ctx context.Context,
 	transaction database.Transaction,
@@ -45,9 +76,138 @@ func storeUniqueKey(
 	return transaction.Set(ctx, key, value, reclaimValue)
 }
 
-// newTestBadgerDatabase creates a new Badger Database at the following directory. This is
+// bulkTransaction is a database.Transaction that reads
+// from a read transaction and writes to a database.BulkWriter
+// (so that module logic written for a database.Transaction can
+// be used for bulk loads). Writes to keys that have already been
+// read are cached so that subsequent reads of these keys (like
+// counter updates) include them. Scans never include pending
+// writes.
+type bulkTransaction struct {
+	database.Transaction
+
+	writer  database.BulkWriter
+	read    map[string]struct{}
+	pending map[string]*bulkValue
+	closed  bool
+	lock    sync.Mutex
+}
+
+// bulkValue is a pending write in a bulkTransaction
+// (a nil value indicates the key was deleted).
+type bulkValue struct {
+	value []byte
+}
+
+func newBulkTransaction(
+	readTxn database.Transaction,
+	writer database.BulkWriter,
+) *bulkTransaction {
+	return &bulkTransaction{
+		Transaction: readTxn,
+		writer:      writer,
+		read:        map[string]struct{}{},
+		pending:     map[string]*bulkValue{},
+	}
+}
+
+// Set writes a key to the BulkWriter.
+func (t *bulkTransaction) Set(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	reclaimValue bool,
+) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.read[string(key)]; ok {
+		// The BulkWriter may reclaim value, so
+		// we must cache a copy.
+		cached := make([]byte, len(value))
+		copy(cached, value)
+		t.pending[string(key)] = &bulkValue{value: cached}
+	}
+
+	return t.writer.Set(ctx, key, value, reclaimValue)
+}
+
+// SetWithTTL is not supported by a BulkWriter.
+func (t *bulkTransaction) SetWithTTL(
+	context.Context,
+	[]byte,
+	[]byte,
+	time.Duration,
+	bool,
+) error {
+	return errors.ErrBulkWriteTTLUnsupported
+}
+
+// Get returns the pending value of a key (if
+// it was written) or the value in the database.
+func (t *bulkTransaction) Get(ctx context.Context, key []byte) (bool, []byte, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if pending, ok := t.pending[string(key)]; ok {
+		return pending.value != nil, pending.value, nil
+	}
+
+	t.read[string(key)] = struct{}{}
+	return t.Transaction.Get(ctx, key)
+}
+
+// Delete removes a key with the BulkWriter.
+func (t *bulkTransaction) Delete(ctx context.Context, key []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.read[string(key)]; ok {
+		t.pending[string(key)] = &bulkValue{}
+	}
+
+	return t.writer.Delete(ctx, key)
+}
+
+// Commit flushes the BulkWriter.
+func (t *bulkTransaction) Commit(ctx context.Context) error {
+	defer t.Discard(ctx)
+
+	return t.writer.Flush(ctx)
+}
+
+// Discard cancels the BulkWriter (if it has not
+// been flushed) and discards the read transaction.
+func (t *bulkTransaction) Discard(ctx context.Context) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.closed {
+		return
+	}
+
+	t.closed = true
+	t.writer.Cancel(ctx)
+	t.Transaction.Discard(ctx)
+}
+
+const (
+	// testDatabaseEnv is the environment variable used to
+	// select the database used in module tests. If it is set
+	// to testDatabaseMemory, a MemoryDatabase is used instead
+	// of a BadgerDatabase.
+	testDatabaseEnv    = "ROSETTA_TEST_DATABASE"
+	testDatabaseMemory = "memory"
+)
+
+// newTestDatabase creates a new Badger Database at the following directory
+// (or a Memory Database if ROSETTA_TEST_DATABASE=memory). This is
 // used extensively in module tests.
-func newTestBadgerDatabase(ctx context.Context, dir string) (database.Database, error) {
+func newTestDatabase(ctx context.Context, dir string) (database.Database, error) {
+	if os.Getenv(testDatabaseEnv) == testDatabaseMemory {
+		return database.NewMemoryDatabase(ctx)
+	}
+
 	return database.NewBadgerDatabase(
 		ctx,
 		dir,
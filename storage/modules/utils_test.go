@@ -0,0 +1,95 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+func TestBulkTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	txn := database.Transaction(ctx)
+	assert.NoError(t, txn.Set(ctx, []byte("existing"), []byte("old"), false))
+	assert.NoError(t, txn.Commit(ctx))
+
+	dbTx := newBulkTransaction(database.ReadTransaction(ctx), database.BulkWrite(ctx))
+
+	// Writes to keys that have been read are visible
+	exists, value, err := dbTx.Get(ctx, []byte("existing"))
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, []byte("old"), value)
+
+	assert.NoError(t, dbTx.Set(ctx, []byte("existing"), []byte("new"), true))
+	exists, value, err = dbTx.Get(ctx, []byte("existing"))
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, []byte("new"), value)
+
+	assert.NoError(t, dbTx.Delete(ctx, []byte("existing")))
+	exists, _, err = dbTx.Get(ctx, []byte("existing"))
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// Counters can be updated many times
+	counters := NewCounterStorage(database)
+	for i := 0; i < 3; i++ {
+		_, err := counters.UpdateTransactional(ctx, dbTx, SeenAccounts, big.NewInt(1))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, dbTx.Set(ctx, []byte("new"), []byte("value"), false))
+	err = dbTx.SetWithTTL(ctx, []byte("ttl"), []byte("value"), time.Minute, false)
+	assert.True(t, errors.Is(err, storageErrs.ErrBulkWriteTTLUnsupported))
+
+	assert.NoError(t, dbTx.Commit(ctx))
+
+	// Discarding after commit is a no-op
+	dbTx.Discard(ctx)
+
+	readTxn := database.ReadTransaction(ctx)
+	defer readTxn.Discard(ctx)
+
+	exists, _, err = readTxn.Get(ctx, []byte("existing"))
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, value, err = readTxn.Get(ctx, []byte("new"))
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, []byte("value"), value)
+
+	seen, err := counters.GetTransactional(ctx, readTxn, SeenAccounts)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(3), seen)
+}
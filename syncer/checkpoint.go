@@ -0,0 +1,225 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// canonical returns a boolean indicating if a
+// *types.BlockIdentifier is on the node's canonical chain.
+func (s *Syncer) canonical(
+	ctx context.Context,
+	block *types.BlockIdentifier,
+) (bool, error) {
+	index := block.Index
+	nodeBlock, err := s.helper.Block(
+		ctx,
+		s.network,
+		&types.PartialBlockIdentifier{
+			Index: &index,
+		},
+	)
+	if errors.Is(err, ErrOrphanHead) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w %d: %v", ErrFetchBlockFailed, index, err)
+	}
+
+	if nodeBlock == nil {
+		return false, nil
+	}
+
+	return types.Hash(nodeBlock.BlockIdentifier) == types.Hash(block), nil
+}
+
+// loadOrphanParent populates the past block cache with the
+// parent of an orphaned block if the past block cache is
+// exhausted (ex: after a restart without a PastBlockStorage).
+// The parent is looked up by fetching the orphaned block by
+// hash with the Helper. If the node no longer has the orphaned
+// block, the past block cache is left empty.
+func (s *Syncer) loadOrphanParent(
+	ctx context.Context,
+	orphan *types.BlockIdentifier,
+) error {
+	if len(s.pastBlocks) > 0 {
+		return nil
+	}
+
+	block, err := s.helper.Block(
+		ctx,
+		s.network,
+		types.ConstructPartialBlockIdentifier(orphan),
+	)
+	if errors.Is(err, ErrOrphanHead) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w %d: %v", ErrFetchBlockFailed, orphan.Index, err)
+	}
+
+	if block == nil ||
+		block.ParentBlockIdentifier == nil ||
+		types.Hash(block.BlockIdentifier) != types.Hash(orphan) ||
+		block.ParentBlockIdentifier.Index >= orphan.Index {
+		return nil
+	}
+
+	s.addPastBlock(block.ParentBlockIdentifier)
+	return nil
+}
+
+// resume sets the next index to sync from the last saved
+// checkpoint (if one exists). If the checkpoint is no longer
+// canonical, we walk backwards through past blocks (removing
+// each one) until we find a canonical ancestor. When the past
+// block cache is exhausted, past blocks are loaded from the
+// PastBlockStorage (if provided) or by fetching the parent
+// of each orphaned block with the Helper.
+func (s *Syncer) resume(ctx context.Context) (bool, error) {
+	checkpoint, err := s.checkpointer.LoadCheckpoint(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrLoadCheckpointFailed, err)
+	}
+
+	if checkpoint == nil {
+		return false, nil
+	}
+
+	// Past blocks at or after the checkpoint are replaced
+	// by the checkpoint (they will be synced again).
+	pastBlocks := []*types.BlockIdentifier{}
+	for _, block := range s.pastBlocks {
+		if block.Index < checkpoint.Index {
+			pastBlocks = append(pastBlocks, block)
+		}
+	}
+	s.setPastBlocks(append(pastBlocks, checkpoint))
+	s.lastCheckpoint = checkpoint
+
+	for len(s.pastBlocks) > 0 {
+		lastBlock := s.pastBlocks[len(s.pastBlocks)-1]
+		isCanonical, err := s.canonical(ctx, lastBlock)
+		if err != nil {
+			return false, err
+		}
+
+		if isCanonical {
+			s.nextIndex = lastBlock.Index + 1
+			log.Printf("Resuming sync from checkpoint %s\n", types.PrintStruct(lastBlock))
+			return true, nil
+		}
+
+		if types.Hash(s.genesisBlock) == types.Hash(lastBlock) {
+			return false, ErrCannotRemoveGenesisBlock
+		}
+
+		if err := s.checkReorgDepth(lastBlock); err != nil {
+			return false, err
+		}
+
+		if err := s.handler.BlockRemoved(ctx, lastBlock); err != nil {
+			return false, err
+		}
+
+		s.removedInReorg(lastBlock)
+		s.removeLastPastBlock()
+		s.nextIndex = lastBlock.Index
+		if err := s.loadPastBlock(ctx); err != nil {
+			return false, err
+		}
+
+		if err := s.loadOrphanParent(ctx, lastBlock); err != nil {
+			return false, err
+		}
+
+		if err := s.checkpointRemoved(ctx, lastBlock); err != nil {
+			return false, err
+		}
+	}
+
+	return false, fmt.Errorf(
+		"%w: %s",
+		ErrCheckpointAncestorNotFound,
+		types.PrintStruct(checkpoint),
+	)
+}
+
+// saveCheckpoint saves the last processed block
+// as the checkpoint.
+func (s *Syncer) saveCheckpoint(
+	ctx context.Context,
+	block *types.BlockIdentifier,
+) error {
+	if err := s.checkpointer.SaveCheckpoint(ctx, block); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveCheckpointFailed, err)
+	}
+
+	s.lastCheckpoint = block
+	s.uncheckpointed = 0
+	return nil
+}
+
+// checkpointAdded saves a checkpoint if checkpointInterval
+// blocks have been added since the last checkpoint.
+func (s *Syncer) checkpointAdded(
+	ctx context.Context,
+	block *types.BlockIdentifier,
+) error {
+	if s.checkpointer == nil {
+		return nil
+	}
+
+	s.uncheckpointed++
+	if s.uncheckpointed < s.checkpointInterval {
+		return nil
+	}
+
+	return s.saveCheckpoint(ctx, block)
+}
+
+// checkpointRemoved moves the checkpoint to the
+// last remaining past block if the removed block
+// was at or before the checkpoint.
+func (s *Syncer) checkpointRemoved(
+	ctx context.Context,
+	removed *types.BlockIdentifier,
+) error {
+	if s.checkpointer == nil ||
+		s.lastCheckpoint == nil ||
+		s.lastCheckpoint.Index < removed.Index ||
+		len(s.pastBlocks) == 0 {
+		return nil
+	}
+
+	return s.saveCheckpoint(ctx, s.pastBlocks[len(s.pastBlocks)-1])
+}
+
+// flushCheckpoint saves the last processed block as
+// the checkpoint if it has not already been saved.
+func (s *Syncer) flushCheckpoint(ctx context.Context) error {
+	if s.checkpointer == nil || s.uncheckpointed == 0 || len(s.pastBlocks) == 0 {
+		return nil
+	}
+
+	return s.saveCheckpoint(ctx, s.pastBlocks[len(s.pastBlocks)-1])
+}
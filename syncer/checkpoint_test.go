@@ -0,0 +1,331 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func mockNetworkStatus(mockHelper *mocks.Helper, tip *types.BlockIdentifier) {
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: tip,
+		GenesisBlockIdentifier: &types.BlockIdentifier{
+			Hash:  "block 0",
+			Index: 0,
+		},
+	}, nil)
+}
+
+func mockSyncedBlocks(
+	mockHelper *mocks.Helper,
+	mockHandler *mocks.Handler,
+	blocks []*types.Block,
+) {
+	for _, b := range blocks {
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &b.BlockIdentifier.Index},
+		).Return(
+			b,
+			nil,
+		).Once()
+		mockHandler.On("BlockSeen", mock.Anything, b).Return(nil).Once()
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+	}
+}
+
+func TestSync_ResumeFromCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockCheckpointer := &mocks.Checkpointer{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithCheckpointer(mockCheckpointer, 2),
+	)
+
+	blocks := createBlocks(0, 10, "")
+	mockNetworkStatus(mockHelper, blocks[10].BlockIdentifier)
+
+	// Verify checkpoint is canonical
+	mockCheckpointer.On("LoadCheckpoint", mock.Anything).Return(blocks[5].BlockIdentifier, nil).Once()
+	mockHelper.On(
+		"Block",
+		mock.Anything,
+		networkIdentifier,
+		&types.PartialBlockIdentifier{Index: &blocks[5].BlockIdentifier.Index},
+	).Return(
+		blocks[5],
+		nil,
+	).Once()
+
+	// Only blocks after the checkpoint are synced
+	mockSyncedBlocks(mockHelper, mockHandler, blocks[6:])
+	mockCheckpointer.On("SaveCheckpoint", mock.Anything, blocks[7].BlockIdentifier).Return(nil).Once()
+	mockCheckpointer.On("SaveCheckpoint", mock.Anything, blocks[9].BlockIdentifier).Return(nil).Once()
+
+	// The last block is saved when syncing completes
+	mockCheckpointer.On("SaveCheckpoint", mock.Anything, blocks[10].BlockIdentifier).Return(nil).Once()
+
+	err := syncer.Sync(ctx, -1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[10].BlockIdentifier, syncer.lastCheckpoint)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockCheckpointer.AssertExpectations(t)
+}
+
+func TestSync_ResumeFromCheckpointReorg(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	blocks := createBlocks(0, 5, "")
+	newBlocks := createBlocks(4, 8, "other")
+	newBlocks[0].ParentBlockIdentifier = blocks[3].BlockIdentifier
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockCheckpointer := &mocks.Checkpointer{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithCheckpointer(mockCheckpointer, 1),
+		WithPastBlocks([]*types.BlockIdentifier{
+			blocks[2].BlockIdentifier,
+			blocks[3].BlockIdentifier,
+			blocks[4].BlockIdentifier,
+			blocks[5].BlockIdentifier,
+		}),
+	)
+	mockNetworkStatus(mockHelper, newBlocks[4].BlockIdentifier)
+
+	// The checkpoint and its parent were orphaned while
+	// the syncer was stopped, so we walk backwards until
+	// we find a canonical block.
+	mockCheckpointer.On("LoadCheckpoint", mock.Anything).Return(blocks[5].BlockIdentifier, nil).Once()
+	mockHelper.On(
+		"Block",
+		mock.Anything,
+		networkIdentifier,
+		&types.PartialBlockIdentifier{Index: &blocks[5].BlockIdentifier.Index},
+	).Return(
+		newBlocks[1],
+		nil,
+	).Once()
+	mockHandler.On("BlockRemoved", mock.Anything, blocks[5].BlockIdentifier).Return(nil).Once()
+	mockCheckpointer.On("SaveCheckpoint", mock.Anything, blocks[4].BlockIdentifier).Return(nil).Once()
+
+	mockHelper.On(
+		"Block",
+		mock.Anything,
+		networkIdentifier,
+		&types.PartialBlockIdentifier{Index: &blocks[4].BlockIdentifier.Index},
+	).Return(
+		newBlocks[0],
+		nil,
+	).Once()
+	mockHandler.On("BlockRemoved", mock.Anything, blocks[4].BlockIdentifier).Return(nil).Once()
+	mockCheckpointer.On("SaveCheckpoint", mock.Anything, blocks[3].BlockIdentifier).Return(nil).Once()
+
+	mockHelper.On(
+		"Block",
+		mock.Anything,
+		networkIdentifier,
+		&types.PartialBlockIdentifier{Index: &blocks[3].BlockIdentifier.Index},
+	).Return(
+		blocks[3],
+		nil,
+	).Once()
+
+	// Sync resumes after the canonical ancestor
+	mockSyncedBlocks(mockHelper, mockHandler, newBlocks)
+	for _, b := range newBlocks {
+		mockCheckpointer.On("SaveCheckpoint", mock.Anything, b.BlockIdentifier).Return(nil).Once()
+	}
+
+	err := syncer.Sync(ctx, -1, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, newBlocks[4].BlockIdentifier, syncer.lastCheckpoint)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockCheckpointer.AssertExpectations(t)
+}
+
+func TestSync_ResumeFromCheckpointReorgAfterRestart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	blocks := createBlocks(0, 5, "")
+	newBlocks := createBlocks(4, 8, "other")
+	newBlocks[0].ParentBlockIdentifier = blocks[3].BlockIdentifier
+
+	// The syncer has no past blocks (it was restarted)
+	// and no PastBlockStorage.
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockCheckpointer := &mocks.Checkpointer{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithCheckpointer(mockCheckpointer, 1),
+	)
+	mockNetworkStatus(mockHelper, newBlocks[4].BlockIdentifier)
+
+	// The checkpoint and its parent were orphaned while the
+	// syncer was stopped, so we walk backwards by fetching
+	// each orphaned block by hash to find its parent.
+	mockCheckpointer.On("LoadCheckpoint", mock.Anything).Return(blocks[5].BlockIdentifier, nil).Once()
+	for i, orphan := range []*types.Block{blocks[5], blocks[4]} {
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &orphan.BlockIdentifier.Index},
+		).Return(
+			newBlocks[1-i],
+			nil,
+		).Once()
+		mockHandler.On("BlockRemoved", mock.Anything, orphan.BlockIdentifier).Return(nil).Once()
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			types.ConstructPartialBlockIdentifier(orphan.BlockIdentifier),
+		).Return(
+			orphan,
+			nil,
+		).Once()
+		mockCheckpointer.On(
+			"SaveCheckpoint",
+			mock.Anything,
+			orphan.ParentBlockIdentifier,
+		).Return(nil).Once()
+	}
+
+	mockHelper.On(
+		"Block",
+		mock.Anything,
+		networkIdentifier,
+		&types.PartialBlockIdentifier{Index: &blocks[3].BlockIdentifier.Index},
+	).Return(
+		blocks[3],
+		nil,
+	).Once()
+
+	// Sync resumes after the canonical ancestor
+	mockSyncedBlocks(mockHelper, mockHandler, newBlocks)
+	for _, b := range newBlocks {
+		mockCheckpointer.On("SaveCheckpoint", mock.Anything, b.BlockIdentifier).Return(nil).Once()
+	}
+
+	err := syncer.Sync(ctx, -1, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, newBlocks[4].BlockIdentifier, syncer.lastCheckpoint)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockCheckpointer.AssertExpectations(t)
+}
+
+func TestSync_ResumeFromCheckpointNoAncestor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	blocks := createBlocks(0, 5, "")
+	newBlocks := createBlocks(5, 5, "other")
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockCheckpointer := &mocks.Checkpointer{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithCheckpointer(mockCheckpointer, 1),
+	)
+	mockNetworkStatus(mockHelper, newBlocks[0].BlockIdentifier)
+
+	mockCheckpointer.On("LoadCheckpoint", mock.Anything).Return(blocks[5].BlockIdentifier, nil).Once()
+	mockHelper.On(
+		"Block",
+		mock.Anything,
+		networkIdentifier,
+		&types.PartialBlockIdentifier{Index: &blocks[5].BlockIdentifier.Index},
+	).Return(
+		newBlocks[0],
+		nil,
+	).Once()
+	mockHandler.On("BlockRemoved", mock.Anything, blocks[5].BlockIdentifier).Return(nil).Once()
+
+	// The node no longer has the orphaned block, so
+	// its parent cannot be determined.
+	mockHelper.On(
+		"Block",
+		mock.Anything,
+		networkIdentifier,
+		types.ConstructPartialBlockIdentifier(blocks[5].BlockIdentifier),
+	).Return(
+		nil,
+		nil,
+	).Once()
+
+	err := syncer.Sync(ctx, -1, 5)
+	assert.True(t, errors.Is(err, ErrCheckpointAncestorNotFound))
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockCheckpointer.AssertExpectations(t)
+}
+
+func TestSync_NoCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockCheckpointer := &mocks.Checkpointer{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithCheckpointer(mockCheckpointer, 10),
+	)
+
+	blocks := createBlocks(0, 3, "")
+	mockNetworkStatus(mockHelper, blocks[3].BlockIdentifier)
+	mockCheckpointer.On("LoadCheckpoint", mock.Anything).Return(nil, nil).Once()
+	mockSyncedBlocks(mockHelper, mockHandler, blocks)
+	mockCheckpointer.On("SaveCheckpoint", mock.Anything, blocks[3].BlockIdentifier).Return(nil).Once()
+
+	err := syncer.Sync(ctx, -1, 3)
+	assert.NoError(t, err)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockCheckpointer.AssertExpectations(t)
+}
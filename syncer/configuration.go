@@ -23,7 +23,10 @@ import (
 // falls back to the default value.
 type Option func(s *Syncer)
 
-// WithCacheSize overrides the default cache size.
+// WithCacheSize overrides the default cache size. Concurrency
+// is adjusted to fit fetched blocks in the cache size and blocks
+// after the next index to process are not fetched while fetched
+// blocks waiting to be processed exceed it.
 func WithCacheSize(cacheSize int) Option {
 	return func(s *Syncer) {
 		s.cacheSize = cacheSize
@@ -52,6 +55,60 @@ func WithPastBlockLimit(blocks int) Option {
 	}
 }
 
+// WithPastBlockCacheSize evicts the oldest past blocks
+// when they occupy more than cacheSize bytes, retaining
+// at least minBlocks past blocks.
+func WithPastBlockCacheSize(cacheSize int, minBlocks int) Option {
+	return func(s *Syncer) {
+		s.pastBlockCacheSize = cacheSize
+		s.minPastBlocks = minBlocks
+	}
+}
+
+// WithPastBlockStorage provides the syncer with a
+// PastBlockStorage to handle reorgs deeper than
+// the past block cache.
+func WithPastBlockStorage(storage PastBlockStorage) Option {
+	return func(s *Syncer) {
+		s.pastBlockStorage = storage
+	}
+}
+
+// WithOmittedHandler provides the syncer with an
+// OmittedHandler to notify when a block is omitted.
+func WithOmittedHandler(handler OmittedHandler) Option {
+	return func(s *Syncer) {
+		s.omittedHandler = handler
+	}
+}
+
+// WithTipHandler provides the syncer with a
+// TipHandler to notify when tip is reached.
+func WithTipHandler(handler TipHandler) Option {
+	return func(s *Syncer) {
+		s.tipHandler = handler
+	}
+}
+
+// WithBehindTipThreshold overrides the default number
+// of blocks the syncer must fall behind tip before the
+// TipHandler is notified again when it catches up.
+func WithBehindTipThreshold(blocks int64) Option {
+	return func(s *Syncer) {
+		s.behindTipThreshold = blocks
+	}
+}
+
+// WithMaxReorgDepth overrides the default max reorg
+// depth (the maximum number of blocks removed while
+// handling a single reorg). A depth <= 0 disables
+// the limit.
+func WithMaxReorgDepth(depth int64) Option {
+	return func(s *Syncer) {
+		s.maxReorgDepth = depth
+	}
+}
+
 // WithMaxConcurrency overrides the default max concurrency.
 func WithMaxConcurrency(concurrency int64) Option {
 	return func(s *Syncer) {
@@ -59,9 +116,50 @@ func WithMaxConcurrency(concurrency int64) Option {
 	}
 }
 
+// WithConcurrency overrides the default number of
+// blocks fetched concurrently at the start of each sync
+// range. Concurrency is still adjusted while syncing
+// (never exceeding max concurrency or the cache size).
+func WithConcurrency(concurrency int64) Option {
+	return func(s *Syncer) {
+		s.startingConcurrency = concurrency
+	}
+}
+
 // WithAdjustmentWindow overrides the default adjustment window.
 func WithAdjustmentWindow(adjustmentWindow int64) Option {
 	return func(s *Syncer) {
 		s.adjustmentWindow = adjustmentWindow
 	}
 }
+
+// WithCheckpointer resumes syncing from the last checkpoint
+// saved by the Checkpointer when no start index is provided.
+// A checkpoint is saved every interval processed blocks (and
+// when syncing completes). Blocks processed after the last
+// checkpoint are processed again after a restart.
+func WithCheckpointer(checkpointer Checkpointer, interval int64) Option {
+	return func(s *Syncer) {
+		s.checkpointer = checkpointer
+		if interval > 0 {
+			s.checkpointInterval = interval
+		}
+	}
+}
+
+// WithObserver provides the syncer with an
+// Observer to notify of sync progress.
+func WithObserver(observer Observer) Option {
+	return func(s *Syncer) {
+		s.observer = observer
+	}
+}
+
+// WithEndCondition stops syncing once the EndCondition
+// is reached (in addition to any end index provided
+// to Sync).
+func WithEndCondition(endCondition EndCondition) Option {
+	return func(s *Syncer) {
+		s.endCondition = endCondition
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Status is provided to an EndCondition to
+// determine if syncing should stop.
+type Status struct {
+	// NextIndex is the next index to sync.
+	NextIndex int64
+
+	// LastBlock is the last processed block
+	// (nil if no block has been processed).
+	LastBlock *types.BlockIdentifier
+
+	// LastBlockTimestamp is the timestamp (in milliseconds)
+	// of LastBlock. It is 0 if LastBlock was not added
+	// by the syncer (i.e. after a block is removed).
+	LastBlockTimestamp int64
+
+	// Tip is the last observed tip.
+	Tip *types.BlockIdentifier
+}
+
+// EndCondition determines when the syncer should stop.
+// Reached is invoked after each processed block and each
+// time the syncer checks for new blocks while at tip.
+type EndCondition interface {
+	Reached(ctx context.Context, status *Status) (bool, error)
+}
+
+// EndAtIndex returns an EndCondition that is reached
+// once index has been processed.
+func EndAtIndex(index int64) EndCondition {
+	return &endAtIndex{index: index}
+}
+
+type endAtIndex struct {
+	index int64
+}
+
+func (e *endAtIndex) Reached(ctx context.Context, status *Status) (bool, error) {
+	return status.NextIndex > e.index, nil
+}
+
+// EndAtHash returns an EndCondition that is reached
+// once block has been processed. If block is orphaned
+// before it is processed, syncing continues.
+func EndAtHash(block *types.BlockIdentifier) EndCondition {
+	return &endAtHash{block: block}
+}
+
+type endAtHash struct {
+	block *types.BlockIdentifier
+}
+
+func (e *endAtHash) Reached(ctx context.Context, status *Status) (bool, error) {
+	if status.LastBlock == nil {
+		return false, nil
+	}
+
+	return types.Hash(status.LastBlock) == types.Hash(e.block), nil
+}
+
+// EndAtTip returns an EndCondition that is reached once
+// the syncer has been at tip for consecutive checks. If
+// staleness is non-zero, the syncer is only considered at
+// tip if the last block timestamp is within staleness of
+// the current time.
+func EndAtTip(consecutive int, staleness time.Duration) EndCondition {
+	return &endAtTip{
+		consecutive: consecutive,
+		staleness:   staleness,
+		now:         time.Now,
+	}
+}
+
+type endAtTip struct {
+	consecutive int
+	staleness   time.Duration
+
+	// now is used to determine block staleness
+	// (overridden in tests).
+	now func() time.Time
+
+	atTip int
+}
+
+func (e *endAtTip) Reached(ctx context.Context, status *Status) (bool, error) {
+	if !e.isAtTip(status) {
+		e.atTip = 0
+		return false, nil
+	}
+
+	e.atTip++
+	return e.atTip >= e.consecutive, nil
+}
+
+func (e *endAtTip) isAtTip(status *Status) bool {
+	if status.LastBlock == nil ||
+		status.Tip == nil ||
+		status.LastBlock.Index < status.Tip.Index {
+		return false
+	}
+
+	if e.staleness == 0 {
+		return true
+	}
+
+	if status.LastBlockTimestamp == 0 {
+		return false
+	}
+
+	lastBlockTime := time.Unix(0, status.LastBlockTimestamp*int64(time.Millisecond))
+	return e.now().Sub(lastBlockTime) <= e.staleness
+}
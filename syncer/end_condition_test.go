@@ -0,0 +1,272 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestEndAtIndex(t *testing.T) {
+	ctx := context.Background()
+	endCondition := EndAtIndex(10)
+
+	reached, err := endCondition.Reached(ctx, &Status{NextIndex: 10})
+	assert.NoError(t, err)
+	assert.False(t, reached)
+
+	// Reached even if block 10 was omitted
+	reached, err = endCondition.Reached(ctx, &Status{
+		NextIndex: 11,
+		LastBlock: &types.BlockIdentifier{Hash: "block 9", Index: 9},
+	})
+	assert.NoError(t, err)
+	assert.True(t, reached)
+}
+
+func TestEndAtHash(t *testing.T) {
+	ctx := context.Background()
+	endCondition := EndAtHash(&types.BlockIdentifier{Hash: "block 10", Index: 10})
+
+	var tests = map[string]struct {
+		status  *Status
+		reached bool
+	}{
+		"no blocks": {
+			status:  &Status{NextIndex: 0},
+			reached: false,
+		},
+		"before block": {
+			status: &Status{
+				NextIndex: 10,
+				LastBlock: &types.BlockIdentifier{Hash: "block 9", Index: 9},
+			},
+			reached: false,
+		},
+		"orphaned block": {
+			status: &Status{
+				NextIndex: 11,
+				LastBlock: &types.BlockIdentifier{Hash: "block other10", Index: 10},
+			},
+			reached: false,
+		},
+		"after orphaned block": {
+			status: &Status{
+				NextIndex: 12,
+				LastBlock: &types.BlockIdentifier{Hash: "block other11", Index: 11},
+			},
+			reached: false,
+		},
+		"block": {
+			status: &Status{
+				NextIndex: 11,
+				LastBlock: &types.BlockIdentifier{Hash: "block 10", Index: 10},
+			},
+			reached: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			reached, err := endCondition.Reached(ctx, test.status)
+			assert.NoError(t, err)
+			assert.Equal(t, test.reached, reached)
+		})
+	}
+}
+
+func TestEndAtTip(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	nowMillis := now.UnixNano() / int64(time.Millisecond)
+	tip := &types.BlockIdentifier{Hash: "block 10", Index: 10}
+	atTip := &Status{
+		NextIndex:          11,
+		LastBlock:          tip,
+		LastBlockTimestamp: nowMillis - 1000,
+		Tip:                tip,
+	}
+	behindTip := &Status{
+		NextIndex:          10,
+		LastBlock:          &types.BlockIdentifier{Hash: "block 9", Index: 9},
+		LastBlockTimestamp: nowMillis - 1000,
+		Tip:                tip,
+	}
+	staleTip := &Status{
+		NextIndex:          11,
+		LastBlock:          tip,
+		LastBlockTimestamp: nowMillis - 60000,
+		Tip:                tip,
+	}
+
+	t.Run("consecutive checks", func(t *testing.T) {
+		endCondition := EndAtTip(3, 0)
+
+		for _, check := range []struct {
+			status  *Status
+			reached bool
+		}{
+			{status: atTip, reached: false},
+			{status: atTip, reached: false},
+			{status: behindTip, reached: false},
+			{status: atTip, reached: false},
+			{status: atTip, reached: false},
+			{status: atTip, reached: true},
+		} {
+			reached, err := endCondition.Reached(ctx, check.status)
+			assert.NoError(t, err)
+			assert.Equal(t, check.reached, reached)
+		}
+	})
+
+	t.Run("staleness", func(t *testing.T) {
+		endCondition := EndAtTip(2, 10*time.Second)
+		endCondition.(*endAtTip).now = func() time.Time {
+			return now
+		}
+
+		for _, check := range []struct {
+			status  *Status
+			reached bool
+		}{
+			{status: atTip, reached: false},
+			{status: staleTip, reached: false},
+			{status: atTip, reached: false},
+			{status: &Status{LastBlock: tip, Tip: tip}, reached: false},
+			{status: atTip, reached: false},
+			{status: atTip, reached: true},
+		} {
+			reached, err := endCondition.Reached(ctx, check.status)
+			assert.NoError(t, err)
+			assert.Equal(t, check.reached, reached)
+		}
+	})
+}
+
+func TestSync_EndAtHash(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	blocks := createBlocks(0, 100, "")
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithEndCondition(EndAtHash(blocks[5].BlockIdentifier)),
+	)
+	mockNetworkStatus(mockHelper, blocks[100].BlockIdentifier)
+
+	for _, b := range blocks {
+		// Blocks after the end condition may be
+		// prefetched but are never added.
+		times := 1
+		if b.BlockIdentifier.Index > 5 {
+			times = 0
+		}
+
+		call := mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &b.BlockIdentifier.Index},
+		).Return(
+			b,
+			nil,
+		)
+		seen := mockHandler.On("BlockSeen", mock.Anything, b).Return(nil)
+		if times == 0 {
+			call.Maybe()
+			seen.Maybe()
+			continue
+		}
+
+		call.Once()
+		seen.Once()
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+	}
+
+	err := syncer.Sync(ctx, -1, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), syncer.nextIndex)
+	assert.Equal(t, int64(0), syncer.concurrency)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestSync_EndAtHashOrphaned(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	blocks := createBlocks(0, 5, "")
+	newBlocks := createBlocks(5, 10, "other")
+	newBlocks[0].ParentBlockIdentifier = blocks[4].BlockIdentifier
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithEndCondition(EndAtHash(blocks[5].BlockIdentifier)),
+	)
+	mockNetworkStatus(mockHelper, newBlocks[5].BlockIdentifier)
+
+	// Block 5 was orphaned so we sync to the
+	// provided end index instead.
+	mockSyncedBlocks(mockHelper, mockHandler, blocks[:5])
+	mockSyncedBlocks(mockHelper, mockHandler, newBlocks)
+
+	err := syncer.Sync(ctx, -1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), syncer.nextIndex)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestSync_EndAtTip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithEndCondition(EndAtTip(2, 0)),
+	)
+
+	blocks := createBlocks(0, 3, "")
+	mockNetworkStatus(mockHelper, blocks[3].BlockIdentifier)
+	mockSyncedBlocks(mockHelper, mockHandler, blocks)
+
+	// The first check at tip occurs after block 3 is
+	// processed and the second occurs when there are
+	// no more blocks to sync.
+	err := syncer.Sync(ctx, -1, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), syncer.nextIndex)
+	mockHelper.AssertNumberOfCalls(t, "NetworkStatus", 3)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
@@ -16,8 +16,10 @@ package syncer
 
 import (
 	"errors"
+	"fmt"
 
 	utils "github.com/coinbase/rosetta-sdk-go/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 // Named error types for Syncer errors
@@ -43,6 +45,32 @@ var (
 	// result is nil.
 	ErrBlockResultNil = errors.New("block result is nil")
 
+	// ErrCheckpointAncestorNotFound is returned when the
+	// checkpoint is no longer canonical and none of the
+	// past blocks are canonical either.
+	ErrCheckpointAncestorNotFound = errors.New("unable to find canonical ancestor of checkpoint")
+
+	// ErrReorgTooDeep is returned (as a *ReorgTooDeepError)
+	// when handling a reorg would remove more than the max
+	// reorg depth.
+	ErrReorgTooDeep = errors.New("reorg too deep")
+
+	// ErrSyncerShutdown is returned by Sync when
+	// the syncer has already been shut down.
+	ErrSyncerShutdown = errors.New("syncer is shut down")
+
+	// ErrShutdownDeadlineExceeded is returned by Shutdown
+	// when in-flight work could not be drained before the
+	// provided context was done. In this case, syncing is
+	// aborted.
+	ErrShutdownDeadlineExceeded = errors.New("unable to drain syncer before shutdown deadline")
+
+	ErrEndConditionFailed          = errors.New("unable to evaluate end condition")
+	ErrBlockOmittedFailed          = errors.New("unable to handle omitted block")
+	ErrTipReachedFailed            = errors.New("unable to handle tip reached")
+	ErrLoadCheckpointFailed        = errors.New("unable to load checkpoint")
+	ErrLoadPastBlockFailed         = errors.New("unable to load past block")
+	ErrSaveCheckpointFailed        = errors.New("unable to save checkpoint")
 	ErrGetCurrentHeadBlockFailed   = errors.New("unable to get current head")
 	ErrGetNetworkStatusFailed      = errors.New("unable to get network status")
 	ErrFetchBlockFailed            = errors.New("unable to fetch block")
@@ -61,6 +89,16 @@ func Err(err error) bool {
 		ErrOutOfOrder,
 		ErrOrphanHead,
 		ErrBlockResultNil,
+		ErrCheckpointAncestorNotFound,
+		ErrReorgTooDeep,
+		ErrSyncerShutdown,
+		ErrShutdownDeadlineExceeded,
+		ErrEndConditionFailed,
+		ErrBlockOmittedFailed,
+		ErrTipReachedFailed,
+		ErrLoadCheckpointFailed,
+		ErrLoadPastBlockFailed,
+		ErrSaveCheckpointFailed,
 		ErrGetCurrentHeadBlockFailed,
 		ErrGetNetworkStatusFailed,
 		ErrFetchBlockFailed,
@@ -73,3 +111,56 @@ func Err(err error) bool {
 
 	return utils.FindError(syncerErrors, err)
 }
+
+// ReorgTooDeepError is returned when handling a reorg
+// would remove more than the max reorg depth. It can be
+// inspected using errors.As (errors.Is(err, ErrReorgTooDeep)
+// also returns true).
+type ReorgTooDeepError struct {
+	// MaxDepth is the max reorg depth.
+	MaxDepth int64
+
+	// BlockToRemove is the block that would have been
+	// removed to exceed MaxDepth. The fork point (the last
+	// block shared with the remote chain) is not known, it
+	// is before this block.
+	BlockToRemove *types.BlockIdentifier
+
+	// LocalTip is the last block processed before
+	// the reorg started.
+	LocalTip *types.BlockIdentifier
+
+	// RemoteTip is the last observed tip.
+	RemoteTip *types.BlockIdentifier
+}
+
+// Error returns a description of the reorg.
+func (e *ReorgTooDeepError) Error() string {
+	return fmt.Sprintf(
+		"%s: removing %s would exceed max depth %d (local tip: %s, remote tip: %s)",
+		ErrReorgTooDeep.Error(),
+		types.PrintStruct(e.BlockToRemove),
+		e.MaxDepth,
+		types.PrintStruct(e.LocalTip),
+		types.PrintStruct(e.RemoteTip),
+	)
+}
+
+// Unwrap returns ErrReorgTooDeep.
+func (e *ReorgTooDeepError) Unwrap() error {
+	return ErrReorgTooDeep
+}
+
+// wrapErr wraps err with sentinel unless err is
+// a *ReorgTooDeepError (which is returned as-is so
+// that callers can inspect it using errors.As) or
+// ErrCheckpointAncestorNotFound (which is returned
+// as-is so that callers can detect it with errors.Is).
+func wrapErr(sentinel error, err error) error {
+	if errors.Is(err, ErrReorgTooDeep) ||
+		errors.Is(err, ErrCheckpointAncestorNotFound) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %v", sentinel, err)
+}
@@ -30,6 +30,10 @@ func TestErr(t *testing.T) {
 			err: ErrCannotRemoveGenesisBlock,
 			is:  true,
 		},
+		"is a typed error": {
+			err: &ReorgTooDeepError{},
+			is:  true,
+		},
 		"not a keys error": {
 			err: errors.New("blah"),
 			is:  false,
@@ -0,0 +1,274 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// defaultObserverQueueSize is the maximum number of
+	// events queued for an Observer. Any events received
+	// when the queue is full are dropped.
+	defaultObserverQueueSize = 1000
+
+	// DefaultProgressWindow is the default rolling window
+	// used by a ProgressObserver to compute the sync rate.
+	DefaultProgressWindow = 60 * time.Second
+)
+
+// BlockTimings are the durations spent on each
+// stage of syncing a block.
+type BlockTimings struct {
+	// Fetch is the time spent fetching the block
+	// from the Helper.
+	Fetch time.Duration
+
+	// Handle is the time spent in Handler.BlockAdded.
+	Handle time.Duration
+
+	// Commit is the time spent saving the
+	// checkpoint (if any).
+	Commit time.Duration
+}
+
+// Observer is notified of sync progress (if provided).
+// Observer callbacks are invoked sequentially on a
+// separate goroutine so that a slow Observer cannot
+// stall syncing. If an Observer falls too far behind,
+// events are dropped.
+type Observer interface {
+	// BlockProcessed is invoked after a block is added.
+	BlockProcessed(block *types.BlockIdentifier, timings *BlockTimings)
+
+	// ReorgDetected is invoked once a reorg is resolved
+	// with the number of blocks removed.
+	ReorgDetected(depth int64)
+
+	// TipReached is invoked whenever the syncer adds
+	// a block at (or past) the last observed tip.
+	TipReached(tip *types.BlockIdentifier)
+}
+
+// startObserver starts a goroutine that invokes queued
+// Observer callbacks (if an Observer is provided). The
+// returned function stops accepting new events (it
+// does not wait for queued events to be handled).
+func (s *Syncer) startObserver() func() {
+	if s.observer == nil {
+		return func() {}
+	}
+
+	events := make(chan func(), s.observerQueueSize)
+	s.observerEvents = events
+	go func() {
+		for event := range events {
+			event()
+		}
+	}()
+
+	return func() {
+		s.observerEvents = nil
+		close(events)
+	}
+}
+
+// observe queues an Observer callback. If the queue is
+// full, the callback is dropped.
+func (s *Syncer) observe(event func(Observer)) {
+	if s.observerEvents == nil {
+		return
+	}
+
+	observer := s.observer
+	select {
+	case s.observerEvents <- func() { event(observer) }:
+	default:
+	}
+}
+
+// observeBlock notifies the Observer (if any) that a
+// block was added (and of any reorg that was resolved
+// or tip that was reached by adding the block).
+func (s *Syncer) observeBlock(block *types.BlockIdentifier, timings *BlockTimings) {
+	if s.reorgDepth > 0 {
+		depth := s.reorgDepth
+		s.observe(func(o Observer) {
+			o.ReorgDetected(depth)
+		})
+	}
+
+	s.observe(func(o Observer) {
+		o.BlockProcessed(block, timings)
+	})
+
+	if s.tip != nil && block.Index >= s.tip.Index {
+		s.observe(func(o Observer) {
+			o.TipReached(block)
+		})
+	}
+}
+
+// Progress is a snapshot of sync progress
+// computed by a ProgressObserver.
+type Progress struct {
+	// LastIndex is the index of the last
+	// processed block (-1 if no block has
+	// been processed).
+	LastIndex int64
+
+	// BlocksPerSecond is the rolling sync rate.
+	BlocksPerSecond float64
+
+	// TimeToTip is the estimated time to sync
+	// to the provided tip. It is nil if there is
+	// not enough information to make an estimate.
+	TimeToTip *time.Duration
+
+	// Reorgs is the number of reorgs observed.
+	Reorgs int64
+}
+
+// progressBucket is the number of blocks
+// processed in a particular second.
+type progressBucket struct {
+	second int64
+	blocks int64
+}
+
+// ProgressObserver is an Observer that aggregates a rolling
+// blocks per second rate (over a window) and estimates the
+// time to reach a remote tip. It is safe to call Progress
+// while syncing.
+type ProgressObserver struct {
+	window time.Duration
+
+	// now is used to get the current time and
+	// can be overridden in tests.
+	now func() time.Time
+
+	lock      sync.Mutex
+	buckets   []*progressBucket
+	start     int64
+	lastIndex int64
+	reorgs    int64
+}
+
+// NewProgressObserver returns a new *ProgressObserver
+// that computes the sync rate over window. If window
+// is less than 1 second, DefaultProgressWindow is used.
+func NewProgressObserver(window time.Duration) *ProgressObserver {
+	if window < time.Second {
+		window = DefaultProgressWindow
+	}
+
+	return &ProgressObserver{
+		window:    window,
+		now:       time.Now,
+		lastIndex: -1,
+	}
+}
+
+// BlockProcessed records the processed block
+// in the current second.
+func (p *ProgressObserver) BlockProcessed(
+	block *types.BlockIdentifier,
+	timings *BlockTimings,
+) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := p.now().Unix()
+	if len(p.buckets) == 0 && p.lastIndex == -1 {
+		p.start = now
+	}
+
+	if n := len(p.buckets); n > 0 && p.buckets[n-1].second == now {
+		p.buckets[n-1].blocks++
+	} else {
+		p.buckets = append(p.buckets, &progressBucket{second: now, blocks: 1})
+	}
+
+	p.lastIndex = block.Index
+	p.prune(now)
+}
+
+// ReorgDetected increments the reorg count.
+func (p *ProgressObserver) ReorgDetected(depth int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.reorgs++
+}
+
+// TipReached is a no-op (the time to tip is
+// computed using the tip provided to Progress).
+func (p *ProgressObserver) TipReached(tip *types.BlockIdentifier) {}
+
+// prune removes any buckets that are
+// outside of the rolling window.
+func (p *ProgressObserver) prune(now int64) {
+	cutoff := now - int64(p.window/time.Second)
+	for len(p.buckets) > 0 && p.buckets[0].second <= cutoff {
+		p.buckets = p.buckets[1:]
+	}
+}
+
+// Progress returns the current *Progress, estimating
+// the time to sync to tip (the remote tip index).
+func (p *ProgressObserver) Progress(tip int64) *Progress {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := p.now().Unix()
+	p.prune(now)
+
+	progress := &Progress{
+		LastIndex: p.lastIndex,
+		Reorgs:    p.reorgs,
+	}
+
+	if p.lastIndex == -1 {
+		return progress
+	}
+
+	// If we started syncing within the window, we only
+	// compute the rate over the elapsed time.
+	seconds := int64(p.window / time.Second)
+	if elapsed := now - p.start + 1; elapsed < seconds {
+		seconds = elapsed
+	}
+
+	blocks := int64(0)
+	for _, bucket := range p.buckets {
+		blocks += bucket.blocks
+	}
+	progress.BlocksPerSecond = float64(blocks) / float64(seconds)
+
+	remaining := tip - p.lastIndex
+	switch {
+	case remaining <= 0:
+		timeToTip := time.Duration(0)
+		progress.TimeToTip = &timeToTip
+	case progress.BlocksPerSecond > 0:
+		timeToTip := time.Duration(float64(remaining) / progress.BlocksPerSecond * float64(time.Second))
+		progress.TimeToTip = &timeToTip
+	}
+
+	return progress
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// observer is an Observer that records
+// all events it receives.
+type observer struct {
+	blocks chan *types.BlockIdentifier
+	reorgs chan int64
+	tips   chan *types.BlockIdentifier
+
+	// release (if not nil) blocks
+	// BlockProcessed until it is closed.
+	release chan struct{}
+}
+
+func newObserver(size int) *observer {
+	return &observer{
+		blocks: make(chan *types.BlockIdentifier, size),
+		reorgs: make(chan int64, size),
+		tips:   make(chan *types.BlockIdentifier, size),
+	}
+}
+
+func (o *observer) BlockProcessed(block *types.BlockIdentifier, timings *BlockTimings) {
+	if o.release != nil {
+		<-o.release
+	}
+
+	if timings == nil || timings.Fetch < 0 || timings.Handle < 0 || timings.Commit < 0 {
+		panic("invalid timings")
+	}
+
+	o.blocks <- block
+}
+
+func (o *observer) ReorgDetected(depth int64) {
+	o.reorgs <- depth
+}
+
+func (o *observer) TipReached(tip *types.BlockIdentifier) {
+	o.tips <- tip
+}
+
+func receive(t *testing.T, c chan *types.BlockIdentifier) *types.BlockIdentifier {
+	select {
+	case b := <-c:
+		return b
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "timed out waiting for observer")
+		return nil
+	}
+}
+
+func TestSync_Observer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	blocks := createBlocks(0, 10, "")
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	o := newObserver(len(blocks))
+	syncer := New(networkIdentifier, mockHelper, mockHandler, cancel, WithObserver(o))
+	mockNetworkStatus(mockHelper, blocks[10].BlockIdentifier)
+	mockSyncedBlocks(mockHelper, mockHandler, blocks)
+
+	err := syncer.Sync(ctx, -1, 10)
+	assert.NoError(t, err)
+
+	for _, b := range blocks {
+		assert.Equal(t, b.BlockIdentifier, receive(t, o.blocks))
+	}
+	assert.Equal(t, blocks[10].BlockIdentifier, receive(t, o.tips))
+	assert.Len(t, o.reorgs, 0)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestProcessBlock_ObserverReorg(t *testing.T) {
+	ctx := context.Background()
+
+	blocks := createBlocks(0, 3, "")
+	newBlocks := createBlocks(2, 4, "other")
+	newBlocks[0].ParentBlockIdentifier = blocks[1].BlockIdentifier
+
+	mockHandler := &mocks.Handler{}
+	o := newObserver(10)
+	syncer := New(networkIdentifier, &mocks.Helper{}, mockHandler, nil, WithObserver(o))
+	syncer.genesisBlock = blocks[0].BlockIdentifier
+	stop := syncer.startObserver()
+	defer stop()
+
+	for _, b := range blocks {
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+		assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: b}))
+		assert.Equal(t, b.BlockIdentifier, receive(t, o.blocks))
+	}
+
+	// Blocks 3 and 2 are removed before the
+	// reorg is resolved.
+	mockHandler.On("BlockRemoved", mock.Anything, blocks[3].BlockIdentifier).Return(nil).Once()
+	mockHandler.On("BlockRemoved", mock.Anything, blocks[2].BlockIdentifier).Return(nil).Once()
+	assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: newBlocks[2]}))
+	assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: newBlocks[1]}))
+	assert.Equal(t, int64(2), syncer.nextIndex)
+
+	syncer.tip = newBlocks[2].BlockIdentifier
+	for _, b := range newBlocks {
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+		assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: b}))
+		assert.Equal(t, b.BlockIdentifier, receive(t, o.blocks))
+	}
+
+	assert.Equal(t, int64(2), <-o.reorgs)
+	assert.Equal(t, newBlocks[2].BlockIdentifier, receive(t, o.tips))
+	assert.Len(t, o.reorgs, 0)
+	assert.Len(t, o.tips, 0)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestSync_SlowObserver(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	blocks := createBlocks(0, 50, "")
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	o := newObserver(len(blocks))
+	o.release = make(chan struct{})
+	syncer := New(networkIdentifier, mockHelper, mockHandler, cancel, WithObserver(o))
+	syncer.observerQueueSize = 5
+	mockNetworkStatus(mockHelper, blocks[50].BlockIdentifier)
+	mockSyncedBlocks(mockHelper, mockHandler, blocks)
+
+	// Syncing should not be stalled by
+	// the blocked observer.
+	err := syncer.Sync(ctx, -1, 50)
+	assert.NoError(t, err)
+	close(o.release)
+
+	// Only queued events are handled (the rest
+	// are dropped).
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, len(o.blocks) > 0)
+	assert.True(t, len(o.blocks) < len(blocks))
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestProgressObserver(t *testing.T) {
+	now := time.Unix(1000, 0)
+	p := NewProgressObserver(10 * time.Second)
+	p.now = func() time.Time { return now }
+
+	// No blocks processed
+	progress := p.Progress(100)
+	assert.Equal(t, int64(-1), progress.LastIndex)
+	assert.Nil(t, progress.TimeToTip)
+
+	// 2 blocks per second for 5 seconds
+	index := int64(0)
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 2; j++ {
+			p.BlockProcessed(&types.BlockIdentifier{Index: index}, &BlockTimings{})
+			index++
+		}
+
+		now = now.Add(time.Second)
+	}
+
+	now = now.Add(-time.Second)
+	progress = p.Progress(109)
+	assert.Equal(t, int64(9), progress.LastIndex)
+	assert.Equal(t, float64(2), progress.BlocksPerSecond)
+	assert.Equal(t, 50*time.Second, *progress.TimeToTip)
+
+	// Blocks outside of the window are not
+	// included in the rate.
+	now = now.Add(8 * time.Second)
+	p.ReorgDetected(2)
+	progress = p.Progress(109)
+	assert.InDelta(t, 0.4, progress.BlocksPerSecond, 0.0001)
+	assert.InDelta(t, 250, progress.TimeToTip.Seconds(), 0.0001)
+	assert.Equal(t, int64(1), progress.Reorgs)
+
+	// At tip
+	progress = p.Progress(9)
+	assert.Equal(t, time.Duration(0), *progress.TimeToTip)
+
+	// No blocks in the window
+	now = now.Add(time.Minute)
+	progress = p.Progress(109)
+	assert.Equal(t, float64(0), progress.BlocksPerSecond)
+	assert.Nil(t, progress.TimeToTip)
+}
+
+func TestProgressObserver_DefaultWindow(t *testing.T) {
+	p := NewProgressObserver(0)
+	assert.Equal(t, DefaultProgressWindow, p.window)
+}
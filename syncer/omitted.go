@@ -0,0 +1,52 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// omitBlock records that the block at nextIndex
+// was omitted, notifies the OmittedHandler (if
+// provided), and increases nextIndex.
+func (s *Syncer) omitBlock(ctx context.Context) error {
+	if s.omittedHandler != nil {
+		if err := s.omittedHandler.BlockOmitted(ctx, s.nextIndex); err != nil {
+			return fmt.Errorf("%w %d: %v", ErrBlockOmittedFailed, s.nextIndex, err)
+		}
+	}
+
+	s.omittedBlocks = append(s.omittedBlocks, s.nextIndex)
+	s.nextIndex++
+	return nil
+}
+
+// omittedPopulated returns a boolean indicating if
+// the parent of a block at nextIndex is in the gap
+// of omitted indexes since the last processed block.
+func (s *Syncer) omittedPopulated(block *types.Block) bool {
+	if block == nil || len(s.omittedBlocks) == 0 {
+		return false
+	}
+
+	if block.BlockIdentifier.Index != s.nextIndex {
+		return false
+	}
+
+	return block.ParentBlockIdentifier.Index >= s.omittedBlocks[0]
+}
@@ -0,0 +1,242 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// omitEveryThird removes every third block (excluding
+// genesis) from blocks and updates the parent of each
+// remaining block to the last remaining block.
+func omitEveryThird(blocks []*types.Block) ([]*types.Block, []int64) {
+	remaining := []*types.Block{}
+	omitted := []int64{}
+	for _, b := range blocks {
+		index := b.BlockIdentifier.Index
+		if index != 0 && index%3 == 0 {
+			omitted = append(omitted, index)
+			continue
+		}
+
+		if len(remaining) > 0 {
+			b.ParentBlockIdentifier = remaining[len(remaining)-1].BlockIdentifier
+		}
+
+		remaining = append(remaining, b)
+	}
+
+	return remaining, omitted
+}
+
+func mockOmittedBlocks(
+	mockHelper *mocks.Helper,
+	mockOmittedHandler *mocks.OmittedHandler,
+	omitted []int64,
+	times int,
+) {
+	for _, index := range omitted {
+		i := index
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &i},
+		).Return(
+			nil,
+			nil,
+		).Times(times)
+		mockOmittedHandler.On("BlockOmitted", mock.Anything, i).Return(nil).Times(times)
+	}
+}
+
+func TestSync_OmittedBlocks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	blocks, omitted := omitEveryThird(createBlocks(0, 12, ""))
+	assert.Equal(t, []int64{3, 6, 9, 12}, omitted)
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockOmittedHandler := &mocks.OmittedHandler{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithOmittedHandler(mockOmittedHandler),
+	)
+	mockNetworkStatus(mockHelper, &types.BlockIdentifier{
+		Hash:  "block 13",
+		Index: 13,
+	})
+	mockSyncedBlocks(mockHelper, mockHandler, blocks)
+	mockOmittedBlocks(mockHelper, mockOmittedHandler, omitted, 1)
+
+	err := syncer.Sync(ctx, -1, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(13), syncer.nextIndex)
+	assert.Equal(t, blocks[len(blocks)-1].BlockIdentifier, lastBlockIdentifier(syncer))
+	assert.Equal(t, []int64{12}, syncer.omittedBlocks)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockOmittedHandler.AssertExpectations(t)
+}
+
+func TestSync_OmittedBlockPopulatedInReorg(t *testing.T) {
+	ctx := context.Background()
+
+	blocks, omitted := omitEveryThird(createBlocks(0, 10, ""))
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockOmittedHandler := &mocks.OmittedHandler{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		func() {},
+		WithOmittedHandler(mockOmittedHandler),
+	)
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: blocks[len(blocks)-1].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}, nil).Times(3)
+	mockSyncedBlocks(mockHelper, mockHandler, blocks)
+	mockOmittedBlocks(mockHelper, mockOmittedHandler, omitted[:2], 1)
+
+	// Index 9 is omitted once in the first sync and twice
+	// in the reorg.
+	mockOmittedBlocks(mockHelper, mockOmittedHandler, omitted[2:], 3)
+
+	err := syncer.Sync(ctx, -1, 10)
+	assert.NoError(t, err)
+
+	// Create a reorg where the previously omitted
+	// index 6 is populated (index 9 is still omitted).
+	newBlocks := createBlocks(6, 11, "other")
+	newBlocks[0].ParentBlockIdentifier = blocks[4].BlockIdentifier // block 5
+	newBlocks[4].ParentBlockIdentifier = newBlocks[2].BlockIdentifier
+	newBlocks = append(newBlocks[:3], newBlocks[4:]...)
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: newBlocks[len(newBlocks)-1].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}, nil)
+
+	// Blocks 8, 10, and 11 are fetched twice (once when we
+	// detect each removal and once after the reorg is resolved).
+	for _, b := range newBlocks {
+		times := 1
+		if b.BlockIdentifier.Index >= 8 {
+			times = 2
+		}
+
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &b.BlockIdentifier.Index},
+		).Return(
+			b,
+			nil,
+		).Times(times)
+		mockHandler.On("BlockSeen", mock.Anything, b).Return(nil).Times(times)
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+	}
+
+	// Blocks 10, 8, and 7 are removed (block 5 is
+	// the common ancestor).
+	for _, b := range blocks[5:] {
+		mockHandler.On("BlockRemoved", mock.Anything, b.BlockIdentifier).Return(nil).Once()
+	}
+
+	err = syncer.Sync(ctx, 11, 11)
+	assert.NoError(t, err)
+	assert.Equal(t, newBlocks[len(newBlocks)-1].BlockIdentifier, lastBlockIdentifier(syncer))
+	assert.Equal(t, int64(12), syncer.nextIndex)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockOmittedHandler.AssertExpectations(t)
+}
+
+func TestProcessBlock_OmittedPopulated(t *testing.T) {
+	ctx := context.Background()
+
+	blocks := createBlocks(0, 4, "")
+	populated := createBlocks(5, 6, "other")
+	populated[0].ParentBlockIdentifier = blocks[4].BlockIdentifier
+
+	mockHandler := &mocks.Handler{}
+	mockOmittedHandler := &mocks.OmittedHandler{}
+	syncer := New(
+		networkIdentifier,
+		&mocks.Helper{},
+		mockHandler,
+		nil,
+		WithOmittedHandler(mockOmittedHandler),
+	)
+	syncer.genesisBlock = blocks[0].BlockIdentifier
+
+	for _, b := range blocks {
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+		assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: b}))
+	}
+
+	t.Run("omitted handler error", func(t *testing.T) {
+		mockOmittedHandler.On("BlockOmitted", mock.Anything, int64(5)).Return(errors.New("bad")).Once()
+		err := syncer.processBlock(ctx, &blockResult{index: 5})
+		assert.True(t, errors.Is(err, ErrBlockOmittedFailed))
+		assert.Equal(t, int64(5), syncer.nextIndex)
+		assert.Len(t, syncer.omittedBlocks, 0)
+	})
+
+	t.Run("omitted block", func(t *testing.T) {
+		mockOmittedHandler.On("BlockOmitted", mock.Anything, int64(5)).Return(nil).Once()
+		err := syncer.processBlock(ctx, &blockResult{index: 5})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(6), syncer.nextIndex)
+		assert.Equal(t, []int64{5}, syncer.omittedBlocks)
+	})
+
+	t.Run("parent of block was omitted", func(t *testing.T) {
+		// The last processed block should not be removed.
+		err := syncer.processBlock(ctx, &blockResult{block: populated[1]})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), syncer.nextIndex)
+		assert.Len(t, syncer.omittedBlocks, 0)
+		assert.Equal(t, blocks[4].BlockIdentifier, lastBlockIdentifier(syncer))
+	})
+
+	t.Run("populated blocks", func(t *testing.T) {
+		for _, b := range populated {
+			mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+			assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: b}))
+		}
+
+		assert.Equal(t, int64(7), syncer.nextIndex)
+		assert.Equal(t, populated[1].BlockIdentifier, lastBlockIdentifier(syncer))
+	})
+
+	mockHandler.AssertExpectations(t)
+	mockOmittedHandler.AssertExpectations(t)
+}
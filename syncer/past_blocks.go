@@ -0,0 +1,116 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// setPastBlocks replaces all past blocks and
+// recomputes the size of the past block cache.
+func (s *Syncer) setPastBlocks(blocks []*types.BlockIdentifier) {
+	size := 0
+	for _, block := range blocks {
+		size += utils.SizeOf(block)
+	}
+
+	s.pastBlocks = blocks
+	atomic.StoreInt64(&s.pastBlockBytes, int64(size))
+	s.evictPastBlocks()
+}
+
+// addPastBlock appends a block to the past block
+// cache and evicts the oldest blocks if the cache
+// exceeds its count or size limit.
+func (s *Syncer) addPastBlock(block *types.BlockIdentifier) {
+	s.pastBlocks = append(s.pastBlocks, block)
+	atomic.AddInt64(&s.pastBlockBytes, int64(utils.SizeOf(block)))
+	s.evictPastBlocks()
+}
+
+// removeLastPastBlock removes the most
+// recent block from the past block cache.
+func (s *Syncer) removeLastPastBlock() {
+	lastBlock := s.pastBlocks[len(s.pastBlocks)-1]
+	s.pastBlocks = s.pastBlocks[:len(s.pastBlocks)-1]
+	atomic.AddInt64(&s.pastBlockBytes, -int64(utils.SizeOf(lastBlock)))
+	atomic.StoreInt64(&s.pastBlockCount, int64(len(s.pastBlocks)))
+}
+
+// lastBlock returns the most recent block in the past
+// block cache (nil if no block has been processed).
+func (s *Syncer) lastBlock() *types.BlockIdentifier {
+	if len(s.pastBlocks) == 0 {
+		return nil
+	}
+
+	return s.pastBlocks[len(s.pastBlocks)-1]
+}
+
+// evictPastBlocks removes the oldest past blocks until
+// there are at most pastBlockLimit blocks and they occupy
+// at most pastBlockCacheSize bytes. minPastBlocks are
+// always retained (regardless of size).
+func (s *Syncer) evictPastBlocks() {
+	for len(s.pastBlocks) > s.pastBlockLimit ||
+		(s.pastBlockCacheSize > 0 &&
+			atomic.LoadInt64(&s.pastBlockBytes) > int64(s.pastBlockCacheSize) &&
+			len(s.pastBlocks) > s.minPastBlocks) {
+		atomic.AddInt64(&s.pastBlockBytes, -int64(utils.SizeOf(s.pastBlocks[0])))
+		s.pastBlocks = s.pastBlocks[1:]
+	}
+
+	atomic.StoreInt64(&s.pastBlockCount, int64(len(s.pastBlocks)))
+}
+
+// loadPastBlock populates the past block cache with
+// the block processed before nextIndex from the
+// PastBlockStorage (if provided). This is only done
+// when the past block cache is empty (i.e. in a reorg
+// deeper than the cache) and a block has been processed
+// before nextIndex.
+func (s *Syncer) loadPastBlock(ctx context.Context) error {
+	if s.pastBlockStorage == nil || len(s.pastBlocks) > 0 {
+		return nil
+	}
+
+	if s.genesisBlock != nil && s.nextIndex <= s.genesisBlock.Index {
+		return nil
+	}
+
+	block, err := s.pastBlockStorage.PastBlock(ctx, s.nextIndex-1)
+	if err != nil {
+		return fmt.Errorf("%w %d: %v", ErrLoadPastBlockFailed, s.nextIndex-1, err)
+	}
+
+	if block == nil {
+		return nil
+	}
+
+	s.addPastBlock(block)
+	return nil
+}
+
+// PastBlockCache returns the number of blocks in the
+// past block cache and their approximate size in bytes.
+// This can be safely called while syncing.
+func (s *Syncer) PastBlockCache() (int, int) {
+	return int(atomic.LoadInt64(&s.pastBlockCount)), int(atomic.LoadInt64(&s.pastBlockBytes))
+}
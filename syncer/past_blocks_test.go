@@ -0,0 +1,199 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// createLargeBlocks creates blocks with artificially
+// large hashes.
+func createLargeBlocks(startIndex int64, endIndex int64, add string, size int) []*types.Block {
+	padding := strings.Repeat("x", size)
+	hash := func(i int64) string {
+		if i == 0 {
+			return "block 0"
+		}
+
+		// The index is padded so that all blocks
+		// (other than genesis) are the same size.
+		return fmt.Sprintf("block %s%02d %s", add, i, padding)
+	}
+
+	blocks := []*types.Block{}
+	for i := startIndex; i <= endIndex; i++ {
+		parentIndex := i - 1
+		if parentIndex < 0 {
+			parentIndex = 0
+		}
+
+		blocks = append(blocks, &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Hash:  hash(i),
+				Index: i,
+			},
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Hash:  hash(parentIndex),
+				Index: parentIndex,
+			},
+		})
+	}
+
+	return blocks
+}
+
+func TestPastBlockEviction(t *testing.T) {
+	ctx := context.Background()
+	blocks := createLargeBlocks(0, 20, "", 1<<20)
+	blockSize := utils.SizeOf(blocks[1].BlockIdentifier)
+	cacheSize := 5*blockSize + blockSize/2
+
+	mockHandler := &mocks.Handler{}
+	syncer := New(
+		networkIdentifier,
+		&mocks.Helper{},
+		mockHandler,
+		nil,
+		WithPastBlockCacheSize(cacheSize, 2),
+	)
+	syncer.genesisBlock = blocks[0].BlockIdentifier
+
+	for _, b := range blocks {
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+		assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: b}))
+
+		count, size := syncer.PastBlockCache()
+		assert.Equal(t, len(syncer.pastBlocks), count)
+		assert.LessOrEqual(t, size, cacheSize)
+	}
+
+	count, size := syncer.PastBlockCache()
+	assert.Equal(t, 5, count)
+	assert.Equal(t, 5*blockSize, size)
+	assert.Equal(t, blocks[20].BlockIdentifier, lastBlockIdentifier(syncer))
+
+	t.Run("minimum blocks retained", func(t *testing.T) {
+		syncer.pastBlockCacheSize = blockSize / 2
+		syncer.evictPastBlocks()
+
+		count, size := syncer.PastBlockCache()
+		assert.Equal(t, 2, count)
+		assert.Equal(t, 2*blockSize, size)
+		assert.Equal(t, []*types.BlockIdentifier{
+			blocks[19].BlockIdentifier,
+			blocks[20].BlockIdentifier,
+		}, syncer.pastBlocks)
+	})
+
+	t.Run("remove block", func(t *testing.T) {
+		mockHandler.On("BlockRemoved", mock.Anything, blocks[20].BlockIdentifier).Return(nil).Once()
+		assert.NoError(t, syncer.processBlock(ctx, &blockResult{orphanHead: true}))
+
+		count, size := syncer.PastBlockCache()
+		assert.Equal(t, 1, count)
+		assert.Equal(t, blockSize, size)
+	})
+
+	mockHandler.AssertExpectations(t)
+}
+
+func TestSync_DeepReorgPastBlockStorage(t *testing.T) {
+	ctx := context.Background()
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockStorage := &mocks.PastBlockStorage{}
+	blocks := createLargeBlocks(0, 10, "", 1<<20)
+	blockSize := utils.SizeOf(blocks[1].BlockIdentifier)
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		func() {},
+		WithPastBlockCacheSize(2*blockSize, 2),
+		WithPastBlockStorage(mockStorage),
+	)
+
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: blocks[10].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}, nil).Times(3)
+	mockSyncedBlocks(mockHelper, mockHandler, blocks)
+
+	err := syncer.Sync(ctx, -1, 10)
+	assert.NoError(t, err)
+	count, size := syncer.PastBlockCache()
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2*blockSize, size)
+
+	// Create a reorg at block 7 (deeper than the
+	// past block cache).
+	newBlocks := createLargeBlocks(7, 14, "other", 1<<20)
+	newBlocks[0].ParentBlockIdentifier = blocks[6].BlockIdentifier
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: newBlocks[7].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}, nil)
+
+	// Blocks prefetched before the reorg are fetched
+	// again, so the number of fetches depends on timing.
+	for _, b := range newBlocks {
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &b.BlockIdentifier.Index},
+		).Return(
+			b,
+			nil,
+		).Maybe()
+		mockHandler.On("BlockSeen", mock.Anything, b).Return(nil).Maybe()
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+	}
+
+	for _, b := range blocks[7:] {
+		mockHandler.On("BlockRemoved", mock.Anything, b.BlockIdentifier).Return(nil).Once()
+	}
+
+	// Blocks evicted from the cache are loaded from storage
+	for _, b := range blocks[6:9] {
+		mockStorage.On("PastBlock", mock.Anything, b.BlockIdentifier.Index).Return(
+			b.BlockIdentifier,
+			nil,
+		).Once()
+	}
+
+	err = syncer.Sync(ctx, 11, 14)
+	assert.NoError(t, err)
+	assert.Equal(t, newBlocks[7].BlockIdentifier, lastBlockIdentifier(syncer))
+	// Blocks on the new chain have larger hashes (and exceed
+	// the cache size) but the minimum blocks are retained.
+	count, size = syncer.PastBlockCache()
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2*utils.SizeOf(newBlocks[1].BlockIdentifier), size)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockStorage.AssertExpectations(t)
+}
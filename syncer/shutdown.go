@@ -0,0 +1,86 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+)
+
+// Shutdown gracefully stops the syncer. No new blocks are
+// fetched, but any block already being processed is fully
+// handled (so that no handler callback is abandoned) and
+// the checkpoint (if any) is persisted before Sync returns.
+//
+// Shutdown blocks until Sync returns or ctx is done. If ctx
+// is done first, syncing is aborted (by canceling the context
+// provided to the Handler) and ErrShutdownDeadlineExceeded
+// is returned. Once shut down, the syncer cannot sync again.
+func (s *Syncer) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		close(s.shutdown)
+	})
+
+	s.syncLock.Lock()
+	syncing, abort := s.syncing, s.abort
+	s.syncLock.Unlock()
+
+	if syncing == nil {
+		return nil
+	}
+
+	select {
+	case <-syncing:
+		return nil
+	case <-ctx.Done():
+		abort()
+		return fmt.Errorf("%w: %v", ErrShutdownDeadlineExceeded, ctx.Err())
+	}
+}
+
+// shuttingDown returns a boolean indicating
+// if Shutdown has been invoked.
+func (s *Syncer) shuttingDown() bool {
+	select {
+	case <-s.shutdown:
+		return true
+	default:
+		return false
+	}
+}
+
+// startSync registers an in-progress sync so that
+// Shutdown can wait for it to drain (or abort it). The
+// returned context should be used for all syncing and
+// the returned function must be invoked when syncing
+// returns.
+func (s *Syncer) startSync(ctx context.Context) (context.Context, func(), error) {
+	s.syncLock.Lock()
+	defer s.syncLock.Unlock()
+
+	if s.shuttingDown() {
+		return nil, nil, ErrSyncerShutdown
+	}
+
+	syncCtx, abort := context.WithCancel(ctx)
+	syncing := make(chan struct{})
+	s.syncing = syncing
+	s.abort = abort
+
+	return syncCtx, func() {
+		abort()
+		close(syncing)
+	}, nil
+}
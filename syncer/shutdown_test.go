@@ -0,0 +1,186 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// mockAvailableBlocks allows (but does not require)
+// the syncer to fetch and see any of blocks.
+func mockAvailableBlocks(
+	mockHelper *mocks.Helper,
+	mockHandler *mocks.Handler,
+	blocks []*types.Block,
+) {
+	for _, b := range blocks {
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &b.BlockIdentifier.Index},
+		).Return(
+			b,
+			nil,
+		).Maybe()
+		mockHandler.On("BlockSeen", mock.Anything, b).Return(nil).Maybe()
+	}
+}
+
+func TestSync_Shutdown(t *testing.T) {
+	ctx := context.Background()
+
+	blocks := createBlocks(0, 100, "")
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockCheckpointer := &mocks.Checkpointer{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		func() {
+			assert.Fail(t, "context should not be canceled on shutdown")
+		},
+		WithCheckpointer(mockCheckpointer, 10),
+	)
+	mockNetworkStatus(mockHelper, blocks[100].BlockIdentifier)
+	mockAvailableBlocks(mockHelper, mockHandler, blocks)
+	mockCheckpointer.On("LoadCheckpoint", mock.Anything).Return(nil, nil).Once()
+
+	for _, b := range blocks[:5] {
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+	}
+
+	// Shutdown while block 5 is being processed. Block 5
+	// should be fully processed before Sync returns and no
+	// other blocks should be added.
+	shutdownErr := make(chan error)
+	mockHandler.On("BlockAdded", mock.Anything, blocks[5]).Return(nil).Run(func(args mock.Arguments) {
+		go func() {
+			shutdownErr <- syncer.Shutdown(ctx)
+		}()
+
+		for !syncer.shuttingDown() {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		assertNotCanceled(t, args)
+	}).Once()
+	mockCheckpointer.On("SaveCheckpoint", mock.Anything, blocks[5].BlockIdentifier).Return(nil).Once()
+
+	err := syncer.Sync(ctx, -1, -1)
+	assert.NoError(t, err)
+	assert.NoError(t, <-shutdownErr)
+
+	// The syncer has fully processed through block 5.
+	assert.Equal(t, int64(6), syncer.nextIndex)
+	assert.Equal(t, blocks[5].BlockIdentifier, lastBlockIdentifier(syncer))
+	assert.Equal(t, blocks[5].BlockIdentifier, syncer.lastCheckpoint)
+
+	// The syncer cannot be used after shutdown.
+	err = syncer.Sync(ctx, -1, -1)
+	assert.True(t, errors.Is(err, ErrSyncerShutdown))
+	assert.NoError(t, syncer.Shutdown(ctx))
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockCheckpointer.AssertExpectations(t)
+}
+
+func TestSync_ShutdownAtTip(t *testing.T) {
+	ctx := context.Background()
+
+	blocks := createBlocks(0, 5, "")
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	syncer := New(networkIdentifier, mockHelper, mockHandler, nil)
+	mockNetworkStatus(mockHelper, blocks[5].BlockIdentifier)
+	mockSyncedBlocks(mockHelper, mockHandler, blocks)
+
+	syncErr := make(chan error)
+	go func() {
+		syncErr <- syncer.Sync(ctx, -1, -1)
+	}()
+
+	// Wait until the syncer is at tip.
+	for {
+		if count, _ := syncer.PastBlockCache(); count == len(blocks) {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.NoError(t, syncer.Shutdown(ctx))
+	assert.NoError(t, <-syncErr)
+	assert.Equal(t, blocks[5].BlockIdentifier, lastBlockIdentifier(syncer))
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestSync_ShutdownDeadline(t *testing.T) {
+	ctx := context.Background()
+
+	blocks := createBlocks(0, 100, "")
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	syncer := New(networkIdentifier, mockHelper, mockHandler, nil)
+	mockNetworkStatus(mockHelper, blocks[100].BlockIdentifier)
+	mockAvailableBlocks(mockHelper, mockHandler, blocks)
+
+	for _, b := range blocks[:5] {
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+	}
+
+	// Block 5 is not processed until the syncer
+	// is aborted.
+	mockHandler.On("BlockAdded", mock.Anything, blocks[5]).Return(
+		context.Canceled,
+	).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Once()
+
+	syncErr := make(chan error)
+	go func() {
+		syncErr <- syncer.Sync(ctx, -1, -1)
+	}()
+
+	for {
+		if count, _ := syncer.PastBlockCache(); count == 5 {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	err := syncer.Shutdown(shutdownCtx)
+	assert.True(t, errors.Is(err, ErrShutdownDeadlineExceeded))
+	assert.Contains(t, (<-syncErr).Error(), context.Canceled.Error())
+
+	// Block 5 was never added.
+	assert.Equal(t, blocks[4].BlockIdentifier, lastBlockIdentifier(syncer))
+	mockHandler.AssertExpectations(t)
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -37,17 +38,24 @@ func New(
 	options ...Option,
 ) *Syncer {
 	s := &Syncer{
-		network:          network,
-		helper:           helper,
-		handler:          handler,
-		concurrency:      DefaultConcurrency,
-		cacheSize:        DefaultCacheSize,
-		maxConcurrency:   DefaultMaxConcurrency,
-		sizeMultiplier:   DefaultSizeMultiplier,
-		cancel:           cancel,
-		pastBlocks:       []*types.BlockIdentifier{},
-		pastBlockLimit:   DefaultPastBlockLimit,
-		adjustmentWindow: DefaultAdjustmentWindow,
+		network:             network,
+		helper:              helper,
+		handler:             handler,
+		concurrency:         DefaultConcurrency,
+		startingConcurrency: DefaultConcurrency,
+		cacheSize:           DefaultCacheSize,
+		maxConcurrency:      DefaultMaxConcurrency,
+		maxReorgDepth:       DefaultMaxReorgDepth,
+		sizeMultiplier:      DefaultSizeMultiplier,
+		cancel:              cancel,
+		pastBlocks:          []*types.BlockIdentifier{},
+		pastBlockLimit:      DefaultPastBlockLimit,
+		minPastBlocks:       DefaultMinPastBlocks,
+		adjustmentWindow:    DefaultAdjustmentWindow,
+		checkpointInterval:  DefaultCheckpointInterval,
+		observerQueueSize:   defaultObserverQueueSize,
+		behindTipThreshold:  DefaultBehindTipThreshold,
+		shutdown:            make(chan struct{}),
 	}
 
 	// Override defaults with any provided options
@@ -55,6 +63,8 @@ func New(
 		opt(s)
 	}
 
+	s.setPastBlocks(s.pastBlocks)
+
 	return s
 }
 
@@ -77,6 +87,17 @@ func (s *Syncer) setStart(
 		return nil
 	}
 
+	if s.checkpointer != nil {
+		resumed, err := s.resume(ctx)
+		if err != nil {
+			return err
+		}
+
+		if resumed {
+			return nil
+		}
+	}
+
 	s.nextIndex = networkStatus.GenesisBlockIdentifier.Index
 	return nil
 }
@@ -104,6 +125,7 @@ func (s *Syncer) nextSyncableRange(
 
 	// Update the syncer's known tip
 	s.tip = networkStatus.CurrentBlockIdentifier
+	s.checkBehindTip()
 
 	if endIndex == -1 || endIndex > networkStatus.CurrentBlockIdentifier.Index {
 		endIndex = networkStatus.CurrentBlockIdentifier.Index
@@ -127,8 +149,13 @@ func (s *Syncer) attemptOrphan(
 }
 
 func (s *Syncer) checkRemove(
+	ctx context.Context,
 	br *blockResult,
 ) (bool, *types.BlockIdentifier, error) {
+	if err := s.loadPastBlock(ctx); err != nil {
+		return false, nil, err
+	}
+
 	if len(s.pastBlocks) == 0 {
 		return false, nil, nil
 	}
@@ -164,40 +191,107 @@ func (s *Syncer) processBlock(
 	if br == nil {
 		return ErrBlockResultNil
 	}
-	// If the block is omitted, increase
-	// index and return.
+	// If the block is omitted, record the
+	// gap, increase index, and return.
 	if br.block == nil && !br.orphanHead {
-		s.nextIndex++
+		return s.omitBlock(ctx)
+	}
+
+	// If the block's parent was previously omitted,
+	// the omitted index has since been populated. We
+	// re-sync from the start of the gap instead of
+	// orphaning the last processed block.
+	if s.omittedPopulated(br.block) {
+		s.nextIndex = s.omittedBlocks[0]
+		s.omittedBlocks = nil
 		return nil
 	}
 
-	shouldRemove, lastBlock, err := s.checkRemove(br)
+	shouldRemove, lastBlock, err := s.checkRemove(ctx, br)
 	if err != nil {
 		return err
 	}
 
 	if shouldRemove {
+		if err := s.checkReorgDepth(lastBlock); err != nil {
+			return err
+		}
+
 		err = s.handler.BlockRemoved(ctx, lastBlock)
 		if err != nil {
 			return err
 		}
-		s.pastBlocks = s.pastBlocks[:len(s.pastBlocks)-1]
+		s.removeLastPastBlock()
+		s.omittedBlocks = nil
 		s.nextIndex = lastBlock.Index
-		return nil
+		s.lastBlockTimestamp = 0
+		s.removedInReorg(lastBlock)
+
+		// Any indexes omitted between the new last block
+		// and the removed block must be synced again (they
+		// may be populated on the new chain).
+		if err := s.loadPastBlock(ctx); err != nil {
+			return err
+		}
+
+		if len(s.pastBlocks) > 0 {
+			s.nextIndex = s.pastBlocks[len(s.pastBlocks)-1].Index + 1
+		}
+
+		return s.checkpointRemoved(ctx, lastBlock)
 	}
 
 	block := br.block
+	handleStart := time.Now()
 	err = s.handler.BlockAdded(ctx, block)
 	if err != nil {
 		return err
 	}
 
-	s.pastBlocks = append(s.pastBlocks, block.BlockIdentifier)
-	if len(s.pastBlocks) > s.pastBlockLimit {
-		s.pastBlocks = s.pastBlocks[1:]
+	commitStart := time.Now()
+	if err := s.checkpointAdded(ctx, block.BlockIdentifier); err != nil {
+		return err
 	}
+
+	s.observeBlock(block.BlockIdentifier, &BlockTimings{
+		Fetch:  br.fetchDuration,
+		Handle: commitStart.Sub(handleStart),
+		Commit: time.Since(commitStart),
+	})
+
+	s.addPastBlock(block.BlockIdentifier)
+	s.omittedBlocks = nil
+	s.reorgDepth = 0
+	s.reorgTip = nil
+	s.lastBlockTimestamp = block.Timestamp
 	s.nextIndex = block.BlockIdentifier.Index + 1
-	return nil
+	return s.tipReached(ctx, block.BlockIdentifier)
+}
+
+// checkReorgDepth returns a *ReorgTooDeepError if
+// removing block would exceed the max reorg depth.
+func (s *Syncer) checkReorgDepth(block *types.BlockIdentifier) error {
+	if s.maxReorgDepth <= 0 || s.reorgDepth < s.maxReorgDepth {
+		return nil
+	}
+
+	return &ReorgTooDeepError{
+		MaxDepth:      s.maxReorgDepth,
+		BlockToRemove: block,
+		LocalTip:      s.reorgTip,
+		RemoteTip:     s.tip,
+	}
+}
+
+// removedInReorg records that block was removed
+// in a reorg.
+func (s *Syncer) removedInReorg(block *types.BlockIdentifier) {
+	if s.reorgDepth == 0 {
+		s.reorgTip = block
+	}
+
+	s.reorgDepth++
+	atomic.AddInt64(&s.reorgs, 1)
 }
 
 // addBlockIndices appends a range of indices (from
@@ -249,6 +343,8 @@ func (s *Syncer) fetchBlockResult(
 	network *types.NetworkIdentifier,
 	index int64,
 ) (*blockResult, error) {
+	reorgs := atomic.LoadInt64(&s.reorgs)
+	fetchStart := time.Now()
 	block, err := s.helper.Block(
 		ctx,
 		network,
@@ -257,7 +353,11 @@ func (s *Syncer) fetchBlockResult(
 		},
 	)
 
-	br := &blockResult{index: index}
+	br := &blockResult{
+		index:         index,
+		reorgs:        reorgs,
+		fetchDuration: time.Since(fetchStart),
+	}
 	switch {
 	case errors.Is(err, ErrOrphanHead):
 		br.orphanHead = true
@@ -296,6 +396,10 @@ func (s *Syncer) fetchBlocks(
 	results chan *blockResult,
 ) error {
 	for b := range blockIndices {
+		if err := s.waitForCache(ctx, b); err != nil {
+			return s.safeExit(err)
+		}
+
 		br, err := s.fetchBlockResult(
 			ctx,
 			network,
@@ -328,6 +432,41 @@ func (s *Syncer) fetchBlocks(
 	return s.safeExit(nil)
 }
 
+// waitForCache blocks until the block at index can be
+// fetched without exceeding the cache size. The next index
+// to process is always fetched so that syncing can make
+// progress.
+func (s *Syncer) waitForCache(ctx context.Context, index int64) error {
+	for {
+		s.cacheLock.Lock()
+		if s.cachedBytes <= s.cacheSize || index <= s.cacheNextIndex {
+			s.cacheLock.Unlock()
+			return nil
+		}
+		changed := s.cacheChanged
+		s.cacheLock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// updateCache records the size of fetched blocks waiting
+// to be processed and the next index to process and wakes
+// any workers waiting in waitForCache.
+func (s *Syncer) updateCache(cachedBytes int, nextIndex int64) {
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+
+	s.cachedBytes = cachedBytes
+	s.cacheNextIndex = nextIndex
+	close(s.cacheChanged)
+	s.cacheChanged = make(chan struct{})
+}
+
 // processBlocks is invoked whenever a new block is fetched. It attempts
 // to process as many blocks as possible.
 func (s *Syncer) processBlocks(
@@ -340,7 +479,10 @@ func (s *Syncer) processBlocks(
 	// if they don't exist in the cache.
 	reorgStart := int64(-1)
 
-	for s.nextIndex <= endIndex {
+	// We stop processing blocks (after the block
+	// currently being processed) if the syncer is
+	// shut down.
+	for s.nextIndex <= endIndex && !s.shuttingDown() {
 		br, exists := cache[s.nextIndex]
 		if !exists {
 			// Wait for more blocks if we aren't
@@ -365,21 +507,63 @@ func (s *Syncer) processBlocks(
 			// will need to make another call to the node
 			// as it is likely in a reorg.
 			delete(cache, s.nextIndex)
+
+			// If the block was prefetched before a reorg
+			// and no longer connects to the last processed
+			// block, it is likely from the orphaned chain. We
+			// re-fetch it instead of orphaning a valid block.
+			if s.stalePrefetch(br) {
+				var err error
+				br, err = s.fetchBlockResult(
+					ctx,
+					s.network,
+					s.nextIndex,
+				)
+				if err != nil {
+					return fmt.Errorf("%w: %v", ErrFetchBlockReorgFailed, err)
+				}
+			}
 		}
 
 		lastProcessed := s.nextIndex
 		if err := s.processBlock(ctx, br); err != nil {
-			return fmt.Errorf("%w: %v", ErrBlockProcessFailed, err)
+			return wrapErr(ErrBlockProcessFailed, err)
 		}
 
 		if s.nextIndex < lastProcessed && reorgStart == -1 {
 			reorgStart = lastProcessed
 		}
+
+		ended, err := s.endReached(ctx)
+		if err != nil {
+			return err
+		}
+
+		if ended {
+			return nil
+		}
 	}
 
 	return nil
 }
 
+// stalePrefetch returns a boolean indicating if a
+// prefetched *blockResult was fetched before the
+// most recent reorg and does not connect to the
+// last processed block.
+func (s *Syncer) stalePrefetch(br *blockResult) bool {
+	if br.reorgs == atomic.LoadInt64(&s.reorgs) {
+		return false
+	}
+
+	if br.block == nil || len(s.pastBlocks) == 0 {
+		return false
+	}
+
+	lastBlock := s.pastBlocks[len(s.pastBlocks)-1]
+	return types.Hash(br.block.ParentBlockIdentifier) != types.Hash(lastBlock)
+}
+
 // blockResult is returned by calls
 // to fetch a particular index. We must
 // use a separate index field in case
@@ -389,6 +573,14 @@ type blockResult struct {
 	index      int64
 	block      *types.Block
 	orphanHead bool
+
+	// reorgs is the number of blocks removed
+	// by the syncer when the fetch started.
+	reorgs int64
+
+	// fetchDuration is the time spent
+	// fetching the block.
+	fetchDuration time.Duration
 }
 
 func (s *Syncer) adjustWorkers() bool {
@@ -479,15 +671,38 @@ func (s *Syncer) sequenceBlocks( // nolint:golint
 	endIndex int64,
 ) error {
 	cache := make(map[int64]*blockResult)
+	cacheSizes := make(map[int64]int)
+	cachedBytes := 0
 	for result := range fetchedBlocks {
+		size := utils.SizeOf(result)
 		cache[result.index] = result
+		cachedBytes += size - cacheSizes[result.index]
+		cacheSizes[result.index] = size
 
 		if err := s.processBlocks(ctx, cache, endIndex); err != nil {
-			return fmt.Errorf("%w: %v", ErrBlocksProcessMultipleFailed, err)
+			return wrapErr(ErrBlocksProcessMultipleFailed, err)
+		}
+
+		// Release the size of any processed blocks
+		// so that workers waiting for cache space
+		// can continue fetching.
+		for index, blockSize := range cacheSizes {
+			if _, ok := cache[index]; !ok {
+				cachedBytes -= blockSize
+				delete(cacheSizes, index)
+			}
+		}
+		s.updateCache(cachedBytes, s.nextIndex)
+
+		// Stop fetching blocks if the end
+		// condition has been reached or the
+		// syncer is shut down.
+		if s.ended || s.shuttingDown() {
+			return nil
 		}
 
 		// Determine if concurrency should be adjusted.
-		s.recentBlockSizes = append(s.recentBlockSizes, utils.SizeOf(result))
+		s.recentBlockSizes = append(s.recentBlockSizes, size)
 		s.lastAdjustment++
 
 		s.concurrencyLock.Lock()
@@ -535,12 +750,16 @@ func (s *Syncer) syncRange(
 	blockIndices := make(chan int64)
 	fetchedBlocks := make(chan *blockResult)
 
-	// Ensure default concurrency is less than max concurrency.
-	startingConcurrency := DefaultConcurrency
+	// Ensure starting concurrency is less than max concurrency.
+	startingConcurrency := s.startingConcurrency
 	if s.maxConcurrency < startingConcurrency {
 		startingConcurrency = s.maxConcurrency
 	}
 
+	if startingConcurrency < MinConcurrency {
+		startingConcurrency = MinConcurrency
+	}
+
 	// Don't create more goroutines than there are blocks
 	// to sync.
 	blocksToSync := endIndex - s.nextIndex + 1
@@ -554,6 +773,9 @@ func (s *Syncer) syncRange(
 	s.doneLoading = false
 	s.concurrency = startingConcurrency
 	s.goalConcurrency = s.concurrency
+	s.cachedBytes = 0
+	s.cacheNextIndex = s.nextIndex
+	s.cacheChanged = make(chan struct{})
 
 	// We create a separate derivative context here instead of
 	// replacing the provided ctx because the context returned
@@ -563,7 +785,23 @@ func (s *Syncer) syncRange(
 	// return immediately if the context is canceled).
 	//
 	// Source: https://godoc.org/golang.org/x/sync/errgroup
-	g, pipelineCtx := errgroup.WithContext(ctx)
+	//
+	// The derivative context is canceled if the end condition
+	// is reached or the syncer is shut down before the entire
+	// range is synced. Blocks are still processed with ctx, so
+	// any in-flight handler invocation is not interrupted.
+	endCtx, endCancel := context.WithCancel(ctx)
+	defer endCancel()
+
+	go func() {
+		select {
+		case <-s.shutdown:
+			endCancel()
+		case <-endCtx.Done():
+		}
+	}()
+
+	g, pipelineCtx := errgroup.WithContext(endCtx)
 	g.Go(func() error {
 		return s.addBlockIndices(pipelineCtx, blockIndices, s.nextIndex, endIndex)
 	})
@@ -592,6 +830,14 @@ func (s *Syncer) syncRange(
 		return err
 	}
 
+	// Stop fetching any remaining blocks and wait for
+	// all goroutines to exit.
+	if s.ended || s.shuttingDown() {
+		endCancel()
+		_ = g.Wait()
+		return nil
+	}
+
 	if err := g.Wait(); err != nil {
 		return fmt.Errorf("%w: unable to sync to %d", err, endIndex)
 	}
@@ -612,17 +858,37 @@ func (s *Syncer) Tip() *types.BlockIdentifier {
 
 // Sync cycles endlessly until there is an error
 // or the requested range is synced. When the requested
-// range is synced, context is canceled.
+// range is synced, context is canceled. If the syncer
+// is shut down (using Shutdown), Sync returns nil
+// after draining any in-flight work (without
+// canceling context).
 func (s *Syncer) Sync(
 	ctx context.Context,
 	startIndex int64,
 	endIndex int64,
 ) error {
+	ctx, done, err := s.startSync(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	defer s.startObserver()()
+
 	if err := s.setStart(ctx, startIndex); err != nil {
-		return fmt.Errorf("%w: %v", ErrSetStartIndexFailed, err)
+		return wrapErr(ErrSetStartIndexFailed, err)
 	}
 
+	s.ended = false
+	s.omittedBlocks = nil
+	s.startCatchUp()
+
+	shutdown := false
 	for {
+		if s.shuttingDown() {
+			shutdown = true
+			break
+		}
+
 		rangeEnd, halt, err := s.nextSyncableRange(
 			ctx,
 			endIndex,
@@ -636,7 +902,26 @@ func (s *Syncer) Sync(
 				break
 			}
 
-			time.Sleep(defaultSyncSleep)
+			// We may already be at tip when we
+			// start syncing (ex: after resuming
+			// from a checkpoint).
+			if err := s.tipReached(ctx, s.lastBlock()); err != nil {
+				return err
+			}
+
+			ended, err := s.endReached(ctx)
+			if err != nil {
+				return err
+			}
+
+			if ended {
+				break
+			}
+
+			select {
+			case <-time.After(defaultSyncSleep):
+			case <-s.shutdown:
+			}
 			continue
 		}
 
@@ -654,6 +939,19 @@ func (s *Syncer) Sync(
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+
+		if s.ended {
+			break
+		}
+	}
+
+	if err := s.flushCheckpoint(ctx); err != nil {
+		return err
+	}
+
+	if shutdown {
+		log.Printf("Shut down syncer after %d\n", s.nextIndex-1)
+		return nil
 	}
 
 	if startIndex == -1 {
@@ -661,6 +959,31 @@ func (s *Syncer) Sync(
 	}
 
 	s.cancel()
-	log.Printf("Finished syncing %d-%d\n", startIndex, endIndex)
+	log.Printf("Finished syncing %d-%d\n", startIndex, s.nextIndex-1)
 	return nil
 }
+
+// endReached returns a boolean indicating if
+// the end condition (if any) has been reached.
+func (s *Syncer) endReached(ctx context.Context) (bool, error) {
+	if s.endCondition == nil {
+		return false, nil
+	}
+
+	if s.ended {
+		return true, nil
+	}
+
+	reached, err := s.endCondition.Reached(ctx, &Status{
+		NextIndex:          s.nextIndex,
+		LastBlock:          s.lastBlock(),
+		LastBlockTimestamp: s.lastBlockTimestamp,
+		Tip:                s.tip,
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrEndConditionFailed, err)
+	}
+
+	s.ended = reached
+	return reached, nil
+}
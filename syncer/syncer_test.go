@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 
 	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
 	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
 )
 
 var (
@@ -201,7 +203,7 @@ func TestProcessBlock(t *testing.T) {
 	syncer.genesisBlock = blockSequence[0].BlockIdentifier
 
 	t.Run("No block exists", func(t *testing.T) {
-		mockHandler.On("BlockAdded", ctx, blockSequence[0]).Return(nil).Once()
+		mockHandler.On("BlockAdded", mock.Anything, blockSequence[0]).Return(nil).Once()
 		assert.Equal(
 			t,
 			[]*types.BlockIdentifier{},
@@ -238,7 +240,7 @@ func TestProcessBlock(t *testing.T) {
 	})
 
 	t.Run("Block exists, no reorg", func(t *testing.T) {
-		mockHandler.On("BlockAdded", ctx, blockSequence[1]).Return(nil).Once()
+		mockHandler.On("BlockAdded", mock.Anything, blockSequence[1]).Return(nil).Once()
 		err := syncer.processBlock(
 			ctx,
 			&blockResult{block: blockSequence[1]},
@@ -257,7 +259,7 @@ func TestProcessBlock(t *testing.T) {
 	})
 
 	t.Run("Orphan block", func(t *testing.T) {
-		mockHandler.On("BlockRemoved", ctx, blockSequence[1].BlockIdentifier).Return(nil).Once()
+		mockHandler.On("BlockRemoved", mock.Anything, blockSequence[1].BlockIdentifier).Return(nil).Once()
 		err := syncer.processBlock(
 			ctx,
 			&blockResult{block: blockSequence[2]},
@@ -271,7 +273,7 @@ func TestProcessBlock(t *testing.T) {
 			syncer.pastBlocks,
 		)
 
-		mockHandler.On("BlockAdded", ctx, blockSequence[3]).Return(nil).Once()
+		mockHandler.On("BlockAdded", mock.Anything, blockSequence[3]).Return(nil).Once()
 		err = syncer.processBlock(
 			ctx,
 			&blockResult{block: blockSequence[3]},
@@ -288,7 +290,7 @@ func TestProcessBlock(t *testing.T) {
 			syncer.pastBlocks,
 		)
 
-		mockHandler.On("BlockAdded", ctx, blockSequence[2]).Return(nil).Once()
+		mockHandler.On("BlockAdded", mock.Anything, blockSequence[2]).Return(nil).Once()
 		err = syncer.processBlock(
 			ctx,
 			&blockResult{block: blockSequence[2]},
@@ -423,7 +425,7 @@ func TestSync_NoReorg(t *testing.T) {
 	assert.Nil(t, syncer.Tip())
 
 	// Force syncer to only get part of the way through the full range
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 200",
 			Index: 200,
@@ -434,7 +436,7 @@ func TestSync_NoReorg(t *testing.T) {
 		},
 	}, nil).Twice()
 
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 1300",
 			Index: 1300,
@@ -515,7 +517,7 @@ func TestSync_SpecificStart(t *testing.T) {
 	mockHandler := &mocks.Handler{}
 	syncer := New(networkIdentifier, mockHelper, mockHandler, cancel)
 
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 1300",
 			Index: 1300,
@@ -576,7 +578,7 @@ func TestSync_Cancel(t *testing.T) {
 	syncer := New(networkIdentifier, mockHelper, mockHandler, cancel)
 
 	// Force syncer to only get part of the way through the full range
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 200",
 			Index: 200,
@@ -587,7 +589,7 @@ func TestSync_Cancel(t *testing.T) {
 		},
 	}, nil).Twice()
 
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 1300",
 			Index: 1300,
@@ -642,7 +644,7 @@ func TestSync_Reorg(t *testing.T) {
 	mockHandler := &mocks.Handler{}
 	syncer := New(networkIdentifier, mockHelper, mockHandler, cancel)
 
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 1300",
 			Index: 1300,
@@ -813,7 +815,7 @@ func TestSync_ManualReorg(t *testing.T) {
 	mockHandler := &mocks.Handler{}
 	syncer := New(networkIdentifier, mockHelper, mockHandler, cancel)
 
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 1300",
 			Index: 1300,
@@ -945,7 +947,7 @@ func TestSync_Dynamic(t *testing.T) {
 	)
 
 	// Force syncer to only get part of the way through the full range
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 1",
 			Index: 1,
@@ -956,7 +958,7 @@ func TestSync_Dynamic(t *testing.T) {
 		},
 	}, nil).Twice()
 
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 1300",
 			Index: 1300,
@@ -1049,7 +1051,7 @@ func TestSync_DynamicOverhead(t *testing.T) {
 	)
 
 	// Force syncer to only get part of the way through the full range
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 1",
 			Index: 1,
@@ -1060,7 +1062,7 @@ func TestSync_DynamicOverhead(t *testing.T) {
 		},
 	}, nil).Twice()
 
-	mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Hash:  "block 1300",
 			Index: 1300,
@@ -1123,3 +1125,319 @@ func TestSync_DynamicOverhead(t *testing.T) {
 	mockHelper.AssertExpectations(t)
 	mockHandler.AssertExpectations(t)
 }
+
+func TestSync_ReorgPrefetched(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithConcurrency(4),
+		WithMaxConcurrency(4),
+	)
+
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: &types.BlockIdentifier{
+			Hash:  "block other20",
+			Index: 20,
+		},
+		GenesisBlockIdentifier: &types.BlockIdentifier{
+			Hash:  "block 0",
+			Index: 0,
+		},
+	}, nil)
+
+	mockBlock := func(b *types.Block, delay time.Duration) {
+		mockHelper.On(
+			"Block",
+			mock.AnythingOfType("*context.cancelCtx"),
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &b.BlockIdentifier.Index},
+		).Return(
+			b,
+			nil,
+		).After(delay).Run(func(args mock.Arguments) {
+			assertNotCanceled(t, args)
+		}).Once()
+	}
+	mockHandlerCall := func(method string, b *types.Block, times int) {
+		mockHandler.On(
+			method,
+			mock.AnythingOfType("*context.cancelCtx"),
+			b,
+		).Return(
+			nil,
+		).Run(func(args mock.Arguments) {
+			assertNotCanceled(t, args)
+		}).Times(times)
+	}
+
+	// The node reorgs at block 10 while we are fetching
+	// block 11 (which is returned last).
+	blocks := createBlocks(0, 12, "")
+	newBlocks := createBlocks(10, 20, "other")
+	newBlocks[0].ParentBlockIdentifier = blocks[9].BlockIdentifier
+
+	for _, b := range blocks[:11] { // [0, 10]
+		mockBlock(b, 0)
+		mockHandlerCall("BlockSeen", b, 1)
+		mockHandlerCall("BlockAdded", b, 1)
+	}
+
+	// Block 11 (on the new chain) is returned after
+	// block 12 (on the old chain) is prefetched.
+	mockBlock(newBlocks[1], 100*time.Millisecond)
+	mockBlock(blocks[12], 0)
+	mockHandlerCall("BlockSeen", blocks[12], 1)
+
+	// Block 10 is orphaned
+	mockHandler.On(
+		"BlockRemoved",
+		mock.AnythingOfType("*context.cancelCtx"),
+		blocks[10].BlockIdentifier,
+	).Return(
+		nil,
+	).Run(func(args mock.Arguments) {
+		assertNotCanceled(t, args)
+	}).Once()
+
+	// Blocks 10 and 11 are fetched during the reorg and the
+	// prefetched block 12 is re-fetched instead of orphaning
+	// block 11.
+	for _, b := range newBlocks[:3] { // [10, 12]
+		mockBlock(b, 0)
+	}
+	mockHandlerCall("BlockSeen", newBlocks[0], 1)
+	mockHandlerCall("BlockSeen", newBlocks[1], 2)
+	mockHandlerCall("BlockSeen", newBlocks[2], 1)
+
+	for _, b := range newBlocks[3:] { // [13, 20]
+		mockBlock(b, 0)
+		mockHandlerCall("BlockSeen", b, 1)
+	}
+
+	for _, b := range newBlocks { // [10, 20]
+		mockHandlerCall("BlockAdded", b, 1)
+	}
+
+	err := syncer.Sync(ctx, -1, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), syncer.concurrency)
+	assert.Equal(t, int64(1), syncer.reorgs)
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestSync_CacheSizeLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	blocks := createBlocks(0, 20, "")
+	blockSize := utils.SizeOf(&blockResult{index: 1, block: blocks[1]})
+
+	// A small size multiplier ensures concurrency is not
+	// reduced, so only the cache size limits prefetching.
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithConcurrency(4),
+		WithMaxConcurrency(4),
+		WithCacheSize(2*blockSize),
+		WithSizeMultiplier(0.01),
+	)
+
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: blocks[20].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}, nil)
+
+	// Block 1 is returned last, so all other fetched
+	// blocks must wait in the cache until it arrives.
+	var block1Fetched int32
+	var fetchedAhead int64
+	for _, b := range blocks {
+		index := b.BlockIdentifier.Index
+		delay := time.Duration(0)
+		if index == 1 {
+			delay = 200 * time.Millisecond
+		}
+
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &b.BlockIdentifier.Index},
+		).Return(
+			b,
+			nil,
+		).After(delay).Run(func(args mock.Arguments) {
+			if index == 1 {
+				atomic.StoreInt32(&block1Fetched, 1)
+				return
+			}
+
+			if index > 1 && atomic.LoadInt32(&block1Fetched) == 0 {
+				atomic.AddInt64(&fetchedAhead, 1)
+			}
+		}).Once()
+		mockHandler.On("BlockSeen", mock.Anything, b).Return(nil).Once()
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+	}
+
+	err := syncer.Sync(ctx, -1, 20)
+	assert.NoError(t, err)
+
+	// Without the cache size limit, all 19 blocks after block 1
+	// would be fetched while waiting. With it, at most the blocks
+	// in the cache (and those already being fetched) are fetched.
+	assert.Greater(t, atomic.LoadInt64(&fetchedAhead), int64(0))
+	assert.LessOrEqual(t, atomic.LoadInt64(&fetchedAhead), int64(10))
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestWaitForCache(t *testing.T) {
+	ctx := context.Background()
+	syncer := New(
+		networkIdentifier,
+		&mocks.Helper{},
+		&mocks.Handler{},
+		nil,
+		WithCacheSize(10),
+	)
+	syncer.cachedBytes = 20
+	syncer.cacheNextIndex = 5
+	syncer.cacheChanged = make(chan struct{})
+
+	t.Run("next index", func(t *testing.T) {
+		assert.NoError(t, syncer.waitForCache(ctx, 5))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.True(t, errors.Is(syncer.waitForCache(ctx, 6), context.Canceled))
+	})
+
+	t.Run("cache released", func(t *testing.T) {
+		done := make(chan error)
+		go func() {
+			done <- syncer.waitForCache(ctx, 6)
+		}()
+
+		// The next index is fetched even if
+		// the cache is full.
+		syncer.updateCache(20, 6)
+		assert.NoError(t, <-done)
+
+		go func() {
+			done <- syncer.waitForCache(ctx, 8)
+		}()
+
+		select {
+		case <-done:
+			assert.Fail(t, "wait should block while the cache is full")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		syncer.updateCache(10, 6)
+		assert.NoError(t, <-done)
+	})
+}
+
+func TestSync_MaxReorgDepth(t *testing.T) {
+	blocks := createBlocks(0, 10, "")
+	pastBlocks := []*types.BlockIdentifier{}
+	for _, b := range blocks {
+		pastBlocks = append(pastBlocks, b.BlockIdentifier)
+	}
+
+	// Create a reorg of depth 5 (blocks 6-10 are removed).
+	newBlocks := createBlocks(6, 12, "other")
+	newBlocks[0].ParentBlockIdentifier = blocks[5].BlockIdentifier
+
+	var tests = map[string]struct {
+		maxDepth int64
+		removed  []*types.Block
+		err      bool
+	}{
+		"just under limit": {
+			maxDepth: 5,
+			removed:  blocks[6:],
+		},
+		"just over limit": {
+			maxDepth: 4,
+			removed:  blocks[7:],
+			err:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			mockHelper := &mocks.Helper{}
+			mockHandler := &mocks.Handler{}
+			syncer := New(
+				networkIdentifier,
+				mockHelper,
+				mockHandler,
+				func() {},
+				WithPastBlocks(append([]*types.BlockIdentifier{}, pastBlocks...)),
+				WithMaxReorgDepth(test.maxDepth),
+			)
+			mockNetworkStatus(mockHelper, newBlocks[6].BlockIdentifier)
+
+			for _, b := range newBlocks {
+				mockHelper.On(
+					"Block",
+					mock.Anything,
+					networkIdentifier,
+					&types.PartialBlockIdentifier{Index: &b.BlockIdentifier.Index},
+				).Return(
+					b,
+					nil,
+				).Maybe()
+				mockHandler.On("BlockSeen", mock.Anything, b).Return(nil).Maybe()
+
+				if !test.err {
+					mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+				}
+			}
+
+			for _, b := range test.removed {
+				mockHandler.On("BlockRemoved", mock.Anything, b.BlockIdentifier).Return(nil).Once()
+			}
+
+			err := syncer.Sync(ctx, 11, 12)
+			if !test.err {
+				assert.NoError(t, err)
+				assert.Equal(t, newBlocks[6].BlockIdentifier, lastBlockIdentifier(syncer))
+			} else {
+				assert.True(t, errors.Is(err, ErrReorgTooDeep))
+
+				var tooDeep *ReorgTooDeepError
+				assert.True(t, errors.As(err, &tooDeep))
+				assert.Equal(t, &ReorgTooDeepError{
+					MaxDepth:      4,
+					BlockToRemove: blocks[6].BlockIdentifier,
+					LocalTip:      blocks[10].BlockIdentifier,
+					RemoteTip:     newBlocks[6].BlockIdentifier,
+				}, tooDeep)
+
+				// The block that would exceed the max
+				// depth is not removed.
+				assert.Equal(t, blocks[6].BlockIdentifier, lastBlockIdentifier(syncer))
+			}
+
+			mockHelper.AssertExpectations(t)
+			mockHandler.AssertExpectations(t)
+		})
+	}
+}
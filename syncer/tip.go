@@ -0,0 +1,98 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// TipHandler is notified once when the syncer
+// first reaches tip. If the syncer later falls more
+// than the behind tip threshold behind tip (see
+// WithBehindTipThreshold), the TipHandler is notified
+// again once the syncer catches up.
+//
+// syncDuration is the time spent catching up (since Sync
+// was first invoked or since the syncer fell behind tip)
+// and first is true if this is the first time the syncer
+// has reached tip.
+type TipHandler interface {
+	TipReached(
+		ctx context.Context,
+		block *types.BlockIdentifier,
+		syncDuration time.Duration,
+		first bool,
+	) error
+}
+
+// startCatchUp records the start of catching
+// up to tip (if not at tip and not already
+// catching up).
+func (s *Syncer) startCatchUp() {
+	if s.atTip || !s.catchUpStart.IsZero() {
+		return
+	}
+
+	s.catchUpStart = time.Now()
+}
+
+// checkBehindTip marks the syncer as catching
+// up if it is at tip and the last observed tip is
+// more than behindTipThreshold past the last
+// processed block.
+func (s *Syncer) checkBehindTip() {
+	if !s.atTip || s.tip == nil {
+		return
+	}
+
+	if s.tip.Index-(s.nextIndex-1) <= s.behindTipThreshold {
+		return
+	}
+
+	s.atTip = false
+	s.startCatchUp()
+}
+
+// tipReached notifies the TipHandler (if provided)
+// if block is at (or past) the last observed tip
+// and the syncer was catching up. If the TipHandler
+// returns an error, the syncer is still considered
+// to be catching up.
+func (s *Syncer) tipReached(ctx context.Context, block *types.BlockIdentifier) error {
+	if s.atTip || s.tip == nil || block == nil || block.Index < s.tip.Index {
+		return nil
+	}
+
+	var syncDuration time.Duration
+	if !s.catchUpStart.IsZero() {
+		syncDuration = time.Since(s.catchUpStart)
+	}
+
+	if s.tipHandler != nil {
+		err := s.tipHandler.TipReached(ctx, block, syncDuration, !s.tipReachedOnce)
+		if err != nil {
+			return fmt.Errorf("%w %d: %v", ErrTipReachedFailed, block.Index, err)
+		}
+	}
+
+	s.atTip = true
+	s.tipReachedOnce = true
+	s.catchUpStart = time.Time{}
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// mockMovingTip returns the current value
+// of tip from each NetworkStatus call.
+func mockMovingTip(mockHelper *mocks.Helper, blocks []*types.Block, tip *int64) {
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(
+		func(context.Context, *types.NetworkIdentifier) *types.NetworkStatusResponse {
+			return &types.NetworkStatusResponse{
+				CurrentBlockIdentifier: blocks[*tip].BlockIdentifier,
+				GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+			}
+		},
+		nil,
+	)
+}
+
+func TestSync_TipReached(t *testing.T) {
+	ctx := context.Background()
+
+	blocks := createBlocks(0, 520, "")
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	mockTipHandler := &mocks.TipHandler{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		func() {},
+		WithTipHandler(mockTipHandler),
+	)
+
+	tip := int64(10)
+	mockMovingTip(mockHelper, blocks, &tip)
+
+	// The TipHandler is notified once the
+	// initial sync reaches tip.
+	mockSyncedBlocks(mockHelper, mockHandler, blocks[:11])
+	mockTipHandler.On(
+		"TipReached",
+		mock.Anything,
+		blocks[10].BlockIdentifier,
+		mock.MatchedBy(func(d time.Duration) bool { return d >= 0 }),
+		true,
+	).Return(nil).Once()
+	assert.NoError(t, syncer.Sync(ctx, -1, 10))
+	assert.True(t, syncer.atTip)
+	mockTipHandler.AssertExpectations(t)
+
+	// Falling behind by less than the threshold
+	// does not trigger another notification.
+	tip = 20
+	mockSyncedBlocks(mockHelper, mockHandler, blocks[11:21])
+	assert.NoError(t, syncer.Sync(ctx, 11, 20))
+	assert.True(t, syncer.atTip)
+	mockTipHandler.AssertNumberOfCalls(t, "TipReached", 1)
+
+	// Falling behind by several hundred blocks
+	// triggers another notification once the
+	// syncer catches up.
+	tip = 520
+	mockSyncedBlocks(mockHelper, mockHandler, blocks[21:])
+	mockTipHandler.On(
+		"TipReached",
+		mock.Anything,
+		blocks[520].BlockIdentifier,
+		mock.MatchedBy(func(d time.Duration) bool { return d >= 0 }),
+		false,
+	).Return(nil).Once()
+	assert.NoError(t, syncer.Sync(ctx, 21, 520))
+	assert.True(t, syncer.atTip)
+	assert.True(t, syncer.catchUpStart.IsZero())
+	mockTipHandler.AssertNumberOfCalls(t, "TipReached", 2)
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mockTipHandler.AssertExpectations(t)
+}
+
+func TestSync_TipReachedAtStart(t *testing.T) {
+	ctx := context.Background()
+
+	blocks := createBlocks(0, 10, "")
+	mockHelper := &mocks.Helper{}
+	mockTipHandler := &mocks.TipHandler{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		&mocks.Handler{},
+		func() {},
+		WithPastBlocks([]*types.BlockIdentifier{blocks[10].BlockIdentifier}),
+		WithTipHandler(mockTipHandler),
+		WithEndCondition(EndAtTip(1, 0)),
+	)
+
+	// When the last processed block is already at tip,
+	// the TipHandler is notified without adding a block.
+	tip := int64(10)
+	mockMovingTip(mockHelper, blocks, &tip)
+	mockTipHandler.On(
+		"TipReached",
+		mock.Anything,
+		blocks[10].BlockIdentifier,
+		mock.Anything,
+		true,
+	).Return(nil).Once()
+	assert.NoError(t, syncer.Sync(ctx, 11, -1))
+	assert.True(t, syncer.atTip)
+
+	mockHelper.AssertExpectations(t)
+	mockTipHandler.AssertExpectations(t)
+}
+
+func TestProcessBlock_TipReached(t *testing.T) {
+	ctx := context.Background()
+
+	blocks := createBlocks(0, 1, "")
+	mockHandler := &mocks.Handler{}
+	mockTipHandler := &mocks.TipHandler{}
+	syncer := New(
+		networkIdentifier,
+		&mocks.Helper{},
+		mockHandler,
+		nil,
+		WithTipHandler(mockTipHandler),
+		WithBehindTipThreshold(0),
+	)
+	syncer.genesisBlock = blocks[0].BlockIdentifier
+	syncer.tip = blocks[1].BlockIdentifier
+
+	// No notification is sent before reaching tip.
+	mockHandler.On("BlockAdded", mock.Anything, blocks[0]).Return(nil).Once()
+	assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: blocks[0]}))
+	assert.False(t, syncer.atTip)
+
+	t.Run("tip handler error", func(t *testing.T) {
+		mockHandler.On("BlockAdded", mock.Anything, blocks[1]).Return(nil).Once()
+		mockTipHandler.On(
+			"TipReached",
+			ctx,
+			blocks[1].BlockIdentifier,
+			time.Duration(0),
+			true,
+		).Return(errors.New("bad")).Once()
+		err := syncer.processBlock(ctx, &blockResult{block: blocks[1]})
+		assert.True(t, errors.Is(err, ErrTipReachedFailed))
+		assert.False(t, syncer.atTip)
+		assert.False(t, syncer.tipReachedOnce)
+	})
+
+	t.Run("behind tip threshold", func(t *testing.T) {
+		syncer.atTip = true
+		syncer.checkBehindTip()
+		assert.True(t, syncer.atTip)
+
+		syncer.tip = &types.BlockIdentifier{Index: 3, Hash: "block 3"}
+		syncer.checkBehindTip()
+		assert.False(t, syncer.atTip)
+		assert.False(t, syncer.catchUpStart.IsZero())
+	})
+
+	mockHandler.AssertExpectations(t)
+	mockTipHandler.AssertExpectations(t)
+}
@@ -29,6 +29,11 @@ const (
 	// DefaultPastBlockLimit, it will not be handled correctly.
 	DefaultPastBlockLimit = 100
 
+	// DefaultMinPastBlocks is the minimum number of
+	// previously processed block headers we keep in the
+	// syncer when evicting past blocks by size.
+	DefaultMinPastBlocks = 10
+
 	// DefaultConcurrency is the default number of
 	// blocks the syncer will try to get concurrently.
 	DefaultConcurrency = int64(4) // nolint:gomnd
@@ -75,6 +80,21 @@ const (
 	// when we are loading more blocks to fetch but we
 	// already have a backlog >= to concurrency.
 	defaultFetchSleep = 500 * time.Millisecond
+
+	// DefaultMaxReorgDepth is the default maximum
+	// number of blocks the syncer will remove while
+	// handling a single reorg.
+	DefaultMaxReorgDepth = int64(1000) // nolint:gomnd
+
+	// DefaultCheckpointInterval is the default number
+	// of processed blocks between checkpoints.
+	DefaultCheckpointInterval = 1
+
+	// DefaultBehindTipThreshold is the default number
+	// of blocks the syncer must fall behind tip (after
+	// reaching it) before the TipHandler is notified
+	// again.
+	DefaultBehindTipThreshold = int64(100) // nolint:gomnd
 )
 
 // Handler is called at various times during the sync cycle
@@ -117,6 +137,38 @@ type Helper interface {
 	) (*types.Block, error)
 }
 
+// PastBlockStorage is used by the syncer to look up
+// previously processed blocks that have been evicted
+// from the past block cache (in a deep reorg).
+type PastBlockStorage interface {
+	// PastBlock returns the last processed block at or
+	// before index (blocks may be omitted on some networks)
+	// or nil if no block was processed at or before index.
+	PastBlock(ctx context.Context, index int64) (*types.BlockIdentifier, error)
+}
+
+// OmittedHandler is notified when the node omits a
+// block at an index (some networks skip indexes entirely).
+// This allows storage to record continuity across gaps.
+// If an omitted index is later populated (in a reorg),
+// the syncer will invoke Handler.BlockAdded for the
+// populated block at that index.
+type OmittedHandler interface {
+	BlockOmitted(ctx context.Context, index int64) error
+}
+
+// Checkpointer is used by the syncer to persist the
+// last processed block so that syncing can resume from it
+// after a restart. It is common to implement this interface
+// using the storage package.
+type Checkpointer interface {
+	// LoadCheckpoint returns the last saved checkpoint
+	// or nil if no checkpoint has been saved.
+	LoadCheckpoint(ctx context.Context) (*types.BlockIdentifier, error)
+
+	SaveCheckpoint(ctx context.Context, block *types.BlockIdentifier) error
+}
+
 // Syncer coordinates blockchain syncing without relying on
 // a storage interface. Instead, it calls a provided Handler
 // whenever a block is added or removed. This provides the client
@@ -124,6 +176,19 @@ type Helper interface {
 // In the rosetta-cli, we handle reconciliation, state storage, and
 // logging in the handler.
 type Syncer struct {
+	// pastBlockCount and pastBlockBytes describe the blocks
+	// in pastBlocks and are accessed atomically (so they must
+	// be first in the struct to be 64-bit aligned on 32-bit
+	// platforms).
+	pastBlockCount int64
+	pastBlockBytes int64
+
+	// reorgs is incremented (atomically) whenever a block
+	// is removed. Blocks fetched before the most recent
+	// reorg may belong to the orphaned chain. Like
+	// pastBlockCount, it must remain 64-bit aligned.
+	reorgs int64
+
 	network *types.NetworkIdentifier
 	helper  Helper
 	handler Handler
@@ -141,25 +206,99 @@ type Syncer struct {
 	//
 	// If a blockchain does not have reorgs, it is not necessary to populate
 	// the blockCache on creation.
-	pastBlocks     []*types.BlockIdentifier
-	pastBlockLimit int
+	//
+	// Past blocks are also evicted (down to minPastBlocks)
+	// when they occupy more than pastBlockCacheSize bytes.
+	// If a reorg is deeper than the past blocks,
+	// pastBlockStorage is used (if provided).
+	pastBlocks         []*types.BlockIdentifier
+	pastBlockLimit     int
+	pastBlockCacheSize int
+	minPastBlocks      int
+	pastBlockStorage   PastBlockStorage
+
+	// omittedBlocks are the indexes omitted
+	// since the last processed block.
+	omittedBlocks  []int64
+	omittedHandler OmittedHandler
 
 	// Automatically manage concurrency based on the
 	// provided max cache size. The algorithm used here
 	// is a slow rise (to increase concurrency) and fast
 	// fall (if we breach our max cache size).
-	cacheSize        int
-	sizeMultiplier   float64
-	maxConcurrency   int64
-	concurrency      int64
-	goalConcurrency  int64
-	recentBlockSizes []int
-	lastAdjustment   int64
-	adjustmentWindow int64
-	concurrencyLock  sync.Mutex
+	cacheSize           int
+	sizeMultiplier      float64
+	maxConcurrency      int64
+	startingConcurrency int64
+	concurrency         int64
+	goalConcurrency     int64
+	recentBlockSizes    []int
+	lastAdjustment      int64
+	adjustmentWindow    int64
+	concurrencyLock     sync.Mutex
 
 	// doneLoading is used to coordinate adding goroutines
 	// when close to the end of syncing a range.
 	doneLoading     bool
 	doneLoadingLock sync.Mutex
+
+	// cachedBytes is the approximate size of fetched blocks
+	// waiting to be processed (starting at cacheNextIndex).
+	// Workers do not fetch blocks after cacheNextIndex while
+	// cachedBytes exceeds cacheSize. cacheChanged is closed
+	// (and replaced) whenever either value is updated.
+	cachedBytes    int
+	cacheNextIndex int64
+	cacheChanged   chan struct{}
+	cacheLock      sync.Mutex
+
+	// checkpointer is used to resume syncing from the
+	// last saved checkpoint (if provided). A checkpoint
+	// is saved every checkpointInterval processed blocks.
+	checkpointer       Checkpointer
+	checkpointInterval int64
+	lastCheckpoint     *types.BlockIdentifier
+	uncheckpointed     int64
+
+	// endCondition is evaluated after each processed
+	// block (if provided). ended is set once it is reached.
+	// lastBlockTimestamp is the timestamp of the last
+	// added block.
+	endCondition       EndCondition
+	ended              bool
+	lastBlockTimestamp int64
+
+	// observer is notified of sync progress (if provided)
+	// using observerEvents (a queue of up to observerQueueSize
+	// callbacks).
+	observer          Observer
+	observerQueueSize int
+	observerEvents    chan func()
+
+	// tipHandler is notified when the syncer transitions
+	// from catching up (since catchUpStart) to at tip. The
+	// syncer is considered to be catching up again if it
+	// falls more than behindTipThreshold blocks behind tip.
+	tipHandler         TipHandler
+	behindTipThreshold int64
+	atTip              bool
+	tipReachedOnce     bool
+	catchUpStart       time.Time
+
+	// reorgDepth is the number of blocks removed since
+	// the last added block (reorgTip is the first block
+	// removed). If removing another block would exceed
+	// maxReorgDepth, syncing stops.
+	reorgDepth    int64
+	reorgTip      *types.BlockIdentifier
+	maxReorgDepth int64
+
+	// shutdown is closed when Shutdown is invoked. If
+	// a sync is in progress, syncing is closed once it
+	// returns and abort cancels its context.
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+	syncLock     sync.Mutex
+	syncing      chan struct{}
+	abort        context.CancelFunc
 }
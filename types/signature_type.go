@@ -24,7 +24,10 @@ package types
 // (32-bytes) || s (32-bytes)` where s = Hash(1st pk || 2nd pk || r) - `64 bytes`  (schnorr
 // signature w/ Poseidon hash function implemented by O(1) Labs where both `r` and `s` are scalars
 // encoded as `32-bytes` values, least significant byte first.
-// https://github.com/CodaProtocol/signer-reference/blob/master/schnorr.ml )
+// https://github.com/CodaProtocol/signer-reference/blob/master/schnorr.ml ) * schnorr_bip340: `r
+// (32-bytes) || s (32-bytes)` - `64 bytes`  (schnorr signature specified in BIP340 where `r` is
+// the x-coordinate of the nonce point and the public key is the `32-byte` x-coordinate of the
+// secp256k1 public key. https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki )
 type SignatureType string
 
 // List of SignatureType
@@ -34,4 +37,5 @@ const (
 	Ed25519         SignatureType = "ed25519"
 	Schnorr1        SignatureType = "schnorr_1"
 	SchnorrPoseidon SignatureType = "schnorr_poseidon"
+	SchnorrBip340   SignatureType = "schnorr_bip340"
 )
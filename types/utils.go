@@ -16,15 +16,27 @@ package types
 
 import (
 	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"math/big"
+	"strconv"
+	"strings"
 
 	"github.com/mitchellh/mapstructure"
 )
 
+const (
+	// canonicalJSONOverhead is the capacity reserved for
+	// keys and punctuation when constructing canonical JSON.
+	canonicalJSONOverhead = 48
+
+	// decimalBase is the base used to encode integers.
+	decimalBase = 10
+)
+
 // ConstructPartialBlockIdentifier constructs a *PartialBlockIdentifier
 // from a *BlockIdentifier.
 //
@@ -48,7 +60,7 @@ func hashBytes(data []byte) string {
 		log.Fatal(fmt.Errorf("%w: unable to hash data %s", err, string(data)))
 	}
 
-	return fmt.Sprintf("%x", h.Sum(nil))
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 // Hash returns a deterministic hash for any interface.
@@ -60,7 +72,24 @@ func hashBytes(data []byte) string {
 // It is important to note that any interface that is a slice
 // or contains slices will not be equal if the slice ordering is
 // different.
+//
+// *AccountIdentifier and *Currency without Metadata are hashed
+// using a fast path that directly constructs the same canonical
+// JSON that would be produced by the marshaler (instead of
+// marshaling the struct twice). Because the hashed bytes are
+// identical, hashes (and any storage keys derived from them) do
+// not change.
 func Hash(i interface{}) string {
+	if encoded, ok := canonicalJSON(i); ok {
+		return hashBytes(encoded)
+	}
+
+	return hashJSON(i)
+}
+
+// hashJSON returns a hash of the canonical JSON
+// representation of any interface.
+func hashJSON(i interface{}) string {
 	// Convert interface to JSON object (not necessarily ordered if struct
 	// contains json.RawMessage)
 	a, err := json.Marshal(i)
@@ -83,6 +112,70 @@ func Hash(i interface{}) string {
 	return hashBytes(c)
 }
 
+// canonicalJSON returns the canonical JSON representation of
+// *AccountIdentifier and *Currency without Metadata (keys are
+// written in sorted order, matching the output of json.Marshal on
+// a map). If i is of any other type or contains Metadata, false is
+// returned and the marshaler should be used.
+func canonicalJSON(i interface{}) ([]byte, bool) {
+	switch v := i.(type) {
+	case *AccountIdentifier:
+		if v == nil || len(v.Metadata) > 0 {
+			return nil, false
+		}
+
+		if v.SubAccount != nil && len(v.SubAccount.Metadata) > 0 {
+			return nil, false
+		}
+
+		b := make([]byte, 0, len(v.Address)+canonicalJSONOverhead)
+		b = append(b, `{"address":`...)
+		b = appendJSONString(b, v.Address)
+		if v.SubAccount != nil {
+			b = append(b, `,"sub_account":{"address":`...)
+			b = appendJSONString(b, v.SubAccount.Address)
+			b = append(b, '}')
+		}
+
+		return append(b, '}'), true
+	case *Currency:
+		if v == nil || len(v.Metadata) > 0 {
+			return nil, false
+		}
+
+		b := make([]byte, 0, len(v.Symbol)+canonicalJSONOverhead)
+		b = append(b, `{"decimals":`...)
+		b = strconv.AppendInt(b, int64(v.Decimals), decimalBase)
+		b = append(b, `,"symbol":`...)
+		b = appendJSONString(b, v.Symbol)
+
+		return append(b, '}'), true
+	default:
+		return nil, false
+	}
+}
+
+// appendJSONString appends the JSON encoding of s to b. Strings
+// containing characters that the marshaler escapes are encoded
+// with the marshaler.
+func appendJSONString(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < ' ' || c > '~' || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			encoded, err := json.Marshal(s)
+			if err != nil {
+				log.Fatal(fmt.Errorf("%w: unable to marshal %s", err, s))
+			}
+
+			return append(b, encoded...)
+		}
+	}
+
+	b = append(b, '"')
+	b = append(b, s...)
+	return append(b, '"')
+}
+
 // BigInt returns a *big.Int representation of a value.
 func BigInt(value string) (*big.Int, error) {
 	parsedVal, ok := new(big.Int).SetString(value, 10)
@@ -103,6 +196,126 @@ func AmountValue(amount *Amount) (*big.Int, error) {
 	return BigInt(amount.Value)
 }
 
+// NewAmount returns an *Amount with value
+// denominated in currency.
+func NewAmount(value *big.Int, currency *Currency) *Amount {
+	return &Amount{
+		Value:    value.String(),
+		Currency: currency,
+	}
+}
+
+// FormatAmount returns a decimal representation of an
+// *Amount by shifting its Value by Currency.Decimals
+// (ex: 1234 with 2 decimals is formatted as "12.34"). All
+// fractional digits are included, so the result can be
+// parsed with ParseAmount without loss of precision.
+func FormatAmount(amount *Amount) (string, error) {
+	value, err := AmountValue(amount)
+	if err != nil {
+		return "", err
+	}
+
+	decimals, err := currencyDecimals(amount.Currency)
+	if err != nil {
+		return "", err
+	}
+
+	digits := new(big.Int).Abs(value).String()
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+
+	if decimals == 0 {
+		return sign + digits, nil
+	}
+
+	// Pad with leading zeros so there is at least
+	// one integer digit.
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	integer, fraction := digits[:len(digits)-decimals], digits[len(digits)-decimals:]
+	return sign + integer + "." + fraction, nil
+}
+
+// ParseAmount returns an *Amount denominated in currency
+// from a decimal representation (the inverse of FormatAmount).
+// An error is returned if decimalStr has more fractional
+// digits than Currency.Decimals (instead of rounding).
+func ParseAmount(decimalStr string, currency *Currency) (*Amount, error) {
+	decimals, err := currencyDecimals(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	unsigned := strings.TrimPrefix(decimalStr, "-")
+	integer, fraction := unsigned, ""
+	if i := strings.Index(unsigned, "."); i >= 0 {
+		integer, fraction = unsigned[:i], unsigned[i+1:]
+		if len(fraction) == 0 {
+			return nil, fmt.Errorf("%s is not a decimal", decimalStr)
+		}
+	}
+
+	if len(integer) == 0 || !isDigits(integer) || !isDigits(fraction) {
+		return nil, fmt.Errorf("%s is not a decimal", decimalStr)
+	}
+
+	if len(fraction) > decimals {
+		return nil, fmt.Errorf(
+			"%s has %d fractional digits but %s has %d decimals",
+			decimalStr,
+			len(fraction),
+			currency.Symbol,
+			decimals,
+		)
+	}
+
+	value, err := BigInt(integer + fraction + strings.Repeat("0", decimals-len(fraction)))
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(decimalStr, "-") {
+		value.Neg(value)
+	}
+
+	return NewAmount(value, currency), nil
+}
+
+// currencyDecimals returns the Decimals of
+// a *Currency or an error if it is invalid.
+func currencyDecimals(currency *Currency) (int, error) {
+	if currency == nil {
+		return 0, errors.New("currency cannot be nil")
+	}
+
+	if currency.Decimals < 0 {
+		return 0, fmt.Errorf(
+			"%s has negative decimals %d",
+			currency.Symbol,
+			currency.Decimals,
+		)
+	}
+
+	return int(currency.Decimals), nil
+}
+
+// isDigits returns a boolean indicating if s
+// only contains the digits 0-9.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
 // AddValues adds string amounts using
 // big.Int.
 func AddValues(
@@ -143,6 +356,108 @@ func SubtractValues(
 	return newVal.String(), nil
 }
 
+// MultiplyValues multiplies a*b using
+// big.Int.
+func MultiplyValues(
+	a string,
+	b string,
+) (string, error) {
+	aVal, err := BigInt(a)
+	if err != nil {
+		return "", err
+	}
+
+	bVal, err := BigInt(b)
+	if err != nil {
+		return "", err
+	}
+
+	newVal := new(big.Int).Mul(aVal, bVal)
+	return newVal.String(), nil
+}
+
+// DivideValues divides a/b using big.Int. If the
+// quotient is not an integer, it is rounded towards
+// positive infinity when roundUp is true (ceil) and
+// towards negative infinity otherwise (floor).
+func DivideValues(
+	a string,
+	b string,
+	roundUp bool,
+) (string, error) {
+	aVal, err := BigInt(a)
+	if err != nil {
+		return "", err
+	}
+
+	bVal, err := BigInt(b)
+	if err != nil {
+		return "", err
+	}
+
+	if bVal.Sign() == 0 {
+		return "", errors.New("cannot divide by zero")
+	}
+
+	// QuoRem truncates towards zero, so we only need to adjust
+	// the quotient when there is a remainder.
+	quotient, remainder := new(big.Int).QuoRem(aVal, bVal, new(big.Int))
+	if remainder.Sign() != 0 {
+		negative := aVal.Sign() != bVal.Sign()
+		switch {
+		case roundUp && !negative:
+			quotient.Add(quotient, big.NewInt(1))
+		case !roundUp && negative:
+			quotient.Sub(quotient, big.NewInt(1))
+		}
+	}
+
+	return quotient.String(), nil
+}
+
+// ModuloValues returns the Euclidean modulus of a and b
+// using big.Int, which is always in the range [0, |b|).
+func ModuloValues(
+	a string,
+	b string,
+) (string, error) {
+	aVal, err := BigInt(a)
+	if err != nil {
+		return "", err
+	}
+
+	bVal, err := BigInt(b)
+	if err != nil {
+		return "", err
+	}
+
+	if bVal.Sign() == 0 {
+		return "", errors.New("cannot take modulo by zero")
+	}
+
+	newVal := new(big.Int).Mod(aVal, bVal)
+	return newVal.String(), nil
+}
+
+// CompareValues compares a and b using big.Int. The
+// result is -1 if a < b, 0 if a == b, and 1 if a > b.
+func CompareValues(
+	a string,
+	b string,
+) (int, error) {
+	aVal, err := BigInt(a)
+	if err != nil {
+		return 0, err
+	}
+
+	bVal, err := BigInt(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return aVal.Cmp(bVal), nil
+}
+
 // NegateValue flips the sign of a value.
 func NegateValue(
 	val string,
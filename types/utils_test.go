@@ -98,6 +98,54 @@ func TestHash(t *testing.T) {
 				"b": "c",
 			},
 		},
+		"account metadata": {
+			&AccountIdentifier{
+				Address: "addr1",
+				SubAccount: &SubAccountIdentifier{
+					Address: "staking",
+					Metadata: map[string]interface{}{
+						"a": "b",
+						"c": json.RawMessage(`{"test":2, "neat":"hello"}`),
+					},
+				},
+				Metadata: map[string]interface{}{
+					"d": 1,
+					"e": []interface{}{"f", "g"},
+				},
+			},
+			&AccountIdentifier{
+				Address: "addr1",
+				SubAccount: &SubAccountIdentifier{
+					Address: "staking",
+					Metadata: map[string]interface{}{
+						"c": json.RawMessage(`{"neat":"hello", "test":2}`),
+						"a": "b",
+					},
+				},
+				Metadata: map[string]interface{}{
+					"e": []interface{}{"f", "g"},
+					"d": 1,
+				},
+			},
+		},
+		"currency metadata": {
+			&Currency{
+				Symbol:   "ETH",
+				Decimals: 18,
+				Metadata: map[string]interface{}{
+					"issuer":   "a",
+					"contract": json.RawMessage(`{"chain":1, "address":"0x1"}`),
+				},
+			},
+			&Currency{
+				Symbol:   "ETH",
+				Decimals: 18,
+				Metadata: map[string]interface{}{
+					"contract": json.RawMessage(`{"address":"0x1", "chain":1}`),
+					"issuer":   "a",
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -114,6 +162,158 @@ func TestHash(t *testing.T) {
 	}
 }
 
+func TestHashCanonicalJSON(t *testing.T) {
+	var tests = map[string]struct {
+		val      interface{}
+		fastPath bool
+		hash     string
+	}{
+		"account": {
+			val: &AccountIdentifier{
+				Address: "addr1",
+				SubAccount: &SubAccountIdentifier{
+					Address: "staking",
+				},
+			},
+			fastPath: true,
+			hash:     "54d19932726b2e776fd70961e696190876fcea420949f85930e2a2d693125d6a",
+		},
+		"account with empty metadata": {
+			val: &AccountIdentifier{
+				Address:  "addr1",
+				Metadata: map[string]interface{}{},
+				SubAccount: &SubAccountIdentifier{
+					Address:  "staking",
+					Metadata: map[string]interface{}{},
+				},
+			},
+			fastPath: true,
+			hash:     "54d19932726b2e776fd70961e696190876fcea420949f85930e2a2d693125d6a",
+		},
+		"account with escaped characters": {
+			val: &AccountIdentifier{
+				Address: "<addr\"1\">&\u2028\n",
+			},
+			fastPath: true,
+		},
+		"account with unicode": {
+			val: &AccountIdentifier{
+				Address: "addr\xff\u00e9",
+			},
+			fastPath: true,
+		},
+		"account with metadata": {
+			val: &AccountIdentifier{
+				Address: "addr1",
+				Metadata: map[string]interface{}{
+					"a": 1,
+				},
+			},
+		},
+		"account with sub account metadata": {
+			val: &AccountIdentifier{
+				Address: "addr1",
+				SubAccount: &SubAccountIdentifier{
+					Address: "staking",
+					Metadata: map[string]interface{}{
+						"a": 1,
+					},
+				},
+			},
+		},
+		"nil account": {
+			val: (*AccountIdentifier)(nil),
+		},
+		"currency": {
+			val: &Currency{
+				Symbol:   "ETH",
+				Decimals: 18,
+			},
+			fastPath: true,
+			hash:     "fd3a15820a9c673e6343187ce20b3fa544f22eaf42db7c6e6e9c1d38f4887dd1",
+		},
+		"currency with negative decimals": {
+			val: &Currency{
+				Symbol:   "&",
+				Decimals: -2147483648,
+			},
+			fastPath: true,
+		},
+		"currency with metadata": {
+			val: &Currency{
+				Symbol:   "ETH",
+				Decimals: 18,
+				Metadata: map[string]interface{}{
+					"a": 1,
+				},
+			},
+		},
+		"nil currency": {
+			val: (*Currency)(nil),
+		},
+		"currency value": {
+			val: Currency{
+				Symbol:   "ETH",
+				Decimals: 18,
+			},
+			hash: "fd3a15820a9c673e6343187ce20b3fa544f22eaf42db7c6e6e9c1d38f4887dd1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, ok := canonicalJSON(test.val)
+			assert.Equal(t, test.fastPath, ok)
+
+			// The fast path must always produce the same hash
+			// as the marshaler.
+			hash := Hash(test.val)
+			assert.Equal(t, hashJSON(test.val), hash)
+			if len(test.hash) > 0 {
+				assert.Equal(t, test.hash, hash)
+			}
+		})
+	}
+}
+
+var (
+	benchmarkAccount = &AccountIdentifier{
+		Address: "0x6c4c8b5b5d8a8b1f7e6b5f5e2e1c6a4f6d7e8a9b",
+		SubAccount: &SubAccountIdentifier{
+			Address: "staking",
+		},
+	}
+
+	benchmarkCurrency = &Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+	}
+)
+
+func BenchmarkHashAccountIdentifier(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Hash(benchmarkAccount)
+	}
+}
+
+func BenchmarkHashJSONAccountIdentifier(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		hashJSON(benchmarkAccount)
+	}
+}
+
+func BenchmarkHashCurrency(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Hash(benchmarkCurrency)
+	}
+}
+
+func BenchmarkHashJSONCurrency(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		hashJSON(benchmarkCurrency)
+	}
+}
+
 func TestAddValues(t *testing.T) {
 	var tests = map[string]struct {
 		a      string
@@ -210,6 +410,296 @@ func TestSubtractValues(t *testing.T) {
 	}
 }
 
+func TestMultiplyValues(t *testing.T) {
+	var tests = map[string]struct {
+		a      string
+		b      string
+		result string
+		err    error
+	}{
+		"simple": {
+			a:      "2",
+			b:      "3",
+			result: "6",
+			err:    nil,
+		},
+		"zero": {
+			a:      "0",
+			b:      "12332",
+			result: "0",
+			err:    nil,
+		},
+		"large": {
+			a:      "18446744073709551616",
+			b:      "100000000000000000000",
+			result: "1844674407370955161600000000000000000000",
+			err:    nil,
+		},
+		"negative": {
+			a:      "-13213",
+			b:      "12332",
+			result: "-162942716",
+			err:    nil,
+		},
+		"both negative": {
+			a:      "-13213",
+			b:      "-12332",
+			result: "162942716",
+			err:    nil,
+		},
+		"decimal": {
+			a:      "10000000000000000000000.01",
+			b:      "100000000000000000000000000000000",
+			result: "",
+			err:    errors.New("10000000000000000000000.01 is not an integer"),
+		},
+		"invalid number": {
+			a:      "-13213",
+			b:      "hello",
+			result: "",
+			err:    errors.New("hello is not an integer"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := MultiplyValues(test.a, test.b)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestDivideValues(t *testing.T) {
+	var tests = map[string]struct {
+		a       string
+		b       string
+		roundUp bool
+		result  string
+		err     error
+	}{
+		"exact": {
+			a:      "12",
+			b:      "3",
+			result: "4",
+			err:    nil,
+		},
+		"exact round up": {
+			a:       "12",
+			b:       "3",
+			roundUp: true,
+			result:  "4",
+			err:     nil,
+		},
+		"floor": {
+			a:      "7",
+			b:      "2",
+			result: "3",
+			err:    nil,
+		},
+		"ceil": {
+			a:       "7",
+			b:       "2",
+			roundUp: true,
+			result:  "4",
+			err:     nil,
+		},
+		"negative floor": {
+			a:      "-7",
+			b:      "2",
+			result: "-4",
+			err:    nil,
+		},
+		"negative ceil": {
+			a:       "-7",
+			b:       "2",
+			roundUp: true,
+			result:  "-3",
+			err:     nil,
+		},
+		"negative divisor floor": {
+			a:      "7",
+			b:      "-2",
+			result: "-4",
+			err:    nil,
+		},
+		"negative divisor ceil": {
+			a:       "7",
+			b:       "-2",
+			roundUp: true,
+			result:  "-3",
+			err:     nil,
+		},
+		"both negative floor": {
+			a:      "-7",
+			b:      "-2",
+			result: "3",
+			err:    nil,
+		},
+		"both negative ceil": {
+			a:       "-7",
+			b:       "-2",
+			roundUp: true,
+			result:  "4",
+			err:     nil,
+		},
+		"zero dividend": {
+			a:       "0",
+			b:       "-2",
+			roundUp: true,
+			result:  "0",
+			err:     nil,
+		},
+		"large": {
+			a:      "1844674407370955161600000000000000000001",
+			b:      "18446744073709551616",
+			result: "100000000000000000000",
+			err:    nil,
+		},
+		"large ceil": {
+			a:       "1844674407370955161600000000000000000001",
+			b:       "18446744073709551616",
+			roundUp: true,
+			result:  "100000000000000000001",
+			err:     nil,
+		},
+		"zero divisor": {
+			a:      "10",
+			b:      "0",
+			result: "",
+			err:    errors.New("cannot divide by zero"),
+		},
+		"negative zero divisor": {
+			a:      "10",
+			b:      "-0",
+			result: "",
+			err:    errors.New("cannot divide by zero"),
+		},
+		"decimal": {
+			a:      "10",
+			b:      "2.5",
+			result: "",
+			err:    errors.New("2.5 is not an integer"),
+		},
+		"invalid number": {
+			a:      "hello",
+			b:      "2",
+			result: "",
+			err:    errors.New("hello is not an integer"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := DivideValues(test.a, test.b, test.roundUp)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestModuloValues(t *testing.T) {
+	var tests = map[string]struct {
+		a      string
+		b      string
+		result string
+		err    error
+	}{
+		"simple": {
+			a:      "10",
+			b:      "3",
+			result: "1",
+		},
+		"negative dividend": {
+			a:      "-10",
+			b:      "3",
+			result: "2",
+		},
+		"negative divisor": {
+			a:      "10",
+			b:      "-3",
+			result: "1",
+		},
+		"large": {
+			a:      "1844674407370955161600000000000000000001",
+			b:      "18446744073709551616",
+			result: "1",
+		},
+		"zero divisor": {
+			a:   "10",
+			b:   "0",
+			err: errors.New("cannot take modulo by zero"),
+		},
+		"decimal": {
+			a:   "10",
+			b:   "2.5",
+			err: errors.New("2.5 is not an integer"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ModuloValues(test.a, test.b)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	var tests = map[string]struct {
+		a      string
+		b      string
+		result int
+		err    error
+	}{
+		"equal": {
+			a:      "100",
+			b:      "100",
+			result: 0,
+			err:    nil,
+		},
+		"less": {
+			a:      "-100",
+			b:      "1",
+			result: -1,
+			err:    nil,
+		},
+		"greater": {
+			a:      "100000000000000000000000000000000",
+			b:      "18446744073709551615",
+			result: 1,
+			err:    nil,
+		},
+		"negative large": {
+			a:      "-100000000000000000000000000000000",
+			b:      "-18446744073709551615",
+			result: -1,
+			err:    nil,
+		},
+		"decimal": {
+			a:      "100",
+			b:      "100.0",
+			result: 0,
+			err:    errors.New("100.0 is not an integer"),
+		},
+		"invalid number": {
+			a:      "hello",
+			b:      "100",
+			result: 0,
+			err:    errors.New("hello is not an integer"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := CompareValues(test.a, test.b)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
 func TestNegateValue(t *testing.T) {
 	var tests = map[string]struct {
 		val    string
@@ -587,6 +1077,258 @@ func TestAmountValue(t *testing.T) {
 	}
 }
 
+var (
+	zeroDecimalCurrency = &Currency{
+		Symbol:   "BTC-SAT",
+		Decimals: 0,
+	}
+
+	eighteenDecimalCurrency = &Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+	}
+)
+
+func TestNewAmount(t *testing.T) {
+	value, ok := new(big.Int).SetString("-100000000000000000000000", 10)
+	assert.True(t, ok)
+
+	assert.Equal(
+		t,
+		&Amount{
+			Value:    "-100000000000000000000000",
+			Currency: eighteenDecimalCurrency,
+		},
+		NewAmount(value, eighteenDecimalCurrency),
+	)
+}
+
+func TestFormatAmount(t *testing.T) {
+	var tests = map[string]struct {
+		amount *Amount
+		result string
+		err    error
+	}{
+		"0 decimals": {
+			amount: &Amount{
+				Value:    "1234",
+				Currency: zeroDecimalCurrency,
+			},
+			result: "1234",
+		},
+		"0 decimals negative": {
+			amount: &Amount{
+				Value:    "-1234",
+				Currency: zeroDecimalCurrency,
+			},
+			result: "-1234",
+		},
+		"18 decimals": {
+			amount: &Amount{
+				Value:    "1500000000000000000",
+				Currency: eighteenDecimalCurrency,
+			},
+			result: "1.500000000000000000",
+		},
+		"18 decimals large": {
+			amount: &Amount{
+				Value:    "123456789000000000000000001",
+				Currency: eighteenDecimalCurrency,
+			},
+			result: "123456789.000000000000000001",
+		},
+		"18 decimals fractional": {
+			amount: &Amount{
+				Value:    "1",
+				Currency: eighteenDecimalCurrency,
+			},
+			result: "0.000000000000000001",
+		},
+		"18 decimals negative": {
+			amount: &Amount{
+				Value:    "-25000000000000000",
+				Currency: eighteenDecimalCurrency,
+			},
+			result: "-0.025000000000000000",
+		},
+		"18 decimals zero": {
+			amount: &Amount{
+				Value:    "0",
+				Currency: eighteenDecimalCurrency,
+			},
+			result: "0.000000000000000000",
+		},
+		"nil amount": {
+			err: errors.New("amount value cannot be nil"),
+		},
+		"invalid value": {
+			amount: &Amount{
+				Value:    "1.5",
+				Currency: eighteenDecimalCurrency,
+			},
+			err: errors.New("1.5 is not an integer"),
+		},
+		"nil currency": {
+			amount: &Amount{
+				Value: "1",
+			},
+			err: errors.New("currency cannot be nil"),
+		},
+		"negative decimals": {
+			amount: &Amount{
+				Value: "1",
+				Currency: &Currency{
+					Symbol:   "BLAH",
+					Decimals: -1,
+				},
+			},
+			err: errors.New("BLAH has negative decimals -1"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := FormatAmount(test.amount)
+			assert.Equal(t, test.result, result)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	var tests = map[string]struct {
+		decimal  string
+		currency *Currency
+		result   *Amount
+		err      error
+	}{
+		"0 decimals": {
+			decimal:  "1234",
+			currency: zeroDecimalCurrency,
+			result: &Amount{
+				Value:    "1234",
+				Currency: zeroDecimalCurrency,
+			},
+		},
+		"0 decimals negative": {
+			decimal:  "-1234",
+			currency: zeroDecimalCurrency,
+			result: &Amount{
+				Value:    "-1234",
+				Currency: zeroDecimalCurrency,
+			},
+		},
+		"0 decimals fractional": {
+			decimal:  "1234.0",
+			currency: zeroDecimalCurrency,
+			err:      errors.New("1234.0 has 1 fractional digits but BTC-SAT has 0 decimals"),
+		},
+		"18 decimals": {
+			decimal:  "1.5",
+			currency: eighteenDecimalCurrency,
+			result: &Amount{
+				Value:    "1500000000000000000",
+				Currency: eighteenDecimalCurrency,
+			},
+		},
+		"18 decimals all digits": {
+			decimal:  "123456789.000000000000000001",
+			currency: eighteenDecimalCurrency,
+			result: &Amount{
+				Value:    "123456789000000000000000001",
+				Currency: eighteenDecimalCurrency,
+			},
+		},
+		"18 decimals integer": {
+			decimal:  "2",
+			currency: eighteenDecimalCurrency,
+			result: &Amount{
+				Value:    "2000000000000000000",
+				Currency: eighteenDecimalCurrency,
+			},
+		},
+		"18 decimals negative": {
+			decimal:  "-0.025",
+			currency: eighteenDecimalCurrency,
+			result: &Amount{
+				Value:    "-25000000000000000",
+				Currency: eighteenDecimalCurrency,
+			},
+		},
+		"18 decimals negative zero": {
+			decimal:  "-0.0",
+			currency: eighteenDecimalCurrency,
+			result: &Amount{
+				Value:    "0",
+				Currency: eighteenDecimalCurrency,
+			},
+		},
+		"18 decimals excess fractional digits": {
+			decimal:  "0.0000000000000000001",
+			currency: eighteenDecimalCurrency,
+			err:      errors.New("0.0000000000000000001 has 19 fractional digits but ETH has 18 decimals"),
+		},
+		"empty": {
+			decimal:  "",
+			currency: eighteenDecimalCurrency,
+			err:      errors.New(" is not a decimal"),
+		},
+		"sign only": {
+			decimal:  "-",
+			currency: eighteenDecimalCurrency,
+			err:      errors.New("- is not a decimal"),
+		},
+		"missing integer": {
+			decimal:  ".5",
+			currency: eighteenDecimalCurrency,
+			err:      errors.New(".5 is not a decimal"),
+		},
+		"missing fraction": {
+			decimal:  "5.",
+			currency: eighteenDecimalCurrency,
+			err:      errors.New("5. is not a decimal"),
+		},
+		"multiple points": {
+			decimal:  "1.2.3",
+			currency: eighteenDecimalCurrency,
+			err:      errors.New("1.2.3 is not a decimal"),
+		},
+		"plus sign": {
+			decimal:  "+1",
+			currency: eighteenDecimalCurrency,
+			err:      errors.New("+1 is not a decimal"),
+		},
+		"exponent": {
+			decimal:  "1e18",
+			currency: eighteenDecimalCurrency,
+			err:      errors.New("1e18 is not a decimal"),
+		},
+		"nil currency": {
+			decimal: "1",
+			err:     errors.New("currency cannot be nil"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseAmount(test.decimal, test.currency)
+			assert.Equal(t, test.result, result)
+			assert.Equal(t, test.err, err)
+
+			if err != nil {
+				return
+			}
+
+			// Ensure the parsed amount round-trips
+			formatted, err := FormatAmount(result)
+			assert.NoError(t, err)
+			reparsed, err := ParseAmount(formatted, test.currency)
+			assert.NoError(t, err)
+			assert.Equal(t, result, reparsed)
+		})
+	}
+}
+
 func TestExtractAmount(t *testing.T) {
 	var (
 		currency1 = &Currency{
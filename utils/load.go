@@ -0,0 +1,287 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLoadTimeout is the default timeout for
+	// loading a file from a URL in LoadAndParseFrom.
+	DefaultLoadTimeout = 10 * time.Minute
+
+	// DefaultMaxLoadBytes is the default maximum number of
+	// (decompressed) bytes read from a URL in LoadAndParseFrom.
+	DefaultMaxLoadBytes = 2 << 30
+
+	// gzipMagicLen is the number of bytes in
+	// the gzip magic number.
+	gzipMagicLen = 2
+)
+
+var (
+	// ErrLoadSizeExceeded is returned when the data loaded from
+	// a URL exceeds the maximum number of allowed bytes.
+	ErrLoadSizeExceeded = errors.New("load size exceeded")
+
+	// gzipMagic is the magic number at the start
+	// of all gzip-compressed data.
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// loader contains the configuration
+// used in LoadAndParseFrom.
+type loader struct {
+	client   *http.Client
+	timeout  time.Duration
+	maxBytes int64
+}
+
+// LoadOption is used to overwrite default values
+// in LoadAndParseFrom. Any LoadOption not provided
+// falls back to the default value.
+type LoadOption func(l *loader)
+
+// WithLoadHTTPClient overrides the default *http.Client
+// used to load URLs.
+func WithLoadHTTPClient(client *http.Client) LoadOption {
+	return func(l *loader) {
+		l.client = client
+	}
+}
+
+// WithLoadTimeout overrides the default timeout
+// for loading a URL.
+func WithLoadTimeout(timeout time.Duration) LoadOption {
+	return func(l *loader) {
+		l.timeout = timeout
+	}
+}
+
+// WithMaxLoadBytes overrides the default maximum number
+// of (decompressed) bytes read from a URL.
+func WithMaxLoadBytes(maxBytes int64) LoadOption {
+	return func(l *loader) {
+		l.maxBytes = maxBytes
+	}
+}
+
+// LoadAndParseFrom reads the file at the provided source
+// and attempts to unmarshal it into output. The source
+// can be a local path or an http(s):// URL, and
+// gzip-compressed data is transparently decompressed.
+//
+// Unlike LoadAndParse, the data is never read fully
+// into memory. If output is a pointer to a slice, each
+// element is decoded as it is read.
+func LoadAndParseFrom(
+	ctx context.Context,
+	source string,
+	output interface{},
+	options ...LoadOption,
+) error {
+	l := &loader{
+		client:   http.DefaultClient,
+		timeout:  DefaultLoadTimeout,
+		maxBytes: DefaultMaxLoadBytes,
+	}
+
+	for _, opt := range options {
+		opt(l)
+	}
+
+	var reader io.Reader
+	if isURL(source) {
+		ctx, cancel := context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+
+		body, err := l.fetch(ctx, source)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+
+		reader = body
+	} else {
+		file, err := os.Open(path.Clean(source))
+		if err != nil {
+			return fmt.Errorf("%w: unable to load file %s", err, source)
+		}
+		defer file.Close()
+
+		reader = file
+	}
+
+	reader, closeReader, err := decompress(reader)
+	if err != nil {
+		return fmt.Errorf("%w: unable to decompress %s", err, source)
+	}
+	defer closeReader()
+
+	if isURL(source) {
+		reader = &limitedReader{reader: reader, remaining: l.maxBytes}
+	}
+
+	if err := decodeStream(reader, output); err != nil {
+		return fmt.Errorf("%w: unable to unmarshal %s", err, source)
+	}
+
+	return nil
+}
+
+// isURL returns a boolean indicating if
+// source is an http(s):// URL.
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// fetch returns the body of a successful
+// GET request to url.
+func (l *loader) fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to create request for %s", err, url)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to load url %s", err, url)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unable to load url %s: received status %s", url, resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// decompress returns a reader that decompresses
+// reader if it contains gzip-compressed data (otherwise
+// reader is returned as is).
+func decompress(reader io.Reader) (io.Reader, func(), error) {
+	buffered := bufio.NewReader(reader)
+	magic, err := buffered.Peek(gzipMagicLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, nil, err
+	}
+
+	if !bytes.Equal(magic, gzipMagic) {
+		return buffered, func() {}, nil
+	}
+
+	gzipReader, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return gzipReader, func() { gzipReader.Close() }, nil
+}
+
+// decodeStream decodes the JSON in reader into output,
+// rejecting any unknown fields. If output is a pointer to
+// a slice, elements are decoded one at a time.
+func decodeStream(reader io.Reader, output interface{}) error {
+	// To prevent silent erroring, we explicitly
+	// reject any unknown fields.
+	dec := json.NewDecoder(reader)
+	dec.DisallowUnknownFields()
+
+	outputValue := reflect.ValueOf(output)
+	if outputValue.Kind() != reflect.Ptr || outputValue.IsNil() ||
+		outputValue.Elem().Kind() != reflect.Slice {
+		return dec.Decode(&output)
+	}
+
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	slice := outputValue.Elem()
+	if token == nil {
+		slice.Set(reflect.Zero(slice.Type()))
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected array but got %v", token)
+	}
+
+	elements := reflect.MakeSlice(slice.Type(), 0, 0)
+	for dec.More() {
+		element := reflect.New(slice.Type().Elem())
+		if err := dec.Decode(element.Interface()); err != nil {
+			return err
+		}
+
+		elements = reflect.Append(elements, element.Elem())
+	}
+
+	// Consume the closing delimiter
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	slice.Set(elements)
+	return nil
+}
+
+// limitedReader returns ErrLoadSizeExceeded
+// after more than remaining bytes are read.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+// Read reads from the underlying reader until
+// the limit is exceeded.
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, ErrLoadSizeExceeded
+	}
+
+	// Read one more byte than allowed so that we can
+	// distinguish between reaching and exceeding the limit.
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	// Any bytes past the limit are dropped so that the
+	// decoder can't complete a value using them.
+	n, err := r.reader.Read(p)
+	if int64(n) > r.remaining {
+		n = int(r.remaining)
+		r.remaining = -1
+		return n, ErrLoadSizeExceeded
+	}
+
+	r.remaining -= int64(n)
+	return n, err
+}
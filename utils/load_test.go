@@ -0,0 +1,236 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	loadCurrencies = []*types.Currency{
+		{
+			Symbol:   "BTC",
+			Decimals: 8,
+		},
+		{
+			Symbol:   "ETH",
+			Decimals: 18,
+		},
+	}
+
+	loadCurrenciesJSON = []byte(
+		`[{"symbol":"BTC","decimals":8},{"symbol":"ETH","decimals":18}]`,
+	)
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestLoadAndParseFromFile(t *testing.T) {
+	ctx := context.Background()
+	dir, err := CreateTempDir()
+	assert.NoError(t, err)
+	defer RemoveTempDir(dir)
+
+	plainPath := path.Join(dir, "currencies.json")
+	assert.NoError(t, ioutil.WriteFile(plainPath, loadCurrenciesJSON, DefaultFilePermissions))
+
+	gzipPath := path.Join(dir, "currencies.json.gz")
+	assert.NoError(t, ioutil.WriteFile(
+		gzipPath,
+		gzipBytes(t, loadCurrenciesJSON),
+		DefaultFilePermissions,
+	))
+
+	t.Run("plain slice", func(t *testing.T) {
+		var currencies []*types.Currency
+		assert.NoError(t, LoadAndParseFrom(ctx, plainPath, &currencies))
+		assert.Equal(t, loadCurrencies, currencies)
+	})
+
+	t.Run("gzip slice", func(t *testing.T) {
+		var currencies []*types.Currency
+		assert.NoError(t, LoadAndParseFrom(ctx, gzipPath, &currencies))
+		assert.Equal(t, loadCurrencies, currencies)
+	})
+
+	t.Run("gzip struct", func(t *testing.T) {
+		structPath := path.Join(dir, "currency.gz")
+		assert.NoError(t, ioutil.WriteFile(
+			structPath,
+			gzipBytes(t, []byte(`{"symbol":"BTC","decimals":8}`)),
+			DefaultFilePermissions,
+		))
+
+		var currency types.Currency
+		assert.NoError(t, LoadAndParseFrom(ctx, structPath, &currency))
+		assert.Equal(t, loadCurrencies[0], &currency)
+	})
+
+	t.Run("null slice", func(t *testing.T) {
+		nullPath := path.Join(dir, "null.json")
+		assert.NoError(t, ioutil.WriteFile(nullPath, []byte("null"), DefaultFilePermissions))
+
+		currencies := []*types.Currency{}
+		assert.NoError(t, LoadAndParseFrom(ctx, nullPath, &currencies))
+		assert.Nil(t, currencies)
+	})
+
+	t.Run("unknown fields", func(t *testing.T) {
+		unknownPath := path.Join(dir, "unknown.json")
+		assert.NoError(t, ioutil.WriteFile(
+			unknownPath,
+			[]byte(`[{"symbol":"BTC","decimals":8,"blah":1}]`),
+			DefaultFilePermissions,
+		))
+
+		var currencies []*types.Currency
+		assert.Error(t, LoadAndParseFrom(ctx, unknownPath, &currencies))
+		assert.Nil(t, currencies)
+	})
+
+	t.Run("not an array", func(t *testing.T) {
+		var currencies []*types.Currency
+		err := LoadAndParseFrom(ctx, path.Join(dir, "currency.gz"), &currencies)
+		assert.Contains(t, err.Error(), "expected array")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		var currencies []*types.Currency
+		err := LoadAndParseFrom(ctx, path.Join(dir, "missing.json"), &currencies)
+		assert.Contains(t, err.Error(), "unable to load file")
+	})
+}
+
+func TestLoadAndParseFromURL(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/currencies.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(loadCurrenciesJSON)
+	})
+	mux.HandleFunc("/currencies.json.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(gzipBytes(t, loadCurrenciesJSON))
+	})
+	mux.HandleFunc("/slow.json", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("plain", func(t *testing.T) {
+		var currencies []*types.Currency
+		assert.NoError(t, LoadAndParseFrom(ctx, server.URL+"/currencies.json", &currencies))
+		assert.Equal(t, loadCurrencies, currencies)
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		var currencies []*types.Currency
+		assert.NoError(t, LoadAndParseFrom(ctx, server.URL+"/currencies.json.gz", &currencies))
+		assert.Equal(t, loadCurrencies, currencies)
+	})
+
+	t.Run("custom client", func(t *testing.T) {
+		var currencies []*types.Currency
+		assert.NoError(t, LoadAndParseFrom(
+			ctx,
+			server.URL+"/currencies.json",
+			&currencies,
+			WithLoadHTTPClient(server.Client()),
+		))
+		assert.Equal(t, loadCurrencies, currencies)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		var currencies []*types.Currency
+		err := LoadAndParseFrom(ctx, server.URL+"/missing.json", &currencies)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("size exceeded", func(t *testing.T) {
+		var currencies []*types.Currency
+		err := LoadAndParseFrom(
+			ctx,
+			server.URL+"/currencies.json",
+			&currencies,
+			WithMaxLoadBytes(int64(len(loadCurrenciesJSON)-1)),
+		)
+		assert.True(t, errors.Is(err, ErrLoadSizeExceeded))
+	})
+
+	t.Run("exact size", func(t *testing.T) {
+		var currencies []*types.Currency
+		assert.NoError(t, LoadAndParseFrom(
+			ctx,
+			server.URL+"/currencies.json",
+			&currencies,
+			WithMaxLoadBytes(int64(len(loadCurrenciesJSON))),
+		))
+		assert.Equal(t, loadCurrencies, currencies)
+	})
+
+	t.Run("decompressed size exceeded", func(t *testing.T) {
+		var currencies []*types.Currency
+		err := LoadAndParseFrom(
+			ctx,
+			server.URL+"/currencies.json.gz",
+			&currencies,
+			WithMaxLoadBytes(int64(len(loadCurrenciesJSON)-1)),
+		)
+		assert.True(t, errors.Is(err, ErrLoadSizeExceeded))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		var currencies []*types.Currency
+		err := LoadAndParseFrom(
+			ctx,
+			server.URL+"/slow.json",
+			&currencies,
+			WithLoadTimeout(10*time.Millisecond),
+		)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		var currencies []*types.Currency
+		err := LoadAndParseFrom(canceledCtx, server.URL+"/currencies.json", &currencies)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
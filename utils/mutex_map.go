@@ -16,6 +16,7 @@ package utils
 
 import (
 	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -30,10 +31,37 @@ const (
 // This is useful for coordinating concurrent, non-overlapping
 // writes in the storage package.
 type MutexMap struct {
+	// acquisitions and contentions are accessed atomically
+	// (and are first in the struct to ensure 64-bit alignment).
+	acquisitions uint64
+	contentions  uint64
+
 	entries     *ShardedMap
 	globalMutex sync.RWMutex
 }
 
+// MutexMapStats contains the lock contention
+// statistics of a *MutexMap.
+type MutexMapStats struct {
+	// Keys is the number of identifiers that are
+	// currently locked (or being waited on). Identifiers
+	// are removed once no caller holds or waits on them.
+	Keys int
+
+	// Acquisitions is the number of times any
+	// identifier lock has been acquired.
+	Acquisitions uint64
+
+	// Contentions is the number of identifier lock
+	// acquisitions that had to wait for another caller
+	// to release the same identifier.
+	Contentions uint64
+
+	// Shards contains the contention statistics of the
+	// shards used to store identifier locks.
+	Shards *ShardedMapStats
+}
+
 // mutexMapEntry is the primitive used
 // to track claimed *PriorityMutex.
 type mutexMapEntry struct {
@@ -89,7 +117,10 @@ func (m *MutexMap) Lock(identifier string, priority bool) {
 
 	// Once we have a m.globalMutex.RLock, it is
 	// safe to acquire an identifier lock.
-	entry.lock.Lock(priority)
+	if entry.lock.acquire(priority) {
+		atomic.AddUint64(&m.contentions, 1)
+	}
+	atomic.AddUint64(&m.acquisitions, 1)
 }
 
 // Unlock releases a lock held for a particular identifier.
@@ -114,3 +145,21 @@ func (m *MutexMap) Unlock(identifier string) {
 	// lock in the table.
 	m.globalMutex.RUnlock()
 }
+
+// Stats returns the lock contention statistics
+// of the *MutexMap.
+func (m *MutexMap) Stats() *MutexMapStats {
+	keys := 0
+	for _, shard := range m.entries.shards {
+		shard.mutex.Lock(unlockPriority)
+		keys += len(shard.entries)
+		shard.mutex.Unlock()
+	}
+
+	return &MutexMapStats{
+		Keys:         keys,
+		Acquisitions: atomic.LoadUint64(&m.acquisitions),
+		Contentions:  atomic.LoadUint64(&m.contentions),
+		Shards:       m.entries.Stats(),
+	}
+}
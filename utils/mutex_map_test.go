@@ -16,6 +16,8 @@ package utils
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -91,3 +93,93 @@ func TestMutexMap(t *testing.T) {
 		len(m.entries.shards[m.entries.shardIndex("b")].entries)
 	assert.Equal(t, totalKeys, 0)
 }
+
+func TestMutexMapStress(t *testing.T) {
+	const (
+		goroutines = 5000
+		keys       = 50
+		iterations = 10
+	)
+
+	m := NewMutexMap(8)
+	g, _ := errgroup.WithContext(context.Background())
+
+	// Each counter is only modified while holding the lock
+	// for its key (the race detector will catch any overlap).
+	counters := make([]int, keys)
+	holders := make([]int32, keys)
+	for i := 0; i < goroutines; i++ {
+		key := i % keys
+		priority := i%2 == 0
+		g.Go(func() error {
+			identifier := fmt.Sprintf("account-%d", key)
+			for j := 0; j < iterations; j++ {
+				m.Lock(identifier, priority)
+				if !atomic.CompareAndSwapInt32(&holders[key], 0, 1) {
+					return fmt.Errorf("%s held concurrently", identifier)
+				}
+				counters[key]++
+				atomic.StoreInt32(&holders[key], 0)
+				m.Unlock(identifier)
+			}
+
+			return nil
+		})
+	}
+
+	assert.NoError(t, g.Wait())
+	for _, counter := range counters {
+		assert.Equal(t, goroutines/keys*iterations, counter)
+	}
+
+	// All entries are removed once uncontended
+	stats := m.Stats()
+	assert.Equal(t, 0, stats.Keys)
+	assert.Equal(t, uint64(goroutines*iterations), stats.Acquisitions)
+	assert.Len(t, stats.Shards.ShardContentions, 8)
+	for _, shard := range m.entries.shards {
+		assert.Len(t, shard.entries, 0)
+	}
+}
+
+func TestMutexMapStats(t *testing.T) {
+	m := NewMutexMap(DefaultShards)
+	m.Lock("a", false)
+	m.Lock("b", false)
+
+	stats := m.Stats()
+	assert.Equal(t, 2, stats.Keys)
+	assert.Equal(t, uint64(2), stats.Acquisitions)
+	assert.Equal(t, uint64(0), stats.Contentions)
+
+	// Wait for another caller to acquire "a"
+	acquired := make(chan struct{})
+	go func() {
+		m.Lock("a", true)
+		close(acquired)
+	}()
+
+	// Wait for the caller to register for "a"
+	for {
+		m.entries.Lock("a", false)
+		count := m.entries.shards[m.entries.shardIndex("a")].entries["a"].(*mutexMapEntry).count
+		m.entries.Unlock("a")
+		if count == 2 {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	m.Unlock("a")
+	<-acquired
+
+	stats = m.Stats()
+	assert.Equal(t, 2, stats.Keys)
+	assert.Equal(t, uint64(3), stats.Acquisitions)
+	assert.Equal(t, uint64(1), stats.Contentions)
+
+	m.Unlock("a")
+	m.Unlock("b")
+	assert.Equal(t, 0, m.Stats().Keys)
+}
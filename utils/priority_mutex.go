@@ -39,12 +39,19 @@ type PriorityMutex struct {
 // priority mutex. When priority is true, a lock
 // will be granted before other low priority callers.
 func (m *PriorityMutex) Lock(priority bool) {
+	m.acquire(priority)
+}
+
+// acquire acquires the mutex and returns a boolean
+// indicating if the caller had to wait for another
+// caller to release it.
+func (m *PriorityMutex) acquire(priority bool) bool {
 	m.mutex.Lock()
 
 	if !m.lock {
 		m.lock = true
 		m.mutex.Unlock()
-		return
+		return false
 	}
 
 	c := make(chan struct{})
@@ -56,6 +63,7 @@ func (m *PriorityMutex) Lock(priority bool) {
 
 	m.mutex.Unlock()
 	<-c
+	return true
 }
 
 // Unlock selects the next highest priority lock
@@ -0,0 +1,204 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sync"
+)
+
+// PriorityRWMutex is a reader/writer mutex that prefers
+// writers. When a writer is waiting for the lock, new
+// readers block until it has acquired (and released) the
+// lock, so a constant stream of readers cannot starve a
+// writer (the wait of a writer is bounded by the longest
+// read lock held when it arrived).
+//
+// Callers can also request priority over other callers.
+// Waiting high priority writers are granted the lock
+// before high priority readers, which are granted the lock
+// before low priority writers and readers. This can be
+// useful to ensure block processing always wins over
+// background reads (like reconciliation).
+//
+// The zero value is an unlocked mutex.
+type PriorityRWMutex struct {
+	mutex sync.Mutex
+
+	readers int
+	writer  bool
+
+	highWriters []chan struct{}
+	lowWriters  []chan struct{}
+	highReaders []chan struct{}
+	lowReaders  []chan struct{}
+}
+
+// Lock acquires a high priority write lock.
+func (m *PriorityRWMutex) Lock() {
+	m.LockPriority(true)
+}
+
+// LockPriority acquires either a high or low priority
+// write lock. When priority is true, the lock will be
+// granted before other waiting low priority callers and
+// high priority readers.
+func (m *PriorityRWMutex) LockPriority(priority bool) {
+	m.mutex.Lock()
+
+	// If there is no holder, there can't be any waiters
+	// (the lock is always handed off on release).
+	if !m.writer && m.readers == 0 {
+		m.writer = true
+		m.mutex.Unlock()
+		return
+	}
+
+	c := make(chan struct{})
+	if priority {
+		m.highWriters = append(m.highWriters, c)
+	} else {
+		m.lowWriters = append(m.lowWriters, c)
+	}
+
+	m.mutex.Unlock()
+	<-c
+}
+
+// Unlock releases a write lock and grants the
+// lock to the next highest priority waiter(s).
+func (m *PriorityRWMutex) Unlock() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.writer {
+		panic("utils: Unlock of unlocked PriorityRWMutex")
+	}
+	m.writer = false
+
+	switch {
+	case len(m.highWriters) > 0:
+		m.grantWriter()
+	case len(m.lowWriters) > 0 && len(m.highReaders) > 0:
+		m.grantReaders(false)
+	case len(m.lowWriters) > 0:
+		m.grantWriter()
+	default:
+		m.grantReaders(true)
+	}
+}
+
+// RLock acquires a high priority read lock.
+func (m *PriorityRWMutex) RLock() {
+	m.RLockPriority(true)
+}
+
+// RLockPriority acquires either a high or low priority
+// read lock. If a writer is waiting for the lock, the
+// caller will wait until the writer releases it.
+func (m *PriorityRWMutex) RLockPriority(priority bool) {
+	m.mutex.Lock()
+
+	if !m.writer && len(m.highWriters) == 0 && len(m.lowWriters) == 0 {
+		m.readers++
+		m.mutex.Unlock()
+		return
+	}
+
+	c := make(chan struct{})
+	if priority {
+		m.highReaders = append(m.highReaders, c)
+	} else {
+		m.lowReaders = append(m.lowReaders, c)
+	}
+
+	m.mutex.Unlock()
+	<-c
+}
+
+// RUnlock releases a read lock. If it is the last
+// read lock, the lock is granted to the next
+// highest priority writer.
+func (m *PriorityRWMutex) RUnlock() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.readers <= 0 {
+		panic("utils: RUnlock of unlocked PriorityRWMutex")
+	}
+
+	m.readers--
+	if m.readers > 0 {
+		return
+	}
+
+	if len(m.highWriters) > 0 || len(m.lowWriters) > 0 {
+		m.grantWriter()
+		return
+	}
+
+	// Low priority readers may still be waiting if
+	// high priority readers were granted the lock
+	// before a low priority writer that has since
+	// been granted the lock and released it.
+	m.grantReaders(true)
+}
+
+// RLocker returns a sync.Locker that acquires and
+// releases a high priority read lock.
+func (m *PriorityRWMutex) RLocker() sync.Locker {
+	return (*priorityRLocker)(m)
+}
+
+// grantWriter grants the lock to the next
+// highest priority writer. The caller must hold
+// m.mutex.
+func (m *PriorityRWMutex) grantWriter() {
+	var c chan struct{}
+	if len(m.highWriters) > 0 {
+		c, m.highWriters = m.highWriters[0], m.highWriters[1:]
+	} else {
+		c, m.lowWriters = m.lowWriters[0], m.lowWriters[1:]
+	}
+
+	m.writer = true
+	close(c)
+}
+
+// grantReaders grants the lock to all waiting high
+// priority readers (and all waiting low priority readers
+// if includeLow is true). The caller must hold m.mutex.
+func (m *PriorityRWMutex) grantReaders(includeLow bool) {
+	waiting := m.highReaders
+	m.highReaders = nil
+	if includeLow {
+		waiting = append(waiting, m.lowReaders...)
+		m.lowReaders = nil
+	}
+
+	m.readers += len(waiting)
+	for _, c := range waiting {
+		close(c)
+	}
+}
+
+// priorityRLocker implements sync.Locker
+// using read locks of a *PriorityRWMutex.
+type priorityRLocker PriorityRWMutex
+
+// Lock acquires a high priority read lock.
+func (r *priorityRLocker) Lock() { (*PriorityRWMutex)(r).RLock() }
+
+// Unlock releases a read lock.
+func (r *priorityRLocker) Unlock() { (*PriorityRWMutex)(r).RUnlock() }
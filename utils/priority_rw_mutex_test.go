@@ -0,0 +1,243 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/errgroup"
+)
+
+// waitForWaiters blocks until the provided number of
+// callers are waiting on each queue of m.
+func waitForWaiters(m *PriorityRWMutex, highWriters, lowWriters, highReaders, lowReaders int) {
+	for {
+		m.mutex.Lock()
+		done := len(m.highWriters) == highWriters &&
+			len(m.lowWriters) == lowWriters &&
+			len(m.highReaders) == highReaders &&
+			len(m.lowReaders) == lowReaders
+		m.mutex.Unlock()
+		if done {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPriorityRWMutexWriterBlocksNewReaders(t *testing.T) {
+	m := new(PriorityRWMutex)
+	m.RLock()
+	m.RLock()
+
+	// Writer waits for active readers
+	writerAcquired := make(chan struct{})
+	go func() {
+		m.Lock()
+		close(writerAcquired)
+	}()
+	waitForWaiters(m, 1, 0, 0, 0)
+
+	// New readers wait for the writer
+	readerAcquired := make(chan struct{})
+	go func() {
+		m.RLock()
+		close(readerAcquired)
+	}()
+	waitForWaiters(m, 1, 0, 1, 0)
+
+	m.RUnlock()
+	select {
+	case <-writerAcquired:
+		t.Fatal("writer acquired lock while reader active")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	m.RUnlock()
+	<-writerAcquired
+	select {
+	case <-readerAcquired:
+		t.Fatal("reader acquired lock while writer active")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	m.Unlock()
+	<-readerAcquired
+	m.RUnlock()
+
+	assert.False(t, m.writer)
+	assert.Equal(t, 0, m.readers)
+}
+
+func TestPriorityRWMutexPriority(t *testing.T) {
+	m := new(PriorityRWMutex)
+	m.Lock()
+
+	var mutex sync.Mutex
+	arr := []string{}
+	record := func(s string) {
+		mutex.Lock()
+		arr = append(arr, s)
+		mutex.Unlock()
+	}
+
+	g, _ := errgroup.WithContext(context.Background())
+	g.Go(func() error {
+		m.RLockPriority(false)
+		record("low-reader")
+		m.RUnlock()
+		return nil
+	})
+	waitForWaiters(m, 0, 0, 0, 1)
+
+	g.Go(func() error {
+		m.LockPriority(false)
+		record("low-writer")
+		m.Unlock()
+		return nil
+	})
+	waitForWaiters(m, 0, 1, 0, 1)
+
+	g.Go(func() error {
+		m.RLock()
+		record("high-reader")
+		m.RUnlock()
+		return nil
+	})
+	waitForWaiters(m, 0, 1, 1, 1)
+
+	g.Go(func() error {
+		m.Lock()
+		record("high-writer")
+		m.Unlock()
+		return nil
+	})
+	waitForWaiters(m, 1, 1, 1, 1)
+
+	m.Unlock()
+	assert.NoError(t, g.Wait())
+	assert.Equal(t, []string{
+		"high-writer",
+		"high-reader",
+		"low-writer",
+		"low-reader",
+	}, arr)
+
+	assert.False(t, m.writer)
+	assert.Equal(t, 0, m.readers)
+}
+
+func TestPriorityRWMutexBoundedWriterWait(t *testing.T) {
+	const (
+		readers  = 50
+		holdTime = 5 * time.Millisecond
+		maxWait  = 500 * time.Millisecond
+	)
+
+	m := new(PriorityRWMutex)
+	ctx, cancel := context.WithCancel(context.Background())
+	g, _ := errgroup.WithContext(ctx)
+
+	// Flood the mutex with overlapping readers so
+	// that there is never a time without a reader.
+	for i := 0; i < readers; i++ {
+		g.Go(func() error {
+			for ctx.Err() == nil {
+				m.RLockPriority(false)
+				time.Sleep(holdTime)
+				m.RUnlock()
+			}
+
+			return nil
+		})
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		start := time.Now()
+		m.Lock()
+		waited := time.Since(start)
+		m.Unlock()
+
+		assert.True(t, waited < maxWait, waited)
+		time.Sleep(holdTime)
+	}
+
+	cancel()
+	assert.NoError(t, g.Wait())
+}
+
+func TestPriorityRWMutexStress(t *testing.T) {
+	var (
+		m       PriorityRWMutex
+		locker  sync.Locker = &m
+		rlocker             = m.RLocker()
+
+		value   int
+		readers int32
+	)
+
+	g, _ := errgroup.WithContext(context.Background())
+	for i := 0; i < 1000; i++ {
+		i := i
+		g.Go(func() error {
+			for j := 0; j < 10; j++ {
+				switch (i + j) % 4 {
+				case 0:
+					locker.Lock()
+					assert.Equal(t, int32(0), atomic.LoadInt32(&readers))
+					value++
+					locker.Unlock()
+				case 1:
+					m.LockPriority(false)
+					assert.Equal(t, int32(0), atomic.LoadInt32(&readers))
+					value++
+					m.Unlock()
+				case 2:
+					rlocker.Lock()
+					atomic.AddInt32(&readers, 1)
+					_ = value
+					atomic.AddInt32(&readers, -1)
+					rlocker.Unlock()
+				default:
+					m.RLockPriority(false)
+					atomic.AddInt32(&readers, 1)
+					_ = value
+					atomic.AddInt32(&readers, -1)
+					m.RUnlock()
+				}
+			}
+
+			return nil
+		})
+	}
+
+	assert.NoError(t, g.Wait())
+	assert.Equal(t, 5000, value)
+	assert.False(t, m.writer)
+	assert.Equal(t, 0, m.readers)
+}
+
+func TestPriorityRWMutexUnlockPanics(t *testing.T) {
+	m := new(PriorityRWMutex)
+	assert.Panics(t, func() { m.Unlock() })
+	assert.Panics(t, func() { m.RUnlock() })
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ErrRetriesExhausted is returned by Retry when
+// all attempts fail.
+var ErrRetriesExhausted = errors.New("retries exhausted")
+
+// BackoffPolicy determines how long to wait
+// between attempts in Retry.
+type BackoffPolicy interface {
+	// Backoff returns the delay before the next attempt
+	// after attempt (starting at 0) fails.
+	Backoff(attempt int) time.Duration
+}
+
+// ConstantBackoff waits the same Delay
+// between each attempt.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+// Backoff returns Delay.
+func (b *ConstantBackoff) Backoff(attempt int) time.Duration {
+	return b.Delay
+}
+
+// ExponentialBackoff waits Base * Multiplier^attempt between
+// each attempt (up to Max, if Max is positive). If Jitter is
+// true, each delay is instead chosen at random between 0 and
+// that value (full jitter).
+type ExponentialBackoff struct {
+	Base       time.Duration
+	Multiplier float64
+	Max        time.Duration
+	Jitter     bool
+}
+
+// Backoff returns the delay after attempt fails.
+func (b *ExponentialBackoff) Backoff(attempt int) time.Duration {
+	ceiling := float64(b.Base) * math.Pow(b.Multiplier, float64(attempt))
+	if b.Max > 0 && ceiling > float64(b.Max) {
+		ceiling = float64(b.Max)
+	}
+
+	// Avoid overflowing time.Duration when there is
+	// no Max.
+	delay := time.Duration(math.MaxInt64)
+	if ceiling < math.MaxInt64 {
+		delay = time.Duration(ceiling)
+	}
+
+	if !b.Jitter || delay <= 0 {
+		return delay
+	}
+
+	return time.Duration(rand.Int63n(int64(delay) + 1)) // #nosec G404
+}
+
+// RetryError is returned by Retry when all attempts
+// fail. It wraps the error returned by the last attempt
+// and matches ErrRetriesExhausted (so that both
+// errors.Is(err, ErrRetriesExhausted) and errors.Is(err,
+// lastErr) are true).
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+// Error returns the last error and
+// the number of attempts.
+func (e *RetryError) Error() string {
+	return fmt.Sprintf(
+		"%s after %d attempts: %s",
+		ErrRetriesExhausted.Error(),
+		e.Attempts,
+		e.Err.Error(),
+	)
+}
+
+// Unwrap returns the error returned
+// by the last attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is
+// ErrRetriesExhausted.
+func (e *RetryError) Is(target error) bool {
+	return target == ErrRetriesExhausted
+}
+
+// permanentError indicates that Retry
+// should not attempt f again.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Permanent wraps err so that Retry returns it
+// (unwrapped) immediately instead of attempting
+// again.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// Retry calls f until it succeeds or has been called attempts
+// times (at least once), waiting the delay returned by backoff between each
+// attempt. If ctx is done before f succeeds (including while
+// waiting), ctx.Err() is returned immediately.
+//
+// If f returns an error wrapped with Permanent, the error is
+// returned without attempting again. If all attempts fail, a
+// *RetryError wrapping the last error is returned.
+func Retry(
+	ctx context.Context,
+	attempts int,
+	backoff BackoffPolicy,
+	f func() error,
+) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		err = f()
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		if attempt == attempts-1 {
+			break
+		}
+
+		if sleepErr := ContextSleep(ctx, backoff.Backoff(attempt)); sleepErr != nil {
+			return sleepErr
+		}
+	}
+
+	return &RetryError{
+		Attempts: attempts,
+		Err:      err,
+	}
+}
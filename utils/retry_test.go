@@ -0,0 +1,179 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextSleep(t *testing.T) {
+	t.Run("sleeps", func(t *testing.T) {
+		start := time.Now()
+		assert.NoError(t, ContextSleep(context.Background(), 10*time.Millisecond))
+		assert.True(t, time.Since(start) >= 10*time.Millisecond)
+	})
+
+	t.Run("zero duration", func(t *testing.T) {
+		assert.NoError(t, ContextSleep(context.Background(), 0))
+	})
+
+	t.Run("already canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.True(t, errors.Is(ContextSleep(ctx, 0), context.Canceled))
+		assert.True(t, errors.Is(ContextSleep(ctx, time.Hour), context.Canceled))
+	})
+
+	t.Run("canceled while sleeping", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+
+		start := time.Now()
+		assert.True(t, errors.Is(ContextSleep(ctx, time.Hour), context.Canceled))
+		assert.True(t, time.Since(start) < time.Minute)
+	})
+}
+
+func TestBackoffPolicies(t *testing.T) {
+	constant := &ConstantBackoff{Delay: time.Second}
+	assert.Equal(t, time.Second, constant.Backoff(0))
+	assert.Equal(t, time.Second, constant.Backoff(10))
+
+	exponential := &ExponentialBackoff{
+		Base:       time.Second,
+		Multiplier: 2,
+		Max:        10 * time.Second,
+	}
+	assert.Equal(t, time.Second, exponential.Backoff(0))
+	assert.Equal(t, 2*time.Second, exponential.Backoff(1))
+	assert.Equal(t, 8*time.Second, exponential.Backoff(3))
+	assert.Equal(t, 10*time.Second, exponential.Backoff(4))
+	assert.Equal(t, 10*time.Second, exponential.Backoff(1000))
+
+	uncapped := &ExponentialBackoff{
+		Base:       time.Second,
+		Multiplier: 2,
+	}
+	assert.Equal(t, time.Duration(1<<63-1), uncapped.Backoff(1000))
+
+	jitter := &ExponentialBackoff{
+		Base:       time.Second,
+		Multiplier: 2,
+		Max:        10 * time.Second,
+		Jitter:     true,
+	}
+	for i := 0; i < 100; i++ {
+		delay := jitter.Backoff(2)
+		assert.True(t, delay >= 0 && delay <= 4*time.Second, delay)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	backoff := &ConstantBackoff{Delay: time.Millisecond}
+	errFailed := errors.New("failed")
+
+	t.Run("fails twice then succeeds", func(t *testing.T) {
+		calls := 0
+		err := Retry(ctx, 3, backoff, func() error {
+			calls++
+			if calls < 3 {
+				return errFailed
+			}
+
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		calls := 0
+		err := Retry(ctx, 3, backoff, func() error {
+			calls++
+			return errFailed
+		})
+		assert.Equal(t, 3, calls)
+		assert.True(t, errors.Is(err, ErrRetriesExhausted))
+		assert.True(t, errors.Is(err, errFailed))
+
+		var retryErr *RetryError
+		assert.True(t, errors.As(err, &retryErr))
+		assert.Equal(t, 3, retryErr.Attempts)
+		assert.Equal(t, "retries exhausted after 3 attempts: failed", err.Error())
+	})
+
+	t.Run("at least one attempt", func(t *testing.T) {
+		calls := 0
+		err := Retry(ctx, 0, backoff, func() error {
+			calls++
+			return errFailed
+		})
+		assert.Equal(t, 1, calls)
+		assert.True(t, errors.Is(err, ErrRetriesExhausted))
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		calls := 0
+		err := Retry(ctx, 3, backoff, func() error {
+			calls++
+			return Permanent(errFailed)
+		})
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, errFailed, err)
+		assert.False(t, errors.Is(err, ErrRetriesExhausted))
+	})
+
+	t.Run("permanent nil", func(t *testing.T) {
+		assert.NoError(t, Permanent(nil))
+	})
+
+	t.Run("canceled during backoff", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		calls := 0
+		start := time.Now()
+		err := Retry(ctx, 3, &ConstantBackoff{Delay: time.Hour}, func() error {
+			calls++
+			cancel()
+			return errFailed
+		})
+		assert.Equal(t, 1, calls)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.False(t, errors.Is(err, ErrRetriesExhausted))
+		assert.True(t, time.Since(start) < time.Minute)
+	})
+
+	t.Run("canceled before attempt", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		calls := 0
+		err := Retry(ctx, 3, backoff, func() error {
+			calls++
+			return nil
+		})
+		assert.Equal(t, 0, calls)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
@@ -15,6 +15,8 @@
 package utils
 
 import (
+	"sync/atomic"
+
 	"github.com/segmentio/fasthash/fnv1a"
 )
 
@@ -27,10 +29,34 @@ const (
 // shardMapEntry governs access to the shard of
 // the map contained at a particular index.
 type shardMapEntry struct {
+	// acquisitions and contentions are accessed atomically
+	// (and are first in the struct to ensure 64-bit alignment).
+	acquisitions uint64
+	contentions  uint64
+
 	mutex   *PriorityMutex
 	entries map[string]interface{}
 }
 
+// ShardedMapStats contains the lock contention
+// statistics of a *ShardedMap.
+type ShardedMapStats struct {
+	// Acquisitions is the number of times
+	// any shard lock has been acquired.
+	Acquisitions uint64
+
+	// Contentions is the number of acquisitions
+	// that had to wait for another caller to
+	// release a shard lock.
+	Contentions uint64
+
+	// ShardContentions is the number of contentions
+	// for each shard (indexed by shard). A shard with
+	// many more contentions than others indicates a
+	// hot key.
+	ShardContentions []uint64
+}
+
 // ShardedMap allows concurrent writes
 // to a map by sharding the map into some
 // number of independently locked subsections.
@@ -72,7 +98,11 @@ func (m *ShardedMap) shardIndex(key string) int {
 func (m *ShardedMap) Lock(key string, priority bool) map[string]interface{} {
 	shardIndex := m.shardIndex(key)
 	shard := m.shards[shardIndex]
-	shard.mutex.Lock(priority)
+	if shard.mutex.acquire(priority) {
+		atomic.AddUint64(&shard.contentions, 1)
+	}
+	atomic.AddUint64(&shard.acquisitions, 1)
+
 	return shard.entries
 }
 
@@ -83,3 +113,20 @@ func (m *ShardedMap) Unlock(key string) {
 	shard := m.shards[shardIndex]
 	shard.mutex.Unlock()
 }
+
+// Stats returns the lock contention statistics
+// of the *ShardedMap.
+func (m *ShardedMap) Stats() *ShardedMapStats {
+	stats := &ShardedMapStats{
+		ShardContentions: make([]uint64, len(m.shards)),
+	}
+
+	for i, shard := range m.shards {
+		contentions := atomic.LoadUint64(&shard.contentions)
+		stats.Acquisitions += atomic.LoadUint64(&shard.acquisitions)
+		stats.Contentions += contentions
+		stats.ShardContentions[i] = contentions
+	}
+
+	return stats
+}
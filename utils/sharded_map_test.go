@@ -67,3 +67,37 @@ func TestShardedMap(t *testing.T) {
 	assert.Equal(t, s["test"], "b")
 	m.Unlock("b")
 }
+
+func TestShardedMapStats(t *testing.T) {
+	m := NewShardedMap(2)
+	m.Lock("a", false)
+
+	// Wait for another caller to acquire the shard
+	acquired := make(chan struct{})
+	go func() {
+		m.Lock("a", false)
+		close(acquired)
+	}()
+
+	shard := m.shards[m.shardIndex("a")]
+	for {
+		shard.mutex.mutex.Lock()
+		waiting := len(shard.mutex.low)
+		shard.mutex.mutex.Unlock()
+		if waiting == 1 {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	m.Unlock("a")
+	<-acquired
+	m.Unlock("a")
+
+	stats := m.Stats()
+	assert.Equal(t, uint64(2), stats.Acquisitions)
+	assert.Equal(t, uint64(1), stats.Contentions)
+	assert.Len(t, stats.ShardContentions, 2)
+	assert.Equal(t, uint64(1), stats.ShardContentions[m.shardIndex("a")])
+}
@@ -114,17 +114,107 @@ func Equal(a interface{}, b interface{}) bool {
 	return types.Hash(a) == types.Hash(b)
 }
 
+// renameFile is used to atomically replace files
+// in SerializeAndWrite (overridden in tests).
+var renameFile = os.Rename
+
+// writer contains the configuration
+// used in SerializeAndWrite.
+type writer struct {
+	pretty bool
+}
+
+// WriteOption is used to overwrite default values
+// in SerializeAndWrite. Any WriteOption not provided
+// falls back to the default value.
+type WriteOption func(w *writer)
+
+// WithPrettyPrint overrides whether the serialized
+// object is indented (defaults to true).
+func WithPrettyPrint(pretty bool) WriteOption {
+	return func(w *writer) {
+		w.pretty = pretty
+	}
+}
+
 // SerializeAndWrite attempts to serialize the provided object
-// into a file at filePath.
-func SerializeAndWrite(filePath string, object interface{}) error {
-	err := ioutil.WriteFile(
-		filePath,
-		[]byte(types.PrettyPrintStruct(object)),
-		os.FileMode(DefaultFilePermissions),
-	)
+// into a file at filePath (so that it can be read with
+// LoadAndParse).
+//
+// The object is written to a temporary file in the same
+// directory, synced to disk, and then renamed to filePath.
+// This ensures filePath always contains either the previous
+// contents or the complete new contents (even if the process
+// crashes while writing).
+func SerializeAndWrite(filePath string, object interface{}, options ...WriteOption) error {
+	w := &writer{
+		pretty: true,
+	}
+
+	for _, opt := range options {
+		opt(w)
+	}
+
+	var serialized []byte
+	var err error
+	if w.pretty {
+		serialized, err = json.MarshalIndent(object, "", " ")
+	} else {
+		serialized, err = json.Marshal(object)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: unable to serialize object", err)
+	}
+
+	dir, file := path.Split(path.Clean(filePath))
+	if len(dir) == 0 {
+		dir = "."
+	}
+
+	tmpFile, err := ioutil.TempFile(dir, "."+file+".tmp")
 	if err != nil {
+		return fmt.Errorf("%w: unable to create temporary file for %s", err, filePath)
+	}
+
+	// Remove the temporary file if we don't
+	// successfully rename it.
+	tmpPath := tmpFile.Name()
+	renamed := false
+	defer func() {
+		if !renamed {
+			_ = tmpFile.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmpFile.Write(serialized); err != nil {
+		return fmt.Errorf("%w: unable to write to file path %s", err, tmpPath)
+	}
+
+	if err := tmpFile.Chmod(os.FileMode(DefaultFilePermissions)); err != nil {
+		return fmt.Errorf("%w: unable to set permissions of %s", err, tmpPath)
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		return fmt.Errorf("%w: unable to sync %s", err, tmpPath)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("%w: unable to close %s", err, tmpPath)
+	}
+
+	if err := renameFile(tmpPath, filePath); err != nil {
 		return fmt.Errorf("%w: unable to write to file path %s", err, filePath)
 	}
+	renamed = true
+
+	// Sync the directory so that the rename is durable. Not
+	// all platforms support syncing directories, so this is
+	// best effort.
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
 
 	return nil
 }
@@ -509,8 +599,13 @@ func AtTip(
 }
 
 // ContextSleep sleeps for the provided duration and returns
-// an error if context is canceled.
+// an error if context is canceled (even if it was canceled
+// before ContextSleep was called).
 func ContextSleep(ctx context.Context, duration time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	timer := time.NewTimer(duration)
 	defer timer.Stop()
 
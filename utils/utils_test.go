@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"os"
 	"path"
@@ -81,6 +82,87 @@ func TestCreateAndRemoveTempDir(t *testing.T) {
 	assert.True(t, os.IsNotExist(err))
 }
 
+func TestSerializeAndWrite(t *testing.T) {
+	dir, err := CreateTempDir()
+	assert.NoError(t, err)
+	defer RemoveTempDir(dir)
+
+	filePath := path.Join(dir, "curr.json")
+	curr := &types.Currency{
+		Symbol:   "BTC",
+		Decimals: 8,
+	}
+
+	t.Run("pretty", func(t *testing.T) {
+		assert.NoError(t, SerializeAndWrite(filePath, curr))
+
+		contents, err := ioutil.ReadFile(filePath)
+		assert.NoError(t, err)
+		assert.Equal(t, types.PrettyPrintStruct(curr), string(contents))
+
+		info, err := os.Stat(filePath)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(DefaultFilePermissions), info.Mode().Perm())
+
+		var newCurr types.Currency
+		assert.NoError(t, LoadAndParse(filePath, &newCurr))
+		assert.Equal(t, curr, &newCurr)
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		assert.NoError(t, SerializeAndWrite(filePath, curr, WithPrettyPrint(false)))
+
+		contents, err := ioutil.ReadFile(filePath)
+		assert.NoError(t, err)
+		assert.Equal(t, types.PrintStruct(curr), string(contents))
+
+		var newCurr types.Currency
+		assert.NoError(t, LoadAndParse(filePath, &newCurr))
+		assert.Equal(t, curr, &newCurr)
+	})
+
+	t.Run("rename failure", func(t *testing.T) {
+		renameFile = func(string, string) error {
+			return errors.New("crash")
+		}
+		defer func() {
+			renameFile = os.Rename
+		}()
+
+		newCurr := &types.Currency{
+			Symbol:   "ETH",
+			Decimals: 18,
+		}
+		err := SerializeAndWrite(filePath, newCurr)
+		assert.Contains(t, err.Error(), "crash")
+
+		// The original file is intact
+		var loadedCurr types.Currency
+		assert.NoError(t, LoadAndParse(filePath, &loadedCurr))
+		assert.Equal(t, curr, &loadedCurr)
+	})
+
+	t.Run("serialization failure", func(t *testing.T) {
+		err := SerializeAndWrite(filePath, make(chan int))
+		assert.Contains(t, err.Error(), "unable to serialize object")
+
+		var loadedCurr types.Currency
+		assert.NoError(t, LoadAndParse(filePath, &loadedCurr))
+		assert.Equal(t, curr, &loadedCurr)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		err := SerializeAndWrite(path.Join(dir, "missing", "curr.json"), curr)
+		assert.Contains(t, err.Error(), "unable to create temporary file")
+	})
+
+	// No temporary files are left behind
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "curr.json", files[0].Name())
+}
+
 func TestCreateCommandPath(t *testing.T) {
 	dir, err := CreateTempDir()
 	assert.NoError(t, err)